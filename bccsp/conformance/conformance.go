@@ -0,0 +1,259 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package conformance contains scenarios that every bccsp.BCCSP
+// implementation is expected to satisfy. Each provider package runs the
+// suite from its own tests with the options appropriate for its algorithms.
+package conformance
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Suite describes the options used to exercise a BCCSP under test.
+type Suite struct {
+	// AsymmetricKeyGenOpts generates the signing keys. The options must not be
+	// ephemeral if the provider's key store is expected to support GetKey.
+	AsymmetricKeyGenOpts bccsp.KeyGenOpts
+
+	// PublicKeyImportOpts imports the output of PublicKey().Bytes() of a key
+	// generated with AsymmetricKeyGenOpts.
+	PublicKeyImportOpts bccsp.KeyImportOpts
+
+	// SymmetricKeyGenOpts generates the encryption keys. When nil, the
+	// encryption scenarios are skipped.
+	SymmetricKeyGenOpts bccsp.KeyGenOpts
+	EncrypterOpts       bccsp.EncrypterOpts
+	DecrypterOpts       bccsp.DecrypterOpts
+
+	// HashOpts selects the hash function used to compute digests.
+	HashOpts bccsp.HashOpts
+
+	// SignerOpts is passed to Sign and Verify.
+	SignerOpts bccsp.SignerOpts
+}
+
+// Run runs every scenario of the suite against csp.
+func Run(t *testing.T, csp bccsp.BCCSP, s *Suite) {
+	t.Run("KeyGen", func(t *testing.T) { RunKeyGen(t, csp, s) })
+	t.Run("KeyImport", func(t *testing.T) { RunKeyImport(t, csp, s) })
+	t.Run("GetKey", func(t *testing.T) { RunGetKey(t, csp, s) })
+	t.Run("Hash", func(t *testing.T) { RunHash(t, csp, s) })
+	t.Run("SignVerify", func(t *testing.T) { RunSignVerify(t, csp, s) })
+	t.Run("EncryptDecrypt", func(t *testing.T) { RunEncryptDecrypt(t, csp, s) })
+}
+
+// RunKeyGen checks the properties of generated asymmetric and symmetric keys.
+func RunKeyGen(t *testing.T, csp bccsp.BCCSP, s *Suite) {
+	_, err := csp.KeyGen(nil)
+	assert.Error(t, err, "KeyGen with nil opts must fail")
+
+	k, err := csp.KeyGen(s.AsymmetricKeyGenOpts)
+	require.NoError(t, err)
+	require.NotNil(t, k)
+	assert.True(t, k.Private(), "generated key must be private")
+	assert.False(t, k.Symmetric(), "generated key must be asymmetric")
+	assert.NotEmpty(t, k.SKI(), "generated key must have a SKI")
+
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	assert.False(t, pk.Private(), "public key must not be private")
+	assert.False(t, pk.Symmetric(), "public key must be asymmetric")
+	assert.Equal(t, k.SKI(), pk.SKI(), "private and public key must share the SKI")
+
+	raw, err := pk.Bytes()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, raw)
+
+	if s.SymmetricKeyGenOpts == nil {
+		return
+	}
+
+	k, err = csp.KeyGen(s.SymmetricKeyGenOpts)
+	require.NoError(t, err)
+	require.NotNil(t, k)
+	assert.True(t, k.Private(), "symmetric key must be private")
+	assert.True(t, k.Symmetric(), "symmetric key must be symmetric")
+	assert.NotEmpty(t, k.SKI(), "symmetric key must have a SKI")
+	_, err = k.PublicKey()
+	assert.Error(t, err, "PublicKey on a symmetric key must fail")
+}
+
+// RunKeyImport checks that a public key exported from a generated key can be
+// imported back and used to verify signatures of the original private key.
+func RunKeyImport(t *testing.T, csp bccsp.BCCSP, s *Suite) {
+	_, err := csp.KeyImport(nil, s.PublicKeyImportOpts)
+	assert.Error(t, err, "KeyImport with nil raw must fail")
+	_, err = csp.KeyImport([]byte{1, 2, 3}, nil)
+	assert.Error(t, err, "KeyImport with nil opts must fail")
+	_, err = csp.KeyImport([]byte{1, 2, 3}, s.PublicKeyImportOpts)
+	assert.Error(t, err, "KeyImport with invalid raw must fail")
+
+	k, err := csp.KeyGen(s.AsymmetricKeyGenOpts)
+	require.NoError(t, err)
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	raw, err := pk.Bytes()
+	require.NoError(t, err)
+
+	imported, err := csp.KeyImport(raw, s.PublicKeyImportOpts)
+	require.NoError(t, err)
+	assert.False(t, imported.Private())
+	assert.Equal(t, pk.SKI(), imported.SKI(), "imported key must keep the SKI")
+
+	digest := hash(t, csp, s, []byte("Hello World"))
+	signature, err := csp.Sign(k, digest, s.SignerOpts)
+	require.NoError(t, err)
+	valid, err := csp.Verify(imported, signature, digest, s.SignerOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid, "imported public key must verify the signature")
+}
+
+// RunGetKey checks that persisted keys can be retrieved by SKI.
+func RunGetKey(t *testing.T, csp bccsp.BCCSP, s *Suite) {
+	if s.AsymmetricKeyGenOpts.Ephemeral() {
+		t.Skip("asymmetric keys are ephemeral, nothing to retrieve")
+	}
+
+	_, err := csp.GetKey(nil)
+	assert.Error(t, err, "GetKey with nil SKI must fail")
+
+	k, err := csp.KeyGen(s.AsymmetricKeyGenOpts)
+	require.NoError(t, err)
+
+	k2, err := csp.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.True(t, k2.Private())
+	assert.False(t, k2.Symmetric())
+	assert.Equal(t, k.SKI(), k2.SKI())
+
+	digest := hash(t, csp, s, []byte("Hello World"))
+	signature, err := csp.Sign(k2, digest, s.SignerOpts)
+	require.NoError(t, err)
+	valid, err := csp.Verify(k, signature, digest, s.SignerOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid, "retrieved key must sign like the generated one")
+
+	if s.SymmetricKeyGenOpts == nil || s.SymmetricKeyGenOpts.Ephemeral() {
+		return
+	}
+
+	k, err = csp.KeyGen(s.SymmetricKeyGenOpts)
+	require.NoError(t, err)
+	k2, err = csp.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.True(t, k2.Symmetric())
+	assert.Equal(t, k.SKI(), k2.SKI())
+}
+
+// RunHash checks that Hash and GetHash agree.
+func RunHash(t *testing.T, csp bccsp.BCCSP, s *Suite) {
+	_, err := csp.Hash([]byte("Hello World"), nil)
+	assert.Error(t, err, "Hash with nil opts must fail")
+	_, err = csp.GetHash(nil)
+	assert.Error(t, err, "GetHash with nil opts must fail")
+
+	msg := []byte("Hello World")
+	digest, err := csp.Hash(msg, s.HashOpts)
+	require.NoError(t, err)
+	assert.NotEmpty(t, digest)
+
+	h, err := csp.GetHash(s.HashOpts)
+	require.NoError(t, err)
+	h.Write(msg)
+	assert.Equal(t, digest, h.Sum(nil), "Hash and GetHash must agree")
+
+	other, err := csp.Hash([]byte("Hello World!"), s.HashOpts)
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, other)
+}
+
+// RunSignVerify checks signature generation and verification with both the
+// private and the public part of a key.
+func RunSignVerify(t *testing.T, csp bccsp.BCCSP, s *Suite) {
+	k, err := csp.KeyGen(s.AsymmetricKeyGenOpts)
+	require.NoError(t, err)
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+
+	digest := hash(t, csp, s, []byte("Hello World"))
+
+	_, err = csp.Sign(nil, digest, s.SignerOpts)
+	assert.Error(t, err, "Sign with nil key must fail")
+	_, err = csp.Sign(k, nil, s.SignerOpts)
+	assert.Error(t, err, "Sign with empty digest must fail")
+
+	signature, err := csp.Sign(k, digest, s.SignerOpts)
+	require.NoError(t, err)
+	assert.NotEmpty(t, signature)
+
+	_, err = csp.Verify(nil, signature, digest, s.SignerOpts)
+	assert.Error(t, err, "Verify with nil key must fail")
+	_, err = csp.Verify(pk, nil, digest, s.SignerOpts)
+	assert.Error(t, err, "Verify with empty signature must fail")
+	_, err = csp.Verify(pk, signature, nil, s.SignerOpts)
+	assert.Error(t, err, "Verify with empty digest must fail")
+
+	valid, err := csp.Verify(k, signature, digest, s.SignerOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid, "private key must verify its own signature")
+
+	valid, err = csp.Verify(pk, signature, digest, s.SignerOpts)
+	assert.NoError(t, err)
+	assert.True(t, valid, "public key must verify the signature")
+
+	tampered := hash(t, csp, s, []byte("Hello World!"))
+	valid, _ = csp.Verify(pk, signature, tampered, s.SignerOpts)
+	assert.False(t, valid, "signature must not verify a different digest")
+
+	other, err := csp.KeyGen(s.AsymmetricKeyGenOpts)
+	require.NoError(t, err)
+	valid, _ = csp.Verify(other, signature, digest, s.SignerOpts)
+	assert.False(t, valid, "signature must not verify under a different key")
+}
+
+// RunEncryptDecrypt checks symmetric encryption round trips.
+func RunEncryptDecrypt(t *testing.T, csp bccsp.BCCSP, s *Suite) {
+	if s.SymmetricKeyGenOpts == nil {
+		t.Skip("no symmetric key generation options, skipping")
+	}
+
+	k, err := csp.KeyGen(s.SymmetricKeyGenOpts)
+	require.NoError(t, err)
+
+	// A single block keeps the scenario valid for block ciphers used
+	// without a padding mode.
+	plaintext := []byte("0123456789ABCDEF")
+
+	_, err = csp.Encrypt(nil, plaintext, s.EncrypterOpts)
+	assert.Error(t, err, "Encrypt with nil key must fail")
+	_, err = csp.Decrypt(nil, plaintext, s.DecrypterOpts)
+	assert.Error(t, err, "Decrypt with nil key must fail")
+
+	ciphertext, err := csp.Encrypt(k, plaintext, s.EncrypterOpts)
+	require.NoError(t, err)
+	assert.False(t, bytes.Equal(plaintext, ciphertext), "ciphertext must differ from plaintext")
+
+	decrypted, err := csp.Decrypt(k, ciphertext, s.DecrypterOpts)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	other, err := csp.KeyGen(s.SymmetricKeyGenOpts)
+	require.NoError(t, err)
+	decrypted, _ = csp.Decrypt(other, ciphertext, s.DecrypterOpts)
+	assert.NotEqual(t, plaintext, decrypted, "a different key must not decrypt the ciphertext")
+}
+
+func hash(t *testing.T, csp bccsp.BCCSP, s *Suite, msg []byte) []byte {
+	digest, err := csp.Hash(msg, s.HashOpts)
+	require.NoError(t, err)
+	return digest
+}
@@ -24,17 +24,36 @@ func (f *GMFactory) Name() string {
 // Get returns an instance of BCCSP using Opts.
 func (f *GMFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 	// Validate arguments
-	if config == nil || config.SwOpts == nil {
+	if config == nil || (config.GmOpts == nil && config.SwOpts == nil) {
 		return nil, errors.New("Invalid config. It must not be nil.")
 	}
 
-	gmOpts := config.SwOpts
+	gmOpts := config.GmOpts
+	if gmOpts == nil {
+		// Configurations predating GmOpts set up the GM provider through SwOpts
+		gmOpts = &GmOpts{
+			SecLevel:      config.SwOpts.SecLevel,
+			HashFamily:    config.SwOpts.HashFamily,
+			Ephemeral:     config.SwOpts.Ephemeral,
+			FileKeystore:  config.SwOpts.FileKeystore,
+			DummyKeystore: config.SwOpts.DummyKeystore,
+		}
+	}
+
+	var kms gm.KMSDriver
+	if gmOpts.KMS != nil {
+		var err error
+		kms, err = gm.NewKMSDriver(gmOpts.KMS.Driver, gmOpts.KMS.Config)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to initialize gm KMS driver: %s", err)
+		}
+	}
 
 	var ks bccsp.KeyStore
 	if gmOpts.Ephemeral == true {
 		ks = gm.NewDummyKeyStore()
 	} else if gmOpts.FileKeystore != nil {
-		fks, err := gm.NewFileBasedKeyStore(nil, gmOpts.FileKeystore.KeyStorePath, false)
+		fks, err := gm.NewFileBasedKeyStoreWithKMS(nil, gmOpts.FileKeystore.KeyStorePath, false, kms)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to initialize gm software key store: %s", err)
 		}
@@ -44,6 +63,36 @@ func (f *GMFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 		ks = gm.NewDummyKeyStore()
 	}
 
-	return gm.New(gmOpts.SecLevel, "GMSM3", ks)
-	//return gm.New(gmOpts.SecLevel, gmOpts.HashFamily, ks)
+	return gm.NewWithParams(gmOpts.SecLevel, "GMSM3", gmOpts.Curve, gmOpts.AllowP256, kms, ks)
+}
+
+// GmOpts contains options for the GMFactory
+type GmOpts struct {
+	// Default algorithms when not specified (Deprecated?)
+	SecLevel   int    `mapstructure:"security" json:"security" yaml:"Security"`
+	HashFamily string `mapstructure:"hash" json:"hash" yaml:"Hash"`
+
+	// Curve is the elliptic curve of the SM2 keys. Only "SM2" is supported,
+	// an empty value defaults to it.
+	Curve string `mapstructure:"curve,omitempty" json:"curve,omitempty" yaml:"Curve"`
+	// AllowP256 additionally accepts NIST P-256 ECDSA keys, which are
+	// otherwise rejected by the GM provider.
+	AllowP256 bool `mapstructure:"allowp256,omitempty" json:"allowp256,omitempty" yaml:"AllowP256"`
+
+	// Keystore Options
+	Ephemeral     bool               `mapstructure:"tempkeys,omitempty" json:"tempkeys,omitempty"`
+	FileKeystore  *FileKeystoreOpts  `mapstructure:"filekeystore,omitempty" json:"filekeystore,omitempty" yaml:"FileKeyStore"`
+	DummyKeystore *DummyKeystoreOpts `mapstructure:"dummykeystore,omitempty" json:"dummykeystore,omitempty"`
+
+	// KMS selects the key management service holding the keys generated
+	// and imported with the KMS key options
+	KMS *KMSOpts `mapstructure:"kms,omitempty" json:"kms,omitempty" yaml:"KMS"`
+}
+
+// KMSOpts contains options for the KMS backing the GM provider
+type KMSOpts struct {
+	// Driver is the name of the KMS driver, e.g. aliyun or inprocess
+	Driver string `mapstructure:"driver" json:"driver" yaml:"Driver"`
+	// Config map for the driver
+	Config map[string]interface{} `mapstructure:"config,omitempty" json:"config,omitempty" yaml:"Config"`
 }
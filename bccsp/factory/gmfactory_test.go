@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGMFactoryName(t *testing.T) {
+	f := &GMFactory{}
+	assert.Equal(t, f.Name(), GuomiBasedFactoryName)
+}
+
+func TestGMFactoryGetInvalidArgs(t *testing.T) {
+	f := &GMFactory{}
+
+	_, err := f.Get(nil)
+	assert.EqualError(t, err, "Invalid config. It must not be nil.")
+
+	_, err = f.Get(&FactoryOpts{})
+	assert.EqualError(t, err, "Invalid config. It must not be nil.")
+
+	_, err = f.Get(&FactoryOpts{GmOpts: &GmOpts{}})
+	assert.Error(t, err)
+
+	_, err = f.Get(&FactoryOpts{GmOpts: &GmOpts{SecLevel: 256, Curve: "P384"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Curve not supported [P384]")
+}
+
+func TestGMFactoryGet(t *testing.T) {
+	f := &GMFactory{}
+
+	csp, err := f.Get(&FactoryOpts{
+		GmOpts: &GmOpts{
+			SecLevel:   256,
+			HashFamily: "GMSM3",
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, bccsp.IsGMCryptoSuite(csp))
+
+	csp, err = f.Get(&FactoryOpts{
+		GmOpts: &GmOpts{
+			SecLevel:     256,
+			HashFamily:   "GMSM3",
+			Curve:        "SM2",
+			AllowP256:    true,
+			FileKeystore: &FileKeystoreOpts{KeyStorePath: os.TempDir()},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, csp)
+
+	// SwOpts are still honoured when GmOpts are not provided
+	csp, err = f.Get(&FactoryOpts{
+		SwOpts: &SwOpts{
+			SecLevel:   256,
+			HashFamily: "GMSM3",
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, bccsp.IsGMCryptoSuite(csp))
+}
+
+func TestGMFactoryGetWithKMS(t *testing.T) {
+	f := &GMFactory{}
+
+	_, err := f.Get(&FactoryOpts{
+		GmOpts: &GmOpts{
+			SecLevel:   256,
+			HashFamily: "GMSM3",
+			KMS:        &KMSOpts{Driver: "foo"},
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "KMS driver not found [foo]")
+
+	csp, err := f.Get(&FactoryOpts{
+		GmOpts: &GmOpts{
+			SecLevel:     256,
+			HashFamily:   "GMSM3",
+			FileKeystore: &FileKeystoreOpts{KeyStorePath: os.TempDir()},
+			KMS:          &KMSOpts{Driver: "inprocess"},
+		},
+	})
+	assert.NoError(t, err)
+
+	k, err := csp.KeyGen(&bccsp.KMSGMSM2KeyGenOpts{})
+	assert.NoError(t, err)
+	k2, err := csp.GetKey(k.SKI())
+	assert.NoError(t, err)
+	assert.Equal(t, k.SKI(), k2.SKI())
+}
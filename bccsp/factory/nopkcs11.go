@@ -19,6 +19,7 @@ const pkcs11Enabled = false
 type FactoryOpts struct {
 	ProviderName string      `mapstructure:"default" json:"default" yaml:"Default"`
 	SwOpts       *SwOpts     `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SwOpts"`
+	GmOpts       *GmOpts     `mapstructure:"GM,omitempty" json:"GM,omitempty" yaml:"GmOpts"`
 	PluginOpts   *PluginOpts `mapstructure:"PLUGIN,omitempty" json:"PLUGIN,omitempty" yaml:"PluginOpts"`
 }
 
@@ -20,6 +20,7 @@ const pkcs11Enabled = true
 type FactoryOpts struct {
 	ProviderName string             `mapstructure:"default" json:"default" yaml:"Default"`
 	SwOpts       *SwOpts            `mapstructure:"SW,omitempty" json:"SW,omitempty" yaml:"SwOpts"`
+	GmOpts       *GmOpts            `mapstructure:"GM,omitempty" json:"GM,omitempty" yaml:"GmOpts"`
 	PluginOpts   *PluginOpts        `mapstructure:"PLUGIN,omitempty" json:"PLUGIN,omitempty" yaml:"PluginOpts"`
 	Pkcs11Opts   *pkcs11.PKCS11Opts `mapstructure:"PKCS11,omitempty" json:"PKCS11,omitempty" yaml:"PKCS11"`
 }
@@ -62,7 +63,7 @@ func initFactories(config *FactoryOpts) error {
 		}
 	}
 
-	if config.ProviderName == "GM" && config.SwOpts != nil {
+	if config.ProviderName == "GM" && (config.GmOpts != nil || config.SwOpts != nil) {
 		f := &GMFactory{}
 		err := initBCCSP(f, config)
 		if err != nil {
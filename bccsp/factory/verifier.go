@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"sync"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// The verifiers are ephemeral software BCCSPs handling the signatures of
+// the identities whose algorithm differs from the one of the BCCSP in use,
+// as in channels hosting both organizations using ECDSA and organizations
+// using SM2.
+var (
+	swVerifier        bccsp.BCCSP
+	swVerifierErr     error
+	swVerifierInitOne sync.Once
+
+	gmVerifier        bccsp.BCCSP
+	gmVerifierErr     error
+	gmVerifierInitOne sync.Once
+)
+
+// GetBCCSPForPublicKey returns a BCCSP able to import pk and to verify the
+// signatures it produced. This is csp when it implements the algorithm of
+// pk: the GM provider for the keys on the SM2 curve, any other provider for
+// the remaining keys. Otherwise, an ephemeral software BCCSP implementing
+// the algorithm is returned.
+func GetBCCSPForPublicKey(csp bccsp.BCCSP, pk crypto.PublicKey) (bccsp.BCCSP, error) {
+	if pk == nil {
+		return nil, errors.New("public key must be different from nil")
+	}
+
+	gmKey := isSM2PublicKey(pk)
+	if csp != nil && gmKey == bccsp.IsGMCryptoSuite(csp) {
+		return csp, nil
+	}
+	if gmKey {
+		return getGMVerifier()
+	}
+	return getSWVerifier()
+}
+
+// GetBCCSPForHash returns csp when it implements the hash function of opts.
+// Otherwise, it returns the ephemeral GM provider, which implements the
+// SHA2, SHA3 and SM3 hash families.
+func GetBCCSPForHash(csp bccsp.BCCSP, opts bccsp.HashOpts) (bccsp.BCCSP, error) {
+	if opts == nil {
+		return nil, errors.New("hash options must be different from nil")
+	}
+	if csp != nil {
+		if _, err := csp.GetHash(opts); err == nil {
+			return csp, nil
+		}
+	}
+	return getGMVerifier()
+}
+
+func isSM2PublicKey(pk crypto.PublicKey) bool {
+	switch k := pk.(type) {
+	case *sm2.PublicKey:
+		return true
+	case *ecdsa.PublicKey:
+		return k.Curve == sm2.P256Sm2()
+	default:
+		return false
+	}
+}
+
+func getSWVerifier() (bccsp.BCCSP, error) {
+	swVerifierInitOne.Do(func() {
+		swVerifier, swVerifierErr = (&SWFactory{}).Get(GetDefaultOpts())
+		if swVerifierErr != nil {
+			swVerifierErr = errors.WithMessage(swVerifierErr, "failed initializing SW verifier")
+		}
+	})
+	return swVerifier, swVerifierErr
+}
+
+func getGMVerifier() (bccsp.BCCSP, error) {
+	gmVerifierInitOne.Do(func() {
+		gmVerifier, gmVerifierErr = (&GMFactory{}).Get(GetGMOpts())
+		if gmVerifierErr != nil {
+			gmVerifierErr = errors.WithMessage(gmVerifierErr, "failed initializing GM verifier")
+		}
+	})
+	return gmVerifier, gmVerifierErr
+}
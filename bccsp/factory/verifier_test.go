@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package factory
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBCCSPForPublicKey(t *testing.T) {
+	swCSP, err := (&SWFactory{}).Get(GetDefaultOpts())
+	require.NoError(t, err)
+	gmCSP, err := (&GMFactory{}).Get(GetGMOpts())
+	require.NoError(t, err)
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	sm2Key, err := sm2.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sm2ECDSAKey := &ecdsa.PublicKey{Curve: sm2Key.Curve, X: sm2Key.X, Y: sm2Key.Y}
+
+	_, err = GetBCCSPForPublicKey(swCSP, nil)
+	assert.EqualError(t, err, "public key must be different from nil")
+
+	// The given provider is kept when it implements the algorithm of the key
+	csp, err := GetBCCSPForPublicKey(swCSP, &ecdsaKey.PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, swCSP, csp)
+	csp, err = GetBCCSPForPublicKey(gmCSP, &sm2Key.PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, gmCSP, csp)
+	csp, err = GetBCCSPForPublicKey(gmCSP, sm2ECDSAKey)
+	assert.NoError(t, err)
+	assert.Equal(t, gmCSP, csp)
+
+	// Otherwise, a provider of the algorithm of the key is returned
+	for _, pk := range []interface{}{&sm2Key.PublicKey, sm2ECDSAKey} {
+		csp, err = GetBCCSPForPublicKey(swCSP, pk)
+		assert.NoError(t, err)
+		assert.True(t, bccsp.IsGMCryptoSuite(csp))
+		_, err = csp.KeyImport(sm2ECDSAKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+		assert.NoError(t, err)
+	}
+
+	csp, err = GetBCCSPForPublicKey(gmCSP, &ecdsaKey.PublicKey)
+	assert.NoError(t, err)
+	assert.False(t, bccsp.IsGMCryptoSuite(csp))
+	_, err = csp.KeyImport(&ecdsaKey.PublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	assert.NoError(t, err)
+
+	csp2, err := GetBCCSPForPublicKey(nil, &ecdsaKey.PublicKey)
+	assert.NoError(t, err)
+	assert.Equal(t, csp, csp2)
+}
+
+func TestGetBCCSPForHash(t *testing.T) {
+	swCSP, err := (&SWFactory{}).Get(GetDefaultOpts())
+	require.NoError(t, err)
+
+	_, err = GetBCCSPForHash(swCSP, nil)
+	assert.EqualError(t, err, "hash options must be different from nil")
+
+	csp, err := GetBCCSPForHash(swCSP, &bccsp.SHA256Opts{})
+	assert.NoError(t, err)
+	assert.Equal(t, swCSP, csp)
+
+	csp, err = GetBCCSPForHash(swCSP, &bccsp.GMSM3Opts{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, swCSP, csp)
+	_, err = csp.Hash([]byte("Hello World"), &bccsp.GMSM3Opts{})
+	assert.NoError(t, err)
+}
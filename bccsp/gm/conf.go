@@ -20,14 +20,25 @@ import (
 	"fmt"
 	"hash"
 
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
 	"github.com/Hyperledger-TWGC/tjfoc-gm/sm3"
 )
 
+const (
+	// CurveSM2 is the name of the SM2 recommended curve (GM/T 0003.5), the only
+	// curve the GM provider generates keys on.
+	CurveSM2 = "SM2"
+)
+
 type config struct {
 	ellipticCurve elliptic.Curve
 	hashFunction  func() hash.Hash
 	aesBitLength  int
 	rsaBitLength  int
+
+	// allowP256 enables the compatibility mode in which NIST P-256 ECDSA keys
+	// can be imported and used next to SM2 keys.
+	allowP256 bool
 }
 
 func (conf *config) setSecurityLevel(securityLevel int, hashFamily string) (err error) {
@@ -38,12 +49,15 @@ func (conf *config) setSecurityLevel(securityLevel int, hashFamily string) (err
 func (conf *config) setSecurityLevelGMSM3(level int) (err error) {
 	switch level {
 	case 256:
-		conf.ellipticCurve = elliptic.P256()
+		conf.ellipticCurve = sm2.P256Sm2()
 		conf.hashFunction = sm3.New
 		conf.rsaBitLength = 2048
 		conf.aesBitLength = 32
 	case 384:
-		conf.ellipticCurve = elliptic.P384()
+		// SM2 only defines a 256-bit curve. Level 384 used to select P-384, and is
+		// kept so that existing configurations still load, with keys on the SM2 curve.
+		logger.Warningf("Security level 384 is deprecated, keys are generated on the SM2 curve as with level 256")
+		conf.ellipticCurve = sm2.P256Sm2()
 		conf.hashFunction = sm3.New
 		conf.rsaBitLength = 3072
 		conf.aesBitLength = 32
@@ -52,3 +66,28 @@ func (conf *config) setSecurityLevelGMSM3(level int) (err error) {
 	}
 	return
 }
+
+func (conf *config) setCurve(curve string, allowP256 bool) error {
+	switch curve {
+	case "", CurveSM2:
+		conf.ellipticCurve = sm2.P256Sm2()
+	default:
+		return fmt.Errorf("Curve not supported [%s]", curve)
+	}
+	conf.allowP256 = allowP256
+	return nil
+}
+
+// checkCurve returns an error if keys on curve cannot be used by the provider.
+func (conf *config) checkCurve(curve elliptic.Curve) error {
+	switch {
+	case curve == sm2.P256Sm2():
+		return nil
+	case curve == elliptic.P256() && conf.allowP256:
+		return nil
+	case curve == elliptic.P256():
+		return fmt.Errorf("P-256 keys are not accepted, P-256 compatibility is disabled")
+	default:
+		return fmt.Errorf("Curve not supported [%s]", curve.Params().Name)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"testing"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityLevels(t *testing.T) {
+	conf := &config{}
+	assert.NoError(t, conf.setSecurityLevel(256, "GMSM3"))
+	assert.Equal(t, sm2.P256Sm2(), conf.ellipticCurve)
+
+	// level 384 is kept for the existing configurations, and maps to the SM2 curve too
+	assert.NoError(t, conf.setSecurityLevel(384, "GMSM3"))
+	assert.Equal(t, sm2.P256Sm2(), conf.ellipticCurve)
+
+	assert.EqualError(t, conf.setSecurityLevel(512, "GMSM3"), "Security level not supported [512]")
+
+	assert.NoError(t, conf.setCurve("", false))
+	assert.NoError(t, conf.setCurve(CurveSM2, true))
+	assert.True(t, conf.allowP256)
+	assert.EqualError(t, conf.setCurve("P256", false), "Curve not supported [P256]")
+}
+
+func TestKeyGenAlwaysSM2(t *testing.T) {
+	csp, err := NewWithParams(256, "GMSM3", CurveSM2, true, nil, NewDummyKeyStore())
+	require.NoError(t, err)
+
+	for _, opts := range []bccsp.KeyGenOpts{
+		&bccsp.GMSM2KeyGenOpts{Temporary: true},
+		&bccsp.ECDSAKeyGenOpts{Temporary: true},
+		&bccsp.ECDSAP256KeyGenOpts{Temporary: true},
+	} {
+		k, err := csp.KeyGen(opts)
+		require.NoError(t, err)
+		sk, ok := k.(*gmsm2PrivateKey)
+		require.True(t, ok, "KeyGen with [%T] must produce an SM2 key", opts)
+		assert.Equal(t, sm2.P256Sm2(), sk.privKey.Curve)
+	}
+
+	_, err = csp.KeyGen(&bccsp.ECDSAP384KeyGenOpts{Temporary: true})
+	assert.Error(t, err)
+}
+
+func TestP256KeysRejectedByDefault(t *testing.T) {
+	csp, err := New(256, "GMSM3", NewDummyKeyStore())
+	require.NoError(t, err)
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = csp.KeyImport(&p256Key.PublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "P-256 compatibility is disabled")
+
+	pkix, err := x509.MarshalPKIXPublicKey(&p256Key.PublicKey)
+	require.NoError(t, err)
+	_, err = csp.KeyImport(pkix, &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "P-256 compatibility is disabled")
+
+	der, err := x509.MarshalECPrivateKey(p256Key)
+	require.NoError(t, err)
+	_, err = csp.KeyImport(der, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "P-256 compatibility is disabled")
+
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	_, err = csp.KeyImport(&p384Key.PublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Curve not supported")
+}
+
+func TestP256Compatibility(t *testing.T) {
+	csp, err := NewWithParams(256, "GMSM3", CurveSM2, true, nil, NewDummyKeyStore())
+	require.NoError(t, err)
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(p256Key)
+	require.NoError(t, err)
+
+	sk, err := csp.KeyImport(der, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	pk, err := csp.KeyImport(&p256Key.PublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, sk.SKI(), pk.SKI())
+
+	// P-256 keys sign with plain ECDSA, so the signature verifies with the
+	// standard library.
+	digest := sha256.Sum256([]byte("Hello World"))
+	signature, err := csp.Sign(sk, digest[:], nil)
+	require.NoError(t, err)
+	r, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	assert.True(t, ecdsa.Verify(&p256Key.PublicKey, digest[:], r, s))
+
+	valid, err := csp.Verify(pk, signature, digest[:], nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSM2CurveECDSAKeysBecomeSM2Keys(t *testing.T) {
+	csp, err := New(256, "GMSM3", NewDummyKeyStore())
+	require.NoError(t, err)
+
+	sm2Key, err := sm2.GenerateKey(nil)
+	require.NoError(t, err)
+
+	k, err := csp.KeyImport(&ecdsa.PublicKey{Curve: sm2Key.Curve, X: sm2Key.X, Y: sm2Key.Y}, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	_, ok := k.(*gmsm2PublicKey)
+	assert.True(t, ok)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/utils"
+)
+
+// signECDSA signs digest with a NIST P-256 key, which the GM provider only accepts
+// when P-256 compatibility is enabled. The signature is plain ECDSA over the digest
+// given by the caller with a low S, so that it verifies with the SW provider.
+func signECDSA(k *ecdsa.PrivateKey, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err = utils.ToLowS(&k.PublicKey, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.MarshalECDSASignature(r, s)
+}
+
+// verifyECDSA verifies a low-S ECDSA signature of digest with a NIST P-256 key,
+// such as the signatures of signECDSA and of the SW provider.
+func verifyECDSA(k *ecdsa.PublicKey, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	r, s, err := utils.UnmarshalECDSASignature(signature)
+	if err != nil {
+		return false, fmt.Errorf("Failed unmashalling signature [%s]", err)
+	}
+
+	lowS, err := utils.IsLowS(k, s)
+	if err != nil {
+		return false, err
+	}
+
+	if !lowS {
+		return false, fmt.Errorf("Invalid S. Must be smaller than half the order [%s][%s].", s, utils.GetCurveHalfOrdersAt(k.Curve))
+	}
+
+	return ecdsa.Verify(k, digest, r, s), nil
+}
+
+type ecdsaPrivateKeySigner struct{}
+
+func (s *ecdsaPrivateKeySigner) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	return signECDSA(k.(*ecdsaPrivateKey).privKey, digest, opts)
+}
+
+type ecdsaPrivateKeyVerifier struct{}
+
+func (v *ecdsaPrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyECDSA(&(k.(*ecdsaPrivateKey).privKey.PublicKey), signature, digest, opts)
+}
+
+type ecdsaPublicKeyKeyVerifier struct{}
+
+func (v *ecdsaPublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	return verifyECDSA(k.(*ecdsaPublicKey).pubKey, signature, digest, opts)
+}
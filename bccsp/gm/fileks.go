@@ -32,7 +32,6 @@ import (
 	"github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/utils"
-	kmssm2 "github.com/tw-bc-group/aliyun-kms/sm2"
 )
 
 // NewFileBasedKeyStore instantiated a file-based key store at a given position.
@@ -44,6 +43,14 @@ func NewFileBasedKeyStore(pwd []byte, path string, readOnly bool) (bccsp.KeyStor
 	return ks, ks.Init(pwd, path, readOnly)
 }
 
+// NewFileBasedKeyStoreWithKMS instantiated a file-based key store at a given
+// position which also keeps track of the keys held by the passed KMS.
+// Only the identifiers of those keys are stored in the folder.
+func NewFileBasedKeyStoreWithKMS(pwd []byte, path string, readOnly bool, kms KMSDriver) (bccsp.KeyStore, error) {
+	ks := &fileBasedKeyStore{kms: kms}
+	return ks, ks.Init(pwd, path, readOnly)
+}
+
 // fileBasedKeyStore is a folder-based KeyStore.
 // Each key is stored in a separated file whose name contains the key's SKI
 // and flags to identity the key's type. All the keys are stored in
@@ -59,6 +66,9 @@ type fileBasedKeyStore struct {
 
 	pwd []byte
 
+	// kms resolves the identifiers of the keys held by a KMS
+	kms KMSDriver
+
 	// Sync
 	m sync.Mutex
 }
@@ -126,6 +136,10 @@ func (ks *fileBasedKeyStore) GetKey(ski []byte) (k bccsp.Key, err error) {
 		if err != nil {
 			return nil, fmt.Errorf("Failed loading key [%x] [%s]", ski, err)
 		}
+		// The SM4 keys generated with 32 bytes before cannot be used by the SM4 cipher
+		if len(key) != sm4KeyLength {
+			return nil, fmt.Errorf("Invalid GMSM4 key [%x]: %d bytes instead of %d, the key must be generated again", ski, len(key), sm4KeyLength)
+		}
 
 		return &gmsm4PrivateKey{key, false}, nil
 	case "sk":
@@ -158,11 +172,11 @@ func (ks *fileBasedKeyStore) GetKey(ski []byte) (k bccsp.Key, err error) {
 			return nil, errors.New("Public key type not recognized")
 		}
 	case "kms":
-		res, err := ks.loadKMSPrivateKey(hex.EncodeToString(ski))
+		key, err := ks.loadKMSPrivateKey(hex.EncodeToString(ski))
 		if err != nil {
-			return nil, fmt.Errorf("Failed loading kms sm2 key adapter [%x] [%s]", ski, err)
+			return nil, fmt.Errorf("Failed loading kms sm2 key [%x] [%s]", ski, err)
 		}
-		return &kmsSm2PrivateKey{adapter: res.(*kmssm2.KeyAdapter)}, nil
+		return key, nil
 	default:
 		return ks.searchKeystoreForSKI(ski)
 	}
@@ -203,8 +217,8 @@ func (ks *fileBasedKeyStore) StoreKey(k bccsp.Key) (err error) {
 		}
 	case *kmsSm2PrivateKey:
 		kk := k.(*kmsSm2PrivateKey)
-		if err = ks.storeKMSPrivateKey(hex.EncodeToString(k.SKI()), kk.adapter.KeyID()); err != nil {
-			return fmt.Errorf("Failed storing GMSM2 private key [%s]", err)
+		if err = ks.storeKMSPrivateKey(hex.EncodeToString(k.SKI()), kk.keyID); err != nil {
+			return fmt.Errorf("Failed storing KMS GMSM2 key [%s]", err)
 		}
 
 	default:
@@ -269,21 +283,17 @@ func (ks *fileBasedKeyStore) getSuffix(alias string) string {
 	return ""
 }
 
-func (ks *fileBasedKeyStore) loadKMSPrivateKey(alias string) (interface{}, error) {
+func (ks *fileBasedKeyStore) loadKMSPrivateKey(alias string) (*kmsSm2PrivateKey, error) {
 	path := ks.getPathForAlias(alias, "kms")
+	logger.Debugf("Loading kms key [%s] at [%s]...", alias, path)
 
 	keyID, err := ioutil.ReadFile(path)
 	if err != nil {
-		logger.Errorf("Failed loading private key [%s]: [%s].", alias, err.Error())
-		return nil, err
-	}
-
-	adapter, err := kmssm2.CreateSm2KeyAdapter(string(keyID), kmssm2.SignAndVerify)
-	if err != nil {
+		logger.Errorf("Failed loading kms key [%s]: [%s].", alias, err.Error())
 		return nil, err
 	}
 
-	return adapter, nil
+	return loadKmsSm2PrivateKey(ks.kms, string(keyID))
 }
 
 func (ks *fileBasedKeyStore) storeKMSPrivateKey(alias, keyID string) error {
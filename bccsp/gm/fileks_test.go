@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileKeyStoreInvalidInit(t *testing.T) {
+	_, err := NewFileBasedKeyStore(nil, "", false)
+	assert.EqualError(t, err, "An invalid KeyStore path provided. Path cannot be an empty string.")
+}
+
+func TestFileKeyStoreRoundTrip(t *testing.T) {
+	td, err := ioutil.TempDir("", "bccsp-gm-ks")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+
+	ks, err := NewFileBasedKeyStore(nil, td, false)
+	require.NoError(t, err)
+	assert.False(t, ks.ReadOnly())
+
+	csp, err := New(256, "SM3", NewDummyKeyStore())
+	require.NoError(t, err)
+
+	sm2Key, err := csp.KeyGen(&bccsp.GMSM2KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	sm2PubKey, err := sm2Key.PublicKey()
+	require.NoError(t, err)
+	sm4Key, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	for _, k := range []bccsp.Key{sm2Key, sm2PubKey, sm4Key} {
+		require.NoError(t, ks.StoreKey(k))
+
+		k2, err := ks.GetKey(k.SKI())
+		require.NoError(t, err)
+		assert.Equal(t, k.SKI(), k2.SKI())
+		assert.Equal(t, k.Private(), k2.Private())
+		assert.Equal(t, k.Symmetric(), k2.Symmetric())
+	}
+
+	// Reopening the folder gives access to the same keys.
+	ks2, err := NewFileBasedKeyStore(nil, td, true)
+	require.NoError(t, err)
+	k, err := ks2.GetKey(sm2Key.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, sm2Key.SKI(), k.SKI())
+}
+
+func TestFileKeyStoreErrors(t *testing.T) {
+	td, err := ioutil.TempDir("", "bccsp-gm-ks")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+
+	ks, err := NewFileBasedKeyStore(nil, td, false)
+	require.NoError(t, err)
+
+	_, err = ks.GetKey(nil)
+	assert.EqualError(t, err, "Invalid SKI. Cannot be of zero length.")
+
+	_, err = ks.GetKey([]byte("unknown"))
+	assert.Error(t, err)
+
+	assert.EqualError(t, ks.StoreKey(nil), "Invalid key. It must be different from nil")
+
+	ro, err := NewFileBasedKeyStore(nil, td, true)
+	require.NoError(t, err)
+	assert.True(t, ro.ReadOnly())
+	csp, err := New(256, "SM3", NewDummyKeyStore())
+	require.NoError(t, err)
+	k, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.EqualError(t, ro.StoreKey(k), "Read only KeyStore")
+}
+
+func TestDummyKeyStore(t *testing.T) {
+	ks := NewDummyKeyStore()
+	assert.True(t, ks.ReadOnly())
+
+	_, err := ks.GetKey([]byte{1})
+	assert.Error(t, err)
+	assert.Error(t, ks.StoreKey(nil))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Examples from GM/T 0004 (GB/T 32905).
+func TestSM3KnownAnswer(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	tests := []struct {
+		msg    string
+		digest string
+	}{
+		{"abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{strings.Repeat("abcd", 16), "debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732"},
+	}
+
+	for _, tt := range tests {
+		digest, err := csp.Hash([]byte(tt.msg), &bccsp.GMSM3Opts{})
+		require.NoError(t, err)
+		assert.Equal(t, decodeHex(t, tt.digest), digest)
+
+		// SHAOpts selects the provider's default hash, which is SM3.
+		digest, err = csp.Hash([]byte(tt.msg), &bccsp.SHAOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, decodeHex(t, tt.digest), digest)
+
+		h, err := csp.GetHash(&bccsp.GMSM3Opts{})
+		require.NoError(t, err)
+		h.Write([]byte(tt.msg))
+		assert.Equal(t, decodeHex(t, tt.digest), h.Sum(nil))
+	}
+}
+
+func TestHashUnsupportedOpts(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	_, err := csp.Hash([]byte("abc"), &mocks.HashOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported 'HashOpt' provided")
+
+	_, err = csp.GetHash(&mocks.HashOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported 'HashOpt' provided")
+}
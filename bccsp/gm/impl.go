@@ -51,13 +51,25 @@ func NewDefaultSecurityLevelWithKeystore(keyStore bccsp.KeyStore) (bccsp.BCCSP,
 
 // New 实例化 返回支持国密算法的 bccsp.BCCSP
 func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
+	return NewWithParams(securityLevel, hashFamily, CurveSM2, false, nil, keyStore)
+}
 
+// NewWithParams returns a new instance of the GM BCCSP whose keys live on
+// the given curve. When allowP256 is true, NIST P-256 ECDSA keys are accepted
+// as well, for networks still holding ECDSA identities. The keys generated
+// and imported with the KMS options are held by kms, which can be nil when
+// no KMS is used.
+func NewWithParams(securityLevel int, hashFamily, curve string, allowP256 bool, kms KMSDriver, keyStore bccsp.KeyStore) (bccsp.BCCSP, error) {
 	// Init config
 	conf := &config{}
 	err := conf.setSecurityLevel(securityLevel, hashFamily)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed initializing configuration at [%v,%v]", securityLevel, hashFamily)
 	}
+	err = conf.setCurve(curve, allowP256)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing configuration at [%v,%v]", curve, allowP256)
+	}
 
 	// Check KeyStore
 	if keyStore == nil {
@@ -67,11 +79,14 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	// Set the encryptors
 	encryptors := make(map[reflect.Type]Encryptor)
 	encryptors[reflect.TypeOf(&gmsm4PrivateKey{})] = &gmsm4Encryptor{} //sm4 加密选项
+	encryptors[reflect.TypeOf(&gmsm2PrivateKey{})] = &gmsm2Encryptor{}
+	encryptors[reflect.TypeOf(&gmsm2PublicKey{})] = &gmsm2Encryptor{}
+	encryptors[reflect.TypeOf(&kmsSm2PrivateKey{})] = &gmsm2Encryptor{}
 
 	// Set the decryptors
 	decryptors := make(map[reflect.Type]Decryptor)
 	decryptors[reflect.TypeOf(&gmsm4PrivateKey{})] = &gmsm4Decryptor{} //sm4 解密选项
-	decryptors[reflect.TypeOf(&gmsm2PrivateKey{})] = &gmsm2Decryptor{} //sm4 解密选项
+	decryptors[reflect.TypeOf(&gmsm2PrivateKey{})] = &gmsm2Decryptor{} //sm2 解密选项
 
 	// Set the signers
 	signers := make(map[reflect.Type]Signer)
@@ -83,6 +98,7 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	verifiers := make(map[reflect.Type]Verifier)
 	verifiers[reflect.TypeOf(&gmsm2PrivateKey{})] = &gmsm2PrivateKeyVerifier{}  //sm2 私钥验签
 	verifiers[reflect.TypeOf(&gmsm2PublicKey{})] = &gmsm2PublicKeyKeyVerifier{} //sm2 公钥验签
+	verifiers[reflect.TypeOf(&kmsSm2PrivateKey{})] = &kmssm2PrivateKeyVerifier{}
 	verifiers[reflect.TypeOf(&ecdsaPrivateKey{})] = &ecdsaPrivateKeyVerifier{}
 	verifiers[reflect.TypeOf(&ecdsaPublicKey{})] = &ecdsaPublicKeyKeyVerifier{}
 
@@ -107,8 +123,13 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 	// Set the key generators
 	keyGenerators := make(map[reflect.Type]KeyGenerator)
 	keyGenerators[reflect.TypeOf(&bccsp.GMSM2KeyGenOpts{})] = &gmsm2KeyGenerator{}
-	keyGenerators[reflect.TypeOf(&bccsp.KMSGMSM2KeyGenOpts{})] = &kmssm2KeyGenerator{}
-	keyGenerators[reflect.TypeOf(&bccsp.GMSM4KeyGenOpts{})] = &gmsm4KeyGenerator{length: 32}
+	keyGenerators[reflect.TypeOf(&bccsp.ECDSAKeyGenOpts{})] = &gmsm2KeyGenerator{}
+	keyGenerators[reflect.TypeOf(&bccsp.ECDSAP256KeyGenOpts{})] = &gmsm2KeyGenerator{}
+	keyGenerators[reflect.TypeOf(&bccsp.KMSGMSM2KeyGenOpts{})] = &kmssm2KeyGenerator{kms: kms}
+	// SM4 keys are 128 bits long (GM/T 0002). The keys used to be generated with 32 bytes,
+	// which the SM4 cipher rejects: such keys in existing key stores cannot be used and are
+	// rejected when loaded, they have to be generated again.
+	keyGenerators[reflect.TypeOf(&bccsp.GMSM4KeyGenOpts{})] = &gmsm4KeyGenerator{length: sm4KeyLength}
 	impl.keyGenerators = keyGenerators
 
 	// Set the key derivers
@@ -117,14 +138,14 @@ func New(securityLevel int, hashFamily string, keyStore bccsp.KeyStore) (bccsp.B
 
 	// Set the key importers
 	keyImporters := make(map[reflect.Type]KeyImporter)
-	keyImporters[reflect.TypeOf(&bccsp.KMSGMSM2KeyImportOpts{})] = &kmssm2ImportKeyOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.KMSGMSM2KeyImportOpts{})] = &kmssm2ImportKeyOptsKeyImporter{kms: kms}
 	keyImporters[reflect.TypeOf(&bccsp.GMSM4ImportKeyOpts{})] = &gmsm4ImportKeyOptsKeyImporter{}
 	keyImporters[reflect.TypeOf(&bccsp.GMSM2PrivateKeyImportOpts{})] = &gmsm2PrivateKeyImportOptsKeyImporter{}
 	keyImporters[reflect.TypeOf(&bccsp.GMSM2PublicKeyImportOpts{})] = &gmsm2PublicKeyImportOptsKeyImporter{}
 	keyImporters[reflect.TypeOf(&bccsp.X509PublicKeyImportOpts{})] = &x509PublicKeyImportOptsKeyImporter{bccsp: impl}
-	keyImporters[reflect.TypeOf(&bccsp.ECDSAGoPublicKeyImportOpts{})] = &ecdsaGoPublicKeyImportOptsKeyImporter{}
-	keyImporters[reflect.TypeOf(&bccsp.ECDSAPrivateKeyImportOpts{})] = &ecdsaPrivateKeyImportOptsKeyImporter{}
-	keyImporters[reflect.TypeOf(&bccsp.ECDSAPKIXPublicKeyImportOpts{})] = &ecdsaPKIXPublicKeyImportOptsKeyImporter{}
+	keyImporters[reflect.TypeOf(&bccsp.ECDSAGoPublicKeyImportOpts{})] = &ecdsaGoPublicKeyImportOptsKeyImporter{conf: conf}
+	keyImporters[reflect.TypeOf(&bccsp.ECDSAPrivateKeyImportOpts{})] = &ecdsaPrivateKeyImportOptsKeyImporter{conf: conf}
+	keyImporters[reflect.TypeOf(&bccsp.ECDSAPKIXPublicKeyImportOpts{})] = &ecdsaPKIXPublicKeyImportOptsKeyImporter{conf: conf}
 
 	impl.keyImporters = keyImporters
 	return impl, nil
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/conformance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T) (bccsp.BCCSP, bccsp.KeyStore, func()) {
+	td, err := ioutil.TempDir("", "bccsp-gm")
+	require.NoError(t, err)
+	ks, err := NewFileBasedKeyStore(nil, td, false)
+	require.NoError(t, err)
+	csp, err := New(256, "SM3", ks)
+	require.NoError(t, err)
+	return csp, ks, func() { os.RemoveAll(td) }
+}
+
+func TestConformance(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	conformance.Run(t, csp, &conformance.Suite{
+		AsymmetricKeyGenOpts: &bccsp.GMSM2KeyGenOpts{Temporary: false},
+		PublicKeyImportOpts:  &bccsp.GMSM2PublicKeyImportOpts{Temporary: true},
+		SymmetricKeyGenOpts:  &bccsp.GMSM4KeyGenOpts{Temporary: false},
+		HashOpts:             &bccsp.GMSM3Opts{},
+	})
+}
+
+func TestInvalidNewParameter(t *testing.T) {
+	_, ks, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	r, err := New(0, "SM3", ks)
+	assert.Error(t, err)
+	assert.Nil(t, r)
+
+	r, err = New(256, "SM3", nil)
+	assert.Error(t, err)
+	assert.Nil(t, r)
+}
+
+func TestNewDefaultSecurityLevel(t *testing.T) {
+	td, err := ioutil.TempDir("", "bccsp-gm")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+
+	csp, err := NewDefaultSecurityLevel(td)
+	require.NoError(t, err)
+	assert.True(t, bccsp.IsGMCryptoSuite(csp))
+
+	csp, err = NewDefaultSecurityLevelWithKeystore(NewDummyKeyStore())
+	require.NoError(t, err)
+	assert.True(t, bccsp.IsGMCryptoSuite(csp))
+}
+
+func TestUnsupportedOpts(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	_, err := csp.KeyGen(&bccsp.RSAKeyGenOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported 'KeyGenOpts' provided")
+
+	_, err = csp.KeyImport([]byte{1}, &bccsp.AES256ImportKeyOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported 'KeyImportOpts' provided")
+
+	k, err := csp.KeyGen(&bccsp.GMSM2KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = csp.KeyDeriv(k, &bccsp.ECDSAReRandKeyOpts{Temporary: true})
+	assert.Error(t, err)
+
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	_, err = csp.Decrypt(pk, []byte("ciphertext"), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported 'DecryptKey' provided")
+}
+
+func TestKeyGenEphemeralIsNotStored(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	k, err := csp.KeyGen(&bccsp.GMSM2KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	_, err = csp.GetKey(k.SKI())
+	assert.Error(t, err)
+}
@@ -36,6 +36,9 @@ func (gm *gmsm2KeyGenerator) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err err
 	return &gmsm2PrivateKey{privKey}, nil
 }
 
+// sm4KeyLength is the length in bytes of the SM4 keys
+const sm4KeyLength = 16
+
 //定义国密SM4 keygen 结构体，实现 KeyGenerator 接口
 type gmsm4KeyGenerator struct {
 	length int
@@ -50,8 +53,20 @@ func (gm *gmsm4KeyGenerator) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err err
 	return &gmsm4PrivateKey{lowLevelKey, false}, nil
 }
 
-type kmssm2KeyGenerator struct{}
+//定义KMS托管的SM2 keygen 结构体，实现 KeyGenerator 接口
+type kmssm2KeyGenerator struct {
+	kms KMSDriver
+}
 
 func (gm *kmssm2KeyGenerator) KeyGen(_ bccsp.KeyGenOpts) (k bccsp.Key, err error) {
-	return createKmsSm2PrivateKey()
+	if gm.kms == nil {
+		return nil, errNoKMSDriver
+	}
+
+	keyID, err := gm.kms.CreateKey()
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating KMS GMSM2 key [%s]", err)
+	}
+
+	return loadKmsSm2PrivateKey(gm.kms, keyID)
 }
@@ -41,6 +41,10 @@ func (*gmsm4ImportKeyOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyI
 		return nil, errors.New("Invalid raw material. It must not be nil.")
 	}
 
+	if len(sm4Raw) != sm4KeyLength {
+		return nil, fmt.Errorf("Invalid Key Length [%d]. Must be %d bytes", len(sm4Raw), sm4KeyLength)
+	}
+
 	return &gmsm4PrivateKey{utils.Clone(sm4Raw), false}, nil
 }
 
@@ -98,20 +102,36 @@ type x509PublicKeyImportOptsKeyImporter struct {
 	bccsp *impl
 }
 
-type ecdsaGoPublicKeyImportOptsKeyImporter struct{}
+// ecdsaPublicKeyToKey wraps an ECDSA public key, turning keys on the SM2 curve
+// into SM2 keys and rejecting curves the provider does not accept.
+func ecdsaPublicKeyToKey(conf *config, pk *ecdsa.PublicKey) (bccsp.Key, error) {
+	if err := conf.checkCurve(pk.Curve); err != nil {
+		return nil, err
+	}
+	if pk.Curve == sm2.P256Sm2() {
+		return &gmsm2PublicKey{&sm2.PublicKey{Curve: pk.Curve, X: pk.X, Y: pk.Y}}, nil
+	}
+	return &ecdsaPublicKey{pk}, nil
+}
+
+type ecdsaGoPublicKeyImportOptsKeyImporter struct {
+	conf *config
+}
 
-func (*ecdsaGoPublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
+func (ki *ecdsaGoPublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
 	lowLevelKey, ok := raw.(*ecdsa.PublicKey)
 	if !ok {
 		return nil, errors.New("Invalid raw material. Expected *ecdsa.PublicKey.")
 	}
 
-	return &ecdsaPublicKey{lowLevelKey}, nil
+	return ecdsaPublicKeyToKey(ki.conf, lowLevelKey)
 }
 
-type ecdsaPrivateKeyImportOptsKeyImporter struct{}
+type ecdsaPrivateKeyImportOptsKeyImporter struct {
+	conf *config
+}
 
-func (*ecdsaPrivateKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
+func (ki *ecdsaPrivateKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
 	der, ok := raw.([]byte)
 	if !ok {
 		return nil, errors.New("[ECDSADERPrivateKeyImportOpts] Invalid raw material. Expected byte array.")
@@ -126,17 +146,27 @@ func (*ecdsaPrivateKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bcc
 		return nil, fmt.Errorf("Failed converting PKIX to ECDSA public key [%s]", err)
 	}
 
-	ecdsaSK, ok := lowLevelKey.(*ecdsa.PrivateKey)
-	if !ok {
+	switch sk := lowLevelKey.(type) {
+	case *sm2.PrivateKey:
+		return &gmsm2PrivateKey{sk}, nil
+	case *ecdsa.PrivateKey:
+		if err := ki.conf.checkCurve(sk.Curve); err != nil {
+			return nil, err
+		}
+		if sk.Curve == sm2.P256Sm2() {
+			return &gmsm2PrivateKey{&sm2.PrivateKey{PublicKey: sm2.PublicKey{Curve: sk.Curve, X: sk.X, Y: sk.Y}, D: sk.D}}, nil
+		}
+		return &ecdsaPrivateKey{sk}, nil
+	default:
 		return nil, errors.New("Failed casting to ECDSA private key. Invalid raw material.")
 	}
-
-	return &ecdsaPrivateKey{ecdsaSK}, nil
 }
 
-type ecdsaPKIXPublicKeyImportOptsKeyImporter struct{}
+type ecdsaPKIXPublicKeyImportOptsKeyImporter struct {
+	conf *config
+}
 
-func (*ecdsaPKIXPublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
+func (ki *ecdsaPKIXPublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
 	der, ok := raw.([]byte)
 	if !ok {
 		return nil, errors.New("Invalid raw material. Expected byte array.")
@@ -156,7 +186,7 @@ func (*ecdsaPKIXPublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts
 		return nil, errors.New("Failed casting to ECDSA public key. Invalid raw material.")
 	}
 
-	return &ecdsaPublicKey{ecdsaPK}, nil
+	return ecdsaPublicKeyToKey(ki.conf, ecdsaPK)
 }
 
 func (ki *x509PublicKeyImportOptsKeyImporter) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (k bccsp.Key, err error) {
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/pkg/errors"
+)
+
+const (
+	// AliyunKMSDriverName is the name of the driver backed by Alibaba Cloud KMS.
+	AliyunKMSDriverName = "aliyun"
+	// InProcessKMSDriverName is the name of the driver keeping the keys in the
+	// memory of the process, optionally persisted to a folder.
+	InProcessKMSDriverName = "inprocess"
+)
+
+// KMSDriver gives access to SM2 signing keys held by a key management
+// service. Keys are addressed by the identifier the service assigns to
+// them on creation; the private key material never leaves the service.
+type KMSDriver interface {
+	// CreateKey creates a new SM2 signing key and returns its identifier.
+	CreateKey() (keyID string, err error)
+
+	// PublicKey returns the public key of the key identified by keyID.
+	PublicKey(keyID string) (*sm2.PublicKey, error)
+
+	// Sign signs digest with the key identified by keyID and returns the
+	// ASN.1 encoded signature. The digest is the SM3 hash of Z_A and the
+	// message, as defined by GM/T 0003.2.
+	Sign(keyID string, digest []byte) ([]byte, error)
+}
+
+// KMSDriverFactory creates a KMSDriver out of its configuration.
+type KMSDriverFactory func(config map[string]interface{}) (KMSDriver, error)
+
+var (
+	kmsDriversLock sync.RWMutex
+	kmsDrivers     = map[string]KMSDriverFactory{
+		AliyunKMSDriverName:    newAliyunKMSDriver,
+		InProcessKMSDriverName: newInProcessKMSDriver,
+	}
+)
+
+// RegisterKMSDriver makes a KMS driver available under the given name,
+// replacing any driver previously registered under the same name.
+// Names are case insensitive.
+func RegisterKMSDriver(name string, factory KMSDriverFactory) {
+	kmsDriversLock.Lock()
+	defer kmsDriversLock.Unlock()
+
+	kmsDrivers[strings.ToLower(name)] = factory
+}
+
+// NewKMSDriver returns an instance of the driver registered under name.
+func NewKMSDriver(name string, config map[string]interface{}) (KMSDriver, error) {
+	kmsDriversLock.RLock()
+	factory, ok := kmsDrivers[strings.ToLower(name)]
+	kmsDriversLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("KMS driver not found [%s], available drivers are %v", name, kmsDriverNames())
+	}
+
+	driver, err := factory(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed initializing KMS driver [%s]", name)
+	}
+	return driver, nil
+}
+
+func kmsDriverNames() []string {
+	kmsDriversLock.RLock()
+	defer kmsDriversLock.RUnlock()
+
+	var names []string
+	for name := range kmsDrivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// kmsConfigString returns the string value of key in config, matching the
+// key case insensitively as configuration loaded through viper is lower cased.
+func kmsConfigString(config map[string]interface{}, key string) (string, error) {
+	for k, v := range config {
+		if !strings.EqualFold(k, key) {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return "", errors.Errorf("KMS configuration property [%s] must be a string, got [%T]", key, v)
+		}
+		return s, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKMSDriver(t *testing.T) {
+	_, err := NewKMSDriver("foo", nil)
+	assert.EqualError(t, err, "KMS driver not found [foo], available drivers are [aliyun inprocess]")
+
+	d, err := NewKMSDriver("InProcess", nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, d)
+
+	_, err = NewKMSDriver(InProcessKMSDriverName, map[string]interface{}{"path": 42})
+	assert.EqualError(t, err, "Failed initializing KMS driver [inprocess]: KMS configuration property [Path] must be a string, got [int]")
+
+	_, err = NewKMSDriver(AliyunKMSDriverName, map[string]interface{}{"region": "cn-hangzhou"})
+	assert.EqualError(t, err, "Failed initializing KMS driver [aliyun]: Region, AccessKeyID and AccessKeySecret must be set together")
+
+	RegisterKMSDriver("Custom", func(config map[string]interface{}) (KMSDriver, error) {
+		return NewInProcessKMSDriver(), nil
+	})
+	defer func() {
+		kmsDriversLock.Lock()
+		delete(kmsDrivers, "custom")
+		kmsDriversLock.Unlock()
+	}()
+	d, err = NewKMSDriver("custom", nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, d)
+}
+
+func TestInProcessKMSDriver(t *testing.T) {
+	d := NewInProcessKMSDriver()
+
+	keyID, err := d.CreateKey()
+	require.NoError(t, err)
+	assert.NotEmpty(t, keyID)
+
+	_, err = d.PublicKey("missing")
+	assert.EqualError(t, err, "key not found [missing]")
+	_, err = d.Sign("missing", []byte("digest"))
+	assert.EqualError(t, err, "key not found [missing]")
+
+	pubKey, err := d.PublicKey(keyID)
+	require.NoError(t, err)
+	assert.Equal(t, sm2.P256Sm2(), pubKey.Curve)
+
+	// The driver signs the digest the way sm2.Sm2Sign signs the message
+	msg := []byte("Hello World")
+	digest, err := pubKey.Sm3Digest(msg, nil)
+	require.NoError(t, err)
+	signature, err := d.Sign(keyID, digest)
+	require.NoError(t, err)
+	assert.True(t, pubKey.Verify(msg, signature))
+	assert.False(t, pubKey.Verify([]byte("Hello World!"), signature))
+}
+
+func TestInProcessKMSDriverPersistence(t *testing.T) {
+	td, err := ioutil.TempDir("", "bccsp-gm-kms")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+
+	config := map[string]interface{}{"Path": td}
+	d, err := NewKMSDriver(InProcessKMSDriverName, config)
+	require.NoError(t, err)
+	keyID, err := d.CreateKey()
+	require.NoError(t, err)
+	pubKey, err := d.PublicKey(keyID)
+	require.NoError(t, err)
+
+	// A new driver over the same folder finds the key
+	d2, err := NewKMSDriver(InProcessKMSDriverName, config)
+	require.NoError(t, err)
+	pubKey2, err := d2.PublicKey(keyID)
+	require.NoError(t, err)
+	assert.Equal(t, pubKey.X, pubKey2.X)
+	assert.Equal(t, pubKey.Y, pubKey2.Y)
+
+	_, err = d2.PublicKey("../" + keyID)
+	assert.Error(t, err)
+}
+
+func TestKMSKeys(t *testing.T) {
+	td, err := ioutil.TempDir("", "bccsp-gm-kms")
+	require.NoError(t, err)
+	defer os.RemoveAll(td)
+
+	kms := NewInProcessKMSDriver()
+	ks, err := NewFileBasedKeyStoreWithKMS(nil, td, false, kms)
+	require.NoError(t, err)
+	csp, err := NewWithParams(256, "GMSM3", CurveSM2, false, kms, ks)
+	require.NoError(t, err)
+
+	k, err := csp.KeyGen(&bccsp.KMSGMSM2KeyGenOpts{})
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+	keyID, err := k.Bytes()
+	require.NoError(t, err)
+
+	msg := []byte("Hello World")
+	signature, err := csp.Sign(k, msg, nil)
+	require.NoError(t, err)
+
+	// KMS signatures verify like the ones of software SM2 keys
+	pk, err := k.PublicKey()
+	require.NoError(t, err)
+	valid, err := csp.Verify(pk, signature, msg, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = csp.Verify(k, signature, msg, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// Only the key identifier is persisted, the key store resolves it through the KMS
+	k2, err := csp.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), k2.SKI())
+	signature, err = csp.Sign(k2, msg, nil)
+	require.NoError(t, err)
+	valid, err = csp.Verify(pk, signature, msg, nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// Keys are imported by identifier
+	k3, err := csp.KeyImport(string(keyID), &bccsp.KMSGMSM2KeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), k3.SKI())
+	_, err = csp.KeyImport([]byte("missing"), &bccsp.KMSGMSM2KeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	_, err = csp.KeyImport(42, &bccsp.KMSGMSM2KeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+
+	// A key store without KMS cannot resolve the key
+	ks2, err := NewFileBasedKeyStore(nil, td, true)
+	require.NoError(t, err)
+	_, err = ks2.GetKey(k.SKI())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "No KMS driver configured")
+}
+
+func TestKMSKeysWithoutDriver(t *testing.T) {
+	csp, err := New(256, "GMSM3", NewDummyKeyStore())
+	require.NoError(t, err)
+
+	_, err = csp.KeyGen(&bccsp.KMSGMSM2KeyGenOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "No KMS driver configured")
+
+	_, err = csp.KeyImport("key", &bccsp.KMSGMSM2KeyImportOpts{Temporary: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "No KMS driver configured")
+}
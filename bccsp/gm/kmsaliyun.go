@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"sync"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/Hyperledger-TWGC/tjfoc-gm/x509"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/kms"
+	"github.com/pkg/errors"
+	"github.com/tw-bc-group/aliyun-kms/comm"
+)
+
+const (
+	aliyunRequestScheme    = "https"
+	aliyunSM2KeySpec       = "EC_SM2"
+	aliyunSM2KeyUsage      = "SIGN/VERIFY"
+	aliyunSM2SignAlgorithm = "SM2DSA"
+)
+
+// aliyunKMSDriver is a KMSDriver backed by Alibaba Cloud KMS.
+type aliyunKMSDriver struct {
+	client *kms.Client
+
+	// keyVersions caches the version of each key used so far
+	lock        sync.Mutex
+	keyVersions map[string]string
+}
+
+// newAliyunKMSDriver creates a driver out of the Region, AccessKeyID and
+// AccessKeySecret properties of config. When none of them is set, the
+// ALIBABA_CLOUD_REGION, ALIBABA_CLOUD_ACCESS_KEY_ID and
+// ALIBABA_CLOUD_ACCESS_KEY_SECRET environment variables are used instead.
+func newAliyunKMSDriver(config map[string]interface{}) (KMSDriver, error) {
+	var props [3]string
+	for i, key := range []string{"Region", "AccessKeyID", "AccessKeySecret"} {
+		v, err := kmsConfigString(config, key)
+		if err != nil {
+			return nil, err
+		}
+		props[i] = v
+	}
+
+	var client *kms.Client
+	var err error
+	switch {
+	case props[0] == "" && props[1] == "" && props[2] == "":
+		client, err = comm.CreateKmsClient()
+	case props[0] == "" || props[1] == "" || props[2] == "":
+		return nil, errors.New("Region, AccessKeyID and AccessKeySecret must be set together")
+	default:
+		client, err = kms.NewClientWithAccessKey(props[0], props[1], props[2])
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed creating Aliyun KMS client")
+	}
+
+	return &aliyunKMSDriver{
+		client:      client,
+		keyVersions: map[string]string{},
+	}, nil
+}
+
+func (d *aliyunKMSDriver) CreateKey() (string, error) {
+	request := kms.CreateCreateKeyRequest()
+	request.Scheme = aliyunRequestScheme
+	request.KeySpec = aliyunSM2KeySpec
+	request.KeyUsage = aliyunSM2KeyUsage
+
+	response, err := d.client.CreateKey(request)
+	if err != nil {
+		return "", errors.Wrap(err, "failed creating Aliyun KMS key")
+	}
+	return response.KeyMetadata.KeyId, nil
+}
+
+func (d *aliyunKMSDriver) PublicKey(keyID string) (*sm2.PublicKey, error) {
+	version, err := d.keyVersion(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := kms.CreateGetPublicKeyRequest()
+	request.Scheme = aliyunRequestScheme
+	request.KeyId = keyID
+	request.KeyVersionId = version
+
+	response, err := d.client.GetPublicKey(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed getting public key of Aliyun KMS key [%s]", keyID)
+	}
+
+	block, _ := pem.Decode([]byte(response.PublicKey))
+	if block == nil {
+		return nil, errors.Errorf("invalid public key PEM of Aliyun KMS key [%s]", keyID)
+	}
+	return x509.ParseSm2PublicKey(block.Bytes)
+}
+
+func (d *aliyunKMSDriver) Sign(keyID string, digest []byte) ([]byte, error) {
+	version, err := d.keyVersion(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := kms.CreateAsymmetricSignRequest()
+	request.Scheme = aliyunRequestScheme
+	request.KeyId = keyID
+	request.KeyVersionId = version
+	request.Algorithm = aliyunSM2SignAlgorithm
+	request.Digest = base64.StdEncoding.EncodeToString(digest)
+
+	response, err := d.client.AsymmetricSign(request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed signing with Aliyun KMS key [%s]", keyID)
+	}
+	return base64.StdEncoding.DecodeString(response.Value)
+}
+
+func (d *aliyunKMSDriver) keyVersion(keyID string) (string, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if version, ok := d.keyVersions[keyID]; ok {
+		return version, nil
+	}
+
+	request := kms.CreateListKeyVersionsRequest()
+	request.Scheme = aliyunRequestScheme
+	request.KeyId = keyID
+
+	response, err := d.client.ListKeyVersions(request)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed listing versions of Aliyun KMS key [%s]", keyID)
+	}
+	if len(response.KeyVersions.KeyVersion) == 0 {
+		return "", errors.Errorf("Aliyun KMS key [%s] has no version", keyID)
+	}
+
+	version := response.KeyVersions.KeyVersion[0].KeyVersionId
+	d.keyVersions[keyID] = version
+	return version, nil
+}
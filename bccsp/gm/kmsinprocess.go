@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/pkg/errors"
+)
+
+// inProcessKMSDriver is a KMSDriver holding its keys in the memory of the
+// process. It stands in for a real KMS in tests and local networks.
+// When a path is configured, the keys are persisted in PEM files named
+// after their identifiers so that they survive restarts.
+type inProcessKMSDriver struct {
+	path string
+
+	lock sync.RWMutex
+	keys map[string]*sm2.PrivateKey
+}
+
+// newInProcessKMSDriver creates a driver persisting its keys to the folder
+// of the Path property of config, if set.
+func newInProcessKMSDriver(config map[string]interface{}) (KMSDriver, error) {
+	path, err := kmsConfigString(config, "Path")
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return nil, errors.Wrapf(err, "failed creating in-process KMS folder [%s]", path)
+		}
+	}
+
+	return &inProcessKMSDriver{
+		path: path,
+		keys: map[string]*sm2.PrivateKey{},
+	}, nil
+}
+
+// NewInProcessKMSDriver returns a KMSDriver holding its keys in memory.
+func NewInProcessKMSDriver() KMSDriver {
+	return &inProcessKMSDriver{keys: map[string]*sm2.PrivateKey{}}
+}
+
+func (d *inProcessKMSDriver) CreateKey() (string, error) {
+	privKey, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", errors.Wrap(err, "failed generating SM2 key")
+	}
+	rawID, err := GetRandomBytes(16)
+	if err != nil {
+		return "", err
+	}
+	keyID := hex.EncodeToString(rawID)
+
+	if d.path != "" {
+		raw, err := utils.PrivateKeyToPEM(privKey, nil)
+		if err != nil {
+			return "", err
+		}
+		if err := ioutil.WriteFile(filepath.Join(d.path, keyID), raw, 0600); err != nil {
+			return "", errors.Wrapf(err, "failed storing key [%s]", keyID)
+		}
+	}
+
+	d.lock.Lock()
+	d.keys[keyID] = privKey
+	d.lock.Unlock()
+
+	return keyID, nil
+}
+
+func (d *inProcessKMSDriver) PublicKey(keyID string) (*sm2.PublicKey, error) {
+	privKey, err := d.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return &privKey.PublicKey, nil
+}
+
+func (d *inProcessKMSDriver) Sign(keyID string, digest []byte) ([]byte, error) {
+	privKey, err := d.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return signSM2Digest(privKey, digest)
+}
+
+func (d *inProcessKMSDriver) key(keyID string) (*sm2.PrivateKey, error) {
+	d.lock.RLock()
+	privKey, ok := d.keys[keyID]
+	d.lock.RUnlock()
+	if ok {
+		return privKey, nil
+	}
+
+	if d.path == "" || filepath.Base(keyID) != keyID {
+		return nil, errors.Errorf("key not found [%s]", keyID)
+	}
+
+	raw, err := ioutil.ReadFile(filepath.Join(d.path, keyID))
+	if os.IsNotExist(err) {
+		return nil, errors.Errorf("key not found [%s]", keyID)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed loading key [%s]", keyID)
+	}
+	key, err := utils.PEMtoPrivateKey(raw, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed parsing key [%s]", keyID)
+	}
+	privKey, ok = key.(*sm2.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("key [%s] is not an SM2 key", keyID)
+	}
+
+	d.lock.Lock()
+	d.keys[keyID] = privKey
+	d.lock.Unlock()
+
+	return privKey, nil
+}
+
+// signSM2Digest computes the SM2 signature of a digest already combining
+// Z_A and the message, as a KMS does.
+func signSM2Digest(privKey *sm2.PrivateKey, digest []byte) ([]byte, error) {
+	c := privKey.Curve
+	n := c.Params().N
+	e := new(big.Int).SetBytes(digest)
+	one := big.NewInt(1)
+
+	// (1 + d)^-1 mod n
+	dInv := new(big.Int).Add(privKey.D, one)
+	dInv.ModInverse(dInv, n)
+
+	for {
+		// k in [1, n-1]
+		k, err := rand.Int(rand.Reader, new(big.Int).Sub(n, one))
+		if err != nil {
+			return nil, err
+		}
+		k.Add(k, one)
+
+		// r = (e + x1) mod n
+		x1, _ := c.ScalarBaseMult(k.Bytes())
+		r := new(big.Int).Add(e, x1)
+		r.Mod(r, n)
+		if r.Sign() == 0 || new(big.Int).Add(r, k).Cmp(n) == 0 {
+			continue
+		}
+
+		// s = (1 + d)^-1 * (k - r*d) mod n
+		s := new(big.Int).Mul(r, privKey.D)
+		s.Sub(k, s)
+		s.Mul(s, dInv)
+		s.Mod(s, n)
+		if s.Sign() == 0 {
+			continue
+		}
+
+		return MarshalSM2Signature(r, s)
+	}
+}
@@ -3,21 +3,29 @@ package gm
 import (
 	"crypto/elliptic"
 	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
 	"github.com/hyperledger/fabric/bccsp"
-	kmssm2 "github.com/tw-bc-group/aliyun-kms/sm2"
 )
 
+var errNoKMSDriver = errors.New("No KMS driver configured")
+
+// kmsSm2PrivateKey is an SM2 private key held by a KMS and referenced by
+// its key identifier.
 type kmsSm2PrivateKey struct {
-	adapter *kmssm2.KeyAdapter
+	kms    KMSDriver
+	keyID  string
+	pubKey *sm2.PublicKey
 }
 
 func (pri *kmsSm2PrivateKey) Bytes() ([]byte, error) {
-	return []byte(pri.adapter.KeyID()), nil
+	return []byte(pri.keyID), nil
 }
 
 func (pri *kmsSm2PrivateKey) SKI() []byte {
-	publicKey := pri.adapter.PublicKey()
-	raw := elliptic.Marshal(publicKey.Curve, publicKey.X, publicKey.Y)
+	raw := elliptic.Marshal(pri.pubKey.Curve, pri.pubKey.X, pri.pubKey.Y)
 	hash := sha256.New()
 	hash.Write(raw)
 	return hash.Sum(nil)
@@ -32,34 +40,64 @@ func (pri *kmsSm2PrivateKey) Private() bool {
 }
 
 func (pri *kmsSm2PrivateKey) PublicKey() (bccsp.Key, error) {
-	return &gmsm2PublicKey{pubKey: pri.adapter.PublicKey()}, nil
+	return &gmsm2PublicKey{pubKey: pri.pubKey}, nil
 }
 
-func createKmsSm2PrivateKey() (*kmsSm2PrivateKey, error) {
-	adapter, err := kmssm2.CreateSm2KeyAdapter("", kmssm2.SignAndVerify)
+// loadKmsSm2PrivateKey returns the key keyID of the given KMS.
+func loadKmsSm2PrivateKey(kms KMSDriver, keyID string) (*kmsSm2PrivateKey, error) {
+	if kms == nil {
+		return nil, errNoKMSDriver
+	}
+	if len(keyID) == 0 {
+		return nil, errors.New("Invalid KMS key identifier. It must not be empty")
+	}
+
+	pubKey, err := kms.PublicKey(keyID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Failed getting public key of KMS key [%s] [%s]", keyID, err)
+	}
+	if pubKey == nil || pubKey.Curve != sm2.P256Sm2() {
+		return nil, fmt.Errorf("KMS key [%s] is not an SM2 key", keyID)
 	}
 
-	return &kmsSm2PrivateKey{
-		adapter: adapter,
-	}, nil
+	return &kmsSm2PrivateKey{kms: kms, keyID: keyID, pubKey: pubKey}, nil
 }
 
-type kmssm2ImportKeyOptsKeyImporter struct{}
+type kmssm2ImportKeyOptsKeyImporter struct {
+	kms KMSDriver
+}
 
-func (*kmssm2ImportKeyOptsKeyImporter) KeyImport(raw interface{}, _ bccsp.KeyImportOpts) (k bccsp.Key, err error) {
-	adapter, err := kmssm2.CreateSm2KeyAdapter(raw.(string), kmssm2.SignAndVerify)
-	if err != nil {
-		return nil, err
+func (ki *kmssm2ImportKeyOptsKeyImporter) KeyImport(raw interface{}, _ bccsp.KeyImportOpts) (k bccsp.Key, err error) {
+	var keyID string
+	switch id := raw.(type) {
+	case string:
+		keyID = id
+	case []byte:
+		keyID = string(id)
+	default:
+		return nil, errors.New("Invalid raw material. Expected string or byte array key identifier")
 	}
-	return &kmsSm2PrivateKey{
-		adapter: adapter,
-	}, nil
+
+	return loadKmsSm2PrivateKey(ki.kms, keyID)
 }
 
 type kmssm2PrivateKeySigner struct{}
 
 func (s *kmssm2PrivateKeySigner) Sign(k bccsp.Key, digest []byte, _ bccsp.SignerOpts) (signature []byte, err error) {
-	return k.(*kmsSm2PrivateKey).adapter.AsymmetricSign(digest)
+	key := k.(*kmsSm2PrivateKey)
+
+	// As for the software keys, the digest is the message to sign in the
+	// sense of GM/T 0003.2, the KMS signs its hash with Z_A.
+	e, err := key.pubKey.Sm3Digest(digest, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.kms.Sign(key.keyID, e)
+}
+
+type kmssm2PrivateKeyVerifier struct{}
+
+func (v *kmssm2PrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
+	return verifyGMSM2(k.(*kmsSm2PrivateKey).pubKey, signature, digest, opts)
 }
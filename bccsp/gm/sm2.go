@@ -92,25 +92,6 @@ func (s *gmsm2Signer) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) (s
 	return signGMSM2(k.(*gmsm2PrivateKey).privKey, digest, opts)
 }
 
-type ecdsaPrivateKeySigner struct{}
-
-func (s *ecdsaPrivateKeySigner) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) (signature []byte, err error) {
-	puk := k.(*ecdsaPrivateKey).privKey.PublicKey
-	sm2pk := sm2.PublicKey{
-		Curve: puk.Curve,
-		X:     puk.X,
-		Y:     puk.Y,
-	}
-
-	privKey := k.(*ecdsaPrivateKey).privKey
-	sm2privKey := sm2.PrivateKey{
-		D:         privKey.D,
-		PublicKey: sm2pk,
-	}
-
-	return signGMSM2(&sm2privKey, digest, opts)
-}
-
 type gmsm2PrivateKeyVerifier struct{}
 
 func (v *gmsm2PrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
@@ -123,30 +104,6 @@ func (v *gmsm2PublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte
 	return verifyGMSM2(k.(*gmsm2PublicKey).pubKey, signature, digest, opts)
 }
 
-type ecdsaPrivateKeyVerifier struct{}
-
-func (v *ecdsaPrivateKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
-	puk := k.(*ecdsaPrivateKey).privKey.PublicKey
-	sm2pk := sm2.PublicKey{
-		Curve: puk.Curve,
-		X:     puk.X,
-		Y:     puk.Y,
-	}
-	return verifyGMSM2(&sm2pk, signature, digest, opts)
-}
-
-type ecdsaPublicKeyKeyVerifier struct{}
-
-func (v *ecdsaPublicKeyKeyVerifier) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (valid bool, err error) {
-	puk := k.(*ecdsaPublicKey).pubKey
-	sm2pk := sm2.PublicKey{
-		Curve: puk.Curve,
-		X:     puk.X,
-		Y:     puk.Y,
-	}
-	return verifyGMSM2(&sm2pk, signature, digest, opts)
-}
-
 func SignatureToLowS(k *ecdsa.PublicKey, signature []byte) ([]byte, error) {
 	r, s, err := UnmarshalSM2Signature(signature)
 	if err != nil {
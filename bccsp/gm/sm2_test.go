@@ -0,0 +1,256 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/Hyperledger-TWGC/tjfoc-gm/x509"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Signature example on the recommended curve from GM/T 0003.5 (GB/T 32918.5),
+// signed with the default user identity "1234567812345678".
+var sm2KnownAnswer = struct {
+	d, x, y, r, s string
+	msg           string
+}{
+	d:   "3945208F7B2144B13F36E38AC6D39F95889393692860B51A42FB81EF4DF7C5B8",
+	x:   "09F9DF311E5421A150DD7D161E4BC5C672179FAD1833FC076BB08FF356F35020",
+	y:   "CCEA490CE26775A52DC6EA718CC1AA600AED05FBF35E084A6632F6072DA9AD13",
+	r:   "F5A03B0648D2C4630EEAC513E1BB81A15944DA3827D5B74143AC7EACEEE720B3",
+	s:   "B1B6AA29DF212FD8763182BC0D421CA1BB9038FD1F7F42D4840B69C485BBC1AA",
+	msg: "message digest",
+}
+
+// Encryption example on the recommended curve from GM/T 0003.5 (GB/T 32918.5),
+// with the private key of sm2KnownAnswer. The ciphertext is C1||C3||C2.
+var sm2EncryptionKnownAnswer = struct {
+	ciphertext string
+	msg        string
+}{
+	ciphertext: "04" +
+		"04EBFC718E8D1798620432268E77FEB6415E2EDE0E073C0F4F640ECD2E149A73" +
+		"E858F9D81E5430A57B36DAAB8F950A3C64E6EE6A63094D99283AFF767E124DF0" +
+		"59983C18F809E262923C53AEC295D30383B54E39D609D160AFCB1908D0BD8766" +
+		"21886CA989CA9C7D58087307CA93092D651EFA",
+	msg: "encryption standard",
+}
+
+func hexToBig(t *testing.T, s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	require.True(t, ok, "invalid hex [%s]", s)
+	return n
+}
+
+func TestSM2KnownAnswer(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	kat := sm2KnownAnswer
+	curve := sm2.P256Sm2()
+	priv := &sm2.PrivateKey{D: hexToBig(t, kat.d)}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(priv.D.Bytes())
+	assert.Equal(t, hexToBig(t, kat.x), priv.X, "public key X mismatch")
+	assert.Equal(t, hexToBig(t, kat.y), priv.Y, "public key Y mismatch")
+
+	signature, err := MarshalSM2Signature(hexToBig(t, kat.r), hexToBig(t, kat.s))
+	require.NoError(t, err)
+
+	pubDER, err := x509.MarshalSm2PublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	pk, err := csp.KeyImport(pubDER, &bccsp.GMSM2PublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+
+	valid, err := csp.Verify(pk, signature, []byte(kat.msg), nil)
+	assert.NoError(t, err)
+	assert.True(t, valid, "known answer signature must verify")
+
+	valid, err = csp.Verify(pk, signature, []byte("message digesT"), nil)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	privDER, err := x509.MarshalSm2PrivateKey(priv, nil)
+	require.NoError(t, err)
+	sk, err := csp.KeyImport(privDER, &bccsp.GMSM2PrivateKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Equal(t, pk.SKI(), sk.SKI())
+
+	// SM2 signatures are randomized, so check a fresh signature verifies
+	// under the known public key.
+	signature, err = csp.Sign(sk, []byte(kat.msg), nil)
+	require.NoError(t, err)
+	valid, err = csp.Verify(pk, signature, []byte(kat.msg), nil)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSM2SignatureMarshalling(t *testing.T) {
+	r, s := big.NewInt(1), big.NewInt(2)
+	raw, err := MarshalSM2Signature(r, s)
+	require.NoError(t, err)
+
+	r2, s2, err := UnmarshalSM2Signature(raw)
+	require.NoError(t, err)
+	assert.Equal(t, r, r2)
+	assert.Equal(t, s, s2)
+
+	_, _, err = UnmarshalSM2Signature([]byte{0, 1, 2})
+	assert.Error(t, err)
+
+	raw, err = MarshalSM2Signature(big.NewInt(0), s)
+	require.NoError(t, err)
+	_, _, err = UnmarshalSM2Signature(raw)
+	assert.EqualError(t, err, "Invalid signature. R must be larger than zero")
+
+	raw, err = MarshalSM2Signature(r, big.NewInt(-1))
+	require.NoError(t, err)
+	_, _, err = UnmarshalSM2Signature(raw)
+	assert.EqualError(t, err, "Invalid signature. S must be larger than zero")
+}
+
+func TestSM2LowS(t *testing.T) {
+	k, err := sm2.GenerateKey(nil)
+	require.NoError(t, err)
+	pub := &ecdsa.PublicKey{Curve: k.Curve, X: k.X, Y: k.Y}
+
+	halfOrder := new(big.Int).Rsh(sm2.P256Sm2().Params().N, 1)
+	lowS, err := IsLowS(pub, halfOrder)
+	assert.NoError(t, err)
+	assert.True(t, lowS)
+
+	highS := new(big.Int).Add(halfOrder, big.NewInt(1))
+	lowS, err = IsLowS(pub, highS)
+	assert.NoError(t, err)
+	assert.False(t, lowS)
+
+	// SM2 signatures are not normalized, the S value is kept as is.
+	s, modified, err := ToLowS(pub, new(big.Int).Set(highS))
+	assert.NoError(t, err)
+	assert.False(t, modified)
+	assert.Equal(t, highS, s)
+}
+
+func TestSM2EncryptionKnownAnswer(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	curve := sm2.P256Sm2()
+	priv := &sm2.PrivateKey{D: hexToBig(t, sm2KnownAnswer.d)}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(priv.D.Bytes())
+	privDER, err := x509.MarshalSm2PrivateKey(priv, nil)
+	require.NoError(t, err)
+	sk, err := csp.KeyImport(privDER, &bccsp.GMSM2PrivateKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+
+	ciphertext, err := hex.DecodeString(sm2EncryptionKnownAnswer.ciphertext)
+	require.NoError(t, err)
+	plaintext, err := csp.Decrypt(sk, ciphertext, &bccsp.GMSM2DecrypterOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(sm2EncryptionKnownAnswer.msg), plaintext)
+
+	// The ASN.1 encoding of GM/T 0009 carries the same parts
+	asn1Ciphertext, err := sm2.CipherMarshal(ciphertext)
+	require.NoError(t, err)
+	plaintext, err = csp.Decrypt(sk, asn1Ciphertext, &bccsp.GMSM2DecrypterOpts{ASN1: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(sm2EncryptionKnownAnswer.msg), plaintext)
+
+	// Any change to C3 or C2 is detected
+	for _, i := range []int{1 + 64, len(ciphertext) - 1} {
+		tampered := append([]byte{}, ciphertext...)
+		tampered[i] ^= 0x01
+		_, err = csp.Decrypt(sk, tampered, &bccsp.GMSM2DecrypterOpts{})
+		assert.Error(t, err)
+	}
+}
+
+func TestSM2Encryption(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	sk, err := csp.KeyGen(&bccsp.GMSM2KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	pk, err := sk.PublicKey()
+	require.NoError(t, err)
+
+	// Encrypt to the public key as obtained from a certificate
+	pubDER, err := pk.Bytes()
+	require.NoError(t, err)
+	importedPK, err := csp.KeyImport(pubDER, &bccsp.GMSM2PublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+
+	msg := []byte("Hello World")
+	for _, tc := range []struct {
+		name    string
+		encOpts bccsp.EncrypterOpts
+		decOpts bccsp.DecrypterOpts
+	}{
+		{"Default", nil, nil},
+		{"C1C3C2", &bccsp.GMSM2EncrypterOpts{PRNG: rand.Reader}, &bccsp.GMSM2DecrypterOpts{}},
+		{"ASN1", &bccsp.GMSM2EncrypterOpts{ASN1: true}, &bccsp.GMSM2DecrypterOpts{ASN1: true}},
+		{"Values", bccsp.GMSM2EncrypterOpts{ASN1: true}, bccsp.GMSM2DecrypterOpts{ASN1: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range []bccsp.Key{importedPK, pk, sk} {
+				ciphertext, err := csp.Encrypt(k, msg, tc.encOpts)
+				require.NoError(t, err)
+				assert.NotContains(t, string(ciphertext), string(msg))
+
+				plaintext, err := csp.Decrypt(sk, ciphertext, tc.decOpts)
+				assert.NoError(t, err)
+				assert.Equal(t, msg, plaintext)
+			}
+		})
+	}
+
+	// Encryption is randomized
+	c1, err := csp.Encrypt(pk, msg, nil)
+	require.NoError(t, err)
+	c2, err := csp.Encrypt(pk, msg, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, c1, c2)
+
+	// Another key cannot decrypt
+	sk2, err := csp.KeyGen(&bccsp.GMSM2KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = csp.Decrypt(sk2, c1, nil)
+	assert.Error(t, err)
+
+	// The public key cannot decrypt
+	_, err = csp.Decrypt(pk, c1, nil)
+	assert.Error(t, err)
+
+	_, err = csp.Encrypt(pk, nil, nil)
+	assert.EqualError(t, err, "Invalid plaintext. It must not be empty")
+	_, err = csp.Encrypt(pk, msg, &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.EqualError(t, err, "Unsupported 'EncrypterOpts' provided [*bccsp.SM4CBCPKCS7ModeOpts]")
+
+	_, err = csp.Decrypt(sk, c1[:sm2CiphertextOverhead], nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid ciphertext. It must be C1||C3||C2 with C1 an uncompressed point")
+	_, err = csp.Decrypt(sk, c1, &bccsp.GMSM2DecrypterOpts{ASN1: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid ciphertext. Failed unmarshalling")
+	_, err = csp.Decrypt(sk, c1, &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported 'DecrypterOpts' provided [*bccsp.SM4CBCPKCS7ModeOpts]")
+	offCurve := append([]byte{}, c1...)
+	offCurve[1] ^= 0x01
+	_, err = csp.Decrypt(sk, offCurve, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid ciphertext. C1 is not on the curve")
+}
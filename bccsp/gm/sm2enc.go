@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	"github.com/hyperledger/fabric/bccsp"
+)
+
+// sm2CiphertextOverhead is the length of C1, an uncompressed point, and of
+// C3, an SM3 hash, in a C1||C3||C2 ciphertext.
+const sm2CiphertextOverhead = 1 + 64 + 32
+
+// gmsm2Encryptor encrypts to the public key of SM2 keys as specified by
+// GM/T 0003.4.
+type gmsm2Encryptor struct{}
+
+func (e *gmsm2Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	var pubKey *sm2.PublicKey
+	switch key := k.(type) {
+	case *gmsm2PrivateKey:
+		pubKey = &key.privKey.PublicKey
+	case *gmsm2PublicKey:
+		pubKey = key.pubKey
+	case *kmsSm2PrivateKey:
+		pubKey = key.pubKey
+	}
+	if pubKey == nil || pubKey.Curve != sm2.P256Sm2() {
+		return nil, errors.New("Invalid key. SM2 encryption requires a key on the SM2 curve")
+	}
+	if len(plaintext) == 0 {
+		return nil, errors.New("Invalid plaintext. It must not be empty")
+	}
+
+	var o *bccsp.GMSM2EncrypterOpts
+	switch opts := opts.(type) {
+	case *bccsp.GMSM2EncrypterOpts:
+		o = opts
+	case bccsp.GMSM2EncrypterOpts:
+		o = &opts
+	case nil:
+		o = &bccsp.GMSM2EncrypterOpts{}
+	default:
+		return nil, fmt.Errorf("Unsupported 'EncrypterOpts' provided [%T]", opts)
+	}
+
+	random := o.PRNG
+	if random == nil {
+		random = rand.Reader
+	}
+	if o.ASN1 {
+		return sm2.EncryptAsn1(pubKey, plaintext, random)
+	}
+	return sm2.Encrypt(pubKey, plaintext, random)
+}
+
+type gmsm2Decryptor struct{}
+
+func (*gmsm2Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) (plaintext []byte, err error) {
+	privKey := k.(*gmsm2PrivateKey).privKey
+
+	asn1Encoded := false
+	switch o := opts.(type) {
+	case *bccsp.GMSM2DecrypterOpts:
+		asn1Encoded = o.ASN1
+	case bccsp.GMSM2DecrypterOpts:
+		asn1Encoded = o.ASN1
+	case nil:
+	default:
+		return nil, fmt.Errorf("Unsupported 'DecrypterOpts' provided [%T]", opts)
+	}
+
+	if asn1Encoded {
+		ciphertext, err = sm2.CipherUnmarshal(ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid ciphertext. Failed unmarshalling [%s]", err)
+		}
+	}
+	if len(ciphertext) <= sm2CiphertextOverhead || ciphertext[0] != 0x04 {
+		return nil, errors.New("Invalid ciphertext. It must be C1||C3||C2 with C1 an uncompressed point")
+	}
+	x := new(big.Int).SetBytes(ciphertext[1:33])
+	y := new(big.Int).SetBytes(ciphertext[33:65])
+	// the optimized IsOnCurve of the SM2 curve accepts points off the curve, the
+	// generic one of its parameters is used instead, as the a coefficient of SM2 is -3
+	params := privKey.Curve.Params()
+	if x.Cmp(params.P) >= 0 || y.Cmp(params.P) >= 0 || !params.IsOnCurve(x, y) {
+		return nil, errors.New("Invalid ciphertext. C1 is not on the curve")
+	}
+
+	plaintext, err = sm2.Decrypt(privKey, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return plaintext, nil
+}
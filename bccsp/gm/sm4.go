@@ -16,9 +16,12 @@ limitations under the License.
 package gm
 
 import (
+	"bytes"
+	"crypto/cipher"
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/Hyperledger-TWGC/tjfoc-gm/sm4"
 	"github.com/hyperledger/fabric/bccsp"
@@ -43,7 +46,7 @@ func GetRandomBytes(len int) ([]byte, error) {
 	return buffer, nil
 }
 
-// sm4 combines CBC encryption and PKCS7 padding
+// SM4Encrypt encrypts a single block with SM4
 func SM4Encrypt(key, src []byte) ([]byte, error) {
 	dst := make([]byte, len(src))
 	cipher, err := sm4.NewCipher(key)
@@ -54,7 +57,7 @@ func SM4Encrypt(key, src []byte) ([]byte, error) {
 	return dst, nil
 }
 
-// sm4 combines CBC decryption and PKCS7 unpadding
+// SM4Decrypt decrypts a single block with SM4
 func SM4Decrypt(key, src []byte) ([]byte, error) {
 	cipher, err := sm4.NewCipher(key)
 	if err != nil {
@@ -65,28 +68,149 @@ func SM4Decrypt(key, src []byte) ([]byte, error) {
 	return dst, nil
 }
 
-type gmsm4Encryptor struct{}
+func sm4PKCS7Padding(src []byte) []byte {
+	padding := sm4.BlockSize - len(src)%sm4.BlockSize
+	padtext := bytes.Repeat([]byte{byte(padding)}, padding)
+	return append(src, padtext...)
+}
 
-//实现 Encryptor 接口
-func (*gmsm4Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) (ciphertext []byte, err error) {
+func sm4PKCS7UnPadding(src []byte) ([]byte, error) {
+	length := len(src)
+	if length == 0 {
+		return nil, errors.New("Invalid pkcs7 padding (empty plaintext)")
+	}
+	unpadding := int(src[length-1])
+
+	if unpadding > sm4.BlockSize || unpadding == 0 {
+		return nil, errors.New("Invalid pkcs7 padding (unpadding > sm4.BlockSize || unpadding == 0)")
+	}
+
+	pad := src[len(src)-unpadding:]
+	for i := 0; i < unpadding; i++ {
+		if pad[i] != byte(unpadding) {
+			return nil, errors.New("Invalid pkcs7 padding (pad[i] != unpadding)")
+		}
+	}
+
+	return src[:(length - unpadding)], nil
+}
+
+func sm4CBCEncryptWithRand(prng io.Reader, key, s []byte) ([]byte, error) {
+	if len(s)%sm4.BlockSize != 0 {
+		return nil, errors.New("Invalid plaintext. It must be a multiple of the block size")
+	}
+
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := make([]byte, sm4.BlockSize+len(s))
+	iv := ciphertext[:sm4.BlockSize]
+	if _, err := io.ReadFull(prng, iv); err != nil {
+		return nil, err
+	}
+
+	mode := cipher.NewCBCEncrypter(block, iv)
+	mode.CryptBlocks(ciphertext[sm4.BlockSize:], s)
+
+	return ciphertext, nil
+}
 
-	return SM4Encrypt(k.(*gmsm4PrivateKey).privKey, plaintext)
-	//return AESCBCPKCS7Encrypt(k.(*sm4PrivateKey).privKey, plaintext)
+func sm4CBCEncryptWithIV(IV []byte, key, s []byte) ([]byte, error) {
+	if len(IV) != sm4.BlockSize {
+		return nil, errors.New("Invalid IV. It must have length the block size")
+	}
 
-	// key := k.(*gmsm4PrivateKey).privKey
-	// var en = make([]byte, 16)
-	// sms4(plaintext, 16, key, en, 1)
-	// return en, nil
+	return sm4CBCEncryptWithRand(bytes.NewReader(IV), key, s)
+}
+
+func sm4CBCDecrypt(key, src []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(src) < sm4.BlockSize {
+		return nil, errors.New("Invalid ciphertext. It must be a multiple of the block size")
+	}
+	iv := src[:sm4.BlockSize]
+	src = src[sm4.BlockSize:]
+
+	if len(src)%sm4.BlockSize != 0 {
+		return nil, errors.New("Invalid ciphertext. It must be a multiple of the block size")
+	}
+
+	dst := make([]byte, len(src))
+	mode := cipher.NewCBCDecrypter(block, iv)
+	mode.CryptBlocks(dst, src)
+
+	return dst, nil
+}
+
+// SM4CBCPKCS7Encrypt combines CBC encryption and PKCS7 padding
+func SM4CBCPKCS7Encrypt(key, src []byte) ([]byte, error) {
+	return SM4CBCPKCS7EncryptWithRand(rand.Reader, key, src)
+}
+
+// SM4CBCPKCS7EncryptWithRand combines CBC encryption and PKCS7 padding using as prng the passed to the function
+func SM4CBCPKCS7EncryptWithRand(prng io.Reader, key, src []byte) ([]byte, error) {
+	return sm4CBCEncryptWithRand(prng, key, sm4PKCS7Padding(src))
+}
+
+// SM4CBCPKCS7EncryptWithIV combines CBC encryption and PKCS7 padding, the IV used is the one passed to the function
+func SM4CBCPKCS7EncryptWithIV(IV []byte, key, src []byte) ([]byte, error) {
+	return sm4CBCEncryptWithIV(IV, key, sm4PKCS7Padding(src))
+}
+
+// SM4CBCPKCS7Decrypt combines CBC decryption and PKCS7 unpadding
+func SM4CBCPKCS7Decrypt(key, src []byte) ([]byte, error) {
+	pt, err := sm4CBCDecrypt(key, src)
+	if err != nil {
+		return nil, err
+	}
+	return sm4PKCS7UnPadding(pt)
+}
+
+type gmsm4Encryptor struct{}
+
+//实现 Encryptor 接口
+func (e *gmsm4Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) (ciphertext []byte, err error) {
+	key := k.(*gmsm4PrivateKey).privKey
+
+	switch o := opts.(type) {
+	case *bccsp.SM4CBCPKCS7ModeOpts:
+		// SM4 in CBC mode with PKCS7 padding
+		if len(o.IV) != 0 && o.PRNG != nil {
+			return nil, errors.New("Invalid options. Either IV or PRNG should be different from nil, or both nil.")
+		}
+
+		if len(o.IV) != 0 {
+			return SM4CBCPKCS7EncryptWithIV(o.IV, key, plaintext)
+		} else if o.PRNG != nil {
+			return SM4CBCPKCS7EncryptWithRand(o.PRNG, key, plaintext)
+		}
+		return SM4CBCPKCS7Encrypt(key, plaintext)
+	case bccsp.SM4CBCPKCS7ModeOpts:
+		return e.Encrypt(k, plaintext, &o)
+	default:
+		// Without a mode, a single block is encrypted
+		return SM4Encrypt(key, plaintext)
+	}
 }
 
 type gmsm4Decryptor struct{}
 
 //实现 Decryptor 接口
 func (*gmsm4Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) (plaintext []byte, err error) {
-
-	return SM4Decrypt(k.(*gmsm4PrivateKey).privKey, ciphertext)
-	// var dc = make([]byte, 16)
-	// key := k.(*gmsm4PrivateKey).privKey
-	// sms4(ciphertext, 16, key, dc, 0)
-	// return dc, nil
+	key := k.(*gmsm4PrivateKey).privKey
+
+	switch opts.(type) {
+	case *bccsp.SM4CBCPKCS7ModeOpts, bccsp.SM4CBCPKCS7ModeOpts:
+		// SM4 in CBC mode with PKCS7 padding
+		return SM4CBCPKCS7Decrypt(key, ciphertext)
+	default:
+		// Without a mode, a single block is decrypted
+		return SM4Decrypt(key, ciphertext)
+	}
 }
@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// Example 1 of GM/T 0002 (GB/T 32907): key and plaintext are the same block.
+func TestSM4KnownAnswer(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	key := decodeHex(t, "0123456789abcdeffedcba9876543210")
+	expected := decodeHex(t, "681edf34d206965e86b3e94f536e4246")
+
+	k, err := csp.KeyImport(key, &bccsp.GMSM4ImportKeyOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.True(t, k.Symmetric())
+
+	ciphertext, err := csp.Encrypt(k, key, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expected, ciphertext)
+
+	plaintext, err := csp.Decrypt(k, ciphertext, nil)
+	require.NoError(t, err)
+	assert.Equal(t, key, plaintext)
+}
+
+// Example 2 of GM/T 0002 (GB/T 32907): the block encrypted 1,000,000 times.
+func TestSM4KnownAnswerIterated(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping iterated SM4 vector in short mode")
+	}
+
+	key := decodeHex(t, "0123456789abcdeffedcba9876543210")
+	expected := decodeHex(t, "595298c7c6fd271f0402f804c33d3f66")
+
+	block := key
+	for i := 0; i < 1000000; i++ {
+		var err error
+		block, err = SM4Encrypt(key, block)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, expected, block)
+}
+
+func TestSM4InvalidKey(t *testing.T) {
+	_, err := SM4Encrypt([]byte{1, 2, 3}, make([]byte, 16))
+	assert.Error(t, err)
+
+	_, err = SM4Decrypt([]byte{1, 2, 3}, make([]byte, 16))
+	assert.Error(t, err)
+}
+
+func TestSM4KeyImportInvalidRaw(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	_, err := csp.KeyImport("not bytes", &bccsp.GMSM4ImportKeyOpts{Temporary: true})
+	assert.Error(t, err)
+
+	_, err = csp.KeyImport([]byte(nil), &bccsp.GMSM4ImportKeyOpts{Temporary: true})
+	assert.Error(t, err)
+
+	_, err = csp.KeyImport(make([]byte, 32), &bccsp.GMSM4ImportKeyOpts{Temporary: true})
+	assert.Contains(t, err.Error(), "Invalid Key Length [32]. Must be 16 bytes")
+}
+
+func TestSM4KeyLength(t *testing.T) {
+	csp, ks, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	k, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	assert.Len(t, k.(*gmsm4PrivateKey).privKey, 16)
+
+	// a key generated with 32 bytes, as it used to be, is rejected when it is loaded
+	legacyKey := &gmsm4PrivateKey{privKey: make([]byte, 32)}
+	require.NoError(t, ks.StoreKey(legacyKey))
+	_, err = ks.GetKey(legacyKey.SKI())
+	assert.EqualError(t, err, fmt.Sprintf("Invalid GMSM4 key [%x]: 32 bytes instead of 16, the key must be generated again", legacyKey.SKI()))
+}
+
+func TestGetRandomBytes(t *testing.T) {
+	_, err := GetRandomBytes(-1)
+	assert.Error(t, err)
+
+	b, err := GetRandomBytes(32)
+	assert.NoError(t, err)
+	assert.Len(t, b, 32)
+}
+
+func TestSM4CBCPKCS7(t *testing.T) {
+	csp, _, cleanup := newTestProvider(t)
+	defer cleanup()
+
+	k, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	for _, l := range []int{0, 1, 15, 16, 17, 100} {
+		plaintext := make([]byte, l)
+		for i := range plaintext {
+			plaintext[i] = byte(i)
+		}
+
+		ciphertext, err := csp.Encrypt(k, plaintext, &bccsp.SM4CBCPKCS7ModeOpts{})
+		require.NoError(t, err)
+		// IV followed by the padded plaintext
+		assert.Len(t, ciphertext, 16+(l/16+1)*16)
+
+		decrypted, err := csp.Decrypt(k, ciphertext, bccsp.SM4CBCPKCS7ModeOpts{})
+		require.NoError(t, err)
+		assert.Equal(t, plaintext, decrypted)
+	}
+
+	_, err = csp.Encrypt(k, []byte("Hello World"), &bccsp.SM4CBCPKCS7ModeOpts{IV: make([]byte, 16), PRNG: rand.Reader})
+	assert.EqualError(t, err, "Invalid options. Either IV or PRNG should be different from nil, or both nil.")
+	_, err = csp.Encrypt(k, []byte("Hello World"), &bccsp.SM4CBCPKCS7ModeOpts{IV: []byte{1, 2, 3}})
+	assert.Error(t, err)
+
+	_, err = csp.Decrypt(k, []byte{1, 2, 3}, &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.Error(t, err)
+	_, err = csp.Decrypt(k, make([]byte, 20), &bccsp.SM4CBCPKCS7ModeOpts{})
+	assert.Error(t, err)
+}
+
+func TestSM4CBCPKCS7KnownAnswer(t *testing.T) {
+	key := decodeHex(t, "0123456789abcdeffedcba9876543210")
+	iv := make([]byte, 16)
+
+	// With a zero IV, the first block is the one of the single block vector
+	ciphertext, err := SM4CBCPKCS7EncryptWithIV(iv, key, key)
+	require.NoError(t, err)
+	require.Len(t, ciphertext, 48)
+	assert.Equal(t, iv, ciphertext[:16])
+	assert.Equal(t, decodeHex(t, "681edf34d206965e86b3e94f536e4246"), ciphertext[16:32])
+
+	plaintext, err := SM4CBCPKCS7Decrypt(key, ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, key, plaintext)
+
+	// The IV is sampled from the PRNG
+	ciphertext, err = SM4CBCPKCS7EncryptWithRand(bytes.NewReader(iv), key, key)
+	require.NoError(t, err)
+	assert.Equal(t, iv, ciphertext[:16])
+}
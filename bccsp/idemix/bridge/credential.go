@@ -51,7 +51,7 @@ func (c *Credential) Sign(key handlers.IssuerSecretKey, credentialRequest []byte
 	for i := 0; i < len(attributes); i++ {
 		switch attributes[i].Type {
 		case bccsp.IdemixBytesAttribute:
-			attrValues[i] = cryptolib.HashModOrder(attributes[i].Value.([]byte))
+			attrValues[i] = iisk.SK.Ipk.HashModOrder(attributes[i].Value.([]byte))
 		case bccsp.IdemixIntAttribute:
 			attrValues[i] = FP256BN.NewBIGint(attributes[i].Value.(int))
 		default:
@@ -97,7 +97,7 @@ func (*Credential) Verify(sk handlers.Big, ipk handlers.IssuerPublicKey, credent
 		switch attributes[i].Type {
 		case bccsp.IdemixBytesAttribute:
 			if !bytes.Equal(
-				cryptolib.BigToBytes(cryptolib.HashModOrder(attributes[i].Value.([]byte))),
+				cryptolib.BigToBytes(iipk.PK.HashModOrder(attributes[i].Value.([]byte))),
 				cred.Attrs[i]) {
 				return errors.Errorf("credential does not contain the correct attribute value at position [%d]", i)
 			}
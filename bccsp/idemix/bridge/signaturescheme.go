@@ -119,7 +119,7 @@ func (*SignatureScheme) Verify(ipk handlers.IssuerPublicKey, signature, digest [
 			attrValues[i] = nil
 		case bccsp.IdemixBytesAttribute:
 			disclosure[i] = 1
-			attrValues[i] = cryptolib.HashModOrder(attributes[i].Value.([]byte))
+			attrValues[i] = iipk.PK.HashModOrder(attributes[i].Value.([]byte))
 		case bccsp.IdemixIntAttribute:
 			disclosure[i] = 1
 			attrValues[i] = FP256BN.NewBIGint(attributes[i].Value.(int))
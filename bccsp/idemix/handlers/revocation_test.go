@@ -14,6 +14,8 @@ import (
 	"encoding/pem"
 	"math/big"
 
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"github.com/hyperledger/fabric/bccsp/idemix/handlers"
 
 	"github.com/hyperledger/fabric/bccsp"
@@ -183,6 +185,38 @@ var _ = Describe("Revocation", func() {
 			})
 		})
 
+		Context("and the key is an SM2 key", func() {
+			var (
+				raw      []byte
+				pemBytes []byte
+			)
+
+			BeforeEach(func() {
+				key, err := sm2.GenerateKey(rand.Reader)
+				Expect(err).NotTo(HaveOccurred())
+
+				raw, err = x509GM.MarshalPKIXPublicKey(&key.PublicKey)
+				Expect(err).NotTo(HaveOccurred())
+
+				pemBytes = pem.EncodeToMemory(
+					&pem.Block{
+						Type:  "PUBLIC KEY",
+						Bytes: raw,
+					},
+				)
+			})
+
+			It("import is successful", func() {
+				k, err := RevocationPublicKeyImporter.KeyImport(pemBytes, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k.SKI()).NotTo(BeNil())
+
+				bytes, err := k.Bytes()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bytes).To(BeEquivalentTo(raw))
+			})
+		})
+
 		Context("and the underlying cryptographic algorithm fails", func() {
 
 			It("returns an error on nil raw", func() {
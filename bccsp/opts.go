@@ -16,6 +16,8 @@ limitations under the License.
 
 package bccsp
 
+import "io"
+
 const (
 	// ECDSA Elliptic Curve Digital Signature Algorithm (key gen, import, sign, verify),
 	// at default security level.
@@ -340,6 +342,41 @@ func (opts *GMSM4ImportKeyOpts) Ephemeral() bool {
 	return opts.Temporary
 }
 
+// SM4CBCPKCS7ModeOpts contains options for SM4 encryption in CBC mode
+// with PKCS7 padding.
+// Notice that both IV and PRNG can be nil. In that case, the BCCSP implementation
+// is supposed to sample the IV using a cryptographic secure PRNG.
+// Notice also that either IV or PRNG can be different from nil.
+type SM4CBCPKCS7ModeOpts struct {
+	// IV is the initialization vector to be used by the underlying cipher.
+	// The length of IV must be the same as the Block's block size.
+	// It is used only if different from nil.
+	IV []byte
+	// PRNG is an instance of a PRNG to be used by the underlying cipher.
+	// It is used only if different from nil.
+	PRNG io.Reader
+}
+
+// GMSM2EncrypterOpts contains options for SM2 public key encryption
+// as specified by GM/T 0003.4.
+// By default, the ciphertext is the concatenation C1||C3||C2, C1 being
+// an uncompressed point.
+type GMSM2EncrypterOpts struct {
+	// ASN1 selects the ASN.1 encoding of the ciphertext specified by
+	// GM/T 0009 instead of the concatenation of its parts.
+	ASN1 bool
+	// PRNG is an instance of a PRNG to be used to sample the ephemeral key.
+	// It is used only if different from nil.
+	PRNG io.Reader
+}
+
+// GMSM2DecrypterOpts contains options for SM2 public key decryption
+// as specified by GM/T 0003.4.
+type GMSM2DecrypterOpts struct {
+	// ASN1 tells that the ciphertext is encoded as specified by GM/T 0009.
+	ASN1 bool
+}
+
 //GMSM2PrivateKeyImportOpts  实现  bccsp.KeyImportOpts 接口
 type GMSM2PrivateKeyImportOpts struct {
 	Temporary bool
@@ -0,0 +1,29 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package sw
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+	provider, _, cleanup := currentTestConfig.Provider(t)
+	defer cleanup()
+
+	conformance.Run(t, provider, &conformance.Suite{
+		AsymmetricKeyGenOpts: &bccsp.ECDSAKeyGenOpts{Temporary: false},
+		PublicKeyImportOpts:  &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true},
+		SymmetricKeyGenOpts:  &bccsp.AESKeyGenOpts{Temporary: false},
+		EncrypterOpts:        &bccsp.AESCBCPKCS7ModeOpts{},
+		DecrypterOpts:        &bccsp.AESCBCPKCS7ModeOpts{},
+		HashOpts:             &bccsp.SHAOpts{},
+	})
+}
@@ -25,15 +25,19 @@ const (
 
 	// ChannelV1_4_3 is the capabilities string for standard new non-backwards compatible fabric v1.4.3 channel capabilities.
 	ChannelV1_4_3 = "V1_4_3"
+
+	// ChannelV1_4_10 is the capabilities string for standard new non-backwards compatible fabric v1.4.10 channel capabilities.
+	ChannelV1_4_10 = "V1_4_10"
 )
 
 // ChannelProvider provides capabilities information for channel level config.
 type ChannelProvider struct {
 	*registry
-	v11  bool
-	v13  bool
-	v142 bool
-	v143 bool
+	v11   bool
+	v13   bool
+	v142  bool
+	v143  bool
+	v1410 bool
 }
 
 // NewChannelProvider creates a channel capabilities provider.
@@ -44,6 +48,7 @@ func NewChannelProvider(capabilities map[string]*cb.Capability) *ChannelProvider
 	_, cp.v13 = capabilities[ChannelV1_3]
 	_, cp.v142 = capabilities[ChannelV1_4_2]
 	_, cp.v143 = capabilities[ChannelV1_4_3]
+	_, cp.v1410 = capabilities[ChannelV1_4_10]
 	return cp
 }
 
@@ -56,6 +61,8 @@ func (cp *ChannelProvider) Type() string {
 func (cp *ChannelProvider) HasCapability(capability string) bool {
 	switch capability {
 	// Add new capability names here
+	case ChannelV1_4_10:
+		return true
 	case ChannelV1_4_3:
 		return true
 	case ChannelV1_4_2:
@@ -72,6 +79,8 @@ func (cp *ChannelProvider) HasCapability(capability string) bool {
 // MSPVersion returns the level of MSP support required by this channel.
 func (cp *ChannelProvider) MSPVersion() msp.MSPVersion {
 	switch {
+	case cp.v1410:
+		return msp.MSPv1_4_3
 	case cp.v143:
 		return msp.MSPv1_4_3
 	case cp.v142:
@@ -87,10 +96,18 @@ func (cp *ChannelProvider) MSPVersion() msp.MSPVersion {
 
 // ConsensusTypeMigration return true if consensus-type migration is supported and permitted in both orderer and peer.
 func (cp *ChannelProvider) ConsensusTypeMigration() bool {
-	return cp.v142 || cp.v143
+	return cp.v142 || cp.v143 || cp.v1410
 }
 
 // OrgSpecificOrdererEndpoints allows for individual orderer orgs to specify their external addresses for their OSNs.
 func (cp *ChannelProvider) OrgSpecificOrdererEndpoints() bool {
-	return cp.v142 || cp.v143
+	return cp.v142 || cp.v143 || cp.v1410
+}
+
+// SM3Hashing checks whether the channel permits the SM3 hashing algorithm, hashing
+// the blocks and deriving the transaction IDs with SM3.
+//
+// Orderers and peers which do not support it would reject the config, so it is rejected when not present.
+func (cp *ChannelProvider) SM3Hashing() bool {
+	return cp.v1410
 }
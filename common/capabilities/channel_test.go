@@ -20,6 +20,7 @@ func TestChannelV10(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_0)
 	assert.False(t, cp.ConsensusTypeMigration())
 	assert.False(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
 }
 
 func TestChannelV11(t *testing.T) {
@@ -30,6 +31,7 @@ func TestChannelV11(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_1)
 	assert.False(t, cp.ConsensusTypeMigration())
 	assert.False(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
 }
 
 func TestChannelV13(t *testing.T) {
@@ -41,6 +43,7 @@ func TestChannelV13(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_3)
 	assert.False(t, cp.ConsensusTypeMigration())
 	assert.False(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
 
 	cp = NewChannelProvider(map[string]*cb.Capability{
 		ChannelV1_3: {},
@@ -49,6 +52,7 @@ func TestChannelV13(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_3)
 	assert.False(t, cp.ConsensusTypeMigration())
 	assert.False(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
 }
 
 func TestChannelV142(t *testing.T) {
@@ -60,6 +64,7 @@ func TestChannelV142(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_3)
 	assert.True(t, cp.ConsensusTypeMigration())
 	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
 
 	cp = NewChannelProvider(map[string]*cb.Capability{
 		ChannelV1_4_2: {},
@@ -68,6 +73,7 @@ func TestChannelV142(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_3)
 	assert.True(t, cp.ConsensusTypeMigration())
 	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
 }
 
 func TestChannelV143(t *testing.T) {
@@ -80,6 +86,7 @@ func TestChannelV143(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
 	assert.True(t, cp.ConsensusTypeMigration())
 	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
 
 	cp = NewChannelProvider(map[string]*cb.Capability{
 		ChannelV1_4_3: {},
@@ -88,6 +95,18 @@ func TestChannelV143(t *testing.T) {
 	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
 	assert.True(t, cp.ConsensusTypeMigration())
 	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.False(t, cp.SM3Hashing())
+}
+
+func TestChannelV1410(t *testing.T) {
+	cp := NewChannelProvider(map[string]*cb.Capability{
+		ChannelV1_4_10: {},
+	})
+	assert.NoError(t, cp.Supported())
+	assert.True(t, cp.MSPVersion() == msp.MSPv1_4_3)
+	assert.True(t, cp.ConsensusTypeMigration())
+	assert.True(t, cp.OrgSpecificOrdererEndpoints())
+	assert.True(t, cp.SM3Hashing())
 }
 
 func TestChannelNotSuported(t *testing.T) {
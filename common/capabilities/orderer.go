@@ -18,6 +18,9 @@ const (
 
 	// OrdererV1_4_2 is the capabilities string for standard new non-backwards compatible Fabric v1.4.2 orderer capabilities.
 	OrdererV1_4_2 = "V1_4_2"
+
+	// OrdererV1_4_10 is the capabilities string for standard new non-backwards compatible Fabric v1.4.10 orderer capabilities.
+	OrdererV1_4_10 = "V1_4_10"
 )
 
 // OrdererProvider provides capabilities information for orderer level config.
@@ -25,6 +28,7 @@ type OrdererProvider struct {
 	*registry
 	v11BugFixes bool
 	v142        bool
+	v1410       bool
 }
 
 // NewOrdererProvider creates an orderer capabilities provider.
@@ -33,6 +37,7 @@ func NewOrdererProvider(capabilities map[string]*cb.Capability) *OrdererProvider
 	cp.registry = newRegistry(cp, capabilities)
 	_, cp.v11BugFixes = capabilities[OrdererV1_1]
 	_, cp.v142 = capabilities[OrdererV1_4_2]
+	_, cp.v1410 = capabilities[OrdererV1_4_10]
 	return cp
 }
 
@@ -49,6 +54,8 @@ func (cp *OrdererProvider) HasCapability(capability string) bool {
 		return true
 	case OrdererV1_4_2:
 		return true
+	case OrdererV1_4_10:
+		return true
 	default:
 		return false
 	}
@@ -57,19 +64,19 @@ func (cp *OrdererProvider) HasCapability(capability string) bool {
 // PredictableChannelTemplate specifies whether the v1.0 undesirable behavior of setting the /Channel
 // group's mod_policy to "" and copying versions from the channel config should be fixed or not.
 func (cp *OrdererProvider) PredictableChannelTemplate() bool {
-	return cp.v11BugFixes || cp.v142
+	return cp.v11BugFixes || cp.v142 || cp.v1410
 }
 
 // Resubmission specifies whether the v1.0 non-deterministic commitment of tx should be fixed by re-submitting
 // the re-validated tx.
 func (cp *OrdererProvider) Resubmission() bool {
-	return cp.v11BugFixes || cp.v142
+	return cp.v11BugFixes || cp.v142 || cp.v1410
 }
 
 // ExpirationCheck specifies whether the orderer checks for identity expiration checks
 // when validating messages
 func (cp *OrdererProvider) ExpirationCheck() bool {
-	return cp.v11BugFixes || cp.v142
+	return cp.v11BugFixes || cp.v142 || cp.v1410
 }
 
 // ConsensusTypeMigration checks whether the orderer permits a consensus-type migration.
@@ -78,5 +85,13 @@ func (cp *OrdererProvider) ExpirationCheck() bool {
 // with consensus-type migration change. Migration is supported from Kafka to Raft only.
 // If not present, these config updates will be rejected.
 func (cp *OrdererProvider) ConsensusTypeMigration() bool {
-	return cp.v142
+	return cp.v142 || cp.v1410
+}
+
+// BatchPriority checks whether the orderer permits the BatchPriority value, ordering the messages
+// of a batch by priority lane and cutting it by the max pending age of its messages.
+//
+// Orderers which do not support it would cut different blocks, so it is rejected when not present.
+func (cp *OrdererProvider) BatchPriority() bool {
+	return cp.v1410
 }
@@ -43,6 +43,19 @@ func TestOrdererV142(t *testing.T) {
 	assert.True(t, op.Resubmission())
 	assert.True(t, op.ExpirationCheck())
 	assert.True(t, op.ConsensusTypeMigration())
+	assert.False(t, op.BatchPriority())
+}
+
+func TestOrdererV1410(t *testing.T) {
+	op := NewOrdererProvider(map[string]*cb.Capability{
+		OrdererV1_4_10: {},
+	})
+	assert.NoError(t, op.Supported())
+	assert.True(t, op.PredictableChannelTemplate())
+	assert.True(t, op.Resubmission())
+	assert.True(t, op.ExpirationCheck())
+	assert.True(t, op.ConsensusTypeMigration())
+	assert.True(t, op.BatchPriority())
 }
 
 func TestNotSuported(t *testing.T) {
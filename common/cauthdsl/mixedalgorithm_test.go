@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cauthdsl
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/testutil"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMixedAlgorithmMember sets up the MSP of an organization using SM2 when
+// gmOrg is true, ECDSA otherwise, and returns it with a function signing
+// data as a member of the organization.
+func newMixedAlgorithmMember(t *testing.T, dir, mspID string, gmOrg bool) (msp.MSP, func(data []byte) *cb.SignedData) {
+	org := testutil.NewMixedAlgorithmOrg(t, dir, mspID, gmOrg)
+
+	mspInstance, err := msp.New(&msp.BCCSPNewOpts{NewBaseOpts: msp.NewBaseOpts{Version: msp.MSPv1_3}})
+	require.NoError(t, err)
+	err = mspInstance.Setup(&mb.MSPConfig{
+		Type:   int32(msp.FABRIC),
+		Config: marshalOrPanic(org.FabricMSPConfig()),
+	})
+	require.NoError(t, err)
+
+	identity, err := msp.NewSerializedIdentity(mspID, org.MemberCertPEM)
+	require.NoError(t, err)
+
+	return mspInstance, func(data []byte) *cb.SignedData {
+		return &cb.SignedData{Identity: identity, Data: data, Signature: org.Sign(t, data)}
+	}
+}
+
+func TestMixedAlgorithmPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cauthdsl-mixed-algorithms")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ecdsaMSP, ecdsaSign := newMixedAlgorithmMember(t, dir, "ECDSAOrg", false)
+	sm2MSP, sm2Sign := newMixedAlgorithmMember(t, dir, "SM2Org", true)
+
+	mspManager := msp.NewMSPManager()
+	err = mspManager.Setup([]msp.MSP{ecdsaMSP, sm2MSP})
+	require.NoError(t, err)
+
+	envelope, err := FromString("AND('ECDSAOrg.member', 'SM2Org.member')")
+	require.NoError(t, err)
+	policy, _, err := NewPolicyProvider(mspManager).NewPolicy(marshalOrPanic(envelope))
+	require.NoError(t, err)
+
+	data := []byte("data")
+	assert.NoError(t, policy.Evaluate([]*cb.SignedData{ecdsaSign(data), sm2Sign(data)}))
+	assert.NoError(t, policy.Evaluate([]*cb.SignedData{sm2Sign(data), ecdsaSign(data)}))
+	assert.Error(t, policy.Evaluate([]*cb.SignedData{ecdsaSign(data)}))
+	assert.Error(t, policy.Evaluate([]*cb.SignedData{sm2Sign(data)}))
+
+	// A signature over other data does not count for its organization
+	badSig := sm2Sign([]byte("other data"))
+	badSig.Data = data
+	assert.Error(t, policy.Evaluate([]*cb.SignedData{ecdsaSign(data), badSig}))
+}
@@ -60,6 +60,10 @@ type Channel interface {
 	// such as computing block hashes, and CreationPolicy digests
 	HashingAlgorithm() func(input []byte) []byte
 
+	// BlockHashingAlgorithm returns the algorithm hashing the block headers,
+	// the block data and the transaction IDs
+	BlockHashingAlgorithm() func(input []byte) []byte
+
 	// BlockDataHashingStructureWidth returns the width to use when constructing the
 	// Merkle tree to compute the BlockData hash
 	BlockDataHashingStructureWidth() uint32
@@ -103,6 +107,9 @@ type Orderer interface {
 	// BatchTimeout returns the amount of time to wait before creating a batch
 	BatchTimeout() time.Duration
 
+	// BatchPriority returns the lanes ordering the messages of a batch
+	BatchPriority() *ab.BatchPriority
+
 	// MaxChannelsCount returns the maximum count of channels to allow for an ordering network
 	MaxChannelsCount() uint64
 
@@ -132,6 +139,9 @@ type ChannelCapabilities interface {
 
 	// OrgSpecificOrdererEndpoints return true if the channel config processing allows orderer orgs to specify their own endpoints
 	OrgSpecificOrdererEndpoints() bool
+
+	// SM3Hashing returns true if the channel permits the SM3 hashing algorithm.
+	SM3Hashing() bool
 }
 
 // ApplicationCapabilities defines the capabilities for the application portion of a channel
@@ -205,6 +215,9 @@ type OrdererCapabilities interface {
 
 	// ConsensusTypeMigration checks whether the orderer permits a consensus-type migration.
 	ConsensusTypeMigration() bool
+
+	// BatchPriority checks whether the orderer permits the BatchPriority value.
+	BatchPriority() bool
 }
 
 // PolicyMapper is an interface for
@@ -11,6 +11,7 @@ import (
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -78,6 +79,14 @@ func (b *Bundle) ConfigtxValidator() configtx.Validator {
 // ValidateNew checks if a new bundle's contained configuration is valid to be derived from the current bundle.
 // This allows checks of the nature "Make sure that the consensus type did not change".
 func (b *Bundle) ValidateNew(nb Resources) error {
+	// The block hashes of the chain must keep being computed the same way
+	if ncc, ok := nb.ChannelConfig().(*ChannelConfig); ok {
+		name, newName := b.channelConfig.hashingAlgorithmName(), ncc.hashingAlgorithmName()
+		if (name == util.SM3) != (newName == util.SM3) {
+			return errors.Errorf("attempted to change the block hashing algorithm from %s to %s", name, newName)
+		}
+	}
+
 	if oc, ok := b.OrdererConfig(); ok {
 		noc, ok := nb.OrdererConfig()
 		if !ok {
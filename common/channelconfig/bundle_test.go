@@ -245,6 +245,25 @@ func TestValidateNew(t *testing.T) {
 	})
 }
 
+func TestValidateNewHashingAlgorithm(t *testing.T) {
+	bundle := func(name string) *Bundle {
+		return &Bundle{
+			channelConfig: &ChannelConfig{
+				protos: &ChannelProtos{HashingAlgorithm: &cb.HashingAlgorithm{Name: name}},
+			},
+		}
+	}
+
+	assert.NoError(t, bundle("SM3").ValidateNew(bundle("SM3")))
+	assert.NoError(t, bundle("SHA256").ValidateNew(bundle("SHA3_256")))
+
+	err := bundle("SHA256").ValidateNew(bundle("SM3"))
+	assert.EqualError(t, err, "attempted to change the block hashing algorithm from SHA256 to SM3")
+
+	err = bundle("SM3").ValidateNew(bundle("SHA256"))
+	assert.EqualError(t, err, "attempted to change the block hashing algorithm from SM3 to SHA256")
+}
+
 func TestValidateNewWithConsensusMigration(t *testing.T) {
 	t.Run("ConsensusTypeMigration Green Path", func(t *testing.T) {
 		for _, sysChan := range []bool{false, true} {
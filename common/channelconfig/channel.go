@@ -46,6 +46,10 @@ type ChannelValues interface {
 	// such as computing block hashes, and CreationPolicy digests
 	HashingAlgorithm() func(input []byte) []byte
 
+	// BlockHashingAlgorithm returns the algorithm hashing the block headers,
+	// the block data and the transaction IDs
+	BlockHashingAlgorithm() func(input []byte) []byte
+
 	// BlockDataHashingStructureWidth returns the width to use when constructing the
 	// Merkle tree to compute the BlockData hash
 	BlockDataHashingStructureWidth() uint32
@@ -143,6 +147,19 @@ func (cc *ChannelConfig) HashingAlgorithm() func(input []byte) []byte {
 	return cc.hashingAlgorithm
 }
 
+// BlockHashingAlgorithm returns a function pointer to the algorithm hashing
+// the block headers, the block data and the transaction IDs of the chain
+func (cc *ChannelConfig) BlockHashingAlgorithm() func(input []byte) []byte {
+	return util.BlockHashingFunction(cc.protos.HashingAlgorithm.Name)
+}
+
+func (cc *ChannelConfig) hashingAlgorithmName() string {
+	if cc == nil || cc.protos == nil || cc.protos.HashingAlgorithm == nil {
+		return ""
+	}
+	return cc.protos.HashingAlgorithm.Name
+}
+
 // BlockDataHashingStructure returns the width to use when forming the block data hashing structure
 func (cc *ChannelConfig) BlockDataHashingStructureWidth() uint32 {
 	return cc.protos.BlockDataHashingStructure.Width
@@ -174,6 +191,10 @@ func (cc *ChannelConfig) Validate(channelCapabilities ChannelCapabilities) error
 		}
 	}
 
+	if cc.protos.HashingAlgorithm.Name == util.SM3 && !channelCapabilities.SM3Hashing() {
+		return fmt.Errorf("Attempted to set the SM3 hashing algorithm until V1_4_10+ channel capabilities have been enabled")
+	}
+
 	if !channelCapabilities.OrgSpecificOrdererEndpoints() {
 		return cc.validateOrdererAddresses()
 	}
@@ -187,6 +208,8 @@ func (cc *ChannelConfig) validateHashingAlgorithm() error {
 		cc.hashingAlgorithm = util.ComputeSHA256
 	case bccsp.SHA3_256:
 		cc.hashingAlgorithm = util.ComputeSHA3256
+	case util.SM3:
+		cc.hashingAlgorithm = util.ComputeSM3
 	default:
 		return fmt.Errorf("Unknown hashing algorithm type: %s", cc.protos.HashingAlgorithm.Name)
 	}
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
@@ -49,6 +50,29 @@ func TestHashingAlgorithm(t *testing.T) {
 
 	assert.Equal(t, reflect.ValueOf(util.ComputeSHA3256).Pointer(), reflect.ValueOf(cc.HashingAlgorithm()).Pointer(),
 		"Unexpected hashing algorithm returned")
+	assert.Equal(t, reflect.ValueOf(util.ComputeSHA256).Pointer(), reflect.ValueOf(cc.BlockHashingAlgorithm()).Pointer(),
+		"Unexpected block hashing algorithm returned")
+
+	cc = &ChannelConfig{protos: &ChannelProtos{HashingAlgorithm: &cb.HashingAlgorithm{Name: util.SM3}}}
+	assert.NoError(t, cc.validateHashingAlgorithm(), "Allowed hashing algorith SM3 supplied")
+
+	assert.Equal(t, reflect.ValueOf(util.ComputeSM3).Pointer(), reflect.ValueOf(cc.HashingAlgorithm()).Pointer(),
+		"Unexpected hashing algorithm returned")
+	assert.Equal(t, reflect.ValueOf(util.ComputeSM3).Pointer(), reflect.ValueOf(cc.BlockHashingAlgorithm()).Pointer(),
+		"Unexpected block hashing algorithm returned")
+}
+
+func TestSM3HashingCapability(t *testing.T) {
+	cc := &ChannelConfig{protos: &ChannelProtos{
+		HashingAlgorithm:          &cb.HashingAlgorithm{Name: util.SM3},
+		BlockDataHashingStructure: &cb.BlockDataHashingStructure{Width: math.MaxUint32},
+	}}
+
+	v1410 := capabilities.NewChannelProvider(map[string]*cb.Capability{capabilities.ChannelV1_4_10: {}})
+	assert.NoError(t, cc.Validate(v1410))
+
+	v143 := capabilities.NewChannelProvider(map[string]*cb.Capability{capabilities.ChannelV1_4_3: {}})
+	assert.EqualError(t, cc.Validate(v143), "Attempted to set the SM3 hashing algorithm until V1_4_10+ channel capabilities have been enabled")
 }
 
 func TestBlockDataHashingStructure(t *testing.T) {
@@ -34,6 +34,9 @@ const (
 	// BatchTimeoutKey is the cb.ConfigItem type key name for the BatchTimeout message.
 	BatchTimeoutKey = "BatchTimeout"
 
+	// BatchPriorityKey is the cb.ConfigItem type key name for the BatchPriority message.
+	BatchPriorityKey = "BatchPriority"
+
 	// ChannelRestrictionsKey is the key name for the ChannelRestrictions message.
 	ChannelRestrictionsKey = "ChannelRestrictions"
 
@@ -49,6 +52,7 @@ type OrdererProtos struct {
 	ConsensusType       *ab.ConsensusType
 	BatchSize           *ab.BatchSize
 	BatchTimeout        *ab.BatchTimeout
+	BatchPriority       *ab.BatchPriority
 	KafkaBrokers        *ab.KafkaBrokers
 	ChannelRestrictions *ab.ChannelRestrictions
 	Capabilities        *cb.Capabilities
@@ -168,6 +172,11 @@ func (oc *OrdererConfig) BatchTimeout() time.Duration {
 	return oc.batchTimeout
 }
 
+// BatchPriority returns the lanes ordering the messages of a batch.
+func (oc *OrdererConfig) BatchPriority() *ab.BatchPriority {
+	return oc.protos.BatchPriority
+}
+
 // KafkaBrokers returns the addresses (IP:port notation) of a set of "bootstrap"
 // Kafka brokers, i.e. this is not necessarily the entire set of Kafka brokers
 // used for ordering.
@@ -194,6 +203,7 @@ func (oc *OrdererConfig) Validate() error {
 	for _, validator := range []func() error{
 		oc.validateBatchSize,
 		oc.validateBatchTimeout,
+		oc.validateBatchPriority,
 		oc.validateKafkaBrokers,
 	} {
 		if err := validator(); err != nil {
@@ -232,6 +242,41 @@ func (oc *OrdererConfig) validateBatchTimeout() error {
 	return nil
 }
 
+func (oc *OrdererConfig) validateBatchPriority() error {
+	if len(oc.protos.BatchPriority.GetLanes()) > 0 && !capabilities.NewOrdererProvider(oc.protos.Capabilities.GetCapabilities()).BatchPriority() {
+		return fmt.Errorf("Attempted to set batch priority lanes until V1_4_10+ orderer capabilities have been enabled")
+	}
+
+	names := map[string]struct{}{}
+	for _, lane := range oc.protos.BatchPriority.GetLanes() {
+		if lane.Name == "" {
+			return fmt.Errorf("Attempted to set a batch priority lane without a name")
+		}
+		if _, exists := names[lane.Name]; exists {
+			return fmt.Errorf("Attempted to set the batch priority lane %s more than once", lane.Name)
+		}
+		names[lane.Name] = struct{}{}
+
+		for _, headerType := range lane.HeaderTypes {
+			if _, ok := cb.HeaderType_value[headerType]; !ok {
+				return fmt.Errorf("Attempted to set the batch priority lane %s to an unknown header type: %s", lane.Name, headerType)
+			}
+		}
+
+		if lane.MaxPendingAge == "" {
+			continue
+		}
+		maxPendingAge, err := time.ParseDuration(lane.MaxPendingAge)
+		if err != nil {
+			return fmt.Errorf("Attempted to set the max pending age of the batch priority lane %s to an invalid value: %s", lane.Name, err)
+		}
+		if maxPendingAge <= 0 {
+			return fmt.Errorf("Attempted to set the max pending age of the batch priority lane %s to a non-positive value: %s", lane.Name, maxPendingAge)
+		}
+	}
+	return nil
+}
+
 func (oc *OrdererConfig) validateKafkaBrokers() error {
 	for _, broker := range oc.protos.KafkaBrokers.Brokers {
 		if !brokerEntrySeemsValid(broker) {
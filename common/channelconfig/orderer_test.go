@@ -9,6 +9,8 @@ package channelconfig
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/common/capabilities"
+	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/stretchr/testify/assert"
 )
@@ -42,6 +44,43 @@ func TestBatchTimeout(t *testing.T) {
 	assert.Error(t, oc.validateBatchTimeout(), "Zero batch timeout")
 }
 
+func TestBatchPriority(t *testing.T) {
+	v1410 := &cb.Capabilities{Capabilities: map[string]*cb.Capability{capabilities.OrdererV1_4_10: {}}}
+	batchPriority := func(lanes ...*ab.PriorityLane) *OrdererConfig {
+		return &OrdererConfig{protos: &OrdererProtos{BatchPriority: &ab.BatchPriority{Lanes: lanes}, Capabilities: v1410}}
+	}
+
+	oc := &OrdererConfig{protos: &OrdererProtos{BatchPriority: &ab.BatchPriority{}}}
+	assert.NoError(t, oc.validateBatchPriority(), "No batch priority lanes")
+
+	oc = batchPriority(
+		&ab.PriorityLane{Name: "config", HeaderTypes: []string{"ORDERER_TRANSACTION"}},
+		&ab.PriorityLane{Name: "org2", HeaderTypes: []string{"ENDORSER_TRANSACTION"}, MspIds: []string{"Org2MSP"}, MaxPendingAge: "100ms"},
+	)
+	assert.NoError(t, oc.validateBatchPriority(), "Valid batch priority lanes")
+
+	oc.protos.Capabilities = &cb.Capabilities{Capabilities: map[string]*cb.Capability{capabilities.OrdererV1_4_2: {}}}
+	assert.EqualError(t, oc.validateBatchPriority(), "Attempted to set batch priority lanes until V1_4_10+ orderer capabilities have been enabled")
+
+	oc.protos.Capabilities = nil
+	assert.EqualError(t, oc.validateBatchPriority(), "Attempted to set batch priority lanes until V1_4_10+ orderer capabilities have been enabled")
+
+	oc = batchPriority(&ab.PriorityLane{MspIds: []string{"Org2MSP"}})
+	assert.EqualError(t, oc.validateBatchPriority(), "Attempted to set a batch priority lane without a name")
+
+	oc = batchPriority(&ab.PriorityLane{Name: "org2"}, &ab.PriorityLane{Name: "org2"})
+	assert.EqualError(t, oc.validateBatchPriority(), "Attempted to set the batch priority lane org2 more than once")
+
+	oc = batchPriority(&ab.PriorityLane{Name: "org2", HeaderTypes: []string{"FOO"}})
+	assert.EqualError(t, oc.validateBatchPriority(), "Attempted to set the batch priority lane org2 to an unknown header type: FOO")
+
+	oc = batchPriority(&ab.PriorityLane{Name: "org2", MaxPendingAge: "foo"})
+	assert.Error(t, oc.validateBatchPriority(), "Invalid max pending age")
+
+	oc = batchPriority(&ab.PriorityLane{Name: "org2", MaxPendingAge: "0s"})
+	assert.EqualError(t, oc.validateBatchPriority(), "Attempted to set the max pending age of the batch priority lane org2 to a non-positive value: 0s")
+}
+
 func TestKafkaBrokers(t *testing.T) {
 	oc := &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1:9092", "foo.bar:9092"}}}}
 	assert.NoError(t, oc.validateKafkaBrokers(), "Valid kafka brokers")
@@ -68,13 +68,23 @@ func ConsortiumValue(name string) *StandardConfigValue {
 	}
 }
 
-// HashingAlgorithm returns the only currently valid hashing algorithm.
+// HashingAlgorithm returns the default hashing algorithm.
 // It is a value for the /Channel group.
 func HashingAlgorithmValue() *StandardConfigValue {
+	return HashingAlgorithmValueWithName(defaultHashingAlgorithm)
+}
+
+// HashingAlgorithmValueWithName returns the config definition for the hashing
+// algorithm name, SM3 selecting it for the block hashes and the transaction IDs.
+// It is a value for the /Channel group.
+func HashingAlgorithmValueWithName(name string) *StandardConfigValue {
+	if name == "" {
+		name = defaultHashingAlgorithm
+	}
 	return &StandardConfigValue{
 		key: HashingAlgorithmKey,
 		value: &cb.HashingAlgorithm{
-			Name: defaultHashingAlgorithm,
+			Name: name,
 		},
 	}
 }
@@ -137,6 +147,17 @@ func BatchTimeoutValue(timeout string) *StandardConfigValue {
 	}
 }
 
+// BatchPriorityValue returns the config definition for the lanes ordering the messages of a batch.
+// It is a value for the /Channel/Orderer group.
+func BatchPriorityValue(lanes []*ab.PriorityLane) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: BatchPriorityKey,
+		value: &ab.BatchPriority{
+			Lanes: lanes,
+		},
+	}
+}
+
 // ChannelRestrictionsValue returns the config definition for the orderer channel restrictions.
 // It is a value for the /Channel/Orderer group.
 func ChannelRestrictionsValue(maxChannelCount uint64) *StandardConfigValue {
@@ -11,6 +11,7 @@ import (
 
 	cb "github.com/hyperledger/fabric/protos/common"
 	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 )
@@ -30,11 +31,13 @@ func basicTest(t *testing.T, sv *StandardConfigValue) {
 func TestUtilsBasic(t *testing.T) {
 	basicTest(t, ConsortiumValue("foo"))
 	basicTest(t, HashingAlgorithmValue())
+	basicTest(t, HashingAlgorithmValueWithName("SM3"))
 	basicTest(t, BlockDataHashingStructureValue())
 	basicTest(t, OrdererAddressesValue([]string{"foo:1", "bar:2"}))
 	basicTest(t, ConsensusTypeValue("foo", []byte("bar")))
 	basicTest(t, BatchSizeValue(1, 2, 3))
 	basicTest(t, BatchTimeoutValue("1s"))
+	basicTest(t, BatchPriorityValue([]*ab.PriorityLane{{Name: "foo", MspIds: []string{"bar"}}}))
 	basicTest(t, ChannelRestrictionsValue(7))
 	basicTest(t, KafkaBrokersValue([]string{"foo:1", "bar:2"}))
 	basicTest(t, MSPValue(&mspprotos.MSPConfig{}))
@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package atrest encrypts values persisted to disk with a symmetric key
+// of a BCCSP, SM4 under the GM provider and AES otherwise.
+package atrest
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// encryptedValueMarker is the first byte of the encrypted values. Neither a
+// marshaled protobuf message, nor a value prefixed with a nil byte, can start
+// with it, so that encrypted values can be told apart from the plaintext
+// values written before the encryption was enabled.
+const encryptedValueMarker = byte(0x01)
+
+// Config selects the keys of the Encryptor by their hex encoded SKI.
+type Config struct {
+	// Key encrypts the new values. When empty, new values are written in
+	// plaintext and the previous keys are only used to decrypt.
+	Key string
+	// PreviousKeys are keys in use before a rotation. They decrypt the
+	// values written with them until these are encrypted again with Key.
+	PreviousKeys []string
+}
+
+// Encryptor encrypts and decrypts values with the keys of a BCCSP.
+// Encrypted values are laid out as
+// <encryptedValueMarker><SKI length><SKI of the key><ciphertext>
+// so that they can be decrypted after the key has been rotated.
+//
+// A nil Encryptor is valid: it leaves the values in plaintext and fails to
+// decrypt encrypted values.
+type Encryptor struct {
+	csp     bccsp.BCCSP
+	current bccsp.Key
+	keys    map[string]bccsp.Key
+	encOpts bccsp.EncrypterOpts
+	decOpts bccsp.DecrypterOpts
+}
+
+// New returns an Encryptor using the keys of csp selected by conf.
+// It returns nil when conf is nil.
+func New(csp bccsp.BCCSP, conf *Config) (*Encryptor, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	if csp == nil {
+		return nil, errors.New("a BCCSP is required for the at-rest encryption")
+	}
+
+	var current bccsp.Key
+	if conf.Key != "" {
+		k, err := getKey(csp, conf.Key)
+		if err != nil {
+			return nil, err
+		}
+		current = k
+	}
+
+	var previous []bccsp.Key
+	for _, ski := range conf.PreviousKeys {
+		k, err := getKey(csp, ski)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, k)
+	}
+
+	return NewEncryptor(csp, current, previous...)
+}
+
+func getKey(csp bccsp.BCCSP, ski string) (bccsp.Key, error) {
+	raw, err := hex.DecodeString(ski)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid SKI [%s]", ski)
+	}
+	k, err := csp.GetKey(raw)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed getting key [%s]", ski)
+	}
+	return k, nil
+}
+
+// NewEncryptor returns an Encryptor encrypting with key and decrypting with
+// key and previousKeys. A nil key leaves the new values in plaintext.
+func NewEncryptor(csp bccsp.BCCSP, key bccsp.Key, previousKeys ...bccsp.Key) (*Encryptor, error) {
+	if key == nil && len(previousKeys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+
+	e := &Encryptor{
+		csp:     csp,
+		current: key,
+		keys:    map[string]bccsp.Key{},
+		encOpts: &bccsp.AESCBCPKCS7ModeOpts{},
+		decOpts: &bccsp.AESCBCPKCS7ModeOpts{},
+	}
+	if bccsp.IsGMCryptoSuite(csp) {
+		e.encOpts = &bccsp.SM4CBCPKCS7ModeOpts{}
+		e.decOpts = &bccsp.SM4CBCPKCS7ModeOpts{}
+	}
+
+	keys := previousKeys
+	if key != nil {
+		keys = append([]bccsp.Key{key}, previousKeys...)
+	}
+	for _, k := range keys {
+		if k == nil || !k.Symmetric() || !k.Private() {
+			return nil, errors.New("at-rest encryption keys must be symmetric keys")
+		}
+		if len(k.SKI()) == 0 || len(k.SKI()) > 255 {
+			return nil, errors.Errorf("invalid SKI length [%d]", len(k.SKI()))
+		}
+		e.keys[string(k.SKI())] = k
+	}
+
+	return e, nil
+}
+
+// IsEncrypted returns whether value has been encrypted by an Encryptor.
+func IsEncrypted(value []byte) bool {
+	return len(value) > 0 && value[0] == encryptedValueMarker
+}
+
+// KeyID returns the SKI of the key encrypting the new values, nil if they
+// are written in plaintext.
+func (e *Encryptor) KeyID() []byte {
+	if e == nil || e.current == nil {
+		return nil
+	}
+	return e.current.SKI()
+}
+
+// Encrypt encrypts value with the current key.
+func (e *Encryptor) Encrypt(value []byte) ([]byte, error) {
+	if e == nil || e.current == nil {
+		return value, nil
+	}
+
+	ciphertext, err := e.csp.Encrypt(e.current, value, e.encOpts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed encrypting value")
+	}
+
+	ski := e.current.SKI()
+	encrypted := make([]byte, 0, 2+len(ski)+len(ciphertext))
+	encrypted = append(encrypted, encryptedValueMarker, byte(len(ski)))
+	encrypted = append(encrypted, ski...)
+	return append(encrypted, ciphertext...), nil
+}
+
+// Decrypt returns the plaintext of value. Values which are not encrypted
+// are returned as is.
+func (e *Encryptor) Decrypt(value []byte) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if e == nil {
+		return nil, errors.New("value is encrypted but no at-rest encryption key is configured")
+	}
+
+	ski, ciphertext, err := split(value)
+	if err != nil {
+		return nil, err
+	}
+	k, ok := e.keys[string(ski)]
+	if !ok {
+		return nil, errors.Errorf("value is encrypted with unknown key [%x]", ski)
+	}
+
+	plaintext, err := e.csp.Decrypt(k, ciphertext, e.decOpts)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed decrypting value with key [%x]", ski)
+	}
+	return plaintext, nil
+}
+
+// NeedsReencryption returns whether value is not in the form the Encryptor
+// writes new values in, i.e. it is in plaintext or encrypted with a previous
+// key while a current key is set, or encrypted while no current key is set.
+func (e *Encryptor) NeedsReencryption(value []byte) bool {
+	if e == nil {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return e.current != nil
+	}
+	if e.current == nil {
+		return true
+	}
+	ski, _, err := split(value)
+	return err != nil || !bytes.Equal(ski, e.current.SKI())
+}
+
+// Reencrypt returns value in the form the Encryptor writes new values in.
+func (e *Encryptor) Reencrypt(value []byte) ([]byte, error) {
+	plaintext, err := e.Decrypt(value)
+	if err != nil {
+		return nil, err
+	}
+	return e.Encrypt(plaintext)
+}
+
+func split(value []byte) (ski, ciphertext []byte, err error) {
+	if len(value) < 2 || len(value) < 2+int(value[1]) {
+		return nil, nil, errors.New("invalid encrypted value, too short")
+	}
+	l := int(value[1])
+	return value[2 : 2+l], value[2+l:], nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package atrest
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/gm"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	k, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{})
+	require.NoError(t, err)
+	ski := hex.EncodeToString(k.SKI())
+
+	e, err := New(csp, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	_, err = New(nil, &Config{Key: ski})
+	assert.EqualError(t, err, "a BCCSP is required for the at-rest encryption")
+
+	_, err = New(csp, &Config{})
+	assert.EqualError(t, err, "at least one key is required")
+
+	_, err = New(csp, &Config{Key: "not hex"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid SKI [not hex]")
+
+	_, err = New(csp, &Config{Key: "0102"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed getting key [0102]")
+
+	ecKey, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{})
+	require.NoError(t, err)
+	_, err = New(csp, &Config{Key: hex.EncodeToString(ecKey.SKI())})
+	assert.EqualError(t, err, "at-rest encryption keys must be symmetric keys")
+
+	e, err = New(csp, &Config{Key: ski})
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), e.KeyID())
+
+	e, err = New(csp, &Config{PreviousKeys: []string{ski}})
+	require.NoError(t, err)
+	assert.Nil(t, e.KeyID())
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	swCSP, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	gmCSP, err := gm.NewDefaultSecurityLevelWithKeystore(gm.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name    string
+		csp     bccsp.BCCSP
+		keyOpts bccsp.KeyGenOpts
+	}{
+		{"SW", swCSP, &bccsp.AES256KeyGenOpts{Temporary: true}},
+		{"GM", gmCSP, &bccsp.GMSM4KeyGenOpts{Temporary: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := tc.csp.KeyGen(tc.keyOpts)
+			require.NoError(t, err)
+			e, err := NewEncryptor(tc.csp, k)
+			require.NoError(t, err)
+
+			plaintext := []byte("private data")
+			encrypted, err := e.Encrypt(plaintext)
+			require.NoError(t, err)
+			assert.True(t, IsEncrypted(encrypted))
+			assert.NotContains(t, string(encrypted), string(plaintext))
+			assert.False(t, e.NeedsReencryption(encrypted))
+
+			decrypted, err := e.Decrypt(encrypted)
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, decrypted)
+
+			// Plaintext values are passed through
+			decrypted, err = e.Decrypt(plaintext)
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, decrypted)
+			assert.True(t, e.NeedsReencryption(plaintext))
+
+			_, err = e.Decrypt([]byte{encryptedValueMarker, 32, 1})
+			assert.EqualError(t, err, "invalid encrypted value, too short")
+
+			// A nil encryptor leaves the values in plaintext
+			var nilEncryptor *Encryptor
+			value, err := nilEncryptor.Encrypt(plaintext)
+			assert.NoError(t, err)
+			assert.Equal(t, plaintext, value)
+			assert.False(t, nilEncryptor.NeedsReencryption(encrypted))
+			_, err = nilEncryptor.Decrypt(encrypted)
+			assert.EqualError(t, err, "value is encrypted but no at-rest encryption key is configured")
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	csp, err := gm.NewDefaultSecurityLevelWithKeystore(gm.NewDummyKeyStore())
+	require.NoError(t, err)
+	oldKey, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	newKey, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	oldEncryptor, err := NewEncryptor(csp, oldKey)
+	require.NoError(t, err)
+	plaintext := []byte("private data")
+	encrypted, err := oldEncryptor.Encrypt(plaintext)
+	require.NoError(t, err)
+
+	// The new key alone cannot decrypt the values of the old key
+	newEncryptor, err := NewEncryptor(csp, newKey)
+	require.NoError(t, err)
+	_, err = newEncryptor.Decrypt(encrypted)
+	assert.EqualError(t, err, "value is encrypted with unknown key ["+hex.EncodeToString(oldKey.SKI())+"]")
+
+	rotatingEncryptor, err := NewEncryptor(csp, newKey, oldKey)
+	require.NoError(t, err)
+	assert.True(t, rotatingEncryptor.NeedsReencryption(encrypted))
+	reencrypted, err := rotatingEncryptor.Reencrypt(encrypted)
+	require.NoError(t, err)
+	assert.False(t, rotatingEncryptor.NeedsReencryption(reencrypted))
+	decrypted, err := newEncryptor.Decrypt(reencrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	// Without a current key, the values are decrypted back to plaintext
+	decryptingEncryptor, err := NewEncryptor(csp, nil, newKey)
+	require.NoError(t, err)
+	assert.True(t, decryptingEncryptor.NeedsReencryption(reencrypted))
+	value, err := decryptingEncryptor.Reencrypt(reencrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, value)
+	assert.False(t, decryptingEncryptor.NeedsReencryption(value))
+}
@@ -295,6 +295,13 @@ func (h *Handler) deliverBlocks(ctx context.Context, srv *Server, envelope *cb.E
 		}
 	}
 
+	if checker, ok := cursor.(blockledger.RangeChecker); ok {
+		if status := checker.CheckRange(stopNum); status != cb.Status_SUCCESS {
+			logger.Warningf("[channel: %s] Rejecting deliver request for %s because blocks [%d, %d] are not retrievable", chdr.ChannelId, addr, number, stopNum)
+			return status, nil
+		}
+	}
+
 	for {
 		if seekInfo.Behavior == ab.SeekInfo_FAIL_IF_NOT_READY {
 			if number > chain.Reader().Height()-1 {
@@ -9,6 +9,8 @@ package deliver_test
 import (
 	"context"
 	"io"
+	"io/ioutil"
+	"os"
 	"time"
 
 	"crypto/x509"
@@ -19,7 +21,10 @@ import (
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/deliver/mock"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	fileledger "github.com/hyperledger/fabric/common/ledger/blockledger/file"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/common/util"
@@ -934,6 +939,82 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when the blocks following the genesis block were pruned from the ledger", func() {
+			var (
+				ledgerDir string
+				provider  blkstorage.BlockStoreProvider
+				blocks    []*cb.Block
+			)
+
+			BeforeEach(func() {
+				var err error
+				ledgerDir, err = ioutil.TempDir("", "deliver-pruned")
+				Expect(err).NotTo(HaveOccurred())
+
+				// every block is written to its own block file, which can be pruned
+				provider = fsblkstorage.NewProvider(
+					fsblkstorage.NewConfWithRetention(ledgerDir, 1, &fsblkstorage.RetentionPolicy{MaxBlocks: 2}),
+					&blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
+					&disabled.Provider{},
+				)
+				store, err := provider.CreateBlockStore("chain-id")
+				Expect(err).NotTo(HaveOccurred())
+				fl := fileledger.NewFileLedger(store)
+				blocks = nil
+				for i := 0; i < 10; i++ {
+					block := blockledger.CreateNextBlock(fl, []*cb.Envelope{{Payload: []byte("payload")}})
+					block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+						Value: utils.MarshalOrPanic(&cb.LastConfig{Index: block.Header.Number}),
+					})
+					Expect(fl.Append(block)).To(Succeed())
+					blocks = append(blocks, block)
+				}
+				fakeChain.ReaderReturns(fl)
+
+				seekInfo = &ab.SeekInfo{
+					Start: seekOldest,
+					Stop: &ab.SeekPosition{
+						Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 5}},
+					},
+				}
+			})
+
+			AfterEach(func() {
+				provider.Close()
+				os.RemoveAll(ledgerDir)
+			})
+
+			It("sends status not found without sending the genesis block", func() {
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(0))
+				Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+				resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+				Expect(resp).To(Equal(cb.Status_NOT_FOUND))
+			})
+
+			Context("when only the genesis block is requested", func() {
+				BeforeEach(func() {
+					seekInfo.Stop = &ab.SeekPosition{
+						Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 0}},
+					}
+				})
+
+				It("sends the genesis block", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+					b := fakeResponseSender.SendBlockResponseArgsForCall(0)
+					Expect(proto.Equal(b, blocks[0])).To(BeTrue())
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+					Expect(resp).To(Equal(cb.Status_SUCCESS))
+				})
+			})
+		})
+
 		Context("when next block status does not indicate success", func() {
 			BeforeEach(func() {
 				fakeBlockIterator.NextReturns(nil, cb.Status_UNKNOWN)
@@ -17,6 +17,7 @@ limitations under the License.
 package genesis
 
 import (
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -44,17 +45,25 @@ func NewFactoryImpl(channelGroup *cb.ConfigGroup) Factory {
 }
 
 // Block constructs and returns a genesis block for a given channel ID.
+// Its data hash and transaction ID are computed with the block hashing
+// algorithm of the channel group.
 func (f *factory) Block(channelID string) *cb.Block {
+	hashingAlgorithm, err := utils.GetHashingAlgorithmFromConfigGroup(f.channelGroup)
+	if err != nil {
+		panic(err)
+	}
+	hash := util.BlockHashingFunction(hashingAlgorithm)
+
 	payloadChannelHeader := utils.MakeChannelHeader(cb.HeaderType_CONFIG, msgVersion, channelID, epoch)
 	payloadSignatureHeader := utils.MakeSignatureHeader(nil, utils.CreateNonceOrPanic())
-	utils.SetTxID(payloadChannelHeader, payloadSignatureHeader)
+	utils.SetTxIDWithHash(payloadChannelHeader, payloadSignatureHeader, hash)
 	payloadHeader := utils.MakePayloadHeader(payloadChannelHeader, payloadSignatureHeader)
 	payload := &cb.Payload{Header: payloadHeader, Data: utils.MarshalOrPanic(&cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: f.channelGroup}})}
 	envelope := &cb.Envelope{Payload: utils.MarshalOrPanic(payload), Signature: nil}
 
 	block := cb.NewBlock(0, nil)
 	block.Data = &cb.BlockData{Data: [][]byte{utils.MarshalOrPanic(envelope)}}
-	block.Header.DataHash = block.Data.Hash()
+	block.Header.DataHash = hash(block.Data.Bytes())
 	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
 		Value: utils.MarshalOrPanic(&cb.LastConfig{Index: 0}),
 	})
@@ -9,6 +9,7 @@ package genesis
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
@@ -27,3 +28,21 @@ func TestForTransactionID(t *testing.T) {
 	configEnvPayloadChannelHeader, _ := utils.UnmarshalChannelHeader(configEnvPayload.GetHeader().ChannelHeader)
 	assert.NotEmpty(t, configEnvPayloadChannelHeader.TxId, "tx_id of configuration transaction should not be empty")
 }
+
+func TestHashingAlgorithm(t *testing.T) {
+	channelGroup := cb.NewConfigGroup()
+	channelGroup.Values["HashingAlgorithm"] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: util.SM3}),
+	}
+	block := NewFactoryImpl(channelGroup).Block("testchainid")
+	assert.Equal(t, util.ComputeSM3(block.Data.Bytes()), block.Header.DataHash)
+
+	configEnv, _ := utils.ExtractEnvelope(block, 0)
+	configEnvPayload, _ := utils.ExtractPayload(configEnv)
+	chdr, _ := utils.UnmarshalChannelHeader(configEnvPayload.GetHeader().ChannelHeader)
+	shdr, _ := utils.GetSignatureHeader(configEnvPayload.GetHeader().SignatureHeader)
+	assert.NoError(t, utils.CheckTxIDWithHash(chdr.TxId, shdr.Nonce, shdr.Creator, util.ComputeSM3))
+
+	block = NewFactoryImpl(cb.NewConfigGroup()).Block("testchainid")
+	assert.Equal(t, block.Data.Hash(), block.Header.DataHash)
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package blkstorage
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/ledger"
 	l "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
@@ -40,12 +42,28 @@ var (
 	ErrAttrNotIndexed = errors.New("attribute not indexed")
 )
 
+// BlockPrunedError is used to indicate that a block was pruned from the block store
+type BlockPrunedError struct {
+	// FirstBlockNum is the number of the first block which was not pruned
+	FirstBlockNum uint64
+}
+
+func (e *BlockPrunedError) Error() string {
+	return fmt.Sprintf("block was pruned, the first block available is [%d]", e.FirstBlockNum)
+}
+
 // BlockStoreProvider provides an handle to a BlockStore
 type BlockStoreProvider interface {
 	CreateBlockStore(ledgerid string) (BlockStore, error)
 	OpenBlockStore(ledgerid string) (BlockStore, error)
+	// BootstrapFromSnapshot creates a block store for the given ledgerid starting at lastBlock, the last
+	// block of a snapshot. The blocks before lastBlock are treated as pruned, except lastConfigBlock which
+	// is kept, and the transactions with the txIDs returned by the iterator, as strings, are treated as the
+	// transactions of these blocks
+	BootstrapFromSnapshot(ledgerid string, lastBlock, lastConfigBlock *common.Block, txIDs ledger.ResultsIterator) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
+	Remove(ledgerid string) error
 	Close()
 }
 
@@ -62,5 +80,9 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// ExportTxIDs returns an iterator over the txID, as a string, of each transaction of the block store,
+	// including those of the snapshot the block store was bootstrapped from, if any. The iterator reads
+	// the transactions committed by the time of the call, whatever is committed while it is consumed
+	ExportTxIDs() (ledger.ResultsIterator, error)
 	Shutdown()
 }
@@ -13,7 +13,10 @@ import (
 	"strings"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/hyperledger/fabric/bccsp"
+	commonutil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 )
 
@@ -124,6 +127,51 @@ func retriveFirstBlockNumFromFile(rootDir string, fileNum int) (uint64, error) {
 	return blockInfo.blockHeader.Number, nil
 }
 
+// blockHashingAlgorithm returns the name of the algorithm hashing the blocks
+// of the chain starting with genesisBlock, as selected by the channel
+// configuration it carries. The blocks of the chains without a configuration
+// are hashed with SHA256.
+func blockHashingAlgorithm(genesisBlock *common.Block) string {
+	algorithm, err := putil.GetHashingAlgorithmFromBlock(genesisBlock)
+	if err != nil || algorithm == "" {
+		logger.Debugf("No hashing algorithm in the genesis block, blocks are hashed with SHA256: %v", err)
+		return bccsp.SHA256
+	}
+	return algorithm
+}
+
+// retrieveBlockHashingAlgorithm returns the function hashing the blocks of
+// the chain whose genesis block is the first block of the files in rootDir
+func retrieveBlockHashingAlgorithm(rootDir string) (func([]byte) []byte, error) {
+	algorithm, err := retrieveBlockHashingAlgorithmName(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	return commonutil.BlockHashingFunction(algorithm), nil
+}
+
+// retrieveBlockHashingAlgorithmName returns the name of the algorithm hashing
+// the blocks of the chain whose genesis block is the first block of the files in rootDir
+func retrieveBlockHashingAlgorithmName(rootDir string) (string, error) {
+	s, err := newBlockfileStream(rootDir, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	defer s.close()
+	bb, err := s.nextBlockBytes()
+	if err != nil {
+		return "", err
+	}
+	if bb == nil {
+		return "", errors.Errorf("no genesis block in the block files of %s", rootDir)
+	}
+	genesisBlock, err := deserializeBlock(bb)
+	if err != nil {
+		return "", err
+	}
+	return blockHashingAlgorithm(genesisBlock), nil
+}
+
 func retrieveLastFileSuffix(rootDir string) (int, error) {
 	logger.Debugf("retrieveLastFileSuffix()")
 	biggestFileNum := -1
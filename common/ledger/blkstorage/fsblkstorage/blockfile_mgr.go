@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	commonutil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	putil "github.com/hyperledger/fabric/protos/utils"
@@ -32,10 +33,12 @@ const (
 )
 
 var (
-	blkMgrInfoKey = []byte("blkMgrInfo")
+	blkMgrInfoKey             = []byte("blkMgrInfo")
+	blkMgrHashingAlgorithmKey = []byte("blkMgrHashingAlgorithm")
 )
 
 type blockfileMgr struct {
+	ledgerID          string
 	rootDir           string
 	conf              *Conf
 	db                *leveldbhelper.DBHandle
@@ -44,6 +47,8 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	pruningInfo       atomic.Value
+	hashingAlgorithm  func([]byte) []byte
 }
 
 /*
@@ -86,6 +91,7 @@ At start up a new manager:
 		-- syncIndex comparing the last block indexed to what is in the FS
 		-- If index and file system are not in sync, syncs index from the FS
   *)  Updates blockchain info used by the APIs
+  *)  Prunes the block files according to the retention policy, if any
 */
 func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig, indexStore *leveldbhelper.DBHandle) *blockfileMgr {
 	logger.Debugf("newBlockfileMgr() initializing file-based block storage for ledger: %s ", id)
@@ -96,7 +102,14 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		panic(fmt.Sprintf("Error creating block storage root dir [%s]: %s", rootDir, err))
 	}
 	// Instantiate the manager, i.e. blockFileMgr structure
-	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
+	mgr := &blockfileMgr{ledgerID: id, rootDir: rootDir, conf: conf, db: indexStore, hashingAlgorithm: commonutil.ComputeSHA256}
+
+	// The block files before the first file which was not pruned are no longer read
+	pi, err := mgr.loadPruningInfo()
+	if err != nil {
+		panic(fmt.Sprintf("Could not get pruning info from db: %s", err))
+	}
+	mgr.pruningInfo.Store(pi)
 
 	// cp = checkpointInfo, retrieve from the database the file suffix or number of where blocks were stored.
 	// It also retrieves the current size of that file and the last block number that was written to that file.
@@ -151,13 +164,19 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		PreviousBlockHash: nil}
 
 	if !cpInfo.isChainEmpty {
+		// The blocks are hashed with the algorithm configured by the genesis block
+		algorithm, err := mgr.loadHashingAlgorithm()
+		if err != nil {
+			panic(fmt.Sprintf("Could not retrieve the block hashing algorithm from the genesis block: %s", err))
+		}
+		mgr.hashingAlgorithm = commonutil.BlockHashingFunction(algorithm)
 		//If start up is a restart of an existing storage, sync the index from block storage and update BlockchainInfo for external API's
 		mgr.syncIndex()
 		lastBlockHeader, err := mgr.retrieveBlockHeaderByNumber(cpInfo.lastBlockNumber)
 		if err != nil {
 			panic(fmt.Sprintf("Could not retrieve header of the last block form file: %s", err))
 		}
-		lastBlockHash := lastBlockHeader.Hash()
+		lastBlockHash := mgr.hashingAlgorithm(lastBlockHeader.Bytes())
 		previousBlockHash := lastBlockHeader.PreviousHash
 		bcInfo = &common.BlockchainInfo{
 			Height:            cpInfo.lastBlockNumber + 1,
@@ -165,6 +184,18 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 			PreviousBlockHash: previousBlockHash}
 	}
 	mgr.bcInfo.Store(bcInfo)
+
+	if conf.retention.enabled(id) && !cpInfo.isChainEmpty {
+		// Archive the files left over by a crash while pruning, then apply the
+		// retention policy which may have changed since the last start
+		if err = mgr.archivePrunedFiles(); err != nil {
+			logger.Errorf("Could not archive pruned block files: %s", err)
+		} else if lastBlock, err := mgr.retrieveBlockByNumber(cpInfo.lastBlockNumber); err != nil {
+			logger.Errorf("Could not retrieve the last block to prune block files: %s", err)
+		} else if err = mgr.prune(lastBlock); err != nil {
+			logger.Errorf("Could not prune block files: %s", err)
+		}
+	}
 	return mgr
 }
 
@@ -246,6 +277,15 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 		)
 	}
 
+	// The genesis block selects the algorithm hashing the blocks of the chain
+	if block.Header.Number == 0 {
+		algorithm := blockHashingAlgorithm(block)
+		if err := mgr.saveHashingAlgorithm(algorithm); err != nil {
+			return errors.WithMessage(err, "error saving block hashing algorithm to db")
+		}
+		mgr.hashingAlgorithm = commonutil.BlockHashingFunction(algorithm)
+	}
+
 	// Add the previous hash check - Though, not essential but may not be a bad idea to
 	// verify the field `block.Header.PreviousHash` present in the block.
 	// This check is a simple bytes comparison and hence does not cause any observable performance penalty
@@ -260,7 +300,7 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	if err != nil {
 		return errors.WithMessage(err, "error serializing block")
 	}
-	blockHash := block.Header.Hash()
+	blockHash := mgr.hashingAlgorithm(block.Header.Bytes())
 	//Get the location / offset where each transaction starts in the block and where the block ends
 	txOffsets := info.txOffsets
 	currentOffset := mgr.cpInfo.latestFileChunksize
@@ -271,9 +311,11 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 
 	//Determine if we need to start a new file since the size of this block
 	//exceeds the amount of space left in the current file
+	movedToNextFile := false
 	if currentOffset+totalBytesToAppend > mgr.conf.maxBlockfileSize {
 		mgr.moveToNextFile()
 		currentOffset = 0
+		movedToNextFile = true
 	}
 	//append blockBytesEncodedLen to the file
 	err = mgr.currentFileWriter.append(blockBytesEncodedLen, false)
@@ -322,6 +364,13 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//update the checkpoint info (for storage) and the blockchain info (for APIs) in the manager
 	mgr.updateCheckpoint(newCPInfo)
 	mgr.updateBlockchainInfo(blockHash, block)
+
+	//the block files are pruned when a block file is completed, failing to prune does not fail the block
+	if movedToNextFile {
+		if err := mgr.prune(block); err != nil {
+			logger.Errorf("Could not prune block files: %s", err)
+		}
+	}
 	return nil
 }
 
@@ -337,13 +386,14 @@ func (mgr *blockfileMgr) syncIndex() error {
 		indexEmpty = true
 	}
 
-	//initialize index to file number:zero, offset:zero and blockNum:0
-	startFileNum := 0
+	//initialize index to the first file which was not pruned, offset:zero and its first block
+	pi := mgr.getPruningInfo()
+	startFileNum := pi.firstFileNum
 	startOffset := 0
 	skipFirstBlock := false
 	//get the last file that blocks were added to using the checkpoint info
 	endFileNum := mgr.cpInfo.latestFileChunkSuffixNum
-	startingBlockNum := uint64(0)
+	startingBlockNum := pi.firstBlockNum
 
 	//if the index stored in the db has value, update the index information with those values
 	if !indexEmpty {
@@ -408,7 +458,7 @@ func (mgr *blockfileMgr) syncIndex() error {
 		}
 
 		//Update the blockIndexInfo with what was actually stored in file system
-		blockIdxInfo.blockHash = info.blockHeader.Hash()
+		blockIdxInfo.blockHash = mgr.hashingAlgorithm(info.blockHeader.Bytes())
 		blockIdxInfo.blockNum = info.blockHeader.Number
 		blockIdxInfo.flp = &fileLocPointer{fileSuffixNum: blockPlacementInfo.fileNum,
 			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
@@ -466,6 +516,10 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
 
+	if pi := mgr.getPruningInfo(); blockNum < pi.firstBlockNum {
+		return mgr.retrievePrunedBlock(blockNum)
+	}
+
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -477,7 +531,9 @@ func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error)
 	logger.Debugf("retrieveBlockByTxID() - txID = [%s]", txID)
 
 	loc, err := mgr.index.getBlockLocByTxID(txID)
-
+	if err == blkstorage.ErrNotFoundInIndex {
+		err = mgr.checkTxIDNotInSnapshot(txID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -507,12 +563,25 @@ func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.B
 }
 
 func (mgr *blockfileMgr) retrieveBlocks(startNum uint64) (*blocksItr, error) {
+	if pi := mgr.getPruningInfo(); startNum < pi.firstBlockNum {
+		// the iterators starting from the genesis block, when it is kept, return it before failing on the pruned blocks
+		kept, err := mgr.isGenesisBlockKept()
+		if err != nil {
+			return nil, err
+		}
+		if startNum != 0 || !kept {
+			return nil, &blkstorage.BlockPrunedError{FirstBlockNum: pi.firstBlockNum}
+		}
+	}
 	return newBlockItr(mgr, startNum), nil
 }
 
 func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByID() - txId = [%s]", txID)
 	loc, err := mgr.index.getTxLoc(txID)
+	if err == blkstorage.ErrNotFoundInIndex {
+		err = mgr.checkTxIDNotInSnapshot(txID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -521,6 +590,9 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if pi := mgr.getPruningInfo(); blockNum < pi.firstBlockNum {
+		return nil, &blkstorage.BlockPrunedError{FirstBlockNum: pi.firstBlockNum}
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -552,6 +624,9 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkNotPruned(lp.fileSuffixNum); err != nil {
+		return nil, err
+	}
 	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
 		return nil, err
@@ -565,6 +640,9 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkNotPruned(lp.fileSuffixNum); err != nil {
+		return nil, err
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
@@ -604,6 +682,31 @@ func (mgr *blockfileMgr) saveCurrentInfo(i *checkpointInfo, sync bool) error {
 	return nil
 }
 
+// loadHashingAlgorithm returns the name of the block hashing algorithm stored in the database.
+// The ledgers created before it was stored retrieve it from their genesis block, which
+// is kept in the first block file, and store it before this file is pruned.
+func (mgr *blockfileMgr) loadHashingAlgorithm() (string, error) {
+	b, err := mgr.db.Get(blkMgrHashingAlgorithmKey)
+	if err != nil {
+		return "", err
+	}
+	if b != nil {
+		return string(b), nil
+	}
+	algorithm, err := retrieveBlockHashingAlgorithmName(mgr.rootDir)
+	if err != nil {
+		return "", err
+	}
+	if err = mgr.saveHashingAlgorithm(algorithm); err != nil {
+		return "", err
+	}
+	return algorithm, nil
+}
+
+func (mgr *blockfileMgr) saveHashingAlgorithm(algorithm string) error {
+	return mgr.db.Put(blkMgrHashingAlgorithmKey, []byte(algorithm), true)
+}
+
 // scanForLastCompleteBlock scan a given block file and detects the last offset in the file
 // after which there may lie a block partially written (towards the end of the file in a crash scenario).
 func scanForLastCompleteBlock(rootDir string, fileNum int, startingOffset int64) ([]byte, int64, int, error) {
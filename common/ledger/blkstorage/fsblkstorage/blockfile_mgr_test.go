@@ -23,7 +23,9 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	commonutil "github.com/hyperledger/fabric/common/util"
 	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
@@ -305,6 +307,45 @@ func TestBlockfileMgrRestart(t *testing.T) {
 	assert.Equal(t, expectedHeight, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
 }
 
+func TestBlockfileMgrSM3HashedChain(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+
+	channelGroup := common.NewConfigGroup()
+	channelGroup.Values["HashingAlgorithm"] = &common.ConfigValue{
+		Value: putil.MarshalOrPanic(&common.HashingAlgorithm{Name: commonutil.SM3}),
+	}
+	gb := genesis.NewFactoryImpl(channelGroup).Block(ledgerid)
+	gb.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = ledgerutil.NewTxValidationFlagsSetValue(len(gb.Data.Data), peer.TxValidationCode_VALID)
+	block1 := testutil.ConstructBlock(t, 1, commonutil.ComputeSM3(gb.Header.Bytes()), [][]byte{[]byte("tx")}, false)
+	blkfileMgrWrapper.addBlocks([]*common.Block{gb, block1})
+	block1Hash := commonutil.ComputeSM3(block1.Header.Bytes())
+	assert.Equal(t, block1Hash, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().CurrentBlockHash)
+
+	// A block chained with SHA256 is rejected
+	block2 := testutil.ConstructBlock(t, 2, block1.Header.Hash(), [][]byte{[]byte("tx")}, false)
+	err := blkfileMgrWrapper.blockfileMgr.addBlock(block2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected Previous block hash")
+
+	block, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockByHash(block1Hash)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(block1, block))
+	blkfileMgrWrapper.close()
+
+	// The hashing algorithm is retrieved from the genesis block at restart
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, block1Hash, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().CurrentBlockHash)
+	block, err = blkfileMgrWrapper.blockfileMgr.retrieveBlockByHash(block1Hash)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(block1, block))
+	block2.Header.PreviousHash = block1Hash
+	assert.NoError(t, blkfileMgrWrapper.blockfileMgr.addBlock(block2))
+}
+
 func TestBlockfileMgrFileRolling(t *testing.T) {
 	blocks := testutil.ConstructTestBlocks(t, 200)
 	size := 0
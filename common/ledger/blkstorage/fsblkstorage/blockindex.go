@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -27,9 +28,13 @@ const (
 	blockNumTranNumIdxKeyPrefix    = 'a'
 	blockTxIDIdxKeyPrefix          = 'b'
 	txValidationResultIdxKeyPrefix = 'v'
+	snapshotTxIDIdxKeyPrefix       = 's'
 	indexCheckpointKeyStr          = "indexCheckpointKey"
 )
 
+// maxTxIDsBatchSize is the number of txids imported from a snapshot per write to the index
+const maxTxIDsBatchSize = 10000
+
 var indexCheckpointKey = []byte(indexCheckpointKeyStr)
 var errIndexEmpty = errors.New("NoBlockIndexed")
 
@@ -43,6 +48,9 @@ type index interface {
 	getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
 	isAttributeIndexed(attribute blkstorage.IndexableAttr) bool
+	importTxIDs(txIDs ledger.ResultsIterator) error
+	isTxIDInSnapshot(txID string) (bool, error)
+	exportTxIDs() (ledger.ResultsIterator, error)
 }
 
 type blockIdxInfo struct {
@@ -199,6 +207,14 @@ func (index *blockIndex) markDuplicateTxids(blockIdxInfo *blockIdxInfo) error {
 		if err != blkstorage.ErrNotFoundInIndex {
 			return err
 		}
+		inSnapshot, err := index.isTxIDInSnapshot(txid)
+		if err != nil {
+			return err
+		}
+		if inSnapshot { // txid is duplicate of a tx of the snapshot the block store was bootstrapped from
+			txIdxInfo.isDuplicate = true
+			continue
+		}
 		uniqueTxids[txid] = true
 	}
 	return nil
@@ -304,6 +320,79 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 	return result, nil
 }
 
+// importTxIDs indexes the txids of the transactions of the snapshot the block store is bootstrapped from.
+// These transactions are not stored by the block store, their txids are only used to detect duplicates.
+// The txids of the transactions already indexed, i.e. of the last block of the snapshot, are skipped
+func (index *blockIndex) importTxIDs(txIDs ledger.ResultsIterator) error {
+	if !index.isAttributeIndexed(blkstorage.IndexableAttrTxID) {
+		return nil
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	for {
+		result, err := txIDs.Next()
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			break
+		}
+		txID := result.(string)
+		b, err := index.db.Get(constructTxIDKey(txID))
+		if err != nil {
+			return err
+		}
+		if b != nil {
+			continue
+		}
+		batch.Put(constructSnapshotTxIDKey(txID), []byte{})
+		if batch.Len() >= maxTxIDsBatchSize {
+			if err := index.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	return index.db.WriteBatch(batch, true)
+}
+
+func (index *blockIndex) isTxIDInSnapshot(txID string) (bool, error) {
+	if !index.isAttributeIndexed(blkstorage.IndexableAttrTxID) {
+		return false, nil
+	}
+	b, err := index.db.Get(constructSnapshotTxIDKey(txID))
+	if err != nil {
+		return false, err
+	}
+	return b != nil, nil
+}
+
+// exportTxIDs returns an iterator over the txids indexed by the block store, and over the txids
+// imported from the snapshot it was bootstrapped from. The iterator reads the index as of the call
+func (index *blockIndex) exportTxIDs() (ledger.ResultsIterator, error) {
+	if !index.isAttributeIndexed(blkstorage.IndexableAttrTxID) {
+		return nil, blkstorage.ErrAttrNotIndexed
+	}
+	// the prefixes of the snapshot txids and of the txids are consecutive,
+	// so that a single iterator, thus a single view of the index, covers both
+	return &txIDsItr{index.db.GetIterator([]byte{snapshotTxIDIdxKeyPrefix}, []byte{txIDIdxKeyPrefix + 1})}, nil
+}
+
+// txIDsItr iterates over the txid keys of the index, returning the txids as strings
+type txIDsItr struct {
+	dbItr *leveldbhelper.Iterator
+}
+
+func (itr *txIDsItr) Next() (ledger.QueryResult, error) {
+	if !itr.dbItr.Next() {
+		return nil, errors.Wrap(itr.dbItr.Error(), "error while iterating over the txid index")
+	}
+	return string(itr.dbItr.Key()[1:]), nil
+}
+
+func (itr *txIDsItr) Close() {
+	itr.dbItr.Release()
+}
+
 func constructBlockNumKey(blockNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	return append([]byte{blockNumIdxKeyPrefix}, blkNumBytes...)
@@ -317,6 +406,10 @@ func constructTxIDKey(txID string) []byte {
 	return append([]byte{txIDIdxKeyPrefix}, []byte(txID)...)
 }
 
+func constructSnapshotTxIDKey(txID string) []byte {
+	return append([]byte{snapshotTxIDIdxKeyPrefix}, []byte(txID)...)
+}
+
 func constructBlockTxIDKey(txID string) []byte {
 	return append([]byte{blockTxIDIdxKeyPrefix}, []byte(txID)...)
 }
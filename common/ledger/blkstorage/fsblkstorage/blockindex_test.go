@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
@@ -64,6 +65,18 @@ func (i *noopIndex) isAttributeIndexed(attribute blkstorage.IndexableAttr) bool
 	return true
 }
 
+func (i *noopIndex) importTxIDs(txIDs ledger.ResultsIterator) error {
+	return nil
+}
+
+func (i *noopIndex) isTxIDInSnapshot(txID string) (bool, error) {
+	return false, nil
+}
+
+func (i *noopIndex) exportTxIDs() (ledger.ResultsIterator, error) {
+	return nil, nil
+}
+
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
 	testBlockIndexSync(t, 10, 5, true)
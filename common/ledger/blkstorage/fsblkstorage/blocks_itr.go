@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 )
 
 // blocksItr - an iterator for iterating over a sequence of blocks
@@ -78,6 +79,19 @@ func (itr *blocksItr) Next() (ledger.QueryResult, error) {
 	if itr.closeMarker {
 		return nil, nil
 	}
+	if firstBlockNum := itr.mgr.getPruningInfo().firstBlockNum; itr.stream == nil && itr.blockNumToRetrieve < firstBlockNum {
+		// the genesis block is kept once its block file is pruned,
+		// unlike the blocks following it up to the first block which was not pruned
+		if itr.blockNumToRetrieve != 0 {
+			return nil, &blkstorage.BlockPrunedError{FirstBlockNum: firstBlockNum}
+		}
+		genesisBlock, err := itr.mgr.retrievePrunedBlock(0)
+		if err != nil {
+			return nil, err
+		}
+		itr.blockNumToRetrieve++
+		return genesisBlock, nil
+	}
 	if itr.stream == nil {
 		logger.Debugf("Initializing block stream for iterator. itr.maxBlockNumAvailable=%d", itr.maxBlockNumAvailable)
 		if err := itr.initStream(); err != nil {
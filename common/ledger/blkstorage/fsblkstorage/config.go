@@ -30,15 +30,22 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	retention        *RetentionPolicy
 }
 
 // NewConf constructs new `Conf`.
 // blockStorageDir is the top level folder under which `FsBlockStore` manages its data
 func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
+	return NewConfWithRetention(blockStorageDir, maxBlockfileSize, nil)
+}
+
+// NewConfWithRetention constructs new `Conf` pruning the block files according to
+// the given retention policy. A nil retention policy keeps all the block files.
+func NewConfWithRetention(blockStorageDir string, maxBlockfileSize int, retention *RetentionPolicy) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir, maxBlockfileSize, retention}
 }
 
 func (conf *Conf) getIndexDir() string {
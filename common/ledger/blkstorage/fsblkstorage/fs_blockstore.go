@@ -102,6 +102,11 @@ func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
 }
 
+// ExportTxIDs returns an iterator over the txID of each transaction of the block store
+func (store *fsBlockStore) ExportTxIDs() (ledger.ResultsIterator, error) {
+	return store.fileMgr.index.exportTxIDs()
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
@@ -17,10 +17,15 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"os"
+
+	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
 )
 
 // FsBlockstoreProvider provides handle to block storage - this is not thread-safe
@@ -52,6 +57,19 @@ func (p *FsBlockstoreProvider) OpenBlockStore(ledgerid string) (blkstorage.Block
 	return newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle, p.stats), nil
 }
 
+// BootstrapFromSnapshot creates a block store for given ledgerid, starting at lastBlock,
+// the last block of a snapshot. The block store of ledgerid is expected to be empty
+func (p *FsBlockstoreProvider) BootstrapFromSnapshot(ledgerid string, lastBlock, lastConfigBlock *common.Block, txIDs ledger.ResultsIterator) (blkstorage.BlockStore, error) {
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
+	store := newFsBlockStore(ledgerid, p.conf, p.indexConfig, indexStoreHandle, p.stats)
+	if err := store.fileMgr.bootstrapFromSnapshot(lastBlock, lastConfigBlock, txIDs); err != nil {
+		store.Shutdown()
+		return nil, errors.WithMessagef(err, "error bootstrapping the block store of ledger [%s]", ledgerid)
+	}
+	store.stats.updateBlockchainHeight(lastBlock.Header.Number + 1)
+	return store, nil
+}
+
 // Exists tells whether the BlockStore with given id exists
 func (p *FsBlockstoreProvider) Exists(ledgerid string) (bool, error) {
 	exists, _, err := util.FileExists(p.conf.getLedgerBlockDir(ledgerid))
@@ -63,6 +81,26 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// Remove removes the block files and the index of the BlockStore with given id.
+// The BlockStore needs to be shut down before it is removed.
+func (p *FsBlockstoreProvider) Remove(ledgerid string) error {
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
+	batch := leveldbhelper.NewUpdateBatch()
+	itr := indexStoreHandle.GetIterator(nil, nil)
+	for itr.Next() {
+		batch.Delete(itr.Key())
+	}
+	err := itr.Error()
+	itr.Release()
+	if err != nil {
+		return errors.Wrapf(err, "error while iterating over the index of ledger [%s]", ledgerid)
+	}
+	if err := indexStoreHandle.WriteBatch(batch, true); err != nil {
+		return errors.Wrapf(err, "error while removing the index of ledger [%s]", ledgerid)
+	}
+	return errors.Wrapf(os.RemoveAll(p.conf.getLedgerBlockDir(ledgerid)), "error while removing the block files of ledger [%s]", ledgerid)
+}
+
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
 	p.leveldbProvider.Close()
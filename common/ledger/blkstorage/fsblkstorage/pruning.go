@@ -0,0 +1,351 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var (
+	blkMgrPruningInfoKey  = []byte("blkMgrPruningInfo")
+	blkMgrGenesisBlockKey = []byte("blkMgrGenesisBlock")
+)
+
+// RetentionPolicy defines which block files are kept by the block storage.
+// A block file is pruned once all of its blocks are older than both MaxBlocks
+// and MaxAge. The block file holding the last config block is never pruned,
+// and the genesis block is kept in the database once its block file is pruned.
+type RetentionPolicy struct {
+	// MaxBlocks is the number of the most recent blocks which are kept,
+	// 0 keeps the blocks regardless of their number.
+	MaxBlocks uint64
+	// MaxAge is the duration after the last write to a block file during
+	// which it is kept, 0 keeps the block files regardless of their age.
+	MaxAge time.Duration
+	// Archiver stores the block files before they are pruned,
+	// the block files are simply removed when it is nil.
+	Archiver Archiver
+	// Exempt reports whether the block files of the given ledger are never
+	// pruned, e.g. those of the orderer system channel which are replicated
+	// from the genesis block. All the ledgers are pruned when it is nil.
+	Exempt func(ledgerID string) bool
+}
+
+func (p *RetentionPolicy) enabled(ledgerID string) bool {
+	if p == nil || (p.MaxBlocks == 0 && p.MaxAge <= 0) {
+		return false
+	}
+	return p.Exempt == nil || !p.Exempt(ledgerID)
+}
+
+// Archiver stores the block files pruned from the block storage, e.g. in an
+// archive directory or an object store.
+type Archiver interface {
+	// Archive stores a copy of the block file at path of the given ledger.
+	// The block file is removed from the block storage once Archive returns
+	// successfully.
+	Archive(ledgerID string, path string) error
+}
+
+// DirArchiver archives the block files in a directory, under a sub-directory per ledger
+type DirArchiver struct {
+	dir string
+}
+
+// NewDirArchiver constructs a new `DirArchiver` archiving the block files in dir
+func NewDirArchiver(dir string) *DirArchiver {
+	return &DirArchiver{dir: dir}
+}
+
+// Archive implements method in interface `fsblkstorage.Archiver`
+func (a *DirArchiver) Archive(ledgerID string, path string) error {
+	ledgerDir := filepath.Join(a.dir, ledgerID)
+	if _, err := util.CreateDirIfMissing(ledgerDir); err != nil {
+		return errors.WithMessagef(err, "error creating archive dir [%s]", ledgerDir)
+	}
+	archivePath := filepath.Join(ledgerDir, filepath.Base(path))
+	// a previous attempt may have archived the file before it was removed
+	if err := os.Remove(archivePath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing archived file [%s]", archivePath)
+	}
+	// hard linking avoids copying the file when the archive dir is on the same file system
+	if err := os.Link(path, archivePath); err == nil {
+		return nil
+	}
+	return copyFile(path, archivePath)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "error opening file [%s]", src)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file [%s]", dst)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "error copying file [%s] to [%s]", src, dst)
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return errors.Wrapf(err, "error syncing file [%s]", dst)
+	}
+	return out.Close()
+}
+
+// pruningInfo tracks the first block file, and the first block, which were not pruned
+type pruningInfo struct {
+	firstFileNum  int
+	firstBlockNum uint64
+}
+
+func (i *pruningInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	if err := buffer.EncodeVarint(uint64(i.firstFileNum)); err != nil {
+		return nil, errors.Wrapf(err, "error encoding the firstFileNum [%d]", i.firstFileNum)
+	}
+	if err := buffer.EncodeVarint(i.firstBlockNum); err != nil {
+		return nil, errors.Wrapf(err, "error encoding the firstBlockNum [%d]", i.firstBlockNum)
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruningInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	val, err := buffer.DecodeVarint()
+	if err != nil {
+		return err
+	}
+	i.firstFileNum = int(val)
+	if i.firstBlockNum, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *pruningInfo) String() string {
+	return fmt.Sprintf("firstFileNum=[%d], firstBlockNum=[%d]", i.firstFileNum, i.firstBlockNum)
+}
+
+// loadPruningInfo returns the pruning info stored in the database,
+// nothing was pruned if there is none
+func (mgr *blockfileMgr) loadPruningInfo() (*pruningInfo, error) {
+	b, err := mgr.db.Get(blkMgrPruningInfoKey)
+	if err != nil {
+		return nil, err
+	}
+	i := &pruningInfo{}
+	if b == nil {
+		return i, nil
+	}
+	if err = i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	logger.Debugf("loaded pruningInfo:%s", i)
+	return i, nil
+}
+
+func (mgr *blockfileMgr) savePruningInfo(i *pruningInfo) error {
+	b, err := i.marshal()
+	if err != nil {
+		return err
+	}
+	if err = mgr.db.Put(blkMgrPruningInfoKey, b, true); err != nil {
+		return err
+	}
+	mgr.pruningInfo.Store(i)
+	return nil
+}
+
+func (mgr *blockfileMgr) getPruningInfo() *pruningInfo {
+	return mgr.pruningInfo.Load().(*pruningInfo)
+}
+
+// checkNotPruned returns a `blkstorage.BlockPrunedError` if the block file
+// numbered fileNum was pruned
+func (mgr *blockfileMgr) checkNotPruned(fileNum int) error {
+	if i := mgr.getPruningInfo(); fileNum < i.firstFileNum {
+		return &blkstorage.BlockPrunedError{FirstBlockNum: i.firstBlockNum}
+	}
+	return nil
+}
+
+// prune prunes the block files according to the retention policy, given the
+// last block added to the block storage
+func (mgr *blockfileMgr) prune(lastBlock *common.Block) error {
+	policy := mgr.conf.retention
+	if !policy.enabled(mgr.ledgerID) {
+		return nil
+	}
+
+	// the last config block is always kept
+	keepFromBlock, err := putil.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return errors.WithMessage(err, "error retrieving the last config index")
+	}
+	if policy.MaxBlocks > 0 {
+		var n uint64
+		if height := lastBlock.Header.Number + 1; height > policy.MaxBlocks {
+			n = height - policy.MaxBlocks
+		}
+		if n < keepFromBlock {
+			keepFromBlock = n
+		}
+	}
+	flp, err := mgr.index.getBlockLocByBlockNum(keepFromBlock)
+	if err != nil {
+		return errors.WithMessagef(err, "error retrieving the location of block [%d]", keepFromBlock)
+	}
+
+	info := mgr.getPruningInfo()
+	pruneToFile := info.firstFileNum
+	for ; pruneToFile < flp.fileSuffixNum; pruneToFile++ {
+		if policy.MaxAge <= 0 {
+			continue
+		}
+		fileInfo, err := os.Stat(deriveBlockfilePath(mgr.rootDir, pruneToFile))
+		if err != nil {
+			return errors.Wrapf(err, "error retrieving file info for file number %d", pruneToFile)
+		}
+		if time.Since(fileInfo.ModTime()) < policy.MaxAge {
+			break
+		}
+	}
+	if pruneToFile == info.firstFileNum {
+		return nil
+	}
+
+	firstBlockNum, err := retriveFirstBlockNumFromFile(mgr.rootDir, pruneToFile)
+	if err != nil {
+		return err
+	}
+	// the genesis block is kept before its block file is pruned, unless the
+	// block storage was bootstrapped from a snapshot, which doesn't include it
+	if info.firstFileNum == 0 && info.firstBlockNum == 0 {
+		if err = mgr.saveGenesisBlock(); err != nil {
+			return err
+		}
+	}
+	// the pruning info is saved before the block files are archived, and the
+	// block files left over by a crash are archived when the manager restarts
+	newInfo := &pruningInfo{firstFileNum: pruneToFile, firstBlockNum: firstBlockNum}
+	if err = mgr.savePruningInfo(newInfo); err != nil {
+		return errors.WithMessage(err, "error saving pruning info to db")
+	}
+	logger.Infof("Pruning block files of ledger [%s] up to block [%d]", mgr.ledgerID, firstBlockNum)
+	return mgr.archivePrunedFiles()
+}
+
+func (mgr *blockfileMgr) saveGenesisBlock() error {
+	flp, err := mgr.index.getBlockLocByBlockNum(0)
+	if err != nil {
+		return errors.WithMessage(err, "error retrieving the location of the genesis block")
+	}
+	genesisBlock, err := mgr.fetchBlock(flp)
+	if err != nil {
+		return errors.WithMessage(err, "error retrieving the genesis block")
+	}
+	b, err := proto.Marshal(genesisBlock)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the genesis block")
+	}
+	if err = mgr.db.Put(blkMgrGenesisBlockKey, b, true); err != nil {
+		return errors.WithMessage(err, "error saving the genesis block to db")
+	}
+	return nil
+}
+
+// isGenesisBlockKept returns true if the genesis block was kept in the
+// database when its block file was pruned
+func (mgr *blockfileMgr) isGenesisBlockKept() (bool, error) {
+	b, err := mgr.db.Get(blkMgrGenesisBlockKey)
+	if err != nil {
+		return false, err
+	}
+	return b != nil, nil
+}
+
+// retrievePrunedBlock returns the pruned block numbered blockNum if it was kept in the database,
+// i.e. the genesis block, or the last config block of the snapshot the block storage was
+// bootstrapped from. It returns a `blkstorage.BlockPrunedError` otherwise
+func (mgr *blockfileMgr) retrievePrunedBlock(blockNum uint64) (*common.Block, error) {
+	if blockNum == 0 {
+		b, err := mgr.db.Get(blkMgrGenesisBlockKey)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			block := &common.Block{}
+			if err := proto.Unmarshal(b, block); err != nil {
+				return nil, errors.Wrap(err, "error unmarshaling the genesis block")
+			}
+			return block, nil
+		}
+	}
+	return mgr.retrieveSnapshotConfigBlock(blockNum)
+}
+
+// archivePrunedFiles archives the block files before the first file which
+// was not pruned that are still present in the block storage
+func (mgr *blockfileMgr) archivePrunedFiles() error {
+	firstFileNum := mgr.getPruningInfo().firstFileNum
+	if firstFileNum == 0 {
+		return nil
+	}
+	filesInfo, err := ioutil.ReadDir(mgr.rootDir)
+	if err != nil {
+		return errors.Wrapf(err, "error reading dir %s", mgr.rootDir)
+	}
+	var fileNums []int
+	for _, fileInfo := range filesInfo {
+		name := fileInfo.Name()
+		if fileInfo.IsDir() || !isBlockFileName(name) {
+			continue
+		}
+		fileNum, err := strconv.Atoi(strings.TrimPrefix(name, blockfilePrefix))
+		if err != nil {
+			return err
+		}
+		if fileNum < firstFileNum {
+			fileNums = append(fileNums, fileNum)
+		}
+	}
+	sort.Ints(fileNums)
+
+	archiver := mgr.conf.retention.Archiver
+	for _, fileNum := range fileNums {
+		filePath := deriveBlockfilePath(mgr.rootDir, fileNum)
+		if archiver != nil {
+			if err := archiver.Archive(mgr.ledgerID, filePath); err != nil {
+				return errors.WithMessagef(err, "error archiving block file [%s]", filePath)
+			}
+		}
+		if err := os.Remove(filePath); err != nil {
+			return errors.Wrapf(err, "error removing block file [%s]", filePath)
+		}
+		logger.Debugf("Pruned block file [%s]", filePath)
+	}
+	return nil
+}
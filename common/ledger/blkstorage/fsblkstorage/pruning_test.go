@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// constructPruningTestBlocks returns blocks whose last config block is
+// lastConfig, or themselves for the blocks before lastConfig, and the max
+// block file size storing one of them per block file
+func constructPruningTestBlocks(t *testing.T, numBlocks int, lastConfig uint64) ([]*common.Block, int) {
+	blocks := testutil.ConstructTestBlocks(t, numBlocks)
+	for _, block := range blocks {
+		index := block.Header.Number
+		if index > lastConfig {
+			index = lastConfig
+		}
+		block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = putil.MarshalOrPanic(&common.Metadata{
+			Value: putil.MarshalOrPanic(&common.LastConfig{Index: index}),
+		})
+	}
+	blockBytes, _, err := serializeBlock(blocks[1])
+	require.NoError(t, err)
+	return blocks, len(blockBytes) + 8
+}
+
+func TestBlockfileMgrPruneMaxBlocks(t *testing.T) {
+	archiveDir, err := ioutil.TempDir("", "fsblkstorage-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(archiveDir)
+
+	blocks, maxFileSize := constructPruningTestBlocks(t, 20, math.MaxUint64)
+	retention := &RetentionPolicy{MaxBlocks: 5, Archiver: NewDirArchiver(archiveDir)}
+	env := newTestEnv(t, NewConfWithRetention(testPath(), maxFileSize, retention))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks)
+	mgr := blkfileMgrWrapper.blockfileMgr
+
+	// the last 5 blocks are kept, along with the blocks sharing their files
+	pi := mgr.getPruningInfo()
+	assert.True(t, pi.firstFileNum > 0)
+	assert.True(t, pi.firstBlockNum > 0 && pi.firstBlockNum <= 15)
+	expectedErr := &blkstorage.BlockPrunedError{FirstBlockNum: pi.firstBlockNum}
+
+	_, err = mgr.retrieveBlockByNumber(1)
+	assert.Equal(t, expectedErr, err)
+	_, err = mgr.retrieveBlockByHash(blocks[1].Header.Hash())
+	assert.Equal(t, expectedErr, err)
+	_, err = mgr.retrieveBlocks(pi.firstBlockNum - 1)
+	assert.Equal(t, expectedErr, err)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[pi.firstBlockNum:], pi.firstBlockNum, nil)
+	testBlockfileMgrBlockIterator(t, mgr, int(pi.firstBlockNum), 19, blocks[pi.firstBlockNum:])
+
+	// the genesis block is kept, the iterators starting from it fail on the pruned blocks
+	genesisBlock, err := mgr.retrieveBlockByNumber(0)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[0], genesisBlock)
+	itr, err := mgr.retrieveBlocks(0)
+	require.NoError(t, err)
+	block, err := itr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[0], block)
+	_, err = itr.Next()
+	assert.Equal(t, expectedErr, err)
+	itr.Close()
+
+	// the pruned block files are moved to the archive dir
+	for fileNum := 0; fileNum < pi.firstFileNum; fileNum++ {
+		_, err := os.Stat(deriveBlockfilePath(mgr.rootDir, fileNum))
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(filepath.Join(archiveDir, ledgerid, filepath.Base(deriveBlockfilePath(mgr.rootDir, fileNum))))
+		assert.NoError(t, err)
+	}
+	blkfileMgrWrapper.close()
+
+	// the pruning info and the hashing algorithm are retrieved on restart
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, pi, blkfileMgrWrapper.blockfileMgr.getPruningInfo())
+	assert.Equal(t, uint64(20), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+	assert.Equal(t, blocks[19].Header.Hash(), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().CurrentBlockHash)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[pi.firstBlockNum:], pi.firstBlockNum, nil)
+	genesisBlock, err = blkfileMgrWrapper.blockfileMgr.retrieveBlockByNumber(0)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[0], genesisBlock)
+}
+
+func TestBlockfileMgrPruneExemptLedger(t *testing.T) {
+	blocks, maxFileSize := constructPruningTestBlocks(t, 20, math.MaxUint64)
+	retention := &RetentionPolicy{
+		MaxBlocks: 5,
+		Exempt:    func(ledgerID string) bool { return ledgerID == "systemLedger" },
+	}
+	env := newTestEnv(t, NewConfWithRetention(testPath(), maxFileSize, retention))
+	defer env.Cleanup()
+
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "systemLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgrWrapper.close()
+
+	// the exempt ledger is not pruned, even when the block storage restarts
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "systemLedger")
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, &pruningInfo{}, blkfileMgrWrapper.blockfileMgr.getPruningInfo())
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0, nil)
+	testBlockfileMgrBlockIterator(t, blkfileMgrWrapper.blockfileMgr, 0, 19, blocks)
+
+	appLedgerWrapper := newTestBlockfileWrapper(env, "appLedger")
+	defer appLedgerWrapper.close()
+	appLedgerWrapper.addBlocks(blocks)
+	assert.True(t, appLedgerWrapper.blockfileMgr.getPruningInfo().firstFileNum > 0)
+}
+
+func TestBlockfileMgrPruneKeepsLastConfigBlock(t *testing.T) {
+	blocks, maxFileSize := constructPruningTestBlocks(t, 20, 8)
+	env := newTestEnv(t, NewConfWithRetention(testPath(), maxFileSize, &RetentionPolicy{MaxBlocks: 2}))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	pi := blkfileMgrWrapper.blockfileMgr.getPruningInfo()
+	assert.True(t, pi.firstBlockNum > 0 && pi.firstBlockNum <= 8)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[8:], 8, nil)
+}
+
+func TestBlockfileMgrPruneMaxAge(t *testing.T) {
+	blocks, maxFileSize := constructPruningTestBlocks(t, 10, math.MaxUint64)
+	conf := NewConfWithRetention(testPath(), maxFileSize, &RetentionPolicy{MaxAge: time.Hour})
+	env := newTestEnv(t, conf)
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	// the block files were just written
+	assert.Equal(t, &pruningInfo{}, blkfileMgrWrapper.blockfileMgr.getPruningInfo())
+	rootDir := blkfileMgrWrapper.blockfileMgr.rootDir
+	lastFileNum := blkfileMgrWrapper.blockfileMgr.cpInfo.latestFileChunkSuffixNum
+	blkfileMgrWrapper.close()
+
+	// the block files older than MaxAge are pruned on restart
+	old := time.Now().Add(-2 * time.Hour)
+	for fileNum := 0; fileNum < lastFileNum-2; fileNum++ {
+		require.NoError(t, os.Chtimes(deriveBlockfilePath(rootDir, fileNum), old, old))
+	}
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	pi := blkfileMgrWrapper.blockfileMgr.getPruningInfo()
+	assert.Equal(t, lastFileNum-2, pi.firstFileNum)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[pi.firstBlockNum:], pi.firstBlockNum, nil)
+}
+
+func TestBlockfileMgrArchivePrunedFilesLeftOver(t *testing.T) {
+	blocks, maxFileSize := constructPruningTestBlocks(t, 10, 0)
+	env := newTestEnv(t, NewConfWithRetention(testPath(), maxFileSize, &RetentionPolicy{MaxBlocks: 100}))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks)
+	mgr := blkfileMgrWrapper.blockfileMgr
+	rootDir := mgr.rootDir
+
+	// simulate a crash after saving the pruning info
+	firstBlockNum, err := retriveFirstBlockNumFromFile(rootDir, 2)
+	require.NoError(t, err)
+	require.NoError(t, mgr.savePruningInfo(&pruningInfo{firstFileNum: 2, firstBlockNum: firstBlockNum}))
+	blkfileMgrWrapper.close()
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	for fileNum := 0; fileNum < 2; fileNum++ {
+		_, err := os.Stat(deriveBlockfilePath(rootDir, fileNum))
+		assert.True(t, os.IsNotExist(err))
+	}
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[firstBlockNum:], firstBlockNum, nil)
+}
+
+func TestPruningInfoSerialization(t *testing.T) {
+	info := &pruningInfo{firstFileNum: 5, firstBlockNum: 1234}
+	b, err := info.marshal()
+	assert.NoError(t, err)
+	infoCopy := &pruningInfo{}
+	assert.NoError(t, infoCopy.unmarshal(b))
+	assert.Equal(t, info, infoCopy)
+}
@@ -21,6 +21,8 @@ type rollbackMgr struct {
 	dbProvider     *leveldbhelper.Provider
 	indexStore     *blockIndex
 	targetBlockNum uint64
+	// hashingAlgorithm hashes the blocks of the chain
+	hashingAlgorithm func([]byte) []byte
 }
 
 // Rollback reverts changes made to the block store beyond a given block number.
@@ -61,6 +63,10 @@ func newRollbackMgr(blockStorageDir, ledgerID string, indexConfig *blkstorage.In
 	var err error
 	indexDB := r.dbProvider.GetDBHandle(ledgerID)
 	r.indexStore, err = newBlockIndex(indexConfig, indexDB)
+	if err != nil {
+		return nil, err
+	}
+	r.hashingAlgorithm, err = retrieveBlockHashingAlgorithm(r.ledgerDir)
 	return r, err
 }
 
@@ -128,7 +134,7 @@ func (r *rollbackMgr) deleteIndexEntriesRange(startBlkNum, endBlkNum uint64) err
 		if err != nil {
 			return err
 		}
-		addIndexEntriesToBeDeleted(batch, blockInfo, r.indexStore)
+		addIndexEntriesToBeDeleted(batch, blockInfo, r.indexStore, r.hashingAlgorithm)
 		numberOfBlocksToRetrieve--
 	}
 
@@ -163,9 +169,9 @@ func populateBlockInfoWithDuplicateTxids(blockInfo *serializedBlockInfo, placeme
 	return nil
 }
 
-func addIndexEntriesToBeDeleted(batch *leveldbhelper.UpdateBatch, blockInfo *serializedBlockInfo, indexStore *blockIndex) error {
+func addIndexEntriesToBeDeleted(batch *leveldbhelper.UpdateBatch, blockInfo *serializedBlockInfo, indexStore *blockIndex, hashingAlgorithm func([]byte) []byte) error {
 	if indexStore.isAttributeIndexed(blkstorage.IndexableAttrBlockHash) {
-		batch.Delete(constructBlockHashKey(blockInfo.blockHeader.Hash()))
+		batch.Delete(constructBlockHashKey(hashingAlgorithm(blockInfo.blockHeader.Bytes())))
 	}
 
 	if indexStore.isAttributeIndexed(blkstorage.IndexableAttrBlockNum) {
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	commonutil "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var (
+	blkMgrSnapshotConfigBlockKey = []byte("blkMgrSnapshotConfigBlock")
+)
+
+// bootstrapFromSnapshot adds lastBlock, the last block of a snapshot, as the first block of the empty block
+// storage. The blocks before lastBlock are treated as pruned, except lastConfigBlock which is kept in the
+// database, and the txids of the transactions of the snapshot are indexed to detect duplicates
+func (mgr *blockfileMgr) bootstrapFromSnapshot(lastBlock, lastConfigBlock *common.Block, txIDs ledger.ResultsIterator) error {
+	if !mgr.cpInfo.isChainEmpty {
+		return errors.Errorf("block storage of ledger [%s] is not empty, it cannot be bootstrapped from a snapshot", mgr.ledgerID)
+	}
+	lastBlockNum := lastBlock.Header.Number
+	if lastConfigBlock.Header.Number > lastBlockNum {
+		return errors.Errorf("last config block [%d] is after the last block [%d] of the snapshot",
+			lastConfigBlock.Header.Number, lastBlockNum)
+	}
+
+	// The hashing algorithm configured by the genesis block is retrieved from the config
+	// carried by the last config block, as the genesis block is not part of the snapshot
+	algorithm := blockHashingAlgorithm(lastConfigBlock)
+	if err := mgr.saveHashingAlgorithm(algorithm); err != nil {
+		return errors.WithMessage(err, "error saving block hashing algorithm to db")
+	}
+	mgr.hashingAlgorithm = commonutil.BlockHashingFunction(algorithm)
+
+	if lastConfigBlock.Header.Number < lastBlockNum {
+		configBlockBytes, err := proto.Marshal(lastConfigBlock)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling the last config block of the snapshot")
+		}
+		if err := mgr.db.Put(blkMgrSnapshotConfigBlockKey, configBlockBytes, true); err != nil {
+			return errors.WithMessage(err, "error saving the last config block of the snapshot to db")
+		}
+	}
+	if err := mgr.savePruningInfo(&pruningInfo{firstFileNum: 0, firstBlockNum: lastBlockNum}); err != nil {
+		return errors.WithMessage(err, "error saving pruning info to db")
+	}
+
+	// the block storage continues the chain of the snapshot
+	mgr.bcInfo.Store(&common.BlockchainInfo{
+		Height:           lastBlockNum,
+		CurrentBlockHash: lastBlock.Header.PreviousHash,
+	})
+	if err := mgr.addBlock(lastBlock); err != nil {
+		return errors.WithMessagef(err, "error adding the last block [%d] of the snapshot", lastBlockNum)
+	}
+	// the txids are imported once the last block is indexed, as the txids of its
+	// transactions, which are part of the snapshot, are not duplicates
+	if err := mgr.index.importTxIDs(txIDs); err != nil {
+		return errors.WithMessage(err, "error importing the txids of the snapshot")
+	}
+	logger.Infof("Bootstrapped block storage of ledger [%s] from a snapshot at block [%d]", mgr.ledgerID, lastBlockNum)
+	return nil
+}
+
+// retrieveSnapshotConfigBlock returns the last config block of the snapshot the block storage was
+// bootstrapped from, if it is numbered blockNum. It returns a `blkstorage.BlockPrunedError` otherwise
+func (mgr *blockfileMgr) retrieveSnapshotConfigBlock(blockNum uint64) (*common.Block, error) {
+	prunedErr := &blkstorage.BlockPrunedError{FirstBlockNum: mgr.getPruningInfo().firstBlockNum}
+	b, err := mgr.db.Get(blkMgrSnapshotConfigBlockKey)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, prunedErr
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(b, block); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the last config block of the snapshot")
+	}
+	if block.Header.Number != blockNum {
+		return nil, prunedErr
+	}
+	return block, nil
+}
+
+// checkTxIDNotInSnapshot returns a `blkstorage.BlockPrunedError` if the transaction with the given txID,
+// which is not found in the index, is a transaction of the snapshot the block storage was bootstrapped from
+func (mgr *blockfileMgr) checkTxIDNotInSnapshot(txID string) error {
+	inSnapshot, err := mgr.index.isTxIDInSnapshot(txID)
+	if err != nil {
+		return err
+	}
+	if inSnapshot {
+		return &blkstorage.BlockPrunedError{FirstBlockNum: mgr.getPruningInfo().firstBlockNum}
+	}
+	return blkstorage.ErrNotFoundInIndex
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func txIDsOfBlocks(t *testing.T, blocks []*common.Block) []string {
+	var txIDs []string
+	for _, block := range blocks {
+		_, info, err := serializeBlock(block)
+		require.NoError(t, err)
+		for _, txOffset := range info.txOffsets {
+			txIDs = append(txIDs, txOffset.txID)
+		}
+	}
+	return txIDs
+}
+
+type txIDsSliceItr []string
+
+func (itr *txIDsSliceItr) Next() (ledger.QueryResult, error) {
+	if len(*itr) == 0 {
+		return nil, nil
+	}
+	txID := (*itr)[0]
+	*itr = (*itr)[1:]
+	return txID, nil
+}
+
+func (itr *txIDsSliceItr) Close() {}
+
+// snapshotTxIDsItr returns the given txids as the iterator consumed by BootstrapFromSnapshot
+func snapshotTxIDsItr(txIDs []string) ledger.ResultsIterator {
+	itr := txIDsSliceItr(txIDs)
+	return &itr
+}
+
+func exportedTxIDs(t *testing.T, itr ledger.ResultsIterator) []string {
+	defer itr.Close()
+	var txIDs []string
+	for {
+		result, err := itr.Next()
+		require.NoError(t, err)
+		if result == nil {
+			break
+		}
+		txIDs = append(txIDs, result.(string))
+	}
+	sort.Strings(txIDs)
+	return txIDs
+}
+
+func TestBootstrapFromSnapshot(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 12)
+	lastBlock, lastConfigBlock := blocks[9], blocks[5]
+	snapshotTxIDs := txIDsOfBlocks(t, blocks[:10])
+
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	store, err := env.provider.BootstrapFromSnapshot("testLedger", lastBlock, lastConfigBlock, snapshotTxIDsItr(snapshotTxIDs))
+	require.NoError(t, err)
+	defer store.Shutdown()
+	mgr := store.(*fsBlockStore).fileMgr
+
+	bcInfo, err := store.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), bcInfo.Height)
+	assert.Equal(t, lastBlock.Header.Hash(), bcInfo.CurrentBlockHash)
+	assert.Equal(t, lastBlock.Header.PreviousHash, bcInfo.PreviousBlockHash)
+
+	// the blocks before the last block of the snapshot are pruned, except the last config block
+	expectedErr := &blkstorage.BlockPrunedError{FirstBlockNum: 9}
+	block, err := store.RetrieveBlockByNumber(5)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(lastConfigBlock, block))
+	_, err = store.RetrieveBlockByNumber(3)
+	assert.Equal(t, expectedErr, err)
+	block, err = store.RetrieveBlockByNumber(9)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(lastBlock, block))
+
+	// the transactions of the snapshot are known but pruned, except those of the last block
+	_, err = store.RetrieveTxByID(snapshotTxIDs[0])
+	assert.Equal(t, expectedErr, err)
+	_, err = store.RetrieveBlockByTxID(snapshotTxIDs[0])
+	assert.Equal(t, expectedErr, err)
+	txIDsOfLastBlock := txIDsOfBlocks(t, blocks[9:10])
+	block, err = store.RetrieveBlockByTxID(txIDsOfLastBlock[0])
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(lastBlock, block))
+	_, err = store.RetrieveTxByID("unknown-txid")
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+
+	// the blocks after the snapshot are added, and a transaction duplicating a
+	// transaction of the snapshot does not replace it in the index. The txids
+	// exported beforehand do not include the txids of the blocks added since
+	itr, err := store.ExportTxIDs()
+	require.NoError(t, err)
+	testutil.SetTxID(t, blocks[10], 0, snapshotTxIDs[0])
+	require.NoError(t, mgr.addBlock(blocks[10]))
+	require.NoError(t, mgr.addBlock(blocks[11]))
+	expectedTxIDs := append([]string{}, snapshotTxIDs...)
+	sort.Strings(expectedTxIDs)
+	assert.Equal(t, expectedTxIDs, exportedTxIDs(t, itr))
+	_, err = store.RetrieveTxByID(snapshotTxIDs[0])
+	assert.Equal(t, expectedErr, err)
+	block, err = store.RetrieveBlockByNumber(11)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[11], block))
+
+	// the txids of the snapshot are exported along with those of the blocks after the snapshot
+	expectedTxIDs = append(append([]string{}, snapshotTxIDs...), txIDsOfBlocks(t, blocks[10:])[1:]...)
+	sort.Strings(expectedTxIDs)
+	itr, err = store.ExportTxIDs()
+	require.NoError(t, err)
+	assert.Equal(t, expectedTxIDs, exportedTxIDs(t, itr))
+	store.Shutdown()
+
+	// the block store bootstrapped from the snapshot is reopened
+	store, err = env.provider.OpenBlockStore("testLedger")
+	require.NoError(t, err)
+	bcInfo, err = store.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), bcInfo.Height)
+	block, err = store.RetrieveBlockByNumber(5)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(lastConfigBlock, block))
+	_, err = store.RetrieveTxByID(snapshotTxIDs[1])
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestBootstrapFromSnapshotErrors(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 10)
+
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	_, err := env.provider.BootstrapFromSnapshot("testLedger", blocks[5], blocks[6], nil)
+	assert.EqualError(t, err, "error bootstrapping the block store of ledger [testLedger]: last config block [6] is after the last block [5] of the snapshot")
+
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "nonEmptyLedger")
+	blkfileMgrWrapper.addBlocks(blocks[:2])
+	blkfileMgrWrapper.close()
+	_, err = env.provider.BootstrapFromSnapshot("nonEmptyLedger", blocks[5], blocks[5], nil)
+	assert.EqualError(t, err, "error bootstrapping the block store of ledger [nonEmptyLedger]: block storage of ledger [nonEmptyLedger] is not empty, it cannot be bootstrapped from a snapshot")
+}
@@ -61,6 +61,18 @@ func (flf *fileLedgerFactory) ChainIDs() []string {
 	return chainIDs
 }
 
+// Remove shuts down the ledger of the given chain ID and removes its blocks
+func (flf *fileLedgerFactory) Remove(chainID string) error {
+	flf.mutex.Lock()
+	defer flf.mutex.Unlock()
+
+	if ledger, ok := flf.ledgers[chainID]; ok {
+		ledger.(*FileLedger).blockStore.Shutdown()
+		delete(flf.ledgers, chainID)
+	}
+	return flf.blkstorageProvider.Remove(chainID)
+}
+
 // Close releases all resources acquired by the factory
 func (flf *fileLedgerFactory) Close() {
 	flf.blkstorageProvider.Close()
@@ -68,9 +80,15 @@ func (flf *fileLedgerFactory) Close() {
 
 // New creates a new ledger factory
 func New(directory string, metricsProvider metrics.Provider) blockledger.Factory {
+	return NewWithRetention(directory, nil, metricsProvider)
+}
+
+// NewWithRetention creates a new ledger factory whose ledgers prune their
+// block files according to the given retention policy
+func NewWithRetention(directory string, retention *fsblkstorage.RetentionPolicy, metricsProvider metrics.Provider) blockledger.Factory {
 	return &fileLedgerFactory{
 		blkstorageProvider: fsblkstorage.NewProvider(
-			fsblkstorage.NewConf(directory, -1),
+			fsblkstorage.NewConfWithRetention(directory, -1, retention),
 			&blkstorage.IndexConfig{
 				AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
 			metricsProvider,
@@ -19,12 +19,15 @@ package fileledger
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"testing"
 
+	cl "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +46,10 @@ func (mbsp *mockBlockStoreProvider) OpenBlockStore(ledgerid string) (blkstorage.
 	return mbsp.blockstore, mbsp.error
 }
 
+func (mbsp *mockBlockStoreProvider) BootstrapFromSnapshot(ledgerid string, lastBlock, lastConfigBlock *cb.Block, txIDs cl.ResultsIterator) (blkstorage.BlockStore, error) {
+	return mbsp.blockstore, mbsp.error
+}
+
 func (mbsp *mockBlockStoreProvider) Exists(ledgerid string) (bool, error) {
 	return mbsp.exists, mbsp.error
 }
@@ -51,6 +58,10 @@ func (mbsp *mockBlockStoreProvider) List() ([]string, error) {
 	return mbsp.list, mbsp.error
 }
 
+func (mbsp *mockBlockStoreProvider) Remove(ledgerid string) error {
+	return mbsp.error
+}
+
 func (mbsp *mockBlockStoreProvider) Close() {
 }
 
@@ -93,3 +104,25 @@ func TestMultiReinitialization(t *testing.T) {
 	assert.Equal(t, 3, len(flf.ChainIDs()), "Expected chain to be recovered")
 	flf.Close()
 }
+
+func TestRemove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.NoError(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(dir)
+
+	flf := New(dir, &disabled.Provider{})
+	defer flf.Close()
+
+	ledger, err := flf.GetOrCreate("foo")
+	assert.NoError(t, err, "Error creating chain")
+	assert.NoError(t, ledger.Append(genesisBlock))
+	_, err = flf.GetOrCreate("bar")
+	assert.NoError(t, err, "Error creating chain")
+
+	assert.NoError(t, flf.Remove("foo"))
+	assert.Equal(t, []string{"bar"}, flf.ChainIDs(), "Expected removed chain to be gone")
+
+	ledger, err = flf.GetOrCreate("foo")
+	assert.NoError(t, err, "Error recreating chain")
+	assert.Zero(t, ledger.Height(), "Expected recreated chain to be empty")
+}
@@ -9,6 +9,7 @@ package fileledger
 import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -28,6 +29,7 @@ type FileLedgerBlockStore interface {
 	AddBlock(block *cb.Block) error
 	GetBlockchainInfo() (*cb.BlockchainInfo, error)
 	RetrieveBlocks(startBlockNumber uint64) (ledger.ResultsIterator, error)
+	Shutdown()
 }
 
 // NewFileLedger creates a new FileLedger for interaction with the ledger
@@ -45,6 +47,10 @@ type fileLedgerIterator struct {
 // It returns an error if the next block is no longer retrievable.
 func (i *fileLedgerIterator) Next() (*cb.Block, cb.Status) {
 	result, err := i.commonIterator.Next()
+	if _, ok := err.(*blkstorage.BlockPrunedError); ok {
+		logger.Warning(err)
+		return nil, cb.Status_NOT_FOUND
+	}
 	if err != nil {
 		logger.Error(err)
 		return nil, cb.Status_SERVICE_UNAVAILABLE
@@ -56,6 +62,24 @@ func (i *fileLedgerIterator) Next() (*cb.Block, cb.Status) {
 	return result.(*cb.Block), cb.Status_SUCCESS
 }
 
+// CheckRange returns cb.Status_NOT_FOUND if the iterator starts from the genesis
+// block, which is kept when the ledger is pruned, while the blocks following it
+// up to stopNum were pruned
+func (i *fileLedgerIterator) CheckRange(stopNum uint64) cb.Status {
+	if i.blockNumber != 0 || stopNum == 0 {
+		return cb.Status_SUCCESS
+	}
+	iterator, err := i.ledger.blockStore.RetrieveBlocks(1)
+	if prunedErr, ok := err.(*blkstorage.BlockPrunedError); ok {
+		logger.Warningf("Block [1] was pruned, blocks are available from block [%d] after the genesis block", prunedErr.FirstBlockNum)
+		return cb.Status_NOT_FOUND
+	}
+	if err == nil {
+		iterator.Close()
+	}
+	return cb.Status_SUCCESS
+}
+
 // Close releases resources acquired by the Iterator
 func (i *fileLedgerIterator) Close() {
 	i.commonIterator.Close()
@@ -86,6 +110,16 @@ func (fl *FileLedger) Iterator(startPosition *ab.SeekPosition) (blockledger.Iter
 	}
 
 	iterator, err := fl.blockStore.RetrieveBlocks(startingBlockNumber)
+	if prunedErr, ok := err.(*blkstorage.BlockPrunedError); ok {
+		// the oldest block is the first block which was not pruned, when
+		// the genesis block isn't kept, e.g. in a ledger bootstrapped from a snapshot
+		if _, oldest := startPosition.Type.(*ab.SeekPosition_Oldest); oldest {
+			startingBlockNumber = prunedErr.FirstBlockNum
+			iterator, err = fl.blockStore.RetrieveBlocks(startingBlockNumber)
+		} else {
+			logger.Warningf("Block [%d] was pruned, blocks are available from block [%d]", startingBlockNumber, prunedErr.FirstBlockNum)
+		}
+	}
 	if err != nil {
 		return &blockledger.NotFoundErrorIterator{}, 0
 	}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	cl "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
@@ -24,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 var genesisBlock = cb.NewBlock(0, nil)
@@ -109,6 +111,10 @@ func (mbs *mockBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return mbs.txValidationCode, mbs.defaultError
 }
 
+func (mbs *mockBlockStore) ExportTxIDs() (cl.ResultsIterator, error) {
+	return nil, mbs.defaultError
+}
+
 func (*mockBlockStore) Shutdown() {
 }
 
@@ -306,6 +312,95 @@ func TestBlockstoreError(t *testing.T) {
 	}
 }
 
+type prunedBlockStore struct {
+	mockBlockStore
+	firstBlockNum uint64
+	startNums     []uint64
+}
+
+func (pbs *prunedBlockStore) RetrieveBlocks(startNum uint64) (cl.ResultsIterator, error) {
+	pbs.startNums = append(pbs.startNums, startNum)
+	if startNum < pbs.firstBlockNum {
+		return nil, &blkstorage.BlockPrunedError{FirstBlockNum: pbs.firstBlockNum}
+	}
+	return pbs.resultsIterator, nil
+}
+
+func TestPrunedRetrieval(t *testing.T) {
+	resultsIterator := &mockBlockStoreIterator{}
+	resultsIterator.On("Close").Return()
+	blockStore := &prunedBlockStore{
+		mockBlockStore: mockBlockStore{
+			blockchainInfo:  &cb.BlockchainInfo{Height: uint64(10)},
+			resultsIterator: resultsIterator,
+		},
+		firstBlockNum: 5,
+	}
+	fl := &FileLedger{blockStore: blockStore, signal: make(chan struct{})}
+
+	// the oldest block is the first block which was not pruned
+	it, num := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	defer it.Close()
+	assert.IsType(t, &fileLedgerIterator{}, it)
+	assert.Equal(t, uint64(5), num)
+	assert.Equal(t, []uint64{0, 5}, blockStore.startNums)
+
+	it, _ = fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 3}}})
+	defer it.Close()
+	assert.IsType(t, &blockledger.NotFoundErrorIterator{}, it)
+	_, status := it.Next()
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+}
+
+type genesisKeptBlockStore struct {
+	mockBlockStore
+	firstBlockNum uint64
+}
+
+func (gbs *genesisKeptBlockStore) RetrieveBlocks(startNum uint64) (cl.ResultsIterator, error) {
+	if startNum != 0 && startNum < gbs.firstBlockNum {
+		return nil, &blkstorage.BlockPrunedError{FirstBlockNum: gbs.firstBlockNum}
+	}
+	return gbs.resultsIterator, nil
+}
+
+func TestPrunedRetrievalGenesisBlockKept(t *testing.T) {
+	resultsIterator := &mockBlockStoreIterator{}
+	resultsIterator.On("Next").Return(genesisBlock, nil).Once()
+	resultsIterator.On("Next").Return(nil, &blkstorage.BlockPrunedError{FirstBlockNum: 5})
+	resultsIterator.On("Close").Return()
+	blockStore := &genesisKeptBlockStore{
+		mockBlockStore: mockBlockStore{
+			blockchainInfo:  &cb.BlockchainInfo{Height: uint64(10)},
+			resultsIterator: resultsIterator,
+		},
+		firstBlockNum: 5,
+	}
+	fl := &FileLedger{blockStore: blockStore, signal: make(chan struct{})}
+
+	// only the genesis block can be requested before the first block which was not pruned
+	it, num := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	defer it.Close()
+	assert.Equal(t, uint64(0), num)
+	checker, ok := it.(blockledger.RangeChecker)
+	require.True(t, ok)
+	assert.Equal(t, cb.Status_SUCCESS, checker.CheckRange(0))
+	assert.Equal(t, cb.Status_NOT_FOUND, checker.CheckRange(1))
+	assert.Equal(t, cb.Status_NOT_FOUND, checker.CheckRange(5))
+
+	block, status := it.Next()
+	assert.Equal(t, cb.Status_SUCCESS, status)
+	assert.Equal(t, genesisBlock, block)
+	_, status = it.Next()
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+
+	// the ranges of a ledger which wasn't pruned are retrievable
+	blockStore.firstBlockNum = 0
+	it, _ = fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	defer it.Close()
+	assert.Equal(t, cb.Status_SUCCESS, it.(blockledger.RangeChecker).CheckRange(5))
+}
+
 func getSampleEnvelopeWithSignatureHeader() *cb.Envelope {
 	nonce := utils.CreateNonceOrPanic()
 	sighdr := &cb.SignatureHeader{Nonce: nonce}
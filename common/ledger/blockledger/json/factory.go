@@ -15,6 +15,7 @@ import (
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/pkg/errors"
 )
 
@@ -55,6 +56,7 @@ func newChain(directory string) blockledger.ReadWriter {
 	jl := &jsonLedger{
 		directory: directory,
 		signal:    make(chan struct{}),
+		hash:      util.ComputeSHA256,
 		marshaler: &jsonpb.Marshaler{Indent: "  "},
 	}
 	jl.initializeBlockHeight()
@@ -88,6 +90,11 @@ func (jl *jsonLedger) initializeBlockHeight() {
 	if jl.height == 0 {
 		return
 	}
+	genesisBlock, found := jl.readBlock(0)
+	if !found || genesisBlock == nil {
+		logger.Panicf("Error reading block 0")
+	}
+	jl.hash = blockledger.BlockHashingAlgorithm(genesisBlock)
 	block, found := jl.readBlock(jl.height - 1)
 	if !found {
 		logger.Panicf("Block %d was in directory listing but error reading", jl.height-1)
@@ -95,7 +102,7 @@ func (jl *jsonLedger) initializeBlockHeight() {
 	if block == nil {
 		logger.Panicf("Error reading block %d", jl.height-1)
 	}
-	jl.lastHash = block.Header.Hash()
+	jl.lastHash = jl.hash(block.Header.Bytes())
 }
 
 // ChainIDs returns the chain IDs the factory is aware of
@@ -113,6 +120,16 @@ func (jlf *jsonLedgerFactory) ChainIDs() []string {
 	return ids
 }
 
+// Remove removes the ledger of the given chain ID along with its directory
+func (jlf *jsonLedgerFactory) Remove(chainID string) error {
+	jlf.mutex.Lock()
+	defer jlf.mutex.Unlock()
+
+	delete(jlf.ledgers, chainID)
+	directory := filepath.Join(jlf.directory, fmt.Sprintf(chainDirectoryFormatString, chainID))
+	return errors.Wrapf(os.RemoveAll(directory), "error removing channel %s", chainID)
+}
+
 // Close is a no-op for the JSON ledger
 func (jlf *jsonLedgerFactory) Close() {
 	return // nothing to do
@@ -45,6 +45,7 @@ type jsonLedger struct {
 	directory string
 	height    uint64
 	lastHash  []byte
+	hash      func([]byte) []byte
 	marshaler *jsonpb.Marshaler
 
 	mutex  sync.Mutex
@@ -134,8 +135,12 @@ func (jl *jsonLedger) Append(block *cb.Block) error {
 		return errors.Errorf("block should have had previous hash of %x but was %x", jl.lastHash, block.Header.PreviousHash)
 	}
 
+	if block.Header.Number == 0 {
+		jl.hash = blockledger.BlockHashingAlgorithm(block)
+	}
+
 	jl.writeBlock(block)
-	jl.lastHash = block.Header.Hash()
+	jl.lastHash = jl.hash(block.Header.Bytes())
 	jl.height++
 
 	// Manage the signal channel under lock to avoid race with read in Next
@@ -13,10 +13,13 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -85,6 +88,31 @@ func TestReinitialization(t *testing.T) {
 	assert.Equal(t, fl.lastHash, block.Header.Hash(), "Block hashes did no match")
 }
 
+func TestSM3Reinitialization(t *testing.T) {
+	tev, _ := initialize(t)
+	defer tev.tearDown()
+
+	channelGroup := cb.NewConfigGroup()
+	channelGroup.Values["HashingAlgorithm"] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: util.SM3}),
+	}
+	ofl, err := New(tev.location).GetOrCreate("sm3channel")
+	assert.NoError(t, err)
+	assert.NoError(t, ofl.Append(genesis.NewFactoryImpl(channelGroup).Block("sm3channel")))
+	block := blockledger.CreateNextBlock(ofl, []*cb.Envelope{{Payload: []byte("My Data")}})
+	assert.Error(t, ofl.Append(block), "Blocks of the channel are hashed with SM3")
+	block = blockledger.CreateNextBlockWithHashingAlgorithm(ofl, []*cb.Envelope{{Payload: []byte("My Data")}}, util.ComputeSM3)
+	assert.NoError(t, ofl.Append(block))
+
+	tfl, err := New(tev.location).GetOrCreate("sm3channel")
+	assert.NoError(t, err)
+	fl := tfl.(*jsonLedger)
+	assert.Equal(t, uint64(2), fl.height, "Block height should be 2")
+	assert.Equal(t, util.ComputeSM3(block.Header.Bytes()), fl.lastHash, "Block hashes did no match")
+	block = blockledger.CreateNextBlockWithHashingAlgorithm(fl, []*cb.Envelope{{Payload: []byte("My Data")}}, util.ComputeSM3)
+	assert.NoError(t, fl.Append(block))
+}
+
 func TestMultiReinitialization(t *testing.T) {
 	tev, _ := initialize(t)
 	defer tev.tearDown()
@@ -20,6 +20,9 @@ type Factory interface {
 	// ChainIDs returns the chain IDs the Factory is aware of
 	ChainIDs() []string
 
+	// Remove removes the ledger of the given chain ID
+	Remove(chainID string) error
+
 	// Close releases all resources acquired by the factory
 	Close()
 }
@@ -33,6 +36,14 @@ type Iterator interface {
 	Close()
 }
 
+// RangeChecker is implemented by the Iterators which can tell, before their
+// first block is retrieved, whether the blocks up to a stop block are retrievable
+type RangeChecker interface {
+	// CheckRange returns cb.Status_NOT_FOUND if some of the blocks from the
+	// starting block of the Iterator up to stopNum are no longer retrievable
+	CheckRange(stopNum uint64) cb.Status
+}
+
 // Reader allows the caller to inspect the ledger
 type Reader interface {
 	// Iterator returns an Iterator, as specified by an ab.SeekInfo message, and
@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 )
 
@@ -57,6 +58,7 @@ func newChain(maxSize int) blockledger.ReadWriter {
 	rl := &ramLedger{
 		maxSize: maxSize,
 		size:    1,
+		hash:    util.ComputeSHA256,
 		oldest: &simpleList{
 			signal: make(chan struct{}),
 			block:  preGenesis,
@@ -81,6 +83,15 @@ func (rlf *ramLedgerFactory) ChainIDs() []string {
 	return ids
 }
 
+// Remove removes the ledger of the given chain ID
+func (rlf *ramLedgerFactory) Remove(chainID string) error {
+	rlf.mutex.Lock()
+	defer rlf.mutex.Unlock()
+
+	delete(rlf.ledgers, chainID)
+	return nil
+}
+
 // Close is a no-op for the RAM ledger
 func (rlf *ramLedgerFactory) Close() {
 	return // nothing to do
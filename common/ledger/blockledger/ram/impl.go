@@ -48,6 +48,7 @@ type ramLedger struct {
 	size    int
 	oldest  *simpleList
 	newest  *simpleList
+	hash    func([]byte) []byte
 }
 
 // Next blocks until there is a new block available, or returns an error if the
@@ -151,10 +152,13 @@ func (rl *ramLedger) Append(block *cb.Block) error {
 	}
 
 	if rl.newest.block.Header.Number+1 != 0 { // Skip this check for genesis block insertion
-		if !bytes.Equal(block.Header.PreviousHash, rl.newest.block.Header.Hash()) {
+		previousHash := rl.hash(rl.newest.block.Header.Bytes())
+		if !bytes.Equal(block.Header.PreviousHash, previousHash) {
 			return errors.Errorf("block should have had previous hash of %x but was %x",
-				rl.newest.block.Header.Hash(), block.Header.PreviousHash)
+				previousHash, block.Header.PreviousHash)
 		}
+	} else {
+		rl.hash = blockledger.BlockHashingAlgorithm(block)
 	}
 
 	rl.appendBlock(block)
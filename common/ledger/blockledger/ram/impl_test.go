@@ -10,10 +10,14 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
 )
 
 var genesisBlock = cb.NewBlock(0, nil)
@@ -182,3 +186,20 @@ func TestAppendBadBLock(t *testing.T) {
 		}
 	})
 }
+
+func TestAppendSM3Blocks(t *testing.T) {
+	channelGroup := cb.NewConfigGroup()
+	channelGroup.Values["HashingAlgorithm"] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: util.SM3}),
+	}
+	rl, err := New(3).GetOrCreate("sm3channel")
+	assert.NoError(t, err)
+	assert.NoError(t, rl.Append(genesis.NewFactoryImpl(channelGroup).Block("sm3channel")))
+
+	nextBlock := blockledger.CreateNextBlock(rl, []*cb.Envelope{{Payload: []byte("My Data")}})
+	assert.Error(t, rl.Append(nextBlock), "Blocks of the channel are hashed with SM3")
+
+	nextBlock = blockledger.CreateNextBlockWithHashingAlgorithm(rl, []*cb.Envelope{{Payload: []byte("My Data")}}, util.ComputeSM3)
+	assert.NoError(t, rl.Append(nextBlock))
+	assert.Equal(t, uint64(2), rl.Height())
+}
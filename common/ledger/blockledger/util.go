@@ -8,8 +8,10 @@ package blockledger
 
 import (
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 var closedChan chan struct{}
@@ -41,6 +43,13 @@ func (nfei *NotFoundErrorIterator) Close() {}
 // XXX This will need to be modified to accept marshaled envelopes
 //     to accommodate non-deterministic marshaling
 func CreateNextBlock(rl Reader, messages []*cb.Envelope) *cb.Block {
+	return CreateNextBlockWithHashingAlgorithm(rl, messages, util.ComputeSHA256)
+}
+
+// CreateNextBlockWithHashingAlgorithm is like CreateNextBlock, the previous
+// block hash and the data hash of the block being computed with hash, the
+// block hashing algorithm of the channel
+func CreateNextBlockWithHashingAlgorithm(rl Reader, messages []*cb.Envelope, hash func([]byte) []byte) *cb.Block {
 	var nextBlockNumber uint64
 	var previousBlockHash []byte
 
@@ -55,7 +64,7 @@ func CreateNextBlock(rl Reader, messages []*cb.Envelope) *cb.Block {
 			panic("Error seeking to newest block for chain with non-zero height")
 		}
 		nextBlockNumber = block.Header.Number + 1
-		previousBlockHash = block.Header.Hash()
+		previousBlockHash = hash(block.Header.Bytes())
 	}
 
 	data := &cb.BlockData{
@@ -71,12 +80,21 @@ func CreateNextBlock(rl Reader, messages []*cb.Envelope) *cb.Block {
 	}
 
 	block := cb.NewBlock(nextBlockNumber, previousBlockHash)
-	block.Header.DataHash = data.Hash()
+	block.Header.DataHash = hash(data.Bytes())
 	block.Data = data
 
 	return block
 }
 
+// BlockHashingAlgorithm returns the function hashing the blocks of the chain
+// starting with genesisBlock, as selected by the channel configuration it
+// carries. The blocks of the chains without a configuration are hashed with
+// SHA256.
+func BlockHashingAlgorithm(genesisBlock *cb.Block) func([]byte) []byte {
+	algorithm, _ := utils.GetHashingAlgorithmFromBlock(genesisBlock)
+	return util.BlockHashingFunction(algorithm)
+}
+
 // GetBlock is a utility method for retrieving a single block
 func GetBlock(rl Reader, index uint64) *cb.Block {
 	iterator, _ := rl.Iterator(&ab.SeekPosition{
@@ -7,11 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package blockledger_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/deliver/mock"
+	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,3 +57,18 @@ func TestClose(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockHashingAlgorithm(t *testing.T) {
+	channelGroup := common.NewConfigGroup()
+	channelGroup.Values["HashingAlgorithm"] = &common.ConfigValue{
+		Value: utils.MarshalOrPanic(&common.HashingAlgorithm{Name: util.SM3}),
+	}
+	hash := blockledger.BlockHashingAlgorithm(genesis.NewFactoryImpl(channelGroup).Block("foo"))
+	assert.Equal(t, reflect.ValueOf(util.ComputeSM3).Pointer(), reflect.ValueOf(hash).Pointer())
+
+	hash = blockledger.BlockHashingAlgorithm(genesis.NewFactoryImpl(common.NewConfigGroup()).Block("foo"))
+	assert.Equal(t, reflect.ValueOf(util.ComputeSHA256).Pointer(), reflect.ValueOf(hash).Pointer())
+
+	hash = blockledger.BlockHashingAlgorithm(common.NewBlock(0, nil))
+	assert.Equal(t, reflect.ValueOf(util.ComputeSHA256).Pointer(), reflect.ValueOf(hash).Pointer())
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"sync"
 
+	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
@@ -27,6 +28,9 @@ import (
 var dbNameKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 
+// maxBatchSize is the number of keys deleted per batch by DeleteAll
+const maxBatchSize = 1000
+
 // Provider enables to use a single leveldb as multiple logical leveldbs
 type Provider struct {
 	db        *DB
@@ -79,6 +83,30 @@ func (h *DBHandle) Delete(key []byte, sync bool) error {
 	return h.db.Delete(constructLevelKey(h.dbName, key), sync)
 }
 
+// DeleteAll deletes all the keys of the named db. The keys are deleted in batches,
+// hence the deletion is not atomic
+func (h *DBHandle) DeleteAll() error {
+	itr := h.GetIterator(nil, nil)
+	defer itr.Release()
+	levelBatch := &leveldb.Batch{}
+	for itr.Next() {
+		levelBatch.Delete(itr.Iterator.Key())
+		if levelBatch.Len() == maxBatchSize {
+			if err := h.db.WriteBatch(levelBatch, true); err != nil {
+				return err
+			}
+			levelBatch.Reset()
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrapf(err, "error while iterating over the keys of db [%s]", h.dbName)
+	}
+	if levelBatch.Len() == 0 {
+		return nil
+	}
+	return h.db.WriteBatch(levelBatch, true)
+}
+
 // WriteBatch writes a batch in an atomic way
 func (h *DBHandle) WriteBatch(batch *UpdateBatch, sync bool) error {
 	if len(batch.KVs) == 0 {
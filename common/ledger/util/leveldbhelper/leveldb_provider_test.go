@@ -90,6 +90,27 @@ func TestBatchedUpdates(t *testing.T) {
 	}
 }
 
+func TestDeleteAll(t *testing.T) {
+	env := newTestProviderEnv(t, testDBPath)
+	defer env.cleanup()
+	p := env.provider
+
+	db1 := p.GetDBHandle("db1")
+	db2 := p.GetDBHandle("db2")
+	// more keys than the size of a batch
+	for i := 0; i < maxBatchSize+10; i++ {
+		db1.Put([]byte(createTestKey(i)), []byte(createTestValue("db1", i)), false)
+		db2.Put([]byte(createTestKey(i)), []byte(createTestValue("db2", i)), false)
+	}
+
+	assert.NoError(t, db1.DeleteAll())
+	checkItrResults(t, db1.GetIterator(nil, nil), nil, nil)
+	checkItrResults(t, db2.GetIterator(nil, nil), createTestKeys(0, maxBatchSize+9), createTestValues("db2", 0, maxBatchSize+9))
+
+	// deleting the keys of an empty db does nothing
+	assert.NoError(t, db1.DeleteAll())
+}
+
 func testDBBasicWriteAndReads(t *testing.T, dbNames ...string) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
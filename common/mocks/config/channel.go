@@ -30,6 +30,8 @@ func nearIdentityHash(input []byte) []byte {
 type Channel struct {
 	// HashingAlgorithmVal is returned as the result of HashingAlgorithm() if set
 	HashingAlgorithmVal func([]byte) []byte
+	// BlockHashingAlgorithmVal is returned as the result of BlockHashingAlgorithm() if set
+	BlockHashingAlgorithmVal func([]byte) []byte
 	// BlockDataHashingStructureWidthVal is returned as the result of BlockDataHashingStructureWidth()
 	BlockDataHashingStructureWidthVal uint32
 	// OrdererAddressesVal is returned as the result of OrdererAddresses()
@@ -46,6 +48,14 @@ func (scm *Channel) HashingAlgorithm() func([]byte) []byte {
 	return scm.HashingAlgorithmVal
 }
 
+// BlockHashingAlgorithm returns the BlockHashingAlgorithmVal if set, otherwise SHA256
+func (scm *Channel) BlockHashingAlgorithm() func([]byte) []byte {
+	if scm.BlockHashingAlgorithmVal == nil {
+		return util.ComputeSHA256
+	}
+	return scm.BlockHashingAlgorithmVal
+}
+
 // BlockDataHashingStructureWidth returns the BlockDataHashingStructureWidthVal
 func (scm *Channel) BlockDataHashingStructureWidth() uint32 {
 	return scm.BlockDataHashingStructureWidthVal
@@ -70,6 +80,9 @@ type ChannelCapabilities struct {
 	MSPVersionVal msp.MSPVersion
 
 	ConsensusTypeMigrationVal bool
+
+	// SM3HashingVal is returned by SM3Hashing()
+	SM3HashingVal bool
 }
 
 func (cc *ChannelCapabilities) OrgSpecificOrdererEndpoints() bool {
@@ -92,3 +105,8 @@ func (cc *ChannelCapabilities) MSPVersion() msp.MSPVersion {
 func (cc *ChannelCapabilities) ConsensusTypeMigration() bool {
 	return cc.ConsensusTypeMigrationVal
 }
+
+// SM3Hashing returns SM3HashingVal
+func (cc *ChannelCapabilities) SM3Hashing() bool {
+	return cc.SM3HashingVal
+}
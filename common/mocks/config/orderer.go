@@ -26,6 +26,8 @@ type Orderer struct {
 	BatchSizeVal *ab.BatchSize
 	// BatchTimeoutVal is returned as the result of BatchTimeout()
 	BatchTimeoutVal time.Duration
+	// BatchPriorityVal is returned as the result of BatchPriority()
+	BatchPriorityVal *ab.BatchPriority
 	// KafkaBrokersVal is returned as the result of KafkaBrokers()
 	KafkaBrokersVal []string
 	// MaxChannelsCountVal is returns as the result of MaxChannelsCount()
@@ -61,6 +63,11 @@ func (o *Orderer) BatchTimeout() time.Duration {
 	return o.BatchTimeoutVal
 }
 
+// BatchPriority returns the BatchPriorityVal
+func (o *Orderer) BatchPriority() *ab.BatchPriority {
+	return o.BatchPriorityVal
+}
+
 // KafkaBrokers returns the KafkaBrokersVal
 func (o *Orderer) KafkaBrokers() []string {
 	return o.KafkaBrokersVal
@@ -96,6 +103,9 @@ type OrdererCapabilities struct {
 	ExpirationVal bool
 
 	ConsensusTypeMigrationVal bool
+
+	// BatchPriorityVal is returned by BatchPriority()
+	BatchPriorityVal bool
 }
 
 // Supported returns SupportedErr
@@ -123,3 +133,8 @@ func (oc *OrdererCapabilities) ExpirationCheck() bool {
 func (oc *OrdererCapabilities) ConsensusTypeMigration() bool {
 	return oc.ConsensusTypeMigrationVal
 }
+
+// BatchPriority returns BatchPriorityVal
+func (oc *OrdererCapabilities) BatchPriority() bool {
+	return oc.BatchPriorityVal
+}
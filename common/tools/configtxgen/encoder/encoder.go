@@ -18,6 +18,8 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -118,6 +120,69 @@ func addOrdererImplicitMetaPolicyDefaults(cg *cb.ConfigGroup) {
 	addImplicitMetaPolicyDefaults(cg)
 }
 
+// addBFTBlockValidationPolicy sets the BlockValidation policy of the orderer group to require the
+// signatures of a quorum of the BFT consenters, so that blocks are only accepted once the consenters agreed on them.
+func addBFTBlockValidationPolicy(cg *cb.ConfigGroup, consensusMetadata []byte, modPolicy string) error {
+	md := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusMetadata, md); err != nil {
+		return err
+	}
+
+	policy, err := bft.BlockValidationPolicy(md.Consenters)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Setting the %s policy of the orderer group to %d signatures out of the %d %s consenters", BlockValidationPolicyKey, bft.Quorum(len(md.Consenters)), len(md.Consenters), bft.TypeKey)
+	addPolicy(cg, policies.SignaturePolicy(BlockValidationPolicyKey, policy), modPolicy)
+	return nil
+}
+
+// UpdateBFTBlockValidationPolicy sets the BlockValidation policy of the orderer group of the config to
+// require the signatures of a quorum of the BFT consenters of its ConsensusType. Blocks are validated
+// against this policy rather than the consenters, so the config update which adds or removes consenters
+// must be computed from a config updated this way, and the BFT chain rejects it otherwise.
+func UpdateBFTBlockValidationPolicy(config *cb.Config) error {
+	ordererGroup, ok := config.GetChannelGroup().GetGroups()[channelconfig.OrdererGroupKey]
+	if !ok {
+		return errors.New("config has no orderer group")
+	}
+	consensusTypeValue, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return errors.New("config has no consensus type")
+	}
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(consensusTypeValue.Value, consensusType); err != nil {
+		return errors.Wrap(err, "failed to unmarshal the consensus type")
+	}
+	if consensusType.Type != bft.TypeKey {
+		return errors.Errorf("consensus type is %s instead of %s", consensusType.Type, bft.TypeKey)
+	}
+
+	modPolicy := channelconfig.AdminsPolicyKey
+	if policy, ok := ordererGroup.Policies[BlockValidationPolicyKey]; ok {
+		modPolicy = policy.ModPolicy
+	}
+	return addBFTBlockValidationPolicy(ordererGroup, consensusType.Metadata, modPolicy)
+}
+
+// priorityLanes converts the priority lanes of the orderer configuration to their protos.
+func priorityLanes(lanes []*genesisconfig.PriorityLane) []*ab.PriorityLane {
+	var result []*ab.PriorityLane
+	for _, lane := range lanes {
+		pl := &ab.PriorityLane{
+			Name:        lane.Name,
+			HeaderTypes: lane.HeaderTypes,
+			MspIds:      lane.MSPIDs,
+		}
+		if lane.MaxPendingAge > 0 {
+			pl.MaxPendingAge = lane.MaxPendingAge.String()
+		}
+		result = append(result, pl)
+	}
+	return result
+}
+
 // addSignaturePolicyDefaults adds the Readers/Writers/Admins policies as signature policies requiring one signature from the given mspID.
 // If devMode is set to true, the Admins policy will accept arbitrary user certs for admin functions, otherwise it requires the cert satisfies
 // the admin role principal.
@@ -148,7 +213,7 @@ func NewChannelGroup(conf *genesisconfig.Profile) (*cb.ConfigGroup, error) {
 		}
 	}
 
-	addValue(channelGroup, channelconfig.HashingAlgorithmValue(), channelconfig.AdminsPolicyKey)
+	addValue(channelGroup, channelconfig.HashingAlgorithmValueWithName(conf.HashingAlgorithm), channelconfig.AdminsPolicyKey)
 	addValue(channelGroup, channelconfig.BlockDataHashingStructureValue(), channelconfig.AdminsPolicyKey)
 	if conf.Orderer != nil && len(conf.Orderer.Addresses) > 0 {
 		addValue(channelGroup, channelconfig.OrdererAddressesValue(conf.Orderer.Addresses), ordererAdminsPolicyName)
@@ -207,6 +272,9 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		conf.BatchSize.PreferredMaxBytes,
 	), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.BatchTimeoutValue(conf.BatchTimeout.String()), channelconfig.AdminsPolicyKey)
+	if len(conf.BatchPriority) > 0 {
+		addValue(ordererGroup, channelconfig.BatchPriorityValue(priorityLanes(conf.BatchPriority)), channelconfig.AdminsPolicyKey)
+	}
 	addValue(ordererGroup, channelconfig.ChannelRestrictionsValue(conf.MaxChannels), channelconfig.AdminsPolicyKey)
 
 	if len(conf.Capabilities) > 0 {
@@ -224,6 +292,13 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		if consensusMetadata, err = etcdraft.Marshal(conf.EtcdRaft); err != nil {
 			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", etcdraft.TypeKey, err)
 		}
+	case bft.TypeKey:
+		if consensusMetadata, err = bft.Marshal(conf.BFT); err != nil {
+			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", bft.TypeKey, err)
+		}
+		if err = addBFTBlockValidationPolicy(ordererGroup, consensusMetadata, channelconfig.AdminsPolicyKey); err != nil {
+			return nil, errors.Wrapf(err, "cannot create block validation policy for orderer type %s", bft.TypeKey)
+		}
 	default:
 		return nil, errors.Errorf("unknown orderer type: %s", conf.OrdererType)
 	}
@@ -8,6 +8,10 @@ package encoder_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,6 +24,7 @@ import (
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -64,6 +69,21 @@ var _ = Describe("Encoder", func() {
 			Expect(cg.Values["OrdererAddresses"]).NotTo(BeNil())
 		})
 
+		Context("when the hashing algorithm is set", func() {
+			BeforeEach(func() {
+				conf.HashingAlgorithm = "SM3"
+			})
+
+			It("encodes it", func() {
+				cg, err := encoder.NewChannelGroup(conf)
+				Expect(err).NotTo(HaveOccurred())
+				hashingAlgorithm := &cb.HashingAlgorithm{}
+				err = proto.Unmarshal(cg.Values["HashingAlgorithm"].Value, hashingAlgorithm)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hashingAlgorithm.Name).To(Equal("SM3"))
+			})
+		})
+
 		Context("when the policies are ommitted", func() {
 			BeforeEach(func() {
 				conf.Policies = nil
@@ -235,6 +255,30 @@ var _ = Describe("Encoder", func() {
 			})
 		})
 
+		Context("when batch priority lanes are configured", func() {
+			BeforeEach(func() {
+				conf.BatchPriority = []*genesisconfig.PriorityLane{
+					{Name: "config", HeaderTypes: []string{"ORDERER_TRANSACTION"}},
+					{Name: "org2", MSPIDs: []string{"Org2MSP"}, MaxPendingAge: 100 * time.Millisecond},
+				}
+			})
+
+			It("adds the batch priority key", func() {
+				cg, err := encoder.NewOrdererGroup(conf)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(cg.Values)).To(Equal(6))
+				batchPriority := &ab.BatchPriority{}
+				err = proto.Unmarshal(cg.Values["BatchPriority"].Value, batchPriority)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(batchPriority, &ab.BatchPriority{
+					Lanes: []*ab.PriorityLane{
+						{Name: "config", HeaderTypes: []string{"ORDERER_TRANSACTION"}},
+						{Name: "org2", MspIds: []string{"Org2MSP"}, MaxPendingAge: "100ms"},
+					},
+				})).To(BeTrue())
+			})
+		})
+
 		Context("when the consensus type is Kafka", func() {
 			BeforeEach(func() {
 				conf.OrdererType = "kafka"
@@ -288,6 +332,130 @@ var _ = Describe("Encoder", func() {
 			})
 		})
 
+		Context("when the consensus type is BFT", func() {
+			var tmpDir string
+
+			BeforeEach(func() {
+				var err error
+				tmpDir, err = ioutil.TempDir("", "encoder-bft")
+				Expect(err).NotTo(HaveOccurred())
+
+				conf.OrdererType = "bft"
+				conf.BFT = &bft.ConfigMetadata{
+					Options: &bft.Options{
+						RequestTimeout:    "10s",
+						ViewChangeTimeout: "20s",
+					},
+				}
+				for i := 1; i <= 4; i++ {
+					consenter := &bft.Consenter{ConsenterId: uint64(i), MspId: "SampleOrg"}
+					for _, file := range []struct {
+						name  string
+						field *[]byte
+					}{
+						{name: "identity", field: &consenter.Identity},
+						{name: "client", field: &consenter.ClientTlsCert},
+						{name: "server", field: &consenter.ServerTlsCert},
+					} {
+						path := filepath.Join(tmpDir, fmt.Sprintf("%s-%d.pem", file.name, i))
+						err := ioutil.WriteFile(path, []byte(fmt.Sprintf("%s %d", file.name, i)), 0644)
+						Expect(err).NotTo(HaveOccurred())
+						*file.field = []byte(path)
+					}
+					conf.BFT.Consenters = append(conf.BFT.Consenters, consenter)
+				}
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(tmpDir)
+			})
+
+			It("adds the BFT metadata and requires a quorum of consenters to validate blocks", func() {
+				cg, err := encoder.NewOrdererGroup(conf)
+				Expect(err).NotTo(HaveOccurred())
+				consensusType := &ab.ConsensusType{}
+				err = proto.Unmarshal(cg.Values["ConsensusType"].Value, consensusType)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(consensusType.Type).To(Equal("bft"))
+				metadata := &bft.ConfigMetadata{}
+				err = proto.Unmarshal(consensusType.Metadata, metadata)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata.Consenters).To(HaveLen(4))
+				Expect(metadata.Consenters[1].Identity).To(Equal([]byte("identity 2")))
+
+				policy := cg.Policies[encoder.BlockValidationPolicyKey].Policy
+				Expect(policy.Type).To(Equal(int32(cb.Policy_SIGNATURE)))
+				sp := &cb.SignaturePolicyEnvelope{}
+				err = proto.Unmarshal(policy.Value, sp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sp.Rule.GetNOutOf().N).To(Equal(int32(3)))
+				Expect(sp.Rule.GetNOutOf().Rules).To(HaveLen(4))
+				Expect(sp.Identities).To(HaveLen(4))
+				Expect(sp.Identities[2].PrincipalClassification).To(Equal(msp.MSPPrincipal_IDENTITY))
+				Expect(sp.Identities[2].Principal).To(Equal(utils.MarshalOrPanic(&msp.SerializedIdentity{
+					Mspid:   "SampleOrg",
+					IdBytes: []byte("identity 3"),
+				})))
+			})
+
+			It("updates the BlockValidation policy when a consenter is added", func() {
+				cg, err := encoder.NewOrdererGroup(conf)
+				Expect(err).NotTo(HaveOccurred())
+				config := &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{"Orderer": cg}}}
+
+				consensusType := &ab.ConsensusType{}
+				err = proto.Unmarshal(cg.Values["ConsensusType"].Value, consensusType)
+				Expect(err).NotTo(HaveOccurred())
+				metadata := &bft.ConfigMetadata{}
+				err = proto.Unmarshal(consensusType.Metadata, metadata)
+				Expect(err).NotTo(HaveOccurred())
+				metadata.Consenters = append(metadata.Consenters, &bft.Consenter{
+					ConsenterId: 5,
+					MspId:       "SampleOrg",
+					Identity:    []byte("identity 5"),
+				})
+				consensusType.Metadata = utils.MarshalOrPanic(metadata)
+				cg.Values["ConsensusType"].Value = utils.MarshalOrPanic(consensusType)
+				cg.Policies[encoder.BlockValidationPolicyKey].ModPolicy = "OrdererAdmins"
+
+				err = encoder.UpdateBFTBlockValidationPolicy(config)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cg.Policies[encoder.BlockValidationPolicyKey].ModPolicy).To(Equal("OrdererAdmins"))
+				sp := &cb.SignaturePolicyEnvelope{}
+				err = proto.Unmarshal(cg.Policies[encoder.BlockValidationPolicyKey].Policy.Value, sp)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sp.Rule.GetNOutOf().N).To(Equal(int32(4)))
+				Expect(sp.Rule.GetNOutOf().Rules).To(HaveLen(5))
+				Expect(sp.Identities[4].Principal).To(Equal(utils.MarshalOrPanic(&msp.SerializedIdentity{
+					Mspid:   "SampleOrg",
+					IdBytes: []byte("identity 5"),
+				})))
+			})
+
+			Context("when the consensus type is not BFT", func() {
+				It("does not update the BlockValidation policy", func() {
+					conf.OrdererType = "solo"
+					cg, err := encoder.NewOrdererGroup(conf)
+					Expect(err).NotTo(HaveOccurred())
+					config := &cb.Config{ChannelGroup: &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{"Orderer": cg}}}
+
+					err = encoder.UpdateBFTBlockValidationPolicy(config)
+					Expect(err).To(MatchError("consensus type is solo instead of bft"))
+				})
+			})
+
+			Context("when the BFT configuration is bad", func() {
+				BeforeEach(func() {
+					conf.BFT.Consenters[0].Identity = []byte(filepath.Join(tmpDir, "missing.pem"))
+				})
+
+				It("wraps and returns the error", func() {
+					_, err := encoder.NewOrdererGroup(conf)
+					Expect(err).To(MatchError(HavePrefix("cannot marshal metadata for orderer type bft: cannot load identity for consenter :0")))
+				})
+			})
+		})
+
 		Context("when the consensus type is unknown", func() {
 			BeforeEach(func() {
 				conf.OrdererType = "bad-type"
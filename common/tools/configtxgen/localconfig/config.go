@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/common/viperutil"
 	cf "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/spf13/viper"
 )
@@ -90,12 +91,13 @@ type TopLevel struct {
 // Profile encodes orderer/application configuration combinations for the
 // configtxgen tool.
 type Profile struct {
-	Consortium   string                 `yaml:"Consortium"`
-	Application  *Application           `yaml:"Application"`
-	Orderer      *Orderer               `yaml:"Orderer"`
-	Consortiums  map[string]*Consortium `yaml:"Consortiums"`
-	Capabilities map[string]bool        `yaml:"Capabilities"`
-	Policies     map[string]*Policy     `yaml:"Policies"`
+	Consortium       string                 `yaml:"Consortium"`
+	Application      *Application           `yaml:"Application"`
+	Orderer          *Orderer               `yaml:"Orderer"`
+	Consortiums      map[string]*Consortium `yaml:"Consortiums"`
+	Capabilities     map[string]bool        `yaml:"Capabilities"`
+	Policies         map[string]*Policy     `yaml:"Policies"`
+	HashingAlgorithm string                 `yaml:"HashingAlgorithm"`
 }
 
 // Policy encodes a channel config policy
@@ -160,8 +162,10 @@ type Orderer struct {
 	Addresses     []string                 `yaml:"Addresses"`
 	BatchTimeout  time.Duration            `yaml:"BatchTimeout"`
 	BatchSize     BatchSize                `yaml:"BatchSize"`
+	BatchPriority []*PriorityLane          `yaml:"BatchPriority"`
 	Kafka         Kafka                    `yaml:"Kafka"`
 	EtcdRaft      *etcdraft.ConfigMetadata `yaml:"EtcdRaft"`
+	BFT           *bft.ConfigMetadata      `yaml:"BFT"`
 	Organizations []*Organization          `yaml:"Organizations"`
 	MaxChannels   uint64                   `yaml:"MaxChannels"`
 	Capabilities  map[string]bool          `yaml:"Capabilities"`
@@ -175,6 +179,15 @@ type BatchSize struct {
 	PreferredMaxBytes uint32 `yaml:"PreferredMaxBytes"`
 }
 
+// PriorityLane contains configuration for a lane ordering the messages of a batch,
+// the lanes are listed by decreasing priority.
+type PriorityLane struct {
+	Name          string        `yaml:"Name"`
+	HeaderTypes   []string      `yaml:"HeaderTypes"`
+	MSPIDs        []string      `yaml:"MSPIDs"`
+	MaxPendingAge time.Duration `yaml:"MaxPendingAge"`
+}
+
 // Kafka contains configuration for the Kafka-based orderer.
 type Kafka struct {
 	Brokers []string `yaml:"Brokers"`
@@ -202,6 +215,12 @@ var genesisDefaults = TopLevel{
 				SnapshotIntervalSize: 20 * 1024 * 1024, // 20 MB
 			},
 		},
+		BFT: &bft.ConfigMetadata{
+			Options: &bft.Options{
+				RequestTimeout:    "10s",
+				ViewChangeTimeout: "20s",
+			},
+		},
 	},
 }
 
@@ -461,6 +480,72 @@ loop:
 			cf.TranslatePathInPlace(configDir, &serverCertPath)
 			c.ServerTlsCert = []byte(serverCertPath)
 		}
+	case bft.TypeKey:
+		if ord.BFT == nil {
+			logger.Panicf("%s configuration missing", bft.TypeKey)
+		}
+		if ord.BFT.Options == nil {
+			logger.Infof("Orderer.BFT.Options unset, setting to %v", genesisDefaults.Orderer.BFT.Options)
+			ord.BFT.Options = genesisDefaults.Orderer.BFT.Options
+		}
+	bft_loop:
+		for {
+			switch {
+			case ord.BFT.Options.RequestTimeout == "":
+				logger.Infof("Orderer.BFT.Options.RequestTimeout unset, setting to %v", genesisDefaults.Orderer.BFT.Options.RequestTimeout)
+				ord.BFT.Options.RequestTimeout = genesisDefaults.Orderer.BFT.Options.RequestTimeout
+
+			case ord.BFT.Options.ViewChangeTimeout == "":
+				logger.Infof("Orderer.BFT.Options.ViewChangeTimeout unset, setting to %v", genesisDefaults.Orderer.BFT.Options.ViewChangeTimeout)
+				ord.BFT.Options.ViewChangeTimeout = genesisDefaults.Orderer.BFT.Options.ViewChangeTimeout
+
+			case len(ord.BFT.Consenters) == 0:
+				logger.Panicf("%s configuration did not specify any consenter", bft.TypeKey)
+
+			default:
+				break bft_loop
+			}
+		}
+
+		if _, err := time.ParseDuration(ord.BFT.Options.RequestTimeout); err != nil {
+			logger.Panicf("BFT RequestTimeout (%s) must be in time duration format", ord.BFT.Options.RequestTimeout)
+		}
+		if _, err := time.ParseDuration(ord.BFT.Options.ViewChangeTimeout); err != nil {
+			logger.Panicf("BFT ViewChangeTimeout (%s) must be in time duration format", ord.BFT.Options.ViewChangeTimeout)
+		}
+
+		for _, c := range ord.BFT.GetConsenters() {
+			if c.ConsenterId == 0 {
+				logger.Panicf("consenter info in %s configuration did not specify consenter ID", bft.TypeKey)
+			}
+			if c.Host == "" {
+				logger.Panicf("consenter info in %s configuration did not specify host", bft.TypeKey)
+			}
+			if c.Port == 0 {
+				logger.Panicf("consenter info in %s configuration did not specify port", bft.TypeKey)
+			}
+			if c.MspId == "" {
+				logger.Panicf("consenter info in %s configuration did not specify MSP ID", bft.TypeKey)
+			}
+			if c.Identity == nil {
+				logger.Panicf("consenter info in %s configuration did not specify identity", bft.TypeKey)
+			}
+			if c.ClientTlsCert == nil {
+				logger.Panicf("consenter info in %s configuration did not specify client TLS cert", bft.TypeKey)
+			}
+			if c.ServerTlsCert == nil {
+				logger.Panicf("consenter info in %s configuration did not specify server TLS cert", bft.TypeKey)
+			}
+			identityPath := string(c.GetIdentity())
+			cf.TranslatePathInPlace(configDir, &identityPath)
+			c.Identity = []byte(identityPath)
+			clientCertPath := string(c.GetClientTlsCert())
+			cf.TranslatePathInPlace(configDir, &clientCertPath)
+			c.ClientTlsCert = []byte(clientCertPath)
+			serverCertPath := string(c.GetServerTlsCert())
+			cf.TranslatePathInPlace(configDir, &serverCertPath)
+			c.ServerTlsCert = []byte(serverCertPath)
+		}
 	default:
 		logger.Panicf("unknown orderer type: %s", ord.OrdererType)
 	}
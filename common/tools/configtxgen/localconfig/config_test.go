@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package localconfig
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -270,4 +272,81 @@ func TestConsensusSpecificInit(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("bft", func(t *testing.T) {
+		consenter := &bft.Consenter{
+			ConsenterId:   1,
+			Host:          "node-1.example.com",
+			Port:          7050,
+			MspId:         "SampleOrg",
+			Identity:      []byte("path/to/identity"),
+			ClientTlsCert: []byte("path/to/client/cert"),
+			ServerTlsCert: []byte("path/to/server/cert"),
+		}
+		makeProfile := func(consenters []*bft.Consenter, options *bft.Options) *Profile {
+			return &Profile{
+				Orderer: &Orderer{
+					OrdererType: "bft",
+					BFT: &bft.ConfigMetadata{
+						Consenters: consenters,
+						Options:    options,
+					},
+				},
+			}
+		}
+
+		t.Run("BFT section not specified in profile", func(t *testing.T) {
+			profile := &Profile{
+				Orderer: &Orderer{
+					OrdererType: "bft",
+				},
+			}
+
+			assert.Panics(t, func() {
+				profile.completeInitialization(devConfigDir)
+			})
+		})
+
+		t.Run("nil consenter set", func(t *testing.T) {
+			profile := makeProfile(nil, nil)
+
+			assert.Panics(t, func() {
+				profile.completeInitialization(devConfigDir)
+			})
+		})
+
+		t.Run("missing identity", func(t *testing.T) {
+			profile := makeProfile([]*bft.Consenter{{
+				ConsenterId:   1,
+				Host:          "node-1.example.com",
+				Port:          7050,
+				MspId:         "SampleOrg",
+				ClientTlsCert: []byte("path/to/client/cert"),
+				ServerTlsCert: []byte("path/to/server/cert"),
+			}}, nil)
+
+			assert.Panics(t, func() {
+				profile.completeInitialization(devConfigDir)
+			})
+		})
+
+		t.Run("invalid view change timeout", func(t *testing.T) {
+			profile := makeProfile([]*bft.Consenter{consenter}, &bft.Options{ViewChangeTimeout: "20"})
+
+			assert.Panics(t, func() {
+				profile.completeInitialization(devConfigDir)
+			})
+		})
+
+		t.Run("request timeout specified in Options", func(t *testing.T) {
+			profile := makeProfile([]*bft.Consenter{consenter}, &bft.Options{RequestTimeout: "5s"})
+			profile.completeInitialization(devConfigDir)
+
+			assert.Equal(t, "5s", profile.Orderer.BFT.Options.RequestTimeout)
+			assert.Equal(t, genesisDefaults.Orderer.BFT.Options.ViewChangeTimeout, profile.Orderer.BFT.Options.ViewChangeTimeout,
+				"ViewChangeTimeout should be set to the default value")
+			assert.Equal(t, []byte(filepath.Join(devConfigDir, "path/to/identity")), profile.Orderer.BFT.Consenters[0].Identity,
+				"Identity path should be translated relative to the config directory")
+		})
+	})
 }
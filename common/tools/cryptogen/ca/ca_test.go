@@ -6,17 +6,19 @@ SPDX-License-Identifier: Apache-2.0
 package ca_test
 
 import (
+	"crypto/ecdsa"
 	"crypto/x509"
-	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
-	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"net"
 	"os"
 	"path/filepath"
 	"testing"
 
-	"github.com/stretchr/testify/assert"
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
+	"github.com/hyperledger/fabric/bccsp/gm"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -37,7 +39,7 @@ const (
 
 var testDir = filepath.Join(os.TempDir(), "ca-test")
 
-func TestLoadCertificateGM(t *testing.T) {
+func TestLoadCertificateECDSA(t *testing.T) {
 	caDir := filepath.Join(testDir, "ca")
 	certDir := filepath.Join(testDir, "certs")
 	// generate private key
@@ -45,7 +47,7 @@ func TestLoadCertificateGM(t *testing.T) {
 	assert.NoError(t, err, "Failed to generate signed certificate")
 
 	// get EC public key
-	ecPubKey, err := csp.GetSM2PublicKey(priv)
+	ecPubKey, err := csp.GetECPublicKey(priv)
 	assert.NoError(t, err, "Failed to generate signed certificate")
 	assert.NotNil(t, ecPubKey, "Failed to generate signed certificate")
 
@@ -58,11 +60,42 @@ func TestLoadCertificateGM(t *testing.T) {
 		[]x509.ExtKeyUsage{x509.ExtKeyUsageAny})
 	assert.NoError(t, err, "Failed to generate signed certificate")
 	// KeyUsage should be x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
-	assert.Equal(t, x509GM.KeyUsageDigitalSignature|x509GM.KeyUsageKeyEncipherment,
+	assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment,
+		cert.KeyUsage)
+	assert.Contains(t, cert.ExtKeyUsage, x509.ExtKeyUsageAny)
+
+	loadedCert, err := ca.LoadCertificateECDSA(certDir)
+	assert.NotNil(t, loadedCert, "Should load cert")
+	assert.Equal(t, cert.SerialNumber, loadedCert.SerialNumber, "Should have same serial number")
+	assert.Equal(t, cert.Subject.CommonName, loadedCert.Subject.CommonName, "Should have same CN")
+	cleanup(testDir)
+}
+
+func TestLoadCertificateSM2(t *testing.T) {
+	caDir := filepath.Join(testDir, "ca")
+	certDir := filepath.Join(testDir, "certs")
+	// generate private key
+	priv, _, err := csp.GenerateSM2PrivateKey(certDir)
+	assert.NoError(t, err, "Failed to generate signed certificate")
+
+	// get SM2 public key
+	sm2PubKey, err := csp.GetSM2PublicKey(priv)
+	assert.NoError(t, err, "Failed to generate signed certificate")
+	assert.NotNil(t, sm2PubKey, "Failed to generate signed certificate")
+
+	// create our CA
+	rootCA, err := ca.NewGMCA(caDir, testCA3Name, testCA3Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	cert, err := rootCA.SignGMCertificate(certDir, testName3, nil, nil, sm2PubKey,
+		x509GM.KeyUsageDigitalSignature|x509GM.KeyUsageKeyEncipherment,
+		[]x509GM.ExtKeyUsage{x509GM.ExtKeyUsageAny})
+	assert.NoError(t, err, "Failed to generate signed certificate")
+	// KeyUsage should be x509GM.KeyUsageDigitalSignature | x509GM.KeyUsageKeyEncipherment
+	assert.Equal(t, x509.KeyUsage(x509GM.KeyUsageDigitalSignature|x509GM.KeyUsageKeyEncipherment),
 		cert.KeyUsage)
-	assert.Contains(t, cert.ExtKeyUsage, x509GM.ExtKeyUsageAny)
 
-	loadedCert, err := ca.LoadCertificateGMSM2(certDir)
+	loadedCert, err := ca.LoadCertificateSM2(certDir)
 	assert.NotNil(t, loadedCert, "Should load cert")
 	assert.Equal(t, cert.SerialNumber, loadedCert.SerialNumber, "Should have same serial number")
 	assert.Equal(t, cert.Subject.CommonName, loadedCert.Subject.CommonName, "Should have same CN")
@@ -75,33 +108,78 @@ func TestNewCA(t *testing.T) {
 	rootCA, err := ca.NewCA(caDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
 	assert.NoError(t, err, "Error generating CA")
 	assert.NotNil(t, rootCA, "Failed to return CA")
-	assert.NotNil(t, rootCA.Sm2Key,
-		"rootCA.Sm2Key should not be empty")
-	assert.IsType(t, &x509GM.Certificate{}, rootCA.SignSm2Cert,
-		"rootCA.SignCert should be type x509GM.Certificate")
+	assert.NotNil(t, rootCA.Signer,
+		"rootCA.Signer should not be empty")
+	assert.IsType(t, &x509.Certificate{}, rootCA.SignCert,
+		"rootCA.SignCert should be type x509.Certificate")
+	assert.IsType(t, &ecdsa.PublicKey{}, rootCA.Signer.Public(),
+		"rootCA.Signer should hold an ECDSA key")
+
+	// check to make sure the root public key was stored
+	pemFile := filepath.Join(caDir, testCAName+"-cert.pem")
+	assert.Equal(t, true, checkForFile(pemFile),
+		"Expected to find file "+pemFile)
+
+	assert.NotEmpty(t, rootCA.SignCert.Subject.Country, "country cannot be empty.")
+	assert.Equal(t, testCountry, rootCA.SignCert.Subject.Country[0], "Failed to match country")
+	assert.NotEmpty(t, rootCA.SignCert.Subject.Province, "province cannot be empty.")
+	assert.Equal(t, testProvince, rootCA.SignCert.Subject.Province[0], "Failed to match province")
+	assert.NotEmpty(t, rootCA.SignCert.Subject.Locality, "locality cannot be empty.")
+	assert.Equal(t, testLocality, rootCA.SignCert.Subject.Locality[0], "Failed to match locality")
+	assert.NotEmpty(t, rootCA.SignCert.Subject.OrganizationalUnit, "organizationalUnit cannot be empty.")
+	assert.Equal(t, testOrganizationalUnit, rootCA.SignCert.Subject.OrganizationalUnit[0], "Failed to match organizationalUnit")
+	assert.NotEmpty(t, rootCA.SignCert.Subject.StreetAddress, "streetAddress cannot be empty.")
+	assert.Equal(t, testStreetAddress, rootCA.SignCert.Subject.StreetAddress[0], "Failed to match streetAddress")
+	assert.NotEmpty(t, rootCA.SignCert.Subject.PostalCode, "postalCode cannot be empty.")
+	assert.Equal(t, testPostalCode, rootCA.SignCert.Subject.PostalCode[0], "Failed to match postalCode")
+
+	cleanup(testDir)
+
+}
+
+func TestNewGMCA(t *testing.T) {
+
+	caDir := filepath.Join(testDir, "ca")
+	rootCA, err := ca.NewGMCA(caDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	assert.NotNil(t, rootCA, "Failed to return CA")
+	assert.NotNil(t, rootCA.Signer,
+		"rootCA.Signer should not be empty")
+	assert.IsType(t, &sm2.PublicKey{}, rootCA.Signer.Public(),
+		"rootCA.Signer should hold an SM2 key")
+	assert.True(t, gm.IsX509SM2Certificate(rootCA.SignCert),
+		"rootCA.SignCert should be an SM2 certificate")
 
 	// check to make sure the root public key was stored
 	pemFile := filepath.Join(caDir, testCAName+"-cert.pem")
 	assert.Equal(t, true, checkForFile(pemFile),
 		"Expected to find file "+pemFile)
 
-	assert.NotEmpty(t, rootCA.SignSm2Cert.Subject.Country, "country cannot be empty.")
-	assert.Equal(t, testCountry, rootCA.SignSm2Cert.Subject.Country[0], "Failed to match country")
-	assert.NotEmpty(t, rootCA.SignSm2Cert.Subject.Province, "province cannot be empty.")
-	assert.Equal(t, testProvince, rootCA.SignSm2Cert.Subject.Province[0], "Failed to match province")
-	assert.NotEmpty(t, rootCA.SignSm2Cert.Subject.Locality, "locality cannot be empty.")
-	assert.Equal(t, testLocality, rootCA.SignSm2Cert.Subject.Locality[0], "Failed to match locality")
-	assert.NotEmpty(t, rootCA.SignSm2Cert.Subject.OrganizationalUnit, "organizationalUnit cannot be empty.")
-	assert.Equal(t, testOrganizationalUnit, rootCA.SignSm2Cert.Subject.OrganizationalUnit[0], "Failed to match organizationalUnit")
-	assert.NotEmpty(t, rootCA.SignSm2Cert.Subject.StreetAddress, "streetAddress cannot be empty.")
-	assert.Equal(t, testStreetAddress, rootCA.SignSm2Cert.Subject.StreetAddress[0], "Failed to match streetAddress")
-	assert.NotEmpty(t, rootCA.SignSm2Cert.Subject.PostalCode, "postalCode cannot be empty.")
-	assert.Equal(t, testPostalCode, rootCA.SignSm2Cert.Subject.PostalCode[0], "Failed to match postalCode")
+	assert.NotEmpty(t, rootCA.SignCert.Subject.Country, "country cannot be empty.")
+	assert.Equal(t, testCountry, rootCA.SignCert.Subject.Country[0], "Failed to match country")
+	assert.NotEmpty(t, rootCA.SignCert.Subject.PostalCode, "postalCode cannot be empty.")
+	assert.Equal(t, testPostalCode, rootCA.SignCert.Subject.PostalCode[0], "Failed to match postalCode")
 
 	cleanup(testDir)
 
 }
 
+func TestNewCAWithAlgorithm(t *testing.T) {
+	defer cleanup(testDir)
+
+	for _, algorithm := range []string{csp.ECDSAP256, csp.ECDSAP384, csp.SM2} {
+		caDir := filepath.Join(testDir, algorithm)
+		rootCA, err := ca.NewCAWithAlgorithm(caDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode, algorithm)
+		assert.NoError(t, err, "Error generating %s CA", algorithm)
+		assert.Equal(t, algorithm, rootCA.Algorithm())
+		assert.Equal(t, true, checkForFile(filepath.Join(caDir, testCAName+"-cert.pem")))
+	}
+
+	_, err := ca.NewCAWithAlgorithm(testDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode, "RSA")
+	assert.EqualError(t, err, "unsupported CA algorithm [RSA]")
+	assert.Equal(t, "", (&ca.CA{}).Algorithm())
+}
+
 func TestGenerateSignCertificate(t *testing.T) {
 
 	caDir := filepath.Join(testDir, "ca")
@@ -111,7 +189,7 @@ func TestGenerateSignCertificate(t *testing.T) {
 	assert.NoError(t, err, "Failed to generate signed certificate")
 
 	// get EC public key
-	ecPubKey, err := csp.GetSM2PublicKey(priv)
+	ecPubKey, err := csp.GetECPublicKey(priv)
 	assert.NoError(t, err, "Failed to generate signed certificate")
 	assert.NotNil(t, ecPubKey, "Failed to generate signed certificate")
 
@@ -122,17 +200,15 @@ func TestGenerateSignCertificate(t *testing.T) {
 	cert, err := rootCA.SignCertificate(certDir, testName, nil, nil, ecPubKey,
 		x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment,
 		[]x509.ExtKeyUsage{x509.ExtKeyUsageAny})
+	assert.NoError(t, err, "Failed to generate signed certificate")
 
-	rootCert := rootCA.SignSm2Cert
-
-	err = cert.CheckSignatureFrom(rootCert)
+	err = cert.CheckSignatureFrom(rootCA.SignCert)
 	assert.NoError(t, err, "Failed to check signed certificate")
 
-	//assert.NoError(t, err, "Failed to generate signed certificate")
 	// KeyUsage should be x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
-	assert.Equal(t, x509GM.KeyUsage(x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment),
+	assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment,
 		cert.KeyUsage)
-	assert.Contains(t, cert.ExtKeyUsage, x509GM.ExtKeyUsageAny)
+	assert.Contains(t, cert.ExtKeyUsage, x509.ExtKeyUsageAny)
 
 	cert, err = rootCA.SignCertificate(certDir, testName, nil, nil, ecPubKey,
 		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
@@ -143,6 +219,7 @@ func TestGenerateSignCertificate(t *testing.T) {
 	ous := []string{"TestOU", "PeerOU"}
 	cert, err = rootCA.SignCertificate(certDir, testName, ous, nil, ecPubKey,
 		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+	assert.NoError(t, err, "Failed to generate signed certificate")
 	assert.Contains(t, cert.Subject.OrganizationalUnit, ous[0])
 	assert.Contains(t, cert.Subject.OrganizationalUnit, ous[1])
 
@@ -150,6 +227,7 @@ func TestGenerateSignCertificate(t *testing.T) {
 	sans := []string{testName2, testIP}
 	cert, err = rootCA.SignCertificate(certDir, testName, nil, sans, ecPubKey,
 		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+	assert.NoError(t, err, "Failed to generate signed certificate")
 	assert.Contains(t, cert.DNSNames, testName2)
 	assert.Contains(t, cert.IPAddresses, net.ParseIP(testIP).To4())
 
@@ -167,13 +245,57 @@ func TestGenerateSignCertificate(t *testing.T) {
 		Name:     "badCA",
 		SignCert: &x509.Certificate{},
 	}
-	_, err = badCA.SignCertificate(certDir, testName, nil, nil, &sm2.PublicKey{},
+	_, err = badCA.SignCertificate(certDir, testName, nil, nil, &ecdsa.PublicKey{},
 		x509.KeyUsageKeyEncipherment, []x509.ExtKeyUsage{x509.ExtKeyUsageAny})
 	assert.Error(t, err, "Empty CA should not be able to sign")
 	cleanup(testDir)
 
 }
 
+func TestGenerateSignGMCertificate(t *testing.T) {
+
+	caDir := filepath.Join(testDir, "ca")
+	certDir := filepath.Join(testDir, "certs")
+	// generate private key
+	priv, _, err := csp.GenerateSM2PrivateKey(certDir)
+	assert.NoError(t, err, "Failed to generate signed certificate")
+
+	// get SM2 public key
+	sm2PubKey, err := csp.GetSM2PublicKey(priv)
+	assert.NoError(t, err, "Failed to generate signed certificate")
+	assert.NotNil(t, sm2PubKey, "Failed to generate signed certificate")
+
+	// create our CA
+	rootCA, err := ca.NewGMCA(caDir, testCA2Name, testCA2Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	// make sure ous and sans are correctly set
+	ous := []string{"TestOU", "PeerOU"}
+	sans := []string{testName2, testIP}
+	cert, err := rootCA.SignGMCertificate(certDir, testName, ous, sans, sm2PubKey,
+		x509GM.KeyUsageDigitalSignature, []x509GM.ExtKeyUsage{})
+	assert.NoError(t, err, "Failed to generate signed certificate")
+	assert.True(t, gm.IsX509SM2Certificate(cert), "Should be an SM2 certificate")
+	assert.Contains(t, cert.Subject.OrganizationalUnit, ous[0])
+	assert.Contains(t, cert.Subject.OrganizationalUnit, ous[1])
+	assert.Contains(t, cert.DNSNames, testName2)
+	assert.Contains(t, cert.IPAddresses, net.ParseIP(testIP).To4())
+
+	err = gm.ParseX509Certificate2Sm2(cert).CheckSignatureFrom(gm.ParseX509Certificate2Sm2(rootCA.SignCert))
+	assert.NoError(t, err, "Failed to check signed certificate")
+
+	// check to make sure the signed public key was stored
+	pemFile := filepath.Join(certDir, testName+"-cert.pem")
+	assert.Equal(t, true, checkForFile(pemFile),
+		"Expected to find file "+pemFile)
+
+	_, err = rootCA.SignGMCertificate(certDir, "empty/CA", nil, nil, sm2PubKey,
+		x509GM.KeyUsageKeyEncipherment, []x509GM.ExtKeyUsage{x509GM.ExtKeyUsageAny})
+	assert.Error(t, err, "Bad name should fail")
+	cleanup(testDir)
+
+}
+
 func cleanup(dir string) {
 	os.RemoveAll(dir)
 }
@@ -8,6 +8,7 @@ package ca
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -20,8 +21,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hyperledger/fabric/bccsp/gm"
 	"github.com/hyperledger/fabric/bccsp/utils"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
+	"github.com/pkg/errors"
 )
 
 type CA struct {
@@ -40,13 +43,31 @@ type CA struct {
 // NewCA creates an instance of CA and saves the signing key pair in
 // baseDir/name
 func NewCA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode string) (*CA, error) {
+	return newECDSACA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, csp.ECDSAP256)
+}
+
+// NewCAWithAlgorithm creates an instance of CA whose signing key pair, saved
+// in baseDir/name, uses algorithm, one of csp.ECDSAP256, csp.ECDSAP384 or
+// csp.SM2
+func NewCAWithAlgorithm(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, algorithm string) (*CA, error) {
+	switch strings.ToUpper(algorithm) {
+	case csp.SM2:
+		return NewGMCA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode)
+	case "", csp.ECDSAP256, csp.ECDSAP384:
+		return newECDSACA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, algorithm)
+	default:
+		return nil, errors.Errorf("unsupported CA algorithm [%s]", algorithm)
+	}
+}
+
+func newECDSACA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, algorithm string) (*CA, error) {
 
 	var response error
 	var ca *CA
 
 	err := os.MkdirAll(baseDir, 0755)
 	if err == nil {
-		priv, signer, err := csp.GeneratePrivateKey(baseDir)
+		priv, signer, err := csp.GenerateKey(baseDir, &csp.KeyOpts{Algorithm: algorithm})
 		response = err
 		if err == nil {
 			// get public signing certificate
@@ -94,6 +115,22 @@ func NewCA(baseDir, org, name, country, province, locality, orgUnit, streetAddre
 	return ca, response
 }
 
+// Algorithm returns the algorithm of the signing key of the CA, one of
+// csp.ECDSAP256, csp.ECDSAP384 or csp.SM2, or an empty string when it has
+// no signing certificate
+func (ca *CA) Algorithm() string {
+	if ca.SignCert == nil {
+		return ""
+	}
+	if gm.IsX509SM2Certificate(ca.SignCert) {
+		return csp.SM2
+	}
+	if pub, ok := ca.SignCert.PublicKey.(*ecdsa.PublicKey); ok && pub.Curve == elliptic.P384() {
+		return csp.ECDSAP384
+	}
+	return csp.ECDSAP256
+}
+
 // SignCertificate creates a signed certificate based on a built-in template
 // and saves it in baseDir/name
 func (ca *CA) SignCertificate(baseDir, name string, ous, sans []string, pub *ecdsa.PublicKey,
@@ -7,6 +7,7 @@ package csp_test
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"encoding/hex"
 	"errors"
 	"os"
@@ -14,6 +15,8 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/bccsp/gm"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
 	"github.com/stretchr/testify/assert"
 )
@@ -132,6 +135,84 @@ func TestGetECPublicKey(t *testing.T) {
 	cleanup(testDir)
 }
 
+func TestGenerateKey(t *testing.T) {
+	defer cleanup(testDir)
+
+	for _, test := range []struct {
+		algorithm string
+		curve     elliptic.Curve
+	}{
+		{"", elliptic.P256()},
+		{csp.ECDSAP256, elliptic.P256()},
+		{csp.ECDSAP384, elliptic.P384()},
+		{"ecdsa-p384", elliptic.P384()},
+	} {
+		priv, signer, err := csp.GenerateKey(testDir, &csp.KeyOpts{Algorithm: test.algorithm})
+		assert.NoError(t, err, "Failed to generate %s key", test.algorithm)
+		assert.NotNil(t, signer)
+		assert.True(t, checkForFile(filepath.Join(testDir, hex.EncodeToString(priv.SKI())+"_sk")))
+		ecPubKey, err := csp.GetECPublicKey(priv)
+		assert.NoError(t, err)
+		assert.Equal(t, test.curve, ecPubKey.Curve)
+	}
+
+	priv, _, err := csp.GenerateKey(testDir, &csp.KeyOpts{Algorithm: csp.SM2})
+	assert.NoError(t, err, "Failed to generate SM2 key")
+	assert.True(t, checkForFile(filepath.Join(testDir, hex.EncodeToString(priv.SKI())+"_sk")))
+	_, err = csp.GetSM2PublicKey(priv)
+	assert.NoError(t, err)
+
+	// keys held by a KMS are referenced by the keystore
+	kmsOpts := &csp.KeyOpts{
+		Algorithm: csp.SM2,
+		KeyStore: &csp.KeyStoreOpts{
+			Type: csp.KMSKeyStore,
+			KMS:  &factory.KMSOpts{Driver: gm.InProcessKMSDriverName},
+		},
+	}
+	priv, _, err = csp.GenerateKey(testDir, kmsOpts)
+	assert.NoError(t, err, "Failed to generate KMS key")
+	assert.True(t, checkForFile(filepath.Join(testDir, hex.EncodeToString(priv.SKI())+"_kms")))
+	assert.False(t, checkForFile(filepath.Join(testDir, hex.EncodeToString(priv.SKI())+"_sk")))
+	_, err = csp.GetSM2PublicKey(priv)
+	assert.NoError(t, err)
+
+	_, _, err = csp.GenerateKey(testDir, &csp.KeyOpts{Algorithm: "RSA"})
+	assert.EqualError(t, err, "unsupported algorithm [RSA], must be one of ECDSA-P256, ECDSA-P384 or SM2")
+}
+
+func TestValidateKeyOpts(t *testing.T) {
+	pkcs11 := &csp.KeyStoreOpts{Type: csp.PKCS11KeyStore, PKCS11: &csp.PKCS11Opts{Library: "lib.so"}}
+	kms := &csp.KeyStoreOpts{Type: csp.KMSKeyStore, KMS: &factory.KMSOpts{Driver: gm.InProcessKMSDriverName}}
+
+	for _, test := range []struct {
+		name   string
+		opts   *csp.KeyOpts
+		errMsg string
+	}{
+		{"nil", nil, ""},
+		{"default", &csp.KeyOpts{}, ""},
+		{"file", &csp.KeyOpts{Algorithm: csp.SM2, KeyStore: &csp.KeyStoreOpts{Type: "file"}}, ""},
+		{"pkcs11", &csp.KeyOpts{Algorithm: csp.ECDSAP384, KeyStore: pkcs11}, ""},
+		{"kms", &csp.KeyOpts{Algorithm: csp.SM2, KeyStore: kms}, ""},
+		{"bad algorithm", &csp.KeyOpts{Algorithm: "ECDSA-P521"}, "unsupported algorithm [ECDSA-P521], must be one of ECDSA-P256, ECDSA-P384 or SM2"},
+		{"bad key store", &csp.KeyOpts{KeyStore: &csp.KeyStoreOpts{Type: "vault"}}, "unsupported key store [vault], must be one of File, PKCS11 or KMS"},
+		{"sm2 pkcs11", &csp.KeyOpts{Algorithm: csp.SM2, KeyStore: pkcs11}, "SM2 keys cannot be stored with PKCS11"},
+		{"ecdsa kms", &csp.KeyOpts{Algorithm: csp.ECDSAP256, KeyStore: kms}, "only SM2 keys can be stored in a KMS"},
+		{"missing pkcs11", &csp.KeyOpts{KeyStore: &csp.KeyStoreOpts{Type: csp.PKCS11KeyStore}}, "PKCS11 key store requires the PKCS11 options"},
+		{"missing kms", &csp.KeyOpts{Algorithm: csp.SM2, KeyStore: &csp.KeyStoreOpts{Type: csp.KMSKeyStore}}, "KMS key store requires the KMS options"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := csp.ValidateKeyOpts(test.opts)
+			if test.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.errMsg)
+			}
+		})
+	}
+}
+
 func cleanup(dir string) {
 	os.RemoveAll(dir)
 }
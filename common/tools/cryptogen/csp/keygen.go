@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package csp
+
+import (
+	"crypto"
+	"strings"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/bccsp/signer"
+	"github.com/pkg/errors"
+)
+
+// Algorithms of the keys generated by cryptogen
+const (
+	ECDSAP256 = "ECDSA-P256"
+	ECDSAP384 = "ECDSA-P384"
+	SM2       = "SM2"
+)
+
+// Targets storing the private keys generated by cryptogen
+const (
+	// FileKeyStore stores the keys as PEM files in the keystore folder
+	FileKeyStore = "File"
+	// PKCS11KeyStore stores the keys in an HSM, nothing is written to the
+	// keystore folder
+	PKCS11KeyStore = "PKCS11"
+	// KMSKeyStore stores the keys in the KMS of the GM provider, the
+	// keystore folder only holds their KMS identifiers
+	KMSKeyStore = "KMS"
+)
+
+// KeyStoreOpts selects where private keys are stored
+type KeyStoreOpts struct {
+	// Type is one of File (default), PKCS11 or KMS
+	Type string `yaml:"Type"`
+	// PKCS11 configures the HSM of the PKCS11 key store
+	PKCS11 *PKCS11Opts `yaml:"PKCS11"`
+	// KMS configures the KMS driver of the KMS key store
+	KMS *factory.KMSOpts `yaml:"KMS"`
+}
+
+// PKCS11Opts contains the options to access an HSM through PKCS11
+type PKCS11Opts struct {
+	Library string `yaml:"Library"`
+	Label   string `yaml:"Label"`
+	Pin     string `yaml:"Pin"`
+}
+
+// KeyOpts selects the algorithm of a private key and where it is stored
+type KeyOpts struct {
+	// Algorithm is one of ECDSA-P256 (default), ECDSA-P384 or SM2
+	Algorithm string
+	// KeyStore defaults to the file key store when nil
+	KeyStore *KeyStoreOpts
+}
+
+// IsSM2 returns true if the options select an SM2 key
+func (opts *KeyOpts) IsSM2() bool {
+	return opts != nil && strings.EqualFold(opts.Algorithm, SM2)
+}
+
+func (opts *KeyOpts) keyStoreType() string {
+	if opts == nil || opts.KeyStore == nil || opts.KeyStore.Type == "" {
+		return FileKeyStore
+	}
+	return opts.KeyStore.Type
+}
+
+// ValidateKeyOpts checks that the algorithm and the key store of opts are
+// supported and can be combined. An empty algorithm is accepted and stands
+// for the default one.
+func ValidateKeyOpts(opts *KeyOpts) error {
+	if opts == nil {
+		return nil
+	}
+
+	switch strings.ToUpper(opts.Algorithm) {
+	case "", ECDSAP256, ECDSAP384, SM2:
+	default:
+		return errors.Errorf("unsupported algorithm [%s], must be one of %s, %s or %s",
+			opts.Algorithm, ECDSAP256, ECDSAP384, SM2)
+	}
+
+	switch ks := opts.keyStoreType(); {
+	case strings.EqualFold(ks, FileKeyStore):
+	case strings.EqualFold(ks, PKCS11KeyStore):
+		if opts.IsSM2() {
+			return errors.New("SM2 keys cannot be stored with PKCS11")
+		}
+		if opts.KeyStore.PKCS11 == nil {
+			return errors.New("PKCS11 key store requires the PKCS11 options")
+		}
+	case strings.EqualFold(ks, KMSKeyStore):
+		if !opts.IsSM2() {
+			return errors.New("only SM2 keys can be stored in a KMS")
+		}
+		if opts.KeyStore.KMS == nil {
+			return errors.New("KMS key store requires the KMS options")
+		}
+	default:
+		return errors.Errorf("unsupported key store [%s], must be one of %s, %s or %s",
+			ks, FileKeyStore, PKCS11KeyStore, KMSKeyStore)
+	}
+
+	return nil
+}
+
+// GenerateKey creates a private key as selected by opts. Keys stored in
+// files are written to keystorePath.
+func GenerateKey(keystorePath string, opts *KeyOpts) (bccsp.Key, crypto.Signer, error) {
+	err := ValidateKeyOpts(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ks := opts.keyStoreType()
+	switch {
+	case strings.EqualFold(ks, PKCS11KeyStore):
+		csp, err := newPKCS11CSP(keystorePath, opts.KeyStore.PKCS11)
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "failed initializing PKCS11 key store")
+		}
+		return generateKey(csp, ecdsaKeyGenOpts(opts))
+
+	case strings.EqualFold(ks, KMSKeyStore):
+		csp, err := factory.GetBCCSPFromOpts(&factory.FactoryOpts{
+			ProviderName: "GM",
+			GmOpts: &factory.GmOpts{
+				HashFamily: "GMSM3",
+				SecLevel:   256,
+				KMS:        opts.KeyStore.KMS,
+				FileKeystore: &factory.FileKeystoreOpts{
+					KeyStorePath: keystorePath,
+				},
+			},
+		})
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "failed initializing KMS key store")
+		}
+		return generateKey(csp, &bccsp.KMSGMSM2KeyGenOpts{Temporary: false})
+
+	case opts.IsSM2():
+		return GenerateSM2PrivateKey(keystorePath)
+
+	default:
+		csp, err := factory.GetBCCSPFromOpts(&factory.FactoryOpts{
+			ProviderName: "SW",
+			SwOpts: &factory.SwOpts{
+				HashFamily: "SHA2",
+				SecLevel:   256,
+				FileKeystore: &factory.FileKeystoreOpts{
+					KeyStorePath: keystorePath,
+				},
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return generateKey(csp, ecdsaKeyGenOpts(opts))
+	}
+}
+
+func ecdsaKeyGenOpts(opts *KeyOpts) bccsp.KeyGenOpts {
+	if opts != nil && strings.EqualFold(opts.Algorithm, ECDSAP384) {
+		return &bccsp.ECDSAP384KeyGenOpts{Temporary: false}
+	}
+	return &bccsp.ECDSAP256KeyGenOpts{Temporary: false}
+}
+
+func generateKey(csp bccsp.BCCSP, opts bccsp.KeyGenOpts) (bccsp.Key, crypto.Signer, error) {
+	priv, err := csp.KeyGen(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	s, err := signer.New(csp, priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv, s, nil
+}
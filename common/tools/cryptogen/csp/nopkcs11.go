@@ -0,0 +1,18 @@
+// +build !pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package csp
+
+import (
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+func newPKCS11CSP(keystorePath string, opts *PKCS11Opts) (bccsp.BCCSP, error) {
+	return nil, errors.New("cryptogen must be built with the pkcs11 build tag to store keys with PKCS11")
+}
@@ -0,0 +1,31 @@
+// +build pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package csp
+
+import (
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/bccsp/pkcs11"
+)
+
+func newPKCS11CSP(keystorePath string, opts *PKCS11Opts) (bccsp.BCCSP, error) {
+	return factory.GetBCCSPFromOpts(&factory.FactoryOpts{
+		ProviderName: "PKCS11",
+		Pkcs11Opts: &pkcs11.PKCS11Opts{
+			HashFamily: "SHA2",
+			SecLevel:   256,
+			Library:    opts.Library,
+			Label:      opts.Label,
+			Pin:        opts.Pin,
+			FileKeystore: &pkcs11.FileKeystoreOpts{
+				KeyStorePath: keystorePath,
+			},
+		},
+	})
+}
@@ -7,6 +7,7 @@ package main
 
 import (
 	"bytes"
+	"crypto"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,6 +15,7 @@ import (
 	"path/filepath"
 	"text/template"
 
+	"github.com/hyperledger/fabric/bccsp/gm"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/metadata"
@@ -50,15 +52,17 @@ type NodeTemplate struct {
 
 type NodeSpec struct {
 	isAdmin            bool
-	Hostname           string   `yaml:"Hostname"`
-	CommonName         string   `yaml:"CommonName"`
-	Country            string   `yaml:"Country"`
-	Province           string   `yaml:"Province"`
-	Locality           string   `yaml:"Locality"`
-	OrganizationalUnit string   `yaml:"OrganizationalUnit"`
-	StreetAddress      string   `yaml:"StreetAddress"`
-	PostalCode         string   `yaml:"PostalCode"`
-	SANS               []string `yaml:"SANS"`
+	Hostname           string            `yaml:"Hostname"`
+	CommonName         string            `yaml:"CommonName"`
+	Country            string            `yaml:"Country"`
+	Province           string            `yaml:"Province"`
+	Locality           string            `yaml:"Locality"`
+	OrganizationalUnit string            `yaml:"OrganizationalUnit"`
+	StreetAddress      string            `yaml:"StreetAddress"`
+	PostalCode         string            `yaml:"PostalCode"`
+	SANS               []string          `yaml:"SANS"`
+	Algorithm          string            `yaml:"Algorithm"`
+	KeyStore           *csp.KeyStoreOpts `yaml:"KeyStore"`
 }
 
 type UsersSpec struct {
@@ -66,13 +70,15 @@ type UsersSpec struct {
 }
 
 type OrgSpec struct {
-	Name          string       `yaml:"Name"`
-	Domain        string       `yaml:"Domain"`
-	EnableNodeOUs bool         `yaml:"EnableNodeOUs"`
-	CA            NodeSpec     `yaml:"CA"`
-	Template      NodeTemplate `yaml:"Template"`
-	Specs         []NodeSpec   `yaml:"Specs"`
-	Users         UsersSpec    `yaml:"Users"`
+	Name          string            `yaml:"Name"`
+	Domain        string            `yaml:"Domain"`
+	EnableNodeOUs bool              `yaml:"EnableNodeOUs"`
+	Algorithm     string            `yaml:"Algorithm"`
+	KeyStore      *csp.KeyStoreOpts `yaml:"KeyStore"`
+	CA            NodeSpec          `yaml:"CA"`
+	Template      NodeTemplate      `yaml:"Template"`
+	Specs         []NodeSpec        `yaml:"Specs"`
+	Users         UsersSpec         `yaml:"Users"`
 }
 
 type Config struct {
@@ -109,6 +115,39 @@ PeerOrgs:
     Domain: org1.example.com
     EnableNodeOUs: false
 
+    # ---------------------------------------------------------------------------
+    # "Algorithm"
+    # ---------------------------------------------------------------------------
+    # Uncomment to select the algorithm of the CAs and keys of this organization:
+    # ECDSA-P256, ECDSA-P384 or SM2.  By default, organizations use SM2 when
+    # cryptogen generate is run with --gm and ECDSA-P256 otherwise, and
+    # organizations extended by cryptogen extend keep the algorithm of their CA.
+    # Nodes may override it with the "Algorithm" of their Spec, within the same
+    # family as the CA: ECDSA nodes for ECDSA CAs, SM2 nodes for SM2 CAs.
+    # ---------------------------------------------------------------------------
+    # Algorithm: ECDSA-P256
+
+    # ---------------------------------------------------------------------------
+    # "KeyStore"
+    # ---------------------------------------------------------------------------
+    # Uncomment to select where the signing keys of the nodes and users of this
+    # organization are stored: File (default), PKCS11 (ECDSA keys, requires
+    # cryptogen built with the pkcs11 tag) or KMS (SM2 keys, through a KMS
+    # driver of the GM provider).  The keys of the CAs and the TLS keys are
+    # always stored in files.  Nodes may override it with the "KeyStore" of
+    # their Spec.
+    # ---------------------------------------------------------------------------
+    # KeyStore:
+    #   Type: PKCS11
+    #   PKCS11:
+    #     Library: /usr/lib/softhsm/libsofthsm2.so
+    #     Label: ForFabric
+    #     Pin: 98765432
+    #   KMS:
+    #     Driver: aliyun
+    #     Config:
+    #       region: cn-hangzhou
+
     # ---------------------------------------------------------------------------
     # "CA"
     # ---------------------------------------------------------------------------
@@ -147,6 +186,10 @@ PeerOrgs:
     #                 NOTE: Two implicit entries are created for you:
     #                     - {{ .CommonName }}
     #                     - {{ .Hostname }}
+    #   - Algorithm:  (Optional) Overrides the algorithm of the organization
+    #                 for the keys of this node.
+    #   - KeyStore:   (Optional) Overrides the key store of the organization
+    #                 for the signing key of this node.
     # ---------------------------------------------------------------------------
     # Specs:
     #   - Hostname: foo # implicitly "foo.org1.example.com"
@@ -157,6 +200,7 @@ PeerOrgs:
     #       - "{{.Hostname}}.org6.net"
     #       - 172.16.10.31
     #   - Hostname: bar
+    #     Algorithm: ECDSA-P384
     #   - Hostname: baz
 
     # ---------------------------------------------------------------------------
@@ -192,6 +236,7 @@ PeerOrgs:
   - Name: Org2
     Domain: org2.example.com
     EnableNodeOUs: false
+    # Algorithm: SM2
     Template:
       Count: 1
     Users:
@@ -205,7 +250,7 @@ var (
 	gen           = app.Command("generate", "Generate key material")
 	outputDir     = gen.Flag("output", "The output directory in which to place artifacts").Default("crypto-config").String()
 	genConfigFile = gen.Flag("config", "The configuration template to use").File()
-	genUseGM      = gen.Flag("gm", "Use GM crypto suite").Bool()
+	genUseGM      = gen.Flag("gm", "Use GM crypto suite for the organizations not selecting an Algorithm").Bool()
 
 	showtemplate = app.Command("showtemplate", "Show the default configuration template")
 
@@ -310,8 +355,11 @@ func extendPeerOrg(orgSpec OrgSpec) {
 
 	signCA := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
 	tlsCA := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	if orgSpec.Algorithm == "" {
+		orgSpec.Algorithm = signCA.Algorithm()
+	}
 
-	generateNodes(peersDir, orgSpec.Specs, signCA, tlsCA, msp.PEER, orgSpec.EnableNodeOUs)
+	generateNodes(peersDir, orgSpec.Specs, signCA, tlsCA, msp.PEER, orgSpec)
 
 	adminUser := NodeSpec{
 		CommonName: fmt.Sprintf("%s@%s", adminBaseName, orgName),
@@ -340,7 +388,7 @@ func extendPeerOrg(orgSpec OrgSpec) {
 		users = append(users, user)
 	}
 
-	generateNodes(usersDir, users, signCA, tlsCA, msp.CLIENT, orgSpec.EnableNodeOUs)
+	generateNodes(usersDir, users, signCA, tlsCA, msp.CLIENT, orgSpec)
 }
 
 func extendOrdererOrg(orgSpec OrgSpec) {
@@ -358,8 +406,11 @@ func extendOrdererOrg(orgSpec OrgSpec) {
 
 	signCA := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
 	tlsCA := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	if orgSpec.Algorithm == "" {
+		orgSpec.Algorithm = signCA.Algorithm()
+	}
 
-	generateNodes(orderersDir, orgSpec.Specs, signCA, tlsCA, msp.ORDERER, orgSpec.EnableNodeOUs)
+	generateNodes(orderersDir, orgSpec.Specs, signCA, tlsCA, msp.ORDERER, orgSpec)
 
 	adminUser := NodeSpec{
 		CommonName: fmt.Sprintf("%s@%s", adminBaseName, orgName),
@@ -523,27 +574,23 @@ func generatePeerOrg(baseDir string, orgSpec OrgSpec) {
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
 
+	if orgSpec.Algorithm == "" {
+		orgSpec.Algorithm = defaultAlgorithm()
+	}
+
 	var err error
 	var signCA *ca.CA
 	var tlsCA *ca.CA
 
 	// generate signing CA
-	if *genUseGM {
-		signCA, err = ca.NewGMCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-	} else {
-		signCA, err = ca.NewCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-	}
+	signCA, err = ca.NewCAWithAlgorithm(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode, orgSpec.Algorithm)
 
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
 	}
 	// generate TLS CA
-	if *genUseGM {
-		tlsCA, err = ca.NewGMCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-	} else {
-		tlsCA, err = ca.NewCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-	}
+	tlsCA, err = ca.NewCAWithAlgorithm(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode, orgSpec.Algorithm)
 	if err != nil {
 		fmt.Printf("Error generating tlsCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
@@ -555,7 +602,7 @@ func generatePeerOrg(baseDir string, orgSpec OrgSpec) {
 		os.Exit(1)
 	}
 
-	generateNodes(peersDir, orgSpec.Specs, signCA, tlsCA, msp.PEER, orgSpec.EnableNodeOUs)
+	generateNodes(peersDir, orgSpec.Specs, signCA, tlsCA, msp.PEER, orgSpec)
 
 	// TODO: add ability to specify usernames
 	users := []NodeSpec{}
@@ -573,7 +620,7 @@ func generatePeerOrg(baseDir string, orgSpec OrgSpec) {
 	}
 
 	users = append(users, adminUser)
-	generateNodes(usersDir, users, signCA, tlsCA, msp.CLIENT, orgSpec.EnableNodeOUs)
+	generateNodes(usersDir, users, signCA, tlsCA, msp.CLIENT, orgSpec)
 
 	// copy the admin cert to the org's MSP admincerts
 	if !orgSpec.EnableNodeOUs {
@@ -624,16 +671,17 @@ func copyAdminCert(usersDir, adminCertsDir, adminUserName string) error {
 	return nil
 }
 
-func generateNodes(baseDir string, nodes []NodeSpec, signCA *ca.CA, tlsCA *ca.CA, nodeType int, nodeOUs bool) {
+func generateNodes(baseDir string, nodes []NodeSpec, signCA *ca.CA, tlsCA *ca.CA, nodeType int, orgSpec OrgSpec) {
 
 	for _, node := range nodes {
 		nodeDir := filepath.Join(baseDir, node.CommonName)
 		if _, err := os.Stat(nodeDir); os.IsNotExist(err) {
 			currentNodeType := nodeType
-			if node.isAdmin && nodeOUs {
+			if node.isAdmin && orgSpec.EnableNodeOUs {
 				currentNodeType = msp.ADMIN
 			}
-			err := msp.GenerateLocalMSP(nodeDir, node.CommonName, node.SANS, signCA, tlsCA, currentNodeType, nodeOUs)
+			err := msp.GenerateLocalMSPWithKeyOpts(nodeDir, node.CommonName, node.SANS, signCA, tlsCA,
+				currentNodeType, orgSpec.EnableNodeOUs, nodeKeyOpts(orgSpec, node))
 			if err != nil {
 				fmt.Printf("Error generating local MSP for %v:\n%v\n", node, err)
 				os.Exit(1)
@@ -642,6 +690,31 @@ func generateNodes(baseDir string, nodes []NodeSpec, signCA *ca.CA, tlsCA *ca.CA
 	}
 }
 
+// defaultAlgorithm is the algorithm of the organizations which do not
+// select one
+func defaultAlgorithm() string {
+	if *genUseGM {
+		return csp.SM2
+	}
+	return csp.ECDSAP256
+}
+
+// nodeKeyOpts returns the key options of node, inherited from its
+// organization unless the node overrides them
+func nodeKeyOpts(orgSpec OrgSpec, node NodeSpec) *csp.KeyOpts {
+	opts := &csp.KeyOpts{
+		Algorithm: orgSpec.Algorithm,
+		KeyStore:  orgSpec.KeyStore,
+	}
+	if node.Algorithm != "" {
+		opts.Algorithm = node.Algorithm
+	}
+	if node.KeyStore != nil {
+		opts.KeyStore = node.KeyStore
+	}
+	return opts
+}
+
 func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 
 	orgName := orgSpec.Domain
@@ -654,17 +727,16 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 	orderersDir := filepath.Join(orgDir, "orderers")
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
-	// generate signing CA
 
+	if orgSpec.Algorithm == "" {
+		orgSpec.Algorithm = defaultAlgorithm()
+	}
+
+	// generate signing CA
 	var err error
 	var signCA *ca.CA
 
-	if *genUseGM {
-		signCA, err = ca.NewGMCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-
-	} else {
-		signCA, err = ca.NewCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-	}
+	signCA, err = ca.NewCAWithAlgorithm(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode, orgSpec.Algorithm)
 
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
@@ -674,11 +746,7 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 	// generate TLS CA
 	var tlsCA *ca.CA
 
-	if *genUseGM {
-		tlsCA, err = ca.NewGMCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-	} else {
-		tlsCA, err = ca.NewCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
-	}
+	tlsCA, err = ca.NewCAWithAlgorithm(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode, orgSpec.Algorithm)
 
 	if err != nil {
 		fmt.Printf("Error generating tlsCA for org %s:\n%v\n", orgName, err)
@@ -691,7 +759,7 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 		os.Exit(1)
 	}
 
-	generateNodes(orderersDir, orgSpec.Specs, signCA, tlsCA, msp.ORDERER, orgSpec.EnableNodeOUs)
+	generateNodes(orderersDir, orgSpec.Specs, signCA, tlsCA, msp.ORDERER, orgSpec)
 
 	adminUser := NodeSpec{
 		isAdmin:    true,
@@ -702,7 +770,7 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 	users := []NodeSpec{}
 	// add an admin user
 	users = append(users, adminUser)
-	generateNodes(usersDir, users, signCA, tlsCA, msp.CLIENT, orgSpec.EnableNodeOUs)
+	generateNodes(usersDir, users, signCA, tlsCA, msp.CLIENT, orgSpec)
 
 	// copy the admin cert to the org's MSP admincerts
 	if !orgSpec.EnableNodeOUs {
@@ -754,8 +822,15 @@ func printVersion() {
 }
 
 func getCA(caDir string, spec OrgSpec, name string) *ca.CA {
-	_, signer, _ := csp.LoadPrivateKey(caDir)
+	var signer crypto.Signer
 	cert, _ := ca.LoadCertificateECDSA(caDir)
+	if cert != nil {
+		_, signer, _ = csp.LoadPrivateKey(caDir)
+	} else if gmCert, _ := ca.LoadCertificateSM2(caDir); gmCert != nil {
+		// the organization was generated with SM2
+		cert = gm.ParseSm2Certificate2X509(gmCert)
+		_, signer, _ = csp.LoadSM2PrivateKey(caDir)
+	}
 	return &ca.CA{
 		Name:               name,
 		Signer:             signer,
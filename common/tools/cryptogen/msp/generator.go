@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
 	fabricmsp "github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
 
@@ -43,14 +44,43 @@ var nodeOUMap = map[int]string{
 	ORDERER: ORDEREROU,
 }
 
+// GenerateLocalMSP generates a local MSP whose keys use the algorithm of
+// their CA and are stored in files
 func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 	tlsCA *ca.CA, nodeType int, nodeOUs bool) error {
+	return GenerateLocalMSPWithKeyOpts(baseDir, name, sans, signCA, tlsCA, nodeType, nodeOUs, nil)
+}
+
+// GenerateLocalMSPWithKeyOpts generates a local MSP whose signing key is
+// generated as selected by keyOpts. The TLS keys use the same algorithm but
+// are always stored in files, as they are read by the TLS stacks. A nil
+// keyOpts stands for the algorithm of the CAs and the file key store.
+func GenerateLocalMSPWithKeyOpts(baseDir, name string, sans []string, signCA *ca.CA,
+	tlsCA *ca.CA, nodeType int, nodeOUs bool, keyOpts *csp.KeyOpts) error {
+
+	if signCA.SignCert == nil || tlsCA.SignCert == nil {
+		return errors.New("the signing and TLS CAs must have a certificate")
+	}
+	tlsKeyOpts := &csp.KeyOpts{Algorithm: tlsCA.Algorithm()}
+	if keyOpts == nil {
+		keyOpts = &csp.KeyOpts{Algorithm: signCA.Algorithm()}
+	} else {
+		tlsKeyOpts.Algorithm = keyOpts.Algorithm
+	}
+	err := checkKeyAlgorithm(signCA, keyOpts)
+	if err != nil {
+		return err
+	}
+	err = checkKeyAlgorithm(tlsCA, tlsKeyOpts)
+	if err != nil {
+		return err
+	}
 
 	// create folder structure
 	mspDir := filepath.Join(baseDir, "msp")
 	tlsDir := filepath.Join(baseDir, "tls")
 
-	err := createFolderStructure(mspDir, true)
+	err = createFolderStructure(mspDir, true)
 	if err != nil {
 		return err
 	}
@@ -66,54 +96,21 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 	// get keystore path
 	keystore := filepath.Join(mspDir, "keystore")
 
-	var cert *x509.Certificate
-
-	if gm.IsX509SM2Certificate(signCA.SignCert) {
-		priv, _, err := csp.GenerateSM2PrivateKey(keystore)
-		if err != nil {
-			return err
-		}
-
-		// get public key
-		ecPubKey, err := csp.GetSM2PublicKey(priv)
-		if err != nil {
-			return err
-		}
-
-		// generate private key
-		// generate X509 certificate using signing CA
-		var ous []string
-		if nodeOUs {
-			ous = []string{nodeOUMap[nodeType]}
-		}
-		cert, err = signCA.SignGMCertificate(filepath.Join(mspDir, "signcerts"),
-			name, ous, nil, ecPubKey, x509GM.KeyUsageDigitalSignature, []x509GM.ExtKeyUsage{})
-		if err != nil {
-			return err
-		}
-	} else {
-		priv, _, err := csp.GeneratePrivateKey(keystore)
-		if err != nil {
-			return err
-		}
-
-		// get public key
-		ecPubKey, err := csp.GetECPublicKey(priv)
-		if err != nil {
-			return err
-		}
+	// generate private key
+	priv, _, err := csp.GenerateKey(keystore, keyOpts)
+	if err != nil {
+		return err
+	}
 
-		// generate private key
-		// generate X509 certificate using signing CA
-		var ous []string
-		if nodeOUs {
-			ous = []string{nodeOUMap[nodeType]}
-		}
-		cert, err = signCA.SignCertificate(filepath.Join(mspDir, "signcerts"),
-			name, ous, nil, ecPubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
-		if err != nil {
-			return err
-		}
+	// generate X509 certificate using signing CA
+	var ous []string
+	if nodeOUs {
+		ous = []string{nodeOUMap[nodeType]}
+	}
+	cert, err := certifyKey(signCA, filepath.Join(mspDir, "signcerts"),
+		name, ous, nil, priv, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+	if err != nil {
+		return err
 	}
 
 	// write artifacts to MSP folders
@@ -153,40 +150,15 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 		Generate the TLS artifacts in the TLS folder
 	*/
 
-	var tlsPrivKey bccsp.Key
-
-	if gm.IsX509SM2Certificate(tlsCA.SignCert) {
-		// generate private key
-		tlsPrivKey, _, err = csp.GenerateSM2PrivateKey(tlsDir)
-		if err != nil {
-			return err
-		}
-		// get public key
-		tlsPubKey, err := csp.GetSM2PublicKey(tlsPrivKey)
-		if err != nil {
-			return err
-		}
-		// generate X509 certificate using TLS CA
-		_, err = tlsCA.SignGMCertificate(filepath.Join(tlsDir),
-			name, nil, sans, tlsPubKey, x509GM.KeyUsageDigitalSignature|x509GM.KeyUsageKeyEncipherment,
-			[]x509GM.ExtKeyUsage{x509GM.ExtKeyUsageServerAuth, x509GM.ExtKeyUsageClientAuth})
-	} else {
-		// generate private key
-		tlsPrivKey, _, err = csp.GeneratePrivateKey(tlsDir)
-		if err != nil {
-			return err
-		}
-		// get public key
-		tlsPubKey, err := csp.GetECPublicKey(tlsPrivKey)
-		if err != nil {
-			return err
-		}
-		// generate X509 certificate using TLS CA
-		_, err = tlsCA.SignCertificate(filepath.Join(tlsDir),
-			name, nil, sans, tlsPubKey, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment,
-			[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
+	// generate private key
+	tlsPrivKey, _, err := csp.GenerateKey(tlsDir, tlsKeyOpts)
+	if err != nil {
+		return err
 	}
-
+	// generate X509 certificate using TLS CA
+	_, err = certifyKey(tlsCA, filepath.Join(tlsDir),
+		name, nil, sans, tlsPrivKey, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
 	if err != nil {
 		return err
 	}
@@ -211,9 +183,87 @@ func GenerateLocalMSP(baseDir, name string, sans []string, signCA *ca.CA,
 		return err
 	}
 
+	// GM TLS (GM/T 0024) servers use a second key pair for the key exchange
+	if gm.IsX509SM2Certificate(tlsCA.SignCert) && (nodeType == PEER || nodeType == ORDERER) {
+		err = generateGMTLSEncryptionPair(tlsDir, name, sans, tlsCA, tlsFilePrefix)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// checkKeyAlgorithm checks that keys generated with opts can be certified
+// by signCA: SM2 keys by an SM2 CA, ECDSA keys by an ECDSA CA.
+func checkKeyAlgorithm(signCA *ca.CA, opts *csp.KeyOpts) error {
+	err := csp.ValidateKeyOpts(opts)
+	if err != nil {
+		return err
+	}
+	if opts.IsSM2() != (signCA.Algorithm() == csp.SM2) {
+		algorithm := opts.Algorithm
+		if algorithm == "" {
+			algorithm = csp.ECDSAP256
+		}
+		return errors.Errorf("%s keys cannot be certified by CA %s using %s",
+			algorithm, signCA.Name, signCA.Algorithm())
+	}
+	return nil
+}
+
+// certifyKey issues a certificate for the public key of priv with signCA,
+// in the GM format when signCA uses SM2.
+func certifyKey(signCA *ca.CA, baseDir, name string, ous, sans []string, priv bccsp.Key,
+	ku x509.KeyUsage, eku []x509.ExtKeyUsage) (*x509.Certificate, error) {
+
+	if signCA.Algorithm() == csp.SM2 {
+		sm2PubKey, err := csp.GetSM2PublicKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		gmEKU := make([]x509GM.ExtKeyUsage, len(eku))
+		for i, u := range eku {
+			gmEKU[i] = x509GM.ExtKeyUsage(u)
+		}
+		return signCA.SignGMCertificate(baseDir, name, ous, sans, sm2PubKey, x509GM.KeyUsage(ku), gmEKU)
+	}
+
+	ecPubKey, err := csp.GetECPublicKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return signCA.SignCertificate(baseDir, name, ous, sans, ecPubKey, ku, eku)
+}
+
+// generateGMTLSEncryptionPair generates the SM2 key pair used for the key
+// exchange of GM TLS, and saves it as <prefix>-enc.crt and <prefix>-enc.key
+// in tlsDir.
+func generateGMTLSEncryptionPair(tlsDir, name string, sans []string, tlsCA *ca.CA, prefix string) error {
+	encPrivKey, _, err := csp.GenerateSM2PrivateKey(tlsDir)
+	if err != nil {
+		return err
+	}
+	encPubKey, err := csp.GetSM2PublicKey(encPrivKey)
+	if err != nil {
+		return err
+	}
+	_, err = tlsCA.SignGMCertificate(tlsDir, name, nil, sans, encPubKey,
+		x509GM.KeyUsageKeyEncipherment|x509GM.KeyUsageDataEncipherment|x509GM.KeyUsageKeyAgreement,
+		[]x509GM.ExtKeyUsage{x509GM.ExtKeyUsageServerAuth, x509GM.ExtKeyUsageClientAuth})
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(filepath.Join(tlsDir, x509Filename(name)),
+		filepath.Join(tlsDir, prefix+"-enc.crt"))
+	if err != nil {
+		return err
+	}
+
+	return keyExport(tlsDir, filepath.Join(tlsDir, prefix+"-enc.key"), encPrivKey)
+}
+
 func GenerateVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA, nodeOUs bool) error {
 
 	// create folder structure and write artifacts to proper locations
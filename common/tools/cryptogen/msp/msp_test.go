@@ -6,15 +6,24 @@ SPDX-License-Identifier: Apache-2.0
 package msp_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/bccsp/gm"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
+	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/msp"
 	fabricmsp "github.com/hyperledger/fabric/msp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 )
 
@@ -135,6 +144,107 @@ func TestGenerateLocalMSPWithoutNodeOU(t *testing.T) {
 	testGenerateLocalMSP(t, false)
 }
 
+func TestGenerateLocalMSPGMTLSEncryptionPair(t *testing.T) {
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	signCA, err := ca.NewGMCA(filepath.Join(testDir, "ca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewGMCA(filepath.Join(testDir, "tlsca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	tlsDir := filepath.Join(testDir, "tls")
+	encFiles := []string{
+		filepath.Join(tlsDir, "server-enc.crt"),
+		filepath.Join(tlsDir, "server-enc.key"),
+	}
+
+	// nodes get a second TLS key pair for the GM TLS key exchange
+	err = msp.GenerateLocalMSP(testDir, testName, []string{"localhost"}, signCA, tlsCA, msp.PEER, true)
+	assert.NoError(t, err, "Failed to generate local MSP")
+	for _, file := range append(encFiles, filepath.Join(tlsDir, "server.crt"), filepath.Join(tlsDir, "server.key")) {
+		assert.True(t, checkForFile(file), "Expected to find file "+file)
+	}
+	signCert, err := ioutil.ReadFile(filepath.Join(tlsDir, "server.crt"))
+	assert.NoError(t, err)
+	encCert, err := ioutil.ReadFile(filepath.Join(tlsDir, "server-enc.crt"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, signCert, encCert)
+	files, err := ioutil.ReadDir(tlsDir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 5, "no other key or certificate should be left in the TLS folder")
+
+	// clients don't serve GM TLS
+	cleanup(tlsDir)
+	err = msp.GenerateLocalMSP(testDir, testName, nil, signCA, tlsCA, msp.CLIENT, true)
+	assert.NoError(t, err, "Failed to generate local MSP")
+	assert.True(t, checkForFile(filepath.Join(tlsDir, "client.crt")))
+	assert.False(t, checkForFile(filepath.Join(tlsDir, "client-enc.crt")))
+}
+
+func TestGenerateLocalMSPWithKeyOpts(t *testing.T) {
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	signCA, err := ca.NewCA(filepath.Join(testDir, "ca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewCA(filepath.Join(testDir, "tlsca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	gmCA, err := ca.NewGMCA(filepath.Join(testDir, "gmca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	// a node can use another curve than its CA. Its MSP is generated in testDir,
+	// as the BCCSP factories are initialized once with the keystore of testDir
+	nodeDir := testDir
+	err = msp.GenerateLocalMSPWithKeyOpts(nodeDir, testName, nil, signCA, tlsCA, msp.PEER, true, &csp.KeyOpts{Algorithm: csp.ECDSAP384})
+	assert.NoError(t, err, "Failed to generate local MSP")
+	for _, file := range []string{
+		filepath.Join(nodeDir, "msp", "signcerts", testName+"-cert.pem"),
+		filepath.Join(nodeDir, "tls", "server.crt"),
+	} {
+		certPEM, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		block, _ := pem.Decode(certPEM)
+		require.NotNil(t, block, "Expected a PEM encoded certificate in "+file)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+		publicKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+		require.True(t, ok, "Expected an ECDSA key in "+file)
+		assert.Equal(t, elliptic.P384(), publicKey.Curve, "Expected a P-384 key in "+file)
+	}
+	testMSPConfig, err := fabricmsp.GetLocalMspConfig(filepath.Join(nodeDir, "msp"), nil, testName)
+	assert.NoError(t, err, "Error parsing local MSP config")
+	testMSP, err := fabricmsp.New(&fabricmsp.BCCSPNewOpts{NewBaseOpts: fabricmsp.NewBaseOpts{Version: fabricmsp.MSPv1_0}})
+	assert.NoError(t, err, "Error creating new BCCSP MSP")
+	err = testMSP.Setup(testMSPConfig)
+	assert.NoError(t, err, "Error setting up local MSP")
+
+	// but it must use the family of its CA
+	err = msp.GenerateLocalMSPWithKeyOpts(filepath.Join(testDir, "sm2node"), testName, nil, signCA, tlsCA, msp.PEER, true, &csp.KeyOpts{Algorithm: csp.SM2})
+	assert.EqualError(t, err, "SM2 keys cannot be certified by CA "+testCAName+" using ECDSA-P256")
+	err = msp.GenerateLocalMSPWithKeyOpts(filepath.Join(testDir, "ecdsanode"), testName, nil, gmCA, gmCA, msp.PEER, true, &csp.KeyOpts{})
+	assert.EqualError(t, err, "ECDSA-P256 keys cannot be certified by CA "+testCAName+" using SM2")
+	err = msp.GenerateLocalMSPWithKeyOpts(filepath.Join(testDir, "badnode"), testName, nil, signCA, tlsCA, msp.PEER, true, &csp.KeyOpts{Algorithm: "RSA"})
+	assert.Error(t, err)
+
+	// keys held by a KMS are only referenced by the keystore
+	gmNodeDir := filepath.Join(testDir, "gmnode")
+	err = msp.GenerateLocalMSPWithKeyOpts(gmNodeDir, testName, nil, gmCA, gmCA, msp.PEER, true, &csp.KeyOpts{
+		Algorithm: csp.SM2,
+		KeyStore: &csp.KeyStoreOpts{
+			Type: csp.KMSKeyStore,
+			KMS:  &factory.KMSOpts{Driver: gm.InProcessKMSDriverName},
+		},
+	})
+	assert.NoError(t, err, "Failed to generate local MSP")
+	files, err := ioutil.ReadDir(filepath.Join(gmNodeDir, "msp", "keystore"))
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), "_kms"))
+	assert.True(t, checkForFile(filepath.Join(gmNodeDir, "tls", "server.key")))
+}
+
 func testGenerateVerifyingMSP(t *testing.T, nodeOUs bool) {
 	caDir := filepath.Join(testDir, "ca")
 	tlsCADir := filepath.Join(testDir, "tlsca")
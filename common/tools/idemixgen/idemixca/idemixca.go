@@ -25,12 +25,18 @@ import (
 // AttributeNameRevocationHandle contains the revocation handle, which can be used to revoke this user
 // Generated keys are serialized to bytes.
 func GenerateIssuerKey() ([]byte, []byte, error) {
+	return GenerateIssuerKeyWithHashAlgorithm("")
+}
+
+// GenerateIssuerKeyWithHashAlgorithm generates an issuer key pair like GenerateIssuerKey,
+// whose zero-knowledge proofs hash with the given algorithm (idemix.HashAlgorithmSM3 for GM).
+func GenerateIssuerKeyWithHashAlgorithm(hashAlgorithm string) ([]byte, []byte, error) {
 	rng, err := idemix.GetRand()
 	if err != nil {
 		return nil, nil, err
 	}
 	AttributeNames := []string{msp.AttributeNameOU, msp.AttributeNameRole, msp.AttributeNameEnrollmentId, msp.AttributeNameRevocationHandle}
-	key, err := idemix.NewIssuerKey(AttributeNames, rng)
+	key, err := idemix.NewIssuerKeyWithHashAlgorithm(AttributeNames, hashAlgorithm, rng)
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "cannot generate CA key")
 	}
@@ -53,9 +59,9 @@ func GenerateSignerConfig(roleMask int, ouString string, enrollmentId string, re
 		return nil, errors.Errorf("the enrollment id value is empty")
 	}
 
-	attrs[msp.AttributeIndexOU] = idemix.HashModOrder([]byte(ouString))
+	attrs[msp.AttributeIndexOU] = key.Ipk.HashModOrder([]byte(ouString))
 	attrs[msp.AttributeIndexRole] = FP256BN.NewBIGint(roleMask)
-	attrs[msp.AttributeIndexEnrollmentId] = idemix.HashModOrder([]byte(enrollmentId))
+	attrs[msp.AttributeIndexEnrollmentId] = key.Ipk.HashModOrder([]byte(enrollmentId))
 	attrs[msp.AttributeIndexRevocationHandle] = FP256BN.NewBIGint(revocationHandle)
 
 	rng, err := idemix.GetRand()
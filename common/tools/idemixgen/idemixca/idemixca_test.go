@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/idemix"
 	m "github.com/hyperledger/fabric/msp"
@@ -68,6 +69,48 @@ func TestIdemixCa(t *testing.T) {
 	assert.EqualError(t, err, "the enrollment id value is empty")
 }
 
+func TestIdemixCaGM(t *testing.T) {
+	cleanup()
+
+	isk, ipkBytes, err := GenerateIssuerKeyWithHashAlgorithm(idemix.HashAlgorithmSM3)
+	assert.NoError(t, err)
+
+	revocationkey, err := idemix.GenerateLongTermSM2RevocationKey()
+	assert.NoError(t, err)
+
+	ipk := &idemix.IssuerPublicKey{}
+	err = proto.Unmarshal(ipkBytes, ipk)
+	assert.NoError(t, err)
+	assert.Equal(t, idemix.HashAlgorithmSM3, ipk.HashAlgorithm)
+
+	encodedRevocationPK, err := x509GM.MarshalPKIXPublicKey(&revocationkey.PublicKey)
+	assert.NoError(t, err)
+	pemEncodedRevocationPK := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedRevocationPK})
+
+	writeVerifierToFile(ipkBytes, pemEncodedRevocationPK)
+
+	key := &idemix.IssuerKey{Isk: isk, Ipk: ipk}
+
+	conf, err := GenerateSignerConfig(m.GetRoleMaskFromIdemixRole(m.MEMBER), "OU1", "enrollmentid1", 1, key, revocationkey)
+	assert.NoError(t, err)
+	cleanupSigner()
+	assert.NoError(t, writeSignerToFile(conf))
+	assert.NoError(t, setupMSP())
+
+	// The signatures of the default signer verify with the SM2 revocation key
+	msp, err := m.New(&m.IdemixNewOpts{NewBaseOpts: m.NewBaseOpts{Version: m.MSPv1_1}})
+	assert.NoError(t, err)
+	mspConfig, err := m.GetIdemixMspConfig(testDir, "TestName")
+	assert.NoError(t, err)
+	assert.NoError(t, msp.Setup(mspConfig))
+	signer, err := msp.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+	sig, err := signer.Sign([]byte("message"))
+	assert.NoError(t, err)
+	assert.NoError(t, signer.Verify([]byte("message"), sig))
+	assert.Error(t, signer.Verify([]byte("another message"), sig))
+}
+
 func cleanup() error {
 	// clean up any previous files
 	err := os.RemoveAll(testDir)
@@ -20,6 +20,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
+	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/tools/idemixgen/idemixca"
 	"github.com/hyperledger/fabric/common/tools/idemixgen/metadata"
@@ -42,6 +44,7 @@ var (
 	outputDir = app.Flag("output", "The output directory in which to place artifacts").Default("idemix-config").String()
 
 	genIssuerKey            = app.Command("ca-keygen", "Generate CA key material")
+	genIssuerKeyGM          = genIssuerKey.Flag("gm", "Hash the zero-knowledge proofs with SM3 and generate an SM2 revocation key").Bool()
 	genSignerConfig         = app.Command("signerconfig", "Generate a default signer for this Idemix MSP")
 	genCredOU               = genSignerConfig.Flag("org-unit", "The Organizational Unit of the default signer").Short('u').String()
 	genCredIsAdmin          = genSignerConfig.Flag("admin", "Make the default signer admin").Short('a').Bool()
@@ -57,16 +60,23 @@ func main() {
 	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
 
 	case genIssuerKey.FullCommand():
-		isk, ipk, err := idemixca.GenerateIssuerKey()
+		hashAlgorithm := idemix.HashAlgorithmSHA256
+		generateRevocationKey := idemix.GenerateLongTermRevocationKey
+		if *genIssuerKeyGM {
+			hashAlgorithm = idemix.HashAlgorithmSM3
+			generateRevocationKey = idemix.GenerateLongTermSM2RevocationKey
+		}
+
+		isk, ipk, err := idemixca.GenerateIssuerKeyWithHashAlgorithm(hashAlgorithm)
 		handleError(err)
 
-		revocationKey, err := idemix.GenerateLongTermRevocationKey()
+		revocationKey, err := generateRevocationKey()
 		handleError(err)
-		encodedRevocationSK, err := x509.MarshalECPrivateKey(revocationKey)
+		encodedRevocationSK, err := marshalRevocationKey(revocationKey)
 		handleError(err)
 		pemEncodedRevocationSK := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: encodedRevocationSK})
 		handleError(err)
-		encodedRevocationPK, err := x509.MarshalPKIXPublicKey(revocationKey.Public())
+		encodedRevocationPK, err := x509GM.MarshalPKIXPublicKey(&revocationKey.PublicKey)
 		handleError(err)
 		pemEncodedRevocationPK := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: encodedRevocationPK})
 
@@ -148,9 +158,29 @@ func readRevocationKey() *ecdsa.PrivateKey {
 		handleError(errors.Errorf("failed to decode ECDSA private key"))
 	}
 	key, err := x509.ParseECPrivateKey(block.Bytes)
-	handleError(err)
+	if err == nil {
+		return key
+	}
+	sm2Key, sm2Err := x509GM.ParsePKCS8UnecryptedPrivateKey(block.Bytes)
+	if sm2Err != nil {
+		handleError(err)
+	}
 
-	return key
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: sm2Key.Curve, X: sm2Key.X, Y: sm2Key.Y},
+		D:         sm2Key.D,
+	}
+}
+
+// marshalRevocationKey encodes ECDSA revocation keys in SEC 1 and SM2 revocation keys in PKCS8
+func marshalRevocationKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	if !idemix.IsSM2RevocationKey(&key.PublicKey) {
+		return x509.MarshalECPrivateKey(key)
+	}
+	return x509GM.MarshalSm2UnecryptedPrivateKey(&sm2.PrivateKey{
+		PublicKey: sm2.PublicKey{Curve: key.Curve, X: key.X, Y: key.Y},
+		D:         key.D,
+	})
 }
 
 // checkDirectoryNotExists checks whether a directory with the given path already exists and exits if this is the case
@@ -37,6 +37,9 @@ type alg struct {
 
 const defaultAlg = "sha256"
 
+// SM3 is the name of the SM3 hashing algorithm in the channel configuration
+const SM3 = "SM3"
+
 var availableIDgenAlgs = map[string]alg{
 	defaultAlg: {GenerateIDfromTxSHAHash},
 }
@@ -59,6 +62,30 @@ func ComputeSHA3256(data []byte) (hash []byte) {
 	return
 }
 
+// ComputeSM3 returns SM3 on data
+func ComputeSM3(data []byte) (hash []byte) {
+	csp, err := factory.GetBCCSPForHash(factory.GetDefault(), &bccsp.GMSM3Opts{})
+	if err != nil {
+		panic(fmt.Errorf("Failed getting a BCCSP computing SM3: %s", err))
+	}
+	hash, err = csp.Hash(data, &bccsp.GMSM3Opts{})
+	if err != nil {
+		panic(fmt.Errorf("Failed computing SM3 on [% x]", data))
+	}
+	return
+}
+
+// BlockHashingFunction returns the function hashing the block headers, the
+// block data and the transaction IDs of a channel configured with the
+// hashing algorithm named algorithm. Only SM3 changes them: the channels
+// configured with another algorithm keep using SHA256.
+func BlockHashingFunction(algorithm string) func([]byte) []byte {
+	if algorithm == SM3 {
+		return ComputeSM3
+	}
+	return ComputeSHA256
+}
+
 // GenerateBytesUUID returns a UUID based on RFC 4122 returning the generated bytes
 func GenerateBytesUUID() []byte {
 	uuid := make([]byte, 16)
@@ -45,6 +45,21 @@ func TestComputeSHA3256(t *testing.T) {
 	}
 }
 
+func TestComputeSM3(t *testing.T) {
+	// Test vector of GB/T 32905-2016
+	expected := "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"
+	assert.Equal(t, expected, fmt.Sprintf("%x", ComputeSM3([]byte("abc"))))
+	assert.NotEqual(t, ComputeSM3([]byte("foobar1")), ComputeSM3([]byte("foobar2")))
+}
+
+func TestBlockHashingFunction(t *testing.T) {
+	data := []byte("foobar")
+	assert.Equal(t, ComputeSM3(data), BlockHashingFunction(SM3)(data))
+	assert.Equal(t, ComputeSHA256(data), BlockHashingFunction("SHA256")(data))
+	assert.Equal(t, ComputeSHA256(data), BlockHashingFunction("SHA3_256")(data))
+	assert.Equal(t, ComputeSHA256(data), BlockHashingFunction("")(data))
+}
+
 func TestUUIDGeneration(t *testing.T) {
 	uuid := GenerateUUID()
 	if len(uuid) != 36 {
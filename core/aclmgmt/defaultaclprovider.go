@@ -75,6 +75,8 @@ func (d *defaultACLProvider) initialize() {
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByHash] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetTransactionByID] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByTxID] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetStateAtHeight] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetStateRangeAtHeight] = CHANNELREADERS
 
 	//--------------- CSCC resources -----------
 	//p resources (implemented by the chaincode currently)
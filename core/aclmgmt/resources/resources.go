@@ -23,11 +23,13 @@ const (
 	Lscc_GetCollectionsConfig      = "lscc/GetCollectionsConfig"
 
 	//Qscc resources
-	Qscc_GetChainInfo       = "qscc/GetChainInfo"
-	Qscc_GetBlockByNumber   = "qscc/GetBlockByNumber"
-	Qscc_GetBlockByHash     = "qscc/GetBlockByHash"
-	Qscc_GetTransactionByID = "qscc/GetTransactionByID"
-	Qscc_GetBlockByTxID     = "qscc/GetBlockByTxID"
+	Qscc_GetChainInfo          = "qscc/GetChainInfo"
+	Qscc_GetBlockByNumber      = "qscc/GetBlockByNumber"
+	Qscc_GetBlockByHash        = "qscc/GetBlockByHash"
+	Qscc_GetTransactionByID    = "qscc/GetTransactionByID"
+	Qscc_GetBlockByTxID        = "qscc/GetBlockByTxID"
+	Qscc_GetStateAtHeight      = "qscc/GetStateAtHeight"
+	Qscc_GetStateRangeAtHeight = "qscc/GetStateRangeAtHeight"
 
 	//Cscc resources
 	Cscc_JoinChain                = "cscc/JoinChain"
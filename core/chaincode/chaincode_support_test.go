@@ -86,6 +86,22 @@ func (meqe *mockExecQuerySimulator) GetHistoryForKey(namespace, query string) (c
 	return meqe.commonQuery(namespace, query)
 }
 
+func (meqe *mockExecQuerySimulator) GetStateAtHeight(namespace, key string, blockNum uint64) ([]byte, error) {
+	return nil, fmt.Errorf("GetStateAtHeight not supported")
+}
+
+func (meqe *mockExecQuerySimulator) GetStateRangeAtHeight(namespace, startKey, endKey string, blockNum uint64) (commonledger.ResultsIterator, error) {
+	return nil, fmt.Errorf("GetStateRangeAtHeight not supported")
+}
+
+func (meqe *mockExecQuerySimulator) GetHistoryForKeyWithOptions(namespace, key string, options *ledger.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
+	return nil, fmt.Errorf("GetHistoryForKeyWithOptions not supported")
+}
+
+func (meqe *mockExecQuerySimulator) GetHistoryForKeyRange(namespace, startKey, endKey string, options *ledger.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
+	return nil, fmt.Errorf("GetHistoryForKeyRange not supported")
+}
+
 func (meqe *mockExecQuerySimulator) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
 	return meqe.commonQuery(namespace, query)
 }
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
@@ -221,6 +222,14 @@ func (h *Handler) handleMessageReadyState(msg *pb.ChaincodeMessage) error {
 		go h.HandleTransaction(msg, h.HandleGetQueryResult)
 	case pb.ChaincodeMessage_GET_HISTORY_FOR_KEY:
 		go h.HandleTransaction(msg, h.HandleGetHistoryForKey)
+	case pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS:
+		go h.HandleTransaction(msg, h.HandleGetHistoryForKeyWithOptions)
+	case pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE:
+		go h.HandleTransaction(msg, h.HandleGetHistoryForKeyRange)
+	case pb.ChaincodeMessage_GET_STATE_AT_HEIGHT:
+		go h.HandleTransaction(msg, h.HandleGetStateAtHeight)
+	case pb.ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT:
+		go h.HandleTransaction(msg, h.HandleGetStateByRangeAtHeight)
 	case pb.ChaincodeMessage_QUERY_STATE_NEXT:
 		go h.HandleTransaction(msg, h.HandleQueryStateNext)
 	case pb.ChaincodeMessage_QUERY_STATE_CLOSE:
@@ -958,6 +967,161 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+// Handles query to ledger history db with the options of the query
+func (h *Handler) HandleGetHistoryForKeyWithOptions(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	iterID := h.UUIDGenerator.New()
+	chaincodeName := h.ChaincodeName()
+
+	getHistoryForKey := &pb.GetHistoryForKeyWithOptions{}
+	err := proto.Unmarshal(msg.Payload, getHistoryForKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	options, metadata, err := getHistoryQueryOptionsFromBytes(getHistoryForKey.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	totalReturnLimit := calculateTotalReturnLimit(metadata)
+	isPaginated := isMetadataSetForPagination(metadata)
+	if options == nil {
+		options = &ledger.HistoryQueryOptions{}
+	}
+	if isPaginated {
+		options.PageSize = totalReturnLimit
+	}
+
+	historyIter, err := txContext.HistoryQueryExecutor.GetHistoryForKeyWithOptions(chaincodeName, getHistoryForKey.Key, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	txContext.InitializeQueryContext(iterID, historyIter)
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, isPaginated, totalReturnLimit)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.WithStack(err)
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	chaincodeLogger.Debugf("Got keys and values. Sending %s", pb.ChaincodeMessage_RESPONSE)
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// Handles query to ledger history db for a range of keys
+func (h *Handler) HandleGetHistoryForKeyRange(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	iterID := h.UUIDGenerator.New()
+	chaincodeName := h.ChaincodeName()
+
+	getHistoryForKeyRange := &pb.GetHistoryForKeyRange{}
+	err := proto.Unmarshal(msg.Payload, getHistoryForKeyRange)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	options, metadata, err := getHistoryQueryOptionsFromBytes(getHistoryForKeyRange.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	totalReturnLimit := calculateTotalReturnLimit(metadata)
+	isPaginated := isMetadataSetForPagination(metadata)
+	if options == nil {
+		options = &ledger.HistoryQueryOptions{}
+	}
+	if isPaginated {
+		options.PageSize = totalReturnLimit
+	}
+
+	historyIter, err := txContext.HistoryQueryExecutor.GetHistoryForKeyRange(chaincodeName,
+		getHistoryForKeyRange.StartKey, getHistoryForKeyRange.EndKey, options)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	txContext.InitializeQueryContext(iterID, historyIter)
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, isPaginated, totalReturnLimit)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.WithStack(err)
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	chaincodeLogger.Debugf("Got keys and values. Sending %s", pb.ChaincodeMessage_RESPONSE)
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// Handles query to the value of a key at a height of the ledger
+func (h *Handler) HandleGetStateAtHeight(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	getStateAtHeight := &pb.GetStateAtHeight{}
+	err := proto.Unmarshal(msg.Payload, getStateAtHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	chaincodeName := h.ChaincodeName()
+	chaincodeLogger.Debugf("[%s] getting state for chaincode %s, key %s, channel %s at block %d", shorttxid(msg.Txid),
+		chaincodeName, getStateAtHeight.Key, txContext.ChainID, getStateAtHeight.BlockNumber)
+
+	res, err := txContext.HistoryQueryExecutor.GetStateAtHeight(chaincodeName, getStateAtHeight.Key, getStateAtHeight.BlockNumber)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if res == nil {
+		chaincodeLogger.Debugf("[%s] No state associated with key: %s at block %d. Sending %s with an empty payload", shorttxid(msg.Txid),
+			getStateAtHeight.Key, getStateAtHeight.BlockNumber, pb.ChaincodeMessage_RESPONSE)
+	}
+
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
+// Handles range query to the values of the keys at a height of the ledger
+func (h *Handler) HandleGetStateByRangeAtHeight(msg *pb.ChaincodeMessage, txContext *TransactionContext) (*pb.ChaincodeMessage, error) {
+	iterID := h.UUIDGenerator.New()
+	chaincodeName := h.ChaincodeName()
+
+	getStateByRangeAtHeight := &pb.GetStateByRangeAtHeight{}
+	err := proto.Unmarshal(msg.Payload, getStateByRangeAtHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	rangeIter, err := txContext.HistoryQueryExecutor.GetStateRangeAtHeight(chaincodeName,
+		getStateByRangeAtHeight.StartKey, getStateByRangeAtHeight.EndKey, getStateByRangeAtHeight.BlockNumber)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	totalReturnLimit := calculateTotalReturnLimit(nil)
+
+	txContext.InitializeQueryContext(iterID, rangeIter)
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, rangeIter, iterID, false, totalReturnLimit)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.WithStack(err)
+	}
+
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		txContext.CleanupQueryContext(iterID)
+		return nil, errors.Wrap(err, "marshal failed")
+	}
+
+	chaincodeLogger.Debugf("Got keys and values. Sending %s", pb.ChaincodeMessage_RESPONSE)
+	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
+}
+
 func isCollectionSet(collection string) bool {
 	return collection != ""
 }
@@ -986,6 +1150,41 @@ func getQueryMetadataFromBytes(metadataBytes []byte) (*pb.QueryMetadata, error)
 	return nil, nil
 }
 
+// getHistoryQueryOptionsFromBytes returns the options of a history query held by the bytes of a HistoryQueryMetadata,
+// along with the QueryMetadata of its pagination. Both are nil if the bytes are nil
+func getHistoryQueryOptionsFromBytes(metadataBytes []byte) (*ledger.HistoryQueryOptions, *pb.QueryMetadata, error) {
+	if metadataBytes == nil {
+		return nil, nil, nil
+	}
+	metadata := &pb.HistoryQueryMetadata{}
+	if err := proto.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal failed")
+	}
+
+	options := &ledger.HistoryQueryOptions{
+		StartBlock:  metadata.StartBlock,
+		EndBlock:    metadata.EndBlock,
+		NewestFirst: metadata.NewestFirst,
+		PageSize:    metadata.PageSize,
+		Bookmark:    metadata.Bookmark,
+	}
+	if metadata.StartTime != nil {
+		startTime, err := ptypes.Timestamp(metadata.StartTime)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid start time")
+		}
+		options.StartTime = startTime
+	}
+	if metadata.EndTime != nil {
+		endTime, err := ptypes.Timestamp(metadata.EndTime)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "invalid end time")
+		}
+		options.EndTime = endTime
+	}
+	return options, &pb.QueryMetadata{PageSize: metadata.PageSize, Bookmark: metadata.Bookmark}, nil
+}
+
 func createPaginationInfoFromMetadata(metadata *pb.QueryMetadata, totalReturnLimit int32, queryType pb.ChaincodeMessage_Type) (map[string]interface{}, error) {
 	paginationInfoMap := make(map[string]interface{})
 
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/util"
@@ -20,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/mock"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -2067,6 +2069,484 @@ var _ = Describe("Handler", func() {
 		})
 	})
 
+	Describe("HandleGetHistoryForKeyWithOptions", func() {
+		var (
+			request               *pb.GetHistoryForKeyWithOptions
+			incomingMessage       *pb.ChaincodeMessage
+			expectedQueryResponse *pb.QueryResponse
+			fakeIterator          *mock.QueryResultsIterator
+			startTime             time.Time
+		)
+
+		BeforeEach(func() {
+			startTime = time.Unix(1000, 0).UTC()
+			startTimestamp, err := ptypes.TimestampProto(startTime)
+			Expect(err).NotTo(HaveOccurred())
+			metadata, err := proto.Marshal(&pb.HistoryQueryMetadata{
+				StartBlock:  2,
+				EndBlock:    5,
+				StartTime:   startTimestamp,
+				NewestFirst: true,
+				PageSize:    10,
+				Bookmark:    "bookmark",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			request = &pb.GetHistoryForKeyWithOptions{
+				Key:      "history-key",
+				Metadata: metadata,
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+
+			expectedQueryResponse = &pb.QueryResponse{
+				Id: "query-response-id",
+			}
+			fakeQueryResponseBuilder.BuildQueryResponseReturns(expectedQueryResponse, nil)
+
+			fakeIterator = &mock.QueryResultsIterator{}
+			fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsReturns(fakeIterator, nil)
+		})
+
+		It("calls GetHistoryForKeyWithOptions on the history query executor", func() {
+			_, err := handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeHistoryQueryExecutor.GetHistoryForKeyCallCount()).To(Equal(0))
+			Expect(fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsCallCount()).To(Equal(1))
+			ccname, key, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(key).To(Equal("history-key"))
+			Expect(options).To(Equal(&ledger.HistoryQueryOptions{
+				StartBlock:  2,
+				EndBlock:    5,
+				StartTime:   startTime,
+				NewestFirst: true,
+				PageSize:    10,
+				Bookmark:    "bookmark",
+			}))
+		})
+
+		It("builds a paginated query response", func() {
+			_, err := handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+			_, iter, iterID, isPaginated, totalReturnLimit := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+			Expect(iter).To(Equal(fakeIterator))
+			Expect(iterID).To(Equal("generated-query-id"))
+			Expect(isPaginated).To(BeTrue())
+			Expect(totalReturnLimit).To(Equal(int32(10)))
+		})
+
+		Context("when no metadata is provided", func() {
+			BeforeEach(func() {
+				request.Metadata = nil
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("calls GetHistoryForKeyWithOptions with empty options", func() {
+				_, err := handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(0)
+				Expect(options).To(Equal(&ledger.HistoryQueryOptions{}))
+				_, _, _, isPaginated, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+				Expect(isPaginated).To(BeFalse())
+			})
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when unmarshalling the metadata fails", func() {
+			BeforeEach(func() {
+				request.Metadata = []byte("this-is-a-bogus-payload")
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the history query executor fails", func() {
+			BeforeEach(func() {
+				fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsReturns(nil, errors.New("anchovies"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+				Expect(err).To(MatchError("anchovies"))
+			})
+		})
+
+		Context("when building the query response fails", func() {
+			BeforeEach(func() {
+				fakeQueryResponseBuilder.BuildQueryResponseReturns(nil, errors.New("olives"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+				Expect(err).To(MatchError("olives"))
+			})
+
+			It("cleans up the query context", func() {
+				handler.HandleGetHistoryForKeyWithOptions(incomingMessage, txContext)
+
+				pqr := txContext.GetPendingQueryResult("generated-query-id")
+				Expect(pqr).To(BeNil())
+				iter := txContext.GetQueryIterator("generated-query-id")
+				Expect(iter).To(BeNil())
+				retCount := txContext.GetTotalReturnCount("generated-query-id")
+				Expect(retCount).To(BeNil())
+			})
+		})
+	})
+
+	Describe("HandleGetHistoryForKeyRange", func() {
+		var (
+			request               *pb.GetHistoryForKeyRange
+			incomingMessage       *pb.ChaincodeMessage
+			expectedQueryResponse *pb.QueryResponse
+			fakeIterator          *mock.QueryResultsIterator
+		)
+
+		BeforeEach(func() {
+			request = &pb.GetHistoryForKeyRange{
+				StartKey: "history-start-key",
+				EndKey:   "history-end-key",
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+
+			expectedQueryResponse = &pb.QueryResponse{
+				Id: "query-response-id",
+			}
+			fakeQueryResponseBuilder.BuildQueryResponseReturns(expectedQueryResponse, nil)
+
+			fakeIterator = &mock.QueryResultsIterator{}
+			fakeHistoryQueryExecutor.GetHistoryForKeyRangeReturns(fakeIterator, nil)
+		})
+
+		It("calls GetHistoryForKeyRange on the history query executor", func() {
+			_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeHistoryQueryExecutor.GetHistoryForKeyRangeCallCount()).To(Equal(1))
+			ccname, startKey, endKey, options := fakeHistoryQueryExecutor.GetHistoryForKeyRangeArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(startKey).To(Equal("history-start-key"))
+			Expect(endKey).To(Equal("history-end-key"))
+			Expect(options).To(Equal(&ledger.HistoryQueryOptions{}))
+		})
+
+		It("initializes a query context", func() {
+			_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			pqr := txContext.GetPendingQueryResult("generated-query-id")
+			Expect(pqr).To(Equal(&chaincode.PendingQueryResult{}))
+			iter := txContext.GetQueryIterator("generated-query-id")
+			Expect(iter).To(Equal(fakeIterator))
+			retCount := txContext.GetTotalReturnCount("generated-query-id")
+			Expect(*retCount).To(Equal(int32(0)))
+		})
+
+		It("builds a query response", func() {
+			_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+			tctx, iter, iterID, isPaginated, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+			Expect(tctx).To(Equal(txContext))
+			Expect(iter).To(Equal(fakeIterator))
+			Expect(iterID).To(Equal("generated-query-id"))
+			Expect(isPaginated).To(BeFalse())
+		})
+
+		Context("when the query is paginated", func() {
+			BeforeEach(func() {
+				metadata, err := proto.Marshal(&pb.HistoryQueryMetadata{PageSize: 10, Bookmark: "bookmark"})
+				Expect(err).NotTo(HaveOccurred())
+				request.Metadata = metadata
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+			})
+
+			It("passes the pagination options to the history query executor", func() {
+				_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, options := fakeHistoryQueryExecutor.GetHistoryForKeyRangeArgsForCall(0)
+				Expect(options).To(Equal(&ledger.HistoryQueryOptions{PageSize: 10, Bookmark: "bookmark"}))
+			})
+
+			It("builds a paginated query response", func() {
+				_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, _, isPaginated, totalReturnLimit := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+				Expect(isPaginated).To(BeTrue())
+				Expect(totalReturnLimit).To(Equal(int32(10)))
+			})
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the history query executor fails", func() {
+			BeforeEach(func() {
+				fakeHistoryQueryExecutor.GetHistoryForKeyRangeReturns(nil, errors.New("pepperoni"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+				Expect(err).To(MatchError("pepperoni"))
+			})
+		})
+
+		Context("when building the query response fails", func() {
+			BeforeEach(func() {
+				fakeQueryResponseBuilder.BuildQueryResponseReturns(nil, errors.New("mushrooms"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+				Expect(err).To(MatchError("mushrooms"))
+			})
+
+			It("cleans up the query context", func() {
+				handler.HandleGetHistoryForKeyRange(incomingMessage, txContext)
+
+				pqr := txContext.GetPendingQueryResult("generated-query-id")
+				Expect(pqr).To(BeNil())
+				iter := txContext.GetQueryIterator("generated-query-id")
+				Expect(iter).To(BeNil())
+				retCount := txContext.GetTotalReturnCount("generated-query-id")
+				Expect(retCount).To(BeNil())
+			})
+		})
+	})
+
+	Describe("HandleGetStateAtHeight", func() {
+		var (
+			request         *pb.GetStateAtHeight
+			incomingMessage *pb.ChaincodeMessage
+		)
+
+		BeforeEach(func() {
+			request = &pb.GetStateAtHeight{
+				Key:         "height-key",
+				BlockNumber: 5,
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_GET_STATE_AT_HEIGHT,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+
+			fakeHistoryQueryExecutor.GetStateAtHeightReturns([]byte("height-value"), nil)
+		})
+
+		It("calls GetStateAtHeight on the history query executor", func() {
+			_, err := handler.HandleGetStateAtHeight(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeHistoryQueryExecutor.GetStateAtHeightCallCount()).To(Equal(1))
+			ccname, key, blockNum := fakeHistoryQueryExecutor.GetStateAtHeightArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(key).To(Equal("height-key"))
+			Expect(blockNum).To(Equal(uint64(5)))
+		})
+
+		It("returns the response message from GetStateAtHeight", func() {
+			resp, err := handler.HandleGetStateAtHeight(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp).To(Equal(&pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_RESPONSE,
+				Payload:   []byte("height-value"),
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}))
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateAtHeight(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the history query executor fails", func() {
+			BeforeEach(func() {
+				fakeHistoryQueryExecutor.GetStateAtHeightReturns(nil, errors.New("pepperoni"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateAtHeight(incomingMessage, txContext)
+				Expect(err).To(MatchError("pepperoni"))
+			})
+		})
+	})
+
+	Describe("HandleGetStateByRangeAtHeight", func() {
+		var (
+			request               *pb.GetStateByRangeAtHeight
+			incomingMessage       *pb.ChaincodeMessage
+			expectedQueryResponse *pb.QueryResponse
+			fakeIterator          *mock.QueryResultsIterator
+		)
+
+		BeforeEach(func() {
+			request = &pb.GetStateByRangeAtHeight{
+				StartKey:    "start-key",
+				EndKey:      "end-key",
+				BlockNumber: 5,
+			}
+			payload, err := proto.Marshal(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			incomingMessage = &pb.ChaincodeMessage{
+				Type:      pb.ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT,
+				Payload:   payload,
+				Txid:      "tx-id",
+				ChannelId: "channel-id",
+			}
+
+			expectedQueryResponse = &pb.QueryResponse{
+				Id: "query-response-id",
+			}
+			fakeQueryResponseBuilder.BuildQueryResponseReturns(expectedQueryResponse, nil)
+
+			fakeIterator = &mock.QueryResultsIterator{}
+			fakeHistoryQueryExecutor.GetStateRangeAtHeightReturns(fakeIterator, nil)
+		})
+
+		It("calls GetStateRangeAtHeight on the history query executor", func() {
+			_, err := handler.HandleGetStateByRangeAtHeight(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeHistoryQueryExecutor.GetStateRangeAtHeightCallCount()).To(Equal(1))
+			ccname, startKey, endKey, blockNum := fakeHistoryQueryExecutor.GetStateRangeAtHeightArgsForCall(0)
+			Expect(ccname).To(Equal("cc-instance-name"))
+			Expect(startKey).To(Equal("start-key"))
+			Expect(endKey).To(Equal("end-key"))
+			Expect(blockNum).To(Equal(uint64(5)))
+		})
+
+		It("initializes a query context", func() {
+			_, err := handler.HandleGetStateByRangeAtHeight(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			pqr := txContext.GetPendingQueryResult("generated-query-id")
+			Expect(pqr).To(Equal(&chaincode.PendingQueryResult{}))
+			iter := txContext.GetQueryIterator("generated-query-id")
+			Expect(iter).To(Equal(fakeIterator))
+			retCount := txContext.GetTotalReturnCount("generated-query-id")
+			Expect(*retCount).To(Equal(int32(0)))
+		})
+
+		It("builds a query response", func() {
+			_, err := handler.HandleGetStateByRangeAtHeight(incomingMessage, txContext)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+			tctx, iter, iterID, isPaginated, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+			Expect(tctx).To(Equal(txContext))
+			Expect(iter).To(Equal(fakeIterator))
+			Expect(iterID).To(Equal("generated-query-id"))
+			Expect(isPaginated).To(BeFalse())
+		})
+
+		Context("when unmarshalling the request fails", func() {
+			BeforeEach(func() {
+				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateByRangeAtHeight(incomingMessage, txContext)
+				Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+			})
+		})
+
+		Context("when the history query executor fails", func() {
+			BeforeEach(func() {
+				fakeHistoryQueryExecutor.GetStateRangeAtHeightReturns(nil, errors.New("pepperoni"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateByRangeAtHeight(incomingMessage, txContext)
+				Expect(err).To(MatchError("pepperoni"))
+			})
+		})
+
+		Context("when building the query response fails", func() {
+			BeforeEach(func() {
+				fakeQueryResponseBuilder.BuildQueryResponseReturns(nil, errors.New("mushrooms"))
+			})
+
+			It("returns an error", func() {
+				_, err := handler.HandleGetStateByRangeAtHeight(incomingMessage, txContext)
+				Expect(err).To(MatchError("mushrooms"))
+			})
+
+			It("cleans up the query context", func() {
+				handler.HandleGetStateByRangeAtHeight(incomingMessage, txContext)
+
+				pqr := txContext.GetPendingQueryResult("generated-query-id")
+				Expect(pqr).To(BeNil())
+				iter := txContext.GetQueryIterator("generated-query-id")
+				Expect(iter).To(BeNil())
+				retCount := txContext.GetTotalReturnCount("generated-query-id")
+				Expect(retCount).To(BeNil())
+			})
+		})
+	})
+
 	Describe("HandleInvokeChaincode", func() {
 		var (
 			expectedSignedProp      *pb.SignedProposal
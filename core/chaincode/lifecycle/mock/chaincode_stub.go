@@ -138,6 +138,56 @@ type ChaincodeStub struct {
 		result1 shim.HistoryQueryIteratorInterface
 		result2 error
 	}
+	GetHistoryForKeyRangeStub        func(string, string, *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)
+	getHistoryForKeyRangeMutex       sync.RWMutex
+	getHistoryForKeyRangeArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *shim.HistoryQueryOptions
+	}
+	getHistoryForKeyRangeReturns struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
+	getHistoryForKeyRangeReturnsOnCall map[int]struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
+	GetHistoryForKeyWithOptionsStub        func(string, *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)
+	getHistoryForKeyWithOptionsMutex       sync.RWMutex
+	getHistoryForKeyWithOptionsArgsForCall []struct {
+		arg1 string
+		arg2 *shim.HistoryQueryOptions
+	}
+	getHistoryForKeyWithOptionsReturns struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
+	getHistoryForKeyWithOptionsReturnsOnCall map[int]struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
+	GetHistoryForPartialCompositeKeyStub        func(string, []string, *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)
+	getHistoryForPartialCompositeKeyMutex       sync.RWMutex
+	getHistoryForPartialCompositeKeyArgsForCall []struct {
+		arg1 string
+		arg2 []string
+		arg3 *shim.HistoryQueryOptions
+	}
+	getHistoryForPartialCompositeKeyReturns struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
+	getHistoryForPartialCompositeKeyReturnsOnCall map[int]struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
 	GetPrivateDataStub        func(string, string) ([]byte, error)
 	getPrivateDataMutex       sync.RWMutex
 	getPrivateDataArgsForCall []struct {
@@ -279,6 +329,20 @@ type ChaincodeStub struct {
 		result1 []byte
 		result2 error
 	}
+	GetStateAtHeightStub        func(string, uint64) ([]byte, error)
+	getStateAtHeightMutex       sync.RWMutex
+	getStateAtHeightArgsForCall []struct {
+		arg1 string
+		arg2 uint64
+	}
+	getStateAtHeightReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getStateAtHeightReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetStateByPartialCompositeKeyStub        func(string, []string) (shim.StateQueryIteratorInterface, error)
 	getStateByPartialCompositeKeyMutex       sync.RWMutex
 	getStateByPartialCompositeKeyArgsForCall []struct {
@@ -343,6 +407,21 @@ type ChaincodeStub struct {
 		result2 *peer.QueryResponseMetadata
 		result3 error
 	}
+	GetStateRangeAtHeightStub        func(string, string, uint64) (shim.StateQueryIteratorInterface, error)
+	getStateRangeAtHeightMutex       sync.RWMutex
+	getStateRangeAtHeightArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}
+	getStateRangeAtHeightReturns struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
+	getStateRangeAtHeightReturnsOnCall map[int]struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}
 	GetStateValidationParameterStub        func(string) ([]byte, error)
 	getStateValidationParameterMutex       sync.RWMutex
 	getStateValidationParameterArgsForCall []struct {
@@ -1123,6 +1202,214 @@ func (fake *ChaincodeStub) GetHistoryForKeyReturnsOnCall(i int, result1 shim.His
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetHistoryForKeyRange(arg1 string, arg2 string, arg3 *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyRangeReturnsOnCall[len(fake.getHistoryForKeyRangeArgsForCall)]
+	fake.getHistoryForKeyRangeArgsForCall = append(fake.getHistoryForKeyRangeArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *shim.HistoryQueryOptions
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetHistoryForKeyRange", []interface{}{arg1, arg2, arg3})
+	fake.getHistoryForKeyRangeMutex.Unlock()
+	if fake.GetHistoryForKeyRangeStub != nil {
+		return fake.GetHistoryForKeyRangeStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getHistoryForKeyRangeReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyRangeCallCount() int {
+	fake.getHistoryForKeyRangeMutex.RLock()
+	defer fake.getHistoryForKeyRangeMutex.RUnlock()
+	return len(fake.getHistoryForKeyRangeArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyRangeCalls(stub func(string, string, *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	defer fake.getHistoryForKeyRangeMutex.Unlock()
+	fake.GetHistoryForKeyRangeStub = stub
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyRangeArgsForCall(i int) (string, string, *shim.HistoryQueryOptions) {
+	fake.getHistoryForKeyRangeMutex.RLock()
+	defer fake.getHistoryForKeyRangeMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyRangeReturns(result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	defer fake.getHistoryForKeyRangeMutex.Unlock()
+	fake.GetHistoryForKeyRangeStub = nil
+	fake.getHistoryForKeyRangeReturns = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyRangeReturnsOnCall(i int, result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	defer fake.getHistoryForKeyRangeMutex.Unlock()
+	fake.GetHistoryForKeyRangeStub = nil
+	if fake.getHistoryForKeyRangeReturnsOnCall == nil {
+		fake.getHistoryForKeyRangeReturnsOnCall = make(map[int]struct {
+			result1 shim.HistoryQueryIteratorInterface
+			result2 *peer.QueryResponseMetadata
+			result3 error
+		})
+	}
+	fake.getHistoryForKeyRangeReturnsOnCall[i] = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptions(arg1 string, arg2 *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithOptionsReturnsOnCall[len(fake.getHistoryForKeyWithOptionsArgsForCall)]
+	fake.getHistoryForKeyWithOptionsArgsForCall = append(fake.getHistoryForKeyWithOptionsArgsForCall, struct {
+		arg1 string
+		arg2 *shim.HistoryQueryOptions
+	}{arg1, arg2})
+	fake.recordInvocation("GetHistoryForKeyWithOptions", []interface{}{arg1, arg2})
+	fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	if fake.GetHistoryForKeyWithOptionsStub != nil {
+		return fake.GetHistoryForKeyWithOptionsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getHistoryForKeyWithOptionsReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsCallCount() int {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithOptionsArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsCalls(stub func(string, *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = stub
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsArgsForCall(i int) (string, *shim.HistoryQueryOptions) {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsReturns(result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	fake.getHistoryForKeyWithOptionsReturns = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithOptionsReturnsOnCall(i int, result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	if fake.getHistoryForKeyWithOptionsReturnsOnCall == nil {
+		fake.getHistoryForKeyWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 shim.HistoryQueryIteratorInterface
+			result2 *peer.QueryResponseMetadata
+			result3 error
+		})
+	}
+	fake.getHistoryForKeyWithOptionsReturnsOnCall[i] = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKey(arg1 string, arg2 []string, arg3 *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.getHistoryForPartialCompositeKeyMutex.Lock()
+	ret, specificReturn := fake.getHistoryForPartialCompositeKeyReturnsOnCall[len(fake.getHistoryForPartialCompositeKeyArgsForCall)]
+	fake.getHistoryForPartialCompositeKeyArgsForCall = append(fake.getHistoryForPartialCompositeKeyArgsForCall, struct {
+		arg1 string
+		arg2 []string
+		arg3 *shim.HistoryQueryOptions
+	}{arg1, arg2Copy, arg3})
+	fake.recordInvocation("GetHistoryForPartialCompositeKey", []interface{}{arg1, arg2Copy, arg3})
+	fake.getHistoryForPartialCompositeKeyMutex.Unlock()
+	if fake.GetHistoryForPartialCompositeKeyStub != nil {
+		return fake.GetHistoryForPartialCompositeKeyStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getHistoryForPartialCompositeKeyReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyCallCount() int {
+	fake.getHistoryForPartialCompositeKeyMutex.RLock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.RUnlock()
+	return len(fake.getHistoryForPartialCompositeKeyArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyCalls(stub func(string, []string, *shim.HistoryQueryOptions) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)) {
+	fake.getHistoryForPartialCompositeKeyMutex.Lock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.Unlock()
+	fake.GetHistoryForPartialCompositeKeyStub = stub
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyArgsForCall(i int) (string, []string, *shim.HistoryQueryOptions) {
+	fake.getHistoryForPartialCompositeKeyMutex.RLock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.RUnlock()
+	argsForCall := fake.getHistoryForPartialCompositeKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyReturns(result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForPartialCompositeKeyMutex.Lock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.Unlock()
+	fake.GetHistoryForPartialCompositeKeyStub = nil
+	fake.getHistoryForPartialCompositeKeyReturns = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChaincodeStub) GetHistoryForPartialCompositeKeyReturnsOnCall(i int, result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForPartialCompositeKeyMutex.Lock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.Unlock()
+	fake.GetHistoryForPartialCompositeKeyStub = nil
+	if fake.getHistoryForPartialCompositeKeyReturnsOnCall == nil {
+		fake.getHistoryForPartialCompositeKeyReturnsOnCall = make(map[int]struct {
+			result1 shim.HistoryQueryIteratorInterface
+			result2 *peer.QueryResponseMetadata
+			result3 error
+		})
+	}
+	fake.getHistoryForPartialCompositeKeyReturnsOnCall[i] = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *ChaincodeStub) GetPrivateData(arg1 string, arg2 string) ([]byte, error) {
 	fake.getPrivateDataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataReturnsOnCall[len(fake.getPrivateDataArgsForCall)]
@@ -1763,6 +2050,70 @@ func (fake *ChaincodeStub) GetStateReturnsOnCall(i int, result1 []byte, result2
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetStateAtHeight(arg1 string, arg2 uint64) ([]byte, error) {
+	fake.getStateAtHeightMutex.Lock()
+	ret, specificReturn := fake.getStateAtHeightReturnsOnCall[len(fake.getStateAtHeightArgsForCall)]
+	fake.getStateAtHeightArgsForCall = append(fake.getStateAtHeightArgsForCall, struct {
+		arg1 string
+		arg2 uint64
+	}{arg1, arg2})
+	fake.recordInvocation("GetStateAtHeight", []interface{}{arg1, arg2})
+	fake.getStateAtHeightMutex.Unlock()
+	if fake.GetStateAtHeightStub != nil {
+		return fake.GetStateAtHeightStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStateAtHeightReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetStateAtHeightCallCount() int {
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
+	return len(fake.getStateAtHeightArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetStateAtHeightCalls(stub func(string, uint64) ([]byte, error)) {
+	fake.getStateAtHeightMutex.Lock()
+	defer fake.getStateAtHeightMutex.Unlock()
+	fake.GetStateAtHeightStub = stub
+}
+
+func (fake *ChaincodeStub) GetStateAtHeightArgsForCall(i int) (string, uint64) {
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
+	argsForCall := fake.getStateAtHeightArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) GetStateAtHeightReturns(result1 []byte, result2 error) {
+	fake.getStateAtHeightMutex.Lock()
+	defer fake.getStateAtHeightMutex.Unlock()
+	fake.GetStateAtHeightStub = nil
+	fake.getStateAtHeightReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetStateAtHeightReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getStateAtHeightMutex.Lock()
+	defer fake.getStateAtHeightMutex.Unlock()
+	fake.GetStateAtHeightStub = nil
+	if fake.getStateAtHeightReturnsOnCall == nil {
+		fake.getStateAtHeightReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getStateAtHeightReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) GetStateByPartialCompositeKey(arg1 string, arg2 []string) (shim.StateQueryIteratorInterface, error) {
 	var arg2Copy []string
 	if arg2 != nil {
@@ -2039,6 +2390,71 @@ func (fake *ChaincodeStub) GetStateByRangeWithPaginationReturnsOnCall(i int, res
 	}{result1, result2, result3}
 }
 
+func (fake *ChaincodeStub) GetStateRangeAtHeight(arg1 string, arg2 string, arg3 uint64) (shim.StateQueryIteratorInterface, error) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	ret, specificReturn := fake.getStateRangeAtHeightReturnsOnCall[len(fake.getStateRangeAtHeightArgsForCall)]
+	fake.getStateRangeAtHeightArgsForCall = append(fake.getStateRangeAtHeightArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetStateRangeAtHeight", []interface{}{arg1, arg2, arg3})
+	fake.getStateRangeAtHeightMutex.Unlock()
+	if fake.GetStateRangeAtHeightStub != nil {
+		return fake.GetStateRangeAtHeightStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStateRangeAtHeightReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetStateRangeAtHeightCallCount() int {
+	fake.getStateRangeAtHeightMutex.RLock()
+	defer fake.getStateRangeAtHeightMutex.RUnlock()
+	return len(fake.getStateRangeAtHeightArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetStateRangeAtHeightCalls(stub func(string, string, uint64) (shim.StateQueryIteratorInterface, error)) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	defer fake.getStateRangeAtHeightMutex.Unlock()
+	fake.GetStateRangeAtHeightStub = stub
+}
+
+func (fake *ChaincodeStub) GetStateRangeAtHeightArgsForCall(i int) (string, string, uint64) {
+	fake.getStateRangeAtHeightMutex.RLock()
+	defer fake.getStateRangeAtHeightMutex.RUnlock()
+	argsForCall := fake.getStateRangeAtHeightArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ChaincodeStub) GetStateRangeAtHeightReturns(result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	defer fake.getStateRangeAtHeightMutex.Unlock()
+	fake.GetStateRangeAtHeightStub = nil
+	fake.getStateRangeAtHeightReturns = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetStateRangeAtHeightReturnsOnCall(i int, result1 shim.StateQueryIteratorInterface, result2 error) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	defer fake.getStateRangeAtHeightMutex.Unlock()
+	fake.GetStateRangeAtHeightStub = nil
+	if fake.getStateRangeAtHeightReturnsOnCall == nil {
+		fake.getStateRangeAtHeightReturnsOnCall = make(map[int]struct {
+			result1 shim.StateQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getStateRangeAtHeightReturnsOnCall[i] = struct {
+		result1 shim.StateQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) GetStateValidationParameter(arg1 string) ([]byte, error) {
 	fake.getStateValidationParameterMutex.Lock()
 	ret, specificReturn := fake.getStateValidationParameterReturnsOnCall[len(fake.getStateValidationParameterArgsForCall)]
@@ -2806,6 +3222,12 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getFunctionAndParametersMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyRangeMutex.RLock()
+	defer fake.getHistoryForKeyRangeMutex.RUnlock()
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	fake.getHistoryForPartialCompositeKeyMutex.RLock()
+	defer fake.getHistoryForPartialCompositeKeyMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
 	fake.getPrivateDataByPartialCompositeKeyMutex.RLock()
@@ -2826,6 +3248,8 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getSignedProposalMutex.RUnlock()
 	fake.getStateMutex.RLock()
 	defer fake.getStateMutex.RUnlock()
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
 	fake.getStateByPartialCompositeKeyMutex.RLock()
 	defer fake.getStateByPartialCompositeKeyMutex.RUnlock()
 	fake.getStateByPartialCompositeKeyWithPaginationMutex.RLock()
@@ -2834,6 +3258,8 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getStateByRangeMutex.RUnlock()
 	fake.getStateByRangeWithPaginationMutex.RLock()
 	defer fake.getStateByRangeWithPaginationMutex.RUnlock()
+	fake.getStateRangeAtHeightMutex.RLock()
+	defer fake.getStateRangeAtHeightMutex.RUnlock()
 	fake.getStateValidationParameterMutex.RLock()
 	defer fake.getStateValidationParameterMutex.RUnlock()
 	fake.getStringArgsMutex.RLock()
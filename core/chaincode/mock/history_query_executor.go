@@ -5,6 +5,7 @@ import (
 	sync "sync"
 
 	ledger "github.com/hyperledger/fabric/common/ledger"
+	ledgera "github.com/hyperledger/fabric/core/ledger"
 )
 
 type HistoryQueryExecutor struct {
@@ -22,6 +23,68 @@ type HistoryQueryExecutor struct {
 		result1 ledger.ResultsIterator
 		result2 error
 	}
+	GetHistoryForKeyRangeStub        func(string, string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)
+	getHistoryForKeyRangeMutex       sync.RWMutex
+	getHistoryForKeyRangeArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *ledgera.HistoryQueryOptions
+	}
+	getHistoryForKeyRangeReturns struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
+	getHistoryForKeyRangeReturnsOnCall map[int]struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
+	GetHistoryForKeyWithOptionsStub        func(string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)
+	getHistoryForKeyWithOptionsMutex       sync.RWMutex
+	getHistoryForKeyWithOptionsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}
+	getHistoryForKeyWithOptionsReturns struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
+	getHistoryForKeyWithOptionsReturnsOnCall map[int]struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}
+	GetStateAtHeightStub        func(string, string, uint64) ([]byte, error)
+	getStateAtHeightMutex       sync.RWMutex
+	getStateAtHeightArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}
+	getStateAtHeightReturns struct {
+		result1 []byte
+		result2 error
+	}
+	getStateAtHeightReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	GetStateRangeAtHeightStub        func(string, string, string, uint64) (ledger.ResultsIterator, error)
+	getStateRangeAtHeightMutex       sync.RWMutex
+	getStateRangeAtHeightArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 uint64
+	}
+	getStateRangeAtHeightReturns struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	getStateRangeAtHeightReturnsOnCall map[int]struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -90,11 +153,281 @@ func (fake *HistoryQueryExecutor) GetHistoryForKeyReturnsOnCall(i int, result1 l
 	}{result1, result2}
 }
 
+func (fake *HistoryQueryExecutor) GetHistoryForKeyRange(arg1 string, arg2 string, arg3 string, arg4 *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyRangeReturnsOnCall[len(fake.getHistoryForKeyRangeArgsForCall)]
+	fake.getHistoryForKeyRangeArgsForCall = append(fake.getHistoryForKeyRangeArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 *ledgera.HistoryQueryOptions
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetHistoryForKeyRange", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getHistoryForKeyRangeMutex.Unlock()
+	if fake.GetHistoryForKeyRangeStub != nil {
+		return fake.GetHistoryForKeyRangeStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getHistoryForKeyRangeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyRangeCallCount() int {
+	fake.getHistoryForKeyRangeMutex.RLock()
+	defer fake.getHistoryForKeyRangeMutex.RUnlock()
+	return len(fake.getHistoryForKeyRangeArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyRangeCalls(stub func(string, string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	defer fake.getHistoryForKeyRangeMutex.Unlock()
+	fake.GetHistoryForKeyRangeStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyRangeArgsForCall(i int) (string, string, string, *ledgera.HistoryQueryOptions) {
+	fake.getHistoryForKeyRangeMutex.RLock()
+	defer fake.getHistoryForKeyRangeMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyRangeReturns(result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	defer fake.getHistoryForKeyRangeMutex.Unlock()
+	fake.GetHistoryForKeyRangeStub = nil
+	fake.getHistoryForKeyRangeReturns = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyRangeReturnsOnCall(i int, result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyRangeMutex.Lock()
+	defer fake.getHistoryForKeyRangeMutex.Unlock()
+	fake.GetHistoryForKeyRangeStub = nil
+	if fake.getHistoryForKeyRangeReturnsOnCall == nil {
+		fake.getHistoryForKeyRangeReturnsOnCall = make(map[int]struct {
+			result1 ledgera.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.getHistoryForKeyRangeReturnsOnCall[i] = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptions(arg1 string, arg2 string, arg3 *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithOptionsReturnsOnCall[len(fake.getHistoryForKeyWithOptionsArgsForCall)]
+	fake.getHistoryForKeyWithOptionsArgsForCall = append(fake.getHistoryForKeyWithOptionsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetHistoryForKeyWithOptions", []interface{}{arg1, arg2, arg3})
+	fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	if fake.GetHistoryForKeyWithOptionsStub != nil {
+		return fake.GetHistoryForKeyWithOptionsStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getHistoryForKeyWithOptionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsCallCount() int {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithOptionsArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsCalls(stub func(string, string, *ledgera.HistoryQueryOptions) (ledgera.QueryResultsIterator, error)) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsArgsForCall(i int) (string, string, *ledgera.HistoryQueryOptions) {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturns(result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	fake.getHistoryForKeyWithOptionsReturns = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturnsOnCall(i int, result1 ledgera.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	if fake.getHistoryForKeyWithOptionsReturnsOnCall == nil {
+		fake.getHistoryForKeyWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 ledgera.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.getHistoryForKeyWithOptionsReturnsOnCall[i] = struct {
+		result1 ledgera.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetStateAtHeight(arg1 string, arg2 string, arg3 uint64) ([]byte, error) {
+	fake.getStateAtHeightMutex.Lock()
+	ret, specificReturn := fake.getStateAtHeightReturnsOnCall[len(fake.getStateAtHeightArgsForCall)]
+	fake.getStateAtHeightArgsForCall = append(fake.getStateAtHeightArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetStateAtHeight", []interface{}{arg1, arg2, arg3})
+	fake.getStateAtHeightMutex.Unlock()
+	if fake.GetStateAtHeightStub != nil {
+		return fake.GetStateAtHeightStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStateAtHeightReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetStateAtHeightCallCount() int {
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
+	return len(fake.getStateAtHeightArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetStateAtHeightCalls(stub func(string, string, uint64) ([]byte, error)) {
+	fake.getStateAtHeightMutex.Lock()
+	defer fake.getStateAtHeightMutex.Unlock()
+	fake.GetStateAtHeightStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetStateAtHeightArgsForCall(i int) (string, string, uint64) {
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
+	argsForCall := fake.getStateAtHeightArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HistoryQueryExecutor) GetStateAtHeightReturns(result1 []byte, result2 error) {
+	fake.getStateAtHeightMutex.Lock()
+	defer fake.getStateAtHeightMutex.Unlock()
+	fake.GetStateAtHeightStub = nil
+	fake.getStateAtHeightReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetStateAtHeightReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.getStateAtHeightMutex.Lock()
+	defer fake.getStateAtHeightMutex.Unlock()
+	fake.GetStateAtHeightStub = nil
+	if fake.getStateAtHeightReturnsOnCall == nil {
+		fake.getStateAtHeightReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.getStateAtHeightReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetStateRangeAtHeight(arg1 string, arg2 string, arg3 string, arg4 uint64) (ledger.ResultsIterator, error) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	ret, specificReturn := fake.getStateRangeAtHeightReturnsOnCall[len(fake.getStateRangeAtHeightArgsForCall)]
+	fake.getStateRangeAtHeightArgsForCall = append(fake.getStateRangeAtHeightArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+		arg4 uint64
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetStateRangeAtHeight", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getStateRangeAtHeightMutex.Unlock()
+	if fake.GetStateRangeAtHeightStub != nil {
+		return fake.GetStateRangeAtHeightStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStateRangeAtHeightReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetStateRangeAtHeightCallCount() int {
+	fake.getStateRangeAtHeightMutex.RLock()
+	defer fake.getStateRangeAtHeightMutex.RUnlock()
+	return len(fake.getStateRangeAtHeightArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetStateRangeAtHeightCalls(stub func(string, string, string, uint64) (ledger.ResultsIterator, error)) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	defer fake.getStateRangeAtHeightMutex.Unlock()
+	fake.GetStateRangeAtHeightStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetStateRangeAtHeightArgsForCall(i int) (string, string, string, uint64) {
+	fake.getStateRangeAtHeightMutex.RLock()
+	defer fake.getStateRangeAtHeightMutex.RUnlock()
+	argsForCall := fake.getStateRangeAtHeightArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *HistoryQueryExecutor) GetStateRangeAtHeightReturns(result1 ledger.ResultsIterator, result2 error) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	defer fake.getStateRangeAtHeightMutex.Unlock()
+	fake.GetStateRangeAtHeightStub = nil
+	fake.getStateRangeAtHeightReturns = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetStateRangeAtHeightReturnsOnCall(i int, result1 ledger.ResultsIterator, result2 error) {
+	fake.getStateRangeAtHeightMutex.Lock()
+	defer fake.getStateRangeAtHeightMutex.Unlock()
+	fake.GetStateRangeAtHeightStub = nil
+	if fake.getStateRangeAtHeightReturnsOnCall == nil {
+		fake.getStateRangeAtHeightReturnsOnCall = make(map[int]struct {
+			result1 ledger.ResultsIterator
+			result2 error
+		})
+	}
+	fake.getStateRangeAtHeightReturnsOnCall[i] = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HistoryQueryExecutor) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyRangeMutex.RLock()
+	defer fake.getHistoryForKeyRangeMutex.RUnlock()
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	fake.getStateAtHeightMutex.RLock()
+	defer fake.getStateAtHeightMutex.RUnlock()
+	fake.getStateRangeAtHeightMutex.RLock()
+	defer fake.getStateRangeAtHeightMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -21,6 +21,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
@@ -715,6 +716,113 @@ func (stub *ChaincodeStub) GetHistoryForKey(key string) (HistoryQueryIteratorInt
 	return &HistoryQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
 }
 
+func createHistoryQueryMetadata(options *HistoryQueryOptions) ([]byte, error) {
+	if options == nil {
+		options = &HistoryQueryOptions{}
+	}
+	metadata := &pb.HistoryQueryMetadata{
+		StartBlock:  options.StartBlock,
+		EndBlock:    options.EndBlock,
+		NewestFirst: options.NewestFirst,
+		PageSize:    options.PageSize,
+		Bookmark:    options.Bookmark,
+	}
+	var err error
+	if !options.StartTime.IsZero() {
+		if metadata.StartTime, err = ptypes.TimestampProto(options.StartTime); err != nil {
+			return nil, errors.WithMessage(err, "invalid start time")
+		}
+	}
+	if !options.EndTime.IsZero() {
+		if metadata.EndTime, err = ptypes.TimestampProto(options.EndTime); err != nil {
+			return nil, errors.WithMessage(err, "invalid end time")
+		}
+	}
+	return proto.Marshal(metadata)
+}
+
+func (stub *ChaincodeStub) createHistoryQueryIterator(response *pb.QueryResponse) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	iterator := &HistoryQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}
+	responseMetadata, err := createQueryResponseMetadata(response.Metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return iterator, responseMetadata, nil
+}
+
+// GetHistoryForKeyWithOptions documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetHistoryForKeyWithOptions(key string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+
+	metadata, err := createHistoryQueryMetadata(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := stub.handler.handleGetHistoryForKeyWithOptions(key, metadata, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stub.createHistoryQueryIterator(response)
+}
+
+func (stub *ChaincodeStub) handleGetHistoryForKeyRange(startKey, endKey string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+
+	metadata, err := createHistoryQueryMetadata(options)
+	if err != nil {
+		return nil, nil, err
+	}
+	response, err := stub.handler.handleGetHistoryForKeyRange(startKey, endKey, metadata, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stub.createHistoryQueryIterator(response)
+}
+
+// GetHistoryForKeyRange documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetHistoryForKeyRange(startKey, endKey string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, nil, err
+	}
+	return stub.handleGetHistoryForKeyRange(startKey, endKey, options)
+}
+
+// GetHistoryForPartialCompositeKey documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetHistoryForPartialCompositeKey(objectType string, keys []string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+
+	startKey, endKey, err := stub.createRangeKeysForPartialCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stub.handleGetHistoryForKeyRange(startKey, endKey, options)
+}
+
+// GetStateAtHeight documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateAtHeight(key string, blockNum uint64) ([]byte, error) {
+	return stub.handler.handleGetStateAtHeight(key, blockNum, stub.ChannelId, stub.TxID)
+}
+
+// GetStateRangeAtHeight documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateRangeAtHeight(startKey, endKey string, blockNum uint64) (StateQueryIteratorInterface, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, err
+	}
+	response, err := stub.handler.handleGetStateByRangeAtHeight(startKey, endKey, blockNum, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return stub.createStateQueryIterator(response), nil
+}
+
 //CreateCompositeKey documentation can be found in interfaces.go
 func (stub *ChaincodeStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
 	return createCompositeKey(objectType, attributes)
@@ -708,6 +708,146 @@ func (handler *Handler) handleGetHistoryForKey(key string, channelId string, txi
 	return nil, errors.Errorf("incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
 }
 
+func (handler *Handler) handleGetHistoryForKeyWithOptions(key string, metadata []byte, channelId string, txid string) (*pb.QueryResponse, error) {
+	// Send GET_HISTORY_FOR_KEY_WITH_OPTIONS message to peer chaincode support
+	//we constructed a valid object. No need to check for error
+	payloadBytes, _ := proto.Marshal(&pb.GetHistoryForKeyWithOptions{Key: key, Metadata: metadata})
+
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
+	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS)
+
+	responseMsg, err := handler.callPeerWithChaincodeMsg(msg, channelId, txid)
+	if err != nil {
+		return nil, errors.Errorf("[%s] error sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS)
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s] Received %s. Successfully got history", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+
+		getHistoryForKeyResponse := &pb.QueryResponse{}
+		if err = proto.Unmarshal(responseMsg.Payload, getHistoryForKeyResponse); err != nil {
+			chaincodeLogger.Errorf("[%s] unmarshal error", shorttxid(responseMsg.Txid))
+			return nil, errors.Errorf("[%s] GetHistoryForKeyWithOptionsResponse unmarshall error", shorttxid(responseMsg.Txid))
+		}
+
+		return getHistoryForKeyResponse, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s] Received %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("Incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.Errorf("incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+}
+
+func (handler *Handler) handleGetHistoryForKeyRange(startKey, endKey string, metadata []byte,
+	channelId string, txid string) (*pb.QueryResponse, error) {
+	// Send GET_HISTORY_FOR_KEY_RANGE message to peer chaincode support
+	//we constructed a valid object. No need to check for error
+	payloadBytes, _ := proto.Marshal(&pb.GetHistoryForKeyRange{StartKey: startKey, EndKey: endKey, Metadata: metadata})
+
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
+	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE)
+
+	responseMsg, err := handler.callPeerWithChaincodeMsg(msg, channelId, txid)
+	if err != nil {
+		return nil, errors.Errorf("[%s] error sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE)
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s] Received %s. Successfully got range", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+
+		getHistoryForKeyRangeResponse := &pb.QueryResponse{}
+		if err = proto.Unmarshal(responseMsg.Payload, getHistoryForKeyRangeResponse); err != nil {
+			chaincodeLogger.Errorf("[%s] unmarshal error", shorttxid(responseMsg.Txid))
+			return nil, errors.Errorf("[%s] GetHistoryForKeyRangeResponse unmarshall error", shorttxid(responseMsg.Txid))
+		}
+
+		return getHistoryForKeyRangeResponse, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s] Received %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("Incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.Errorf("incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+}
+
+// handleGetStateAtHeight communicates with the peer to fetch the value of a key at a height of the ledger.
+func (handler *Handler) handleGetStateAtHeight(key string, blockNum uint64, channelId string, txid string) ([]byte, error) {
+	// Construct payload for GET_STATE_AT_HEIGHT
+	payloadBytes, _ := proto.Marshal(&pb.GetStateAtHeight{Key: key, BlockNumber: blockNum})
+
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_AT_HEIGHT, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
+	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_AT_HEIGHT)
+
+	responseMsg, err := handler.callPeerWithChaincodeMsg(msg, channelId, txid)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("[%s] error sending GET_STATE_AT_HEIGHT", shorttxid(txid)))
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s] GetStateAtHeight received payload %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+		return responseMsg.Payload, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s] GetStateAtHeight received error %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("[%s] Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.Errorf("[%s] incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+}
+
+func (handler *Handler) handleGetStateByRangeAtHeight(startKey, endKey string, blockNum uint64,
+	channelId string, txid string) (*pb.QueryResponse, error) {
+	// Send GET_STATE_BY_RANGE_AT_HEIGHT message to peer chaincode support
+	//we constructed a valid object. No need to check for error
+	payloadBytes, _ := proto.Marshal(&pb.GetStateByRangeAtHeight{StartKey: startKey, EndKey: endKey, BlockNumber: blockNum})
+
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT, Payload: payloadBytes, Txid: txid, ChannelId: channelId}
+	chaincodeLogger.Debugf("[%s] Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT)
+
+	responseMsg, err := handler.callPeerWithChaincodeMsg(msg, channelId, txid)
+	if err != nil {
+		return nil, errors.Errorf("[%s] error sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT)
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s] Received %s. Successfully got range", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+
+		rangeQueryResponse := &pb.QueryResponse{}
+		err = proto.Unmarshal(responseMsg.Payload, rangeQueryResponse)
+		if err != nil {
+			chaincodeLogger.Errorf("[%s] unmarshal error", shorttxid(responseMsg.Txid))
+			return nil, errors.Errorf("[%s] GetStateByRangeAtHeightResponse unmarshall error", shorttxid(responseMsg.Txid))
+		}
+
+		return rangeQueryResponse, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s] Received %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("Incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.Errorf("incorrect chaincode message %s received. Expecting %s or %s", responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+}
+
 func (handler *Handler) createResponse(status int32, payload []byte) pb.Response {
 	return pb.Response{Status: status, Payload: payload}
 }
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package shim
 
 import (
+	"time"
+
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -233,6 +235,76 @@ type ChaincodeStubInterface interface {
 	// update ledger, and should limit use to read-only chaincode operations.
 	GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error)
 
+	// GetStateAtHeight returns the value of the specified `key` as it was
+	// after the block `blockNum` was committed. If the key did not exist or
+	// was deleted at that height, (nil, nil) is returned.
+	// GetStateAtHeight requires peer configuration
+	// core.ledger.history.enableHistoryDatabase to be true. On a peer which
+	// joined the channel from a snapshot, an error is returned for the heights
+	// before the snapshot, and for the keys not written since the snapshot.
+	// The query is NOT re-executed during validation phase and the key is
+	// not added to the read set of the transaction. Applications should
+	// limit its use to read-only chaincode operations, such as audits.
+	GetStateAtHeight(key string, blockNum uint64) ([]byte, error)
+
+	// GetStateRangeAtHeight returns a range iterator over the keys of the
+	// ledger, with their values as they were after the block `blockNum`
+	// was committed. The startKey is included whereas the endKey is excluded,
+	// and the keys which did not exist or were deleted at that height are
+	// skipped. As for GetStateByRange, the keys are returned in lexical order,
+	// the startKey and endKey can be empty strings, which implies unbounded
+	// range query on start or end, and the results are capped by the
+	// totalQueryLimit (defined in core.yaml).
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	// GetStateRangeAtHeight requires peer configuration
+	// core.ledger.history.enableHistoryDatabase to be true, and is not
+	// supported on a peer which joined the channel from a snapshot.
+	// The query is NOT re-executed during validation phase, phantom reads are
+	// not detected. Applications should limit its use to read-only chaincode
+	// operations, such as audits.
+	GetStateRangeAtHeight(startKey, endKey string, blockNum uint64) (StateQueryIteratorInterface, error)
+
+	// GetHistoryForKeyWithOptions returns a history of key values across time,
+	// as GetHistoryForKey does, filtered by the range of blocks and the time
+	// window of the options, and ordered from the newest to the oldest value
+	// if NewestFirst is set.
+	// When the options hold a page size, the returned iterator can be used to
+	// fetch the first `PageSize` values of the history, and a bookmark is
+	// returned in the ResponseMetadata, which can be set in the options to
+	// fetch the next page. Only the bookmark present in a prior page of query
+	// results (ResponseMetadata) of the same history query can be used.
+	// GetHistoryForKeyWithOptions requires peer configuration
+	// core.ledger.history.enableHistoryDatabase to be true.
+	// The query is NOT re-executed during validation phase, phantom reads are
+	// not detected. Applications should limit its use to read-only chaincode
+	// operations.
+	GetHistoryForKeyWithOptions(key string, options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
+	// GetHistoryForKeyRange returns the history of the values across time of
+	// the keys between the startKey (inclusive) and endKey (exclusive), key by
+	// key in lexical order, or in reverse order if NewestFirst is set.
+	// The Key of each returned KeyModification is set. Note that startKey and
+	// endKey can be empty string, which implies unbounded range query on start
+	// or end. The options filter, order and paginate the results as for
+	// GetHistoryForKeyWithOptions, and the results are capped by the
+	// totalQueryLimit (defined in core.yaml).
+	// Call Close() on the returned HistoryQueryIteratorInterface object when done.
+	// GetHistoryForKeyRange requires peer configuration
+	// core.ledger.history.enableHistoryDatabase to be true.
+	// The query is NOT re-executed during validation phase, phantom reads are
+	// not detected. Applications should limit its use to read-only chaincode
+	// operations.
+	GetHistoryForKeyRange(startKey, endKey string, options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
+	// GetHistoryForPartialCompositeKey returns the history of the values across
+	// time of the composite keys whose prefix matches the given partial
+	// composite key, as GetHistoryForKeyRange does for a range of keys.
+	// The `objectType` and attributes are expected to have only valid utf8
+	// strings and should not contain U+0000 (nil byte) and U+10FFFF (biggest
+	// and unallocated code point).
+	GetHistoryForPartialCompositeKey(objectType string, keys []string,
+		options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetPrivateData returns the value of the specified `key` from the specified
 	// `collection`. Note that GetPrivateData doesn't read data from the
 	// private writeset, which has not been committed to the `collection`. In
@@ -372,6 +444,25 @@ type StateQueryIteratorInterface interface {
 	Next() (*queryresult.KV, error)
 }
 
+// HistoryQueryOptions filters, orders and paginates the results of a history
+// query. The zero value of an option leaves it unset.
+type HistoryQueryOptions struct {
+	// StartBlock and EndBlock bound, inclusively, the blocks of the returned
+	// values. An EndBlock of 0 denotes the last block.
+	StartBlock uint64
+	EndBlock   uint64
+	// StartTime (inclusive) and EndTime (exclusive) bound the timestamps of
+	// the transactions of the returned values.
+	StartTime time.Time
+	EndTime   time.Time
+	// NewestFirst returns the values from the newest to the oldest.
+	NewestFirst bool
+	// PageSize is the number of values to fetch, and Bookmark is the bookmark
+	// of the ResponseMetadata of the previous page.
+	PageSize int32
+	Bookmark string
+}
+
 // HistoryQueryIteratorInterface allows a chaincode to iterate over a set of
 // key/value pairs returned by a history query.
 type HistoryQueryIteratorInterface interface {
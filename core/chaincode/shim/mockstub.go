@@ -296,6 +296,39 @@ func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterfac
 	return nil, errors.New("not implemented")
 }
 
+// GetStateAtHeight function can be invoked by a chaincode to return the value of
+// a key at a height of the ledger. The mock does not keep the history of the state.
+func (stub *MockStub) GetStateAtHeight(key string, blockNum uint64) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+// GetStateRangeAtHeight function can be invoked by a chaincode to return the values of
+// a range of keys at a height of the ledger. The mock does not keep the history of the state.
+func (stub *MockStub) GetStateRangeAtHeight(startKey, endKey string, blockNum uint64) (StateQueryIteratorInterface, error) {
+	return nil, errors.New("not implemented")
+}
+
+// GetHistoryForKeyWithOptions function can be invoked by a chaincode to return a filtered
+// and paginated history of a key. The mock does not keep the history of the state.
+func (stub *MockStub) GetHistoryForKeyWithOptions(key string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// GetHistoryForKeyRange function can be invoked by a chaincode to return the history
+// of a range of keys. The mock does not keep the history of the state.
+func (stub *MockStub) GetHistoryForKeyRange(startKey, endKey string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// GetHistoryForPartialCompositeKey function can be invoked by a chaincode to return the
+// history of the keys matching a partial composite key. The mock does not keep the history of the state.
+func (stub *MockStub) GetHistoryForPartialCompositeKey(objectType string, keys []string,
+	options *HistoryQueryOptions) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
 //state based on a given partial composite key. This function returns an
 //iterator which can be used to iterate over all composite keys whose prefix
@@ -314,6 +314,11 @@ func TestMockMock(t *testing.T) {
 	stub.GetArgsSlice()
 	stub.SetEvent("e", nil)
 	stub.GetHistoryForKey("k")
+	stub.GetStateAtHeight("k", 1)
+	stub.GetStateRangeAtHeight("start", "end", 1)
+	stub.GetHistoryForKeyWithOptions("k", &HistoryQueryOptions{NewestFirst: true})
+	stub.GetHistoryForKeyRange("start", "end", nil)
+	stub.GetHistoryForPartialCompositeKey("obj", []string{"a"}, nil)
 	iter := &MockStateRangeQueryIterator{}
 	iter.HasNext()
 	iter.Close()
@@ -81,6 +81,14 @@ func (t *shimTestCC) Invoke(stub ChaincodeStubInterface) pb.Response {
 		return t.rangeq(stub, args)
 	} else if function == "historyq" {
 		return t.historyq(stub, args)
+	} else if function == "historyoptq" {
+		return t.historyoptq(stub, args)
+	} else if function == "historyrangeq" {
+		return t.historyrangeq(stub, args)
+	} else if function == "heightq" {
+		return t.heightq(stub, args)
+	} else if function == "rangeheightq" {
+		return t.rangeheightq(stub, args)
 	} else if function == "richq" {
 		return t.richq(stub, args)
 	} else if function == "putep" {
@@ -342,6 +350,116 @@ func (t *shimTestCC) historyq(stub ChaincodeStubInterface, args []string) pb.Res
 	return Success(buffer.Bytes())
 }
 
+// historyoptq calls paginated history query for a key
+func (t *shimTestCC) historyoptq(stub ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 2 {
+		return Error("Incorrect number of arguments. Expecting key for history query and page size")
+	}
+
+	pageSize, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	options := &HistoryQueryOptions{NewestFirst: true, PageSize: int32(pageSize)}
+	resultsIterator, responseMetadata, err := stub.GetHistoryForKeyWithOptions(args[0], options)
+	if err != nil {
+		return Error(err.Error())
+	}
+	defer resultsIterator.Close()
+
+	var buffer bytes.Buffer
+	for resultsIterator.HasNext() {
+		response, err := resultsIterator.Next()
+		if err != nil {
+			return Error(err.Error())
+		}
+		buffer.WriteString(response.TxId + ";")
+	}
+	buffer.WriteString("bookmark=" + responseMetadata.Bookmark)
+
+	return Success(buffer.Bytes())
+}
+
+// historyrangeq calls paginated history query for a range of keys
+func (t *shimTestCC) historyrangeq(stub ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 3 {
+		return Error("Incorrect number of arguments. Expecting keys for range query and page size")
+	}
+
+	pageSize, err := strconv.ParseInt(args[2], 10, 32)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	options := &HistoryQueryOptions{NewestFirst: true, PageSize: int32(pageSize)}
+	resultsIterator, responseMetadata, err := stub.GetHistoryForKeyRange(args[0], args[1], options)
+	if err != nil {
+		return Error(err.Error())
+	}
+	defer resultsIterator.Close()
+
+	var buffer bytes.Buffer
+	for resultsIterator.HasNext() {
+		response, err := resultsIterator.Next()
+		if err != nil {
+			return Error(err.Error())
+		}
+		buffer.WriteString(response.Key + "=" + response.TxId + ";")
+	}
+	buffer.WriteString("bookmark=" + responseMetadata.Bookmark)
+
+	return Success(buffer.Bytes())
+}
+
+// heightq queries the value of a key at a height
+func (t *shimTestCC) heightq(stub ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 2 {
+		return Error("Incorrect number of arguments. Expecting key and block number")
+	}
+
+	blockNum, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	value, err := stub.GetStateAtHeight(args[0], blockNum)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	return Success(value)
+}
+
+// rangeheightq calls range query at a height
+func (t *shimTestCC) rangeheightq(stub ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 3 {
+		return Error("Incorrect number of arguments. Expecting keys for range query and block number")
+	}
+
+	blockNum, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	resultsIterator, err := stub.GetStateRangeAtHeight(args[0], args[1], blockNum)
+	if err != nil {
+		return Error(err.Error())
+	}
+	defer resultsIterator.Close()
+
+	var buffer bytes.Buffer
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return Error(err.Error())
+		}
+		buffer.WriteString(queryResponse.Key + "=" + string(queryResponse.Value) + ";")
+	}
+
+	return Success(buffer.Bytes())
+}
+
 func (t *shimTestCC) putEP(stub ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 	err := stub.SetStateValidationParameter(string(args[1]), args[2])
@@ -876,6 +994,177 @@ func TestInvoke(t *testing.T) {
 	//wait for done
 	processDone(t, done, false)
 
+	//query at height
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_AT_HEIGHT, Txid: "7b", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: []byte("100"), Txid: "7b", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7b", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("heightq"), []byte("A"), []byte("5")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7b", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//error query at height
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_AT_HEIGHT, Txid: "7c", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: "7c", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7c", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("heightq"), []byte("A"), []byte("5")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7c", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//range query at height
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT, Txid: "7d", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: rangeQPayload, Txid: "7d", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_QUERY_STATE_NEXT, Txid: "7d", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: utils.MarshalOrPanic(rangeQueryNext), Txid: "7d", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_QUERY_STATE_CLOSE, Txid: "7d", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: "7d", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7d", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("rangeheightq"), []byte("A"), []byte("C"), []byte("5")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7d", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//error range query at height
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT, Txid: "7e", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: "7e", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7e", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("rangeheightq"), []byte("A"), []byte("C"), []byte("5")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7e", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//history query for a key with options
+
+	//create the response
+	historyOptionsQueryResponse := &pb.QueryResponse{Results: []*pb.QueryResultBytes{
+		{ResultBytes: utils.MarshalOrPanic(&lproto.KeyModification{Key: "A", TxId: "6", Value: []byte("100")})}},
+		HasMore:  false,
+		Metadata: utils.MarshalOrPanic(&pb.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: "41"})}
+	payload = utils.MarshalOrPanic(historyOptionsQueryResponse)
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS, Txid: "7h", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payload, Txid: "7h", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_QUERY_STATE_CLOSE, Txid: "7h", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: "7h", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7h", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("historyoptq"), []byte("A"), []byte("1")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7h", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//error history query for a key with options
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS, Txid: "7i", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: "7i", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7i", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("historyoptq"), []byte("A"), []byte("1")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7i", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//history query for a range of keys
+
+	//create the response
+	historyRangeQueryResponse := &pb.QueryResponse{Results: []*pb.QueryResultBytes{
+		{ResultBytes: utils.MarshalOrPanic(&lproto.KeyModification{Key: "B", TxId: "6", Value: []byte("200")})},
+		{ResultBytes: utils.MarshalOrPanic(&lproto.KeyModification{Key: "A", TxId: "6", Value: []byte("100")})}},
+		HasMore:  false,
+		Metadata: utils.MarshalOrPanic(&pb.QueryResponseMetadata{FetchedRecordsCount: 2, Bookmark: "41"})}
+	payload = utils.MarshalOrPanic(historyRangeQueryResponse)
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE, Txid: "7f", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payload, Txid: "7f", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_QUERY_STATE_CLOSE, Txid: "7f", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Txid: "7f", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7f", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("historyrangeq"), []byte("A"), []byte("C"), []byte("2")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7f", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
+	//error history query for a range of keys
+
+	respSet = &mockpeer.MockResponseSet{
+		DoneFunc:  errorFunc,
+		ErrorFunc: errorFunc,
+		Responses: []*mockpeer.MockResponse{
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE, Txid: "7g", ChannelId: channelId}, RespMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: "7g", ChannelId: channelId}},
+			{RecvMsg: &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "7g", ChannelId: channelId}, RespMsg: nil},
+		},
+	}
+	peerSide.SetResponses(respSet)
+
+	ci = &pb.ChaincodeInput{Args: [][]byte{[]byte("historyrangeq"), []byte("A"), []byte("C"), []byte("2")}, Decorations: nil}
+	payload = utils.MarshalOrPanic(ci)
+	peerSide.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Payload: payload, Txid: "7g", ChannelId: channelId})
+
+	//wait for done
+	processDone(t, done, false)
+
 	//query result
 
 	//create the response
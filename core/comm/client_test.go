@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	tls "github.com/Hyperledger-TWGC/tjfoc-gm/gmtls"
+	"github.com/Hyperledger-TWGC/tjfoc-gm/gmtls/gmcredentials"
 	x509 "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"io/ioutil"
 	"net"
@@ -339,7 +340,7 @@ func TestNewConnection(t *testing.T) {
 			defer lis.Close()
 			serverOpts := []grpc.ServerOption{}
 			if test.serverTLS != nil {
-				serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(test.serverTLS)))
+				serverOpts = append(serverOpts, grpc.Creds(gmcredentials.NewTLS(test.serverTLS)))
 			}
 			srv := grpc.NewServer(serverOpts...)
 			defer srv.Stop()
@@ -390,7 +391,7 @@ func TestSetServerRootCAs(t *testing.T) {
 	t.Logf("server listening on [%s]", lis.Addr().String())
 	t.Logf("client will use [%s]", address)
 	defer lis.Close()
-	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+	srv := grpc.NewServer(grpc.Creds(gmcredentials.NewTLS(&tls.Config{
 		Certificates: []tls.Certificate{testCerts.serverCert},
 	})))
 	defer srv.Stop()
@@ -105,6 +105,11 @@ type SecureOptions struct {
 	Certificate []byte
 	// PEM-encoded private key to be used for TLS communication
 	Key []byte
+	// PEM-encoded SM2 X509 certificate and private key used by GM TLS
+	// (GM/T 0024) servers for the key exchange. When not set, a GM TLS
+	// server uses its signing certificate and key for the key exchange as well
+	EncCertificate []byte
+	EncKey         []byte
 	// Set of PEM-encoded X509 certificate authorities used by clients to
 	// verify server certificates
 	ServerRootCAs [][]byte
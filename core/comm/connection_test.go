@@ -86,7 +86,7 @@ func TestClientConnections(t *testing.T) {
 					UseTLS:      true,
 					Certificate: certPEMBlock,
 					Key:         keyPEMBlock}},
-			creds: gmcredentials.NewClientTLSFromCert(certPool, ""),
+			creds: gmcredentials.NewTLS(&tls.Config{RootCAs: certPool}),
 		},
 		{
 			name: "InvalidConnectionTLS",
@@ -102,6 +102,16 @@ func (sc *serverCreds) ClientHandshake(context.Context,
 // ServerHandshake does the authentication handshake for servers.
 func (sc *serverCreds) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
 	serverConfig := sc.serverConfig.Config()
+	// the GM TLS stack panics while completing TLS 1.0 and 1.1 handshakes,
+	// so the clients offering neither TLS 1.2 nor GM TLS are turned down
+	tls12Config := serverConfig.Clone()
+	tls12Config.MinVersion = tls.VersionTLS12
+	serverConfig.GetConfigForClient = func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+		if len(info.SupportedVersions) > 0 && info.SupportedVersions[0] < tls.VersionTLS12 {
+			return tls12Config, nil
+		}
+		return nil, nil
+	}
 
 	conn := tls.Server(rawConn, &serverConfig)
 	if err := conn.Handshake(); err != nil {
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package comm_test
 
 import (
+	stdtls "crypto/tls"
+	"crypto/x509"
+
 	tls "github.com/Hyperledger-TWGC/tjfoc-gm/gmtls"
 	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"io/ioutil"
@@ -87,9 +90,14 @@ func TestCreds(t *testing.T) {
 	wg = &sync.WaitGroup{}
 	wg.Add(1)
 	go handshake(wg)
-	_, err = tls.Dial("tcp", addr, &tls.Config{
-		RootCAs:    certPool,
-		MaxVersion: tls.VersionTLS10,
+	// the GM TLS client only supports TLS 1.2, so the standard one
+	// checks that the older protocol versions are rejected
+	stdCertPool := x509.NewCertPool()
+	stdCertPool.AppendCertsFromPEM(caPEM)
+	_, err = stdtls.Dial("tcp", addr, &stdtls.Config{
+		RootCAs:    stdCertPool,
+		MinVersion: stdtls.VersionTLS10,
+		MaxVersion: stdtls.VersionTLS10,
 	})
 	wg.Wait()
 	assert.Contains(t, err.Error(), "protocol version not supported")
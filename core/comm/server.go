@@ -89,14 +89,9 @@ func NewGRPCServerFromListener(listener net.Listener, serverConfig ServerConfig)
 				secureConfig.CipherSuites = DefaultTLSCipherSuites
 			}
 
-			var gmSupport *tls.GMSupport
-			var certificates []tls.Certificate
-
-			certificates = append(certificates, cert)
-
-			if gm.IsPureGMTLSCertificate(&cert) {
-				gmSupport = &tls.GMSupport{}
-				certificates = append(certificates, cert)
+			certificates, gmSupport, err := serverCertificates(cert, secureConfig.EncCertificate, secureConfig.EncKey)
+			if err != nil {
+				return nil, err
 			}
 
 			//base server certificate
@@ -107,6 +102,15 @@ func NewGRPCServerFromListener(listener net.Listener, serverConfig ServerConfig)
 				SessionTicketsDisabled: true,
 				CipherSuites:           secureConfig.CipherSuites,
 			})
+			// the certificate set by SetServerCertificate is presented in place of
+			// a single signing certificate, GM TLS servers keep their certificates
+			if gmSupport == nil {
+				grpcServer.tls.config.Certificates = nil
+				grpcServer.tls.config.GetCertificate = func(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+					cert := grpcServer.serverCertificate.Load().(tls.Certificate)
+					return &cert, nil
+				}
+			}
 
 			if serverConfig.SecOpts.TimeShift > 0 {
 				timeShift := serverConfig.SecOpts.TimeShift
@@ -328,3 +332,35 @@ func (gServer *GRPCServer) SetClientRootCAs(clientRoots [][]byte) error {
 	gServer.tls.SetClientCAs(certPool)
 	return nil
 }
+
+// serverCertificates returns the certificates a TLS server presents along with
+// the GM support they require. GM TLS (GM/T 0024) servers present a signing
+// certificate followed by an encryption certificate. When no encryption
+// certificate is configured, the signing certificate is presented twice.
+func serverCertificates(cert tls.Certificate, encCertPEM, encKeyPEM []byte) ([]tls.Certificate, *tls.GMSupport, error) {
+	hasEncCert := encCertPEM != nil || encKeyPEM != nil
+	if hasEncCert && (encCertPEM == nil || encKeyPEM == nil) {
+		return nil, nil, errors.New("serverConfig.SecOpts must contain both EncKey and EncCertificate, or none of them")
+	}
+
+	if !gm.IsPureGMTLSCertificate(&cert) {
+		if hasEncCert {
+			return nil, nil, errors.New("an encryption certificate requires an SM2 signing certificate")
+		}
+		return []tls.Certificate{cert}, nil, nil
+	}
+
+	encCert := cert
+	if hasEncCert {
+		var err error
+		encCert, err = tls.X509KeyPair(encCertPEM, encKeyPEM)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed loading encryption key pair: %s", err)
+		}
+		if !gm.IsPureGMTLSCertificate(&encCert) {
+			return nil, nil, errors.New("the encryption certificate must be an SM2 certificate")
+		}
+	}
+
+	return []tls.Certificate{cert, encCert}, &tls.GMSupport{}, nil
+}
@@ -82,13 +82,13 @@ func genKeyECDSA(name string) (*ecdsa.PrivateKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	//write key out to file
-	keyBytes, err := x509.MarshalECPrivateKey(priv)
+	//write key out to file, in PKCS#8 as the GM TLS stack can't load SEC 1 EC keys
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(priv)
 	keyFile, err := os.OpenFile(name+"-key.pem", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return nil, err
 	}
-	pem.Encode(keyFile, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+	pem.Encode(keyFile, &pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
 	keyFile.Close()
 	return priv, nil
 }
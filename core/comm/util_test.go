@@ -16,6 +16,9 @@ import (
 	"testing"
 	"time"
 
+	gmtls "github.com/Hyperledger-TWGC/tjfoc-gm/gmtls"
+	"github.com/Hyperledger-TWGC/tjfoc-gm/gmtls/gmcredentials"
+	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/comm"
 	grpc_testdata "github.com/hyperledger/fabric/core/comm/testdata/grpc"
@@ -39,13 +42,13 @@ func TestExtractCertificateHashFromContext(t *testing.T) {
 	ctx = peer.NewContext(context.Background(), p)
 	assert.Nil(t, comm.ExtractCertificateHashFromContext(ctx))
 
-	p.AuthInfo = credentials.TLSInfo{}
+	p.AuthInfo = gmcredentials.TLSInfo{}
 	ctx = peer.NewContext(context.Background(), p)
 	assert.Nil(t, comm.ExtractCertificateHashFromContext(ctx))
 
-	p.AuthInfo = credentials.TLSInfo{
-		State: tls.ConnectionState{
-			PeerCertificates: []*x509.Certificate{
+	p.AuthInfo = gmcredentials.TLSInfo{
+		State: gmtls.ConnectionState{
+			PeerCertificates: []*x509GM.Certificate{
 				{Raw: []byte{1, 2, 3}},
 			},
 		},
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/common/configtx"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
@@ -492,6 +493,16 @@ func (v *TxValidator) checkTxIdDupsLedger(tIdx int, chdr *common.ChannelHeader,
 		}
 	}
 
+	// if returned error is of type blkstorage.BlockPrunedError, there is a tx with
+	// the supplied id in the snapshot the ledger was created from
+	if _, isBlockPrunedErrType := err.(*blkstorage.BlockPrunedError); isBlockPrunedErrType {
+		logger.Error("Duplicate transaction found in the snapshot of the ledger, ", txID, ", skipping")
+		return &blockValidationResult{
+			tIdx:           tIdx,
+			validationCode: peer.TxValidationCode_DUPLICATE_TXID,
+		}
+	}
+
 	// if returned error is not of type blkstorage.NotFoundInIndexErr, it means
 	// we could not verify whether a tx with the supplied id is in the ledger
 	if _, isNotFoundInIndexErrType := err.(ledger.NotFoundInIndexErr); !isNotFoundInIndexErrType {
@@ -19,6 +19,7 @@ import (
 	ctxt "github.com/hyperledger/fabric/common/configtx/test"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	ledger2 "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/mocks/scc"
@@ -1666,6 +1667,16 @@ func (m *mockLedger) DoesPvtDataInfoExist(blkNum uint64) (bool, error) {
 	return args.Get(0).(bool), args.Error(1)
 }
 
+func (m *mockLedger) SubmitSnapshotRequest(blockNum uint64) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *mockLedger) PendingSnapshotRequests() ([]uint64, error) {
+	args := m.Called()
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
 func (m *mockLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
 	args := m.Called(blockNumber)
 	return args.Get(0).(*common.Block), nil
@@ -1947,6 +1958,34 @@ func TestDuplicateTxId(t *testing.T) {
 	assertion.True(txsfltr.Flag(0) == peer.TxValidationCode_DUPLICATE_TXID)
 }
 
+func TestDuplicateTxIdInSnapshot(t *testing.T) {
+	theLedger := new(mockLedger)
+	vcs := struct {
+		*mocktxvalidator.Support
+		*semaphore.Weighted
+	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
+	mp := (&scc.MocksccProviderFactory{}).NewSystemChaincodeProvider()
+	pm := &mocks.PluginMapper{}
+	validator := txvalidator.NewTxValidator("", vcs, mp, pm)
+
+	ccID := "mycc"
+	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
+
+	// the ledger was created from a snapshot which has a tx with the same txid
+	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, &blkstorage.BlockPrunedError{FirstBlockNum: 10})
+
+	b := &common.Block{
+		Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}},
+		Header: &common.BlockHeader{},
+	}
+
+	err := validator.Validate(b)
+	assert.NoError(t, err)
+
+	txsfltr := lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsfltr.Flag(0) == peer.TxValidationCode_DUPLICATE_TXID)
+}
+
 func TestValidationInvalidEndorsing(t *testing.T) {
 	theLedger := new(mockLedger)
 	vcs := struct {
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+var (
+	hashingAlgorithmsLock sync.RWMutex
+	hashingAlgorithms     = map[string]func([]byte) []byte{}
+)
+
+// SetBlockHashingAlgorithm registers the algorithm hashing the blocks of the
+// given channel. The transaction IDs of the channel are checked with it.
+func SetBlockHashingAlgorithm(chainID string, hash func([]byte) []byte) {
+	hashingAlgorithmsLock.Lock()
+	defer hashingAlgorithmsLock.Unlock()
+	hashingAlgorithms[chainID] = hash
+}
+
+// GetBlockHashingAlgorithm returns the algorithm hashing the blocks of the
+// given channel, SHA256 if none was registered for it.
+func GetBlockHashingAlgorithm(chainID string) func([]byte) []byte {
+	if hash := blockHashingAlgorithm(chainID); hash != nil {
+		return hash
+	}
+	return util.ComputeSHA256
+}
+
+func blockHashingAlgorithm(chainID string) func([]byte) []byte {
+	hashingAlgorithmsLock.RLock()
+	defer hashingAlgorithmsLock.RUnlock()
+	return hashingAlgorithms[chainID]
+}
+
+// checkTxID checks that txid has been computed over the concatenation of
+// nonce and creator with the hashing algorithm of the given channel.
+func checkTxID(chainID, txid string, nonce, creator []byte) error {
+	hash := blockHashingAlgorithm(chainID)
+	if hash == nil {
+		return utils.CheckTxID(txid, nonce, creator)
+	}
+	return utils.CheckTxIDWithHash(txid, nonce, creator, hash)
+}
@@ -0,0 +1,35 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockHashingAlgorithm(t *testing.T) {
+	nonce := []byte("nonce")
+	creator := []byte("creator")
+	sha256TxID, err := utils.ComputeTxID(nonce, creator)
+	assert.NoError(t, err)
+	sm3TxID := utils.ComputeTxIDWithHash(nonce, creator, util.ComputeSM3)
+	assert.NotEqual(t, sha256TxID, sm3TxID)
+
+	// Channels default to SHA256
+	assert.Equal(t, util.ComputeSHA256([]byte("data")), GetBlockHashingAlgorithm("unknownchannel")([]byte("data")))
+	assert.NoError(t, checkTxID("unknownchannel", sha256TxID, nonce, creator))
+	assert.Error(t, checkTxID("unknownchannel", sm3TxID, nonce, creator))
+
+	SetBlockHashingAlgorithm("sm3channel", util.ComputeSM3)
+	defer SetBlockHashingAlgorithm("sm3channel", nil)
+	assert.Equal(t, util.ComputeSM3([]byte("data")), GetBlockHashingAlgorithm("sm3channel")([]byte("data")))
+	assert.NoError(t, checkTxID("sm3channel", sm3TxID, nonce, creator))
+	assert.Error(t, checkTxID("sm3channel", sha256TxID, nonce, creator))
+}
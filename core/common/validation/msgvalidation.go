@@ -117,7 +117,8 @@ func ValidateProposalMessage(signedProp *pb.SignedProposal) (*pb.Proposal, *comm
 	// Verify that the transaction ID has been computed properly.
 	// This check is needed to ensure that the lookup into the ledger
 	// for the same TxID catches duplicates.
-	err = utils.CheckTxID(
+	err = checkTxID(
+		chdr.ChannelId,
 		chdr.TxId,
 		shdr.Nonce,
 		shdr.Creator)
@@ -411,7 +412,8 @@ func ValidateTransaction(e *common.Envelope, c channelconfig.ApplicationCapabili
 		// Verify that the transaction ID has been computed properly.
 		// This check is needed to ensure that the lookup into the ledger
 		// for the same TxID catches duplicates.
-		err = utils.CheckTxID(
+		err = checkTxID(
+			chdr.ChannelId,
 			chdr.TxId,
 			shdr.Nonce,
 			shdr.Creator)
@@ -446,7 +448,8 @@ func ValidateTransaction(e *common.Envelope, c channelconfig.ApplicationCapabili
 		// Verify that the transaction ID has been computed properly.
 		// This check is needed to ensure that the lookup into the ledger
 		// for the same TxID catches duplicates.
-		err = utils.CheckTxID(
+		err = checkTxID(
+			chdr.ChannelId,
 			chdr.TxId,
 			shdr.Nonce,
 			shdr.Creator)
@@ -11,6 +11,8 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/common"
@@ -28,9 +30,22 @@ const (
 type Mgr interface {
 	ledger.StateListener
 	GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetriever) ledger.ConfigHistoryRetriever
+	// ExportConfigHistory returns an iterator over the entries, as *Entry, of the config history of the given
+	// ledger. The iterator reads the entries committed by the time of the call
+	ExportConfigHistory(ledgerID string) (commonledger.ResultsIterator, error)
+	// ImportConfigHistory adds the given entries, exported from a snapshot, to the config history of the given ledger
+	ImportConfigHistory(ledgerID string, entries []*Entry) error
 	Close()
 }
 
+// Entry is an entry of the config history, the value of a key of a namespace committed by a block
+type Entry struct {
+	Namespace string
+	Key       string
+	BlockNum  uint64
+	Value     []byte
+}
+
 type mgr struct {
 	ccInfoProvider ledger.DeployedChaincodeInfoProvider
 	dbProvider     *dbProvider
@@ -96,6 +111,38 @@ func (m *mgr) GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetrie
 	return &retriever{dbHandle: m.dbProvider.getDB(ledgerID), ledgerInfoRetriever: ledgerInfoRetriever}
 }
 
+// ExportConfigHistory implements the function in the interface 'Mgr'
+func (m *mgr) ExportConfigHistory(ledgerID string) (commonledger.ResultsIterator, error) {
+	itr := m.dbProvider.getDB(ledgerID).GetIterator([]byte(keyPrefix), []byte(keyPrefix+"\xff"))
+	return &entriesItr{ledgerID: ledgerID, dbItr: itr}, nil
+}
+
+type entriesItr struct {
+	ledgerID string
+	dbItr    *leveldbhelper.Iterator
+}
+
+func (itr *entriesItr) Next() (commonledger.QueryResult, error) {
+	if !itr.dbItr.Next() {
+		return nil, errors.Wrapf(itr.dbItr.Error(), "error while iterating over the config history of ledger [%s]", itr.ledgerID)
+	}
+	k := decodeCompositeKey(itr.dbItr.Key())
+	return &Entry{Namespace: k.ns, Key: k.key, BlockNum: k.blockNum, Value: append([]byte{}, itr.dbItr.Value()...)}, nil
+}
+
+func (itr *entriesItr) Close() {
+	itr.dbItr.Release()
+}
+
+// ImportConfigHistory implements the function in the interface 'Mgr'
+func (m *mgr) ImportConfigHistory(ledgerID string, entries []*Entry) error {
+	batch := newBatch()
+	for _, entry := range entries {
+		batch.add(entry.Namespace, entry.Key, entry.BlockNum, entry.Value)
+	}
+	return m.dbProvider.getDB(ledgerID).writeBatch(batch, true)
+}
+
 // Close implements the function in the interface 'Mgr'
 func (m *mgr) Close() {
 	m.dbProvider.Close()
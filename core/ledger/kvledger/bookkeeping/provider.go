@@ -21,6 +21,8 @@ const (
 	PvtdataExpiry Category = iota
 	// MetadataPresenceIndicator maintains the bookkeeping about whether metadata is ever set for a namespace
 	MetadataPresenceIndicator
+	// SnapshotRequest maintains the bookkeeping about the pending requests to generate snapshots of the ledger
+	SnapshotRequest
 )
 
 // Provider provides handle to different bookkeepers for the given ledger
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
+	"sync"
+
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
@@ -18,6 +20,8 @@ type collElgNotifier struct {
 	deployedChaincodeInfoProvider ledger.DeployedChaincodeInfoProvider
 	membershipInfoProvider        ledger.MembershipInfoProvider
 	listeners                     map[string]collElgListener
+	// listenersLock guards the listeners, as the ledgers may be opened concurrently
+	listenersLock sync.RWMutex
 }
 
 // InterestedInNamespaces implements function in interface ledger.StateListener
@@ -78,11 +82,15 @@ func (n *collElgNotifier) HandleStateUpdates(trigger *ledger.StateUpdateTrigger)
 }
 
 func (n *collElgNotifier) registerListener(ledgerID string, listener collElgListener) {
+	n.listenersLock.Lock()
+	defer n.listenersLock.Unlock()
 	n.listeners[ledgerID] = listener
 }
 
 func (n *collElgNotifier) invokeLedgerSpecificNotifier(ledgerID string, commtingBlk uint64, nsCollMap map[string][]string) {
+	n.listenersLock.RLock()
 	listener := n.listeners[ledgerID]
+	n.listenersLock.RUnlock()
 	listener.ProcessCollsEligibilityEnabled(commtingBlk, nsCollMap)
 }
 
@@ -42,9 +42,9 @@ func TestCollElgNotifier(t *testing.T) {
 	mockCollElgListener := &mockCollElgListener{}
 
 	collElgNotifier := &collElgNotifier{
-		mockDeployedChaincodeInfoProvider,
-		mockMembershipInfoProvider,
-		make(map[string]collElgListener),
+		deployedChaincodeInfoProvider: mockDeployedChaincodeInfoProvider,
+		membershipInfoProvider:        mockMembershipInfoProvider,
+		listeners:                     make(map[string]collElgListener),
 	}
 	collElgNotifier.registerListener("testLedger", mockCollElgListener)
 
@@ -8,17 +8,26 @@ package kvledger
 import (
 	"bytes"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	lutil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 )
 
 // constructValidAndInvalidPvtData computes the valid pvt data and hash mismatch list
-// from a received pvt data list of old blocks.
-func constructValidAndInvalidPvtData(blocksPvtData []*ledger.BlockPvtData, blockStore *ledgerstorage.Store) (
+// from a received pvt data list of old blocks. The pvt data of the blocks pruned from
+// the block store, i.e. of the snapshot the ledger was created from, is validated
+// against the hashes of the private state in db
+func constructValidAndInvalidPvtData(blocksPvtData []*ledger.BlockPvtData, blockStore *ledgerstorage.Store, db privacyenabledstate.DB) (
 	map[uint64][]*ledger.TxPvtData, []*ledger.PvtdataHashMismatch, error,
 ) {
 	// for each block, for each transaction, retrieve the txEnvelope to
@@ -29,7 +38,7 @@ func constructValidAndInvalidPvtData(blocksPvtData []*ledger.BlockPvtData, block
 	var invalidPvtData []*ledger.PvtdataHashMismatch
 
 	for _, blockPvtData := range blocksPvtData {
-		validData, invalidData, err := findValidAndInvalidBlockPvtData(blockPvtData, blockStore)
+		validData, invalidData, err := findValidAndInvalidBlockPvtData(blockPvtData, blockStore, db)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -41,7 +50,7 @@ func constructValidAndInvalidPvtData(blocksPvtData []*ledger.BlockPvtData, block
 	return validPvtData, invalidPvtData, nil
 }
 
-func findValidAndInvalidBlockPvtData(blockPvtData *ledger.BlockPvtData, blockStore *ledgerstorage.Store) (
+func findValidAndInvalidBlockPvtData(blockPvtData *ledger.BlockPvtData, blockStore *ledgerstorage.Store, db privacyenabledstate.DB) (
 	[]*ledger.TxPvtData, []*ledger.PvtdataHashMismatch, error,
 ) {
 	var validPvtData []*ledger.TxPvtData
@@ -50,6 +59,17 @@ func findValidAndInvalidBlockPvtData(blockPvtData *ledger.BlockPvtData, blockSto
 		// (1) retrieve the txrwset from the blockstore
 		logger.Debugf("Retrieving rwset of blockNum:[%d], txNum:[%d]", blockPvtData.BlockNum, txPvtData.SeqInBlock)
 		txRWSet, err := retrieveRwsetForTx(blockPvtData.BlockNum, txPvtData.SeqInBlock, blockStore)
+		if _, ok := err.(*blkstorage.BlockPrunedError); ok {
+			validData, invalidData, err := findValidAndInvalidTxPvtDataFromState(txPvtData, blockPvtData.BlockNum, db)
+			if err != nil {
+				return nil, nil, err
+			}
+			if validData != nil {
+				validPvtData = append(validPvtData, validData)
+			}
+			invalidPvtData = append(invalidPvtData, invalidData...)
+			continue
+		}
 		if err != nil {
 			return nil, nil, err
 		}
@@ -112,6 +132,76 @@ func findValidAndInvalidTxPvtData(txPvtData *ledger.TxPvtData, txRWSet *rwsetuti
 	return txPvtData, invalidPvtData
 }
 
+// findValidAndInvalidTxPvtDataFromState validates the pvt data of a transaction of a block pruned from the
+// block store against the hashes of the private state, as the hashes of the transaction are not available.
+// Only the writes of the keys last written by the transaction can be validated: the other writes, of the keys
+// written again or deleted since, are removed from the pvt data, which is stale for these keys anyway
+func findValidAndInvalidTxPvtDataFromState(txPvtData *ledger.TxPvtData, blkNum uint64, db privacyenabledstate.DB) (
+	*ledger.TxPvtData, []*ledger.PvtdataHashMismatch, error,
+) {
+	var invalidPvtData []*ledger.PvtdataHashMismatch
+	var toDeleteNsColl []*nsColl
+	txNum := txPvtData.SeqInBlock
+	txHeight := version.NewHeight(blkNum, txNum)
+	for _, nsRwset := range txPvtData.WriteSet.NsPvtRwset {
+		ns := nsRwset.Namespace
+		for _, collPvtRwset := range nsRwset.CollectionPvtRwset {
+			coll := collPvtRwset.CollectionName
+			kvRWSet := &kvrwset.KVRWSet{}
+			if err := proto.Unmarshal(collPvtRwset.Rwset, kvRWSet); err != nil {
+				logger.Warningf("Invalid pvtdata of namespace: %s collection: %s for txNum %d in BlkNum %d: %s", ns, coll, txNum, blkNum, err)
+				toDeleteNsColl = append(toDeleteNsColl, &nsColl{ns, coll})
+				continue
+			}
+			var validWrites []*kvrwset.KVWrite
+			var valueHashMismatch []byte
+			for _, kvWrite := range kvRWSet.Writes {
+				vv, err := db.GetValueHash(ns, coll, lutil.ComputeStringHash(kvWrite.Key))
+				if err != nil {
+					return nil, nil, err
+				}
+				if vv == nil || vv.Version.Compare(txHeight) != 0 {
+					continue
+				}
+				if kvWrite.IsDelete || !bytes.Equal(lutil.ComputeHash(kvWrite.Value), vv.Value) {
+					valueHashMismatch = vv.Value
+					break
+				}
+				validWrites = append(validWrites, kvWrite)
+			}
+			if valueHashMismatch != nil {
+				invalidPvtData = append(invalidPvtData, &ledger.PvtdataHashMismatch{
+					BlockNum:     blkNum,
+					TxNum:        txNum,
+					Namespace:    ns,
+					Collection:   coll,
+					ExpectedHash: valueHashMismatch})
+				toDeleteNsColl = append(toDeleteNsColl, &nsColl{ns, coll})
+				continue
+			}
+			if len(validWrites) == 0 {
+				toDeleteNsColl = append(toDeleteNsColl, &nsColl{ns, coll})
+				continue
+			}
+			if len(validWrites) < len(kvRWSet.Writes) {
+				kvRWSet.Writes = validWrites
+				rwsetBytes, err := proto.Marshal(kvRWSet)
+				if err != nil {
+					return nil, nil, errors.Wrap(err, "error marshaling the validated pvtdata")
+				}
+				collPvtRwset.Rwset = rwsetBytes
+			}
+		}
+	}
+	for _, nsColl := range toDeleteNsColl {
+		txPvtData.WriteSet.Remove(nsColl.ns, nsColl.coll)
+	}
+	if len(txPvtData.WriteSet.NsPvtRwset) == 0 {
+		return nil, invalidPvtData, nil
+	}
+	return txPvtData, invalidPvtData, nil
+}
+
 type nsColl struct {
 	ns, coll string
 }
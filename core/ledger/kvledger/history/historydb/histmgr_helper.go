@@ -54,6 +54,19 @@ func ConstructPartialCompositeHistoryKey(ns string, key string, endkey bool) []b
 	return compositeKey
 }
 
+//ConstructCompositeHistoryRangeKeys builds the range of the History Keys of the keys of namespace ns
+// between startKey (inclusive) and endKey (exclusive). An empty endKey denotes the end of the namespace
+func ConstructCompositeHistoryRangeKeys(ns string, startKey string, endKey string) ([]byte, []byte) {
+	compositeStartKey := ConstructPartialCompositeHistoryKey(ns, startKey, false)
+	if endKey != "" {
+		return compositeStartKey, ConstructPartialCompositeHistoryKey(ns, endKey, false)
+	}
+	var compositeEndKey []byte
+	compositeEndKey = append(compositeEndKey, []byte(ns)...)
+	compositeEndKey = append(compositeEndKey, CompositeKeySep[0]+1)
+	return compositeStartKey, compositeEndKey
+}
+
 //SplitCompositeHistoryKey splits the key bytes using a separator
 func SplitCompositeHistoryKey(bytesToSplit []byte, separator []byte) ([]byte, []byte) {
 	split := bytes.SplitN(bytesToSplit, separator, 2)
@@ -38,6 +38,16 @@ func TestConstructPartialCompositeKey(t *testing.T) {
 	assert.Equal(t, []byte("ns1"+strKeySep+"key1"+strKeySep+string([]byte{0xff})), compositeEndKey)
 }
 
+func TestConstructCompositeRangeKeys(t *testing.T) {
+	compositeStartKey, compositeEndKey := ConstructCompositeHistoryRangeKeys("ns1", "key1", "key5")
+	assert.Equal(t, []byte("ns1"+strKeySep+"key1"+strKeySep), compositeStartKey)
+	assert.Equal(t, []byte("ns1"+strKeySep+"key5"+strKeySep), compositeEndKey)
+
+	compositeStartKey, compositeEndKey = ConstructCompositeHistoryRangeKeys("ns1", "", "")
+	assert.Equal(t, []byte("ns1"+strKeySep+strKeySep), compositeStartKey)
+	assert.Equal(t, []byte("ns1"+string([]byte{0x01})), compositeEndKey)
+}
+
 func TestSplitCompositeKey(t *testing.T) {
 	compositeFullKey := []byte("ns1" + strKeySep + "key1" + strKeySep + "extra bytes to split")
 	compositePartialKey := ConstructPartialCompositeHistoryKey("ns1", "key1", false)
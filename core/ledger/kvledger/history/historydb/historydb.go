@@ -35,6 +35,9 @@ type HistoryDBProvider interface {
 type HistoryDB interface {
 	NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error)
 	Commit(block *common.Block) error
+	// CommitSnapshotLastBlock commits the last block of the snapshot a ledger is created from,
+	// which is the first block covered by the history database of that ledger
+	CommitSnapshotLastBlock(block *common.Block) error
 	GetLastSavepoint() (*version.Height, error)
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
 	CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error
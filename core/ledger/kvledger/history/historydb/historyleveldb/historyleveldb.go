@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package historyleveldb
 
 import (
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -18,11 +19,15 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 )
 
 var logger historydbLogger = flogging.MustGetLogger("historyleveldb")
 
 var savePointKey = []byte{0x00}
+
+// startingBlockKey records the first block covered by the history database of a ledger created from a snapshot
+var startingBlockKey = []byte{0x01}
 var emptyValue = []byte{}
 
 //go:generate counterfeiter -o fakes/historydb_logger.go -fake-name HistorydbLogger . historydbLogger
@@ -173,6 +178,30 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 	return nil
 }
 
+// CommitSnapshotLastBlock implements method in HistoryDB interface
+func (historyDB *historyDB) CommitSnapshotLastBlock(block *common.Block) error {
+	// the keys not written since the snapshot have no history records, so the history is only
+	// complete from the last block of the snapshot, which is recorded before the block is committed
+	if err := historyDB.db.Put(startingBlockKey, proto.EncodeVarint(block.Header.Number), true); err != nil {
+		return err
+	}
+	return historyDB.Commit(block)
+}
+
+// getStartingBlockNum returns the first block covered by the history database, which is the
+// last block of the snapshot for a ledger created from a snapshot, and 0 otherwise
+func (historyDB *historyDB) getStartingBlockNum() (uint64, error) {
+	startingBlockBytes, err := historyDB.db.Get(startingBlockKey)
+	if err != nil || startingBlockBytes == nil {
+		return 0, err
+	}
+	startingBlockNum, n := proto.DecodeVarint(startingBlockBytes)
+	if n == 0 {
+		return 0, errors.Errorf("error decoding the first block covered by the history database [%x]", startingBlockBytes)
+	}
+	return startingBlockNum, nil
+}
+
 // NewHistoryQueryExecutor implements method in HistoryDB interface
 func (historyDB *historyDB) NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error) {
 	return &LevelHistoryDBQueryExecutor{historyDB, blockStore}, nil
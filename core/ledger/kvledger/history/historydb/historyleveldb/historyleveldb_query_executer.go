@@ -7,9 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package historyleveldb
 
 import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/golang/protobuf/ptypes"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -43,6 +48,435 @@ func (q *LevelHistoryDBQueryExecutor) GetHistoryForKey(namespace string, key str
 	return newHistoryScanner(compositeStartKey, namespace, key, dbItr, q.blockStore), nil
 }
 
+// GetStateAtHeight implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetStateAtHeight(namespace string, key string, blockNum uint64) ([]byte, error) {
+	if ledgerconfig.IsHistoryDBEnabled() == false {
+		return nil, errors.New("history database not enabled")
+	}
+	startingBlockNum, err := q.checkBlockNum(blockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	// scan the history records of namespace~key backwards, starting with the last one committed up to blockNum
+	compositeStartKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
+	compositeEndKey := historydb.ConstructCompositeHistoryKey(namespace, key, blockNum+1, 0)
+	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
+	defer dbItr.Release()
+
+	for ok := dbItr.Last(); ok; ok = dbItr.Prev() {
+		_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(dbItr.Key(), compositeStartKey)
+		// false keys, of the keys starting with key followed by a nil byte, are skipped as done by the historyScanner
+		keyBlockNum, tranNum, err := decodeBlockNumTranNum(blockNumTranNumBytes)
+		if err != nil || keyBlockNum > blockNum {
+			continue
+		}
+		keyModification, err := q.retrieveKeyModification(namespace, key, keyBlockNum, tranNum)
+		if err != nil {
+			return nil, err
+		}
+		if keyModification == nil {
+			continue
+		}
+		if keyModification.IsDelete {
+			return nil, nil
+		}
+		return keyModification.Value, nil
+	}
+	if startingBlockNum > 0 {
+		// the key may have been written before the snapshot the ledger was created from
+		return nil, errors.Errorf("the value of key [%s] at block number [%d] is not available, the key was not written between "+
+			"the first block [%d] covered by the history database and block number [%d]", key, blockNum, startingBlockNum, blockNum)
+	}
+	return nil, nil
+}
+
+// GetStateRangeAtHeight implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetStateRangeAtHeight(namespace string, startKey string, endKey string, blockNum uint64) (commonledger.ResultsIterator, error) {
+	if ledgerconfig.IsHistoryDBEnabled() == false {
+		return nil, errors.New("history database not enabled")
+	}
+	startingBlockNum, err := q.checkBlockNum(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if startingBlockNum > 0 {
+		// the keys not written since the snapshot the ledger was created from have no history records
+		return nil, errors.Errorf("the values of a range of keys are not available, the history database covers the keys "+
+			"written since block number [%d] only", startingBlockNum)
+	}
+
+	compositeStartKey, compositeEndKey := historydb.ConstructCompositeHistoryRangeKeys(namespace, startKey, endKey)
+	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
+	return &rangeAtHeightScanner{queryExecutor: q, namespace: namespace, blockNum: blockNum, dbItr: dbItr}, nil
+}
+
+// GetHistoryForKeyWithOptions implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string, options *ledger.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
+	if ledgerconfig.IsHistoryDBEnabled() == false {
+		return nil, errors.New("history database not enabled")
+	}
+	if options == nil {
+		options = &ledger.HistoryQueryOptions{}
+	}
+	if err := validateHistoryQueryOptions(options); err != nil {
+		return nil, err
+	}
+
+	// the history records of a key are ordered by height, so the range scanned is bounded by the blocks of the options
+	compositeStartKey := historydb.ConstructCompositeHistoryKey(namespace, key, options.StartBlock, 0)
+	compositeEndKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	if options.EndBlock != 0 {
+		compositeEndKey = historydb.ConstructCompositeHistoryKey(namespace, key, options.EndBlock+1, 0)
+	}
+	return q.newHistoryQueryScanner(namespace, key, true, compositeStartKey, compositeEndKey, options)
+}
+
+// GetHistoryForKeyRange implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetHistoryForKeyRange(namespace string, startKey string, endKey string, options *ledger.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
+	if ledgerconfig.IsHistoryDBEnabled() == false {
+		return nil, errors.New("history database not enabled")
+	}
+	if options == nil {
+		options = &ledger.HistoryQueryOptions{}
+	}
+	if err := validateHistoryQueryOptions(options); err != nil {
+		return nil, err
+	}
+
+	compositeStartKey, compositeEndKey := historydb.ConstructCompositeHistoryRangeKeys(namespace, startKey, endKey)
+	return q.newHistoryQueryScanner(namespace, "", false, compositeStartKey, compositeEndKey, options)
+}
+
+func validateHistoryQueryOptions(options *ledger.HistoryQueryOptions) error {
+	if options.EndBlock != 0 && options.StartBlock > options.EndBlock {
+		return errors.Errorf("start block [%d] is after the end block [%d]", options.StartBlock, options.EndBlock)
+	}
+	if !options.StartTime.IsZero() && !options.EndTime.IsZero() && !options.StartTime.Before(options.EndTime) {
+		return errors.Errorf("start time [%s] is not before the end time [%s]", options.StartTime, options.EndTime)
+	}
+	if options.PageSize < 0 {
+		return errors.Errorf("page size [%d] must not be negative", options.PageSize)
+	}
+	return nil
+}
+
+// newHistoryQueryScanner returns a scanner of the history records between compositeStartKey and compositeEndKey,
+// resuming from the record of the bookmark of the options if any
+func (q *LevelHistoryDBQueryExecutor) newHistoryQueryScanner(namespace, key string, singleKey bool,
+	compositeStartKey, compositeEndKey []byte, options *ledger.HistoryQueryOptions) (*historyQueryScanner, error) {
+	if options.Bookmark != "" {
+		bookmarkKey, err := hex.DecodeString(options.Bookmark)
+		if err != nil || bytes.Compare(bookmarkKey, compositeStartKey) < 0 || bytes.Compare(bookmarkKey, compositeEndKey) >= 0 {
+			return nil, errors.Errorf("invalid bookmark [%s]", options.Bookmark)
+		}
+		if options.NewestFirst {
+			// the end key is exclusive, and the key following the bookmark is the bookmark suffixed with a nil byte
+			compositeEndKey = append(bookmarkKey, historydb.CompositeKeySep...)
+		} else {
+			compositeStartKey = bookmarkKey
+		}
+	}
+	scanner := &historyQueryScanner{
+		queryExecutor: q,
+		namespace:     namespace,
+		key:           key,
+		options:       options,
+		dbItr:         q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey),
+	}
+	if singleKey {
+		scanner.compositePartialKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
+	}
+	return scanner, nil
+}
+
+// checkBlockNum checks that the block blockNum has been committed and is covered by the history database.
+// It returns the first block covered by the history database.
+// As a block is committed to the block storage before the history database, and the history database
+// might be recovering blocks, the block must also be at or before the savepoint of the history database.
+func (q *LevelHistoryDBQueryExecutor) checkBlockNum(blockNum uint64) (uint64, error) {
+	bcInfo, err := q.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	if blockNum >= bcInfo.Height {
+		return 0, errors.Errorf("block number [%d] is beyond the height [%d] of the ledger", blockNum, bcInfo.Height)
+	}
+	savepoint, err := q.historyDB.GetLastSavepoint()
+	if err != nil {
+		return 0, err
+	}
+	if savepoint == nil || blockNum > savepoint.BlockNum {
+		return 0, errors.Errorf("block number [%d] is not committed to the history database yet", blockNum)
+	}
+	startingBlockNum, err := q.historyDB.getStartingBlockNum()
+	if err != nil {
+		return 0, err
+	}
+	if blockNum < startingBlockNum {
+		return 0, errors.Errorf("block number [%d] is before the first block [%d] covered by the history database, "+
+			"the ledger was created from a snapshot", blockNum, startingBlockNum)
+	}
+	return startingBlockNum, nil
+}
+
+// retrieveKeyModification loads the transaction blockNum:tranNum from block storage and returns the write
+// of the key by the transaction, or nil if the transaction did not write the key
+func (q *LevelHistoryDBQueryExecutor) retrieveKeyModification(namespace, key string, blockNum, tranNum uint64) (*queryresult.KeyModification, error) {
+	tranEnvelope, err := q.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+	if err == blkstorage.ErrNotFoundInIndex {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	queryResult, err := getKeyModificationFromTran(tranEnvelope, namespace, key)
+	if err != nil || queryResult == nil {
+		return nil, err
+	}
+	return queryResult.(*queryresult.KeyModification), nil
+}
+
+// rangeAtHeightScanner implements ResultsIterator for iterating through the values of a range of keys at a height.
+// The history records of a key are contiguous and ordered by height, so the scanner reads the records of a key,
+// and returns the value written by the last of them committed up to the height, unless the key was deleted.
+// As for the historyScanner, the records of keys containing nil bytes may be attributed to the wrong key,
+// in which case they do not map to a write of that key in the block storage and are skipped.
+type rangeAtHeightScanner struct {
+	queryExecutor *LevelHistoryDBQueryExecutor
+	namespace     string
+	blockNum      uint64
+	dbItr         iterator.Iterator
+	// pending is the first record of the next key, read along with the records of the previous key
+	pending *historyRecord
+	done    bool
+}
+
+// historyRecord is a history record of a key, decoded from its history key
+type historyRecord struct {
+	key      string
+	blockNum uint64
+	tranNum  uint64
+}
+
+// Next returns the next key of the range that had a value at the height
+func (scanner *rangeAtHeightScanner) Next() (commonledger.QueryResult, error) {
+	for {
+		key, records, ok := scanner.nextKeyRecords()
+		if !ok {
+			return nil, nil
+		}
+		// check the records from the last one, for the write of the key committed last
+		for i := len(records) - 1; i >= 0; i-- {
+			keyModification, err := scanner.queryExecutor.retrieveKeyModification(scanner.namespace, key,
+				records[i].blockNum, records[i].tranNum)
+			if err != nil {
+				return nil, err
+			}
+			if keyModification == nil {
+				continue
+			}
+			if keyModification.IsDelete {
+				break
+			}
+			return &queryresult.KV{Namespace: scanner.namespace, Key: key, Value: keyModification.Value}, nil
+		}
+	}
+}
+
+// nextKeyRecords reads the records of the next key, keeping those committed up to the height.
+// It returns false once all the keys are read
+func (scanner *rangeAtHeightScanner) nextKeyRecords() (string, []*historyRecord, bool) {
+	record := scanner.pending
+	scanner.pending = nil
+	if record == nil {
+		record = scanner.nextRecord()
+	}
+	if record == nil {
+		return "", nil, false
+	}
+	key := record.key
+	var records []*historyRecord
+	for ; record != nil; record = scanner.nextRecord() {
+		if record.key != key {
+			scanner.pending = record
+			break
+		}
+		if record.blockNum <= scanner.blockNum {
+			records = append(records, record)
+		}
+	}
+	return key, records, true
+}
+
+// nextRecord reads and decodes the next history record, skipping the records that cannot be decoded
+func (scanner *rangeAtHeightScanner) nextRecord() *historyRecord {
+	if scanner.done {
+		return nil
+	}
+	nsPrefixLen := len(scanner.namespace) + len(historydb.CompositeKeySep)
+	for scanner.dbItr.Next() {
+		keyBlockNumTranNum := scanner.dbItr.Key()[nsPrefixLen:]
+		if record := decodeHistoryRecord(keyBlockNumTranNum); record != nil {
+			return record
+		}
+		logger.Warnf("Some other key [%#v] found in the range while scanning the state of namespace [%s] at height [%d]. Skipping",
+			scanner.dbItr.Key(), scanner.namespace, scanner.blockNum)
+	}
+	scanner.done = true
+	return nil
+}
+
+func (scanner *rangeAtHeightScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// decodeHistoryRecord splits key~blocknum~trannum at the first separator followed by a valid encoding of blockNum:tranNum
+func decodeHistoryRecord(keyBlockNumTranNum []byte) *historyRecord {
+	for i, b := range keyBlockNumTranNum {
+		if b != historydb.CompositeKeySep[0] {
+			continue
+		}
+		blockNum, tranNum, err := decodeBlockNumTranNum(keyBlockNumTranNum[i+1:])
+		if err == nil {
+			return &historyRecord{key: string(keyBlockNumTranNum[:i]), blockNum: blockNum, tranNum: tranNum}
+		}
+	}
+	return nil
+}
+
+// historyQueryScanner implements QueryResultsIterator for iterating through the history records of a key,
+// or of a range of keys, that match the options of a history query. As for the historyScanner, the records of
+// the keys containing nil bytes may be attributed to the wrong key, in which case they do not map to a write of
+// that key in the block storage and are skipped.
+type historyQueryScanner struct {
+	queryExecutor *LevelHistoryDBQueryExecutor
+	namespace     string
+	key           string
+	// compositePartialKey is namespace~key~ for the history of a single key, and nil for a range of keys
+	compositePartialKey []byte
+	options             *ledger.HistoryQueryOptions
+	dbItr               iterator.Iterator
+	started             bool
+	done                bool
+	returned            int32
+}
+
+// Next returns the next history record that matches the options, the key of the record being set
+func (scanner *historyQueryScanner) Next() (commonledger.QueryResult, error) {
+	if scanner.options.PageSize > 0 && scanner.returned >= scanner.options.PageSize {
+		return nil, nil
+	}
+	_, keyModification, err := scanner.nextMatch()
+	if err != nil || keyModification == nil {
+		return nil, err
+	}
+	scanner.returned++
+	return keyModification, nil
+}
+
+// nextMatch moves to the next history record that matches the options, and returns its history key along with
+// the key modification of the record, the key of which is set. A nil key modification is returned once there
+// are no more records
+func (scanner *historyQueryScanner) nextMatch() ([]byte, *queryresult.KeyModification, error) {
+	for {
+		if !scanner.move() {
+			return nil, nil, nil
+		}
+		historyKey := scanner.dbItr.Key()
+		record := scanner.decodeRecord(historyKey)
+		if record == nil {
+			logger.Warnf("Some other key [%#v] found in the range while scanning history of namespace [%s]. Skipping",
+				historyKey, scanner.namespace)
+			continue
+		}
+		if record.blockNum < scanner.options.StartBlock ||
+			(scanner.options.EndBlock != 0 && record.blockNum > scanner.options.EndBlock) {
+			continue
+		}
+		keyModification, err := scanner.queryExecutor.retrieveKeyModification(scanner.namespace, record.key,
+			record.blockNum, record.tranNum)
+		if err != nil {
+			return nil, nil, err
+		}
+		if keyModification == nil {
+			logger.Warnf("Some other key [%#v] found in the range while scanning history of namespace [%s]. Skipping (namespace or key not found)",
+				historyKey, scanner.namespace)
+			continue
+		}
+		if !scanner.inTimeWindow(keyModification) {
+			continue
+		}
+		keyModification.Key = record.key
+		return historyKey, keyModification, nil
+	}
+}
+
+// move moves the iterator to the next history record in the order of the query
+func (scanner *historyQueryScanner) move() bool {
+	if scanner.done {
+		return false
+	}
+	var ok bool
+	switch {
+	case !scanner.options.NewestFirst:
+		ok = scanner.dbItr.Next()
+	case !scanner.started:
+		ok = scanner.dbItr.Last()
+	default:
+		ok = scanner.dbItr.Prev()
+	}
+	scanner.started = true
+	scanner.done = !ok
+	return ok
+}
+
+// decodeRecord decodes the history record of a history key, or returns nil if the key cannot be decoded
+func (scanner *historyQueryScanner) decodeRecord(historyKey []byte) *historyRecord {
+	if scanner.compositePartialKey == nil {
+		return decodeHistoryRecord(historyKey[len(scanner.namespace)+len(historydb.CompositeKeySep):])
+	}
+	_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
+	blockNum, tranNum, err := decodeBlockNumTranNum(blockNumTranNumBytes)
+	if err != nil {
+		return nil
+	}
+	return &historyRecord{key: scanner.key, blockNum: blockNum, tranNum: tranNum}
+}
+
+func (scanner *historyQueryScanner) inTimeWindow(keyModification *queryresult.KeyModification) bool {
+	if scanner.options.StartTime.IsZero() && scanner.options.EndTime.IsZero() {
+		return true
+	}
+	timestamp, err := ptypes.Timestamp(keyModification.Timestamp)
+	if err != nil {
+		return false
+	}
+	return (scanner.options.StartTime.IsZero() || !timestamp.Before(scanner.options.StartTime)) &&
+		(scanner.options.EndTime.IsZero() || timestamp.Before(scanner.options.EndTime))
+}
+
+func (scanner *historyQueryScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// GetBookmarkAndClose returns the hex encoding of the history key of the next record that matches the options,
+// or an empty bookmark if the remaining records are all filtered out by the options. If the next record cannot
+// be read, the bookmark is the history key of that record, so that the next page retries reading it
+func (scanner *historyQueryScanner) GetBookmarkAndClose() string {
+	bookmark := ""
+	historyKey, keyModification, err := scanner.nextMatch()
+	switch {
+	case err != nil:
+		logger.Warnf("Error while looking for the next history record of namespace [%s] to bookmark: %s", scanner.namespace, err)
+		bookmark = hex.EncodeToString(scanner.dbItr.Key())
+	case keyModification != nil:
+		bookmark = hex.EncodeToString(historyKey)
+	}
+	scanner.Close()
+	return bookmark
+}
+
 //historyScanner implements ResultsIterator for iterating through history results
 type historyScanner struct {
 	compositePartialKey []byte //compositePartialKey includes namespace~key
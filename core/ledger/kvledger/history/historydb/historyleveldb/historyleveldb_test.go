@@ -8,11 +8,14 @@ package historyleveldb
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -520,6 +523,288 @@ func TestHistoryWithBlockNumber256(t *testing.T) {
 	assert.Equal(t, "value256", valueInBlock256)
 }
 
+func TestStateAtHeight(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// commitBlock commits a block with a transaction writing the given keys, a nil value deleting the key
+	commitBlock := func(ns string, writes map[string][]byte) {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		for key, value := range writes {
+			if value == nil {
+				assert.NoError(t, simulator.DeleteState(ns, key))
+				continue
+			}
+			assert.NoError(t, simulator.SetState(ns, key, value))
+		}
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		assert.NoError(t, store1.AddBlock(block))
+		assert.NoError(t, env.testHistoryDB.Commit(block))
+	}
+	commitBlock("ns1", map[string][]byte{"key1": []byte("value1-1"), "key2": []byte("value2-1")})
+	commitBlock("ns1", map[string][]byte{"key1": []byte("value1-2"), "key3": []byte("value3-1")})
+	commitBlock("ns1", map[string][]byte{"key2": nil, "key3": []byte("value3-2")})
+	commitBlock("ns2", map[string][]byte{"key1": []byte("value1-ns2")})
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	testcases := []struct {
+		key           string
+		blockNum      uint64
+		expectedValue []byte
+	}{
+		{"key1", 0, nil},
+		{"key1", 1, []byte("value1-1")},
+		{"key1", 2, []byte("value1-2")},
+		{"key1", 4, []byte("value1-2")},
+		{"key2", 2, []byte("value2-1")},
+		{"key2", 3, nil},
+		{"key4", 4, nil},
+	}
+	for _, tc := range testcases {
+		value, err := qhistory.GetStateAtHeight("ns1", tc.key, tc.blockNum)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expectedValue, value, "value of key [%s] at block [%d]", tc.key, tc.blockNum)
+	}
+
+	rangeTestcases := []struct {
+		startKey, endKey string
+		blockNum         uint64
+		expectedKVs      []*queryresult.KV
+	}{
+		{"", "", 0, nil},
+		{"", "", 1, []*queryresult.KV{
+			{Namespace: "ns1", Key: "key1", Value: []byte("value1-1")},
+			{Namespace: "ns1", Key: "key2", Value: []byte("value2-1")},
+		}},
+		{"", "", 2, []*queryresult.KV{
+			{Namespace: "ns1", Key: "key1", Value: []byte("value1-2")},
+			{Namespace: "ns1", Key: "key2", Value: []byte("value2-1")},
+			{Namespace: "ns1", Key: "key3", Value: []byte("value3-1")},
+		}},
+		{"", "", 4, []*queryresult.KV{
+			{Namespace: "ns1", Key: "key1", Value: []byte("value1-2")},
+			{Namespace: "ns1", Key: "key3", Value: []byte("value3-2")},
+		}},
+		{"key2", "key3", 2, []*queryresult.KV{
+			{Namespace: "ns1", Key: "key2", Value: []byte("value2-1")},
+		}},
+		{"key2", "", 3, []*queryresult.KV{
+			{Namespace: "ns1", Key: "key3", Value: []byte("value3-2")},
+		}},
+	}
+	for _, tc := range rangeTestcases {
+		itr, err := qhistory.GetStateRangeAtHeight("ns1", tc.startKey, tc.endKey, tc.blockNum)
+		assert.NoError(t, err)
+		var kvs []*queryresult.KV
+		for {
+			kv, err := itr.Next()
+			assert.NoError(t, err)
+			if kv == nil {
+				break
+			}
+			kvs = append(kvs, kv.(*queryresult.KV))
+		}
+		itr.Close()
+		assert.Equal(t, tc.expectedKVs, kvs, "range [%s, %s) at block [%d]", tc.startKey, tc.endKey, tc.blockNum)
+	}
+
+	_, err = qhistory.GetStateAtHeight("ns1", "key1", 5)
+	assert.EqualError(t, err, "block number [5] is beyond the height [5] of the ledger")
+	_, err = qhistory.GetStateRangeAtHeight("ns1", "", "", 5)
+	assert.EqualError(t, err, "block number [5] is beyond the height [5] of the ledger")
+
+	// a block committed to the block storage but not yet to the history database is not queried
+	block5 := bg.NextBlock([][]byte{})
+	assert.NoError(t, store1.AddBlock(block5))
+	_, err = qhistory.GetStateAtHeight("ns1", "key1", 5)
+	assert.EqualError(t, err, "block number [5] is not committed to the history database yet")
+	_, err = qhistory.GetStateRangeAtHeight("ns1", "", "", 5)
+	assert.EqualError(t, err, "block number [5] is not committed to the history database yet")
+	assert.NoError(t, env.testHistoryDB.Commit(block5))
+	_, err = qhistory.GetStateAtHeight("ns1", "key1", 5)
+	assert.NoError(t, err)
+
+	viper.Set("ledger.history.enableHistoryDatabase", false)
+	defer viper.Set("ledger.history.enableHistoryDatabase", true)
+	_, err = qhistory.GetStateAtHeight("ns1", "key1", 1)
+	assert.EqualError(t, err, "history database not enabled")
+	_, err = qhistory.GetStateRangeAtHeight("ns1", "", "", 1)
+	assert.EqualError(t, err, "history database not enabled")
+}
+
+func TestHistoryQueryWithOptions(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// commitBlock commits a block with a transaction writing the given keys, a nil value deleting the key
+	commitBlock := func(ns string, writes map[string][]byte) {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		for key, value := range writes {
+			if value == nil {
+				assert.NoError(t, simulator.DeleteState(ns, key))
+				continue
+			}
+			assert.NoError(t, simulator.SetState(ns, key, value))
+		}
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		assert.NoError(t, store1.AddBlock(block))
+		assert.NoError(t, env.testHistoryDB.Commit(block))
+	}
+	commitBlock("ns1", map[string][]byte{"key1": []byte("value1-1"), "key2": []byte("value2-1")})
+	commitBlock("ns1", map[string][]byte{"key1": []byte("value1-2"), "key3": []byte("value3-1")})
+	commitBlock("ns1", map[string][]byte{"key2": nil, "key3": []byte("value3-2")})
+	commitBlock("ns2", map[string][]byte{"key1": []byte("value1-ns2")})
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	// retrieve returns the history records of a query, described as key:value, and the bookmark of the query
+	retrieve := func(itr ledger.QueryResultsIterator, err error) ([]string, string) {
+		assert.NoError(t, err)
+		var records []string
+		for {
+			res, err := itr.Next()
+			assert.NoError(t, err)
+			if res == nil {
+				break
+			}
+			kmod := res.(*queryresult.KeyModification)
+			if kmod.IsDelete {
+				records = append(records, kmod.Key+":deleted")
+				continue
+			}
+			records = append(records, kmod.Key+":"+string(kmod.Value))
+		}
+		return records, itr.GetBookmarkAndClose()
+	}
+
+	testcases := []struct {
+		name            string
+		isRangeQuery    bool
+		startKey        string
+		endKey          string
+		options         *ledger.HistoryQueryOptions
+		expectedRecords []string
+	}{
+		{"key", false, "key1", "", nil, []string{"key1:value1-1", "key1:value1-2"}},
+		{"key newest first", false, "key1", "", &ledger.HistoryQueryOptions{NewestFirst: true}, []string{"key1:value1-2", "key1:value1-1"}},
+		{"key from block", false, "key1", "", &ledger.HistoryQueryOptions{StartBlock: 2}, []string{"key1:value1-2"}},
+		{"key up to block", false, "key1", "", &ledger.HistoryQueryOptions{EndBlock: 1}, []string{"key1:value1-1"}},
+		{"range", true, "", "", nil,
+			[]string{"key1:value1-1", "key1:value1-2", "key2:value2-1", "key2:deleted", "key3:value3-1", "key3:value3-2"}},
+		{"range newest first", true, "", "", &ledger.HistoryQueryOptions{NewestFirst: true},
+			[]string{"key3:value3-2", "key3:value3-1", "key2:deleted", "key2:value2-1", "key1:value1-2", "key1:value1-1"}},
+		{"range in block", true, "", "", &ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 2}, []string{"key1:value1-2", "key3:value3-1"}},
+		{"bounded range", true, "key2", "key3", nil, []string{"key2:value2-1", "key2:deleted"}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var records []string
+			if tc.isRangeQuery {
+				records, _ = retrieve(qhistory.GetHistoryForKeyRange("ns1", tc.startKey, tc.endKey, tc.options))
+			} else {
+				records, _ = retrieve(qhistory.GetHistoryForKeyWithOptions("ns1", tc.startKey, tc.options))
+			}
+			assert.Equal(t, tc.expectedRecords, records)
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		options := &ledger.HistoryQueryOptions{PageSize: 4}
+		records, bookmark := retrieve(qhistory.GetHistoryForKeyRange("ns1", "", "", options))
+		assert.Equal(t, []string{"key1:value1-1", "key1:value1-2", "key2:value2-1", "key2:deleted"}, records)
+		assert.NotEmpty(t, bookmark)
+		options.Bookmark = bookmark
+		records, bookmark = retrieve(qhistory.GetHistoryForKeyRange("ns1", "", "", options))
+		assert.Equal(t, []string{"key3:value3-1", "key3:value3-2"}, records)
+		assert.Empty(t, bookmark)
+
+		options = &ledger.HistoryQueryOptions{PageSize: 1, NewestFirst: true}
+		records, bookmark = retrieve(qhistory.GetHistoryForKeyWithOptions("ns1", "key1", options))
+		assert.Equal(t, []string{"key1:value1-2"}, records)
+		assert.NotEmpty(t, bookmark)
+		options.Bookmark = bookmark
+		records, bookmark = retrieve(qhistory.GetHistoryForKeyWithOptions("ns1", "key1", options))
+		assert.Equal(t, []string{"key1:value1-1"}, records)
+		assert.Empty(t, bookmark)
+
+		// the bookmark skips the records filtered out by the options
+		options = &ledger.HistoryQueryOptions{PageSize: 1, StartBlock: 1, EndBlock: 1}
+		records, bookmark = retrieve(qhistory.GetHistoryForKeyRange("ns1", "", "", options))
+		assert.Equal(t, []string{"key1:value1-1"}, records)
+		assert.Equal(t, hex.EncodeToString(historydb.ConstructCompositeHistoryKey("ns1", "key2", 1, 0)), bookmark)
+		options.Bookmark = bookmark
+		records, bookmark = retrieve(qhistory.GetHistoryForKeyRange("ns1", "", "", options))
+		assert.Equal(t, []string{"key2:value2-1"}, records)
+		assert.Empty(t, bookmark)
+	})
+
+	t.Run("time window", func(t *testing.T) {
+		itr, err := qhistory.GetHistoryForKey("ns1", "key1")
+		assert.NoError(t, err)
+		defer itr.Close()
+		itr.Next()
+		res, err := itr.Next()
+		assert.NoError(t, err)
+		timestamp, err := ptypes.Timestamp(res.(*queryresult.KeyModification).Timestamp)
+		assert.NoError(t, err)
+
+		records, _ := retrieve(qhistory.GetHistoryForKeyWithOptions("ns1", "key1", &ledger.HistoryQueryOptions{StartTime: timestamp}))
+		assert.Equal(t, []string{"key1:value1-2"}, records)
+		records, _ = retrieve(qhistory.GetHistoryForKeyWithOptions("ns1", "key1", &ledger.HistoryQueryOptions{EndTime: timestamp}))
+		assert.Equal(t, []string{"key1:value1-1"}, records)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := qhistory.GetHistoryForKeyWithOptions("ns1", "key1", &ledger.HistoryQueryOptions{StartBlock: 3, EndBlock: 2})
+		assert.EqualError(t, err, "start block [3] is after the end block [2]")
+		now := time.Now()
+		_, err = qhistory.GetHistoryForKeyRange("ns1", "", "", &ledger.HistoryQueryOptions{StartTime: now, EndTime: now})
+		assert.Contains(t, err.Error(), "is not before the end time")
+		_, err = qhistory.GetHistoryForKeyRange("ns1", "", "", &ledger.HistoryQueryOptions{PageSize: -1})
+		assert.EqualError(t, err, "page size [-1] must not be negative")
+		_, err = qhistory.GetHistoryForKeyRange("ns1", "", "", &ledger.HistoryQueryOptions{Bookmark: "bookmark"})
+		assert.EqualError(t, err, "invalid bookmark [bookmark]")
+		// the bookmark of a key is out of the range of another key
+		_, bookmark := retrieve(qhistory.GetHistoryForKeyWithOptions("ns1", "key1", &ledger.HistoryQueryOptions{PageSize: 1}))
+		_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key2", &ledger.HistoryQueryOptions{Bookmark: bookmark})
+		assert.EqualError(t, err, fmt.Sprintf("invalid bookmark [%s]", bookmark))
+	})
+
+	viper.Set("ledger.history.enableHistoryDatabase", false)
+	defer viper.Set("ledger.history.enableHistoryDatabase", true)
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key1", nil)
+	assert.EqualError(t, err, "history database not enabled")
+	_, err = qhistory.GetHistoryForKeyRange("ns1", "", "", nil)
+	assert.EqualError(t, err, "history database not enabled")
+}
+
 func TestName(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
@@ -14,6 +14,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
@@ -35,17 +37,27 @@ import (
 
 var logger = flogging.MustGetLogger("kvledger")
 
+// recommitProgressInterval is the number of blocks after which the progress of recommitting lost blocks is logged
+const recommitProgressInterval = 1000
+
 // KVLedger provides an implementation of `ledger.PeerLedger`.
 // This implementation provides a key-value based data model
 type kvLedger struct {
-	ledgerID               string
-	blockStore             *ledgerstorage.Store
-	txtmgmt                txmgr.TxMgr
-	historyDB              historydb.HistoryDB
-	configHistoryRetriever ledger.ConfigHistoryRetriever
-	blockAPIsRWLock        *sync.RWMutex
-	stats                  *ledgerStats
-	commitHash             []byte
+	ledgerID                  string
+	blockStore                *ledgerstorage.Store
+	versionedDB               privacyenabledstate.DB
+	txtmgmt                   txmgr.TxMgr
+	historyDB                 historydb.HistoryDB
+	configHistoryMgr          confighistory.Mgr
+	configHistoryRetriever    ledger.ConfigHistoryRetriever
+	snapshotRequestBookkeeper *leveldbhelper.DBHandle
+	// snapshotsInProgress holds the block numbers of the snapshots being generated in the background
+	snapshotsInProgress     map[uint64]bool
+	snapshotsInProgressLock sync.Mutex
+	snapshotsWG             sync.WaitGroup
+	blockAPIsRWLock         *sync.RWMutex
+	stats                   *ledgerStats
+	commitHash              []byte
 }
 
 // NewKVLedger constructs new `KVLedger`
@@ -63,7 +75,16 @@ func newKVLedger(
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{}}
+	l := &kvLedger{
+		ledgerID:                  ledgerID,
+		blockStore:                blockStore,
+		versionedDB:               versionedDB,
+		historyDB:                 historyDB,
+		configHistoryMgr:          configHistoryMgr,
+		snapshotRequestBookkeeper: bookkeeperProvider.GetDBHandle(ledgerID, bookkeeping.SnapshotRequest),
+		snapshotsInProgress:       map[uint64]bool{},
+		blockAPIsRWLock:           &sync.RWMutex{},
+	}
 
 	// Retrieves the current commit hash from the blockstore
 	var err error
@@ -90,6 +111,11 @@ func newKVLedger(
 		return nil, err
 	}
 	l.configHistoryRetriever = configHistoryMgr.GetRetriever(ledgerID, l)
+	// A snapshot requested for the last committed block was not generated if the peer stopped
+	// right after committing the block, or while generating the snapshot
+	if err := l.recoverSnapshotRequest(); err != nil {
+		return nil, err
+	}
 
 	l.stats = stats
 	return l, nil
@@ -264,6 +290,10 @@ func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64,
 				return err
 			}
 		}
+		if (blockNumber-firstBlockNum+1)%recommitProgressInterval == 0 && blockNumber < lastBlockNum {
+			logger.Infof("[%s] Recommitted blocks up to block [%d] of [%d] - %d%% done", l.ledgerID, blockNumber, lastBlockNum,
+				(blockNumber-firstBlockNum+1)*100/(lastBlockNum-firstBlockNum+1))
+		}
 	}
 	logger.Infof("Recommitted lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
 	return nil
@@ -428,6 +458,10 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData, co
 		}
 	}
 
+	if err := l.processSnapshotRequest(blockNo); err != nil {
+		logger.Errorf("[%s] Error while processing the snapshot request for block [%d]: %+v", l.ledgerID, blockNo, err)
+	}
+
 	logger.Infof("[%s] Committed block [%d] with %d transaction(s) in %dms (state_validation=%dms block_and_pvtdata_commit=%dms state_commit=%dms)"+
 		" commitHash=[%x]",
 		l.ledgerID, block.Header.Number, len(block.Data.Data),
@@ -539,7 +573,7 @@ func (l *kvLedger) CommitPvtDataOfOldBlocks(pvtData []*ledger.BlockPvtData) ([]*
 	logger.Debugf("[%s:] Comparing pvtData of [%d] old blocks against the hashes in transaction's rwset to find valid and invalid data",
 		l.ledgerID, len(pvtData))
 
-	hashVerifiedPvtData, hashMismatches, err := constructValidAndInvalidPvtData(pvtData, l.blockStore)
+	hashVerifiedPvtData, hashMismatches, err := constructValidAndInvalidPvtData(pvtData, l.blockStore, l.versionedDB)
 	if err != nil {
 		return nil, err
 	}
@@ -582,8 +616,9 @@ func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, err
 	return l, nil
 }
 
-// Close closes `KVLedger`
+// Close closes `KVLedger`. It waits for the snapshots being generated
 func (l *kvLedger) Close() {
+	l.snapshotsWG.Wait()
 	l.blockStore.Shutdown()
 	l.txtmgmt.Shutdown()
 }
@@ -629,6 +664,12 @@ func filterPvtDataOfInvalidTx(hashVerifiedPvtData map[uint64][]*ledger.TxPvtData
 		// the TxValidationFlags from the block metadata. For that, we would need
 		// to add a new index for the block metadata. FAB- FAB-15808
 		block, err := blockStore.RetrieveBlockByNumber(blkNum)
+		if _, ok := err.(*blkstorage.BlockPrunedError); ok {
+			// the pvt data of a block pruned from the block store, i.e. of the snapshot the ledger
+			// was created from, is validated against the state, thus of valid transactions only
+			committedPvtData[blkNum] = txsPvtData
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -8,6 +8,7 @@ package kvledger
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
@@ -18,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
 	"github.com/hyperledger/fabric/protos/common"
@@ -35,7 +37,10 @@ var (
 	ErrLedgerNotOpened = errors.New("ledger is not opened yet")
 
 	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
-	ledgerKeyPrefix            = []byte("l")
+	// underConstructionFromSnapshotKey is set along with underConstructionLedgerKey
+	// when the ledger under construction is created from a snapshot
+	underConstructionFromSnapshotKey = []byte("underConstructionFromSnapshotKey")
+	ledgerKeyPrefix                  = []byte("l")
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -56,22 +61,26 @@ type Provider struct {
 // NewProvider instantiates a new Provider.
 // This is not thread-safe and assumed to be synchronized be the caller
 func NewProvider() (ledger.PeerLedgerProvider, error) {
-	logger.Info("Initializing ledger provider")
-	// Initialize the ID store (inventory of chainIds/ledgerIds)
-	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
-	// Initialize the history database (index for history of values by key)
-	historydbProvider := historyleveldb.NewHistoryDBProvider()
-
 	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
 	if err := fileLock.Lock(); err != nil {
 		return nil, errors.Wrap(err, "as another peer node command is executing,"+
 			" wait for that command to complete its execution or terminate it before retrying")
 	}
+	return newProvider(fileLock), nil
+}
+
+// newProvider instantiates a new Provider with the file lock already held by the caller.
+// The file lock is released when the provider is closed
+func newProvider(fileLock *leveldbhelper.FileLock) *Provider {
+	logger.Info("Initializing ledger provider")
+	// Initialize the ID store (inventory of chainIds/ledgerIds)
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	// Initialize the history database (index for history of values by key)
+	historydbProvider := historyleveldb.NewHistoryDBProvider()
 
 	logger.Info("ledger provider Initialized")
-	provider := &Provider{idStore, nil,
+	return &Provider{idStore, nil,
 		nil, historydbProvider, nil, nil, nil, nil, nil, nil, fileLock}
-	return provider, nil
 }
 
 // Initialize implements the corresponding method from interface ledger.PeerLedgerProvider
@@ -79,16 +88,19 @@ func (provider *Provider) Initialize(initializer *ledger.Initializer) error {
 	var err error
 	configHistoryMgr := confighistory.NewMgr(initializer.DeployedChaincodeInfoProvider)
 	collElgNotifier := &collElgNotifier{
-		initializer.DeployedChaincodeInfoProvider,
-		initializer.MembershipInfoProvider,
-		make(map[string]collElgListener),
+		deployedChaincodeInfoProvider: initializer.DeployedChaincodeInfoProvider,
+		membershipInfoProvider:        initializer.MembershipInfoProvider,
+		listeners:                     make(map[string]collElgListener),
 	}
 	stateListeners := initializer.StateListeners
 	stateListeners = append(stateListeners, collElgNotifier)
 	stateListeners = append(stateListeners, configHistoryMgr)
 
 	provider.initializer = initializer
-	provider.ledgerStoreProvider = ledgerstorage.NewProvider(initializer.MetricsProvider)
+	provider.ledgerStoreProvider, err = ledgerstorage.NewProvider(initializer.MetricsProvider)
+	if err != nil {
+		return err
+	}
 	provider.configHistoryMgr = configHistoryMgr
 	provider.stateListeners = stateListeners
 	provider.collElgNotifier = collElgNotifier
@@ -137,6 +149,107 @@ func (provider *Provider) Create(genesisBlock *common.Block) (ledger.PeerLedger,
 	return lgr, nil
 }
 
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider
+// The snapshot is verified before setting the under construction flag. Then, the block store is bootstrapped
+// with the last block of the snapshot, and the config history and the state are imported, the savepoint of
+// the state database being recorded last. If a crash happens in between, the 'recoverUnderConstructionLedger'
+// function detects that the state of the snapshot was not completely imported
+func (provider *Provider) CreateFromSnapshot(snapshotDir string) (ledger.PeerLedger, string, error) {
+	metadata, err := loadSnapshotMetadata(snapshotDir)
+	if err != nil {
+		return nil, "", err
+	}
+	lastBlock, err := readSnapshotBlock(snapshotDir, snapshotLastBlockFileName)
+	if err != nil {
+		return nil, "", err
+	}
+	lastConfigBlock, err := readSnapshotBlock(snapshotDir, snapshotLastConfigBlockFileName)
+	if err != nil {
+		return nil, "", err
+	}
+	ledgerID, err := utils.GetChainIDFromBlock(lastBlock)
+	if err != nil {
+		return nil, "", err
+	}
+	if ledgerID != metadata.ChannelName || lastBlock.Header.Number != metadata.LastBlockNumber {
+		return nil, "", errors.Errorf("the last block of the snapshot is block [%d] of channel [%s] while the snapshot metadata refers to block [%d] of channel [%s]",
+			lastBlock.Header.Number, ledgerID, metadata.LastBlockNumber, metadata.ChannelName)
+	}
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if exists {
+		return nil, "", ErrLedgerIDExists
+	}
+	configHistory, err := readSnapshotConfigHistory(snapshotDir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	logger.Infof("Creating ledger [%s] from the snapshot at block [%d] in %s", ledgerID, metadata.LastBlockNumber, snapshotDir)
+	if err = provider.idStore.setUnderConstructionFromSnapshotFlag(ledgerID); err != nil {
+		return nil, "", err
+	}
+	if err := provider.importSnapshot(ledgerID, snapshotDir, metadata, lastBlock, lastConfigBlock, configHistory); err != nil {
+		return nil, "", errors.WithMessagef(err, "error creating ledger [%s] from snapshot, the partially created ledger must be removed from the peer before retrying", ledgerID)
+	}
+	lgr, err := provider.openInternal(ledgerID)
+	if err != nil {
+		return nil, "", err
+	}
+	// the collection configs, needed to tell the eligible collections, are read from the state once imported
+	if err := lgr.(*kvLedger).importMissingPvtData(snapshotDir, lastBlock.Header.Number, provider.initializer.MembershipInfoProvider,
+		provider.initializer.DeployedChaincodeInfoProvider); err != nil {
+		lgr.Close()
+		return nil, "", errors.WithMessagef(err, "error creating ledger [%s] from snapshot, the partially created ledger must be removed from the peer before retrying", ledgerID)
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, lastConfigBlock), "Error while marking ledger as created")
+	logger.Infof("Created ledger [%s] from the snapshot at block [%d]", ledgerID, metadata.LastBlockNumber)
+	return lgr, ledgerID, nil
+}
+
+func (provider *Provider) importSnapshot(ledgerID, snapshotDir string, metadata *snapshotMetadata,
+	lastBlock, lastConfigBlock *common.Block, configHistory []*confighistory.Entry) error {
+	txIDs, err := newSnapshotTxIDsReader(snapshotDir)
+	if err != nil {
+		return err
+	}
+	defer txIDs.Close()
+	blockStore, err := provider.ledgerStoreProvider.BootstrapFromSnapshot(ledgerID, lastBlock, lastConfigBlock, txIDs)
+	if err != nil {
+		return err
+	}
+	bcInfo, err := blockStore.GetBlockchainInfo()
+	blockStore.Shutdown()
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(bcInfo.CurrentBlockHash) != metadata.LastBlockHash {
+		return errors.Errorf("the hash of the last block of the snapshot [%x] does not match the hash in the snapshot metadata [%s]",
+			bcInfo.CurrentBlockHash, metadata.LastBlockHash)
+	}
+
+	if err := provider.configHistoryMgr.ImportConfigHistory(ledgerID, configHistory); err != nil {
+		return err
+	}
+	// the history database starts at the last block of the snapshot, whether it is enabled or not,
+	// so that it is not recovered from the blocks of the snapshot if it gets enabled later on
+	historyDB, err := provider.historydbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	if err := historyDB.CommitSnapshotLastBlock(lastBlock); err != nil {
+		return err
+	}
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	savepoint := version.NewHeight(lastBlock.Header.Number, uint64(len(lastBlock.Data.Data)-1))
+	return importSnapshotState(snapshotDir, vDB, savepoint)
+}
+
 // Open implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	logger.Debugf("Open() opening kvledger: %s", ledgerID)
@@ -219,6 +332,12 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 		return
 	}
 	logger.Infof("ledger [%s] found as under construction", ledgerID)
+	fromSnapshot, err := provider.idStore.isUnderConstructionFromSnapshot()
+	panicOnErr(err, "Error while checking whether the under construction ledger is created from a snapshot")
+	if fromSnapshot {
+		provider.recoverUnderConstructionLedgerFromSnapshot(ledgerID)
+		return
+	}
 	ledger, err := provider.openInternal(ledgerID)
 	panicOnErr(err, "Error while opening under construction ledger [%s]", ledgerID)
 	bcInfo, err := ledger.GetBlockchainInfo()
@@ -243,6 +362,34 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 	return
 }
 
+// recoverUnderConstructionLedgerFromSnapshot completes the creation of a ledger from a snapshot if the state of the
+// snapshot was completely imported, as denoted by the savepoint of the state database. Else, the data of the ledger
+// cannot be cleaned up from the shared databases, and the peer refuses to start until it is removed
+func (provider *Provider) recoverUnderConstructionLedgerFromSnapshot(ledgerID string) {
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	panicOnErr(err, "Error while opening the state database of the under construction ledger [%s]", ledgerID)
+	savepoint, err := vDB.GetLatestSavePoint()
+	panicOnErr(err, "Error while retrieving the savepoint of the state database of the under construction ledger [%s]", ledgerID)
+	if savepoint == nil {
+		panic(errors.Errorf(
+			"ledger [%s] was being created from a snapshot when the peer stopped, its data must be removed from the peer before creating it again",
+			ledgerID))
+	}
+	logger.Infof("The state of the snapshot was imported. Hence, marking the peer ledger as created")
+	ledger, err := provider.openInternal(ledgerID)
+	panicOnErr(err, "Error while opening under construction ledger [%s]", ledgerID)
+	defer ledger.Close()
+	bcInfo, err := ledger.GetBlockchainInfo()
+	panicOnErr(err, "Error while getting blockchain info for the under construction ledger [%s]", ledgerID)
+	lastBlock, err := ledger.GetBlockByNumber(bcInfo.Height - 1)
+	panicOnErr(err, "Error while retrieving the last block from blockchain for ledger [%s]", ledgerID)
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	panicOnErr(err, "Error while retrieving the last config index from the last block of ledger [%s]", ledgerID)
+	lastConfigBlock, err := ledger.GetBlockByNumber(lastConfigBlockNum)
+	panicOnErr(err, "Error while retrieving the last config block from blockchain for ledger [%s]", ledgerID)
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, lastConfigBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
+}
+
 // runCleanup cleans up blockstorage, statedb, and historydb for what
 // may have got created during in-complete ledger creation
 func (provider *Provider) runCleanup(ledgerID string) error {
@@ -279,8 +426,26 @@ func (s *idStore) setUnderConstructionFlag(ledgerID string) error {
 	return s.db.Put(underConstructionLedgerKey, []byte(ledgerID), true)
 }
 
+func (s *idStore) setUnderConstructionFromSnapshotFlag(ledgerID string) error {
+	batch := &leveldb.Batch{}
+	batch.Put(underConstructionLedgerKey, []byte(ledgerID))
+	batch.Put(underConstructionFromSnapshotKey, []byte{})
+	return s.db.WriteBatch(batch, true)
+}
+
 func (s *idStore) unsetUnderConstructionFlag() error {
-	return s.db.Delete(underConstructionLedgerKey, true)
+	batch := &leveldb.Batch{}
+	batch.Delete(underConstructionLedgerKey)
+	batch.Delete(underConstructionFromSnapshotKey)
+	return s.db.WriteBatch(batch, true)
+}
+
+func (s *idStore) isUnderConstructionFromSnapshot() (bool, error) {
+	val, err := s.db.Get(underConstructionFromSnapshotKey)
+	if err != nil {
+		return false, err
+	}
+	return val != nil, nil
 }
 
 func (s *idStore) getUnderConstructionFlag() (string, error) {
@@ -307,6 +472,7 @@ func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	batch := &leveldb.Batch{}
 	batch.Put(key, val)
 	batch.Delete(underConstructionLedgerKey)
+	batch.Delete(underConstructionFromSnapshotKey)
 	return s.db.WriteBatch(batch, true)
 }
 
@@ -326,7 +492,8 @@ func (s *idStore) getAllLedgerIds() ([]string, error) {
 	defer itr.Release()
 	itr.First()
 	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
+		if bytes.Equal(itr.Key(), underConstructionLedgerKey) || bytes.Equal(itr.Key(), underConstructionFromSnapshotKey) {
+			itr.Next()
 			continue
 		}
 		id := string(s.decodeLedgerID(itr.Key()))
@@ -311,6 +311,7 @@ func testutilNewProvider(t *testing.T) lgr.PeerLedgerProvider {
 	provider.Initialize(&lgr.Initializer{
 		DeployedChaincodeInfoProvider: &mock.DeployedChaincodeInfoProvider{},
 		MetricsProvider:               &disabled.Provider{},
+		MembershipInfoProvider:        &mock.MembershipInfoProvider{},
 	})
 	return provider
 }
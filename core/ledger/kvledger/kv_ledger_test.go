@@ -711,9 +711,12 @@ func prepareNextBlockForTest(t *testing.T, l lgr.PeerLedger, bg *testutil.BlockG
 	simRes, _ := simulator.GetTxSimulationResults()
 	pubSimBytes, _ := simRes.GetPubSimulationBytes()
 	block := bg.NextBlockWithTxid([][]byte{pubSimBytes}, []string{txid})
-	return &lgr.BlockAndPvtData{Block: block,
-		PvtData: lgr.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}},
+	blockAndPvtData := &lgr.BlockAndPvtData{Block: block}
+	// the block carries no private data when the transaction writes none
+	if simRes.PvtSimulationResults != nil {
+		blockAndPvtData.PvtData = lgr.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}}
 	}
+	return blockAndPvtData
 }
 
 func checkBCSummaryForTest(t *testing.T, l lgr.PeerLedger, expectedBCSummary *bcSummary) {
@@ -0,0 +1,241 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	"github.com/pkg/errors"
+)
+
+const (
+	// StateDBType is the type of the state database. The config history database and the bookkeeping
+	// of the expiry of the private data and of the presence of metadata are rebuilt along with the
+	// state database, as they are updated when the state is committed
+	StateDBType = "state"
+	// HistoryDBType is the type of the history database
+	HistoryDBType = "history"
+)
+
+// RebuildDBs drops the databases of the given type of a ledger, or of all the ledgers if ledgerID is empty,
+// and rebuilds them from the block store. All the databases are rebuilt if dbType is empty.
+// The ledgers are rebuilt in parallel and the progress of each of them is logged
+func RebuildDBs(ledgerID, dbType string, initializer *ledger.Initializer) error {
+	rebuildState, rebuildHistory, err := dbTypesToRebuild(dbType)
+	if err != nil {
+		return err
+	}
+
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	// the file lock is handed over to the provider opening the ledgers, which releases it on close
+	defer fileLock.Unlock()
+
+	ledgerIDs, err := ledgerIDsToRebuild(ledgerID)
+	if err != nil {
+		return err
+	}
+	if err := checkBlocksAvailable(ledgerIDs, initializer); err != nil {
+		return err
+	}
+	for _, id := range ledgerIDs {
+		if err := dropLedgerDBs(id, rebuildState, rebuildHistory, initializer.MetricsProvider); err != nil {
+			return err
+		}
+	}
+
+	provider := newProvider(fileLock)
+	defer provider.Close()
+	if err := provider.Initialize(initializer); err != nil {
+		return err
+	}
+	// the changes of the eligibility of the peer for the collections were processed by the
+	// pvt data store when the blocks were committed for the first time
+	provider.stateListeners = removeStateListener(provider.stateListeners, provider.collElgNotifier)
+	return rebuildLedgers(provider, ledgerIDs)
+}
+
+func dbTypesToRebuild(dbType string) (rebuildState bool, rebuildHistory bool, err error) {
+	switch dbType {
+	case "":
+		rebuildState, rebuildHistory = true, ledgerconfig.IsHistoryDBEnabled()
+	case StateDBType:
+		rebuildState = true
+	case HistoryDBType:
+		if !ledgerconfig.IsHistoryDBEnabled() {
+			return false, false, errors.New("the history database is disabled")
+		}
+		rebuildHistory = true
+	default:
+		return false, false, errors.Errorf("unknown database type [%s], the type must be either %s or %s",
+			dbType, StateDBType, HistoryDBType)
+	}
+	stateDatabase := statedb.VersionedDBProviderName(ledgerconfig.GetStateDatabase())
+	if rebuildState && stateDatabase != statedb.GoLevelDB && stateDatabase != statedb.CouchDB {
+		return false, false, errors.Errorf("the state database is %s, only %s and %s state databases can be rebuilt",
+			stateDatabase, statedb.GoLevelDB, statedb.CouchDB)
+	}
+	return rebuildState, rebuildHistory, nil
+}
+
+func ledgerIDsToRebuild(ledgerID string) ([]string, error) {
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer idStore.close()
+	if ledgerID == "" {
+		return idStore.getAllLedgerIds()
+	}
+	exists, err := idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Errorf("ledger [%s] does not exist", ledgerID)
+	}
+	return []string{ledgerID}, nil
+}
+
+// checkBlocksAvailable checks that the block stores of the ledgers have all the blocks since the
+// genesis block, which is not the case of the ledgers created from a snapshot
+func checkBlocksAvailable(ledgerIDs []string, initializer *ledger.Initializer) error {
+	ledgerStoreProvider, err := ledgerstorage.NewProvider(initializer.MetricsProvider)
+	if err != nil {
+		return err
+	}
+	defer ledgerStoreProvider.Close()
+	for _, ledgerID := range ledgerIDs {
+		blockStore, err := ledgerStoreProvider.Open(ledgerID)
+		if err != nil {
+			return err
+		}
+		itr, err := blockStore.RetrieveBlocks(0)
+		if prunedErr, ok := err.(*blkstorage.BlockPrunedError); ok {
+			blockStore.Shutdown()
+			return errors.Errorf("the databases of ledger [%s] cannot be rebuilt as its block store starts at block [%d]",
+				ledgerID, prunedErr.FirstBlockNum)
+		}
+		if err != nil {
+			blockStore.Shutdown()
+			return err
+		}
+		itr.Close()
+		blockStore.Shutdown()
+	}
+	return nil
+}
+
+// dropLedgerDBs drops the data of a ledger from the databases, which are shared by all the ledgers.
+// As for the reset and the rollback, the state database is dropped before the config history and the
+// bookkeeping, so that all of them are rebuilt on the next start if the command fails midway.
+// A CouchDB state database holds databases dedicated to each ledger, which are dropped entirely
+func dropLedgerDBs(ledgerID string, dropState, dropHistory bool, metricsProvider metrics.Provider) error {
+	if dropState {
+		logger.Infof("[%s] Dropping the state database", ledgerID)
+		if err := dropStateDB(ledgerID, metricsProvider); err != nil {
+			return errors.WithMessage(err, "error dropping the state database")
+		}
+		logger.Infof("[%s] Dropping the config history database", ledgerID)
+		if err := dropLedgerData(ledgerconfig.GetConfigHistoryPath(), ledgerID); err != nil {
+			return errors.WithMessage(err, "error dropping the config history database")
+		}
+		logger.Infof("[%s] Dropping the bookkeeping of the state", ledgerID)
+		if err := dropBookkeeping(ledgerID); err != nil {
+			return errors.WithMessage(err, "error dropping the bookkeeping of the state")
+		}
+	}
+	if dropHistory {
+		logger.Infof("[%s] Dropping the history database", ledgerID)
+		if err := dropLedgerData(ledgerconfig.GetHistoryLevelDBPath(), ledgerID); err != nil {
+			return errors.WithMessage(err, "error dropping the history database")
+		}
+	}
+	return nil
+}
+
+func dropStateDB(ledgerID string, metricsProvider metrics.Provider) error {
+	if statedb.VersionedDBProviderName(ledgerconfig.GetStateDatabase()) == statedb.CouchDB {
+		return statecouchdb.DropLedgerDBs(ledgerID, metricsProvider)
+	}
+	return dropLedgerData(ledgerconfig.GetStateLevelDBPath(), ledgerID)
+}
+
+// dropLedgerData deletes the keys of a ledger from the database located at dbPath,
+// where the data of each ledger is kept in a db named after the ledger
+func dropLedgerData(dbPath, ledgerID string) error {
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+	defer dbProvider.Close()
+	return dbProvider.GetDBHandle(ledgerID).DeleteAll()
+}
+
+// dropBookkeeping deletes the bookkeeping maintained along with the state. The pending
+// snapshot requests are kept, as they cannot be rebuilt from the block store
+func dropBookkeeping(ledgerID string) error {
+	bookkeepingProvider := bookkeeping.NewProvider()
+	defer bookkeepingProvider.Close()
+	for _, cat := range []bookkeeping.Category{bookkeeping.PvtdataExpiry, bookkeeping.MetadataPresenceIndicator} {
+		if err := bookkeepingProvider.GetDBHandle(ledgerID, cat).DeleteAll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeStateListener(stateListeners []ledger.StateListener, toRemove ledger.StateListener) []ledger.StateListener {
+	var remaining []ledger.StateListener
+	for _, l := range stateListeners {
+		if l != toRemove {
+			remaining = append(remaining, l)
+		}
+	}
+	return remaining
+}
+
+// rebuildLedgers opens the ledgers in parallel, which recommits the blocks of each of them
+// to the dropped databases
+func rebuildLedgers(provider *Provider, ledgerIDs []string) error {
+	var wg sync.WaitGroup
+	var failedMutex sync.Mutex
+	var failed []string
+	parallelism := make(chan struct{}, runtime.NumCPU())
+	for _, ledgerID := range ledgerIDs {
+		wg.Add(1)
+		go func(ledgerID string) {
+			defer wg.Done()
+			parallelism <- struct{}{}
+			defer func() { <-parallelism }()
+
+			logger.Infof("[%s] Rebuilding the databases", ledgerID)
+			l, err := provider.openInternal(ledgerID)
+			if err != nil {
+				logger.Errorf("[%s] Failed to rebuild the databases: %+v", ledgerID, err)
+				failedMutex.Lock()
+				failed = append(failed, ledgerID)
+				failedMutex.Unlock()
+				return
+			}
+			l.Close()
+			logger.Infof("[%s] Rebuilt the databases", ledgerID)
+		}(ledgerID)
+	}
+	wg.Wait()
+	if len(failed) > 0 {
+		return errors.Errorf("failed to rebuild the databases of ledgers %v, the databases will be rebuilt on the next peer start", failed)
+	}
+	return nil
+}
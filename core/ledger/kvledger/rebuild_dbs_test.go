@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildDBs(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	for _, ledgerID := range []string{"ledger1", "ledger2"} {
+		bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+		l, err := provider.Create(gb)
+		require.NoError(t, err)
+		for i, value := range []string{"value1", "value2", "value3"} {
+			blk := prepareNextBlockForTest(t, l, bg, ledgerID+"-txid-"+value, map[string]string{"key1": value}, nil)
+			require.NoError(t, l.CommitWithPvtData(blk, &lgr.CommitOptions{}))
+			if i == 0 {
+				require.NoError(t, l.SubmitSnapshotRequest(10))
+			}
+		}
+		l.Close()
+	}
+	provider.Close()
+
+	initializer := &lgr.Initializer{
+		DeployedChaincodeInfoProvider: &mock.DeployedChaincodeInfoProvider{},
+		MetricsProvider:               &disabled.Provider{},
+	}
+	assert.EqualError(t, RebuildDBs("ledger1", "unknown", initializer),
+		"unknown database type [unknown], the type must be either state or history")
+	assert.EqualError(t, RebuildDBs("noLedger", StateDBType, initializer), "ledger [noLedger] does not exist")
+
+	provider = testutilNewProvider(t)
+	assert.Contains(t, RebuildDBs("ledger1", StateDBType, initializer).Error(), "as another peer node command is executing")
+	provider.Close()
+
+	verifyLedger := func(ledgerID string) {
+		provider := testutilNewProvider(t)
+		defer provider.Close()
+		l, err := provider.Open(ledgerID)
+		require.NoError(t, err)
+		defer l.Close()
+
+		qe, err := l.NewQueryExecutor()
+		require.NoError(t, err)
+		defer qe.Done()
+		value, err := qe.GetState("ns", "key1")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("value3"), value)
+
+		hqe, err := l.NewHistoryQueryExecutor()
+		require.NoError(t, err)
+		itr, err := hqe.GetHistoryForKey("ns", "key1")
+		require.NoError(t, err)
+		defer itr.Close()
+		numValues := 0
+		for {
+			res, err := itr.Next()
+			require.NoError(t, err)
+			if res == nil {
+				break
+			}
+			numValues++
+		}
+		assert.Equal(t, 3, numValues)
+
+		// the pending snapshot requests are kept
+		pending, err := l.PendingSnapshotRequests()
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{10}, pending)
+	}
+	// the dbs are rebuilt by the command, and not when the ledgers are opened next time
+	verifyRebuilt := func(dbPath, ledgerID string) {
+		dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+		defer dbProvider.Close()
+		itr := dbProvider.GetDBHandle(ledgerID).GetIterator(nil, nil)
+		defer itr.Release()
+		assert.True(t, itr.Next())
+	}
+
+	t.Run("state of one ledger", func(t *testing.T) {
+		require.NoError(t, RebuildDBs("ledger1", StateDBType, initializer))
+		verifyRebuilt(ledgerconfig.GetStateLevelDBPath(), "ledger1")
+		verifyLedger("ledger1")
+		verifyLedger("ledger2")
+	})
+
+	t.Run("history of all the ledgers", func(t *testing.T) {
+		require.NoError(t, RebuildDBs("", HistoryDBType, initializer))
+		verifyRebuilt(ledgerconfig.GetHistoryLevelDBPath(), "ledger1")
+		verifyRebuilt(ledgerconfig.GetHistoryLevelDBPath(), "ledger2")
+		verifyLedger("ledger1")
+		verifyLedger("ledger2")
+	})
+
+	t.Run("all the dbs of all the ledgers", func(t *testing.T) {
+		require.NoError(t, RebuildDBs("", "", initializer))
+		for _, ledgerID := range []string{"ledger1", "ledger2"} {
+			verifyRebuilt(ledgerconfig.GetStateLevelDBPath(), ledgerID)
+			verifyRebuilt(ledgerconfig.GetHistoryLevelDBPath(), ledgerID)
+			verifyLedger(ledgerID)
+		}
+	})
+
+	t.Run("history database disabled", func(t *testing.T) {
+		viper.Set("ledger.history.enableHistoryDatabase", false)
+		defer viper.Set("ledger.history.enableHistoryDatabase", true)
+		assert.EqualError(t, RebuildDBs("", HistoryDBType, initializer), "the history database is disabled")
+	})
+
+	t.Run("unsupported state database", func(t *testing.T) {
+		statedb.RegisterVersionedDBProvider("TestDB", func(metrics.Provider) (statedb.VersionedDBProvider, error) {
+			return nil, errors.New("not implemented")
+		})
+		viper.Set("ledger.state.stateDatabase", "TestDB")
+		defer viper.Set("ledger.state.stateDatabase", "goleveldb")
+		assert.EqualError(t, RebuildDBs("", StateDBType, initializer),
+			"the state database is TestDB, only goleveldb and CouchDB state databases can be rebuilt")
+	})
+}
+
+func TestRebuildDBsOfLedgerFromSnapshot(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	// the ledger is named after the channel of the transactions of the test blocks,
+	// as the ledger created from the snapshot is named after the channel of its last block
+	ledgerID := util.GetTestChainID()
+	bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	blk1 := prepareNextBlockForTest(t, l, bg, "txid-1", map[string]string{"key1": "value1"}, nil)
+	require.NoError(t, l.CommitWithPvtData(blk1, &lgr.CommitOptions{}))
+	require.NoError(t, l.SubmitSnapshotRequest(0))
+	l.Close()
+	provider.Close()
+	snapshotDir := SnapshotDir(ledgerID, 1)
+
+	// a second peer, with its own file system path, creates the ledger from the snapshot
+	env2 := newTestEnv(t)
+	defer env2.cleanup()
+	provider2 := testutilNewProvider(t)
+	l2, _, err := provider2.CreateFromSnapshot(snapshotDir)
+	require.NoError(t, err)
+	l2.Close()
+	provider2.Close()
+
+	initializer := &lgr.Initializer{
+		DeployedChaincodeInfoProvider: &mock.DeployedChaincodeInfoProvider{},
+		MetricsProvider:               &disabled.Provider{},
+	}
+	assert.EqualError(t, RebuildDBs(ledgerID, "", initializer),
+		"the databases of ledger [testchainid] cannot be rebuilt as its block store starts at block [1]")
+}
@@ -0,0 +1,787 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/confighistory"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	snapshotMetadataFileName        = "_snapshot_metadata.json"
+	snapshotPublicStateFileName     = "public_state.data"
+	snapshotPvtStateHashesFileName  = "private_state_hashes.data"
+	snapshotTxIDsFileName           = "txids.data"
+	snapshotConfigHistoryFileName   = "confighistory.data"
+	snapshotLastBlockFileName       = "last_block.data"
+	snapshotLastConfigBlockFileName = "last_config_block.data"
+)
+
+// snapshotMetadata is stored in the directory of a snapshot along with its data files.
+// It carries the sha256 hashes of the data files, hex encoded, to detect corrupted snapshots
+type snapshotMetadata struct {
+	ChannelName       string            `json:"channel_name"`
+	LastBlockNumber   uint64            `json:"last_block_number"`
+	LastBlockHash     string            `json:"last_block_hash"`
+	PreviousBlockHash string            `json:"previous_block_hash"`
+	FileHashes        map[string]string `json:"file_hashes"`
+}
+
+// SnapshotsDir returns the directory where the snapshots of a ledger are placed once generated
+func SnapshotsDir(ledgerID string) string {
+	return filepath.Join(ledgerconfig.GetSnapshotsRootDir(), "completed", ledgerID)
+}
+
+// SnapshotDir returns the directory of the snapshot of a ledger generated at the given block
+func SnapshotDir(ledgerID string, blockNum uint64) string {
+	return filepath.Join(SnapshotsDir(ledgerID), strconv.FormatUint(blockNum, 10))
+}
+
+func snapshotsTempDir() string {
+	return filepath.Join(ledgerconfig.GetSnapshotsRootDir(), "temp")
+}
+
+// SubmitSnapshotRequest implements the corresponding method from interface ledger.PeerLedger
+func (l *kvLedger) SubmitSnapshotRequest(blockNum uint64) error {
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	lastCommittedBlockNum := bcInfo.Height - 1
+	if blockNum == 0 {
+		blockNum = lastCommittedBlockNum
+	}
+	if blockNum < lastCommittedBlockNum {
+		return errors.Errorf("requested snapshot for block number %d cannot be less than the last committed block number %d",
+			blockNum, lastCommittedBlockNum)
+	}
+	if blockNum == lastCommittedBlockNum {
+		return l.generateSnapshot(nil)
+	}
+
+	key := encodeSnapshotRequestKey(blockNum)
+	existing, err := l.snapshotRequestBookkeeper.Get(key)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return errors.Errorf("duplicate snapshot request for block number %d", blockNum)
+	}
+	if err := l.snapshotRequestBookkeeper.Put(key, []byte{}, true); err != nil {
+		return err
+	}
+	logger.Infof("[%s] Submitted snapshot request for block [%d]", l.ledgerID, blockNum)
+	return nil
+}
+
+// PendingSnapshotRequests implements the corresponding method from interface ledger.PeerLedger
+func (l *kvLedger) PendingSnapshotRequests() ([]uint64, error) {
+	l.blockAPIsRWLock.RLock()
+	defer l.blockAPIsRWLock.RUnlock()
+
+	var blockNums []uint64
+	itr := l.snapshotRequestBookkeeper.GetIterator(nil, nil)
+	defer itr.Release()
+	for itr.Next() {
+		blockNum, _, err := util.DecodeOrderPreservingVarUint64(itr.Key())
+		if err != nil {
+			return nil, errors.Wrap(err, "error decoding snapshot request")
+		}
+		blockNums = append(blockNums, blockNum)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrap(err, "error while iterating over the snapshot requests")
+	}
+	return blockNums, nil
+}
+
+// processSnapshotRequest starts the generation of the snapshot of the ledger, if one was requested
+// for the block just committed. The request is dropped once the generation ends, even if it fails,
+// as the block of the request is committed the snapshot cannot be generated later
+func (l *kvLedger) processSnapshotRequest(blockNum uint64) error {
+	key := encodeSnapshotRequestKey(blockNum)
+	requested, err := l.snapshotRequestBookkeeper.Get(key)
+	if err != nil || requested == nil {
+		return err
+	}
+	dropRequest := func() {
+		if err := l.snapshotRequestBookkeeper.Delete(key, true); err != nil {
+			logger.Errorf("[%s] Failed to drop the snapshot request for block [%d]: %+v", l.ledgerID, blockNum, err)
+		}
+	}
+	if err := l.generateSnapshot(dropRequest); err != nil {
+		logger.Errorf("[%s] Failed to generate the snapshot requested for block [%d]: %+v", l.ledgerID, blockNum, err)
+		return l.snapshotRequestBookkeeper.Delete(key, true)
+	}
+	return nil
+}
+
+// recoverSnapshotRequest generates the snapshot requested for the last committed block, if the peer stopped
+// before generating it. The requests for the blocks before are dropped, the peer stopped while generating
+// their snapshots, which cannot be generated anymore
+func (l *kvLedger) recoverSnapshotRequest() error {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil || bcInfo.Height == 0 {
+		return err
+	}
+	lastBlockNum := bcInfo.Height - 1
+	pending, err := l.PendingSnapshotRequests()
+	if err != nil {
+		return err
+	}
+	for _, blockNum := range pending {
+		if blockNum >= lastBlockNum {
+			break
+		}
+		logger.Warningf("[%s] Dropping the snapshot request for block [%d], the peer stopped while generating the snapshot", l.ledgerID, blockNum)
+		if err := l.snapshotRequestBookkeeper.Delete(encodeSnapshotRequestKey(blockNum), true); err != nil {
+			return err
+		}
+	}
+	return l.processSnapshotRequest(lastBlockNum)
+}
+
+// snapshotSources holds the data of the ledger at the block of a snapshot, captured
+// when the generation of the snapshot starts, to be written to the snapshot files
+type snapshotSources struct {
+	lastBlock        *common.Block
+	lastConfigBlock  *common.Block
+	stateItr         statedb.ResultsIterator
+	txIDsItr         commonledger.ResultsIterator
+	configHistoryItr commonledger.ResultsIterator
+}
+
+func (s *snapshotSources) close() {
+	for _, itr := range []interface{ Close() }{s.stateItr, s.txIDsItr, s.configHistoryItr} {
+		if itr != nil {
+			itr.Close()
+		}
+	}
+}
+
+// generateSnapshot starts the generation of the snapshot of the ledger at the last committed block.
+// The caller holds the blockAPIsRWLock, under which the state, the txids and the config history are
+// captured. They are written to the snapshot files in the background, so that the commit of the next
+// blocks is not held by the generation. The snapshot is written in a temporary directory, which is moved
+// to the snapshot directory once all its files are written. done, if not nil, is called once the
+// generation ends, whether it succeeds or not
+func (l *kvLedger) generateSnapshot(done func()) error {
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	lastBlockNum := bcInfo.Height - 1
+	snapshotDir := SnapshotDir(l.ledgerID, lastBlockNum)
+	if _, err := os.Stat(snapshotDir); err == nil {
+		return errors.Errorf("snapshot for block number %d already exists", lastBlockNum)
+	}
+	l.snapshotsInProgressLock.Lock()
+	defer l.snapshotsInProgressLock.Unlock()
+	if l.snapshotsInProgress[lastBlockNum] {
+		return errors.Errorf("snapshot for block number %d is being generated", lastBlockNum)
+	}
+
+	sources, err := l.captureSnapshotSources(lastBlockNum)
+	if err != nil {
+		return err
+	}
+	metadata := &snapshotMetadata{
+		ChannelName:       l.ledgerID,
+		LastBlockNumber:   lastBlockNum,
+		LastBlockHash:     hex.EncodeToString(bcInfo.CurrentBlockHash),
+		PreviousBlockHash: hex.EncodeToString(bcInfo.PreviousBlockHash),
+		FileHashes:        map[string]string{},
+	}
+	logger.Infof("[%s] Generating snapshot for block [%d]", l.ledgerID, lastBlockNum)
+	l.snapshotsInProgress[lastBlockNum] = true
+	l.snapshotsWG.Add(1)
+	go func() {
+		defer l.snapshotsWG.Done()
+		if err := l.writeSnapshot(snapshotDir, metadata, sources); err != nil {
+			logger.Errorf("[%s] Failed to generate the snapshot for block [%d]: %+v", l.ledgerID, lastBlockNum, err)
+		} else {
+			logger.Infof("[%s] Generated snapshot for block [%d] in %s", l.ledgerID, lastBlockNum, snapshotDir)
+		}
+		l.snapshotsInProgressLock.Lock()
+		delete(l.snapshotsInProgress, lastBlockNum)
+		l.snapshotsInProgressLock.Unlock()
+		if done != nil {
+			done()
+		}
+	}()
+	return nil
+}
+
+// captureSnapshotSources captures the data of the ledger at the last committed block. The iterators
+// read the dbs as of their creation, so that the blocks committed afterwards are not exported
+func (l *kvLedger) captureSnapshotSources(lastBlockNum uint64) (*snapshotSources, error) {
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(lastBlockNum)
+	if err != nil {
+		return nil, err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	lastConfigBlock, err := l.blockStore.RetrieveBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := &snapshotSources{lastBlock: lastBlock, lastConfigBlock: lastConfigBlock}
+	if sources.stateItr, err = l.versionedDB.ExportSnapshotEntries(); err != nil {
+		return nil, errors.WithMessage(err, "error exporting the state")
+	}
+	if sources.txIDsItr, err = l.blockStore.ExportTxIDs(); err != nil {
+		sources.close()
+		return nil, errors.WithMessage(err, "error exporting the txids")
+	}
+	if sources.configHistoryItr, err = l.configHistoryMgr.ExportConfigHistory(l.ledgerID); err != nil {
+		sources.close()
+		return nil, errors.WithMessage(err, "error exporting the config history")
+	}
+	return sources, nil
+}
+
+// writeSnapshot writes the snapshot files from the sources captured for the snapshot, and closes them
+func (l *kvLedger) writeSnapshot(snapshotDir string, metadata *snapshotMetadata, sources *snapshotSources) error {
+	defer sources.close()
+	if err := os.MkdirAll(snapshotsTempDir(), 0755); err != nil {
+		return errors.Wrap(err, "error creating the snapshots temp dir")
+	}
+	tempDir, err := ioutil.TempDir(snapshotsTempDir(), l.ledgerID+"-"+strconv.FormatUint(metadata.LastBlockNumber, 10)+"-")
+	if err != nil {
+		return errors.Wrap(err, "error creating the snapshot temp dir")
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, export := range []func(dir string, fileHashes map[string]string) error{
+		func(dir string, fileHashes map[string]string) error {
+			return exportState(dir, sources.stateItr, fileHashes)
+		},
+		func(dir string, fileHashes map[string]string) error {
+			return exportTxIDs(dir, sources.txIDsItr, fileHashes)
+		},
+		func(dir string, fileHashes map[string]string) error {
+			return exportConfigHistory(dir, sources.configHistoryItr, fileHashes)
+		},
+		func(dir string, fileHashes map[string]string) error {
+			return writeSnapshotBlock(dir, snapshotLastBlockFileName, sources.lastBlock, fileHashes)
+		},
+		func(dir string, fileHashes map[string]string) error {
+			return writeSnapshotBlock(dir, snapshotLastConfigBlockFileName, sources.lastConfigBlock, fileHashes)
+		},
+	} {
+		if err := export(tempDir, metadata.FileHashes); err != nil {
+			return err
+		}
+	}
+
+	metadataBytes, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "error marshaling the snapshot metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDir, snapshotMetadataFileName), metadataBytes, 0644); err != nil {
+		return errors.Wrap(err, "error writing the snapshot metadata")
+	}
+	if err := os.MkdirAll(SnapshotsDir(l.ledgerID), 0755); err != nil {
+		return errors.Wrap(err, "error creating the snapshots dir")
+	}
+	if err := os.Rename(tempDir, snapshotDir); err != nil {
+		return errors.Wrap(err, "error moving the snapshot to the snapshots dir")
+	}
+	return nil
+}
+
+// exportState writes the public state and the hashes of the private state to their snapshot files
+func exportState(dir string, itr statedb.ResultsIterator, fileHashes map[string]string) error {
+	pubWriter, err := newSnapshotFileWriter(dir, snapshotPublicStateFileName)
+	if err != nil {
+		return err
+	}
+	defer pubWriter.close()
+	hashesWriter, err := newSnapshotFileWriter(dir, snapshotPvtStateHashesFileName)
+	if err != nil {
+		return err
+	}
+	defer hashesWriter.close()
+
+	for {
+		result, err := itr.Next()
+		if err != nil {
+			return errors.WithMessage(err, "error exporting the state")
+		}
+		if result == nil {
+			break
+		}
+		entry := result.(*privacyenabledstate.SnapshotEntry)
+		fields := [][]byte{[]byte(entry.Namespace), []byte(entry.Key)}
+		writer := pubWriter
+		if entry.Collection != "" {
+			fields = [][]byte{[]byte(entry.Namespace), []byte(entry.Collection), []byte(entry.Key)}
+			writer = hashesWriter
+		}
+		vv := entry.VersionedValue
+		fields = append(fields, vv.Value, vv.Metadata, vv.Version.ToBytes())
+		if err := writer.encodeBytes(fields...); err != nil {
+			return err
+		}
+	}
+	if err := pubWriter.done(fileHashes); err != nil {
+		return err
+	}
+	return hashesWriter.done(fileHashes)
+}
+
+// exportTxIDs writes the txids of the transactions of the ledger to their snapshot file
+func exportTxIDs(dir string, itr commonledger.ResultsIterator, fileHashes map[string]string) error {
+	writer, err := newSnapshotFileWriter(dir, snapshotTxIDsFileName)
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+	for {
+		result, err := itr.Next()
+		if err != nil {
+			return errors.WithMessage(err, "error exporting the txids")
+		}
+		if result == nil {
+			break
+		}
+		if err := writer.encodeBytes([]byte(result.(string))); err != nil {
+			return err
+		}
+	}
+	return writer.done(fileHashes)
+}
+
+// exportConfigHistory writes the history of the collection configs to its snapshot file
+func exportConfigHistory(dir string, itr commonledger.ResultsIterator, fileHashes map[string]string) error {
+	writer, err := newSnapshotFileWriter(dir, snapshotConfigHistoryFileName)
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+	for {
+		result, err := itr.Next()
+		if err != nil {
+			return errors.WithMessage(err, "error exporting the config history")
+		}
+		if result == nil {
+			break
+		}
+		entry := result.(*confighistory.Entry)
+		if err := writer.encodeBytes([]byte(entry.Namespace), []byte(entry.Key), util.EncodeOrderPreservingVarUint64(entry.BlockNum), entry.Value); err != nil {
+			return err
+		}
+	}
+	return writer.done(fileHashes)
+}
+
+func writeSnapshotBlock(dir, fileName string, block *common.Block, fileHashes map[string]string) error {
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling block [%d]", block.Header.Number)
+	}
+	writer, err := newSnapshotFileWriter(dir, fileName)
+	if err != nil {
+		return err
+	}
+	defer writer.close()
+	if err := writer.encodeBytes(blockBytes); err != nil {
+		return err
+	}
+	return writer.done(fileHashes)
+}
+
+// loadSnapshotMetadata reads the metadata of the snapshot in snapshotDir,
+// and verifies the hashes of the data files of the snapshot
+func loadSnapshotMetadata(snapshotDir string) (*snapshotMetadata, error) {
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading the snapshot metadata")
+	}
+	metadata := &snapshotMetadata{}
+	if err := json.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the snapshot metadata")
+	}
+	for _, fileName := range []string{
+		snapshotPublicStateFileName,
+		snapshotPvtStateHashesFileName,
+		snapshotTxIDsFileName,
+		snapshotConfigHistoryFileName,
+		snapshotLastBlockFileName,
+		snapshotLastConfigBlockFileName,
+	} {
+		expectedHash, ok := metadata.FileHashes[fileName]
+		if !ok {
+			return nil, errors.Errorf("the snapshot metadata does not have the hash of file %s", fileName)
+		}
+		h, err := computeFileHash(filepath.Join(snapshotDir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		if hex.EncodeToString(h) != expectedHash {
+			return nil, errors.Errorf("the hash of file %s does not match the hash in the snapshot metadata", fileName)
+		}
+	}
+	return metadata, nil
+}
+
+func readSnapshotBlock(snapshotDir, fileName string) (*common.Block, error) {
+	reader, err := newSnapshotFileReader(snapshotDir, fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.close()
+	fields, err := reader.decodeBytes(1)
+	if err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, errors.Errorf("snapshot file %s is empty", fileName)
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(fields[0], block); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling the block of snapshot file %s", fileName)
+	}
+	return block, nil
+}
+
+// snapshotTxIDsReader returns the txids of the txids file of a snapshot, as strings, one at a time
+type snapshotTxIDsReader struct {
+	reader *snapshotFileReader
+}
+
+func newSnapshotTxIDsReader(snapshotDir string) (*snapshotTxIDsReader, error) {
+	reader, err := newSnapshotFileReader(snapshotDir, snapshotTxIDsFileName)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotTxIDsReader{reader}, nil
+}
+
+func (r *snapshotTxIDsReader) Next() (commonledger.QueryResult, error) {
+	fields, err := r.reader.decodeBytes(1)
+	if err != nil || fields == nil {
+		return nil, err
+	}
+	return string(fields[0]), nil
+}
+
+func (r *snapshotTxIDsReader) Close() {
+	r.reader.close()
+}
+
+func readSnapshotConfigHistory(snapshotDir string) ([]*confighistory.Entry, error) {
+	reader, err := newSnapshotFileReader(snapshotDir, snapshotConfigHistoryFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.close()
+	var entries []*confighistory.Entry
+	for {
+		fields, err := reader.decodeBytes(4)
+		if err != nil {
+			return nil, err
+		}
+		if fields == nil {
+			return entries, nil
+		}
+		blockNum, _, err := util.DecodeOrderPreservingVarUint64(fields[2])
+		if err != nil {
+			return nil, errors.Wrap(err, "error decoding the block number of a config history entry")
+		}
+		entries = append(entries, &confighistory.Entry{
+			Namespace: string(fields[0]),
+			Key:       string(fields[1]),
+			BlockNum:  blockNum,
+			Value:     fields[3],
+		})
+	}
+}
+
+// importSnapshotState imports the public state and the hashes of the private state of the snapshot
+func importSnapshotState(snapshotDir string, db privacyenabledstate.DB, savepoint *version.Height) error {
+	pubReader, err := newSnapshotFileReader(snapshotDir, snapshotPublicStateFileName)
+	if err != nil {
+		return err
+	}
+	defer pubReader.close()
+	hashesReader, err := newSnapshotFileReader(snapshotDir, snapshotPvtStateHashesFileName)
+	if err != nil {
+		return err
+	}
+	defer hashesReader.close()
+
+	pubDone := false
+	return db.ImportSnapshotEntries(func() (*privacyenabledstate.SnapshotEntry, error) {
+		if !pubDone {
+			fields, err := pubReader.decodeBytes(5)
+			if err != nil || fields != nil {
+				return snapshotEntry(fields, err)
+			}
+			pubDone = true
+		}
+		fields, err := hashesReader.decodeBytes(6)
+		if err != nil || fields == nil {
+			return nil, err
+		}
+		collection := string(fields[1])
+		entry, err := snapshotEntry(append(fields[:1], fields[2:]...), nil)
+		if err != nil {
+			return nil, err
+		}
+		entry.Collection = collection
+		return entry, nil
+	}, savepoint)
+}
+
+// maxMissingPvtDataBatchSize is the number of missing pvt data entries recorded per update of the pvt data store
+const maxMissingPvtDataBatchSize = 10000
+
+type missingPvtDataKey struct {
+	blkNum, txNum uint64
+	ns, coll      string
+}
+
+type missingPvtDataCollInfo struct {
+	btl      uint64
+	eligible bool
+}
+
+// importMissingPvtData records the pvt data of the transactions of the snapshot the ledger was created from as
+// missing, so that the reconciliation fetches it from the other peers. For each key of the hashes of the private
+// state, the pvt data of the transaction that wrote it last is missing. It is eligible if the peer is a member of
+// the collection as per the collection config of the last block of the snapshot, lastBlockNum
+func (l *kvLedger) importMissingPvtData(snapshotDir string, lastBlockNum uint64,
+	membershipInfoProvider ledger.MembershipInfoProvider, ccInfoProvider ledger.DeployedChaincodeInfoProvider) error {
+	reader, err := newSnapshotFileReader(snapshotDir, snapshotPvtStateHashesFileName)
+	if err != nil {
+		return err
+	}
+	defer reader.close()
+
+	collInfoRetriever := &collectionInfoRetriever{l, ccInfoProvider}
+	collInfos := map[nsColl]*missingPvtDataCollInfo{}
+	recorded := map[missingPvtDataKey]bool{}
+	missingPvtData := map[uint64]ledger.TxMissingPvtDataMap{}
+	numRecorded := 0
+	for {
+		fields, err := reader.decodeBytes(6)
+		if err != nil {
+			return err
+		}
+		if fields == nil {
+			break
+		}
+		ns, coll := string(fields[0]), string(fields[1])
+		ver, _, err := version.NewHeightFromBytes(fields[5])
+		if err != nil {
+			return errors.WithMessage(err, "error decoding the version of a state entry")
+		}
+		key := missingPvtDataKey{ver.BlockNum, ver.TxNum, ns, coll}
+		if recorded[key] {
+			continue
+		}
+
+		info, ok := collInfos[nsColl{ns, coll}]
+		if !ok {
+			collConfig, err := collInfoRetriever.CollectionInfo(ns, coll)
+			if err != nil {
+				return err
+			}
+			if collConfig != nil {
+				eligible, err := membershipInfoProvider.AmMemberOf(l.ledgerID, collConfig.MemberOrgsPolicy)
+				if err != nil {
+					return err
+				}
+				info = &missingPvtDataCollInfo{btl: collConfig.BlockToLive, eligible: eligible}
+			} else {
+				logger.Warningf("[%s] The private data of collection [%s:%s] is not recorded as missing, the collection config is not found", l.ledgerID, ns, coll)
+			}
+			collInfos[nsColl{ns, coll}] = info
+		}
+		// the pvt data expired by the last block of the snapshot is not missing
+		if info == nil || (info.btl != 0 && ver.BlockNum+info.btl+1 <= lastBlockNum) {
+			continue
+		}
+
+		recorded[key] = true
+		if missingPvtData[ver.BlockNum] == nil {
+			missingPvtData[ver.BlockNum] = ledger.TxMissingPvtDataMap{}
+		}
+		missingPvtData[ver.BlockNum].Add(ver.TxNum, ns, coll, info.eligible)
+		numRecorded++
+		if len(recorded) >= maxMissingPvtDataBatchSize {
+			if err := l.blockStore.ImportMissingPvtData(missingPvtData); err != nil {
+				return err
+			}
+			recorded = map[missingPvtDataKey]bool{}
+			missingPvtData = map[uint64]ledger.TxMissingPvtDataMap{}
+		}
+	}
+	if err := l.blockStore.ImportMissingPvtData(missingPvtData); err != nil {
+		return err
+	}
+	logger.Infof("[%s] Recorded %d missing private data entries of the snapshot at block [%d]", l.ledgerID, numRecorded, lastBlockNum)
+	return nil
+}
+
+// snapshotEntry constructs a state entry from its namespace, key, value, metadata and version fields
+func snapshotEntry(fields [][]byte, err error) (*privacyenabledstate.SnapshotEntry, error) {
+	if err != nil {
+		return nil, err
+	}
+	ver, _, err := version.NewHeightFromBytes(fields[4])
+	if err != nil {
+		return nil, errors.WithMessage(err, "error decoding the version of a state entry")
+	}
+	return &privacyenabledstate.SnapshotEntry{
+		Namespace: string(fields[0]),
+		Key:       string(fields[1]),
+		VersionedValue: &statedb.VersionedValue{
+			Value:    fields[2],
+			Metadata: fields[3],
+			Version:  ver,
+		},
+	}, nil
+}
+
+func encodeSnapshotRequestKey(blockNum uint64) []byte {
+	return util.EncodeOrderPreservingVarUint64(blockNum)
+}
+
+// snapshotFileWriter writes the records of a data file of a snapshot, each field of a
+// record being prefixed by its length, and computes the hash of the file while writing it
+type snapshotFileWriter struct {
+	fileName  string
+	file      *os.File
+	bufWriter *bufio.Writer
+	hasher    hash.Hash
+	writer    io.Writer
+}
+
+func newSnapshotFileWriter(dir, fileName string) (*snapshotFileWriter, error) {
+	file, err := os.Create(filepath.Join(dir, fileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating snapshot file %s", fileName)
+	}
+	bufWriter := bufio.NewWriter(file)
+	hasher := sha256.New()
+	return &snapshotFileWriter{
+		fileName:  fileName,
+		file:      file,
+		bufWriter: bufWriter,
+		hasher:    hasher,
+		writer:    io.MultiWriter(bufWriter, hasher),
+	}, nil
+}
+
+func (w *snapshotFileWriter) encodeBytes(fields ...[]byte) error {
+	for _, field := range fields {
+		if _, err := w.writer.Write(proto.EncodeVarint(uint64(len(field)))); err != nil {
+			return errors.Wrapf(err, "error writing snapshot file %s", w.fileName)
+		}
+		if _, err := w.writer.Write(field); err != nil {
+			return errors.Wrapf(err, "error writing snapshot file %s", w.fileName)
+		}
+	}
+	return nil
+}
+
+// done flushes the file to the disk and adds its hash to fileHashes
+func (w *snapshotFileWriter) done(fileHashes map[string]string) error {
+	if err := w.bufWriter.Flush(); err != nil {
+		return errors.Wrapf(err, "error writing snapshot file %s", w.fileName)
+	}
+	if err := w.file.Sync(); err != nil {
+		return errors.Wrapf(err, "error syncing snapshot file %s", w.fileName)
+	}
+	fileHashes[w.fileName] = hex.EncodeToString(w.hasher.Sum(nil))
+	return nil
+}
+
+func (w *snapshotFileWriter) close() {
+	w.file.Close()
+}
+
+// snapshotFileReader reads the records written by a snapshotFileWriter
+type snapshotFileReader struct {
+	fileName  string
+	file      *os.File
+	bufReader *bufio.Reader
+}
+
+func newSnapshotFileReader(dir, fileName string) (*snapshotFileReader, error) {
+	file, err := os.Open(filepath.Join(dir, fileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening snapshot file %s", fileName)
+	}
+	return &snapshotFileReader{fileName: fileName, file: file, bufReader: bufio.NewReader(file)}, nil
+}
+
+// decodeBytes reads a record of numFields fields. It returns nil at the end of the file
+func (r *snapshotFileReader) decodeBytes(numFields int) ([][]byte, error) {
+	fields := make([][]byte, numFields)
+	for i := range fields {
+		length, err := binary.ReadUvarint(r.bufReader)
+		if err == io.EOF && i == 0 {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading snapshot file %s", r.fileName)
+		}
+		fields[i] = make([]byte, length)
+		if _, err := io.ReadFull(r.bufReader, fields[i]); err != nil {
+			return nil, errors.Wrapf(err, "error reading snapshot file %s", r.fileName)
+		}
+	}
+	return fields, nil
+}
+
+func (r *snapshotFileReader) close() {
+	r.file.Close()
+}
+
+func computeFileHash(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening snapshot file %s", filepath.Base(path))
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return nil, errors.Wrapf(err, "error reading snapshot file %s", filepath.Base(path))
+	}
+	return hasher.Sum(nil), nil
+}
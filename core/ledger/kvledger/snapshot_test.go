@@ -0,0 +1,224 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	commonutil "github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotGenerationAndCreation(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProviderWithCollectionConfig(t, "ns", map[string]uint64{"coll": 0})
+	defer provider.Close()
+
+	// the ledger is named after the channel of the transactions of the test blocks,
+	// as the ledger created from the snapshot is named after the channel of its last block
+	testLedgerID := commonutil.GetTestChainID()
+	bg, gb := testutil.NewBlockGenerator(t, testLedgerID, false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer l.Close()
+
+	blk1 := prepareNextBlockForTest(t, l, bg, "txid-1",
+		map[string]string{"key1": "value1", "key2": "value2"}, map[string]string{"key1": "pvtValue1"})
+	require.NoError(t, l.CommitWithPvtData(blk1, &lgr.CommitOptions{}))
+
+	// a snapshot is requested for a future block
+	require.NoError(t, l.SubmitSnapshotRequest(3))
+	assert.EqualError(t, l.SubmitSnapshotRequest(3), "duplicate snapshot request for block number 3")
+	pending, err := l.PendingSnapshotRequests()
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3}, pending)
+
+	blk2 := prepareNextBlockForTest(t, l, bg, "txid-2",
+		map[string]string{"key1": "value3"}, map[string]string{"key2": "pvtValue2"})
+	require.NoError(t, l.CommitWithPvtData(blk2, &lgr.CommitOptions{}))
+	blk3 := prepareNextBlockForTest(t, l, bg, "txid-3",
+		map[string]string{"key3": "value4"}, nil)
+	require.NoError(t, l.CommitWithPvtData(blk3, &lgr.CommitOptions{}))
+
+	// the snapshot is generated in the background when the block of the request is committed
+	l.(*kvLedger).snapshotsWG.Wait()
+	pending, err = l.PendingSnapshotRequests()
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	snapshotDir := SnapshotDir(testLedgerID, 3)
+	_, err = os.Stat(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	require.NoError(t, err)
+	assert.EqualError(t, l.SubmitSnapshotRequest(1), "requested snapshot for block number 1 cannot be less than the last committed block number 3")
+	assert.EqualError(t, l.SubmitSnapshotRequest(0), "snapshot for block number 3 already exists")
+	bcInfo, err := l.GetBlockchainInfo()
+	require.NoError(t, err)
+
+	// the snapshot is copied away, as the test env of the second peer replaces the one of the first peer
+	copiedSnapshotDir, err := ioutil.TempDir("", "kvledger-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(copiedSnapshotDir)
+	files, err := ioutil.ReadDir(snapshotDir)
+	require.NoError(t, err)
+	for _, f := range files {
+		b, err := ioutil.ReadFile(filepath.Join(snapshotDir, f.Name()))
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(copiedSnapshotDir, f.Name()), b, 0644))
+	}
+	l.Close()
+	provider.Close()
+
+	// a second peer creates the ledger from the snapshot
+	env2 := newTestEnv(t)
+	defer env2.cleanup()
+	provider2 := testutilNewProviderWithCollectionConfig(t, "ns", map[string]uint64{"coll": 0})
+	defer provider2.Close()
+	provider2.(*Provider).initializer.MembershipInfoProvider.(*mock.MembershipInfoProvider).AmMemberOfReturns(true, nil)
+	l2, ledgerID, err := provider2.CreateFromSnapshot(copiedSnapshotDir)
+	require.NoError(t, err)
+	defer l2.Close()
+	assert.Equal(t, testLedgerID, ledgerID)
+
+	bcInfo2, err := l2.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, bcInfo, bcInfo2)
+	qe, err := l2.NewQueryExecutor()
+	require.NoError(t, err)
+	for key, value := range map[string]string{"key1": "value3", "key2": "value2", "key3": "value4"} {
+		v, err := qe.GetState("ns", key)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(value), v)
+	}
+	for key, value := range map[string]string{"key1": "pvtValue1", "key2": "pvtValue2"} {
+		h, err := qe.GetPrivateDataHash("ns", "coll", key)
+		require.NoError(t, err)
+		assert.Equal(t, util.ComputeStringHash(value), h)
+	}
+	qe.Done()
+
+	// the private data of the eligible collections is recorded as missing
+	missingPvtDataTracker, err := l2.GetMissingPvtDataTracker()
+	require.NoError(t, err)
+	missingPvtDataInfo, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	require.NoError(t, err)
+	expectedMissingPvtDataInfo := make(lgr.MissingPvtDataInfo)
+	expectedMissingPvtDataInfo.Add(1, 0, "ns", "coll")
+	expectedMissingPvtDataInfo.Add(2, 0, "ns", "coll")
+	assert.Equal(t, expectedMissingPvtDataInfo, missingPvtDataInfo)
+
+	// the missing private data is validated against the hashes in the state, as the blocks are not available
+	blk1PvtData := proto.Clone(blk1.PvtData[0].WriteSet).(*rwset.TxPvtReadWriteSet)
+	staleBlk2PvtData := proto.Clone(blk1.PvtData[0].WriteSet).(*rwset.TxPvtReadWriteSet)
+	hashMismatches, err := l2.CommitPvtDataOfOldBlocks([]*lgr.BlockPvtData{
+		{BlockNum: 1, WriteSets: lgr.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: blk1PvtData}}},
+		{BlockNum: 2, WriteSets: lgr.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: staleBlk2PvtData}}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, hashMismatches)
+	qe, err = l2.NewQueryExecutor()
+	require.NoError(t, err)
+	v, err := qe.GetPrivateData("ns", "coll", "key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("pvtValue1"), v)
+	// the stale private data of block 2 does not match its hashes and is not committed
+	v, err = qe.GetPrivateData("ns", "coll", "key2")
+	_, ok := err.(*txmgr.ErrPvtdataNotAvailable)
+	assert.True(t, ok)
+	assert.Nil(t, v)
+	qe.Done()
+
+	// the blocks and transactions before the last block of the snapshot are not available
+	_, err = l2.GetBlockByNumber(1)
+	assert.Error(t, err)
+	_, err = l2.GetTransactionByID("txid-1")
+	assert.Error(t, err)
+	b, err := l2.GetBlockByNumber(3)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(blk3.Block, b), "proto messages are not equal")
+	b, err = l2.GetBlockByNumber(0)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(gb, b), "proto messages are not equal")
+
+	// the ledger created from the snapshot commits the next blocks
+	blk4 := prepareNextBlockForTest(t, l2, bg, "txid-4",
+		map[string]string{"key1": "value5"}, nil)
+	require.NoError(t, l2.CommitWithPvtData(blk4, &lgr.CommitOptions{}))
+
+	// the history database covers the blocks from the last block of the snapshot
+	hqe, err := l2.NewHistoryQueryExecutor()
+	require.NoError(t, err)
+	v, err = hqe.GetStateAtHeight("ns", "key1", 4)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value5"), v)
+	v, err = hqe.GetStateAtHeight("ns", "key3", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value4"), v)
+	_, err = hqe.GetStateAtHeight("ns", "key1", 2)
+	assert.EqualError(t, err, "block number [2] is before the first block [3] covered by the history database, the ledger was created from a snapshot")
+	_, err = hqe.GetStateAtHeight("ns", "key1", 3)
+	assert.EqualError(t, err, "the value of key [key1] at block number [3] is not available, the key was not written between the first block [3] covered by the history database and block number [3]")
+	_, err = hqe.GetStateAtHeight("ns", "key2", 4)
+	assert.EqualError(t, err, "the value of key [key2] at block number [4] is not available, the key was not written between the first block [3] covered by the history database and block number [4]")
+	_, err = hqe.GetStateRangeAtHeight("ns", "", "", 4)
+	assert.EqualError(t, err, "the values of a range of keys are not available, the history database covers the keys written since block number [3] only")
+
+	_, _, err = provider2.CreateFromSnapshot(copiedSnapshotDir)
+	assert.Equal(t, ErrLedgerIDExists, err)
+	l2.Close()
+	provider2.Close()
+
+	provider2 = testutilNewProviderWithCollectionConfig(t, "ns", map[string]uint64{"coll": 0})
+	l2, err = provider2.Open(testLedgerID)
+	require.NoError(t, err)
+	bcInfo2, err = l2.GetBlockchainInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), bcInfo2.Height)
+}
+
+func TestCreateFromCorruptedSnapshot(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	// the ledger is named after the channel of the transactions of the test blocks,
+	// as the ledger created from the snapshot is named after the channel of its last block
+	testLedgerID := commonutil.GetTestChainID()
+	bg, gb := testutil.NewBlockGenerator(t, testLedgerID, false)
+	l, err := provider.Create(gb)
+	require.NoError(t, err)
+	defer l.Close()
+	blk1 := prepareNextBlockForTest(t, l, bg, "txid-1", map[string]string{"key1": "value1"}, nil)
+	require.NoError(t, l.CommitWithPvtData(blk1, &lgr.CommitOptions{}))
+	require.NoError(t, l.SubmitSnapshotRequest(0))
+	l.(*kvLedger).snapshotsWG.Wait()
+	snapshotDir := SnapshotDir(testLedgerID, 1)
+
+	// the snapshot of a ledger already created on the peer is rejected
+	_, _, err = provider.CreateFromSnapshot(snapshotDir)
+	assert.Equal(t, ErrLedgerIDExists, err)
+
+	stateFile := filepath.Join(snapshotDir, snapshotPublicStateFileName)
+	require.NoError(t, ioutil.WriteFile(stateFile, []byte("corrupted"), 0644))
+	_, _, err = provider.CreateFromSnapshot(snapshotDir)
+	assert.EqualError(t, err, "the hash of file public_state.data does not match the hash in the snapshot metadata")
+
+	require.NoError(t, os.Remove(stateFile))
+	_, _, err = provider.CreateFromSnapshot(snapshotDir)
+	assert.Error(t, err)
+}
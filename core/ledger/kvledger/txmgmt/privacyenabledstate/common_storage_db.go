@@ -18,8 +18,9 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	// the state databases shipped with fabric register themselves on import
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/pkg/errors"
@@ -42,14 +43,9 @@ type CommonStorageDBProvider struct {
 
 // NewCommonStorageDBProvider constructs an instance of DBProvider
 func NewCommonStorageDBProvider(bookkeeperProvider bookkeeping.Provider, metricsProvider metrics.Provider, healthCheckRegistry ledger.HealthCheckRegistry) (DBProvider, error) {
-	var vdbProvider statedb.VersionedDBProvider
-	var err error
-	if ledgerconfig.IsCouchDBEnabled() {
-		if vdbProvider, err = statecouchdb.NewVersionedDBProvider(metricsProvider); err != nil {
-			return nil, err
-		}
-	} else {
-		vdbProvider = stateleveldb.NewVersionedDBProvider()
+	vdbProvider, err := statedb.NewVersionedDBProvider(ledgerconfig.GetStateDatabase(), metricsProvider)
+	if err != nil {
+		return nil, err
 	}
 
 	dbProvider := &CommonStorageDBProvider{vdbProvider, healthCheckRegistry, bookkeeperProvider}
@@ -64,7 +60,7 @@ func NewCommonStorageDBProvider(bookkeeperProvider bookkeeping.Provider, metrics
 
 func (p *CommonStorageDBProvider) RegisterHealthChecker() error {
 	if healthChecker, ok := p.VersionedDBProvider.(healthz.HealthChecker); ok {
-		return p.HealthCheckRegistry.RegisterChecker("couchdb", healthChecker)
+		return p.HealthCheckRegistry.RegisterChecker(strings.ToLower(statedb.VersionedDBProviderName(ledgerconfig.GetStateDatabase())), healthChecker)
 	}
 	return nil
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/mock"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
 )
 
 func TestHealthCheckRegister(t *testing.T) {
@@ -29,6 +30,8 @@ func TestHealthCheckRegister(t *testing.T) {
 	gt.Expect(err).NotTo(HaveOccurred())
 	gt.Expect(fakeHealthCheckRegistry.RegisterCheckerCallCount()).To(Equal(0))
 
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	defer viper.Set("ledger.state.stateDatabase", "goleveldb")
 	dbProvider.VersionedDBProvider = &statecouchdb.VersionedDBProvider{}
 	err = dbProvider.RegisterHealthChecker()
 	gt.Expect(err).NotTo(HaveOccurred())
@@ -39,6 +39,13 @@ type DB interface {
 	GetPrivateDataMetadataByHash(namespace, collection string, keyHash []byte) ([]byte, error)
 	ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error)
 	ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error
+	// ExportSnapshotEntries returns an iterator over the entries, as *SnapshotEntry, of the public state and
+	// of the hashes of the private state, the private state itself is not exported. The iterator reads the
+	// state as of the call, whatever is committed while it is consumed
+	ExportSnapshotEntries() (statedb.ResultsIterator, error)
+	// ImportSnapshotEntries imports the entries returned by next, until it returns nil,
+	// and records savepoint as the savepoint of the db once all the entries are imported
+	ImportSnapshotEntries(next func() (*SnapshotEntry, error), savepoint *version.Height) error
 }
 
 // PvtdataCompositeKey encloses Namespace, CollectionName and Key components
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/pkg/errors"
+)
+
+// maxSnapshotBatchSize is the number of entries imported from a snapshot per update of the db
+const maxSnapshotBatchSize = 10000
+
+// SnapshotEntry is an entry of the public state, or of the hashes of the private state, of a snapshot
+type SnapshotEntry struct {
+	Namespace string
+	// Collection is empty for the entries of the public state
+	Collection string
+	// Key is the hash of the key for the entries of the hashes of the private state
+	Key            string
+	VersionedValue *statedb.VersionedValue
+}
+
+// ExportSnapshotEntries implements corresponding function in interface DB
+func (s *CommonStorageDB) ExportSnapshotEntries() (statedb.ResultsIterator, error) {
+	fullScanCapable, ok := s.VersionedDB.(statedb.FullScanCapable)
+	if !ok {
+		return nil, errors.New("the state database does not support exporting its state to a snapshot, only goleveldb does")
+	}
+	itr, err := fullScanCapable.GetFullScanIterator()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotEntriesItr{itr}, nil
+}
+
+// snapshotEntriesItr converts the results of a full scan of the state db to snapshot entries
+type snapshotEntriesItr struct {
+	dbItr statedb.ResultsIterator
+}
+
+func (itr *snapshotEntriesItr) Next() (statedb.QueryResult, error) {
+	for {
+		result, err := itr.dbItr.Next()
+		if err != nil || result == nil {
+			return nil, err
+		}
+		kv := result.(*statedb.VersionedKV)
+		entry := &SnapshotEntry{Namespace: kv.Namespace, Key: kv.Key, VersionedValue: &kv.VersionedValue}
+		if ns, prefix, coll, ok := splitDerivedNs(kv.Namespace); ok {
+			// the private data itself is not part of the snapshot
+			if prefix == pvtDataPrefix {
+				continue
+			}
+			entry.Namespace, entry.Collection = ns, coll
+		}
+		return entry, nil
+	}
+}
+
+func (itr *snapshotEntriesItr) Close() {
+	itr.dbItr.Close()
+}
+
+// ImportSnapshotEntries implements corresponding function in interface DB
+func (s *CommonStorageDB) ImportSnapshotEntries(next func() (*SnapshotEntry, error), savepoint *version.Height) error {
+	batch := NewUpdateBatch()
+	numEntries := 0
+	for {
+		entry, err := next()
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			break
+		}
+		vv := entry.VersionedValue
+		if entry.Collection == "" {
+			batch.PubUpdates.PutValAndMetadata(entry.Namespace, entry.Key, vv.Value, vv.Metadata, vv.Version)
+		} else {
+			batch.HashUpdates.PutValHashAndMetadata(entry.Namespace, entry.Collection, []byte(entry.Key), vv.Value, vv.Metadata, vv.Version)
+		}
+		numEntries++
+		if numEntries%maxSnapshotBatchSize == 0 {
+			// the savepoint is only recorded with the last batch, so that an
+			// interrupted import can be told apart from a completed one
+			if err := s.ApplyPrivacyAwareUpdates(batch, nil); err != nil {
+				return err
+			}
+			batch = NewUpdateBatch()
+		}
+	}
+	logger.Debugf("Imported %d entries of a snapshot", numEntries)
+	return s.ApplyPrivacyAwareUpdates(batch, savepoint)
+}
+
+// splitDerivedNs splits a namespace derived for the private data, or for the hashes
+// of the private data, of a collection into the namespace, the prefix and the collection
+func splitDerivedNs(derivedNs string) (string, string, string, bool) {
+	i := strings.Index(derivedNs, nsJoiner)
+	if i < 0 || len(derivedNs) == i+len(nsJoiner) {
+		return "", "", "", false
+	}
+	prefixAndColl := derivedNs[i+len(nsJoiner):]
+	return derivedNs[:i], prefixAndColl[:1], prefixAndColl[1:], true
+}
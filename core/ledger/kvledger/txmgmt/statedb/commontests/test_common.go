@@ -966,3 +966,18 @@ func TestApplyUpdatesWithNilHeight(t *testing.T, dbProvider statedb.VersionedDBP
 	assert.Equal(t, savePoint, ht) // savepoint should still be what was set with batch1
 	// (because batch2 calls ApplyUpdates with savepoint as nil)
 }
+
+// TestVersionedDBProvider runs the tests that hold for any state database, whatever the queries
+// it supports, against dbProvider. The implementations registered with statedb.RegisterVersionedDBProvider
+// are expected to pass them
+func TestVersionedDBProvider(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	t.Run("GetStateMultipleKeys", func(t *testing.T) { TestGetStateMultipleKeys(t, dbProvider) })
+	t.Run("BasicRW", func(t *testing.T) { TestBasicRW(t, dbProvider) })
+	t.Run("MultiDBBasicRW", func(t *testing.T) { TestMultiDBBasicRW(t, dbProvider) })
+	t.Run("Deletes", func(t *testing.T) { TestDeletes(t, dbProvider) })
+	t.Run("Iterator", func(t *testing.T) { TestIterator(t, dbProvider) })
+	t.Run("GetVersion", func(t *testing.T) { TestGetVersion(t, dbProvider) })
+	t.Run("ValueAndMetadataWrites", func(t *testing.T) { TestValueAndMetadataWrites(t, dbProvider) })
+	t.Run("PaginatedRangeQuery", func(t *testing.T) { TestPaginatedRangeQuery(t, dbProvider) })
+	t.Run("ApplyUpdatesWithNilHeight", func(t *testing.T) { TestApplyUpdatesWithNilHeight(t, dbProvider) })
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("statedb")
+
+const (
+	// GoLevelDB is the name of the state database stored in goleveldb
+	GoLevelDB = "goleveldb"
+	// CouchDB is the name of the state database stored in CouchDB
+	CouchDB = "CouchDB"
+)
+
+// VersionedDBProviderFactory creates a VersionedDBProvider. The implementations
+// read their own configuration, under the ledger.state section of core.yaml
+type VersionedDBProviderFactory func(metricsProvider metrics.Provider) (VersionedDBProvider, error)
+
+var (
+	versionedDBProvidersLock sync.RWMutex
+	versionedDBProviders     = map[string]VersionedDBProviderFactory{}
+)
+
+// RegisterVersionedDBProvider makes a state database available under the given name, which is the
+// value of ledger.state.stateDatabase selecting it in core.yaml. Any factory previously registered
+// under the same name is replaced.
+// The implementations shipped with fabric register themselves when their package is imported
+func RegisterVersionedDBProvider(name string, factory VersionedDBProviderFactory) {
+	versionedDBProvidersLock.Lock()
+	defer versionedDBProvidersLock.Unlock()
+
+	versionedDBProviders[name] = factory
+}
+
+// VersionedDBProviderName returns the name of the state database selected by name, the value of
+// ledger.state.stateDatabase. As before state databases were registered, the names are matched exactly,
+// and a name under which no state database is registered selects goleveldb
+func VersionedDBProviderName(name string) string {
+	versionedDBProvidersLock.RLock()
+	defer versionedDBProvidersLock.RUnlock()
+
+	if _, ok := versionedDBProviders[name]; ok {
+		return name
+	}
+	return GoLevelDB
+}
+
+// NewVersionedDBProvider returns an instance of the VersionedDBProvider selected by name
+func NewVersionedDBProvider(name string, metricsProvider metrics.Provider) (VersionedDBProvider, error) {
+	selectedName := VersionedDBProviderName(name)
+	if selectedName != name {
+		logger.Warningf("State database [%s] is not registered, falling back to %s. The available state databases are %v, "+
+			"set ledger.state.stateDatabase to one of them", name, selectedName, RegisteredVersionedDBProviders())
+	}
+	versionedDBProvidersLock.RLock()
+	factory, ok := versionedDBProviders[selectedName]
+	versionedDBProvidersLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("state database not found [%s], available state databases are %v", name, RegisteredVersionedDBProviders())
+	}
+
+	dbProvider, err := factory(metricsProvider)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed initializing state database [%s]", selectedName)
+	}
+	return dbProvider, nil
+}
+
+// RegisteredVersionedDBProviders returns the sorted names of the registered state databases
+func RegisteredVersionedDBProviders() []string {
+	versionedDBProvidersLock.RLock()
+	defer versionedDBProvidersLock.RUnlock()
+
+	var names []string
+	for name := range versionedDBProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
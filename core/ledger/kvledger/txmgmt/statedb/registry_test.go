@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testVersionedDBProvider struct {
+	metricsProvider metrics.Provider
+}
+
+func (p *testVersionedDBProvider) GetDBHandle(id string) (VersionedDB, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *testVersionedDBProvider) Close() {}
+
+func TestVersionedDBProviderRegistry(t *testing.T) {
+	defer func() {
+		versionedDBProvidersLock.Lock()
+		delete(versionedDBProviders, "TestDB")
+		delete(versionedDBProviders, "FaultyDB")
+		delete(versionedDBProviders, GoLevelDB)
+		versionedDBProvidersLock.Unlock()
+	}()
+
+	RegisterVersionedDBProvider("TestDB", func(metricsProvider metrics.Provider) (VersionedDBProvider, error) {
+		return &testVersionedDBProvider{metricsProvider}, nil
+	})
+	RegisterVersionedDBProvider("FaultyDB", func(metrics.Provider) (VersionedDBProvider, error) {
+		return nil, errors.New("connection refused")
+	})
+	assert.Contains(t, RegisteredVersionedDBProviders(), "TestDB")
+	assert.Contains(t, RegisteredVersionedDBProviders(), "FaultyDB")
+
+	metricsProvider := &disabled.Provider{}
+	dbProvider, err := NewVersionedDBProvider("TestDB", metricsProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, &testVersionedDBProvider{metricsProvider}, dbProvider)
+
+	_, err = NewVersionedDBProvider("FaultyDB", metricsProvider)
+	assert.EqualError(t, err, "failed initializing state database [FaultyDB]: connection refused")
+
+	// without goleveldb to fall back to, an unknown name is an error
+	_, err = NewVersionedDBProvider("unknown", metricsProvider)
+	assert.Contains(t, err.Error(), "state database not found [unknown], available state databases are")
+
+	// the names are matched exactly, and an unknown name falls back to goleveldb
+	goLevelDBProvider := &testVersionedDBProvider{}
+	RegisterVersionedDBProvider(GoLevelDB, func(metrics.Provider) (VersionedDBProvider, error) {
+		return goLevelDBProvider, nil
+	})
+	for _, name := range []string{"unknown", "testdb", "TESTDB", GoLevelDB} {
+		assert.Equal(t, GoLevelDB, VersionedDBProviderName(name))
+		dbProvider, err = NewVersionedDBProvider(name, metricsProvider)
+		assert.NoError(t, err)
+		assert.True(t, dbProvider == goLevelDBProvider, "name [%s] does not select goleveldb", name)
+	}
+	assert.Equal(t, "TestDB", VersionedDBProviderName("TestDB"))
+
+	// registering under an existing name replaces the factory
+	RegisterVersionedDBProvider("TestDB", func(metrics.Provider) (VersionedDBProvider, error) {
+		return &testVersionedDBProvider{}, nil
+	})
+	dbProvider, err = NewVersionedDBProvider("TestDB", metricsProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, &testVersionedDBProvider{}, dbProvider)
+}
@@ -31,6 +31,16 @@ const querySkip = 0
 // LsccCacheSize denotes the number of entries allowed in the lsccStateCache
 const lsccCacheSize = 50
 
+func init() {
+	statedb.RegisterVersionedDBProvider(statedb.CouchDB, func(metricsProvider metrics.Provider) (statedb.VersionedDBProvider, error) {
+		dbProvider, err := NewVersionedDBProvider(metricsProvider)
+		if err != nil {
+			return nil, err
+		}
+		return dbProvider, nil
+	})
+}
+
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	couchInstance *couchdb.CouchInstance
@@ -77,6 +87,32 @@ func (provider *VersionedDBProvider) HealthCheck(ctx context.Context) error {
 	return provider.couchInstance.HealthCheck(ctx)
 }
 
+// DropLedgerDBs drops the metadata database and the namespace databases of a ledger
+func DropLedgerDBs(ledgerID string, metricsProvider metrics.Provider) error {
+	couchDBDef := couchdb.GetCouchDBDefinition()
+	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB, metricsProvider)
+	if err != nil {
+		return err
+	}
+	dbNames, err := couchInstance.RetrieveApplicationDBNames()
+	if err != nil {
+		return err
+	}
+	ledgerDBNames, err := couchdb.SelectLedgerDBNames(ledgerID, dbNames)
+	if err != nil {
+		return err
+	}
+	for _, dbName := range ledgerDBNames {
+		logger.Debugf("[%s] Dropping database [%s]", ledgerID, dbName)
+		db := &couchdb.CouchDatabase{CouchInstance: couchInstance, DBName: dbName}
+		if _, err := db.DropDatabase(); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error dropping database [%s]", dbName))
+		}
+	}
+	return nil
+}
+
 // VersionedDB implements VersionedDB interface
 type VersionedDB struct {
 	couchInstance      *couchdb.CouchInstance
@@ -16,6 +16,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
@@ -145,6 +146,15 @@ func TestPaginatedRangeQuery(t *testing.T) {
 	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
 }
 
+func TestRegisteredVersionedDBProvider(t *testing.T) {
+	dbProvider, err := statedb.NewVersionedDBProvider(statedb.CouchDB, &disabled.Provider{})
+	assert.NoError(t, err)
+	assert.IsType(t, &VersionedDBProvider{}, dbProvider)
+	defer dbProvider.Close()
+	defer CleanupDB(t, dbProvider)
+	commontests.TestVersionedDBProvider(t, dbProvider)
+}
+
 // TestUtilityFunctions tests utility functions
 func TestUtilityFunctions(t *testing.T) {
 
@@ -837,3 +847,28 @@ func assertQueryResults(t *testing.T, results []*couchdb.QueryResult, expectedId
 	}
 	assert.Equal(t, expectedIds, actualIds)
 }
+
+func TestDropLedgerDBs(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	for _, ledgerID := range []string{"testdropledgerdbs", "testdropledgerdbs2"} {
+		db, err := env.DBProvider.GetDBHandle(ledgerID)
+		assert.NoError(t, err)
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+		batch.Put("ns2", "key1", []byte("value1"), version.NewHeight(1, 2))
+		assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 2)))
+	}
+
+	assert.NoError(t, DropLedgerDBs("testdropledgerdbs", &disabled.Provider{}))
+	dbNames, err := env.DBProvider.(*VersionedDBProvider).couchInstance.RetrieveApplicationDBNames()
+	assert.NoError(t, err)
+	for _, dbName := range []string{"testdropledgerdbs_", "testdropledgerdbs_ns1", "testdropledgerdbs_ns2"} {
+		assert.NotContains(t, dbNames, dbName)
+	}
+	for _, dbName := range []string{"testdropledgerdbs2_", "testdropledgerdbs2_ns1", "testdropledgerdbs2_ns2"} {
+		assert.Contains(t, dbNames, dbName)
+	}
+	// the dropped databases are not dropped again on cleanup
+	delete(env.DBProvider.(*VersionedDBProvider).databases, "testdropledgerdbs")
+}
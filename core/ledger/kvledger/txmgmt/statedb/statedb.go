@@ -88,6 +88,14 @@ type IndexCapable interface {
 	ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error
 }
 
+// FullScanCapable interface provides additional functions for
+// databases capable of iterating over all their keys
+type FullScanCapable interface {
+	// GetFullScanIterator returns an iterator over all the keys of the db, in the order of their namespaces.
+	// The returned iterator contains results of type *VersionedKV
+	GetFullScanIterator() (ResultsIterator, error)
+}
+
 // CompositeKey encloses Namespace and Key components
 type CompositeKey struct {
 	Namespace string
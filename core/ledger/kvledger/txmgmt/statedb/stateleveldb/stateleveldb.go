@@ -10,6 +10,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -23,6 +24,12 @@ var compositeKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 var savePointKey = []byte{0x00}
 
+func init() {
+	statedb.RegisterVersionedDBProvider(statedb.GoLevelDB, func(metrics.Provider) (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider(), nil
+	})
+}
+
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	dbProvider *leveldbhelper.Provider
@@ -154,6 +161,11 @@ func (vdb *versionedDB) GetStateRangeScanIteratorWithMetadata(namespace string,
 
 }
 
+// GetFullScanIterator implements method in FullScanCapable interface
+func (vdb *versionedDB) GetFullScanIterator() (statedb.ResultsIterator, error) {
+	return &fullScanner{dbItr: vdb.db.GetIterator(nil, nil)}, nil
+}
+
 // ExecuteQuery implements method in VersionedDB interface
 func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
 	return nil, errors.New("ExecuteQuery not supported for leveldb")
@@ -278,3 +290,32 @@ func (scanner *kvScanner) GetBookmarkAndClose() string {
 	scanner.Close()
 	return retval
 }
+
+type fullScanner struct {
+	dbItr iterator.Iterator
+}
+
+func (scanner *fullScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		if bytes.Equal(dbKey, savePointKey) {
+			continue
+		}
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		namespace, key := splitCompositeKey(dbKey)
+		vv, err := decodeValue(dbValCopy)
+		if err != nil {
+			return nil, err
+		}
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+			VersionedValue: *vv}, nil
+	}
+	return nil, errors.Wrap(scanner.dbItr.Error(), "error while iterating over the state db")
+}
+
+func (scanner *fullScanner) Close() {
+	scanner.dbItr.Release()
+}
@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -56,6 +57,16 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestRegisteredVersionedDBProvider(t *testing.T) {
+	removeDBPath(t, "TestRegisteredVersionedDBProvider")
+	defer removeDBPath(t, "TestRegisteredVersionedDBProvider")
+	dbProvider, err := statedb.NewVersionedDBProvider(statedb.GoLevelDB, &disabled.Provider{})
+	assert.NoError(t, err)
+	assert.IsType(t, &VersionedDBProvider{}, dbProvider)
+	defer dbProvider.Close()
+	commontests.TestVersionedDBProvider(t, dbProvider)
+}
+
 func TestCompositeKey(t *testing.T) {
 	testCompositeKey(t, "ledger1", "ns", "key")
 	testCompositeKey(t, "ledger2", "ns", "")
@@ -136,3 +147,50 @@ func TestApplyUpdatesWithNilHeight(t *testing.T) {
 	defer env.Cleanup()
 	commontests.TestApplyUpdatesWithNilHeight(t, env.DBProvider)
 }
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	db, err := env.DBProvider.GetDBHandle("testfullscan")
+	assert.NoError(t, err)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns2", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.PutValAndMetadata("ns1", "key2", []byte("value2"), []byte("metadata2"), version.NewHeight(1, 2))
+	batch.Put("ns1", "key1", []byte("value3"), version.NewHeight(2, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+
+	// the keys of other dbs are not part of the scan
+	otherDB, err := env.DBProvider.GetDBHandle("testfullscanother")
+	assert.NoError(t, err)
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "otherkey", []byte("othervalue"), version.NewHeight(1, 1))
+	assert.NoError(t, otherDB.ApplyUpdates(batch, version.NewHeight(1, 1)))
+
+	itr, err := db.(statedb.FullScanCapable).GetFullScanIterator()
+	assert.NoError(t, err)
+	defer itr.Close()
+	var results []*statedb.VersionedKV
+	for {
+		result, err := itr.Next()
+		assert.NoError(t, err)
+		if result == nil {
+			break
+		}
+		results = append(results, result.(*statedb.VersionedKV))
+	}
+	assert.Equal(t, []*statedb.VersionedKV{
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(2, 1)},
+		},
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key2"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value2"), Metadata: []byte("metadata2"), Version: version.NewHeight(1, 2)},
+		},
+		{
+			CompositeKey:   statedb.CompositeKey{Namespace: "ns2", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)},
+		},
+	}, results)
+}
@@ -8,6 +8,7 @@ package ledger
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-lib-go/healthz"
@@ -35,6 +36,10 @@ type PeerLedgerProvider interface {
 	// This function guarantees that the creation of ledger and committing the genesis block would an atomic action
 	// The chain id retrieved from the genesis block is treated as a ledger id
 	Create(genesisBlock *common.Block) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from the snapshot in the given directory, and returns it along
+	// with its id. The blockchain of the ledger starts at the last block of the snapshot, the blocks before
+	// it are not available except for the last config block of the snapshot
+	CreateFromSnapshot(snapshotDir string) (PeerLedger, string, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exists
@@ -102,6 +107,12 @@ type PeerLedger interface {
 	//     missing info is recorded in the ledger (or)
 	// (3) the block is committed and does not contain any pvtData.
 	DoesPvtDataInfoExist(blockNum uint64) (bool, error)
+	// SubmitSnapshotRequest submits a request to generate a snapshot of the ledger once the given block is
+	// committed. A block number of 0 denotes the last committed block, for which the snapshot is generated
+	// right away. The snapshots are generated under the directory returned by kvledger.SnapshotDir
+	SubmitSnapshotRequest(blockNum uint64) error
+	// PendingSnapshotRequests returns the block numbers of the pending snapshot requests, in increasing order
+	PendingSnapshotRequests() ([]uint64, error)
 }
 
 // SimpleQueryExecutor encapsulates basic functions
@@ -178,6 +189,40 @@ type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetStateAtHeight retrieves the value of a key as it was after the block blockNum was committed.
+	// A nil value is returned if the key did not exist or was deleted at that height. For a ledger created
+	// from a snapshot, an error is returned if the key was not written since the last block of the snapshot
+	GetStateAtHeight(namespace string, key string, blockNum uint64) ([]byte, error)
+	// GetStateRangeAtHeight retrieves the values of the keys between startKey (inclusive) and endKey (exclusive)
+	// as they were after the block blockNum was committed. An empty endKey denotes the last key of the namespace.
+	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
+	// It is not supported on a ledger created from a snapshot.
+	GetStateRangeAtHeight(namespace string, startKey string, endKey string, blockNum uint64) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeyWithOptions retrieves the history of values for a key, filtered, ordered and paginated as per the options.
+	// The returned QueryResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
+	GetHistoryForKeyWithOptions(namespace string, key string, options *HistoryQueryOptions) (QueryResultsIterator, error)
+	// GetHistoryForKeyRange retrieves the history of values for the keys between startKey (inclusive) and endKey (exclusive),
+	// filtered, ordered and paginated as per the options. An empty endKey denotes the last key of the namespace.
+	// The history of the keys is returned key by key, and the returned QueryResultsIterator contains results of
+	// type *KeyModification, with the key set, which is defined in protos/ledger/queryresult.
+	GetHistoryForKeyRange(namespace string, startKey string, endKey string, options *HistoryQueryOptions) (QueryResultsIterator, error)
+}
+
+// HistoryQueryOptions filters, orders and paginates the results of the history queries.
+// The zero value of an option leaves it unset
+type HistoryQueryOptions struct {
+	// StartBlock and EndBlock bound, inclusively, the blocks of the returned values. An EndBlock
+	// of 0 denotes the last block
+	StartBlock uint64
+	EndBlock   uint64
+	// StartTime (inclusive) and EndTime (exclusive) bound the timestamps of the transactions of the returned values
+	StartTime time.Time
+	EndTime   time.Time
+	// NewestFirst returns the values of a key from the newest to the oldest, and the keys of a range in reverse order
+	NewestFirst bool
+	// PageSize limits the number of returned values, and Bookmark resumes a query from where a previous page ended
+	PageSize int32
+	Bookmark string
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
@@ -9,6 +9,7 @@ package ledgerconfig
 import (
 	"path/filepath"
 
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
 )
@@ -22,6 +23,16 @@ func IsCouchDBEnabled() bool {
 	return false
 }
 
+// GetStateDatabase returns the name of the state database configured for the
+// peer, goleveldb if none is configured
+func GetStateDatabase() string {
+	stateDatabase := viper.GetString(confStateDatabase)
+	if stateDatabase == "" {
+		return "goleveldb"
+	}
+	return stateDatabase
+}
+
 const confPeerFileSystemPath = "peer.fileSystemPath"
 const confLedgersData = "ledgersData"
 const confLedgerProvider = "ledgerProvider"
@@ -31,13 +42,19 @@ const confBookkeeper = "bookkeeper"
 const confConfigHistory = "configHistory"
 const confChains = "chains"
 const confPvtdataStore = "pvtdataStore"
+const confSnapshots = "snapshots"
 const fileLockPath = "fileLock"
+const confStateDatabase = "ledger.state.stateDatabase"
 const confTotalQueryLimit = "ledger.state.totalQueryLimit"
 const confInternalQueryLimit = "ledger.state.couchDBConfig.internalQueryLimit"
 const confEnableHistoryDatabase = "ledger.history.enableHistoryDatabase"
 const confMaxBatchSize = "ledger.state.couchDBConfig.maxBatchUpdateSize"
 const confAutoWarmIndexes = "ledger.state.couchDBConfig.autoWarmIndexes"
 const confWarmIndexesAfterNBlocks = "ledger.state.couchDBConfig.warmIndexesAfterNBlocks"
+const confEncryptionEnabled = "ledger.encryption.enabled"
+const confEncryptionKey = "ledger.encryption.key"
+const confEncryptionPreviousKeys = "ledger.encryption.previousKeys"
+const confSnapshotsRootDir = "ledger.snapshots.rootDir"
 
 var confCollElgProcMaxDbBatchSize = &conf{"ledger.pvtdataStore.collElgProcMaxDbBatchSize", 5000}
 var confCollElgProcDbBatchesInterval = &conf{"ledger.pvtdataStore.collElgProcDbBatchesInterval", 1000}
@@ -89,6 +106,14 @@ func GetConfigHistoryPath() string {
 	return filepath.Join(GetRootPath(), confConfigHistory)
 }
 
+// GetSnapshotsRootDir returns the filesystem path under which the snapshots of the ledgers are generated
+func GetSnapshotsRootDir() string {
+	if viper.GetString(confSnapshotsRootDir) != "" {
+		return config.GetPath(confSnapshotsRootDir)
+	}
+	return filepath.Join(GetRootPath(), confSnapshots)
+}
+
 // GetMaxBlockfileSize returns maximum size of the block file
 func GetMaxBlockfileSize() int {
 	return 64 * 1024 * 1024
@@ -192,6 +217,18 @@ func GetWarmIndexesAfterNBlocks() int {
 	return warmAfterNBlocks
 }
 
+// GetAtRestEncryptionConfig returns the keys encrypting the private data and
+// the transient store at rest, nil if the encryption is not enabled
+func GetAtRestEncryptionConfig() *atrest.Config {
+	if !viper.GetBool(confEncryptionEnabled) {
+		return nil
+	}
+	return &atrest.Config{
+		Key:          viper.GetString(confEncryptionKey),
+		PreviousKeys: viper.GetStringSlice(confEncryptionPreviousKeys),
+	}
+}
+
 type conf struct {
 	Name       string
 	DefaultVal int
@@ -19,6 +19,7 @@ package ledgerconfig
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -46,6 +47,16 @@ func TestIsCouchDBEnabled(t *testing.T) {
 	assert.True(t, updatedValue) //test config returns true
 }
 
+func TestGetStateDatabase(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, "goleveldb", GetStateDatabase())
+	viper.Set("ledger.state.stateDatabase", "")
+	assert.Equal(t, "goleveldb", GetStateDatabase())
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	assert.Equal(t, "CouchDB", GetStateDatabase())
+}
+
 func TestLedgerConfigPathDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	assert.Equal(t, "/var/hyperledger/production/ledgersData", GetRootPath())
@@ -250,6 +261,27 @@ func TestGetMaxBlockfileSize(t *testing.T) {
 	assert.Equal(t, 67108864, GetMaxBlockfileSize())
 }
 
+func TestGetAtRestEncryptionConfig(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Nil(t, GetAtRestEncryptionConfig())
+
+	viper.Set("ledger.encryption.enabled", true)
+	viper.Set("ledger.encryption.key", "0a0b")
+	viper.Set("ledger.encryption.previousKeys", []string{"0c0d", "0e0f"})
+	conf := GetAtRestEncryptionConfig()
+	assert.Equal(t, &atrest.Config{Key: "0a0b", PreviousKeys: []string{"0c0d", "0e0f"}}, conf)
+}
+
+func TestGetSnapshotsRootDir(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, "/var/hyperledger/production/ledgersData/snapshots", GetSnapshotsRootDir())
+
+	viper.Set("ledger.snapshots.rootDir", "/tmp/snapshots")
+	assert.Equal(t, "/tmp/snapshots", GetSnapshotsRootDir())
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
@@ -109,6 +109,26 @@ func CreateLedger(genesisBlock *common.Block) (ledger.PeerLedger, error) {
 	return l, nil
 }
 
+// CreateLedgerFromSnapshot creates a new ledger from the snapshot in the given directory,
+// and returns it along with its id
+func CreateLedgerFromSnapshot(snapshotDir string) (ledger.PeerLedger, string, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return nil, "", ErrLedgerMgmtNotInitialized
+	}
+
+	logger.Infof("Creating ledger from snapshot in %s", snapshotDir)
+	l, id, err := ledgerProvider.CreateFromSnapshot(snapshotDir)
+	if err != nil {
+		return nil, "", err
+	}
+	l = wrapLedger(id, l)
+	openedLedgers[id] = l
+	logger.Infof("Created ledger [%s] from snapshot", id)
+	return l, id, nil
+}
+
 // OpenLedger returns a ledger for the given id
 func OpenLedger(id string) (ledger.PeerLedger, error) {
 	logger.Infof("Opening ledger with id = %s", id)
@@ -11,6 +11,7 @@ import (
 	"sync/atomic"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/metrics"
@@ -48,28 +49,44 @@ var attrsToIndex = []blkstorage.IndexableAttr{
 }
 
 // NewProvider returns the handle to the provider
-func NewProvider(metricsProvider metrics.Provider) *Provider {
+func NewProvider(metricsProvider metrics.Provider) (*Provider, error) {
+	pvtStoreProvider, err := pvtdatastorage.NewProvider()
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the block storage
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
 	blockStoreProvider := fsblkstorage.NewProvider(
 		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
 		indexConfig,
 		metricsProvider)
-
-	pvtStoreProvider := pvtdatastorage.NewProvider()
-	return &Provider{blockStoreProvider, pvtStoreProvider}
+	return &Provider{blockStoreProvider, pvtStoreProvider}, nil
 }
 
 // Open opens the store
 func (p *Provider) Open(ledgerid string) (*Store, error) {
-	var blockStore blkstorage.BlockStore
-	var pvtdataStore pvtdatastorage.Store
-	var err error
+	blockStore, err := p.blkStoreProvider.OpenBlockStore(ledgerid)
+	if err != nil {
+		return nil, err
+	}
+	return p.open(ledgerid, blockStore)
+}
 
-	if blockStore, err = p.blkStoreProvider.OpenBlockStore(ledgerid); err != nil {
+// BootstrapFromSnapshot creates the store of a ledger starting at lastBlock, the last block of a snapshot.
+// The pvt data store starts at lastBlock too, without the pvt data of the blocks of the snapshot, which
+// is to be recorded as missing
+func (p *Provider) BootstrapFromSnapshot(ledgerid string, lastBlock, lastConfigBlock *common.Block, txIDs commonledger.ResultsIterator) (*Store, error) {
+	blockStore, err := p.blkStoreProvider.BootstrapFromSnapshot(ledgerid, lastBlock, lastConfigBlock, txIDs)
+	if err != nil {
 		return nil, err
 	}
-	if pvtdataStore, err = p.pvtdataStoreProvider.OpenStore(ledgerid); err != nil {
+	return p.open(ledgerid, blockStore)
+}
+
+func (p *Provider) open(ledgerid string, blockStore blkstorage.BlockStore) (*Store, error) {
+	pvtdataStore, err := p.pvtdataStoreProvider.OpenStore(ledgerid)
+	if err != nil {
 		return nil, err
 	}
 	store := &Store{
@@ -258,6 +275,11 @@ func (s *Store) ProcessCollsEligibilityEnabled(committingBlk uint64, nsCollMap m
 	return s.pvtdataStore.ProcessCollsEligibilityEnabled(committingBlk, nsCollMap)
 }
 
+// ImportMissingPvtData invokes the function on underlying pvtdata store
+func (s *Store) ImportMissingPvtData(blocksMissingPvtData map[uint64]ledger.TxMissingPvtDataMap) error {
+	return s.pvtdataStore.ImportMissingPvtData(blocksMissingPvtData)
+}
+
 // GetLastUpdatedOldBlocksPvtData invokes the function on underlying pvtdata store
 func (s *Store) GetLastUpdatedOldBlocksPvtData() (map[uint64][]*ledger.TxPvtData, error) {
 	return s.pvtdataStore.GetLastUpdatedOldBlocksPvtData()
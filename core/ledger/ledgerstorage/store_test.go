@@ -39,7 +39,8 @@ func TestMain(m *testing.M) {
 func TestStore(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open("testLedger")
 	store.Init(btlPolicyForSampleData())
@@ -146,7 +147,8 @@ func TestStoreWithExistingBlockchain(t *testing.T) {
 
 	// Simulating the upgrade from 1.0 situation:
 	// Open the ledger storage - pvtdata store is opened for the first time with an existing block storage
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open(testLedgerid)
 	store.Init(btlPolicyForSampleData())
@@ -168,7 +170,8 @@ func TestStoreWithExistingBlockchain(t *testing.T) {
 func TestCrashAfterPvtdataStorePreparation(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open("testLedger")
 	store.Init(btlPolicyForSampleData())
@@ -193,7 +196,8 @@ func TestCrashAfterPvtdataStorePreparation(t *testing.T) {
 	provider.Close()
 
 	// restart the store
-	provider = NewProvider(metricsProvider)
+	provider, err = NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	store, err = provider.Open("testLedger")
 	assert.NoError(t, err)
 	store.Init(btlPolicyForSampleData())
@@ -214,7 +218,8 @@ func TestCrashAfterPvtdataStorePreparation(t *testing.T) {
 func TestCrashAfterPvtdataStorePreparationWithReset(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open("testLedger")
 	store.Init(btlPolicyForSampleData())
@@ -242,7 +247,8 @@ func TestCrashAfterPvtdataStorePreparationWithReset(t *testing.T) {
 	fsblkstorage.ResetBlockStore(ledgerconfig.GetBlockStorePath())
 
 	// restart the store
-	provider = NewProvider(metricsProvider)
+	provider, err = NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	store, err = provider.Open("testLedger")
 	assert.NoError(t, err)
 	store.Init(btlPolicyForSampleData())
@@ -266,7 +272,8 @@ func testVerifyPvtData(t *testing.T, blkPvtdata ledger.TxPvtDataMap, pvtdata led
 func TestCrashBeforePvtdataStoreCommit(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open("testLedger")
 	store.Init(btlPolicyForSampleData())
@@ -293,7 +300,8 @@ func TestCrashBeforePvtdataStoreCommit(t *testing.T) {
 	store.Shutdown()
 	provider.Close()
 
-	provider = NewProvider(metricsProvider)
+	provider, err = NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	store, err = provider.Open("testLedger")
 	assert.NoError(t, err)
 	store.Init(btlPolicyForSampleData())
@@ -314,7 +322,8 @@ func TestCrashBeforePvtdataStoreCommit(t *testing.T) {
 func TestCrashBeforePvtdataStoreCommitWithReset(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open("testLedger")
 	store.Init(btlPolicyForSampleData())
@@ -344,7 +353,8 @@ func TestCrashBeforePvtdataStoreCommitWithReset(t *testing.T) {
 	// reset the block store to the genesis block
 	fsblkstorage.ResetBlockStore(ledgerconfig.GetBlockStorePath())
 
-	provider = NewProvider(metricsProvider)
+	provider, err = NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	store, err = provider.Open("testLedger")
 	assert.NoError(t, err)
 	store.Init(btlPolicyForSampleData())
@@ -358,7 +368,8 @@ func TestCrashBeforePvtdataStoreCommitWithReset(t *testing.T) {
 func TestAddAfterPvtdataStoreError(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open("testLedger")
 	store.Init(btlPolicyForSampleData())
@@ -395,7 +406,8 @@ func TestAddAfterPvtdataStoreError(t *testing.T) {
 func TestAddAfterBlkStoreError(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	defer provider.Close()
 	store, err := provider.Open("testLedger")
 	store.Init(btlPolicyForSampleData())
@@ -425,7 +437,8 @@ func TestAddAfterBlkStoreError(t *testing.T) {
 func TestPvtStoreAheadOfBlockStore(t *testing.T) {
 	testEnv := newTestEnv(t)
 	defer testEnv.cleanup()
-	provider := NewProvider(metricsProvider)
+	provider, err := NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	store, err := provider.Open("testLedger")
 	assert.NoError(t, err)
 	store.Init(btlPolicyForSampleData())
@@ -441,7 +454,8 @@ func TestPvtStoreAheadOfBlockStore(t *testing.T) {
 	// close and reopen
 	store.Shutdown()
 	provider.Close()
-	provider = NewProvider(metricsProvider)
+	provider, err = NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	store, err = provider.Open("testLedger")
 	assert.NoError(t, err)
 	store.Init(btlPolicyForSampleData())
@@ -467,7 +481,8 @@ func TestPvtStoreAheadOfBlockStore(t *testing.T) {
 	// close and reopen
 	store.Shutdown()
 	provider.Close()
-	provider = NewProvider(metricsProvider)
+	provider, err = NewProvider(metricsProvider)
+	assert.NoError(t, err)
 	store, err = provider.Open("testLedger")
 	assert.NoError(t, err)
 	store.Init(btlPolicyForSampleData())
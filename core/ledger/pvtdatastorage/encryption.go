@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/pkg/errors"
+)
+
+func (s *store) encodeDataValue(collData *rwset.CollectionPvtReadWriteSet) ([]byte, error) {
+	valBytes, err := encodeDataValue(collData)
+	if err != nil {
+		return nil, err
+	}
+	return s.encryptor.Encrypt(valBytes)
+}
+
+func (s *store) decodeDataValue(datavalueBytes []byte) (*rwset.CollectionPvtReadWriteSet, error) {
+	valBytes, err := s.encryptor.Decrypt(datavalueBytes)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDataValue(valBytes)
+}
+
+// migrateEncryption brings the private data written before the encryption
+// was enabled, or before the encryption key was rotated, in line with the
+// current encryption configuration. The key in use is recorded in the store
+// so that the migration is skipped on the next openings.
+func (s *store) migrateEncryption() error {
+	marker, err := s.db.Get(encryptionKeyIDKey)
+	if err != nil {
+		return err
+	}
+	expectedMarker := encodeEncryptionKeyID(s.encryptor.KeyID())
+	if s.encryptor == nil {
+		if marker == nil {
+			return nil
+		}
+		if len(marker) > 1 {
+			return errors.Errorf("pvtdata store for ledger [%s] is encrypted but no at-rest encryption key is configured", s.ledgerid)
+		}
+		return s.db.Delete(encryptionKeyIDKey, true)
+	}
+	if bytes.Equal(marker, expectedMarker) {
+		return nil
+	}
+
+	logger.Infof("Migrating the encryption of the pvtdata store for ledger [%s]", s.ledgerid)
+	maxBatchSize := ledgerconfig.GetPvtdataStoreCollElgProcMaxDbBatchSize()
+	batch := leveldbhelper.NewUpdateBatch()
+	numMigrated := 0
+	// the data entries written in the v11 format, which hold the write set of a whole
+	// transaction, share the key prefix of the data entries and are migrated with them
+	itr := s.db.GetIterator(pvtDataKeyPrefix, expiryKeyPrefix)
+	defer itr.Release()
+	for itr.Next() {
+		value := itr.Value()
+		if !s.encryptor.NeedsReencryption(value) {
+			continue
+		}
+		newValue, err := s.encryptor.Reencrypt(value)
+		if err != nil {
+			return errors.WithMessagef(err, "failed migrating the encryption of key [%x]", itr.Key())
+		}
+		batch.Put(itr.Key(), newValue)
+		numMigrated++
+		if batch.Len() >= maxBatchSize {
+			if err := s.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	batch.Put(encryptionKeyIDKey, expectedMarker)
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Infof("Migrated the encryption of %d private data entries of ledger [%s]", numMigrated, s.ledgerid)
+	return nil
+}
+
+// encodeEncryptionKeyID encodes the SKI of the key encrypting the store,
+// the version byte alone stands for a store written in plaintext.
+func encodeEncryptionKeyID(keyID []byte) []byte {
+	return append([]byte{1}, keyID...)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/gm"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
+	"github.com/hyperledger/fabric/core/ledger"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreEncryption(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestStoreEncryption", btlPolicy)
+	defer env.Cleanup()
+
+	csp, err := gm.NewDefaultSecurityLevelWithKeystore(gm.NewDummyKeyStore())
+	require.NoError(t, err)
+	key1, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	key2, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+
+	reopen := func(encryptor *atrest.Encryptor) *store {
+		env.TestStoreProvider.Close()
+		newProvider, err := NewProvider()
+		require.NoError(t, err)
+		p := newProvider.(*provider)
+		p.encryptor = encryptor
+		s, err := p.OpenStore(env.ledgerid)
+		require.NoError(t, err)
+		s.Init(btlPolicy)
+		env.TestStoreProvider = p
+		env.TestStore = s
+		return s.(*store)
+	}
+	assertValues := func(s *store, keyID []byte) {
+		itr := s.db.GetIterator(pvtDataKeyPrefix, expiryKeyPrefix)
+		defer itr.Release()
+		numValues := 0
+		for itr.Next() {
+			numValues++
+			value := itr.Value()
+			if keyID == nil {
+				assert.False(t, atrest.IsEncrypted(value))
+				continue
+			}
+			assert.True(t, atrest.IsEncrypted(value))
+			assert.Equal(t, keyID, value[2:2+len(keyID)])
+		}
+		assert.Equal(t, 2, numValues)
+	}
+
+	testData := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}
+	assertRetrieval := func(s *store) {
+		retrievedData, err := s.GetPvtDataByBlockNum(1, nil)
+		require.NoError(t, err)
+		require.Len(t, retrievedData, len(testData))
+		for i, data := range retrievedData {
+			assert.Equal(t, testData[i].SeqInBlock, data.SeqInBlock)
+			assert.True(t, proto.Equal(testData[i].WriteSet, data.WriteSet))
+		}
+	}
+
+	// block 0 and 1 are written in plaintext
+	s := env.TestStore
+	assert.NoError(t, s.Prepare(0, nil, nil))
+	assert.NoError(t, s.Commit())
+	assert.NoError(t, s.Prepare(1, testData, nil))
+	assert.NoError(t, s.Commit())
+
+	// enabling the encryption migrates the existing data
+	encryptor1, err := atrest.NewEncryptor(csp, key1)
+	require.NoError(t, err)
+	encryptedStore := reopen(encryptor1)
+	assertValues(encryptedStore, key1.SKI())
+	assertRetrieval(encryptedStore)
+
+	// new data is encrypted as well
+	testData = append(testData, produceSamplePvtdata(t, 4, []string{"ns-1:coll-1", "ns-1:coll-2"}))
+	assert.NoError(t, encryptedStore.Prepare(2, testData[1:], nil))
+	assert.NoError(t, encryptedStore.Commit())
+	retrievedData, err := encryptedStore.GetPvtDataByBlockNum(2, nil)
+	require.NoError(t, err)
+	require.Len(t, retrievedData, 1)
+	assert.True(t, proto.Equal(testData[1].WriteSet, retrievedData[0].WriteSet))
+	testData = testData[:1]
+
+	// the encryption cannot be turned off while the data is encrypted
+	env.TestStoreProvider.Close()
+	p, err := NewProvider()
+	require.NoError(t, err)
+	_, err = p.OpenStore(env.ledgerid)
+	assert.EqualError(t, err, "pvtdata store for ledger [TestStoreEncryption] is encrypted but no at-rest encryption key is configured")
+	env.TestStoreProvider = p
+
+	// the key is rotated
+	encryptor2, err := atrest.NewEncryptor(csp, key2, key1)
+	require.NoError(t, err)
+	rotatedStore := reopen(encryptor2)
+	assertRetrieval(rotatedStore)
+	itr := rotatedStore.db.GetIterator(pvtDataKeyPrefix, expiryKeyPrefix)
+	for itr.Next() {
+		assert.False(t, encryptor2.NeedsReencryption(itr.Value()))
+	}
+	itr.Release()
+
+	// the data is decrypted before turning the encryption off
+	decryptor, err := atrest.NewEncryptor(csp, nil, key2)
+	require.NoError(t, err)
+	decryptedStore := reopen(decryptor)
+	assertRetrieval(decryptedStore)
+	plaintextStore := reopen(nil)
+	assertRetrieval(plaintextStore)
+	marker, err := plaintextStore.db.Get(encryptionKeyIDKey)
+	assert.NoError(t, err)
+	assert.Nil(t, marker)
+}
+
+func TestNewProviderEncryptionError(t *testing.T) {
+	viper.Set("ledger.encryption.enabled", true)
+	viper.Set("ledger.encryption.key", "not-hex")
+	defer viper.Set("ledger.encryption.enabled", false)
+	defer viper.Set("ledger.encryption.key", "")
+
+	_, err := NewProvider()
+	assert.EqualError(t, err, "failed initializing the encryption of the pvtdata store: invalid SKI [not-hex]: encoding/hex: invalid byte: U+006E 'n'")
+}
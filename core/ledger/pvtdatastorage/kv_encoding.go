@@ -27,6 +27,7 @@ var (
 	ineligibleMissingDataKeyPrefix = []byte{5}
 	collElgKeyPrefix               = []byte{6}
 	lastUpdatedOldBlocksKey        = []byte{7}
+	encryptionKeyIDKey             = []byte{8}
 
 	nilByte    = byte(0)
 	emptyValue = []byte{}
@@ -65,6 +65,11 @@ type Store interface {
 	// these pvtData, the `lastUpdatedOldBlocksList` must be removed. During the peer startup,
 	// if the `lastUpdatedOldBlocksList` exists, stateDB needs to be updated with the appropriate pvtData.
 	CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPvtData) error
+	// ImportMissingPvtData stores both eligible and ineligible missing private data of blocks already committed,
+	// as for `Prepare`. It is used when a ledger is created from a snapshot: the pvt data of the transactions of
+	// the snapshot, whose hashes are in the state, is missing, and is fetched by the reconciliation.
+	// It may be invoked several times for the same blocks, the missing private data of each call are added
+	ImportMissingPvtData(blocksMissingPvtData map[uint64]ledger.TxMissingPvtDataMap) error
 	// GetLastUpdatedOldBlocksPvtData returns the pvtdata of blocks listed in `lastUpdatedOldBlocksList`
 	GetLastUpdatedOldBlocksPvtData() (map[uint64][]*ledger.TxPvtData, error)
 	// ResetLastUpdatedOldBlocksList removes the `lastUpdatedOldBlocksList` entry from the store
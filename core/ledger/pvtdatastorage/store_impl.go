@@ -14,12 +14,15 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/pkg/errors"
 	"github.com/willf/bitset"
 )
 
@@ -27,12 +30,16 @@ var logger = flogging.MustGetLogger("pvtdatastorage")
 
 type provider struct {
 	dbProvider *leveldbhelper.Provider
+	encryptor  *atrest.Encryptor
 }
 
 type store struct {
 	db        *leveldbhelper.DBHandle
 	ledgerid  string
 	btlPolicy pvtdatapolicy.BTLPolicy
+	// encryptor encrypts the private data values at rest,
+	// nil if the encryption is not enabled
+	encryptor *atrest.Encryptor
 
 	isEmpty            bool
 	lastCommittedBlock uint64
@@ -106,16 +113,23 @@ type entriesForPvtDataOfOldBlocks struct {
 //////////////////////////////////////////
 
 // NewProvider instantiates a StoreProvider
-func NewProvider() Provider {
+func NewProvider() (Provider, error) {
+	var encryptor *atrest.Encryptor
+	if conf := ledgerconfig.GetAtRestEncryptionConfig(); conf != nil {
+		var err error
+		if encryptor, err = atrest.New(factory.GetDefault(), conf); err != nil {
+			return nil, errors.WithMessage(err, "failed initializing the encryption of the pvtdata store")
+		}
+	}
 	dbPath := ledgerconfig.GetPvtdataStorePath()
 	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
-	return &provider{dbProvider: dbProvider}
+	return &provider{dbProvider: dbProvider, encryptor: encryptor}, nil
 }
 
 // OpenStore returns a handle to a store
 func (p *provider) OpenStore(ledgerid string) (Store, error) {
 	dbHandle := p.dbProvider.GetDBHandle(ledgerid)
-	s := &store{db: dbHandle, ledgerid: ledgerid,
+	s := &store{db: dbHandle, ledgerid: ledgerid, encryptor: p.encryptor,
 		collElgProcSync: &collElgProcSync{
 			notification: make(chan bool, 1),
 			procComplete: make(chan bool, 1),
@@ -124,6 +138,9 @@ func (p *provider) OpenStore(ledgerid string) (Store, error) {
 	if err := s.initState(); err != nil {
 		return nil, err
 	}
+	if err := s.migrateEncryption(); err != nil {
+		return nil, err
+	}
 	s.launchCollElgProc()
 	logger.Debugf("Pvtdata store opened. Initial state: isEmpty [%t], lastCommittedBlock [%d], batchPending [%t]",
 		s.isEmpty, s.lastCommittedBlock, s.batchPending)
@@ -183,7 +200,7 @@ func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvt
 
 	for _, dataEntry := range storeEntries.dataEntries {
 		keyBytes = encodeDataKey(dataEntry.key)
-		if valBytes, err = encodeDataValue(dataEntry.value); err != nil {
+		if valBytes, err = s.encodeDataValue(dataEntry.value); err != nil {
 			return err
 		}
 		batch.Put(keyBytes, valBytes)
@@ -262,7 +279,7 @@ func (s *store) CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPv
 
 	// (3) create a db update batch from the update entries
 	logger.Debug("Constructing update batch from pvtdatastore entries")
-	batch, err := constructUpdateBatchFromUpdateEntries(updateEntries)
+	batch, err := s.constructUpdateBatchFromUpdateEntries(updateEntries)
 	if err != nil {
 		return err
 	}
@@ -402,7 +419,7 @@ func (updateEntries *entriesForPvtDataOfOldBlocks) updateAndAddMissingDataEntry(
 	updateEntries.missingDataEntries[nsCollBlk] = missingData
 }
 
-func constructUpdateBatchFromUpdateEntries(updateEntries *entriesForPvtDataOfOldBlocks) (*leveldbhelper.UpdateBatch, error) {
+func (s *store) constructUpdateBatchFromUpdateEntries(updateEntries *entriesForPvtDataOfOldBlocks) (*leveldbhelper.UpdateBatch, error) {
 	batch := leveldbhelper.NewUpdateBatch()
 
 	// add the following four types of entries to the update batch: (1) new data entries
@@ -410,7 +427,7 @@ func constructUpdateBatchFromUpdateEntries(updateEntries *entriesForPvtDataOfOld
 	// (4) updated block list
 
 	// (1) add new data entries to the batch
-	if err := addNewDataEntriesToUpdateBatch(batch, updateEntries); err != nil {
+	if err := s.addNewDataEntriesToUpdateBatch(batch, updateEntries); err != nil {
 		return nil, err
 	}
 
@@ -430,12 +447,12 @@ func constructUpdateBatchFromUpdateEntries(updateEntries *entriesForPvtDataOfOld
 	return batch, nil
 }
 
-func addNewDataEntriesToUpdateBatch(batch *leveldbhelper.UpdateBatch, entries *entriesForPvtDataOfOldBlocks) error {
+func (s *store) addNewDataEntriesToUpdateBatch(batch *leveldbhelper.UpdateBatch, entries *entriesForPvtDataOfOldBlocks) error {
 	var keyBytes, valBytes []byte
 	var err error
 	for dataKey, pvtData := range entries.dataEntries {
 		keyBytes = encodeDataKey(&dataKey)
-		if valBytes, err = encodeDataValue(pvtData); err != nil {
+		if valBytes, err = s.encodeDataValue(pvtData); err != nil {
 			return err
 		}
 		batch.Put(keyBytes, valBytes)
@@ -513,6 +530,65 @@ func (s *store) commitBatch(batch *leveldbhelper.UpdateBatch) error {
 	return nil
 }
 
+// ImportMissingPvtData implements the function in the interface `Store`
+func (s *store) ImportMissingPvtData(blocksMissingPvtData map[uint64]ledger.TxMissingPvtDataMap) error {
+	if s.batchPending {
+		return &ErrIllegalCall{"A pending batch exists. ImportMissingPvtData() function call is not allowed"}
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	for blkNum, missingPvtData := range blocksMissingPvtData {
+		if s.isEmpty || blkNum > s.lastCommittedBlock {
+			return &ErrIllegalArgs{fmt.Sprintf("Missing private data of block [%d] cannot be imported, the block is not committed", blkNum)}
+		}
+		// the entries stored by a previous call for the same blocks are extended
+		missingDataEntries := prepareMissingDataEntries(blkNum, missingPvtData)
+		for key, bitmap := range missingDataEntries {
+			existing, err := s.getBitmapOfMissingDataKey(&key)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				bitmap.InPlaceUnion(existing)
+			}
+			valBytes, err := encodeMissingDataValue(bitmap)
+			if err != nil {
+				return err
+			}
+			batch.Put(encodeMissingDataKey(&key), valBytes)
+		}
+
+		expiryEntries, err := prepareExpiryEntries(blkNum, nil, missingDataEntries, s.btlPolicy)
+		if err != nil {
+			return err
+		}
+		for _, expiryEntry := range expiryEntries {
+			expiryData, err := s.getExpiryDataOfExpiryKey(expiryEntry.key)
+			if err != nil {
+				return err
+			}
+			if expiryData == nil {
+				expiryData = expiryEntry.value
+			} else {
+				for ns, colls := range expiryEntry.value.Map {
+					for coll := range colls.MissingDataMap {
+						expiryData.addMissingData(ns, coll)
+					}
+				}
+			}
+			valBytes, err := encodeExpiryValue(expiryData)
+			if err != nil {
+				return err
+			}
+			batch.Put(encodeExpiryKey(expiryEntry.key), valBytes)
+		}
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Debugf("Imported missing private data of %d blocks", len(blocksMissingPvtData))
+	return nil
+}
+
 // GetLastUpdatedOldBlocksPvtData implements the function in the interface `Store`
 func (s *store) GetLastUpdatedOldBlocksPvtData() (map[uint64][]*ledger.TxPvtData, error) {
 	if !s.isLastUpdatedOldBlocksSet {
@@ -599,7 +675,7 @@ func (s *store) GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFil
 			return nil, err
 		}
 		if v11Fmt {
-			return v11RetrievePvtdata(itr, filter)
+			return v11RetrievePvtdata(itr, filter, s.encryptor)
 		}
 		dataValueBytes := itr.Value()
 		dataKey, err := decodeDatakey(dataKeyBytes)
@@ -613,7 +689,7 @@ func (s *store) GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFil
 		if expired || !passesFilter(dataKey, filter) {
 			continue
 		}
-		dataValue, err := decodeDataValue(dataValueBytes)
+		dataValue, err := s.decodeDataValue(dataValueBytes)
 		if err != nil {
 			return nil, err
 		}
@@ -670,6 +670,69 @@ func TestInitLastCommittedBlock(t *testing.T) {
 	assert.True(ok)
 }
 
+func TestImportMissingPvtData(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 10,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestImportMissingPvtData", btlPolicy)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+
+	blk2MissingData := make(ledger.TxMissingPvtDataMap)
+	blk2MissingData.Add(1, "ns-1", "coll-1", true)
+	blk3MissingData := make(ledger.TxMissingPvtDataMap)
+	blk3MissingData.Add(0, "ns-1", "coll-2", true)
+	blocksMissingData := map[uint64]ledger.TxMissingPvtDataMap{2: blk2MissingData, 3: blk3MissingData}
+
+	// importing into an empty store fails
+	err := s.ImportMissingPvtData(blocksMissingData)
+	_, ok := err.(*ErrIllegalArgs)
+	assert.True(ok)
+
+	assert.NoError(s.InitLastCommittedBlock(5))
+	assert.NoError(s.ImportMissingPvtData(blocksMissingData))
+
+	// a second import for the same block extends the existing entries
+	blk2MissingData = make(ledger.TxMissingPvtDataMap)
+	blk2MissingData.Add(4, "ns-1", "coll-1", true)
+	assert.NoError(s.ImportMissingPvtData(map[uint64]ledger.TxMissingPvtDataMap{2: blk2MissingData}))
+
+	// importing for a block beyond the last committed block fails
+	blk6MissingData := make(ledger.TxMissingPvtDataMap)
+	blk6MissingData.Add(0, "ns-1", "coll-1", true)
+	err = s.ImportMissingPvtData(map[uint64]ledger.TxMissingPvtDataMap{6: blk6MissingData})
+	_, ok = err.(*ErrIllegalArgs)
+	assert.True(ok)
+
+	expectedMissingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	expectedMissingPvtDataInfo.Add(2, 1, "ns-1", "coll-1")
+	expectedMissingPvtDataInfo.Add(2, 4, "ns-1", "coll-1")
+	expectedMissingPvtDataInfo.Add(3, 0, "ns-1", "coll-2")
+	missingPvtDataInfo, err := s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Equal(expectedMissingPvtDataInfo, missingPvtDataInfo)
+
+	// the missing data of a collection with a non-zero btl gets an expiry entry
+	expiryData, err := s.(*store).getExpiryDataOfExpiryKey(&expiryKey{expiringBlk: 14, committingBlk: 3})
+	assert.NoError(err)
+	assert.NotNil(expiryData)
+	expiryData, err = s.(*store).getExpiryDataOfExpiryKey(&expiryKey{expiringBlk: 3, committingBlk: 2})
+	assert.NoError(err)
+	assert.Nil(expiryData)
+
+	// the imported missing data can be committed later on
+	oldBlocksPvtData := map[uint64][]*ledger.TxPvtData{
+		3: {produceSamplePvtdata(t, 0, []string{"ns-1:coll-2"})},
+	}
+	assert.NoError(s.CommitPvtDataOfOldBlocks(oldBlocksPvtData))
+	assert.True(testDataKeyExists(t, s, &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 3}, txNum: 0}))
+	assert.False(testMissingDataKeyExists(t, s, &missingDataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 3}, isEligible: true}))
+}
+
 func TestCollElgEnabled(t *testing.T) {
 	testCollElgEnabled(t)
 	defaultValBatchSize := ledgerconfig.GetPvtdataStoreCollElgProcMaxDbBatchSize()
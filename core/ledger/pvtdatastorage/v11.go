@@ -8,6 +8,7 @@ package pvtdatastorage
 
 import (
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -32,20 +33,24 @@ func v11DecodePK(key blkTranNumKey) (uint64, uint64, error) {
 	return height.BlockNum, height.TxNum, nil
 }
 
-func v11DecodePvtRwSet(encodedBytes []byte) (*rwset.TxPvtReadWriteSet, error) {
+func v11DecodePvtRwSet(encodedBytes []byte, encryptor *atrest.Encryptor) (*rwset.TxPvtReadWriteSet, error) {
+	encodedBytes, err := encryptor.Decrypt(encodedBytes)
+	if err != nil {
+		return nil, err
+	}
 	writeset := &rwset.TxPvtReadWriteSet{}
 	return writeset, proto.Unmarshal(encodedBytes, writeset)
 }
 
-func v11RetrievePvtdata(itr *leveldbhelper.Iterator, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
+func v11RetrievePvtdata(itr *leveldbhelper.Iterator, filter ledger.PvtNsCollFilter, encryptor *atrest.Encryptor) ([]*ledger.TxPvtData, error) {
 	var blkPvtData []*ledger.TxPvtData
-	txPvtData, err := v11DecodeKV(itr.Key(), itr.Value(), filter)
+	txPvtData, err := v11DecodeKV(itr.Key(), itr.Value(), filter, encryptor)
 	if err != nil {
 		return nil, err
 	}
 	blkPvtData = append(blkPvtData, txPvtData)
 	for itr.Next() {
-		pvtDatum, err := v11DecodeKV(itr.Key(), itr.Value(), filter)
+		pvtDatum, err := v11DecodeKV(itr.Key(), itr.Value(), filter, encryptor)
 		if err != nil {
 			return nil, err
 		}
@@ -54,13 +59,13 @@ func v11RetrievePvtdata(itr *leveldbhelper.Iterator, filter ledger.PvtNsCollFilt
 	return blkPvtData, nil
 }
 
-func v11DecodeKV(k, v []byte, filter ledger.PvtNsCollFilter) (*ledger.TxPvtData, error) {
+func v11DecodeKV(k, v []byte, filter ledger.PvtNsCollFilter, encryptor *atrest.Encryptor) (*ledger.TxPvtData, error) {
 	bNum, tNum, err := v11DecodePK(k)
 	if err != nil {
 		return nil, err
 	}
 	var pvtWSet *rwset.TxPvtReadWriteSet
-	if pvtWSet, err = v11DecodePvtRwSet(v); err != nil {
+	if pvtWSet, err = v11DecodePvtRwSet(v, encryptor); err != nil {
 		return nil, err
 	}
 	logger.Debugf("Retrieved V11 private data write set for block [%d] tran [%d]", bNum, tNum)
@@ -11,10 +11,14 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/gm"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestV11v12 test that we are able to read the mixed format data (for v11 and v12)
@@ -37,7 +41,8 @@ func TestV11v12(t *testing.T) {
 			{"marbles_private", "collectionMarblePrivateDetails"}: 0,
 		},
 	)
-	p := NewProvider()
+	p, err := NewProvider()
+	assert.NoError(t, err)
 	defer p.Close()
 	s, err := p.OpenStore(ledgerid)
 	assert.NoError(t, err)
@@ -57,6 +62,56 @@ func TestV11v12(t *testing.T) {
 	assert.True(t, ok)
 }
 
+// TestV11v12Encryption tests that enabling the encryption migrates the private data
+// written in the v11 format along with the one written in the v12 format
+func TestV11v12Encryption(t *testing.T) {
+	testWorkingDir := "test-working-dir"
+	testutil.CopyDir("testdata/v11_v12/ledgersData", testWorkingDir)
+	defer os.RemoveAll(testWorkingDir)
+
+	viper.Set("peer.fileSystemPath", testWorkingDir)
+	defer viper.Reset()
+
+	csp, err := gm.NewDefaultSecurityLevelWithKeystore(gm.NewDummyKeyStore())
+	require.NoError(t, err)
+	key, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	encryptor, err := atrest.NewEncryptor(csp, key)
+	require.NoError(t, err)
+
+	ledgerid := "ch1"
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"marbles_private", "collectionMarbles"}:              0,
+			{"marbles_private", "collectionMarblePrivateDetails"}: 0,
+		},
+	)
+	newProvider, err := NewProvider()
+	require.NoError(t, err)
+	p := newProvider.(*provider)
+	p.encryptor = encryptor
+	defer p.Close()
+	s, err := p.OpenStore(ledgerid)
+	require.NoError(t, err)
+	s.Init(btlPolicy)
+
+	itr := s.(*store).db.GetIterator(pvtDataKeyPrefix, expiryKeyPrefix)
+	numV11Values := 0
+	for itr.Next() {
+		v11Fmt, err := v11Format(itr.Key())
+		require.NoError(t, err)
+		if v11Fmt {
+			numV11Values++
+		}
+		assert.False(t, encryptor.NeedsReencryption(itr.Value()))
+	}
+	itr.Release()
+	assert.Equal(t, 1, numV11Values)
+
+	checkDataExists(t, s, 10)
+	checkDataExists(t, s, 14)
+}
+
 func checkDataNotExists(t *testing.T, s Store, blkNum int) {
 	data, err := s.GetPvtDataByBlockNum(uint64(blkNum), nil)
 	assert.NoError(t, err)
@@ -80,6 +80,8 @@ func ResetConfigToDefaultValues() {
 	viper.Set("ledger.history.enableHistoryDatabase", false)
 	viper.Set("ledger.state.couchDBConfig.autoWarmIndexes", true)
 	viper.Set("ledger.state.couchDBConfig.warmIndexesAfterNBlocks", 1)
+	viper.Set("ledger.encryption.enabled", false)
+	viper.Set("ledger.snapshots.rootDir", "")
 	viper.Set("peer.fileSystemPath", "/var/hyperledger/production")
 }
 
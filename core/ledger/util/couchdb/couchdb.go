@@ -451,6 +451,41 @@ func (couchInstance *CouchInstance) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// RetrieveApplicationDBNames returns the names of the databases of the couch instance,
+// except the system databases whose names start with an underscore
+func (couchInstance *CouchInstance) RetrieveApplicationDBNames() ([]string, error) {
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err)
+		return nil, errors.Wrapf(err, "error parsing CouchDB URL: %s", couchInstance.conf.URL)
+	}
+	connectURL.Path = "/_all_dbs"
+
+	//get the number of retries
+	maxRetries := couchInstance.conf.MaxRetries
+
+	resp, _, err := couchInstance.handleRequest(context.Background(), http.MethodGet, "", "RetrieveApplicationDBNames", connectURL, nil,
+		couchInstance.conf.Username, couchInstance.conf.Password, maxRetries, true, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	var dbNames []string
+	decodeErr := json.NewDecoder(resp.Body).Decode(&dbNames)
+	if decodeErr != nil {
+		return nil, errors.Wrap(decodeErr, "error decoding response body")
+	}
+
+	var applicationDBNames []string
+	for _, dbName := range dbNames {
+		if !strings.HasPrefix(dbName, "_") {
+			applicationDBNames = append(applicationDBNames, dbName)
+		}
+	}
+	return applicationDBNames, nil
+}
+
 //DropDatabase provides method to drop an existing database
 func (dbclient *CouchDatabase) DropDatabase() (*DBOperationResponse, error) {
 	dbName := dbclient.DBName
@@ -204,6 +204,10 @@ func TestBadCouchDBInstance(t *testing.T) {
 	_, err = badDB.EnsureFullCommit()
 	assert.Error(t, err, "Error should have been thrown with EnsureFullCommit and invalid connection")
 
+	//Test RetrieveApplicationDBNames with bad connection
+	_, err = badCouchDBInstance.RetrieveApplicationDBNames()
+	assert.Error(t, err, "Error should have been thrown with RetrieveApplicationDBNames and invalid connection")
+
 	//Test DropDatabase with bad connection
 	_, err = badDB.DropDatabase()
 	assert.Error(t, err, "Error should have been thrown with DropDatabase and invalid connection")
@@ -299,6 +303,24 @@ func TestDBCreateEnsureFullCommit(t *testing.T) {
 	assert.NoError(t, commiterr, "Error when trying to ensure a full commit")
 }
 
+func TestRetrieveApplicationDBNames(t *testing.T) {
+	database := "testretrieveapplicationdbnames"
+	err := cleanup(database)
+	assert.NoError(t, err, "Error when trying to cleanup  Error: %s", err)
+	defer cleanup(database)
+
+	couchInstance, err := CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB, &disabled.Provider{})
+	assert.NoError(t, err, "Error when trying to create couch instance")
+	_, err = CreateCouchDatabase(couchInstance, database)
+	assert.NoError(t, err, "Error when trying to create database")
+
+	dbNames, err := couchInstance.RetrieveApplicationDBNames()
+	assert.NoError(t, err, "Error when trying to retrieve the database names")
+	assert.Contains(t, dbNames, database)
+	assert.NotContains(t, dbNames, "_users")
+}
+
 func TestDBBadDatabaseName(t *testing.T) {
 
 	//create a new instance and database object using a valid database name mixed case
@@ -245,6 +245,33 @@ func ConstructNamespaceDBName(chainName, namespace string) string {
 	return namespaceDBName
 }
 
+// SelectLedgerDBNames returns the names, among dbNames, of the metadataDB and of the namespaceDBs
+// of a chain/channel. Their names start with the chainName, where each '.' is mapped to '$', followed
+// by '_', which a chainName never contains. A truncated namespaceDBName only starts with the first
+// 50 chars (i.e., chainNameAllowedLength) of the chainName though, which cannot be told apart from
+// the namespaceDBNames of the other chains starting with the same chars, so an error is returned
+func SelectLedgerDBNames(chainName string, dbNames []string) ([]string, error) {
+	mappedChainName := strings.Replace(chainName, ".", "$", -1)
+	metadataDBName := strings.Replace(ConstructMetadataDBName(chainName), ".", "$", -1)
+	prefix := mappedChainName + "_"
+	truncatedPrefix := ""
+	if len(mappedChainName) > chainNameAllowedLength {
+		truncatedPrefix = mappedChainName[:chainNameAllowedLength] + "_"
+	}
+
+	var ledgerDBNames []string
+	for _, dbName := range dbNames {
+		switch {
+		case dbName == metadataDBName, strings.HasPrefix(dbName, prefix):
+			ledgerDBNames = append(ledgerDBNames, dbName)
+		case truncatedPrefix != "" && strings.HasPrefix(dbName, truncatedPrefix):
+			return nil, errors.Errorf("database [%s] may belong to chain [%s] or to another chain starting with [%s]",
+				dbName, chainName, chainName[:chainNameAllowedLength])
+		}
+	}
+	return ledgerDBNames, nil
+}
+
 //mapAndValidateDatabaseName checks to see if the database name contains illegal characters
 //CouchDB Rules: Only lowercase characters (a-z), digits (0-9), and any of the characters
 //_, $, (, ), +, -, and / are allowed. Must begin with a letter.
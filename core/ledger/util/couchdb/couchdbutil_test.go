@@ -7,6 +7,7 @@ package couchdb
 
 import (
 	"encoding/hex"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/metrics/disabled"
@@ -188,3 +189,26 @@ func TestConstructedNamespaceDBName(t *testing.T) {
 	assert.Equal(t, expectedDBNameLength, len(constructedDBName))
 	assert.Equal(t, expectedDBName, constructedDBName)
 }
+
+func TestSelectLedgerDBNames(t *testing.T) {
+	dbNames := []string{
+		"ch1$a_", "ch1$a_lscc", "ch1$a_mycc$$pcoll", "ch1$a2_", "ch1$a2_lscc", "ch1_", "ch1_lscc", "other",
+	}
+	ledgerDBNames, err := SelectLedgerDBNames("ch1.a", dbNames)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ch1$a_", "ch1$a_lscc", "ch1$a_mycc$$pcoll"}, ledgerDBNames)
+
+	// The names of the databases of a chain longer than 50 chars (i.e., chainNameAllowedLength) are
+	// selected as long as none of its namespaceDBNames is truncated
+	chainName := "tob2g.y-z0f.qwp-rq5g4-ogid5g6oucyryg9sc16mz0t4vuake5q557esz7sn493nf0ghch0xih6dwuirokyoi4jvs67gh6r5v6mhz3-292un2-9egdcs88cstg3f7xa9m1i8v4gj0t3jedsm-woh3kgiqehwej6h93hdy5tr4v.1qmmqjzz0ox62k.507sh3fkw3-mfqh.ukfvxlm5szfbwtpfkd1r4j.cy8oft5obvwqpzjxb27xuw6"
+	metadataDBName := strings.Replace(ConstructMetadataDBName(chainName), ".", "$", -1)
+	ledgerDBNames, err = SelectLedgerDBNames(chainName, []string{metadataDBName, "ch1_", "ch1_lscc"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{metadataDBName}, ledgerDBNames)
+
+	namespaceDBName := strings.Replace(ConstructNamespaceDBName(chainName, "lscc"), ".", "$", -1)
+	ledgerDBNames, err = SelectLedgerDBNames(chainName, []string{metadataDBName, namespaceDBName})
+	assert.EqualError(t, err, "database ["+namespaceDBName+"] may belong to chain ["+chainName+
+		"] or to another chain starting with [tob2g.y-z0f.qwp-rq5g4-ogid5g6oucyryg9sc16mz0t4vuak]")
+	assert.Nil(t, ledgerDBNames)
+}
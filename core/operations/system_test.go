@@ -104,6 +104,25 @@ var _ = Describe("System", func() {
 		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 	})
 
+	It("hosts secure endpoints for registered handlers", func() {
+		err := system.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		system.RegisterHandler("/registered/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}))
+
+		registeredURL := fmt.Sprintf("https://%s/registered/path", system.Addr())
+		resp, err := client.Get(registeredURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusTeapot))
+		resp.Body.Close()
+
+		resp, err = unauthClient.Get(registeredURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
 	Context("when TLS is disabled", func() {
 		BeforeEach(func() {
 			options.TLS.Enabled = false
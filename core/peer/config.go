@@ -145,6 +145,25 @@ func GetServerConfig() (comm.ServerConfig, error) {
 		}
 		secureOptions.Certificate = serverCert
 		secureOptions.Key = serverKey
+		// GM TLS servers may use a separate key pair for the key exchange
+		encKeyPath := config.GetPath("peer.tls.encKey.file")
+		encCertPath := config.GetPath("peer.tls.encCert.file")
+		if encKeyPath != "" || encCertPath != "" {
+			if encKeyPath == "" || encCertPath == "" {
+				return serverConfig, errors.New("peer.tls.encKey.file and " +
+					"peer.tls.encCert.file must both be set or must both be empty")
+			}
+			encKey, err := ioutil.ReadFile(encKeyPath)
+			if err != nil {
+				return serverConfig, fmt.Errorf("error loading TLS encryption key (%s)", err)
+			}
+			encCert, err := ioutil.ReadFile(encCertPath)
+			if err != nil {
+				return serverConfig, fmt.Errorf("error loading TLS encryption certificate (%s)", err)
+			}
+			secureOptions.EncCertificate = encCert
+			secureOptions.EncKey = encKey
+		}
 		secureOptions.RequireClientCert = viper.GetBool("peer.tls.clientAuthRequired")
 		if secureOptions.RequireClientCert {
 			var clientRoots [][]byte
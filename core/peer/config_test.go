@@ -166,9 +166,26 @@ func TestGetServerConfig(t *testing.T) {
 	assert.Equal(t, 2, len(sc.SecOpts.ClientRootCAs),
 		"ServerConfig.SecOpts.ClientRootCAs should contain 2 entries")
 
+	// GM TLS encryption key pair
+	assert.Nil(t, sc.SecOpts.EncCertificate)
+	assert.Nil(t, sc.SecOpts.EncKey)
+	viper.Set("peer.tls.encCert.file", filepath.Join("testdata", "Org2-server1-cert.pem"))
+	_, err := GetServerConfig()
+	assert.EqualError(t, err, "peer.tls.encKey.file and peer.tls.encCert.file must both be set or must both be empty")
+	viper.Set("peer.tls.encKey.file", filepath.Join("testdata", "Org2-server1-key.pem"))
+	sc, err = GetServerConfig()
+	assert.NoError(t, err)
+	assert.NotNil(t, sc.SecOpts.EncCertificate)
+	assert.NotNil(t, sc.SecOpts.EncKey)
+	viper.Set("peer.tls.encKey.file", filepath.Join("testdata", "Org11-server1-key.pem"))
+	_, err = GetServerConfig()
+	assert.Error(t, err, "GetServerConfig should return error with bad tls encryption key path")
+	viper.Set("peer.tls.encCert.file", "")
+	viper.Set("peer.tls.encKey.file", "")
+
 	// bad config with TLS
 	viper.Set("peer.tls.rootcert.file", filepath.Join("testdata", "Org11-cert.pem"))
-	_, err := GetServerConfig()
+	_, err = GetServerConfig()
 	assert.Error(t, err, "GetServerConfig should return error with bad root cert path")
 	viper.Set("peer.tls.cert.file", filepath.Join("testdata", "Org11-cert.pem"))
 	_, err = GetServerConfig()
@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
@@ -128,7 +129,11 @@ func (sp *storeProvider) OpenStore(ledgerID string) (transientstore.Store, error
 	sp.Lock()
 	defer sp.Unlock()
 	if sp.StoreProvider == nil {
-		sp.StoreProvider = transientstore.NewStoreProvider()
+		provider, err := transientstore.NewStoreProvider()
+		if err != nil {
+			return nil, err
+		}
+		sp.StoreProvider = provider
 	}
 	store, err := sp.StoreProvider.OpenStore(ledgerID)
 	if err == nil {
@@ -403,6 +408,10 @@ func createChain(
 		mspmgmt.XXXSetMSPManager(cid, bundle.MSPManager())
 	}
 
+	hashingAlgorithmCallback := func(bundle *channelconfig.Bundle) {
+		validation.SetBlockHashingAlgorithm(cid, bundle.ChannelConfig().BlockHashingAlgorithm())
+	}
+
 	ac, ok := bundle.ApplicationConfig()
 	if !ok {
 		ac = nil
@@ -429,6 +438,7 @@ func createChain(
 		gossipCallbackWrapper,
 		trustedRootsCallbackWrapper,
 		mspCallback,
+		hashingAlgorithmCallback,
 		peerSingletonCallback,
 		cp.updateChannelConfig,
 	)
@@ -523,6 +533,21 @@ func CreateChainFromBlock(cb *common.Block, ccp ccprovider.ChaincodeProvider, sc
 	return createChain(cid, l, cb, ccp, sccp, pluginMapper)
 }
 
+// CreateChainFromSnapshot creates a new chain from the snapshot in the given directory,
+// and returns the chain ID
+func CreateChainFromSnapshot(snapshotDir string, ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider) (string, error) {
+	l, cid, err := ledgermgmt.CreateLedgerFromSnapshot(snapshotDir)
+	if err != nil {
+		return "", errors.WithMessage(err, "cannot create ledger from snapshot")
+	}
+
+	cb, err := getCurrConfigBlockFromLedger(l)
+	if err != nil {
+		return "", errors.WithMessage(err, "cannot retrieve the config block of the ledger created from snapshot")
+	}
+	return cid, createChain(cid, l, cb, ccp, sccp, pluginMapper)
+}
+
 // GetLedger returns the ledger of the chain with chain ID. Note that this
 // call returns nil if chain cid has not been created.
 func GetLedger(cid string) ledger.PeerLedger {
@@ -838,6 +863,10 @@ func (flbs fileLedgerBlockStore) RetrieveBlocks(startBlockNumber uint64) (common
 	return flbs.GetBlocksIterator(startBlockNumber)
 }
 
+// Shutdown does nothing, as the ledger is closed by the peer
+func (flbs fileLedgerBlockStore) Shutdown() {
+}
+
 // NewConfigSupport returns
 func NewConfigSupport() cc.Manager {
 	return &configSupport{}
@@ -13,6 +13,7 @@ package cscc
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
@@ -77,6 +78,9 @@ const (
 	GetChannels              string = "GetChannels"
 	GetConfigTree            string = "GetConfigTree"
 	SimulateConfigTreeUpdate string = "SimulateConfigTreeUpdate"
+	JoinChainBySnapshot      string = "JoinChainBySnapshot"
+	SubmitSnapshotRequest    string = "SubmitSnapshotRequest"
+	PendingSnapshotRequests  string = "PendingSnapshotRequests"
 )
 
 // Init is mostly useless from an SCC perspective
@@ -162,6 +166,38 @@ func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Res
 		}
 
 		return joinChain(cid, block, e.ccp, e.sccp)
+	case JoinChainBySnapshot:
+		if len(args[1]) == 0 {
+			return shim.Error("Cannot join the channel, no snapshot path provided")
+		}
+
+		// 2. check local MSP Admins policy
+		// TODO: move to ACLProvider once it will support chainless ACLs
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, args[1], err))
+		}
+
+		return joinChainBySnapshot(string(args[1]), e.ccp, e.sccp)
+	case SubmitSnapshotRequest:
+		if len(args) < 3 {
+			return shim.Error(fmt.Sprintf("Incorrect number of arguments, %d", len(args)))
+		}
+
+		// 2. check local MSP Admins policy
+		// TODO: move to ACLProvider once it will support chainless ACLs
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, args[1], err))
+		}
+
+		return submitSnapshotRequest(args[1], args[2])
+	case PendingSnapshotRequests:
+		// 2. check local MSP Admins policy
+		// TODO: move to ACLProvider once it will support chainless ACLs
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, args[1], err))
+		}
+
+		return pendingSnapshotRequests(args[1])
 	case GetConfigBlock:
 		// 2. check policy
 		if err = e.aclProvider.CheckACL(resources.Cscc_GetConfigBlock, string(args[1]), sp); err != nil {
@@ -242,6 +278,63 @@ func joinChain(chainID string, block *common.Block, ccp ccprovider.ChaincodeProv
 	return shim.Success(nil)
 }
 
+// joinChainBySnapshot will join the chain whose ledger is created from the
+// snapshot found at the specified path on the peer
+func joinChainBySnapshot(snapshotDir string, ccp ccprovider.ChaincodeProvider, sccp sysccprovider.SystemChaincodeProvider) pb.Response {
+	chainID, err := peer.CreateChainFromSnapshot(snapshotDir, ccp, sccp)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	peer.InitChain(chainID)
+
+	return shim.Success(nil)
+}
+
+// submitSnapshotRequest submits a request to generate a snapshot of the ledger
+// of the specified chainID at the specified block number. A block number of
+// zero requests a snapshot at the last committed block
+func submitSnapshotRequest(chainID []byte, blockNumber []byte) pb.Response {
+	if chainID == nil {
+		return shim.Error("ChainID must not be nil.")
+	}
+	blockNum, err := strconv.ParseUint(string(blockNumber), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Invalid block number [%s]: %s", blockNumber, err))
+	}
+	lgr := peer.GetLedger(string(chainID))
+	if lgr == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", string(chainID)))
+	}
+	if err := lgr.SubmitSnapshotRequest(blockNum); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
+// pendingSnapshotRequests returns the block numbers of the pending requests to
+// generate snapshots of the ledger of the specified chainID
+func pendingSnapshotRequests(chainID []byte) pb.Response {
+	if chainID == nil {
+		return shim.Error("ChainID must not be nil.")
+	}
+	lgr := peer.GetLedger(string(chainID))
+	if lgr == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", string(chainID)))
+	}
+	blockNums, err := lgr.PendingSnapshotRequests()
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	psrBytes, err := proto.Marshal(&pb.PendingSnapshotRequests{BlockNumbers: blockNums})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(psrBytes)
+}
+
 // Return the current configuration block for the specified chainID. If the
 // peer doesn't belong to the chain, return error
 func getConfigBlock(chainID []byte) pb.Response {
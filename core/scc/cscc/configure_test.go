@@ -369,6 +369,51 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	if len(cqr.GetChannels()) != 1 {
 		t.FailNow()
 	}
+
+	// request snapshots of the ledger of the channel
+	args = [][]byte{[]byte(SubmitSnapshotRequest), []byte(chainID), []byte("10")}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "duplicate snapshot request for block number 10")
+	args = [][]byte{[]byte(SubmitSnapshotRequest), []byte(chainID), []byte("notanumber")}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Invalid block number [notanumber]")
+	args = [][]byte{[]byte(SubmitSnapshotRequest), []byte("unknownchainid"), []byte("10")}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Unknown chain ID, unknownchainid", res.Message)
+
+	args = [][]byte{[]byte(PendingSnapshotRequests), []byte(chainID)}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	psr := &pb.PendingSnapshotRequests{}
+	err = proto.Unmarshal(res.Payload, psr)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{10}, psr.BlockNumbers)
+
+	// joining by a snapshot requires a path to the snapshot
+	args = [][]byte{[]byte(JoinChainBySnapshot), nil}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Cannot join the channel, no snapshot path provided", res.Message)
+	args = [][]byte{[]byte(JoinChainBySnapshot), []byte("/tmp/hyperledgertest/nonexistent")}
+	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+
+	// These calls must fail for non admins
+	sProp.Signature = nil
+	for _, args := range [][][]byte{
+		{[]byte(JoinChainBySnapshot), []byte("/tmp/hyperledgertest/nonexistent")},
+		{[]byte(SubmitSnapshotRequest), []byte(chainID), []byte("10")},
+		{[]byte(PendingSnapshotRequests), []byte(chainID)},
+	} {
+		res = stub.MockInvokeWithSignedProposal("3", args, sProp)
+		assert.Equal(t, int32(shim.ERROR), res.Status)
+		assert.Contains(t, res.Message, fmt.Sprintf("access denied for [%s]", args[0]))
+	}
 }
 
 func TestGetConfigTree(t *testing.T) {
@@ -14,7 +14,9 @@ import (
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -40,6 +42,8 @@ func (e *LedgerQuerier) Enabled() bool             { return true }
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetStateAtHeight returns the value of a key at a block height
+// - GetStateRangeAtHeight returns the values of a range of keys at a block height
 type LedgerQuerier struct {
 	aclProvider aclmgmt.ACLProvider
 }
@@ -53,6 +57,9 @@ const (
 	GetBlockByHash     string = "GetBlockByHash"
 	GetTransactionByID string = "GetTransactionByID"
 	GetBlockByTxID     string = "GetBlockByTxID"
+
+	GetStateAtHeight      string = "GetStateAtHeight"
+	GetStateRangeAtHeight string = "GetStateRangeAtHeight"
 )
 
 // Init is called once per chain when the chain is created.
@@ -71,6 +78,13 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetStateAtHeight: Return the value of the key in args[3] of the namespace
+// in args[2] as of the block number in args[4]
+// # GetStateRangeAtHeight: Return a QueryResponse holding the KVs of the keys
+// in the range [args[3], args[4]) of the namespace in args[2] as of the block
+// number in args[5]. When the range holds more KVs than the total query limit,
+// the response has more results, and the bookmark of its metadata is the
+// start key of the range holding the next KVs
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -115,6 +129,16 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
 		return getBlockByTxID(targetLedger, args[2])
+	case GetStateAtHeight:
+		if len(args) < 5 {
+			return shim.Error(fmt.Sprintf("missing arguments for %s", fname))
+		}
+		return getStateAtHeight(targetLedger, args[2], args[3], args[4])
+	case GetStateRangeAtHeight:
+		if len(args) < 6 {
+			return shim.Error(fmt.Sprintf("missing arguments for %s", fname))
+		}
+		return getStateRangeAtHeight(targetLedger, args[2], args[3], args[4], args[5])
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
@@ -214,6 +238,78 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+func getStateAtHeight(vledger ledger.PeerLedger, namespace, key, number []byte) pb.Response {
+	bnum, err := strconv.ParseUint(string(number), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse block number with error %s", err))
+	}
+	hqe, err := vledger.NewHistoryQueryExecutor()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history query executor with error %s", err))
+	}
+	value, err := hqe.GetStateAtHeight(string(namespace), string(key), bnum)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get state of key %s at block number %d, error %s", string(key), bnum, err))
+	}
+
+	return shim.Success(value)
+}
+
+func getStateRangeAtHeight(vledger ledger.PeerLedger, namespace, startKey, endKey, number []byte) pb.Response {
+	bnum, err := strconv.ParseUint(string(number), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse block number with error %s", err))
+	}
+	hqe, err := vledger.NewHistoryQueryExecutor()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get history query executor with error %s", err))
+	}
+	itr, err := hqe.GetStateRangeAtHeight(string(namespace), string(startKey), string(endKey), bnum)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get state of range [%s, %s) at block number %d, error %s",
+			string(startKey), string(endKey), bnum, err))
+	}
+	defer itr.Close()
+
+	// the number of results is bounded as for the range queries of the chaincodes,
+	// the key of the first KV left out being the bookmark of the next range
+	queryResponse := &pb.QueryResponse{}
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get state of range [%s, %s) at block number %d, error %s",
+				string(startKey), string(endKey), bnum, err))
+		}
+		if res == nil {
+			break
+		}
+		kv := res.(*queryresult.KV)
+		if len(queryResponse.Results) >= ledgerconfig.GetTotalQueryLimit() {
+			queryResponse.HasMore = true
+			queryResponse.Metadata, err = utils.Marshal(&pb.QueryResponseMetadata{
+				FetchedRecordsCount: int32(len(queryResponse.Results)),
+				Bookmark:            kv.Key,
+			})
+			if err != nil {
+				return shim.Error(err.Error())
+			}
+			break
+		}
+		kvBytes, err := utils.Marshal(kv)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		queryResponse.Results = append(queryResponse.Results, &pb.QueryResultBytes{ResultBytes: kvBytes})
+	}
+
+	bytes, err := utils.Marshal(queryResponse)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
 func getACLResource(fname string) string {
 	return "qscc/" + fname
 }
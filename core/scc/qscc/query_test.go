@@ -21,6 +21,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
@@ -29,6 +30,7 @@ import (
 	ledger2 "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	peer2 "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -328,6 +330,87 @@ func TestQueryGeneratedBlock(t *testing.T) {
 	}
 }
 
+func TestQueryStateAtHeight(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := tempDir(t, "test9")
+	defer os.RemoveAll(path)
+
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	defer viper.Set("ledger.history.enableHistoryDatabase", false)
+	stub, err := setupTestLedger(chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	addBlockForTesting(t, chainid)
+
+	// the key is set in block 1
+	args := [][]byte{[]byte(GetStateAtHeight), []byte(chainid), []byte("ns1"), []byte("key1"), []byte("1")}
+	prop := resetProvider(resources.Qscc_GetStateAtHeight, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetStateAtHeight should have succeeded: %s", res.Message)
+	assert.Equal(t, []byte("value1"), res.Payload)
+
+	args = [][]byte{[]byte(GetStateAtHeight), []byte(chainid), []byte("ns1"), []byte("key1"), []byte("0")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetStateAtHeight should have succeeded: %s", res.Message)
+	assert.Nil(t, res.Payload)
+
+	args = [][]byte{[]byte(GetStateAtHeight), []byte(chainid), []byte("ns1"), []byte("key1"), []byte("2")}
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetStateAtHeight should have failed for a block beyond the height")
+
+	args = [][]byte{[]byte(GetStateAtHeight), []byte(chainid), []byte("ns1"), []byte("key1"), []byte("abc")}
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetStateAtHeight should have failed for an invalid block number")
+
+	args = [][]byte{[]byte(GetStateAtHeight), []byte(chainid), []byte("ns1"), []byte("key1")}
+	res = stub.MockInvokeWithSignedProposal("5", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetStateAtHeight should have failed with missing arguments")
+
+	args = [][]byte{[]byte(GetStateRangeAtHeight), []byte(chainid), []byte("ns1"), []byte("key2"), []byte(""), []byte("1")}
+	prop = resetProvider(resources.Qscc_GetStateRangeAtHeight, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("6", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetStateRangeAtHeight should have succeeded: %s", res.Message)
+	queryResponse := &peer2.QueryResponse{}
+	require.NoError(t, proto.Unmarshal(res.Payload, queryResponse))
+	var values []string
+	for _, result := range queryResponse.Results {
+		kv := &queryresult.KV{}
+		require.NoError(t, proto.Unmarshal(result.ResultBytes, kv))
+		values = append(values, string(kv.Value))
+	}
+	assert.Equal(t, []string{"value2", "value3"}, values)
+	assert.False(t, queryResponse.HasMore)
+
+	// the results beyond the total query limit are returned by the range starting with the bookmark
+	viper.Set("ledger.state.totalQueryLimit", 1)
+	defer viper.Set("ledger.state.totalQueryLimit", 10000)
+	res = stub.MockInvokeWithSignedProposal("7", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetStateRangeAtHeight should have succeeded: %s", res.Message)
+	queryResponse = &peer2.QueryResponse{}
+	require.NoError(t, proto.Unmarshal(res.Payload, queryResponse))
+	assert.Len(t, queryResponse.Results, 1)
+	assert.True(t, queryResponse.HasMore)
+	metadata := &peer2.QueryResponseMetadata{}
+	require.NoError(t, proto.Unmarshal(queryResponse.Metadata, metadata))
+	assert.Equal(t, &peer2.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: "key3"}, metadata)
+
+	args = [][]byte{[]byte(GetStateRangeAtHeight), []byte(chainid), []byte("ns1"), []byte(metadata.Bookmark), []byte(""), []byte("1")}
+	res = stub.MockInvokeWithSignedProposal("8", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetStateRangeAtHeight should have succeeded: %s", res.Message)
+	queryResponse = &peer2.QueryResponse{}
+	require.NoError(t, proto.Unmarshal(res.Payload, queryResponse))
+	require.Len(t, queryResponse.Results, 1)
+	kv := &queryresult.KV{}
+	require.NoError(t, proto.Unmarshal(queryResponse.Results[0].ResultBytes, kv))
+	assert.Equal(t, []byte("value3"), kv.Value)
+	assert.False(t, queryResponse.HasMore)
+
+	args = [][]byte{[]byte(GetStateRangeAtHeight), []byte(chainid), []byte("ns1"), []byte("key2"), []byte("")}
+	res = stub.MockInvokeWithSignedProposal("9", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetStateRangeAtHeight should have failed with missing arguments")
+}
+
 func addBlockForTesting(t *testing.T, chainid string) *common.Block {
 	ledger := peer.GetLedger(chainid)
 	defer ledger.Close()
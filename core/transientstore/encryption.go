@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// maxEncryptionMigrationBatchSize bounds the number of private write sets
+// written at once while migrating the encryption of the store
+const maxEncryptionMigrationBatchSize = 1000
+
+// migrateEncryption brings the private write sets persisted before the
+// encryption was enabled, or before the encryption key was rotated, in line
+// with the current encryption configuration. The key in use is recorded in
+// the store so that the migration is skipped on the next openings.
+func (s *store) migrateEncryption() error {
+	marker, err := s.db.Get(encryptionKeyIDKey)
+	if err != nil {
+		return err
+	}
+	expectedMarker := append([]byte{1}, s.encryptor.KeyID()...)
+	if s.encryptor == nil {
+		if marker == nil {
+			return nil
+		}
+		if len(marker) > 1 {
+			return errors.Errorf("transient store for ledger [%s] is encrypted but no at-rest encryption key is configured", s.ledgerID)
+		}
+		return s.db.Delete(encryptionKeyIDKey, true)
+	}
+	if bytes.Equal(marker, expectedMarker) {
+		return nil
+	}
+
+	logger.Infof("Migrating the encryption of the transient store for ledger [%s]", s.ledgerID)
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	iter := s.db.GetIterator([]byte{prwsetPrefix}, []byte{prwsetPrefix + 1})
+	defer iter.Release()
+	for iter.Next() {
+		value := iter.Value()
+		if !s.encryptor.NeedsReencryption(value) {
+			continue
+		}
+		newValue, err := s.encryptor.Reencrypt(value)
+		if err != nil {
+			return errors.WithMessagef(err, "failed migrating the encryption of key [%x]", iter.Key())
+		}
+		dbBatch.Put(iter.Key(), newValue)
+		if dbBatch.Len() >= maxEncryptionMigrationBatchSize {
+			if err := s.db.WriteBatch(dbBatch, true); err != nil {
+				return err
+			}
+			dbBatch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	dbBatch.Put(encryptionKeyIDKey, expectedMarker)
+	return s.db.WriteBatch(dbBatch, true)
+}
@@ -7,15 +7,17 @@ SPDX-License-Identifier: Apache-2.0
 package transientstore
 
 import (
-	"errors"
-
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/transientstore"
+	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
 
@@ -105,30 +107,44 @@ type EndorserPvtSimulationResultsWithConfig struct {
 // interface.
 type storeProvider struct {
 	dbProvider *leveldbhelper.Provider
+	encryptor  *atrest.Encryptor
 }
 
 // store holds an instance of a levelDB.
 type store struct {
-	db       *leveldbhelper.DBHandle
-	ledgerID string
+	db        *leveldbhelper.DBHandle
+	ledgerID  string
+	encryptor *atrest.Encryptor
 }
 
 type RwsetScanner struct {
-	txid   string
-	dbItr  iterator.Iterator
-	filter ledger.PvtNsCollFilter
+	txid      string
+	dbItr     iterator.Iterator
+	filter    ledger.PvtNsCollFilter
+	encryptor *atrest.Encryptor
 }
 
 // NewStoreProvider instantiates TransientStoreProvider
-func NewStoreProvider() StoreProvider {
+func NewStoreProvider() (StoreProvider, error) {
+	var encryptor *atrest.Encryptor
+	if conf := ledgerconfig.GetAtRestEncryptionConfig(); conf != nil {
+		var err error
+		if encryptor, err = atrest.New(factory.GetDefault(), conf); err != nil {
+			return nil, errors.WithMessage(err, "failed initializing the encryption of the transient store")
+		}
+	}
 	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: GetTransientStorePath()})
-	return &storeProvider{dbProvider: dbProvider}
+	return &storeProvider{dbProvider: dbProvider, encryptor: encryptor}, nil
 }
 
 // OpenStore returns a handle to a ledgerId in Store
 func (provider *storeProvider) OpenStore(ledgerID string) (Store, error) {
 	dbHandle := provider.dbProvider.GetDBHandle(ledgerID)
-	return &store{db: dbHandle, ledgerID: ledgerID}, nil
+	s := &store{db: dbHandle, ledgerID: ledgerID, encryptor: provider.encryptor}
+	if err := s.migrateEncryption(); err != nil {
+		return nil, err
+	}
+	return s, nil
 }
 
 // Close closes the TransientStoreProvider
@@ -155,6 +171,10 @@ func (s *store) Persist(txid string, blockHeight uint64,
 	if err != nil {
 		return err
 	}
+	privateSimulationResultsBytes, err = s.encryptor.Encrypt(privateSimulationResultsBytes)
+	if err != nil {
+		return err
+	}
 	dbBatch.Put(compositeKeyPvtRWSet, privateSimulationResultsBytes)
 
 	// Create two index: (i) by txid, and (ii) by height
@@ -209,7 +229,10 @@ func (s *store) PersistWithConfig(txid string, blockHeight uint64,
 	// retrieving, a nil byte is prepended to the new proto, i.e., privateSimulationResultsWithConfigBytes,
 	// as a marshaled message can never start with a nil byte. In v1.3, we can avoid prepending the
 	// nil byte.
-	value := append([]byte{nilByte}, privateSimulationResultsWithConfigBytes...)
+	value, err := s.encryptor.Encrypt(append([]byte{nilByte}, privateSimulationResultsWithConfigBytes...))
+	if err != nil {
+		return err
+	}
 	dbBatch.Put(compositeKeyPvtRWSet, value)
 
 	// Create two index: (i) by txid, and (ii) by height
@@ -249,7 +272,7 @@ func (s *store) GetTxPvtRWSetByTxid(txid string, filter ledger.PvtNsCollFilter)
 	endKey := createTxidRangeEndKey(txid)
 
 	iter := s.db.GetIterator(startKey, endKey)
-	return &RwsetScanner{txid, iter, filter}, nil
+	return &RwsetScanner{txid, iter, filter, s.encryptor}, nil
 }
 
 // PurgeByTxids removes private write sets of a given set of transactions from the
@@ -376,11 +399,14 @@ func (scanner *RwsetScanner) Next() (*EndorserPvtSimulationResults, error) {
 		return nil, nil
 	}
 	dbKey := scanner.dbItr.Key()
-	dbVal := scanner.dbItr.Value()
 	_, blockHeight, err := splitCompositeKeyOfPvtRWSet(dbKey)
 	if err != nil {
 		return nil, err
 	}
+	dbVal, err := scanner.encryptor.Decrypt(scanner.dbItr.Value())
+	if err != nil {
+		return nil, err
+	}
 
 	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
 	if err := proto.Unmarshal(dbVal, txPvtRWSet); err != nil {
@@ -402,11 +428,14 @@ func (scanner *RwsetScanner) NextWithConfig() (*EndorserPvtSimulationResultsWith
 		return nil, nil
 	}
 	dbKey := scanner.dbItr.Key()
-	dbVal := scanner.dbItr.Value()
 	_, blockHeight, err := splitCompositeKeyOfPvtRWSet(dbKey)
 	if err != nil {
 		return nil, err
 	}
+	dbVal, err := scanner.encryptor.Decrypt(scanner.dbItr.Value())
+	if err != nil {
+		return nil, err
+	}
 
 	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
 	filteredTxPvtRWSet := &rwset.TxPvtReadWriteSet{}
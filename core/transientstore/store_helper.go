@@ -22,6 +22,7 @@ var (
 	prwsetPrefix             = []byte("P")[0] // key prefix for storing private write set in transient store.
 	purgeIndexByHeightPrefix = []byte("H")[0] // key prefix for storing index on private write set using received at block height.
 	purgeIndexByTxidPrefix   = []byte("T")[0] // key prefix for storing index on private write set using txid
+	encryptionKeyIDKey       = []byte("E")    // key storing the SKI of the key encrypting the private write sets.
 	compositeKeySep          = byte(0x00)
 )
 
@@ -13,7 +13,10 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/gm"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
@@ -518,6 +521,101 @@ func TestTransientStoreRetrievalWithFilter(t *testing.T) {
 
 }
 
+func TestTransientStoreEncryption(t *testing.T) {
+	env := NewTestStoreEnv(t)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	txid := "txid-1"
+	var receivedAtBlockHeight uint64 = 10
+
+	csp, err := gm.NewDefaultSecurityLevelWithKeystore(gm.NewDummyKeyStore())
+	assert.NoError(err)
+	key1, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	assert.NoError(err)
+	key2, err := csp.KeyGen(&bccsp.GMSM4KeyGenOpts{Temporary: true})
+	assert.NoError(err)
+
+	reopen := func(encryptor *atrest.Encryptor) (Store, error) {
+		env.TestStoreProvider.Close()
+		newProvider, err := NewStoreProvider()
+		if err != nil {
+			return nil, err
+		}
+		provider := newProvider.(*storeProvider)
+		provider.encryptor = encryptor
+		env.TestStoreProvider = provider
+		return provider.OpenStore("TestStore")
+	}
+	assertResults := func(s Store) {
+		iter, err := s.GetTxPvtRWSetByTxid(txid, nil)
+		assert.NoError(err)
+		var results []*EndorserPvtSimulationResultsWithConfig
+		for {
+			result, err := iter.NextWithConfig()
+			assert.NoError(err)
+			if result == nil {
+				break
+			}
+			results = append(results, result)
+		}
+		iter.Close()
+		assert.Len(results, 2)
+		minBlkHt, err := s.GetMinTransientBlkHt()
+		assert.NoError(err)
+		assert.Equal(receivedAtBlockHeight, minBlkHt)
+	}
+	assertValues := func(s Store, keyID []byte) {
+		iter := s.(*store).db.GetIterator([]byte{prwsetPrefix}, []byte{prwsetPrefix + 1})
+		defer iter.Release()
+		for iter.Next() {
+			value := iter.Value()
+			if keyID == nil {
+				assert.False(atrest.IsEncrypted(value))
+				continue
+			}
+			assert.True(atrest.IsEncrypted(value))
+			assert.Equal(keyID, value[2:2+len(keyID)])
+		}
+	}
+
+	// Persist private simulation results in plaintext with both protos
+	assert.NoError(env.TestStore.Persist(txid, receivedAtBlockHeight, samplePvtData(t)))
+	assert.NoError(env.TestStore.PersistWithConfig(txid, receivedAtBlockHeight, samplePvtDataWithConfigInfo(t)))
+
+	// Enabling the encryption migrates the existing results
+	encryptor1, err := atrest.NewEncryptor(csp, key1)
+	assert.NoError(err)
+	s, err := reopen(encryptor1)
+	assert.NoError(err)
+	assertValues(s, key1.SKI())
+	assertResults(s)
+
+	// The encryption cannot be turned off while the results are encrypted
+	_, err = reopen(nil)
+	assert.EqualError(err, "transient store for ledger [TestStore] is encrypted but no at-rest encryption key is configured")
+
+	// Rotating the key encrypts the results again
+	encryptor2, err := atrest.NewEncryptor(csp, key2, key1)
+	assert.NoError(err)
+	s, err = reopen(encryptor2)
+	assert.NoError(err)
+	assertValues(s, key2.SKI())
+	assertResults(s)
+
+	// New results are encrypted with the current key
+	assert.NoError(s.PersistWithConfig(txid, receivedAtBlockHeight+1, samplePvtDataWithConfigInfo(t)))
+	assertValues(s, key2.SKI())
+
+	// The results are decrypted before turning the encryption off
+	decryptor, err := atrest.NewEncryptor(csp, nil, key2)
+	assert.NoError(err)
+	_, err = reopen(decryptor)
+	assert.NoError(err)
+	s, err = reopen(nil)
+	assert.NoError(err)
+	assertValues(s, nil)
+}
+
 func sortResults(res []*EndorserPvtSimulationResultsWithConfig) {
 	// Results are sorted by ascending order of received at block height. When the block
 	// heights are same, we sort by comparing the hash of private write set.
@@ -621,3 +719,13 @@ func sampleCollectionConfigPackage(colName string) *common.CollectionConfig {
 
 	return createCollectionConfig(colName, policyEnvelope, requiredPeerCount, maximumPeerCount)
 }
+
+func TestNewStoreProviderEncryptionError(t *testing.T) {
+	viper.Set("ledger.encryption.enabled", true)
+	viper.Set("ledger.encryption.key", "not-hex")
+	defer viper.Set("ledger.encryption.enabled", false)
+	defer viper.Set("ledger.encryption.key", "")
+
+	_, err := NewStoreProvider()
+	assert.EqualError(t, err, "failed initializing the encryption of the transient store: invalid SKI [not-hex]: encoding/hex: invalid byte: U+006E 'n'")
+}
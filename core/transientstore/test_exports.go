@@ -34,7 +34,8 @@ type StoreEnv struct {
 func NewTestStoreEnv(t *testing.T) *StoreEnv {
 	removeStorePath(t)
 	assert := assert.New(t)
-	testStoreProvider := NewStoreProvider()
+	testStoreProvider, err := NewStoreProvider()
+	assert.NoError(err)
 	testStore, err := testStoreProvider.OpenStore("TestStore")
 	assert.NoError(err)
 	return &StoreEnv{t, testStoreProvider, testStore}
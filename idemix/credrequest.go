@@ -59,7 +59,7 @@ func NewCredRequest(sk *FP256BN.BIG, IssuerNonce []byte, ipk *IssuerPublicKey, r
 	index = appendBytesG1(proofData, index, Nym)
 	index = appendBytes(proofData, index, IssuerNonce)
 	copy(proofData[index:], ipk.Hash)
-	proofC := HashModOrder(proofData)
+	proofC := ipk.HashModOrder(proofData)
 
 	// Step 3: reply to the challenge message (s-values)
 	proofS := Modadd(FP256BN.Modmul(proofC, sk, GroupOrder), rSk, GroupOrder) // s = r_{sk} + C \cdot sk
@@ -101,7 +101,7 @@ func (m *CredRequest) Check(ipk *IssuerPublicKey) error {
 	index = appendBytes(proofData, index, IssuerNonce)
 	copy(proofData[index:], ipk.Hash)
 
-	if *ProofC != *HashModOrder(proofData) {
+	if *ProofC != *ipk.HashModOrder(proofData) {
 		return errors.Errorf("zero knowledge proof is invalid")
 	}
 
@@ -135,6 +135,7 @@ func (m *ECP2) GetYb() []byte {
 // h_sk, h_rand, h_attrs, w, bar_g1, bar_g2 - group elements corresponding to the signing key, randomness, and attributes
 // proof_c, proof_s compose a zero-knowledge proof of knowledge of the secret key
 // hash is a hash of the public key appended to it
+// hash_algorithm is the hash function of the Fiat-Shamir challenges, SHA256 if empty
 type IssuerPublicKey struct {
 	AttributeNames       []string `protobuf:"bytes,1,rep,name=attribute_names,json=attributeNames,proto3" json:"attribute_names,omitempty"`
 	HSk                  *ECP     `protobuf:"bytes,2,opt,name=h_sk,json=hSk,proto3" json:"h_sk,omitempty"`
@@ -146,6 +147,7 @@ type IssuerPublicKey struct {
 	ProofC               []byte   `protobuf:"bytes,8,opt,name=proof_c,json=proofC,proto3" json:"proof_c,omitempty"`
 	ProofS               []byte   `protobuf:"bytes,9,opt,name=proof_s,json=proofS,proto3" json:"proof_s,omitempty"`
 	Hash                 []byte   `protobuf:"bytes,10,opt,name=hash,proto3" json:"hash,omitempty"`
+	HashAlgorithm        string   `protobuf:"bytes,11,opt,name=hash_algorithm,json=hashAlgorithm,proto3" json:"hash_algorithm,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -245,6 +247,13 @@ func (m *IssuerPublicKey) GetHash() []byte {
 	return nil
 }
 
+func (m *IssuerPublicKey) GetHashAlgorithm() string {
+	if m != nil {
+		return m.HashAlgorithm
+	}
+	return ""
+}
+
 // IssuerKey specifies an issuer key pair that consists of
 // ISk - the issuer secret key and
 // IssuerPublicKey - the issuer public key
@@ -815,56 +824,58 @@ func init() {
 func init() { proto.RegisterFile("idemix/idemix.proto", fileDescriptor_idemix_ea623f6980eee47e) }
 
 var fileDescriptor_idemix_ea623f6980eee47e = []byte{
-	// 816 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0xdd, 0x6e, 0xe2, 0x46,
-	0x14, 0xd6, 0x60, 0x9b, 0x84, 0x83, 0x13, 0xb2, 0x93, 0xa8, 0x3b, 0xfd, 0x53, 0x59, 0xab, 0xdb,
-	0x8d, 0x7a, 0x41, 0xba, 0x44, 0x7d, 0x80, 0x2c, 0xa5, 0xd5, 0xaa, 0x12, 0x42, 0xe6, 0xae, 0x37,
-	0xa3, 0x19, 0x18, 0xb0, 0x05, 0xb6, 0xe9, 0xd8, 0x74, 0x71, 0x2f, 0xfa, 0x34, 0x7d, 0x9b, 0x5e,
-	0xf4, 0x95, 0xaa, 0xf9, 0x01, 0x0f, 0x61, 0xb7, 0x57, 0xf1, 0x39, 0xdf, 0xf9, 0xe3, 0xfb, 0x3e,
-	0xc7, 0x70, 0x9b, 0x2e, 0x44, 0x96, 0xee, 0x1f, 0xcc, 0x9f, 0xc1, 0x56, 0x16, 0x55, 0x11, 0xbd,
-	0x02, 0x6f, 0x3c, 0x9a, 0xe2, 0x10, 0xd0, 0x9e, 0xa0, 0x3e, 0xba, 0x0f, 0x63, 0xb4, 0x57, 0x51,
-	0x4d, 0x5a, 0x26, 0xaa, 0xa3, 0x9f, 0xc1, 0x1f, 0x8f, 0xa6, 0x43, 0x7c, 0x0d, 0xad, 0x3d, 0xb3,
-	0x45, 0xad, 0x3d, 0xd3, 0x31, 0xb7, 0x65, 0xad, 0x3d, 0x57, 0x71, 0xcd, 0x88, 0x67, 0xe2, 0x5a,
-	0xe3, 0x35, 0x27, 0xbe, 0x8d, 0x79, 0xf4, 0x77, 0x0b, 0x7a, 0xef, 0xcb, 0x72, 0x27, 0xe4, 0x74,
-	0xc7, 0x37, 0xe9, 0xfc, 0x57, 0x51, 0xe3, 0x37, 0xd0, 0x63, 0x55, 0x25, 0x53, 0xbe, 0xab, 0x04,
-	0xcd, 0x59, 0x26, 0x4a, 0x82, 0xfa, 0xde, 0x7d, 0x27, 0xbe, 0x3e, 0xa6, 0x27, 0x2a, 0x8b, 0x5f,
-	0x82, 0x9f, 0xd0, 0x72, 0xad, 0xd7, 0x75, 0x87, 0xfe, 0x60, 0x3c, 0x9a, 0xc6, 0x5e, 0x32, 0x5b,
-	0xe3, 0x2f, 0xa1, 0x9d, 0x50, 0xc9, 0xf2, 0x85, 0xde, 0x7c, 0x80, 0x82, 0x24, 0x66, 0xf9, 0x02,
-	0x7f, 0x0d, 0x17, 0x09, 0x55, 0x93, 0x4a, 0xe2, 0xf7, 0xbd, 0x23, 0xda, 0x4e, 0x9e, 0x54, 0x0e,
-	0xdf, 0x02, 0xfa, 0x40, 0x02, 0xdd, 0x16, 0x28, 0x60, 0x18, 0xa3, 0x0f, 0x6a, 0x20, 0x67, 0x92,
-	0xae, 0xde, 0x92, 0xb6, 0x3b, 0x90, 0x33, 0xf9, 0xcb, 0xdb, 0x23, 0x38, 0x24, 0x17, 0xcf, 0xc1,
-	0x21, 0x7e, 0x09, 0x17, 0x5b, 0x59, 0x14, 0x4b, 0x3a, 0x27, 0x97, 0xfa, 0x57, 0xb7, 0x75, 0x38,
-	0x6a, 0x80, 0x92, 0x74, 0x1c, 0x60, 0x86, 0x31, 0xf8, 0x09, 0x2b, 0x13, 0x02, 0x3a, 0xab, 0x9f,
-	0xa3, 0x27, 0xe8, 0x18, 0x96, 0x14, 0x3f, 0x37, 0xe0, 0xa5, 0xe5, 0xda, 0x92, 0xae, 0x1e, 0x71,
-	0x04, 0x5e, 0xba, 0x3d, 0xf0, 0x70, 0x33, 0x78, 0x46, 0x68, 0xac, 0xc0, 0x68, 0x09, 0x30, 0x92,
-	0x62, 0x21, 0xf2, 0x2a, 0x65, 0x1b, 0x8c, 0x01, 0x19, 0xd9, 0x0e, 0xe7, 0x22, 0xa6, 0x72, 0xfc,
-	0x84, 0x4b, 0xc4, 0x95, 0xea, 0xc2, 0xca, 0x87, 0x84, 0x8a, 0x4a, 0x2b, 0x1e, 0x2a, 0xf1, 0x1d,
-	0x04, 0x86, 0xc6, 0xa0, 0xef, 0xdd, 0x87, 0xb1, 0x09, 0xa2, 0x3f, 0xa1, 0xab, 0xf6, 0xc4, 0xe2,
-	0xf7, 0x9d, 0x28, 0x2b, 0xfc, 0x19, 0x78, 0x79, 0x9d, 0x9d, 0xac, 0x52, 0x09, 0xfc, 0x0a, 0xc2,
-	0x54, 0x9f, 0x49, 0xf3, 0x22, 0x9f, 0x0b, 0x6b, 0x99, 0xae, 0xc9, 0x4d, 0x54, 0xca, 0xa5, 0xce,
-	0xfb, 0x14, 0x75, 0xbe, 0x4b, 0x5d, 0xf4, 0xaf, 0x0f, 0x9d, 0x59, 0xba, 0xca, 0x59, 0xb5, 0x93,
-	0x42, 0x09, 0xcd, 0xe8, 0x56, 0xa6, 0x99, 0x38, 0x59, 0xdf, 0x66, 0x53, 0x95, 0xc3, 0x9f, 0x43,
-	0xc0, 0x28, 0x67, 0xf2, 0xe4, 0x27, 0xfb, 0xec, 0x1d, 0x93, 0xaa, 0x93, 0xdb, 0x4e, 0xd7, 0x40,
-	0x6d, 0x6e, 0x3a, 0x9d, 0xc3, 0xfc, 0x93, 0xc3, 0xbe, 0x02, 0xb0, 0x87, 0x29, 0x5b, 0x06, 0x1a,
-	0xbb, 0x34, 0xb7, 0xcd, 0xd6, 0xf8, 0x0b, 0xe8, 0x1c, 0x50, 0xa1, 0x7d, 0x14, 0xc6, 0x66, 0xce,
-	0x6c, 0xec, 0x76, 0x4a, 0xe3, 0xa3, 0x63, 0x67, 0x3c, 0x3c, 0x41, 0x1f, 0xad, 0x8f, 0x0e, 0xe8,
-	0x23, 0x7e, 0x0d, 0xbd, 0xe3, 0x56, 0x7b, 0xb5, 0x71, 0x54, 0x68, 0x57, 0x9b, 0xab, 0x23, 0xb8,
-	0x3a, 0x94, 0x19, 0xd9, 0x40, 0xcb, 0xd6, 0x35, 0x45, 0xc6, 0xfc, 0x77, 0x10, 0x18, 0x39, 0xba,
-	0x7a, 0x80, 0x09, 0x0e, 0x1a, 0x86, 0xe7, 0x1a, 0x1e, 0x27, 0x4a, 0xaa, 0x2a, 0xae, 0x74, 0x17,
-	0xd8, 0xcb, 0x26, 0x75, 0x86, 0x7f, 0x84, 0x5b, 0x29, 0xfe, 0x28, 0xe6, 0xac, 0x4a, 0x8b, 0x9c,
-	0x8a, 0x6d, 0x31, 0x4f, 0xe8, 0x76, 0x4d, 0xae, 0xdd, 0xf7, 0xeb, 0x45, 0x53, 0x31, 0x56, 0x05,
-	0xd3, 0x35, 0xfe, 0x1e, 0x9c, 0x24, 0xdd, 0xae, 0x69, 0x99, 0xae, 0x48, 0x4f, 0x4f, 0xef, 0x35,
-	0xc0, 0x74, 0x3d, 0x4b, 0x57, 0xea, 0x66, 0x3d, 0x97, 0xdc, 0xf4, 0xd1, 0xbd, 0x17, 0x9b, 0x00,
-	0x8f, 0xe1, 0x2e, 0x2f, 0x72, 0xea, 0x4e, 0x51, 0x57, 0x91, 0x17, 0x7a, 0xf3, 0xed, 0x60, 0x52,
-	0xe4, 0x71, 0x33, 0x48, 0x41, 0x31, 0xce, 0xcf, 0x72, 0x51, 0x06, 0xf8, 0xbc, 0x12, 0xbf, 0x86,
-	0x6b, 0x67, 0x30, 0xdb, 0xac, 0xb4, 0xc1, 0x82, 0xf8, 0xaa, 0xc9, 0x3e, 0x6d, 0x56, 0xf8, 0x87,
-	0x4f, 0xdc, 0x60, 0xbc, 0xfe, 0xb1, 0x75, 0x7f, 0x41, 0x38, 0xa9, 0xb3, 0xc6, 0xc2, 0x8e, 0xd3,
-	0xd0, 0xff, 0x38, 0xad, 0xf5, 0xcc, 0x69, 0x67, 0xc2, 0x78, 0x67, 0xc2, 0x1c, 0x95, 0xf6, 0x1d,
-	0xa5, 0xa3, 0x7f, 0x10, 0x7c, 0xd3, 0xfc, 0x97, 0x68, 0xae, 0x7b, 0x9f, 0x2f, 0x0b, 0x99, 0xe9,
-	0xc7, 0x86, 0x6f, 0xe4, 0xf2, 0xdd, 0x87, 0xcb, 0xa3, 0xba, 0x2d, 0x57, 0xdd, 0x0b, 0x61, 0x35,
-	0xed, 0x43, 0x78, 0xa8, 0xd0, 0x72, 0xda, 0x9b, 0x2c, 0xac, 0x94, 0x3c, 0xa7, 0xd5, 0xff, 0x18,
-	0xad, 0x6f, 0xc0, 0xf1, 0x00, 0x5d, 0xb0, 0x8a, 0xd9, 0x57, 0xcd, 0xe9, 0xfe, 0x89, 0x55, 0xec,
-	0xdd, 0x77, 0xbf, 0x7d, 0xbb, 0x4a, 0xab, 0x64, 0xc7, 0x07, 0xf3, 0x22, 0x7b, 0x48, 0xea, 0xad,
-	0x90, 0x1b, 0xb1, 0x58, 0x09, 0xf9, 0xb0, 0x64, 0x5c, 0xa6, 0x73, 0xfb, 0xd5, 0xe3, 0x6d, 0xfd,
-	0xd9, 0x7b, 0xfc, 0x2f, 0x00, 0x00, 0xff, 0xff, 0xaa, 0xcc, 0x9c, 0x10, 0x0d, 0x07, 0x00, 0x00,
+	// 835 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0x4d, 0x8f, 0xe2, 0x46,
+	0x10, 0x55, 0x63, 0x9b, 0x19, 0x0a, 0xcf, 0x30, 0xdb, 0x33, 0xca, 0x76, 0xbe, 0x14, 0xd6, 0xca,
+	0x66, 0x51, 0x0e, 0x4c, 0x96, 0x51, 0x7e, 0xc0, 0x2c, 0x21, 0xd1, 0x2a, 0x12, 0x42, 0xe6, 0x96,
+	0x4b, 0xab, 0x0d, 0x3d, 0xb6, 0x05, 0xb6, 0x49, 0xdb, 0x64, 0x71, 0x0e, 0xf9, 0x83, 0x7b, 0xc8,
+	0x5f, 0x8a, 0xfa, 0x03, 0xbb, 0x19, 0x76, 0x73, 0xc2, 0xf5, 0x5e, 0x75, 0x55, 0xb9, 0xde, 0x6b,
+	0x0c, 0xb7, 0xe9, 0x9a, 0x67, 0xe9, 0xe1, 0x5e, 0xff, 0x8c, 0x77, 0xa2, 0xa8, 0x8a, 0xe0, 0x15,
+	0x38, 0xb3, 0xe9, 0x02, 0xfb, 0x80, 0x0e, 0x04, 0x0d, 0xd1, 0xc8, 0x0f, 0xd1, 0x41, 0x46, 0x35,
+	0xe9, 0xe8, 0xa8, 0x0e, 0x7e, 0x05, 0x77, 0x36, 0x5d, 0x4c, 0xf0, 0x35, 0x74, 0x0e, 0xcc, 0x24,
+	0x75, 0x0e, 0x4c, 0xc5, 0x91, 0x49, 0xeb, 0x1c, 0x22, 0x19, 0xd7, 0x8c, 0x38, 0x3a, 0xae, 0x15,
+	0x5f, 0x47, 0xc4, 0x35, 0x71, 0x14, 0x7c, 0xec, 0xc0, 0xe0, 0x7d, 0x59, 0xee, 0xb9, 0x58, 0xec,
+	0xa3, 0x6d, 0xba, 0xfa, 0x9d, 0xd7, 0xf8, 0x0d, 0x0c, 0x58, 0x55, 0x89, 0x34, 0xda, 0x57, 0x9c,
+	0xe6, 0x2c, 0xe3, 0x25, 0x41, 0x43, 0x67, 0xd4, 0x0b, 0xaf, 0x1b, 0x78, 0x2e, 0x51, 0xfc, 0x12,
+	0xdc, 0x84, 0x96, 0x1b, 0xd5, 0xae, 0x3f, 0x71, 0xc7, 0xb3, 0xe9, 0x22, 0x74, 0x92, 0xe5, 0x06,
+	0x7f, 0x0d, 0xdd, 0x84, 0x0a, 0x96, 0xaf, 0x89, 0x63, 0x51, 0x5e, 0x12, 0xb2, 0x7c, 0x8d, 0xbf,
+	0x85, 0x8b, 0x84, 0xca, 0x4a, 0x25, 0x71, 0x87, 0x4e, 0xc3, 0x76, 0x93, 0x47, 0x89, 0xe1, 0x5b,
+	0x40, 0x1f, 0x88, 0xa7, 0x8e, 0x79, 0x92, 0x98, 0x84, 0xe8, 0x83, 0x2c, 0x18, 0x31, 0x41, 0xe3,
+	0xb7, 0xa4, 0x6b, 0x17, 0x8c, 0x98, 0xf8, 0xed, 0x6d, 0x43, 0x4e, 0xc8, 0xc5, 0x73, 0x72, 0x82,
+	0x5f, 0xc2, 0xc5, 0x4e, 0x14, 0xc5, 0x13, 0x5d, 0x91, 0x4b, 0xf5, 0xd6, 0x5d, 0x15, 0x4e, 0x5b,
+	0xa2, 0x24, 0x3d, 0x8b, 0x58, 0x62, 0x0c, 0x6e, 0xc2, 0xca, 0x84, 0x80, 0x42, 0xd5, 0x33, 0x7e,
+	0x0d, 0xd7, 0xf2, 0x97, 0xb2, 0x6d, 0x5c, 0x88, 0xb4, 0x4a, 0x32, 0xd2, 0x1f, 0xa2, 0x51, 0x2f,
+	0xbc, 0x92, 0xe8, 0xe3, 0x11, 0x0c, 0x1e, 0xa1, 0xa7, 0x97, 0x29, 0xd7, 0x78, 0x03, 0x4e, 0x5a,
+	0x6e, 0x8c, 0x36, 0xf2, 0x11, 0x07, 0xe0, 0xa4, 0xbb, 0xe3, 0xba, 0x6e, 0xc6, 0xcf, 0xf6, 0x1e,
+	0x4a, 0x32, 0x78, 0x02, 0x98, 0x0a, 0xbe, 0xe6, 0x79, 0x95, 0xb2, 0x2d, 0xc6, 0x80, 0xb4, 0xba,
+	0xc7, 0xb7, 0x42, 0x4c, 0x62, 0xd1, 0xc9, 0xca, 0x51, 0x24, 0xcd, 0xc1, 0x8d, 0xca, 0x88, 0xcb,
+	0xa8, 0x34, 0x1a, 0xa3, 0x12, 0xdf, 0x81, 0xa7, 0xb7, 0xed, 0x0d, 0x9d, 0x91, 0x1f, 0xea, 0x20,
+	0xf8, 0x1b, 0xfa, 0xb2, 0x4f, 0xc8, 0xff, 0xdc, 0xf3, 0xb2, 0xc2, 0x5f, 0x80, 0x93, 0xd7, 0xd9,
+	0x49, 0x2b, 0x09, 0xe0, 0x57, 0xe0, 0xa7, 0x6a, 0x4c, 0x9a, 0x17, 0xf9, 0x8a, 0x1b, 0x67, 0xf5,
+	0x35, 0x36, 0x97, 0x90, 0xbd, 0x61, 0xe7, 0x73, 0x1b, 0x76, 0xed, 0x0d, 0x07, 0xff, 0xba, 0xd0,
+	0x5b, 0xa6, 0x71, 0xce, 0xaa, 0xbd, 0xe0, 0xd2, 0x0f, 0x8c, 0xee, 0x44, 0x9a, 0xf1, 0x93, 0xf6,
+	0x5d, 0xb6, 0x90, 0x18, 0xfe, 0x12, 0x3c, 0x46, 0x23, 0x26, 0x4e, 0x5e, 0xd9, 0x65, 0xef, 0x98,
+	0x90, 0x27, 0x23, 0x73, 0xd2, 0xf6, 0x59, 0x37, 0xd2, 0x27, 0xad, 0xc1, 0xdc, 0x93, 0xc1, 0xbe,
+	0x01, 0x30, 0x83, 0x49, 0xf7, 0x7a, 0x8a, 0xbb, 0xd4, 0xb3, 0x2d, 0x37, 0xf8, 0x2b, 0xe8, 0x1d,
+	0x59, 0xae, 0xec, 0xe6, 0x87, 0xba, 0xce, 0x72, 0x66, 0x9f, 0x14, 0xda, 0x6e, 0xcd, 0xc9, 0x70,
+	0x72, 0xc2, 0x3e, 0x90, 0xcb, 0x13, 0xf6, 0x01, 0xbf, 0x86, 0x41, 0xd3, 0xd5, 0x4c, 0xad, 0x8d,
+	0xe7, 0x9b, 0xd6, 0x7a, 0xea, 0x00, 0xae, 0x8e, 0x69, 0x5a, 0x36, 0x50, 0xb2, 0xf5, 0x75, 0x92,
+	0xbe, 0x23, 0x77, 0xe0, 0x69, 0x39, 0xfa, 0xaa, 0x80, 0x0e, 0x8e, 0x1a, 0xfa, 0xe7, 0x1a, 0x36,
+	0x15, 0x05, 0x95, 0x19, 0x57, 0xea, 0x14, 0x98, 0xc9, 0xe6, 0x75, 0x86, 0x7f, 0x86, 0x5b, 0xc1,
+	0xff, 0x2a, 0x56, 0xac, 0x4a, 0x8b, 0x9c, 0xf2, 0x5d, 0xb1, 0x4a, 0xe8, 0x6e, 0x43, 0xae, 0xed,
+	0x6b, 0xf8, 0xa2, 0xcd, 0x98, 0xc9, 0x84, 0xc5, 0x06, 0xff, 0x08, 0x16, 0x48, 0x77, 0x1b, 0x5a,
+	0xa6, 0x31, 0x19, 0xa8, 0xea, 0x83, 0x96, 0x58, 0x6c, 0x96, 0x69, 0x2c, 0x67, 0x56, 0x75, 0xc9,
+	0xcd, 0x10, 0x8d, 0x9c, 0x50, 0x07, 0x78, 0x06, 0x77, 0x79, 0x91, 0x53, 0xbb, 0x8a, 0x9c, 0x8a,
+	0xbc, 0x50, 0x9d, 0x6f, 0xc7, 0xf3, 0x22, 0x0f, 0xdb, 0x42, 0x92, 0x0a, 0x71, 0x7e, 0x86, 0x05,
+	0x19, 0xe0, 0xf3, 0x4c, 0x79, 0x6b, 0xad, 0xc2, 0x6c, 0x1b, 0x2b, 0x83, 0x79, 0xe1, 0x55, 0x8b,
+	0x3e, 0x6e, 0x63, 0xfc, 0xd3, 0x67, 0x66, 0xd0, 0x5e, 0xff, 0x54, 0xbb, 0x7f, 0xc0, 0x9f, 0xd7,
+	0x59, 0x6b, 0x61, 0xcb, 0x69, 0xe8, 0x7f, 0x9c, 0xd6, 0x79, 0xe6, 0xb4, 0x33, 0x61, 0x9c, 0x33,
+	0x61, 0x1a, 0xa5, 0x5d, 0x4b, 0xe9, 0xe0, 0x23, 0x82, 0xef, 0xda, 0x7f, 0x89, 0x76, 0xba, 0xf7,
+	0xf9, 0x53, 0x21, 0x32, 0xf5, 0xd8, 0xee, 0x1b, 0xd9, 0xfb, 0x1e, 0xc2, 0x65, 0xa3, 0x6e, 0xc7,
+	0x56, 0xf7, 0x82, 0x1b, 0x4d, 0x87, 0xe0, 0x1f, 0x33, 0x94, 0x9c, 0x66, 0x26, 0x43, 0x4b, 0x25,
+	0xcf, 0xd7, 0xea, 0x7e, 0x6a, 0xad, 0x6f, 0xc0, 0xf2, 0x00, 0x5d, 0xb3, 0x8a, 0x99, 0xab, 0x66,
+	0x9d, 0xfe, 0x85, 0x55, 0xec, 0xdd, 0x0f, 0x7f, 0x7c, 0x1f, 0xa7, 0x55, 0xb2, 0x8f, 0xc6, 0xab,
+	0x22, 0xbb, 0x4f, 0xea, 0x1d, 0x17, 0x5b, 0xbe, 0x8e, 0xb9, 0xb8, 0x7f, 0x62, 0x91, 0x48, 0x57,
+	0xe6, 0xe3, 0x18, 0x75, 0xd5, 0xd7, 0xf1, 0xe1, 0xbf, 0x01, 0x00, 0xf6, 0xb1, 0xb0, 0x82, 0x34,
+	0x07, 0x00, 0x00,
 }
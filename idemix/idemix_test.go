@@ -162,3 +162,77 @@ func TestIdemix(t *testing.T) {
 		return
 	}
 }
+
+func TestIdemixGM(t *testing.T) {
+	rng, err := GetRand()
+	assert.NoError(t, err)
+
+	AttributeNames := []string{"Attr1", "Attr2", "Attr3", "Attr4", "Attr5"}
+	attrs := make([]*FP256BN.BIG, len(AttributeNames))
+	for i := range AttributeNames {
+		attrs[i] = FP256BN.NewBIGint(i)
+	}
+
+	_, err = NewIssuerKeyWithHashAlgorithm(AttributeNames, "MD5", rng)
+	assert.EqualError(t, err, "unsupported hash algorithm MD5")
+
+	key, err := NewIssuerKeyWithHashAlgorithm(AttributeNames, HashAlgorithmSM3, rng)
+	assert.NoError(t, err)
+	assert.Equal(t, HashAlgorithmSM3, key.Ipk.HashAlgorithm)
+	assert.NoError(t, key.Ipk.Check())
+	assert.NotEqual(t, *HashModOrder([]byte("data")), *key.Ipk.HashModOrder([]byte("data")))
+
+	// The proofs of the key do not verify with another hash algorithm
+	key.Ipk.HashAlgorithm = HashAlgorithmSHA256
+	assert.EqualError(t, key.Ipk.Check(), "zero knowledge proof in public key invalid")
+	key.Ipk.HashAlgorithm = "MD5"
+	assert.EqualError(t, key.Ipk.Check(), "unsupported hash algorithm MD5")
+	key.Ipk.HashAlgorithm = HashAlgorithmSM3
+	assert.NoError(t, key.Ipk.Check())
+
+	sk := RandModOrder(rng)
+	ni := RandModOrder(rng)
+	m := NewCredRequest(sk, BigToBytes(ni), key.Ipk, rng)
+	assert.NoError(t, m.Check(key.Ipk))
+	cred, err := NewCredential(key, m, attrs, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, cred.Ver(sk, key.Ipk))
+
+	// The revocation authority signs with SM2
+	revocationKey, err := GenerateLongTermSM2RevocationKey()
+	assert.NoError(t, err)
+	assert.True(t, IsSM2RevocationKey(&revocationKey.PublicKey))
+	ecdsaRevocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	assert.False(t, IsSM2RevocationKey(&ecdsaRevocationKey.PublicKey))
+
+	epoch := 0
+	cri, err := CreateCRI(revocationKey, []*FP256BN.BIG{}, epoch, ALG_NO_REVOCATION, rng)
+	assert.NoError(t, err)
+	err = VerifyEpochPK(&revocationKey.PublicKey, cri.EpochPk, cri.EpochPkSig, int(cri.Epoch), RevocationAlgorithm(cri.RevocationAlg))
+	assert.NoError(t, err)
+	err = VerifyEpochPK(&revocationKey.PublicKey, cri.EpochPk, cri.EpochPkSig, int(cri.Epoch)+1, RevocationAlgorithm(cri.RevocationAlg))
+	assert.Error(t, err)
+	err = VerifyEpochPK(&ecdsaRevocationKey.PublicKey, cri.EpochPk, cri.EpochPkSig, int(cri.Epoch), RevocationAlgorithm(cri.RevocationAlg))
+	assert.Error(t, err)
+
+	Nym, RandNym := MakeNym(sk, key.Ipk, rng)
+	disclosure := []byte{0, 1, 1, 1, 0}
+	msg := []byte{1, 2, 3, 4, 5}
+	rhindex := 4
+	sig, err := NewSignature(cred, sk, Nym, RandNym, key.Ipk, disclosure, msg, rhindex, cri, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, sig.Ver(disclosure, key.Ipk, msg, attrs, rhindex, &revocationKey.PublicKey, epoch))
+	assert.Error(t, sig.Ver(disclosure, key.Ipk, []byte("another message"), attrs, rhindex, &revocationKey.PublicKey, epoch))
+
+	nymsig, err := NewNymSignature(sk, Nym, RandNym, key.Ipk, []byte("testing"), rng)
+	assert.NoError(t, err)
+	assert.NoError(t, nymsig.Ver(Nym, key.Ipk, []byte("testing")))
+
+	// A signature computed w.r.t. SHA256 challenges does not verify in GM mode
+	sha256Ipk := *key.Ipk
+	sha256Ipk.HashAlgorithm = ""
+	nymsig, err = NewNymSignature(sk, Nym, RandNym, &sha256Ipk, []byte("testing"), rng)
+	assert.NoError(t, err)
+	assert.Error(t, nymsig.Ver(Nym, key.Ipk, []byte("testing")))
+}
@@ -27,7 +27,17 @@ import (
 // that will be contained in credentials certified by this issuer (a credential specification)
 // See http://eprint.iacr.org/2016/663.pdf Sec. 4.3, for references.
 func NewIssuerKey(AttributeNames []string, rng *amcl.RAND) (*IssuerKey, error) {
+	return NewIssuerKeyWithHashAlgorithm(AttributeNames, "", rng)
+}
+
+// NewIssuerKeyWithHashAlgorithm creates a new issuer key pair like NewIssuerKey.
+// The Fiat-Shamir challenges of the proofs w.r.t. this key are computed with
+// HashAlgorithm, one of HashAlgorithmSHA256 (the default, if empty) or HashAlgorithmSM3.
+func NewIssuerKeyWithHashAlgorithm(AttributeNames []string, HashAlgorithm string, rng *amcl.RAND) (*IssuerKey, error) {
 	// validate inputs
+	if err := checkHashAlgorithm(HashAlgorithm); err != nil {
+		return nil, err
+	}
 
 	// check for duplicated attributes
 	attributeNamesMap := map[string]bool{}
@@ -47,6 +57,7 @@ func NewIssuerKey(AttributeNames []string, rng *amcl.RAND) (*IssuerKey, error) {
 	// generate the corresponding public key
 	key.Ipk = new(IssuerPublicKey)
 	key.Ipk.AttributeNames = AttributeNames
+	key.Ipk.HashAlgorithm = HashAlgorithm
 
 	W := GenG2.Mul(ISk)
 	key.Ipk.W = Ecp2ToProto(W)
@@ -91,7 +102,7 @@ func NewIssuerKey(AttributeNames []string, rng *amcl.RAND) (*IssuerKey, error) {
 	index = appendBytesG2(proofData, index, W)
 	index = appendBytesG1(proofData, index, BarG2)
 
-	proofC := HashModOrder(proofData)
+	proofC := key.Ipk.HashModOrder(proofData)
 	key.Ipk.ProofC = BigToBytes(proofC)
 
 	// Step 3: reply to the challenge message (s-values)
@@ -103,7 +114,7 @@ func NewIssuerKey(AttributeNames []string, rng *amcl.RAND) (*IssuerKey, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal issuer public key")
 	}
-	key.Ipk.Hash = BigToBytes(HashModOrder(serializedIPk))
+	key.Ipk.Hash = BigToBytes(key.Ipk.HashModOrder(serializedIPk))
 
 	// We are done
 	return key, nil
@@ -112,6 +123,10 @@ func NewIssuerKey(AttributeNames []string, rng *amcl.RAND) (*IssuerKey, error) {
 // Check checks that this issuer public key is valid, i.e.
 // that all components are present and a ZK proofs verifies
 func (IPk *IssuerPublicKey) Check() error {
+	if err := checkHashAlgorithm(IPk.GetHashAlgorithm()); err != nil {
+		return err
+	}
+
 	// Unmarshall the public key
 	NumAttrs := len(IPk.GetAttributeNames())
 	HSk := EcpFromProto(IPk.GetHSk())
@@ -164,7 +179,7 @@ func (IPk *IssuerPublicKey) Check() error {
 	index = appendBytesG1(proofData, index, BarG2)
 
 	// Verify that the challenge is the same
-	if *ProofC != *HashModOrder(proofData) {
+	if *ProofC != *IPk.HashModOrder(proofData) {
 		return errors.Errorf("zero knowledge proof in public key invalid")
 	}
 
@@ -178,6 +193,6 @@ func (IPk *IssuerPublicKey) SetHash() error {
 	if err != nil {
 		return errors.Wrap(err, "Failed to marshal issuer public key")
 	}
-	IPk.Hash = BigToBytes(HashModOrder(serializedIPk))
+	IPk.Hash = BigToBytes(IPk.HashModOrder(serializedIPk))
 	return nil
 }
@@ -50,13 +50,13 @@ func NewNymSignature(sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP256BN.BIG, ipk *
 	copy(proofData[index:], ipk.Hash)
 	index = index + FieldBytes
 	copy(proofData[index:], msg)
-	c := HashModOrder(proofData)
+	c := ipk.HashModOrder(proofData)
 	// combine the previous hash and the nonce and hash again to compute the final Fiat-Shamir value 'ProofC'
 	index = 0
 	proofData = proofData[:2*FieldBytes]
 	index = appendBytesBig(proofData, index, c)
 	index = appendBytesBig(proofData, index, Nonce)
-	ProofC := HashModOrder(proofData)
+	ProofC := ipk.HashModOrder(proofData)
 
 	// Step 3: reply to the challenge message (s-values)
 	ProofSSk := Modadd(rSk, FP256BN.Modmul(ProofC, sk, GroupOrder), GroupOrder)       // s_{sk} = r_{sk} + C \cdot sk
@@ -95,13 +95,13 @@ func (sig *NymSignature) Ver(nym *FP256BN.ECP, ipk *IssuerPublicKey, msg []byte)
 	copy(proofData[index:], ipk.Hash)
 	index = index + FieldBytes
 	copy(proofData[index:], msg)
-	c := HashModOrder(proofData)
+	c := ipk.HashModOrder(proofData)
 	index = 0
 	proofData = proofData[:2*FieldBytes]
 	index = appendBytesBig(proofData, index, c)
 	index = appendBytesBig(proofData, index, Nonce)
 
-	if *ProofC != *HashModOrder(proofData) {
+	if *ProofC != *ipk.HashModOrder(proofData) {
 		return errors.Errorf("pseudonym signature invalid: zero-knowledge proof is invalid")
 	}
 
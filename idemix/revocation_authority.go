@@ -12,6 +12,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm2"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-amcl/amcl"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
@@ -34,6 +35,24 @@ func GenerateLongTermRevocationKey() (*ecdsa.PrivateKey, error) {
 	return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
 }
 
+// GenerateLongTermSM2RevocationKey generates a long term SM2 signing key that will be used for revocation.
+// The key is returned as an ECDSA key on the SM2 curve, CRIs signed with it carry SM2 signatures.
+func GenerateLongTermSM2RevocationKey() (*ecdsa.PrivateKey, error) {
+	key, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: key.Curve, X: key.X, Y: key.Y},
+		D:         key.D,
+	}, nil
+}
+
+// IsSM2RevocationKey returns true if pk is a revocation public key on the SM2 curve
+func IsSM2RevocationKey(pk *ecdsa.PublicKey) bool {
+	return pk != nil && pk.Curve == sm2.P256Sm2()
+}
+
 // CreateCRI creates the Credential Revocation Information for a certain time period (epoch).
 // Users can use the CRI to prove that they are not revoked.
 // Note that when not using revocation (i.e., alg = ALG_NO_REVOCATION), the entered unrevokedHandles are not used,
@@ -61,9 +80,17 @@ func CreateCRI(key *ecdsa.PrivateKey, unrevokedHandles []*FP256BN.BIG, epoch int
 		return nil, errors.Wrap(err, "failed to marshal CRI")
 	}
 
-	digest := sha256.Sum256(bytesToSign)
-
-	cri.EpochPkSig, err = key.Sign(rand.Reader, digest[:], nil)
+	if IsSM2RevocationKey(&key.PublicKey) {
+		// SM2 signatures hash the signed bytes with SM3
+		sm2Key := &sm2.PrivateKey{
+			PublicKey: sm2.PublicKey{Curve: key.Curve, X: key.X, Y: key.Y},
+			D:         key.D,
+		}
+		cri.EpochPkSig, err = sm2Key.Sign(rand.Reader, bytesToSign, nil)
+	} else {
+		digest := sha256.Sum256(bytesToSign)
+		cri.EpochPkSig, err = key.Sign(rand.Reader, digest[:], nil)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +119,14 @@ func VerifyEpochPK(pk *ecdsa.PublicKey, epochPK *ECP2, epochPkSig []byte, epoch
 	if err != nil {
 		return err
 	}
+	if IsSM2RevocationKey(pk) {
+		sm2PK := &sm2.PublicKey{Curve: pk.Curve, X: pk.X, Y: pk.Y}
+		if !sm2PK.Verify(bytesToSign, epochPkSig) {
+			return errors.Errorf("EpochPKSig invalid")
+		}
+		return nil
+	}
+
 	digest := sha256.Sum256(bytesToSign)
 
 	r, s, err := utils.UnmarshalECDSASignature(epochPkSig)
@@ -187,14 +187,14 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 	copy(proofData[index:], Disclosure)
 	index = index + len(Disclosure)
 	copy(proofData[index:], msg)
-	c := HashModOrder(proofData)
+	c := ipk.HashModOrder(proofData)
 
 	// add the previous hash and the nonce and hash again to compute a second hash (C value)
 	index = 0
 	proofData = proofData[:2*FieldBytes]
 	index = appendBytesBig(proofData, index, c)
 	index = appendBytesBig(proofData, index, Nonce)
-	ProofC := HashModOrder(proofData)
+	ProofC := ipk.HashModOrder(proofData)
 
 	// Step 3: reply to the challenge message (s-values)
 	ProofSSk := Modadd(rSk, FP256BN.Modmul(ProofC, sk, GroupOrder), GroupOrder)             // s_sk = rSK + C \cdot sk
@@ -368,13 +368,13 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	index = index + len(Disclosure)
 	copy(proofData[index:], msg)
 
-	c := HashModOrder(proofData)
+	c := ipk.HashModOrder(proofData)
 	index = 0
 	proofData = proofData[:2*FieldBytes]
 	index = appendBytesBig(proofData, index, c)
 	index = appendBytesBig(proofData, index, Nonce)
 
-	if *ProofC != *HashModOrder(proofData) {
+	if *ProofC != *ipk.HashModOrder(proofData) {
 		// This debug line helps identify where the mismatch happened
 		idemixLogger.Debugf("Signature Verification : \n"+
 			"	[t1:%v]\n,"+
@@ -10,6 +10,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm3"
 	"github.com/hyperledger/fabric-amcl/amcl"
 	"github.com/hyperledger/fabric-amcl/amcl/FP256BN"
 	"github.com/pkg/errors"
@@ -43,14 +44,44 @@ func RandModOrder(rng *amcl.RAND) *FP256BN.BIG {
 	return FP256BN.Randomnum(q, rng)
 }
 
+// Hash algorithms of the Fiat-Shamir challenges of the issuer public keys
+const (
+	// HashAlgorithmSHA256 is the default hash algorithm, used when none is set
+	HashAlgorithmSHA256 = "SHA256"
+	// HashAlgorithmSM3 is the hash algorithm of the GM idemix mode
+	HashAlgorithmSM3 = "SM3"
+)
+
 // HashModOrder hashes data into 0, ..., GroupOrder-1
 func HashModOrder(data []byte) *FP256BN.BIG {
 	digest := sha256.Sum256(data)
-	digestBig := FP256BN.FromBytes(digest[:])
+	return digestModOrder(digest[:])
+}
+
+// HashModOrder hashes data into 0, ..., GroupOrder-1 with the hash algorithm
+// of this issuer public key
+func (IPk *IssuerPublicKey) HashModOrder(data []byte) *FP256BN.BIG {
+	if IPk.GetHashAlgorithm() == HashAlgorithmSM3 {
+		return digestModOrder(sm3.Sm3Sum(data))
+	}
+	return HashModOrder(data)
+}
+
+func digestModOrder(digest []byte) *FP256BN.BIG {
+	digestBig := FP256BN.FromBytes(digest)
 	digestBig.Mod(GroupOrder)
 	return digestBig
 }
 
+func checkHashAlgorithm(hashAlgorithm string) error {
+	switch hashAlgorithm {
+	case "", HashAlgorithmSHA256, HashAlgorithmSM3:
+		return nil
+	default:
+		return errors.Errorf("unsupported hash algorithm %s", hashAlgorithm)
+	}
+}
+
 func appendBytes(data []byte, index int, bytesToAdd []byte) int {
 	copy(data[index:], bytesToAdd)
 	return index + len(bytesToAdd)
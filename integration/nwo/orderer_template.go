@@ -97,6 +97,7 @@ Debug:
 Consensus:
   WALDir: {{ .OrdererDir Orderer }}/etcdraft/wal
   SnapDir: {{ .OrdererDir Orderer }}/etcdraft/snapshot
+  BFTLockDir: {{ .OrdererDir Orderer }}/bft/lock
   EvictionSuspicion: 10s
 Operations:
   ListenAddress: 127.0.0.1:{{ .OrdererPort Orderer "Operations" }}
@@ -71,55 +71,62 @@ func isECDSASignedCert(cert *x509.Certificate) bool {
 		cert.SignatureAlgorithm == x509.ECDSAWithSHA512
 }
 
+// isSM2WithSM3SignedCert returns whether cert is signed with SM2WithSM3.
+// The signature algorithm identifier alone is not enough, as the value of
+// x509GM.SM2WithSM3 collides with x509.PureEd25519.
 func isSM2WithSM3SignedCert(cert *x509.Certificate) bool {
-	return cert.SignatureAlgorithm == x509.SignatureAlgorithm(x509GM.SM2WithSM3)
-}
-
-func sanitizeSM2WithSM3SignedCert(cert *x509GM.Certificate, parentCert *x509GM.Certificate) (*x509.Certificate, error) {
-	if cert == nil {
-		return nil, errors.New("certificate must be different from nil")
-	}
-	if parentCert == nil {
-		return nil, errors.New("parent certificate must be different from nil")
+	if cert.SignatureAlgorithm != x509.SignatureAlgorithm(x509GM.SM2WithSM3) {
+		return false
 	}
+	c, err := certFromX509Cert(cert)
+	return err == nil && c.SignatureAlgorithm.Algorithm.Equal(oidSignatureSM2WithSM3)
+}
 
-	expectedSig, err := gm.SignatureToLowS(parentCert.PublicKey.(*ecdsa.PublicKey), cert.Signature)
-	if err != nil {
-		return nil, err
-	}
+var (
+	oidNamedCurveSM2       = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301}
+	oidSignatureSM2WithSM3 = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 501}
+)
 
-	// if sig == cert.Signature, nothing needs to be done
-	if bytes.Equal(cert.Signature, expectedSig) {
-		return gm.ParseSm2Certificate2X509(cert), nil
+// parseCertificate parses a DER encoded certificate according to the
+// algorithms of the certificate itself rather than to the crypto suite of
+// the MSP, so that identities of organizations using ECDSA and of
+// organizations using SM2 can be handled side by side. Certificates with a
+// key on the SM2 curve, or signed with SM2, are parsed with the GM x509
+// package, which the standard one cannot do.
+func parseCertificate(der []byte) (*x509.Certificate, error) {
+	var c certificate
+	if _, err := asn1.Unmarshal(der, &c); err != nil || !isSM2Certificate(c) {
+		return x509.ParseCertificate(der)
 	}
-	// otherwise create a new certificate with the new signature
 
-	// 1. Unmarshal cert.Raw to get an instance of certificate,
-	//    the lower level interface that represent an x509 certificate
-	//    encoding
-	var newCert certificate
-	newCert, err = certFromX509GMCert(cert)
+	sm2Cert, err := x509GM.ParseCertificate(der)
 	if err != nil {
 		return nil, err
 	}
+	return gm.ParseSm2Certificate2X509(sm2Cert), nil
+}
 
-	// 2. Change the signature
-	newCert.SignatureValue = asn1.BitString{Bytes: expectedSig, BitLength: len(expectedSig) * 8}
-
-	// 3. marshal again newCert. Raw must be nil
-	newCert.Raw = nil
-	newRaw, err := asn1.Marshal(newCert)
-	if err != nil {
-		return nil, errors.Wrap(err, "marshalling of the certificate failed")
+// parseCRL parses a PEM or DER encoded CRL according to its signature
+// algorithm rather than to the crypto suite of the MSP, as the revocation
+// lists of an MSP may be issued by ECDSA as well as by SM2 CAs.
+func parseCRL(crlBytes []byte) (*pkix.CertificateList, error) {
+	crl, err := x509.ParseCRL(crlBytes)
+	if err != nil || crl.SignatureAlgorithm.Algorithm.Equal(oidSignatureSM2WithSM3) {
+		return x509GM.ParseCRL(crlBytes)
 	}
+	return crl, nil
+}
 
-	sm2Cert, err := x509GM.ParseCertificate(newRaw)
-	if err != nil {
-		return nil, err
+func isSM2Certificate(c certificate) bool {
+	if c.SignatureAlgorithm.Algorithm.Equal(oidSignatureSM2WithSM3) {
+		return true
 	}
 
-	// 4. parse newRaw to get an x509 certificate
-	return gm.ParseSm2Certificate2X509(sm2Cert), nil
+	var namedCurve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(c.TBSCertificate.PublicKey.Algorithm.Parameters.FullBytes, &namedCurve); err != nil {
+		return false
+	}
+	return namedCurve.Equal(oidNamedCurveSM2)
 }
 
 // sanitizeECDSASignedCert checks that the signatures signing a cert
@@ -177,16 +184,6 @@ func certFromX509Cert(cert *x509.Certificate) (certificate, error) {
 	return newCert, nil
 }
 
-
-func certFromX509GMCert(cert *x509GM.Certificate) (certificate, error) {
-	var newCert certificate
-	_, err := asn1.Unmarshal(cert.Raw, &newCert)
-	if err != nil {
-		return certificate{}, errors.Wrap(err, "unmarshalling of the certificate failed")
-	}
-	return newCert, nil
-}
-
 // String returns a PEM representation of a certificate
 func (c certificate) String() string {
 	b, err := asn1.Marshal(c)
@@ -152,6 +152,15 @@ func SetupBCCSPKeystoreConfig(bccspConfig *factory.FactoryOpts, keystoreDir stri
 		}
 	}
 
+	if bccspConfig.ProviderName == "GM" && bccspConfig.GmOpts != nil {
+		// Only override the KeyStorePath if it was left empty
+		if bccspConfig.GmOpts.FileKeystore == nil ||
+			bccspConfig.GmOpts.FileKeystore.KeyStorePath == "" {
+			bccspConfig.GmOpts.Ephemeral = false
+			bccspConfig.GmOpts.FileKeystore = &factory.FileKeystoreOpts{KeyStorePath: keystoreDir}
+		}
+	}
+
 	return bccspConfig
 }
 
@@ -35,6 +35,9 @@ type identity struct {
 	// this is the public key of this instance
 	pk bccsp.Key
 
+	// csp is the crypto provider handling the algorithm of pk
+	csp bccsp.BCCSP
+
 	// reference to the MSP that "owns" this identity
 	msp *bccspmsp
 }
@@ -58,7 +61,12 @@ func newIdentity(cert *x509.Certificate, pk bccsp.Key, msp *bccspmsp) (Identity,
 		return nil, errors.WithMessage(err, "failed getting hash function options")
 	}
 
-	digest, err := msp.bccsp.Hash(cert.Raw, hashOpt)
+	hashCSP, err := msp.getCSPForHash(hashOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := hashCSP.Hash(cert.Raw, hashOpt)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed hashing raw certificate to compute the id of the IdentityIdentifier")
 	}
@@ -67,7 +75,12 @@ func newIdentity(cert *x509.Certificate, pk bccsp.Key, msp *bccspmsp) (Identity,
 		Mspid: msp.name,
 		Id:    hex.EncodeToString(digest)}
 
-	return &identity{id: id, cert: cert, pk: pk, msp: msp}, nil
+	csp, err := msp.getCSPForCert(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity{id: id, cert: cert, pk: pk, csp: csp, msp: msp}, nil
 }
 
 // ExpiresAt returns the time at which the Identity expires.
@@ -162,7 +175,12 @@ func (id *identity) Verify(msg []byte, sig []byte) error {
 		return errors.WithMessage(err, "failed getting hash function options")
 	}
 
-	digest, err := id.msp.bccsp.Hash(msg, hashOpt)
+	hashCSP, err := id.msp.getCSPForHash(hashOpt)
+	if err != nil {
+		return err
+	}
+
+	digest, err := hashCSP.Hash(msg, hashOpt)
 	if err != nil {
 		return errors.WithMessage(err, "failed computing digest")
 	}
@@ -172,7 +190,7 @@ func (id *identity) Verify(msg []byte, sig []byte) error {
 		mspIdentityLogger.Debugf("Verify: sig = %s", hex.Dump(sig))
 	}
 
-	valid, err := id.msp.bccsp.Verify(id.pk, sig, digest, nil)
+	valid, err := id.csp.Verify(id.pk, sig, digest, nil)
 	if err != nil {
 		return errors.WithMessage(err, "could not determine the validity of the signature")
 	} else if !valid {
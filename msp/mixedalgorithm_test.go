@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/bccsp/gm"
+	"github.com/hyperledger/fabric/msp/testutil"
+	m "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mspConfig returns the configuration of the FABRIC MSP of the organization
+func mspConfig(t *testing.T, org *testutil.MixedAlgorithmOrg) *m.MSPConfig {
+	return &m.MSPConfig{Config: protoMarshal(t, org.FabricMSPConfig()), Type: int32(FABRIC)}
+}
+
+// revokeMember returns the PEM encoded CRL, signed by the CA of the
+// organization, revoking the certificate of its member.
+func revokeMember(t *testing.T, org *testutil.MixedAlgorithmOrg) []byte {
+	bl, _ := pem.Decode(org.MemberCertPEM)
+	cert, err := parseCertificate(bl.Bytes)
+	require.NoError(t, err)
+
+	revoked := []pkix.RevokedCertificate{{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()}}
+	var crl []byte
+	if org.GM {
+		crl, err = gm.ParseX509Certificate2Sm2(org.CA.SignCert).CreateCRL(rand.Reader, org.CA.Signer, revoked, time.Now(), time.Now().Add(time.Hour))
+	} else {
+		crl, err = org.CA.SignCert.CreateCRL(rand.Reader, org.CA.Signer, revoked, time.Now(), time.Now().Add(time.Hour))
+	}
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crl})
+}
+
+func TestParseCertificateByAlgorithm(t *testing.T) {
+	dir, err := ioutil.TempDir("", "msp-mixed-algorithms")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ecdsaOrg := testutil.NewMixedAlgorithmOrg(t, dir, "ECDSAOrg", false)
+	sm2Org := testutil.NewMixedAlgorithmOrg(t, dir, "SM2Org", true)
+
+	bl, _ := pem.Decode(ecdsaOrg.MemberCertPEM)
+	cert, err := parseCertificate(bl.Bytes)
+	assert.NoError(t, err)
+	assert.True(t, isECDSASignedCert(cert))
+	assert.False(t, isSM2WithSM3SignedCert(cert))
+
+	bl, _ = pem.Decode(sm2Org.MemberCertPEM)
+	_, err = x509.ParseCertificate(bl.Bytes)
+	assert.Error(t, err)
+	cert, err = parseCertificate(bl.Bytes)
+	assert.NoError(t, err)
+	assert.False(t, isECDSASignedCert(cert))
+	assert.True(t, isSM2WithSM3SignedCert(cert))
+
+	// An Ed25519 signature algorithm shares its value with SM2WithSM3
+	assert.False(t, isSM2WithSM3SignedCert(&x509.Certificate{SignatureAlgorithm: x509.PureEd25519}))
+
+	_, err = parseCertificate([]byte("garbage"))
+	assert.Error(t, err)
+}
+
+func TestMixedAlgorithmMSPs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "msp-mixed-algorithms")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	orgs := []*testutil.MixedAlgorithmOrg{
+		testutil.NewMixedAlgorithmOrg(t, dir, "ECDSAOrg", false),
+		testutil.NewMixedAlgorithmOrg(t, dir, "SM2Org", true),
+	}
+	msg := []byte("Hello World")
+
+	swCSP, err := (&factory.SWFactory{}).Get(factory.GetDefaultOpts())
+	require.NoError(t, err)
+	gmCSP, err := (&factory.GMFactory{}).Get(factory.GetGMOpts())
+	require.NoError(t, err)
+
+	// Whatever the local crypto suite, the identities of both organizations
+	// are validated and their signatures verified
+	for name, localCSP := range map[string]bccsp.BCCSP{"SW": swCSP, "GM": gmCSP} {
+		t.Run(name, func(t *testing.T) {
+			for _, org := range orgs {
+				thisMSP, err := newBccspMsp(MSPv1_3)
+				require.NoError(t, err)
+				thisMSP.(*bccspmsp).bccsp = localCSP
+				err = thisMSP.Setup(mspConfig(t, org))
+				require.NoError(t, err, "failed setting up MSP [%s]", org.Name)
+
+				serialized, err := NewSerializedIdentity(org.Name, org.MemberCertPEM)
+				require.NoError(t, err)
+				id, err := thisMSP.DeserializeIdentity(serialized)
+				require.NoError(t, err, "failed deserializing identity of [%s]", org.Name)
+
+				assert.NoError(t, id.Validate())
+				assert.NoError(t, id.Verify(msg, org.Sign(t, msg)))
+				assert.Error(t, id.Verify([]byte("Hello World!"), org.Sign(t, msg)))
+
+				principal := &m.MSPPrincipal{
+					PrincipalClassification: m.MSPPrincipal_ROLE,
+					Principal:               protoMarshal(t, &m.MSPRole{MspIdentifier: org.Name, Role: m.MSPRole_MEMBER}),
+				}
+				assert.NoError(t, id.SatisfiesPrincipal(principal))
+			}
+		})
+	}
+}
+
+func TestMixedAlgorithmRevocationLists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "msp-mixed-algorithms")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ecdsaOrg := testutil.NewMixedAlgorithmOrg(t, dir, "ECDSAOrg", false)
+	sm2Org := testutil.NewMixedAlgorithmOrg(t, dir, "SM2Org", true)
+
+	ecdsaCRL := revokeMember(t, ecdsaOrg)
+	crl, err := parseCRL(ecdsaCRL)
+	assert.NoError(t, err)
+	assert.False(t, crl.SignatureAlgorithm.Algorithm.Equal(oidSignatureSM2WithSM3))
+	sm2CRL := revokeMember(t, sm2Org)
+	crl, err = parseCRL(sm2CRL)
+	assert.NoError(t, err)
+	assert.True(t, crl.SignatureAlgorithm.Algorithm.Equal(oidSignatureSM2WithSM3))
+	_, err = parseCRL([]byte("garbage"))
+	assert.Error(t, err)
+
+	swCSP, err := (&factory.SWFactory{}).Get(factory.GetDefaultOpts())
+	require.NoError(t, err)
+	gmCSP, err := (&factory.GMFactory{}).Get(factory.GetGMOpts())
+	require.NoError(t, err)
+
+	// Whatever the local crypto suite, the revocation lists of both
+	// organizations are parsed and their signatures verified
+	for name, localCSP := range map[string]bccsp.BCCSP{"SW": swCSP, "GM": gmCSP} {
+		t.Run(name, func(t *testing.T) {
+			for _, org := range []*testutil.MixedAlgorithmOrg{ecdsaOrg, sm2Org} {
+				fabricConf := org.FabricMSPConfig()
+				fabricConf.RevocationList = [][]byte{ecdsaCRL, sm2CRL}
+
+				thisMSP, err := newBccspMsp(MSPv1_3)
+				require.NoError(t, err)
+				thisMSP.(*bccspmsp).bccsp = localCSP
+				err = thisMSP.Setup(&m.MSPConfig{Config: protoMarshal(t, fabricConf), Type: int32(FABRIC)})
+				require.NoError(t, err, "failed setting up MSP [%s]", org.Name)
+
+				serialized, err := NewSerializedIdentity(org.Name, org.MemberCertPEM)
+				require.NoError(t, err)
+				id, err := thisMSP.DeserializeIdentity(serialized)
+				require.NoError(t, err, "failed deserializing identity of [%s]", org.Name)
+
+				err = id.Validate()
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "The certificate has been revoked")
+			}
+		})
+	}
+}
+
+func protoMarshal(t *testing.T, msg proto.Message) []byte {
+	raw, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return raw
+}
@@ -178,24 +178,46 @@ func (msp *bccspmsp) getCertFromPem(idBytes []byte) (*x509.Certificate, error) {
 	}
 
 	// get a cert
-	var err error
-	var cert *x509.Certificate
-	if bccsp.IsGMCryptoSuite(msp.bccsp) {
-		sm2Cert, err := x509GM.ParseCertificate(pemCert.Bytes)
-		if err != nil {
-			return nil, errors.Wrap(err, "getCertFromPem error: failed to parse gm x509 cert")
-		}
-		cert = gm.ParseSm2Certificate2X509(sm2Cert)
-	} else {
-		cert, err = x509.ParseCertificate(pemCert.Bytes)
-		if err != nil {
-			return nil, errors.Wrap(err, "getCertFromPem error: failed to parse x509 cert")
-		}
+	cert, err := parseCertificate(pemCert.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "getCertFromPem error: failed to parse x509 cert")
 	}
 
 	return cert, nil
 }
 
+// getCSPForCert returns the BCCSP handling the key of cert. This is the
+// BCCSP of this MSP, unless the key uses another algorithm than the one of
+// the BCCSP, as do the keys of the organizations of a channel that use
+// another crypto suite than the local one.
+func (msp *bccspmsp) getCSPForCert(cert *x509.Certificate) (bccsp.BCCSP, error) {
+	csp, err := factory.GetBCCSPForPublicKey(msp.bccsp, cert.PublicKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed getting the BCCSP for the certificate's public key")
+	}
+	return csp, nil
+}
+
+// getCSPForHash returns the BCCSP computing the hash function of opts,
+// which is the BCCSP of this MSP unless it does not implement it.
+func (msp *bccspmsp) getCSPForHash(opts bccsp.HashOpts) (bccsp.BCCSP, error) {
+	csp, err := factory.GetBCCSPForHash(msp.bccsp, opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed getting the BCCSP for the hash function")
+	}
+	return csp, nil
+}
+
+// importCertPublicKey imports the public key of cert with the BCCSP
+// handling its algorithm.
+func (msp *bccspmsp) importCertPublicKey(cert *x509.Certificate) (bccsp.Key, error) {
+	csp, err := msp.getCSPForCert(cert)
+	if err != nil {
+		return nil, err
+	}
+	return csp.KeyImport(cert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+}
+
 func (msp *bccspmsp) getIdentityFromConf(idBytes []byte) (Identity, bccsp.Key, error) {
 	// get a cert
 	cert, err := msp.getCertFromPem(idBytes)
@@ -204,7 +226,7 @@ func (msp *bccspmsp) getIdentityFromConf(idBytes []byte) (Identity, bccsp.Key, e
 	}
 
 	// get the public key in the right format
-	certPubK, err := msp.bccsp.KeyImport(cert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+	certPubK, err := msp.importCertPublicKey(cert)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -420,20 +442,9 @@ func (msp *bccspmsp) deserializeIdentityInternal(serializedIdentity []byte) (Ide
 		return nil, errors.New("could not decode the PEM structure")
 	}
 
-	var err error
-	var cert *x509.Certificate
-
-	if bccsp.IsGMCryptoSuite(msp.bccsp) {
-		sm2Cert, err := x509GM.ParseCertificate(bl.Bytes)
-		if err != nil {
-			return nil, errors.Wrap(err, "parseGMCertificate failed")
-		}
-		cert = gm.ParseSm2Certificate2X509(sm2Cert)
-	} else {
-		cert, err = x509.ParseCertificate(bl.Bytes)
-		if err != nil {
-			return nil, errors.Wrap(err, "parseCertificate failed")
-		}
+	cert, err := parseCertificate(bl.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "parseCertificate failed")
 	}
 
 	// Now we have the certificate; make sure that its fields
@@ -443,7 +454,7 @@ func (msp *bccspmsp) deserializeIdentityInternal(serializedIdentity []byte) (Ide
 	// We can't do it yet because there is no standardized way
 	// (yet) to encode the MSP ID into the x.509 body of a cert
 
-	pub, err := msp.bccsp.KeyImport(cert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+	pub, err := msp.importCertPublicKey(cert)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to import certificate's public key")
 	}
@@ -843,7 +854,12 @@ func (msp *bccspmsp) getCertificationChainIdentifierFromChain(chain []*x509.Cert
 		return nil, errors.WithMessage(err, "failed getting hash function options")
 	}
 
-	hf, err := msp.bccsp.GetHash(hashOpt)
+	csp, err := msp.getCSPForHash(hashOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	hf, err := csp.GetHash(hashOpt)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed getting hash function when computing certification chain identifier")
 	}
@@ -880,25 +896,12 @@ func (msp *bccspmsp) sanitizeCert(cert *x509.Certificate) (*x509.Certificate, er
 			return nil, err
 		}
 	} else if isSM2WithSM3SignedCert(cert) {
-		var parentCert *x509GM.Certificate
+		// Unlike ECDSA signatures, SM2 signatures have no malleable
+		// counterpart: replacing s with N-s does not yield a valid
+		// signature. There is nothing to regenerate, the certificate is
+		// only required to chain up to this MSP as ECDSA ones are.
 		sm2Cert := gm.ParseX509Certificate2Sm2(cert)
-		chain, err := msp.getGMUniqueValidationChain(sm2Cert, msp.getValidityOptsForGMCert(sm2Cert))
-		if err != nil {
-			return nil, err
-		}
-
-		// at this point, cert might be a root CA certificate
-		// or an intermediate CA certificate
-		if cert.IsCA && len(chain) == 1 {
-			// cert is a root CA certificate
-			parentCert = sm2Cert
-		} else {
-			parentCert = gm.ParseX509Certificate2Sm2(chain[1])
-		}
-
-		// Sanitize
-		cert, err = sanitizeSM2WithSM3SignedCert(sm2Cert, parentCert)
-		if err != nil {
+		if _, err := msp.getGMUniqueValidationChain(sm2Cert, msp.getValidityOptsForGMCert(sm2Cert)); err != nil {
 			return nil, err
 		}
 	}
@@ -926,15 +929,9 @@ func (msp *bccspmsp) IsWellFormed(identity *m.SerializedIdentity) error {
 		return errors.Errorf("pem type is %s, should be 'CERTIFICATE' or missing", bl.Type)
 	}
 
-	var cert *x509.Certificate
-	sm2Cert, err := x509GM.ParseCertificate(bl.Bytes)
+	cert, err := parseCertificate(bl.Bytes)
 	if err != nil {
-		cert, err = x509.ParseCertificate(bl.Bytes)
-		if err != nil {
-			return err
-		}
-	} else {
-		cert = gm.ParseSm2Certificate2X509(sm2Cert)
+		return err
 	}
 
 	if !isECDSASignedCert(cert) && !isSM2WithSM3SignedCert(cert) {
@@ -83,7 +83,18 @@ func (msp *bccspmsp) getCertifiersIdentifier(certRaw []byte) ([]byte, error) {
 func (msp *bccspmsp) setupCrypto(conf *m.FabricMSPConfig) error {
 	var signatureHashFamily, identityIdentifierHashFunction string
 
-	if bccsp.IsGMCryptoSuite(msp.bccsp) {
+	// The defaults follow the algorithm of the root CA certificate, not the
+	// local crypto suite, so that all nodes of a channel hosting both ECDSA
+	// and SM2 organizations agree on them. The local crypto suite is only
+	// used when no root CA certificate can tell.
+	gmMSP := bccsp.IsGMCryptoSuite(msp.bccsp)
+	if len(conf.RootCerts) > 0 {
+		if cert, err := msp.getCertFromPem(conf.RootCerts[0]); err == nil {
+			gmMSP = gm.IsX509SM2Certificate(cert) || isSM2WithSM3SignedCert(cert)
+		}
+	}
+
+	if gmMSP {
 		signatureHashFamily = bccsp.GMSM3
 		identityIdentifierHashFunction = bccsp.GMSM3
 	} else {
@@ -254,14 +265,7 @@ func (msp *bccspmsp) setupCRLs(conf *m.FabricMSPConfig) error {
 	// setup the CRL (if present)
 	msp.CRL = make([]*pkix.CertificateList, len(conf.RevocationList))
 	for i, crlbytes := range conf.RevocationList {
-		var err error
-		var crl *pkix.CertificateList
-
-		if bccsp.IsGMCryptoSuite(msp.bccsp) {
-			crl, err = x509GM.ParseCRL(crlbytes)
-		} else {
-			crl, err = x509.ParseCRL(crlbytes)
-		}
+		crl, err := parseCRL(crlbytes)
 		if err != nil {
 			return errors.Wrap(err, "could not parse RevocationList")
 		}
@@ -130,7 +130,11 @@ func (msp *bccspmsp) validateCertAgainstChain(cert *x509.Certificate, validation
 					// certificate that is under validation. As a
 					// precaution, we verify that said CA is also the
 					// signer of this CRL.
-					err = validationChain[1].CheckCRLSignature(crl)
+					if gm.IsX509SM2Certificate(validationChain[1]) {
+						err = gm.ParseX509Certificate2Sm2(validationChain[1]).CheckCRLSignature(crl)
+					} else {
+						err = validationChain[1].CheckCRLSignature(crl)
+					}
 					if err != nil {
 						// the CA cert that signed the certificate
 						// that is under validation did not sign the
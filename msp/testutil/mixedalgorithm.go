@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"path/filepath"
+	"testing"
+
+	"github.com/Hyperledger-TWGC/tjfoc-gm/sm3"
+	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
+	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
+	"github.com/hyperledger/fabric/common/tools/cryptogen/csp"
+	m "github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/require"
+)
+
+// MixedAlgorithmOrg is an organization whose CA and member use SM2 when GM
+// is true, ECDSA otherwise
+type MixedAlgorithmOrg struct {
+	Name string
+	CA   *ca.CA
+	GM   bool
+	// MemberCertPEM is the PEM encoded certificate of the member
+	MemberCertPEM []byte
+
+	signer crypto.Signer
+	digest func(msg []byte) []byte
+}
+
+// NewMixedAlgorithmOrg generates the CA and a member of an organization
+// under dir, using SM2 when gmOrg is true, ECDSA otherwise
+func NewMixedAlgorithmOrg(t *testing.T, dir, name string, gmOrg bool) *MixedAlgorithmOrg {
+	caDir := filepath.Join(dir, name, "ca")
+	memberDir := filepath.Join(dir, name, "member")
+	org := &MixedAlgorithmOrg{Name: name, GM: gmOrg}
+
+	var cert *x509.Certificate
+	if gmOrg {
+		var err error
+		org.CA, err = ca.NewGMCA(caDir, name, "ca."+name, "", "", "", "", "", "")
+		require.NoError(t, err)
+		priv, signer, err := csp.GenerateSM2PrivateKey(memberDir)
+		require.NoError(t, err)
+		pub, err := csp.GetSM2PublicKey(priv)
+		require.NoError(t, err)
+		cert, err = org.CA.SignGMCertificate(memberDir, "member."+name, nil, nil, pub, x509GM.KeyUsageDigitalSignature, nil)
+		require.NoError(t, err)
+		org.signer = signer
+		org.digest = sm3.Sm3Sum
+	} else {
+		var err error
+		org.CA, err = ca.NewCA(caDir, name, "ca."+name, "", "", "", "", "", "")
+		require.NoError(t, err)
+		priv, signer, err := csp.GeneratePrivateKey(memberDir)
+		require.NoError(t, err)
+		pub, err := csp.GetECPublicKey(priv)
+		require.NoError(t, err)
+		cert, err = org.CA.SignCertificate(memberDir, "member."+name, nil, nil, pub, x509.KeyUsageDigitalSignature, nil)
+		require.NoError(t, err)
+		org.signer = signer
+		org.digest = func(msg []byte) []byte {
+			digest := sha256.Sum256(msg)
+			return digest[:]
+		}
+	}
+	org.MemberCertPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+
+	return org
+}
+
+// FabricMSPConfig returns the MSP configuration of the organization. It holds
+// no crypto configuration, so that the defaults follow the algorithm of the CA
+func (org *MixedAlgorithmOrg) FabricMSPConfig() *m.FabricMSPConfig {
+	return &m.FabricMSPConfig{
+		Name:      org.Name,
+		RootCerts: [][]byte{pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: org.CA.SignCert.Raw})},
+	}
+}
+
+// Sign signs msg as the member of the organization
+func (org *MixedAlgorithmOrg) Sign(t *testing.T, msg []byte) []byte {
+	sig, err := org.signer.Sign(rand.Reader, org.digest(msg), nil)
+	require.NoError(t, err)
+	return sig
+}
@@ -7,11 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package blockcutter
 
 import (
+	"sort"
 	"time"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 )
 
 var logger = flogging.MustGetLogger("orderer.common.blockcutter")
@@ -27,14 +29,25 @@ type Receiver interface {
 	// `pending` indicates if there are still messages pending in the receiver.
 	Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool)
 
-	// Cut returns the current batch and starts a new one
+	// Cut returns the current batch and starts a new one.
+	// The messages of the batch are ordered by priority lane.
 	Cut() []*cb.Envelope
+
+	// MaxPendingAge returns how long the current batch may still wait before it is cut,
+	// as the max pending age of the priority lane of one of its messages expires.
+	// It returns false if none of the pending messages has a max pending age.
+	MaxPendingAge() (time.Duration, bool)
 }
 
 type receiver struct {
 	sharedConfigFetcher   OrdererConfigFetcher
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
+	pendingBatchLanes     []int
+	pendingBatchDeadline  time.Time // when the first max pending age of the pending messages expires, if any
+
+	batchPriority *ab.BatchPriority
+	lanes         lanes
 
 	PendingBatchStartTime time.Time
 	ChannelID             string
@@ -67,9 +80,10 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 //
 // Note that messageBatches can not be greater than 2.
 func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool) {
+	now := time.Now()
 	if len(r.pendingBatch) == 0 {
 		// We are beginning a new batch, mark the time
-		r.PendingBatchStartTime = time.Now()
+		r.PendingBatchStartTime = now
 	}
 
 	ordererConfig, ok := r.sharedConfigFetcher.OrdererConfig()
@@ -79,6 +93,11 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 
 	batchSize := ordererConfig.BatchSize()
 
+	if batchPriority := ordererConfig.BatchPriority(); batchPriority != r.batchPriority {
+		r.batchPriority = batchPriority
+		r.lanes = newLanes(batchPriority)
+	}
+
 	messageSizeBytes := messageSizeBytes(msg)
 	if messageSizeBytes > batchSize.PreferredMaxBytes {
 		logger.Debugf("The current message, with %v bytes, is larger than the preferred batch size of %v bytes and will be isolated.", messageSizeBytes, batchSize.PreferredMaxBytes)
@@ -109,8 +128,16 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	}
 
 	logger.Debugf("Enqueuing message into batch")
+	lane := r.lanes.match(msg)
 	r.pendingBatch = append(r.pendingBatch, msg)
 	r.pendingBatchSizeBytes += messageSizeBytes
+	r.pendingBatchLanes = append(r.pendingBatchLanes, lane)
+	if lane < len(r.lanes) && r.lanes[lane].maxPendingAge > 0 {
+		deadline := now.Add(r.lanes[lane].maxPendingAge)
+		if r.pendingBatchDeadline.IsZero() || deadline.Before(r.pendingBatchDeadline) {
+			r.pendingBatchDeadline = deadline
+		}
+	}
 	pending = true
 
 	if uint32(len(r.pendingBatch)) >= batchSize.MaxMessageCount {
@@ -128,11 +155,42 @@ func (r *receiver) Cut() []*cb.Envelope {
 	r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(time.Since(r.PendingBatchStartTime).Seconds())
 	r.PendingBatchStartTime = time.Time{}
 	batch := r.pendingBatch
+	if len(r.lanes) > 0 {
+		sort.Stable(&byLane{batch: batch, lanes: r.pendingBatchLanes})
+	}
 	r.pendingBatch = nil
 	r.pendingBatchSizeBytes = 0
+	r.pendingBatchLanes = nil
+	r.pendingBatchDeadline = time.Time{}
 	return batch
 }
 
+// MaxPendingAge returns the time left until the first max pending age of the pending
+// messages expires, measured from when each of them was ordered.
+func (r *receiver) MaxPendingAge() (time.Duration, bool) {
+	if r.pendingBatchDeadline.IsZero() {
+		return 0, false
+	}
+	if left := time.Until(r.pendingBatchDeadline); left > 0 {
+		return left, true
+	}
+	return 0, true
+}
+
+// byLane sorts the messages of a batch by priority lane,
+// keeping the order in which the messages of a lane were received.
+type byLane struct {
+	batch []*cb.Envelope
+	lanes []int
+}
+
+func (b *byLane) Len() int           { return len(b.batch) }
+func (b *byLane) Less(i, j int) bool { return b.lanes[i] < b.lanes[j] }
+func (b *byLane) Swap(i, j int) {
+	b.batch[i], b.batch[j] = b.batch[j], b.batch[i]
+	b.lanes[i], b.lanes[j] = b.lanes[j], b.lanes[i]
+}
+
 func messageSizeBytes(message *cb.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
@@ -7,13 +7,17 @@ SPDX-License-Identifier: Apache-2.0
 package blockcutter_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 var _ = Describe("Blockcutter", func() {
@@ -183,4 +187,135 @@ var _ = Describe("Blockcutter", func() {
 			})
 		})
 	})
+
+	Describe("Cut", func() {
+		var (
+			bulk, config, org2, malformed *cb.Envelope
+		)
+
+		BeforeEach(func() {
+			fakeConfig.BatchSizeReturns(&ab.BatchSize{
+				MaxMessageCount:   10,
+				PreferredMaxBytes: 1000,
+			})
+			fakeConfig.BatchPriorityReturns(&ab.BatchPriority{
+				Lanes: []*ab.PriorityLane{
+					{Name: "config", HeaderTypes: []string{"ORDERER_TRANSACTION"}},
+					{Name: "org2", HeaderTypes: []string{"ENDORSER_TRANSACTION"}, MspIds: []string{"Org2MSP"}},
+				},
+			})
+
+			bulk = newEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP")
+			config = newEnvelope(cb.HeaderType_ORDERER_TRANSACTION, "OrdererMSP")
+			org2 = newEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP")
+			malformed = &cb.Envelope{Payload: []byte("garbage")}
+		})
+
+		It("orders the messages of the batch by priority lane", func() {
+			for _, msg := range []*cb.Envelope{bulk, org2, malformed, config, bulk, org2} {
+				_, pending := bc.Ordered(msg)
+				Expect(pending).To(BeTrue())
+			}
+
+			Expect(bc.Cut()).To(Equal([]*cb.Envelope{config, org2, org2, bulk, malformed, bulk}))
+			Expect(bc.Cut()).To(BeEmpty())
+		})
+
+		Context("when the batch is cut by the max message count", func() {
+			BeforeEach(func() {
+				fakeConfig.BatchSizeReturns(&ab.BatchSize{
+					MaxMessageCount:   3,
+					PreferredMaxBytes: 1000,
+				})
+			})
+
+			It("orders the messages of the batch by priority lane", func() {
+				bc.Ordered(bulk)
+				bc.Ordered(bulk)
+				batches, pending := bc.Ordered(org2)
+				Expect(pending).To(BeFalse())
+				Expect(batches).To(Equal([][]*cb.Envelope{{org2, bulk, bulk}}))
+			})
+		})
+
+		Context("when there are no priority lanes", func() {
+			BeforeEach(func() {
+				fakeConfig.BatchPriorityReturns(nil)
+			})
+
+			It("keeps the order of the messages", func() {
+				for _, msg := range []*cb.Envelope{bulk, org2, config} {
+					bc.Ordered(msg)
+				}
+				Expect(bc.Cut()).To(Equal([]*cb.Envelope{bulk, org2, config}))
+			})
+		})
+	})
+
+	Describe("MaxPendingAge", func() {
+		BeforeEach(func() {
+			fakeConfig.BatchSizeReturns(&ab.BatchSize{
+				MaxMessageCount:   10,
+				PreferredMaxBytes: 1000,
+			})
+			fakeConfig.BatchTimeoutReturns(2 * time.Second)
+			fakeConfig.BatchPriorityReturns(&ab.BatchPriority{
+				Lanes: []*ab.PriorityLane{
+					{Name: "fast", MspIds: []string{"Org2MSP"}, MaxPendingAge: "100ms"},
+					{Name: "slow", MspIds: []string{"Org3MSP"}, MaxPendingAge: "1m"},
+					{Name: "ordered", MspIds: []string{"Org4MSP"}},
+				},
+			})
+		})
+
+		It("returns nothing when none of the pending messages has a max pending age", func() {
+			_, ok := bc.MaxPendingAge()
+			Expect(ok).To(BeFalse())
+
+			bc.Ordered(newEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org4MSP"))
+			bc.Ordered(newEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP"))
+			_, ok = bc.MaxPendingAge()
+			Expect(ok).To(BeFalse())
+			Expect(blockcutter.PendingBatchTimeout(fakeConfig, bc)).To(Equal(2 * time.Second))
+		})
+
+		It("returns the time left until the first max pending age of the pending messages expires", func() {
+			bc.Ordered(newEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org3MSP"))
+			maxPendingAge, ok := bc.MaxPendingAge()
+			Expect(ok).To(BeTrue())
+			Expect(maxPendingAge).To(BeNumerically("~", time.Minute, time.Second))
+			Expect(blockcutter.PendingBatchTimeout(fakeConfig, bc)).To(Equal(2 * time.Second))
+
+			bc.Ordered(newEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP"))
+			maxPendingAge, ok = bc.MaxPendingAge()
+			Expect(ok).To(BeTrue())
+			Expect(maxPendingAge).To(BeNumerically("~", 100*time.Millisecond, 50*time.Millisecond))
+
+			// the max pending age is measured from when the message was ordered
+			time.Sleep(60 * time.Millisecond)
+			bc.Ordered(newEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP"))
+			Expect(blockcutter.PendingBatchTimeout(fakeConfig, bc)).To(BeNumerically("<=", 40*time.Millisecond))
+
+			bc.Cut()
+			_, ok = bc.MaxPendingAge()
+			Expect(ok).To(BeFalse())
+		})
+	})
 })
+
+func newEnvelope(headerType cb.HeaderType, mspID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(headerType),
+					ChannelId: "mychannel",
+				}),
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{
+					Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(mspID)}),
+				}),
+			},
+			Data: []byte(mspID),
+		}),
+	}
+}
@@ -10,6 +10,16 @@ import (
 )
 
 type OrdererConfig struct {
+	BatchPriorityStub        func() *orderer.BatchPriority
+	batchPriorityMutex       sync.RWMutex
+	batchPriorityArgsForCall []struct {
+	}
+	batchPriorityReturns struct {
+		result1 *orderer.BatchPriority
+	}
+	batchPriorityReturnsOnCall map[int]struct {
+		result1 *orderer.BatchPriority
+	}
 	BatchSizeStub        func() *orderer.BatchSize
 	batchSizeMutex       sync.RWMutex
 	batchSizeArgsForCall []struct {
@@ -104,6 +114,58 @@ type OrdererConfig struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *OrdererConfig) BatchPriority() *orderer.BatchPriority {
+	fake.batchPriorityMutex.Lock()
+	ret, specificReturn := fake.batchPriorityReturnsOnCall[len(fake.batchPriorityArgsForCall)]
+	fake.batchPriorityArgsForCall = append(fake.batchPriorityArgsForCall, struct {
+	}{})
+	fake.recordInvocation("BatchPriority", []interface{}{})
+	fake.batchPriorityMutex.Unlock()
+	if fake.BatchPriorityStub != nil {
+		return fake.BatchPriorityStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.batchPriorityReturns
+	return fakeReturns.result1
+}
+
+func (fake *OrdererConfig) BatchPriorityCallCount() int {
+	fake.batchPriorityMutex.RLock()
+	defer fake.batchPriorityMutex.RUnlock()
+	return len(fake.batchPriorityArgsForCall)
+}
+
+func (fake *OrdererConfig) BatchPriorityCalls(stub func() *orderer.BatchPriority) {
+	fake.batchPriorityMutex.Lock()
+	defer fake.batchPriorityMutex.Unlock()
+	fake.BatchPriorityStub = stub
+}
+
+func (fake *OrdererConfig) BatchPriorityReturns(result1 *orderer.BatchPriority) {
+	fake.batchPriorityMutex.Lock()
+	defer fake.batchPriorityMutex.Unlock()
+	fake.BatchPriorityStub = nil
+	fake.batchPriorityReturns = struct {
+		result1 *orderer.BatchPriority
+	}{result1}
+}
+
+func (fake *OrdererConfig) BatchPriorityReturnsOnCall(i int, result1 *orderer.BatchPriority) {
+	fake.batchPriorityMutex.Lock()
+	defer fake.batchPriorityMutex.Unlock()
+	fake.BatchPriorityStub = nil
+	if fake.batchPriorityReturnsOnCall == nil {
+		fake.batchPriorityReturnsOnCall = make(map[int]struct {
+			result1 *orderer.BatchPriority
+		})
+	}
+	fake.batchPriorityReturnsOnCall[i] = struct {
+		result1 *orderer.BatchPriority
+	}{result1}
+}
+
 func (fake *OrdererConfig) BatchSize() *orderer.BatchSize {
 	fake.batchSizeMutex.Lock()
 	ret, specificReturn := fake.batchSizeReturnsOnCall[len(fake.batchSizeArgsForCall)]
@@ -575,6 +637,8 @@ func (fake *OrdererConfig) OrganizationsReturnsOnCall(i int, result1 map[string]
 func (fake *OrdererConfig) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.batchPriorityMutex.RLock()
+	defer fake.batchPriorityMutex.RUnlock()
 	fake.batchSizeMutex.RLock()
 	defer fake.batchSizeMutex.RUnlock()
 	fake.batchTimeoutMutex.RLock()
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// lane is a priority lane of the channel config, parsed for matching messages.
+type lane struct {
+	headerTypes   map[cb.HeaderType]struct{}
+	mspIDs        map[string]struct{}
+	maxPendingAge time.Duration
+}
+
+// lanes are the priority lanes of a channel, in decreasing order of priority.
+type lanes []lane
+
+func newLanes(priority *ab.BatchPriority) lanes {
+	var l lanes
+	for _, pl := range priority.GetLanes() {
+		var ln lane
+		if len(pl.HeaderTypes) > 0 {
+			ln.headerTypes = map[cb.HeaderType]struct{}{}
+			for _, headerType := range pl.HeaderTypes {
+				ln.headerTypes[cb.HeaderType(cb.HeaderType_value[headerType])] = struct{}{}
+			}
+		}
+		if len(pl.MspIds) > 0 {
+			ln.mspIDs = map[string]struct{}{}
+			for _, mspID := range pl.MspIds {
+				ln.mspIDs[mspID] = struct{}{}
+			}
+		}
+		if pl.MaxPendingAge != "" {
+			// the max pending age was validated along with the channel config
+			ln.maxPendingAge, _ = time.ParseDuration(pl.MaxPendingAge)
+		}
+		l = append(l, ln)
+	}
+	return l
+}
+
+// match returns the index of the first lane the message belongs to,
+// or the number of lanes if it belongs to none of them.
+func (l lanes) match(msg *cb.Envelope) int {
+	if len(l) == 0 {
+		return 0
+	}
+
+	headerType, mspID, err := classify(msg)
+	if err != nil {
+		logger.Debugf("Could not match the message to a priority lane: %s", err)
+		return len(l)
+	}
+
+	for i, ln := range l {
+		if ln.headerTypes != nil {
+			if _, ok := ln.headerTypes[headerType]; !ok {
+				continue
+			}
+		}
+		if ln.mspIDs != nil {
+			if _, ok := ln.mspIDs[mspID]; !ok {
+				continue
+			}
+		}
+		return i
+	}
+	return len(l)
+}
+
+// classify returns the header type of the message and the MSP ID of its creator.
+func classify(msg *cb.Envelope) (cb.HeaderType, string, error) {
+	payload, err := utils.UnmarshalPayload(msg.Payload)
+	if err != nil {
+		return 0, "", err
+	}
+	if payload.Header == nil {
+		return 0, "", errors.New("message has no header")
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return 0, "", err
+	}
+
+	shdr, err := utils.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return 0, "", err
+	}
+
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, sID); err != nil {
+		return 0, "", errors.Wrap(err, "failed unmarshaling creator of message")
+	}
+	return cb.HeaderType(chdr.Type), sID.Mspid, nil
+}
+
+// PendingBatchTimeout returns how long the pending batch of the receiver may wait
+// before it is cut. This is the time left until the max pending age of one of its
+// messages expires, when it is shorter than the batch timeout, and the batch
+// timeout otherwise.
+func PendingBatchTimeout(ordererConfig channelconfig.Orderer, receiver Receiver) time.Duration {
+	batchTimeout := ordererConfig.BatchTimeout()
+	if maxPendingAge, ok := receiver.MaxPendingAge(); ok && maxPendingAge < batchTimeout {
+		return maxPendingAge
+	}
+	return batchTimeout
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package broadcast
 
 import (
+	"fmt"
 	"io"
 	"time"
 
@@ -60,6 +61,9 @@ type Consenter interface {
 type Handler struct {
 	SupportRegistrar ChannelSupportRegistrar
 	Metrics          *Metrics
+	// Limiter limits the rate at which clients broadcast messages,
+	// messages are not rate limited if it is nil
+	Limiter *Limiter
 }
 
 // Handle reads requests from a Broadcast stream, processes them, and returns the responses to the stream
@@ -167,6 +171,10 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 		}
 		tracker.EndValidate()
 
+		if resp := bh.throttle(chdr, msg, addr); resp != nil {
+			return resp
+		}
+
 		tracker.BeginEnqueue()
 		if err = processor.WaitReady(); err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
@@ -188,6 +196,10 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 		}
 		tracker.EndValidate()
 
+		if resp := bh.throttle(chdr, msg, addr); resp != nil {
+			return resp
+		}
+
 		tracker.BeginEnqueue()
 		if err = processor.WaitReady(); err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
@@ -206,6 +218,34 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 	return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
 }
 
+// throttle returns a SERVICE_UNAVAILABLE response if the client, or its organization,
+// broadcasts messages to the channel faster than the limiter allows, and nil otherwise.
+// It is called once the message is validated, so that the creator of the message
+// is authenticated.
+func (bh *Handler) throttle(chdr *cb.ChannelHeader, msg *cb.Envelope, addr string) *ab.BroadcastResponse {
+	if bh.Limiter == nil {
+		return nil
+	}
+
+	mspID, identity, err := clientIdentity(msg)
+	if err != nil {
+		logger.Warningf("[channel: %s] Rejecting broadcast of message from %s because its creator could not be extracted: %s", chdr.ChannelId, addr, err)
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+	}
+
+	retryAfter := bh.Limiter.Allow(chdr.ChannelId, mspID, identity)
+	if retryAfter == 0 {
+		return nil
+	}
+
+	bh.Metrics.ThrottledCount.With("channel", chdr.ChannelId, "mspid", mspID).Add(1)
+	logger.Warningf("[channel: %s] Rejecting broadcast of message from %s of %s with SERVICE_UNAVAILABLE: rate limit exceeded", chdr.ChannelId, addr, mspID)
+	return &ab.BroadcastResponse{
+		Status: cb.Status_SERVICE_UNAVAILABLE,
+		Info:   fmt.Sprintf("rate limit exceeded, retry after %s", retryAfter),
+	}
+}
+
 // ClassifyError converts an error type into a status code.
 func ClassifyError(err error) cb.Status {
 	switch errors.Cause(err) {
@@ -10,7 +10,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
+	"code.cloudfoundry.org/clock/fakeclock"
 	"github.com/golang/protobuf/proto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -19,7 +21,9 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/broadcast/mock"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 var _ = Describe("Broadcast", func() {
@@ -197,6 +201,73 @@ var _ = Describe("Broadcast", func() {
 			})
 		})
 
+		Context("when the client exceeds its rate limit", func() {
+			var (
+				fakeThrottledCounter *mock.MetricsCounter
+				fakeClock            *fakeclock.FakeClock
+			)
+
+			BeforeEach(func() {
+				fakeMsg.Payload = utils.MarshalOrPanic(&cb.Payload{
+					Header: &cb.Header{
+						SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{
+							Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{
+								Mspid:   "Org1MSP",
+								IdBytes: []byte("client"),
+							}),
+						}),
+					},
+				})
+				fakeABServer.RecvReturnsOnCall(1, fakeMsg, nil)
+				fakeABServer.RecvReturnsOnCall(2, nil, io.EOF)
+
+				fakeThrottledCounter = &mock.MetricsCounter{}
+				fakeThrottledCounter.WithReturns(fakeThrottledCounter)
+				handler.Metrics.ThrottledCount = fakeThrottledCounter
+
+				fakeClock = fakeclock.NewFakeClock(time.Now())
+				handler.Limiter = broadcast.NewLimiter(broadcast.LimiterConfig{ClientRate: 1}, fakeClock)
+			})
+
+			It("returns a service unavailable status with a retry hint", func() {
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.OrderCallCount()).To(Equal(1))
+				Expect(fakeSupport.ProcessNormalMsgCallCount()).To(Equal(2))
+
+				Expect(fakeABServer.SendCallCount()).To(Equal(2))
+				Expect(proto.Equal(fakeABServer.SendArgsForCall(0), &ab.BroadcastResponse{Status: cb.Status_SUCCESS})).To(BeTrue())
+				Expect(proto.Equal(
+					fakeABServer.SendArgsForCall(1),
+					&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "rate limit exceeded, retry after 1s"}),
+				).To(BeTrue())
+
+				Expect(fakeThrottledCounter.WithCallCount()).To(Equal(1))
+				Expect(fakeThrottledCounter.WithArgsForCall(0)).To(Equal([]string{
+					"channel", "fake-channel",
+					"mspid", "Org1MSP",
+				}))
+				Expect(fakeThrottledCounter.AddCallCount()).To(Equal(1))
+				Expect(fakeThrottledCounter.AddArgsForCall(0)).To(Equal(float64(1)))
+			})
+
+			Context("when the creator of the message cannot be extracted", func() {
+				BeforeEach(func() {
+					fakeMsg.Payload = []byte("garbage")
+				})
+
+				It("returns a bad request status", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeSupport.OrderCallCount()).To(Equal(0))
+					Expect(fakeABServer.SendCallCount()).To(Equal(1))
+					Expect(fakeABServer.SendArgsForCall(0).Status).To(Equal(cb.Status_BAD_REQUEST))
+				})
+			})
+		})
+
 		Context("when the send to the client fails", func() {
 			BeforeEach(func() {
 				fakeABServer.SendReturns(fmt.Errorf("send-error"))
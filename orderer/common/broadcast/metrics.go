@@ -30,12 +30,20 @@ var (
 		LabelNames:   []string{"channel", "type", "status"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{type}.%{status}",
 	}
+	throttledCount = metrics.CounterOpts{
+		Namespace:    "broadcast",
+		Name:         "throttled_count",
+		Help:         "The number of transactions rejected because their client or organization exceeded its rate limit.",
+		LabelNames:   []string{"channel", "mspid"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{mspid}",
+	}
 )
 
 type Metrics struct {
 	ValidateDuration metrics.Histogram
 	EnqueueDuration  metrics.Histogram
 	ProcessedCount   metrics.Counter
+	ThrottledCount   metrics.Counter
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
@@ -43,5 +51,6 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		ValidateDuration: p.NewHistogram(validateDuration),
 		EnqueueDuration:  p.NewHistogram(enqueueDuration),
 		ProcessedCount:   p.NewCounter(processedCount),
+		ThrottledCount:   p.NewCounter(throttledCount),
 	}
 }
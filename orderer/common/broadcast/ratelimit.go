@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// LimiterConfig contains the rates at which clients may broadcast messages to a channel.
+// A rate of 0 disables the corresponding limit.
+type LimiterConfig struct {
+	// ClientRate is the number of messages per second a single client identity
+	// may broadcast to a channel.
+	ClientRate int
+	// ClientBurst is the number of messages a client may broadcast at once,
+	// it defaults to ClientRate.
+	ClientBurst int
+	// OrgRate is the number of messages per second the clients of an organization
+	// may broadcast to a channel, all together.
+	OrgRate int
+	// OrgBurst is the number of messages the clients of an organization may
+	// broadcast at once, it defaults to OrgRate.
+	OrgBurst int
+	// InactivityTimeout is the time after which the state of an idle client or
+	// organization is discarded.
+	InactivityTimeout time.Duration
+}
+
+// bucket is a token bucket which holds at most burst tokens, and
+// is refilled at rate tokens per second.
+type bucket struct {
+	rate     float64
+	burst    float64
+	tokens   float64
+	lastSeen time.Time
+}
+
+// refill adds the tokens accumulated since the bucket was last seen.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastSeen).Seconds()
+	b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+	b.lastSeen = now
+}
+
+// wait returns how long it takes for the bucket to hold a token.
+func (b *bucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+}
+
+// Limiter limits the rate at which clients broadcast messages, using a token bucket
+// per client identity and a token bucket per organization, on every channel.
+type Limiter struct {
+	config LimiterConfig
+	clock  clock.Clock
+
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastPurge time.Time
+}
+
+// NewLimiter creates a limiter enforcing the given config.
+func NewLimiter(config LimiterConfig, clock clock.Clock) *Limiter {
+	if config.ClientBurst == 0 {
+		config.ClientBurst = config.ClientRate
+	}
+	if config.OrgBurst == 0 {
+		config.OrgBurst = config.OrgRate
+	}
+
+	return &Limiter{
+		config:    config,
+		clock:     clock,
+		buckets:   map[string]*bucket{},
+		lastPurge: clock.Now(),
+	}
+}
+
+// Allow takes a token from the buckets of the client and of its organization on the
+// channel. If either of them is empty, no token is taken, and Allow returns how long
+// the client should wait before it retries.
+func (l *Limiter) Allow(channel, mspID string, identity []byte) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.clock.Now()
+	l.purge(now)
+
+	var buckets []*bucket
+	if l.config.OrgRate > 0 {
+		buckets = append(buckets, l.bucket(channel+"\x00"+mspID, l.config.OrgRate, l.config.OrgBurst, now))
+	}
+	if l.config.ClientRate > 0 {
+		digest := sha256.Sum256(identity)
+		key := channel + "\x00" + mspID + "\x00" + hex.EncodeToString(digest[:])
+		buckets = append(buckets, l.bucket(key, l.config.ClientRate, l.config.ClientBurst, now))
+	}
+
+	var retryAfter time.Duration
+	for _, b := range buckets {
+		if wait := b.wait(); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return 0
+}
+
+func (l *Limiter) bucket(key string, rate, burst int, now time.Time) *bucket {
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{
+			rate:     float64(rate),
+			burst:    float64(burst),
+			tokens:   float64(burst),
+			lastSeen: now,
+		}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+	return b
+}
+
+// purge discards the buckets which have not been used for longer than the inactivity
+// timeout, as these would have been refilled by then anyway.
+func (l *Limiter) purge(now time.Time) {
+	if l.config.InactivityTimeout == 0 || now.Sub(l.lastPurge) < l.config.InactivityTimeout {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= l.config.InactivityTimeout {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPurge = now
+}
+
+// clientIdentity returns the MSP ID and the identity of the creator of the message.
+func clientIdentity(msg *cb.Envelope) (string, []byte, error) {
+	payload, err := utils.UnmarshalPayload(msg.Payload)
+	if err != nil {
+		return "", nil, err
+	}
+	if payload.Header == nil {
+		return "", nil, errors.New("message has no header")
+	}
+
+	shdr, err := utils.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, sID); err != nil {
+		return "", nil, errors.Wrap(err, "failed unmarshaling creator of message")
+	}
+	return sID.Mspid, sID.IdBytes, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
+)
+
+var _ = Describe("Limiter", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		limiter   *broadcast.Limiter
+	)
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		limiter = broadcast.NewLimiter(broadcast.LimiterConfig{
+			ClientRate:        2,
+			OrgRate:           10,
+			OrgBurst:          3,
+			InactivityTimeout: time.Minute,
+		}, fakeClock)
+	})
+
+	It("allows clients to broadcast at their rate", func() {
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(Equal(500 * time.Millisecond))
+
+		fakeClock.Increment(250 * time.Millisecond)
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(Equal(250 * time.Millisecond))
+
+		fakeClock.Increment(250 * time.Millisecond)
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+	})
+
+	It("limits the clients of an organization together", func() {
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client2"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client3"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client4"))).To(Equal(100 * time.Millisecond))
+
+		By("not limiting other organizations and channels")
+		Expect(limiter.Allow("channel", "Org2MSP", []byte("client5"))).To(BeZero())
+		Expect(limiter.Allow("other-channel", "Org1MSP", []byte("client4"))).To(BeZero())
+	})
+
+	It("does not take tokens from the client when its organization is limited", func() {
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client2"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client2"))).To(BeZero())
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).NotTo(BeZero())
+
+		fakeClock.Increment(100 * time.Millisecond)
+		Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+	})
+
+	Context("when a rate is 0", func() {
+		BeforeEach(func() {
+			limiter = broadcast.NewLimiter(broadcast.LimiterConfig{ClientRate: 1}, fakeClock)
+		})
+
+		It("does not enforce it", func() {
+			for i := 0; i < 100; i++ {
+				Expect(limiter.Allow("channel", "Org1MSP", []byte{byte(i)})).To(BeZero())
+			}
+			Expect(limiter.Allow("channel", "Org1MSP", []byte{0})).To(Equal(time.Second))
+		})
+	})
+
+	Context("when a client is idle for longer than the inactivity timeout", func() {
+		It("starts over with a full bucket", func() {
+			Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+			Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+
+			fakeClock.Increment(2 * time.Minute)
+			Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+			Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).To(BeZero())
+			Expect(limiter.Allow("channel", "Org1MSP", []byte("client1"))).NotTo(BeZero())
+		})
+	})
+})
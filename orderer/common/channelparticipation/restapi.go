@@ -0,0 +1,284 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	URLBaseV1              = "/participation/v1/"
+	URLBaseV1Channels      = URLBaseV1 + "channels"
+	FormDataConfigBlockKey = "config-block"
+)
+
+//go:generate counterfeiter -o mocks/channel_management.go -fake-name ChannelManagement . ChannelManagement
+
+// ChannelManagement is the interface the channel participation API uses to
+// list, join and remove the channels of the orderer.
+type ChannelManagement interface {
+	// ChannelList returns a slice of ChannelInfoShort containing all application channels (excluding the system
+	// channel), and ChannelInfoShort of the system channel (nil if does not exist).
+	// The URL fields are empty, and are to be completed by the caller.
+	ChannelList() types.ChannelList
+
+	// ChannelInfo provides extended status information about a channel.
+	// The URL field is empty, and is to be completed by the caller.
+	ChannelInfo(channelID string) (types.ChannelInfo, error)
+
+	// JoinChannel instructs the orderer to create a channel and join it with the provided config block,
+	// which must be the genesis block of the channel.
+	JoinChannel(channelID string, configBlock *cb.Block) (types.ChannelInfo, error)
+
+	// RemoveChannel instructs the orderer to remove a channel.
+	RemoveChannel(channelID string) error
+}
+
+// HTTPHandler handles all the HTTP requests to the channel participation API.
+type HTTPHandler struct {
+	logger    *flogging.FabricLogger
+	config    localconfig.ChannelParticipation
+	registrar ChannelManagement
+}
+
+// NewHTTPHandler creates the HTTP handler of the channel participation API.
+func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelManagement) *HTTPHandler {
+	return &HTTPHandler{
+		logger:    flogging.MustGetLogger("channelparticipation"),
+		config:    config,
+		registrar: registrar,
+	}
+}
+
+// ServeHTTP routes the requests by path and method:
+// GET /participation/v1/channels lists the channels,
+// GET, POST and DELETE /participation/v1/channels/{channelID} respectively
+// list, join and remove a channel.
+func (h *HTTPHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if !h.config.Enabled {
+		h.sendResponseJsonError(resp, http.StatusServiceUnavailable, errors.New("channel participation API is disabled"))
+		return
+	}
+
+	switch {
+	case req.URL.Path == URLBaseV1Channels || req.URL.Path == URLBaseV1Channels+"/":
+		switch req.Method {
+		case http.MethodGet:
+			h.serveListAll(resp, req)
+		default:
+			h.sendResponseNotAllowed(resp, errors.Errorf("invalid request method: %s", req.Method), http.MethodGet)
+		}
+
+	case strings.HasPrefix(req.URL.Path, URLBaseV1Channels+"/"):
+		channelID := strings.TrimPrefix(req.URL.Path, URLBaseV1Channels+"/")
+		if err := configtx.ValidateChannelID(channelID); err != nil {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "invalid channel ID"))
+			return
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+			h.serveListOne(resp, req, channelID)
+		case http.MethodPost:
+			h.serveJoin(resp, req, channelID)
+		case http.MethodDelete:
+			h.serveRemove(resp, req, channelID)
+		default:
+			h.sendResponseNotAllowed(resp, errors.Errorf("invalid request method: %s", req.Method), http.MethodGet, http.MethodPost, http.MethodDelete)
+		}
+
+	default:
+		h.sendResponseJsonError(resp, http.StatusNotFound, errors.Errorf("path not found: %s", req.URL.Path))
+	}
+}
+
+// List all channels
+func (h *HTTPHandler) serveListAll(resp http.ResponseWriter, req *http.Request) {
+	if !h.acceptsJSON(resp, req) {
+		return
+	}
+
+	channelList := h.registrar.ChannelList()
+	if channelList.SystemChannel != nil {
+		channelList.SystemChannel.URL = path.Join(URLBaseV1Channels, channelList.SystemChannel.Name)
+	}
+	for i, info := range channelList.Channels {
+		channelList.Channels[i].URL = path.Join(URLBaseV1Channels, info.Name)
+	}
+	resp.Header().Set("Cache-Control", "no-store")
+	h.sendResponseOK(resp, channelList)
+}
+
+// List a single channel
+func (h *HTTPHandler) serveListOne(resp http.ResponseWriter, req *http.Request, channelID string) {
+	if !h.acceptsJSON(resp, req) {
+		return
+	}
+
+	infoFull, err := h.registrar.ChannelInfo(channelID)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotFound, err)
+		return
+	}
+	infoFull.URL = path.Join(URLBaseV1Channels, infoFull.Name)
+	resp.Header().Set("Cache-Control", "no-store")
+	h.sendResponseOK(resp, infoFull)
+}
+
+// Join a channel.
+// Expect multipart/form-data with the config block under the FormDataConfigBlockKey.
+func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request, channelID string) {
+	if !h.acceptsJSON(resp, req) {
+		return
+	}
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot parse Mime media type"))
+		return
+	}
+	if mediaType != "multipart/form-data" || params["boundary"] == "" {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Errorf("unsupported Content-Type: %s", mediaType))
+		return
+	}
+
+	req.Body = http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize))
+	defer req.Body.Close()
+	if err := req.ParseMultipartForm(int64(h.config.MaxRequestBodySize)); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot read form from request body"))
+		return
+	}
+
+	file, _, err := req.FormFile(FormDataConfigBlockKey)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "form does not contain part key: %s", FormDataConfigBlockKey))
+		return
+	}
+	defer file.Close()
+
+	blockBytes, err := ioutil.ReadAll(file)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot read file part from request body"))
+		return
+	}
+
+	block := &cb.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot unmarshal file part to a block"))
+		return
+	}
+
+	info, err := h.registrar.JoinChannel(channelID, block)
+	if err != nil {
+		h.sendJoinError(resp, err)
+		return
+	}
+	info.URL = path.Join(URLBaseV1Channels, info.Name)
+
+	h.logger.Debugf("Successfully joined channel: %s", info.URL)
+	h.sendResponseCreated(resp, info.URL, info)
+}
+
+// Remove a channel
+func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request, channelID string) {
+	if err := h.registrar.RemoveChannel(channelID); err != nil {
+		h.sendRemoveError(resp, err)
+		return
+	}
+
+	h.logger.Debugf("Successfully removed channel: %s", channelID)
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) acceptsJSON(resp http.ResponseWriter, req *http.Request) bool {
+	accept := req.Header.Get("Accept")
+	if accept == "" {
+		return true
+	}
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+		if mediaType == "application/json" || mediaType == "application/*" || mediaType == "*/*" {
+			return true
+		}
+	}
+	h.sendResponseJsonError(resp, http.StatusNotAcceptable, errors.Errorf("response Content-Type is application/json only"))
+	return false
+}
+
+func (h *HTTPHandler) sendJoinError(resp http.ResponseWriter, err error) {
+	h.logger.Debugf("Failed to JoinChannel: %s", err)
+	switch err {
+	case types.ErrSystemChannelExists:
+		// Channels are joined through the system channel when it exists
+		h.sendResponseNotAllowed(resp, err, http.MethodGet)
+	case types.ErrChannelAlreadyExists:
+		h.sendResponseNotAllowed(resp, err, http.MethodGet, http.MethodDelete)
+	default:
+		h.sendResponseJsonError(resp, http.StatusBadRequest, err)
+	}
+}
+
+func (h *HTTPHandler) sendRemoveError(resp http.ResponseWriter, err error) {
+	h.logger.Debugf("Failed to RemoveChannel: %s", err)
+	switch err {
+	case types.ErrSystemChannelExists:
+		h.sendResponseNotAllowed(resp, err, http.MethodGet)
+	case types.ErrChannelNotExist:
+		h.sendResponseJsonError(resp, http.StatusNotFound, err)
+	default:
+		h.sendResponseJsonError(resp, http.StatusBadRequest, err)
+	}
+}
+
+func (h *HTTPHandler) sendResponseJsonError(resp http.ResponseWriter, code int, err error) {
+	encoder := json.NewEncoder(resp)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := encoder.Encode(&types.ErrorResponse{Error: err.Error()}); err != nil {
+		h.logger.Errorf("failed to encode error, err: %s", err)
+	}
+}
+
+func (h *HTTPHandler) sendResponseOK(resp http.ResponseWriter, content interface{}) {
+	encoder := json.NewEncoder(resp)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if err := encoder.Encode(content); err != nil {
+		h.logger.Errorf("failed to encode content, err: %s", err)
+	}
+}
+
+func (h *HTTPHandler) sendResponseCreated(resp http.ResponseWriter, location string, info types.ChannelInfo) {
+	encoder := json.NewEncoder(resp)
+	resp.Header().Set("Location", location)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusCreated)
+	if err := encoder.Encode(info); err != nil {
+		h.logger.Errorf("failed to encode content, err: %s", err)
+	}
+}
+
+func (h *HTTPHandler) sendResponseNotAllowed(resp http.ResponseWriter, err error, allow ...string) {
+	resp.Header().Set("Allow", strings.Join(allow, ", "))
+	h.sendResponseJsonError(resp, http.StatusMethodNotAllowed, err)
+}
@@ -0,0 +1,319 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHandler(fakeManager *mocks.ChannelManagement) *channelparticipation.HTTPHandler {
+	config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}
+	return channelparticipation.NewHTTPHandler(config, fakeManager)
+}
+
+func joinBody(t *testing.T, key string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(key, "join-block.pb")
+	require.NoError(t, err)
+	_, err = io.Copy(part, bytes.NewReader(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func checkErrorResponse(t *testing.T, resp *httptest.ResponseRecorder, expectedCode int, expectedErr string) {
+	assert.Equal(t, expectedCode, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	errResp := &types.ErrorResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), errResp))
+	assert.Equal(t, expectedErr, errResp.Error)
+}
+
+func TestHTTPHandler_Disabled(t *testing.T) {
+	h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{}, &mocks.ChannelManagement{})
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil))
+	checkErrorResponse(t, resp, http.StatusServiceUnavailable, "channel participation API is disabled")
+}
+
+func TestHTTPHandler_ListAll(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	fakeManager.ChannelListReturns(types.ChannelList{
+		Channels: []types.ChannelInfoShort{{Name: "app-channel1"}, {Name: "app-channel2"}},
+	})
+	h := newHandler(fakeManager)
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil)
+	req.Header.Set("Accept", "application/json")
+	h.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"))
+	list := types.ChannelList{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.Nil(t, list.SystemChannel)
+	assert.Equal(t, []types.ChannelInfoShort{
+		{Name: "app-channel1", URL: "/participation/v1/channels/app-channel1"},
+		{Name: "app-channel2", URL: "/participation/v1/channels/app-channel2"},
+	}, list.Channels)
+
+	t.Run("with a system channel", func(t *testing.T) {
+		fakeManager.ChannelListReturns(types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: "system-channel"}})
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+		list := types.ChannelList{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+		assert.Equal(t, &types.ChannelInfoShort{Name: "system-channel", URL: "/participation/v1/channels/system-channel"}, list.SystemChannel)
+		assert.Empty(t, list.Channels)
+	})
+
+	t.Run("bad Accept header", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil)
+		req.Header.Set("Accept", "text/html")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, resp, http.StatusNotAcceptable, "response Content-Type is application/json only")
+	})
+
+	t.Run("bad method", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, channelparticipation.URLBaseV1Channels, nil))
+		checkErrorResponse(t, resp, http.StatusMethodNotAllowed, "invalid request method: PUT")
+		assert.Equal(t, "GET", resp.Header().Get("Allow"))
+	})
+}
+
+func TestHTTPHandler_ListOne(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	fakeManager.ChannelInfoReturns(types.ChannelInfo{
+		Name:            "app-channel",
+		ClusterRelation: types.ClusterRelationMember,
+		Status:          types.StatusActive,
+		Height:          3,
+	}, nil)
+	h := newHandler(fakeManager)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil))
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "no-store", resp.Header().Get("Cache-Control"))
+	info := types.ChannelInfo{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+	assert.Equal(t, types.ChannelInfo{
+		Name:            "app-channel",
+		URL:             "/participation/v1/channels/app-channel",
+		ClusterRelation: "member",
+		Status:          "active",
+		Height:          3,
+	}, info)
+	assert.Equal(t, "app-channel", fakeManager.ChannelInfoArgsForCall(0))
+
+	t.Run("channel does not exist", func(t *testing.T) {
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil))
+		checkErrorResponse(t, resp, http.StatusNotFound, "channel does not exist")
+	})
+
+	t.Run("invalid channel ID", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/App_Channel", nil))
+		checkErrorResponse(t, resp, http.StatusBadRequest, "invalid channel ID: channel ID 'App_Channel' contains illegal characters")
+	})
+
+	t.Run("bad method", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, channelparticipation.URLBaseV1Channels+"/app-channel", nil))
+		checkErrorResponse(t, resp, http.StatusMethodNotAllowed, "invalid request method: PATCH")
+		assert.Equal(t, "GET, POST, DELETE", resp.Header().Get("Allow"))
+	})
+
+	t.Run("path not found", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1+"peers", nil))
+		checkErrorResponse(t, resp, http.StatusNotFound, "path not found: /participation/v1/peers")
+	})
+}
+
+func TestHTTPHandler_Join(t *testing.T) {
+	block := cb.NewBlock(0, nil)
+	blockBytes := utils.MarshalOrPanic(block)
+
+	t.Run("success", func(t *testing.T) {
+		fakeManager := &mocks.ChannelManagement{}
+		fakeManager.JoinChannelReturns(types.ChannelInfo{
+			Name:            "app-channel",
+			ClusterRelation: types.ClusterRelationMember,
+			Status:          types.StatusActive,
+			Height:          1,
+		}, nil)
+		h := newHandler(fakeManager)
+
+		body, contentType := joinBody(t, channelparticipation.FormDataConfigBlockKey, blockBytes)
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels+"/app-channel", body)
+		req.Header.Set("Content-Type", contentType)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, "/participation/v1/channels/app-channel", resp.Header().Get("Location"))
+		info := types.ChannelInfo{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+		assert.Equal(t, types.ChannelInfo{
+			Name:            "app-channel",
+			URL:             "/participation/v1/channels/app-channel",
+			ClusterRelation: "member",
+			Status:          "active",
+			Height:          1,
+		}, info)
+
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+		channelID, joinBlock := fakeManager.JoinChannelArgsForCall(0)
+		assert.Equal(t, "app-channel", channelID)
+		assert.Equal(t, block.Header.Hash(), joinBlock.Header.Hash())
+	})
+
+	joinErrors := []struct {
+		name          string
+		err           error
+		expectedCode  int
+		expectedAllow string
+	}{
+		{"system channel exists", types.ErrSystemChannelExists, http.StatusMethodNotAllowed, "GET"},
+		{"channel already exists", types.ErrChannelAlreadyExists, http.StatusMethodNotAllowed, "GET, DELETE"},
+		{"invalid block", errors.New("invalid config block"), http.StatusBadRequest, ""},
+	}
+	for _, tc := range joinErrors {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fakeManager := &mocks.ChannelManagement{}
+			fakeManager.JoinChannelReturns(types.ChannelInfo{}, tc.err)
+			h := newHandler(fakeManager)
+
+			body, contentType := joinBody(t, channelparticipation.FormDataConfigBlockKey, blockBytes)
+			req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels+"/app-channel", body)
+			req.Header.Set("Content-Type", contentType)
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+
+			checkErrorResponse(t, resp, tc.expectedCode, tc.err.Error())
+			assert.Equal(t, tc.expectedAllow, resp.Header().Get("Allow"))
+		})
+	}
+
+	badRequests := []struct {
+		name        string
+		key         string
+		content     []byte
+		contentType string
+		expectedErr string
+	}{
+		{
+			name:        "bad content type",
+			contentType: "application/json",
+			expectedErr: "unsupported Content-Type: application/json",
+		},
+		{
+			name:        "missing form part",
+			key:         "wrong-key",
+			content:     blockBytes,
+			expectedErr: "form does not contain part key: config-block",
+		},
+		{
+			name:        "bad block",
+			key:         channelparticipation.FormDataConfigBlockKey,
+			content:     []byte{1, 2, 3},
+			expectedErr: "cannot unmarshal file part to a block",
+		},
+	}
+	for _, tc := range badRequests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fakeManager := &mocks.ChannelManagement{}
+			h := newHandler(fakeManager)
+
+			body, contentType := &bytes.Buffer{}, tc.contentType
+			if tc.key != "" {
+				body, contentType = joinBody(t, tc.key, tc.content)
+			}
+			req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels+"/app-channel", body)
+			req.Header.Set("Content-Type", contentType)
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.Contains(t, resp.Body.String(), tc.expectedErr)
+			assert.Zero(t, fakeManager.JoinChannelCallCount())
+		})
+	}
+
+	t.Run("body exceeds max size", func(t *testing.T) {
+		fakeManager := &mocks.ChannelManagement{}
+		config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 64}
+		h := channelparticipation.NewHTTPHandler(config, fakeManager)
+
+		body, contentType := joinBody(t, channelparticipation.FormDataConfigBlockKey, make([]byte, 128))
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels+"/app-channel", body)
+		req.Header.Set("Content-Type", contentType)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "cannot read form from request body")
+		assert.Zero(t, fakeManager.JoinChannelCallCount())
+	})
+}
+
+func TestHTTPHandler_Remove(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	h := newHandler(fakeManager)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app-channel", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+	assert.Equal(t, "app-channel", fakeManager.RemoveChannelArgsForCall(0))
+
+	removeErrors := []struct {
+		err           error
+		expectedCode  int
+		expectedAllow string
+	}{
+		{types.ErrSystemChannelExists, http.StatusMethodNotAllowed, "GET"},
+		{types.ErrChannelNotExist, http.StatusNotFound, ""},
+		{errors.New("failed removing the ledger"), http.StatusBadRequest, ""},
+	}
+	for _, tc := range removeErrors {
+		fakeManager.RemoveChannelReturns(tc.err)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app-channel", nil))
+		checkErrorResponse(t, resp, tc.expectedCode, tc.err.Error())
+		assert.Equal(t, tc.expectedAllow, resp.Header().Get("Allow"))
+	}
+}
@@ -62,6 +62,19 @@ type RemoteNode struct {
 	ServerTLSCert []byte
 	// ClientTLSCert is the DER encoded TLS client certificate of the node
 	ClientTLSCert []byte
+	// PreviousServerTLSCert is the DER encoded TLS server certificate the node
+	// used before rotating its certificates, accepted until GracePeriodEnd
+	PreviousServerTLSCert []byte
+	// PreviousClientTLSCert is the DER encoded TLS client certificate the node
+	// used before rotating its certificates, accepted until GracePeriodEnd
+	PreviousClientTLSCert []byte
+	// GracePeriodEnd is the time until which the previous certificates are accepted
+	GracePeriodEnd time.Time
+}
+
+// inGracePeriod returns whether the previous certificates of the node are accepted at the given time
+func (rm RemoteNode) inGracePeriod(now time.Time) bool {
+	return now.Before(rm.GracePeriodEnd)
 }
 
 // String returns a string representation of this RemoteNode
@@ -329,7 +342,14 @@ func (c *Comm) createRemoteContext(stub *Stub, channel string) func() (*RemoteCo
 
 		c.Logger.Debug("Connecting to", stub.RemoteNode, "for channel", channel)
 
-		conn, err := c.Connections.Connection(stub.Endpoint, stub.ServerTLSCert)
+		// A node rotating its certificates may still present its previous
+		// server certificate until it is restarted with the new one
+		var previousServerCerts [][]byte
+		if len(stub.PreviousServerTLSCert) > 0 && stub.inGracePeriod(time.Now()) {
+			previousServerCerts = append(previousServerCerts, stub.PreviousServerTLSCert)
+		}
+
+		conn, err := c.Connections.Connection(stub.Endpoint, stub.ServerTLSCert, previousServerCerts...)
 		if err != nil {
 			c.Logger.Warningf("Unable to obtain connection to %d(%s) (channel %s): %v", stub.ID, stub.Endpoint, channel, err)
 			return nil, err
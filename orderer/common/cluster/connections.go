@@ -56,11 +56,13 @@ func NewConnectionStore(dialer SecureDialer, tlsConnectionCount metrics.Gauge) *
 }
 
 // verifyHandshake returns a predicate that verifies that the remote node authenticates
-// itself with the given TLS certificate
-func (c *ConnectionStore) verifyHandshake(endpoint string, certificate []byte) RemoteVerifier {
+// itself with one of the given TLS certificates
+func (c *ConnectionStore) verifyHandshake(endpoint string, certificates ...[]byte) RemoteVerifier {
 	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
-		if bytes.Equal(certificate, rawCerts[0]) {
-			return nil
+		for _, certificate := range certificates {
+			if bytes.Equal(certificate, rawCerts[0]) {
+				return nil
+			}
 		}
 		return errors.Errorf("certificate presented by %s doesn't match any authorized certificate", endpoint)
 	}
@@ -80,8 +82,9 @@ func (c *ConnectionStore) Disconnect(expectedServerCert []byte) {
 }
 
 // Connection obtains a connection to the given endpoint and expects the given server certificate
-// to be presented by the remote node
-func (c *ConnectionStore) Connection(endpoint string, expectedServerCert []byte) (*grpc.ClientConn, error) {
+// to be presented by the remote node. The previous server certificates of a node rotating its
+// certificates are also accepted when establishing a new connection.
+func (c *ConnectionStore) Connection(endpoint string, expectedServerCert []byte, previousServerCerts ...[]byte) (*grpc.ClientConn, error) {
 	c.lock.RLock()
 	conn, alreadyConnected := c.Connections.Lookup(expectedServerCert)
 	c.lock.RUnlock()
@@ -91,12 +94,12 @@ func (c *ConnectionStore) Connection(endpoint string, expectedServerCert []byte)
 	}
 
 	// Else, we need to connect to the remote endpoint
-	return c.connect(endpoint, expectedServerCert)
+	return c.connect(endpoint, expectedServerCert, previousServerCerts...)
 }
 
-// connect connects to the given endpoint and expects the given TLS server certificate
-// to be presented at the time of authentication
-func (c *ConnectionStore) connect(endpoint string, expectedServerCert []byte) (*grpc.ClientConn, error) {
+// connect connects to the given endpoint and expects the given TLS server certificate,
+// or one of the previous ones, to be presented at the time of authentication
+func (c *ConnectionStore) connect(endpoint string, expectedServerCert []byte, previousServerCerts ...[]byte) (*grpc.ClientConn, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	// Check again to see if some other goroutine has already connected while
@@ -106,7 +109,7 @@ func (c *ConnectionStore) connect(endpoint string, expectedServerCert []byte) (*
 		return conn, nil
 	}
 
-	v := c.verifyHandshake(endpoint, expectedServerCert)
+	v := c.verifyHandshake(endpoint, append([][]byte{expectedServerCert}, previousServerCerts...)...)
 	conn, err := c.dialer.Dial(endpoint, v)
 	if err != nil {
 		return nil, err
@@ -105,3 +105,31 @@ func TestConcurrentLookupMiss(t *testing.T) {
 	// Wait for all goroutines to exit
 	goroutinesExited.Wait()
 }
+
+func TestConnectionAcceptsPreviousServerCerts(t *testing.T) {
+	t.Parallel()
+	// Scenario: A node rotating its certificates is connected to while
+	// still presenting its previous server certificate.
+	dialer := &mocks.SecureDialer{}
+	conn := &grpc.ClientConn{}
+	var verify cluster.RemoteVerifier
+	dialer.On("Dial", mock.Anything, mock.Anything).Return(conn, nil).Run(func(args mock.Arguments) {
+		verify = args.Get(1).(cluster.RemoteVerifier)
+	})
+	connStore := cluster.NewConnectionStore(dialer, &disabled.Gauge{})
+
+	conn2, err := connStore.Connection("node1:7050", []byte("new cert"), []byte("previous cert"))
+	assert.NoError(t, err)
+	assert.True(t, conn2 == conn)
+
+	assert.NoError(t, verify([][]byte{[]byte("new cert")}, nil))
+	assert.NoError(t, verify([][]byte{[]byte("previous cert")}, nil))
+	assert.EqualError(t, verify([][]byte{[]byte("other cert")}, nil),
+		"certificate presented by node1:7050 doesn't match any authorized certificate")
+
+	// The connection is mapped to the new certificate
+	conn3, err := connStore.Connection("node1:7050", []byte("new cert"))
+	assert.NoError(t, err)
+	assert.True(t, conn3 == conn)
+	dialer.AssertNumberOfCalls(t, "Dial", 1)
+}
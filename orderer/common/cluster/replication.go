@@ -90,6 +90,10 @@ type Replicator struct {
 	BootBlock                       *common.Block
 	AmIPartOfChannel                SelfMembershipPredicate
 	LedgerFactory                   LedgerFactory
+
+	// hashingAlgorithms holds the block hashing algorithms of the
+	// channels whose genesis block was seen during replication
+	hashingAlgorithms map[string]func([]byte) []byte
 }
 
 // IsReplicationNeeded returns whether replication is needed,
@@ -146,6 +150,7 @@ func (r *Replicator) ReplicateChains() []string {
 				r.Logger.Panicf("Failed converting channel creation block for channel %s to genesis block: %v",
 					channel.ChannelName, err)
 			}
+			r.rememberHashingAlgorithm(channel.ChannelName, gb)
 			r.appendBlock(gb, ledger, channel.ChannelName)
 		}
 	}
@@ -218,8 +223,12 @@ func (r *Replicator) pullChannelBlocks(channel string, puller *BlockPuller, late
 	if nextBlock == nil {
 		return ErrRetryCountExhausted
 	}
+	if nextBlock.Header.Number == 0 {
+		r.rememberHashingAlgorithm(channel, nextBlock)
+	}
 	r.appendBlock(nextBlock, ledger, channel)
-	actualPrevHash := nextBlock.Header.Hash()
+	hashingAlgorithm := r.blockHashingAlgorithm(channel)
+	actualPrevHash := hashingAlgorithm(nextBlock.Header.Bytes())
 
 	for seq := uint64(nextBlockToPull + 1); seq < latestHeight; seq++ {
 		block := puller.PullBlock(seq)
@@ -231,7 +240,7 @@ func (r *Replicator) pullChannelBlocks(channel string, puller *BlockPuller, late
 			return errors.Errorf("block header mismatch on sequence %d, expected %x, got %x",
 				block.Header.Number, actualPrevHash, reportedPrevHash)
 		}
-		actualPrevHash = block.Header.Hash()
+		actualPrevHash = hashingAlgorithm(block.Header.Bytes())
 		if channel == r.SystemChannel && block.Header.Number == r.BootBlock.Header.Number {
 			r.compareBootBlockWithSystemChannelLastConfigBlock(block)
 			r.appendBlock(block, ledger, channel)
@@ -243,6 +252,27 @@ func (r *Replicator) pullChannelBlocks(channel string, puller *BlockPuller, late
 	return nil
 }
 
+func (r *Replicator) rememberHashingAlgorithm(channel string, genesisBlock *common.Block) {
+	if r.hashingAlgorithms == nil {
+		r.hashingAlgorithms = make(map[string]func([]byte) []byte)
+	}
+	r.hashingAlgorithms[channel] = BlockHashingAlgorithm(genesisBlock)
+}
+
+// blockHashingAlgorithm returns the block hashing algorithm of the given
+// channel, as configured by its genesis block. The channels whose genesis
+// block wasn't seen are assumed to share the algorithm of the system channel.
+func (r *Replicator) blockHashingAlgorithm(channel string) func([]byte) []byte {
+	if channel == r.SystemChannel {
+		return BlockHashingAlgorithm(r.BootBlock)
+	}
+	if hashingAlgorithm, exists := r.hashingAlgorithms[channel]; exists {
+		return hashingAlgorithm
+	}
+	r.Logger.Warningf("Genesis block of channel %s wasn't seen, assuming its blocks are hashed like those of the system channel", channel)
+	return BlockHashingAlgorithm(r.BootBlock)
+}
+
 func (r *Replicator) appendBlock(block *common.Block, ledger LedgerWriter, channel string) {
 	height := ledger.Height()
 	if height > block.Header.Number {
@@ -256,11 +286,12 @@ func (r *Replicator) appendBlock(block *common.Block, ledger LedgerWriter, chann
 }
 
 func (r *Replicator) compareBootBlockWithSystemChannelLastConfigBlock(block *common.Block) {
+	hashingAlgorithm := BlockHashingAlgorithm(r.BootBlock)
 	// Overwrite the received block's data hash
-	block.Header.DataHash = block.Data.Hash()
+	block.Header.DataHash = hashingAlgorithm(block.Data.Bytes())
 
-	bootBlockHash := r.BootBlock.Header.Hash()
-	retrievedBlockHash := block.Header.Hash()
+	bootBlockHash := hashingAlgorithm(r.BootBlock.Header.Bytes())
+	retrievedBlockHash := hashingAlgorithm(block.Header.Bytes())
 	if bytes.Equal(bootBlockHash, retrievedBlockHash) {
 		return
 	}
@@ -381,6 +412,8 @@ func BlockPullerFromConfigBlock(conf PullerConfig, block *common.Block, verifier
 		return nil, errors.Errorf("unable to decode TLS certificate PEM: %s", base64.StdEncoding.EncodeToString(conf.TLSCert))
 	}
 
+	hashingAlgorithm := BlockHashingAlgorithm(block)
+
 	return &BlockPuller{
 		Logger:  flogging.MustGetLogger("orderer.common.cluster.replication"),
 		Dialer:  dialer,
@@ -390,7 +423,12 @@ func BlockPullerFromConfigBlock(conf PullerConfig, block *common.Block, verifier
 			if verifier == nil {
 				return errors.Errorf("couldn't acquire verifier for channel %s", channel)
 			}
-			return VerifyBlocks(blocks, verifier)
+			// The verifiers of the channels know their block hashing algorithm,
+			// which may differ from the one of the channel of the config block
+			if bh, ok := verifier.(blockHasher); ok {
+				return VerifyBlocksWithHashingAlgorithm(blocks, verifier, bh.BlockHashingAlgorithm())
+			}
+			return VerifyBlocksWithHashingAlgorithm(blocks, verifier, hashingAlgorithm)
 		},
 		MaxTotalBufferBytes: conf.MaxTotalBufferBytes,
 		Endpoints:           endpoints,
@@ -544,6 +582,7 @@ func (gbs GenesisBlocks) Names() []string {
 func (ci *ChainInspector) Channels() []ChannelGenesisBlock {
 	channels := make(map[string]ChannelGenesisBlock)
 	lastConfigBlockNum := ci.LastConfigBlock.Header.Number
+	hashingAlgorithm := BlockHashingAlgorithm(ci.LastConfigBlock)
 	var block *common.Block
 	var prevHash []byte
 	for seq := uint64(0); seq < lastConfigBlockNum; seq++ {
@@ -560,7 +599,7 @@ func (ci *ChainInspector) Channels() []ChannelGenesisBlock {
 			continue
 		}
 		// Set the previous hash for the next iteration
-		prevHash = block.Header.Hash()
+		prevHash = hashingAlgorithm(block.Header.Bytes())
 		if channel == "" {
 			ci.Logger.Info("Block", seq, "doesn't contain a new channel")
 			continue
@@ -577,7 +616,7 @@ func (ci *ChainInspector) Channels() []ChannelGenesisBlock {
 	// We don't need to verify the entire chain of all blocks we pulled,
 	// because the block puller calls VerifyBlockHash on all blocks it pulls.
 	last2Blocks := []*common.Block{block, ci.LastConfigBlock}
-	if err := VerifyBlockHash(1, last2Blocks); err != nil {
+	if err := VerifyBlockHashWithHashingAlgorithm(1, last2Blocks, hashingAlgorithm); err != nil {
 		ci.Logger.Panic("System channel pulled doesn't match the boot last config block:", err)
 	}
 
@@ -617,9 +656,9 @@ func ChannelCreationBlockToGenesisBlock(block *common.Block) (*common.Block, err
 		return nil, err
 	}
 	block.Data.Data = [][]byte{payload.Data}
-	block.Header.DataHash = block.Data.Hash()
 	block.Header.Number = 0
 	block.Header.PreviousHash = nil
+	block.Header.DataHash = BlockHashingAlgorithm(block)(block.Data.Bytes())
 	metadata := &common.BlockMetadata{
 		Metadata: make([][]byte, 4),
 	}
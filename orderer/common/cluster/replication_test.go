@@ -16,8 +16,11 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/common/mocks/crypto"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/cluster/mocks"
@@ -349,6 +352,79 @@ func TestPullChannelFailure(t *testing.T) {
 
 }
 
+func TestPullChannelBlockHashingAlgorithm(t *testing.T) {
+	// Scenario: The blocks of the system channel are hashed with SHA256,
+	// while the blocks of the channel pulled are hashed with SM3.
+	// The hash chain of the channel is verified with SM3.
+	channelGroup := common.NewConfigGroup()
+	channelGroup.Values[channelconfig.HashingAlgorithmKey] = &common.ConfigValue{
+		Value: utils.MarshalOrPanic(&common.HashingAlgorithm{Name: util.SM3}),
+	}
+	blockchain := []*common.Block{genesis.NewFactoryImpl(channelGroup).Block("mychannel")}
+	for seq := uint64(1); seq <= 3; seq++ {
+		block := common.NewBlock(seq, util.ComputeSM3(blockchain[seq-1].Header.Bytes()))
+		block.Data.Data = [][]byte{{1, 2, 3}}
+		block.Header.DataHash = util.ComputeSM3(block.Data.Bytes())
+		blockchain = append(blockchain, block)
+	}
+
+	var committedBlocks []*common.Block
+	lw := &mocks.LedgerWriter{}
+	lw.On("Append", mock.Anything).Return(nil).Run(func(arg mock.Arguments) {
+		committedBlocks = append(committedBlocks, arg.Get(0).(*common.Block))
+	})
+	lw.On("Height").Return(uint64(0))
+
+	lf := &mocks.LedgerFactory{}
+	lf.On("GetOrCreate", "mychannel").Return(lw, nil)
+
+	osn := newClusterNode(t)
+	defer osn.stop()
+
+	enqueueBlock := func(seq int) {
+		osn.blockResponses <- &orderer.DeliverResponse{
+			Type: &orderer.DeliverResponse_Block{
+				Block: blockchain[seq],
+			},
+		}
+	}
+
+	dialer := newCountingDialer()
+	bp := newBlockPuller(dialer, osn.srv.Address())
+	bp.FetchTimeout = time.Hour
+	bp.MaxPullBlockRetries = 1
+	// Do not buffer blocks in memory
+	bp.MaxTotalBufferBytes = 1
+
+	systemChannelBlock, err := configtxtest.MakeGenesisBlock("system")
+	assert.NoError(t, err)
+
+	r := cluster.Replicator{
+		Filter: cluster.AnyChannel,
+		AmIPartOfChannel: func(configBlock *common.Block) error {
+			return nil
+		},
+		Logger:        flogging.MustGetLogger("test"),
+		SystemChannel: "system",
+		LedgerFactory: lf,
+		Puller:        bp,
+		BootBlock:     systemChannelBlock,
+	}
+
+	osn.addExpectProbeAssert()
+	enqueueBlock(3)
+	osn.addExpectProbeAssert()
+	enqueueBlock(3)
+	osn.addExpectPullAssert(0)
+	for seq := range blockchain {
+		enqueueBlock(seq)
+	}
+
+	err = r.PullChannel("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, blockchain, committedBlocks)
+}
+
 func TestPullerConfigFromTopLevelConfig(t *testing.T) {
 	signer := &crypto.LocalSigner{}
 	expected := cluster.PullerConfig{
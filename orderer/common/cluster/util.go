@@ -67,13 +67,20 @@ func (mp MemberMapping) ByID(ID uint64) *Stub {
 	return mp[ID]
 }
 
-// LookupByClientCert retrieves a Stub with the given client certificate
+// LookupByClientCert retrieves a Stub with the given client certificate,
+// or with the given previous client certificate during its grace period
 func (mp MemberMapping) LookupByClientCert(cert []byte) *Stub {
 	for _, stub := range mp {
 		if bytes.Equal(stub.ClientTLSCert, cert) {
 			return stub
 		}
 	}
+	now := time.Now()
+	for _, stub := range mp {
+		if len(stub.PreviousClientTLSCert) > 0 && bytes.Equal(stub.PreviousClientTLSCert, cert) && stub.inGracePeriod(now) {
+			return stub
+		}
+	}
 	return nil
 }
 
@@ -205,6 +212,13 @@ type Dialer interface {
 // VerifyBlocks verifies the given consecutive sequence of blocks is valid,
 // and returns nil if it's valid, else an error.
 func VerifyBlocks(blockBuff []*common.Block, signatureVerifier BlockVerifier) error {
+	return VerifyBlocksWithHashingAlgorithm(blockBuff, signatureVerifier, util.ComputeSHA256)
+}
+
+// VerifyBlocksWithHashingAlgorithm verifies the given consecutive sequence of
+// blocks hashed with hashingAlgorithm is valid, and returns nil if it's valid,
+// else an error.
+func VerifyBlocksWithHashingAlgorithm(blockBuff []*common.Block, signatureVerifier BlockVerifier, hashingAlgorithm func([]byte) []byte) error {
 	if len(blockBuff) == 0 {
 		return errors.New("buffer is empty")
 	}
@@ -212,7 +226,7 @@ func VerifyBlocks(blockBuff []*common.Block, signatureVerifier BlockVerifier) er
 	// Equal to the hash in the header
 	// Equal to the previous hash in the succeeding block
 	for i := range blockBuff {
-		if err := VerifyBlockHash(i, blockBuff); err != nil {
+		if err := VerifyBlockHashWithHashingAlgorithm(i, blockBuff, hashingAlgorithm); err != nil {
 			return err
 		}
 	}
@@ -291,9 +305,31 @@ func ConfigFromBlock(block *common.Block) (*common.ConfigEnvelope, error) {
 	return configEnvelope, nil
 }
 
+// BlockHashingAlgorithm returns the algorithm hashing the blocks of the
+// channel configured by the given config block. The blocks of the channels
+// whose configuration cannot be retrieved are hashed with SHA256.
+func BlockHashingAlgorithm(configBlock *common.Block) func([]byte) []byte {
+	configEnvelope, err := ConfigFromBlock(configBlock)
+	if err != nil {
+		return util.ComputeSHA256
+	}
+	hashingAlgorithm, err := utils.GetHashingAlgorithmFromConfigGroup(configEnvelope.GetConfig().GetChannelGroup())
+	if err != nil {
+		return util.ComputeSHA256
+	}
+	return util.BlockHashingFunction(hashingAlgorithm)
+}
+
 // VerifyBlockHash verifies the hash chain of the block with the given index
 // among the blocks of the given block buffer.
 func VerifyBlockHash(indexInBuffer int, blockBuff []*common.Block) error {
+	return VerifyBlockHashWithHashingAlgorithm(indexInBuffer, blockBuff, util.ComputeSHA256)
+}
+
+// VerifyBlockHashWithHashingAlgorithm verifies the hash chain, computed with
+// hashingAlgorithm, of the block with the given index among the blocks of the
+// given block buffer.
+func VerifyBlockHashWithHashingAlgorithm(indexInBuffer int, blockBuff []*common.Block, hashingAlgorithm func([]byte) []byte) error {
 	if len(blockBuff) <= indexInBuffer {
 		return errors.Errorf("index %d out of bounds (total %d blocks)", indexInBuffer, len(blockBuff))
 	}
@@ -302,7 +338,7 @@ func VerifyBlockHash(indexInBuffer int, blockBuff []*common.Block) error {
 		return errors.New("missing block header")
 	}
 	seq := block.Header.Number
-	dataHash := block.Data.Hash()
+	dataHash := hashingAlgorithm(block.Data.Bytes())
 	// Verify data hash matches the hash in the header
 	if !bytes.Equal(dataHash, block.Header.DataHash) {
 		computedHash := hex.EncodeToString(dataHash)
@@ -321,9 +357,10 @@ func VerifyBlockHash(indexInBuffer int, blockBuff []*common.Block) error {
 		if prevSeq+1 != currSeq {
 			return errors.Errorf("sequences %d and %d were received consecutively", prevSeq, currSeq)
 		}
-		if !bytes.Equal(block.Header.PreviousHash, prevBlock.Header.Hash()) {
+		prevHash := hashingAlgorithm(prevBlock.Header.Bytes())
+		if !bytes.Equal(block.Header.PreviousHash, prevHash) {
 			claimedPrevHash := hex.EncodeToString(block.Header.PreviousHash)
-			actualPrevHash := hex.EncodeToString(prevBlock.Header.Hash())
+			actualPrevHash := hex.EncodeToString(prevHash)
 			return errors.Errorf("block [%d]'s hash (%s) mismatches %d's prev block hash (%s)",
 				prevSeq, actualPrevHash, currSeq, claimedPrevHash)
 		}
@@ -561,17 +598,34 @@ func (bva *BlockVerifierAssembler) VerifierFromConfig(configuration *common.Conf
 	policyMgr := bundle.PolicyManager()
 
 	return &BlockValidationPolicyVerifier{
-		Logger:    bva.Logger,
-		PolicyMgr: policyMgr,
-		Channel:   channel,
+		Logger:           bva.Logger,
+		PolicyMgr:        policyMgr,
+		Channel:          channel,
+		HashingAlgorithm: bundle.ChannelConfig().BlockHashingAlgorithm(),
 	}, nil
 }
 
+// blockHasher is implemented by the BlockVerifiers knowing the block hashing
+// algorithm of their channel.
+type blockHasher interface {
+	BlockHashingAlgorithm() func([]byte) []byte
+}
+
 // BlockValidationPolicyVerifier verifies signatures based on the block validation policy.
 type BlockValidationPolicyVerifier struct {
-	Logger    *flogging.FabricLogger
-	Channel   string
-	PolicyMgr policies.Manager
+	Logger           *flogging.FabricLogger
+	Channel          string
+	PolicyMgr        policies.Manager
+	HashingAlgorithm func([]byte) []byte
+}
+
+// BlockHashingAlgorithm returns the algorithm hashing the blocks of the channel,
+// SHA256 if none was set.
+func (bv *BlockValidationPolicyVerifier) BlockHashingAlgorithm() func([]byte) []byte {
+	if bv.HashingAlgorithm == nil {
+		return util.ComputeSHA256
+	}
+	return bv.HashingAlgorithm
 }
 
 // VerifyBlockSignature verifies the signed data associated to a block, optionally with the given config envelope.
@@ -12,6 +12,7 @@ import (
 	"errors"
 	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
 	"io/ioutil"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
@@ -29,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/cluster/mocks"
@@ -159,6 +161,33 @@ func TestDERtoPEM(t *testing.T) {
 	assert.Equal(t, cluster.DERtoPEM(keyPair.TLSCert.Raw), string(keyPair.Cert))
 }
 
+func TestLookupByClientCert(t *testing.T) {
+	t.Parallel()
+	mapping := cluster.MemberMapping{}
+	mapping.Put(&cluster.Stub{RemoteNode: cluster.RemoteNode{ID: 1, ClientTLSCert: []byte("cert1")}})
+	mapping.Put(&cluster.Stub{RemoteNode: cluster.RemoteNode{
+		ID:                    2,
+		ClientTLSCert:         []byte("new cert2"),
+		PreviousClientTLSCert: []byte("cert2"),
+		GracePeriodEnd:        time.Now().Add(time.Hour),
+	}})
+	mapping.Put(&cluster.Stub{RemoteNode: cluster.RemoteNode{
+		ID:                    3,
+		ClientTLSCert:         []byte("new cert3"),
+		PreviousClientTLSCert: []byte("cert3"),
+		GracePeriodEnd:        time.Now().Add(-time.Hour),
+	}})
+
+	assert.Equal(t, uint64(1), mapping.LookupByClientCert([]byte("cert1")).ID)
+	assert.Equal(t, uint64(2), mapping.LookupByClientCert([]byte("new cert2")).ID)
+	// the previous certificate is accepted during the grace period
+	assert.Equal(t, uint64(2), mapping.LookupByClientCert([]byte("cert2")).ID)
+	assert.Equal(t, uint64(3), mapping.LookupByClientCert([]byte("new cert3")).ID)
+	// and rejected after it
+	assert.Nil(t, mapping.LookupByClientCert([]byte("cert3")))
+	assert.Nil(t, mapping.LookupByClientCert([]byte("cert4")))
+}
+
 func TestStandardDialer(t *testing.T) {
 	t.Parallel()
 	emptyCertificate := []byte("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----")
@@ -829,6 +858,27 @@ func TestBlockVerifierAssembler(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.NoError(t, verifier.VerifyBlockSignature(nil, nil))
+		hash := verifier.(*cluster.BlockValidationPolicyVerifier).BlockHashingAlgorithm()
+		assert.Equal(t, reflect.ValueOf(util.ComputeSHA256).Pointer(), reflect.ValueOf(hash).Pointer())
+	})
+
+	t.Run("SM3 config envelope", func(t *testing.T) {
+		config := configtxgentest.Load(localconfig.SampleInsecureSoloProfile)
+		config.HashingAlgorithm = util.SM3
+		config.Capabilities = map[string]bool{capabilities.ChannelV1_4_10: true}
+		group, err := encoder.NewChannelGroup(config)
+		assert.NoError(t, err)
+
+		bva := &cluster.BlockVerifierAssembler{}
+		verifier, err := bva.VerifierFromConfig(&common.ConfigEnvelope{
+			Config: &common.Config{
+				ChannelGroup: group,
+			},
+		}, "mychannel")
+		assert.NoError(t, err)
+
+		hash := verifier.(*cluster.BlockValidationPolicyVerifier).BlockHashingAlgorithm()
+		assert.Equal(t, reflect.ValueOf(util.ComputeSM3).Pointer(), reflect.ValueOf(hash).Pointer())
 	})
 
 	t.Run("Bad config envelope", func(t *testing.T) {
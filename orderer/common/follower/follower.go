@@ -0,0 +1,277 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package follower
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// DefaultPullInterval is the default time a follower waits before it polls
+// the ordering nodes again, once it has pulled all their blocks.
+const DefaultPullInterval = time.Second * 10
+
+// commitPollInterval is the interval at which a follower checks whether a
+// config block it wrote has been committed to the ledger.
+const commitPollInterval = time.Millisecond * 10
+
+//go:generate counterfeiter -o mocks/block_puller.go --fake-name BlockPuller . BlockPuller
+
+// BlockPuller pulls the blocks of a channel from the ordering nodes servicing it
+type BlockPuller interface {
+	PullBlock(seq uint64) *common.Block
+	HeightsByEndpoints() (map[string]uint64, error)
+	Close()
+}
+
+// CreateBlockPuller creates a BlockPuller from the latest config of the channel.
+// It is passed into the chain so that tests could mock it.
+type CreateBlockPuller func() (BlockPuller, error)
+
+// Options contains the configuration of a follower chain.
+type Options struct {
+	Logger *flogging.FabricLogger
+
+	// PullInterval is the time the chain waits before it polls the ordering
+	// nodes again, once it has pulled all their blocks.
+	PullInterval time.Duration
+
+	// IsConsenter returns nil if this node is a consenter of the channel
+	// according to the given config block.
+	IsConsenter func(configBlock *common.Block) error
+
+	// Join is called once the chain has written a config block which adds this
+	// node to the consenters of the channel, after the chain has stopped.
+	Join func()
+}
+
+// Chain implements consensus.Chain for the channels this node is not a consenter of.
+// It continuously pulls the blocks of the channel from the consenters, so that it can
+// serve them to Deliver clients, and joins the channel once this node is added to its
+// consenters.
+type Chain struct {
+	support      consensus.ConsenterSupport
+	createPuller CreateBlockPuller
+	opts         Options
+	logger       *flogging.FabricLogger
+
+	onboarding uint32 // set to 1 while the chain is behind the cluster
+
+	startC   chan struct{} // Closes when the chain is started
+	haltC    chan struct{} // Closes when the chain is asked to halt
+	doneC    chan struct{} // Closes when the chain stops following the channel
+	haltOnce sync.Once
+}
+
+// NewChain constructs a follower chain object.
+func NewChain(support consensus.ConsenterSupport, opts Options, f CreateBlockPuller) *Chain {
+	if opts.PullInterval == 0 {
+		opts.PullInterval = DefaultPullInterval
+	}
+
+	return &Chain{
+		support:      support,
+		createPuller: f,
+		opts:         opts,
+		logger:       opts.Logger.With("channel", support.ChainID()),
+		startC:       make(chan struct{}),
+		haltC:        make(chan struct{}),
+		doneC:        make(chan struct{}),
+	}
+}
+
+// Order rejects transactions, as this node does not order the channel.
+func (c *Chain) Order(_ *common.Envelope, _ uint64) error {
+	return c.notConsenter()
+}
+
+// Configure rejects config transactions, as this node does not order the channel.
+func (c *Chain) Configure(_ *common.Envelope, _ uint64) error {
+	return c.notConsenter()
+}
+
+// WaitReady rejects broadcast requests, as this node does not order the channel.
+func (c *Chain) WaitReady() error {
+	return c.notConsenter()
+}
+
+func (c *Chain) notConsenter() error {
+	return errors.Errorf("orderer is a follower of channel %s, and is not a consenter of it", c.support.ChainID())
+}
+
+// Errored returns a channel which closes when the chain stops following the
+// channel, so that Deliver clients fetch the blocks of the channel until then.
+func (c *Chain) Errored() <-chan struct{} {
+	return c.doneC
+}
+
+// Start starts pulling the blocks of the channel.
+func (c *Chain) Start() {
+	c.logger.Infof("Starting to follow the channel from block [%d]", c.support.Height())
+	close(c.startC)
+
+	go func() {
+		joined := c.follow()
+		close(c.doneC)
+		if joined {
+			c.opts.Join()
+		}
+	}()
+}
+
+// Halt stops the chain.
+func (c *Chain) Halt() {
+	select {
+	case <-c.startC:
+	default:
+		c.logger.Warnf("Attempted to halt a chain that has not started")
+		return
+	}
+
+	c.haltOnce.Do(func() { close(c.haltC) })
+	<-c.doneC
+}
+
+// StatusReport returns the ClusterRelation & Status
+func (c *Chain) StatusReport() (types.ClusterRelation, types.Status) {
+	if atomic.LoadUint32(&c.onboarding) == 1 {
+		return types.ClusterRelationFollower, types.StatusOnBoarding
+	}
+	return types.ClusterRelationFollower, types.StatusActive
+}
+
+// follow pulls the blocks of the channel until the chain is halted, and
+// returns true if it stopped because this node was added to the consenters.
+func (c *Chain) follow() bool {
+	var puller BlockPuller
+	defer func() {
+		if puller != nil {
+			puller.Close()
+		}
+	}()
+
+	next := c.support.Height()
+	for !c.halted() {
+		if puller == nil {
+			p, err := c.createPuller()
+			if err != nil {
+				c.logger.Errorf("Failed to create block puller: %s", err)
+				if !c.wait(c.opts.PullInterval) {
+					return false
+				}
+				continue
+			}
+			puller = p
+		}
+
+		target := c.clusterHeight(puller)
+		if next >= target {
+			atomic.StoreUint32(&c.onboarding, 0)
+			if !c.wait(c.opts.PullInterval) {
+				return false
+			}
+			continue
+		}
+
+		atomic.StoreUint32(&c.onboarding, 1)
+		c.logger.Debugf("Pulling blocks [%d, %d] from the cluster", next, target-1)
+
+		for next < target && !c.halted() {
+			block := puller.PullBlock(next)
+			if block == nil {
+				c.logger.Warnf("Failed to pull block [%d] from the cluster", next)
+				break
+			}
+
+			// The block is written as pulled, with the signatures of the consenters
+			// and their metadata, instead of being signed by this node.
+			c.support.WriteSignedBlock(block, nil)
+			next++
+
+			if !utils.IsConfigBlock(block) {
+				continue
+			}
+
+			// The config block is committed asynchronously, and the puller, as well as
+			// the chain this node switches to, are created from the config in the ledger.
+			if !c.waitCommitted(next) {
+				return false
+			}
+
+			if err := c.opts.IsConsenter(block); err == nil {
+				c.logger.Infof("Config block [%d] adds this node to the consenters of the channel, joining it", block.Header.Number)
+				return true
+			}
+
+			// the config block might change the endpoints of the ordering nodes
+			puller.Close()
+			puller = nil
+			break
+		}
+
+		if puller != nil && next < target && !c.wait(c.opts.PullInterval) {
+			return false
+		}
+	}
+
+	return false
+}
+
+// clusterHeight returns the highest block height of the ordering nodes
+func (c *Chain) clusterHeight(puller BlockPuller) uint64 {
+	heights, err := puller.HeightsByEndpoints()
+	if err != nil {
+		c.logger.Debugf("Failed to reach some of the ordering nodes: %s", err)
+	}
+
+	var max uint64
+	for _, height := range heights {
+		if height > max {
+			max = height
+		}
+	}
+	return max
+}
+
+// waitCommitted waits until the ledger reaches the given height, and
+// returns false if the chain is halted meanwhile.
+func (c *Chain) waitCommitted(height uint64) bool {
+	for c.support.Height() < height {
+		if !c.wait(commitPollInterval) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Chain) halted() bool {
+	select {
+	case <-c.haltC:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait waits for the given duration, and returns false if the chain
+// is halted meanwhile.
+func (c *Chain) wait(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-c.haltC:
+		return false
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package follower_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/follower"
+	"github.com/hyperledger/fabric/orderer/common/follower/mocks"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	consensusmocks "github.com/hyperledger/fabric/orderer/consensus/mocks"
+	"github.com/hyperledger/fabric/orderer/mocks/common/multichannel"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBlock(number uint64, headerType common.HeaderType) *common.Block {
+	block := common.NewBlock(number, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(&common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					Type:      int32(headerType),
+					ChannelId: "mychannel",
+				}),
+			},
+		}),
+	})}
+	return block
+}
+
+// newPuller returns a puller serving the given blocks, starting from block 1
+func newPuller(blocks ...*common.Block) *mocks.BlockPuller {
+	puller := &mocks.BlockPuller{}
+	puller.HeightsByEndpointsReturns(map[string]uint64{
+		"orderer1:7050": uint64(len(blocks)),
+		"orderer2:7050": uint64(len(blocks) + 1),
+	}, nil)
+	puller.PullBlockStub = func(seq uint64) *common.Block {
+		return blocks[seq-1]
+	}
+	return puller
+}
+
+func newSupport() *multichannel.ConsenterSupport {
+	return &multichannel.ConsenterSupport{
+		ChainIDVal: "mychannel",
+		HeightVal:  1,
+		Blocks:     make(chan *common.Block, 10),
+	}
+}
+
+func TestFollowerPullsBlocks(t *testing.T) {
+	support := newSupport()
+	puller := newPuller(newBlock(1, common.HeaderType_ENDORSER_TRANSACTION), newBlock(2, common.HeaderType_ENDORSER_TRANSACTION))
+
+	// an unreachable ordering node is skipped
+	puller.HeightsByEndpointsReturns(map[string]uint64{"orderer1:7050": 3}, errors.New("orderer2:7050 is unreachable"))
+
+	chain := follower.NewChain(support, follower.Options{
+		Logger:       flogging.MustGetLogger("test"),
+		PullInterval: time.Hour,
+		IsConsenter:  func(*common.Block) error { return cluster.ErrNotInChannel },
+		Join:         func() { t.Fatal("joined the channel") },
+	}, func() (follower.BlockPuller, error) { return puller, nil })
+
+	relation, status := chain.StatusReport()
+	assert.Equal(t, types.ClusterRelationFollower, relation)
+	assert.Equal(t, types.StatusActive, status)
+
+	chain.Start()
+	defer chain.Halt()
+
+	for _, expected := range []uint64{1, 2} {
+		select {
+		case block := <-support.Blocks:
+			assert.Equal(t, expected, block.Header.Number)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block [%d] was not written", expected)
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		_, status := chain.StatusReport()
+		return status == types.StatusActive && puller.HeightsByEndpointsCallCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	select {
+	case <-chain.Errored():
+		t.Fatal("follower errored while following the channel")
+	default:
+	}
+}
+
+func TestFollowerKeepsBlockSignatures(t *testing.T) {
+	block := newBlock(1, common.HeaderType_ENDORSER_TRANSACTION)
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&common.Metadata{
+		Signatures: []*common.MetadataSignature{{Signature: []byte("consenter signature")}},
+	})
+	puller := newPuller(block)
+
+	support := &consensusmocks.FakeConsenterSupport{}
+	support.ChainIDReturns("mychannel")
+	support.HeightReturns(1)
+
+	chain := follower.NewChain(support, follower.Options{
+		Logger:       flogging.MustGetLogger("test"),
+		PullInterval: time.Hour,
+		IsConsenter:  func(*common.Block) error { return cluster.ErrNotInChannel },
+		Join:         func() { t.Fatal("joined the channel") },
+	}, func() (follower.BlockPuller, error) { return puller, nil })
+
+	chain.Start()
+	defer chain.Halt()
+
+	require.Eventually(t, func() bool { return support.WriteSignedBlockCallCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	written, metadata := support.WriteSignedBlockArgsForCall(0)
+	assert.Equal(t, block, written)
+	assert.Nil(t, metadata)
+	assert.Zero(t, support.WriteBlockCallCount())
+	assert.Zero(t, support.WriteConfigBlockCallCount())
+}
+
+func TestFollowerRejectsTransactions(t *testing.T) {
+	chain := follower.NewChain(newSupport(), follower.Options{Logger: flogging.MustGetLogger("test")}, nil)
+
+	expected := "orderer is a follower of channel mychannel, and is not a consenter of it"
+	assert.EqualError(t, chain.Order(nil, 0), expected)
+	assert.EqualError(t, chain.Configure(nil, 0), expected)
+	assert.EqualError(t, chain.WaitReady(), expected)
+
+	assert.NotPanics(t, chain.Halt)
+}
+
+func TestFollowerJoinsOnceConsenter(t *testing.T) {
+	support := newSupport()
+	configBlock := newBlock(2, common.HeaderType_CONFIG)
+	puller := newPuller(
+		newBlock(1, common.HeaderType_ENDORSER_TRANSACTION),
+		configBlock,
+		newBlock(3, common.HeaderType_ENDORSER_TRANSACTION),
+	)
+
+	var isConsenterCalls int
+	joined := make(chan struct{})
+	var chain *follower.Chain
+	chain = follower.NewChain(support, follower.Options{
+		Logger:       flogging.MustGetLogger("test"),
+		PullInterval: time.Hour,
+		IsConsenter: func(block *common.Block) error {
+			isConsenterCalls++
+			assert.Equal(t, configBlock, block)
+			return nil
+		},
+		Join: func() {
+			// the follower is halted while the chain is re-created
+			chain.Halt()
+			close(joined)
+		},
+	}, func() (follower.BlockPuller, error) { return puller, nil })
+
+	chain.Start()
+
+	select {
+	case <-joined:
+	case <-time.After(5 * time.Second):
+		t.Fatal("follower did not join the channel")
+	}
+
+	require.Len(t, support.Blocks, 2)
+	assert.Equal(t, 1, isConsenterCalls)
+	assert.Equal(t, 2, puller.PullBlockCallCount())
+	assert.Equal(t, 1, puller.CloseCallCount())
+
+	_, open := <-chain.Errored()
+	assert.False(t, open)
+}
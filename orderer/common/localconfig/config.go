@@ -36,6 +36,8 @@ type TopLevel struct {
 	Consensus  interface{}
 	Operations Operations
 	Metrics    Metrics
+
+	ChannelParticipation ChannelParticipation
 }
 
 // General contains config which should be common among all orderer types.
@@ -56,6 +58,7 @@ type General struct {
 	LocalMSPID        string
 	BCCSP             *bccsp.FactoryOpts
 	Authentication    Authentication
+	Throttling        Throttling
 }
 
 type Cluster struct {
@@ -63,6 +66,8 @@ type Cluster struct {
 	ListenPort                           uint16
 	ServerCertificate                    string
 	ServerPrivateKey                     string
+	ServerEncCertificate                 string
+	ServerEncPrivateKey                  string
 	ClientCertificate                    string
 	ClientPrivateKey                     string
 	RootCAs                              []string
@@ -90,6 +95,8 @@ type TLS struct {
 	Enabled               bool
 	PrivateKey            string
 	Certificate           string
+	EncPrivateKey         string
+	EncCertificate        string
 	RootCAs               []string
 	ClientAuthRequired    bool
 	ClientRootCAs         []string
@@ -110,6 +117,17 @@ type Authentication struct {
 	NoExpirationChecks bool
 }
 
+// Throttling contains configuration for rate limiting the messages clients
+// broadcast to a channel, per client identity and per organization.
+type Throttling struct {
+	Enabled           bool
+	ClientRate        int
+	ClientBurst       int
+	OrgRate           int
+	OrgBurst          int
+	InactivityTimeout time.Duration
+}
+
 // Profile contains configuration for Go pprof profiling.
 type Profile struct {
 	Enabled bool
@@ -118,8 +136,16 @@ type Profile struct {
 
 // FileLedger contains configuration for the file-based ledger.
 type FileLedger struct {
-	Location string
-	Prefix   string
+	Location  string
+	Prefix    string
+	Retention Retention
+}
+
+// Retention contains configuration for pruning the block files of the file-based ledger.
+type Retention struct {
+	MaxBlocks  uint64
+	MaxAge     time.Duration
+	ArchiveDir string
 }
 
 // RAMLedger contains configuration for the RAM ledger.
@@ -197,6 +223,13 @@ type Operations struct {
 	TLS           TLS
 }
 
+// ChannelParticipation provides the channel participation API configuration for the orderer.
+// Channel participation uses the same ListenAddress and TLS settings of the Operations service.
+type ChannelParticipation struct {
+	Enabled            bool
+	MaxRequestBodySize uint32
+}
+
 // Operations confiures the metrics provider for the orderer.
 type Metrics struct {
 	Provider string
@@ -242,6 +275,9 @@ var Defaults = TopLevel{
 		Authentication: Authentication{
 			TimeWindow: time.Duration(15 * time.Minute),
 		},
+		Throttling: Throttling{
+			InactivityTimeout: time.Minute * 5,
+		},
 	},
 	RAMLedger: RAMLedger{
 		HistorySize: 10000,
@@ -292,6 +328,10 @@ var Defaults = TopLevel{
 	Metrics: Metrics{
 		Provider: "disabled",
 	},
+	ChannelParticipation: ChannelParticipation{
+		Enabled:            false,
+		MaxRequestBodySize: 1024 * 1024,
+	},
 }
 
 // Load parses the orderer YAML file and environment, producing
@@ -332,6 +372,12 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		c.General.TLS.ClientRootCAs = translateCAs(configDir, c.General.TLS.ClientRootCAs)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.PrivateKey)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
+		if c.General.TLS.EncPrivateKey != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.EncPrivateKey)
+		}
+		if c.General.TLS.EncCertificate != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.General.TLS.EncCertificate)
+		}
 		coreconfig.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		coreconfig.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
 	}()
@@ -351,6 +397,8 @@ func (c *TopLevel) completeInitialization(configDir string) {
 
 		case c.General.GenesisMethod == "":
 			c.General.GenesisMethod = Defaults.General.GenesisMethod
+		case c.General.GenesisMethod == "none" && !c.ChannelParticipation.Enabled:
+			logger.Panicf("General.GenesisMethod can only be set to none if ChannelParticipation.Enabled is set to true.")
 		case c.General.GenesisFile == "":
 			c.General.GenesisFile = Defaults.General.GenesisFile
 		case c.General.GenesisProfile == "":
@@ -375,6 +423,8 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			c.General.Cluster.ReplicationBackgroundRefreshInterval = Defaults.General.Cluster.ReplicationBackgroundRefreshInterval
 		case c.General.Cluster.CertExpirationWarningThreshold == 0:
 			c.General.Cluster.CertExpirationWarningThreshold = Defaults.General.Cluster.CertExpirationWarningThreshold
+		case c.General.Throttling.Enabled && c.General.Throttling.InactivityTimeout == 0:
+			c.General.Throttling.InactivityTimeout = Defaults.General.Throttling.InactivityTimeout
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.Certificate == "":
 			logger.Panicf("General.Kafka.TLS.Certificate must be set if General.Kafka.TLS.Enabled is set to true.")
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.PrivateKey == "":
@@ -447,6 +497,10 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Retry.Consumer.RetryBackoff unset, setting to %v", Defaults.Kafka.Retry.Consumer.RetryBackoff)
 			c.Kafka.Retry.Consumer.RetryBackoff = Defaults.Kafka.Retry.Consumer.RetryBackoff
 
+		case c.ChannelParticipation.MaxRequestBodySize == 0:
+			logger.Infof("ChannelParticipation.MaxRequestBodySize unset, setting to %v", Defaults.ChannelParticipation.MaxRequestBodySize)
+			c.ChannelParticipation.MaxRequestBodySize = Defaults.ChannelParticipation.MaxRequestBodySize
+
 		case c.Kafka.Version == sarama.KafkaVersion{}:
 			logger.Infof("Kafka.Version unset, setting to %v", Defaults.Kafka.Version)
 			c.Kafka.Version = Defaults.Kafka.Version
@@ -235,3 +235,18 @@ func TestConnectionTimeout(t *testing.T) {
 		assert.Equal(t, cfg.General.ConnectionTimeout, 10*time.Second)
 	})
 }
+
+func TestChannelParticipationDefaults(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	cfg, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, Defaults.ChannelParticipation, cfg.ChannelParticipation)
+
+	uconf := &TopLevel{General: General{GenesisMethod: "none"}}
+	assert.Panics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should panic without channel participation")
+
+	uconf = &TopLevel{General: General{GenesisMethod: "none"}, ChannelParticipation: ChannelParticipation{Enabled: true}}
+	assert.NotPanics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should not panic with channel participation")
+	assert.Equal(t, Defaults.ChannelParticipation.MaxRequestBodySize, uconf.ChannelParticipation.MaxRequestBodySize)
+}
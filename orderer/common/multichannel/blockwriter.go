@@ -26,6 +26,7 @@ type blockWriterSupport interface {
 	Update(*newchannelconfig.Bundle)
 	CreateBundle(channelID string, config *cb.Config) (*newchannelconfig.Bundle, error)
 	SharedConfig() newchannelconfig.Orderer
+	ChannelConfig() newchannelconfig.Channel
 }
 
 // BlockWriter efficiently writes the blockchain to disk.
@@ -64,8 +65,10 @@ func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSuppor
 }
 
 // CreateNextBlock creates a new block with the next block number, and the given contents.
+// The block is hashed with the block hashing algorithm of the channel.
 func (bw *BlockWriter) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
-	previousBlockHash := bw.lastBlock.Header.Hash()
+	hash := bw.support.ChannelConfig().BlockHashingAlgorithm()
+	previousBlockHash := hash(bw.lastBlock.Header.Bytes())
 
 	data := &cb.BlockData{
 		Data: make([][]byte, len(messages)),
@@ -80,7 +83,7 @@ func (bw *BlockWriter) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
 	}
 
 	block := cb.NewBlock(bw.lastBlock.Header.Number+1, previousBlockHash)
-	block.Header.DataHash = data.Hash()
+	block.Header.DataHash = hash(data.Bytes())
 	block.Data = data
 
 	return block
@@ -90,6 +93,23 @@ func (bw *BlockWriter) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
 // This call will block until the new config has taken effect, then will return
 // while the block is written asynchronously to disk.
 func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []byte) {
+	bw.WriteBlock(block, bw.applyConfigBlock(block, encodedMetadataValue))
+}
+
+// WriteSignedBlock should be invoked by consenters which gather the signatures of
+// the block from several ordering nodes. The block is written as by WriteBlock,
+// or WriteConfigBlock if it contains a config transaction, except that it keeps
+// the signatures set in its SIGNATURES metadata instead of being signed by this node.
+func (bw *BlockWriter) WriteSignedBlock(block *cb.Block, encodedMetadataValue []byte) {
+	if utils.IsConfigBlock(block) {
+		encodedMetadataValue = bw.applyConfigBlock(block, encodedMetadataValue)
+	}
+	bw.writeBlock(block, encodedMetadataValue, false)
+}
+
+// applyConfigBlock applies the config transaction of the block and returns the
+// orderer metadata to write along with it.
+func (bw *BlockWriter) applyConfigBlock(block *cb.Block, encodedMetadataValue []byte) []byte {
 	ctx, err := utils.ExtractEnvelope(block, 0)
 	if err != nil {
 		logger.Panicf("Told to write a config block, but could not get configtx: %s", err)
@@ -156,7 +176,7 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 		logger.Panicf("Told to write a config block with unknown header type: %v", chdr.Type)
 	}
 
-	bw.WriteBlock(block, encodedMetadataValue)
+	return encodedMetadataValue
 }
 
 // WriteBlock should be invoked for blocks which contain normal transactions.
@@ -166,25 +186,31 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 // then release the lock.  This allows the calling thread to begin assembling the next block
 // before the commit phase is complete.
 func (bw *BlockWriter) WriteBlock(block *cb.Block, encodedMetadataValue []byte) {
+	bw.writeBlock(block, encodedMetadataValue, true)
+}
+
+func (bw *BlockWriter) writeBlock(block *cb.Block, encodedMetadataValue []byte, sign bool) {
 	bw.committingBlock.Lock()
 	bw.lastBlock = block
 
 	go func() {
 		defer bw.committingBlock.Unlock()
-		bw.commitBlock(encodedMetadataValue)
+		bw.commitBlock(encodedMetadataValue, sign)
 	}()
 }
 
 // commitBlock should only ever be invoked with the bw.committingBlock held
 // this ensures that the encoded config sequence numbers stay in sync
-func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte) {
+func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte, sign bool) {
 	// Set the orderer-related metadata field
 	if encodedMetadataValue != nil {
 		bw.lastBlock.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: encodedMetadataValue})
 	}
 
 	bw.addLastConfigSignature(bw.lastBlock)
-	bw.addBlockSignature(bw.lastBlock)
+	if sign {
+		bw.addBlockSignature(bw.lastBlock)
+	}
 
 	err := bw.support.Append(bw.lastBlock)
 	if err != nil {
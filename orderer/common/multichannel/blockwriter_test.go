@@ -9,15 +9,18 @@ package multichannel
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	newchannelconfig "github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/orderer"
@@ -29,7 +32,8 @@ type mockBlockWriterSupport struct {
 	*mockconfigtx.Validator
 	crypto.LocalSigner
 	blockledger.ReadWriter
-	fakeConfig *mock.OrdererConfig
+	fakeConfig    *mock.OrdererConfig
+	channelConfig newchannelconfig.Channel
 }
 
 func (mbws mockBlockWriterSupport) Update(bundle *newchannelconfig.Bundle) {
@@ -44,11 +48,18 @@ func (mbws mockBlockWriterSupport) SharedConfig() newchannelconfig.Orderer {
 	return mbws.fakeConfig
 }
 
+func (mbws mockBlockWriterSupport) ChannelConfig() newchannelconfig.Channel {
+	if mbws.channelConfig == nil {
+		return &mockconfig.Channel{}
+	}
+	return mbws.channelConfig
+}
+
 func TestCreateBlock(t *testing.T) {
 	seedBlock := cb.NewBlock(7, []byte("lasthash"))
 	seedBlock.Data.Data = [][]byte{[]byte("somebytes")}
 
-	bw := &BlockWriter{lastBlock: seedBlock}
+	bw := &BlockWriter{lastBlock: seedBlock, support: &mockBlockWriterSupport{}}
 	block := bw.CreateNextBlock([]*cb.Envelope{
 		{Payload: []byte("some other bytes")},
 	})
@@ -58,6 +69,25 @@ func TestCreateBlock(t *testing.T) {
 	assert.Equal(t, seedBlock.Header.Hash(), block.Header.PreviousHash)
 }
 
+func TestCreateBlockWithBlockHashingAlgorithm(t *testing.T) {
+	seedBlock := cb.NewBlock(7, []byte("lasthash"))
+	seedBlock.Data.Data = [][]byte{[]byte("somebytes")}
+
+	bw := &BlockWriter{
+		lastBlock: seedBlock,
+		support: &mockBlockWriterSupport{
+			channelConfig: &mockconfig.Channel{BlockHashingAlgorithmVal: util.ComputeSM3},
+		},
+	}
+	block := bw.CreateNextBlock([]*cb.Envelope{
+		{Payload: []byte("some other bytes")},
+	})
+
+	assert.Equal(t, seedBlock.Header.Number+1, block.Header.Number)
+	assert.Equal(t, util.ComputeSM3(block.Data.Bytes()), block.Header.DataHash)
+	assert.Equal(t, util.ComputeSM3(seedBlock.Header.Bytes()), block.Header.PreviousHash)
+}
+
 func TestBlockSignature(t *testing.T) {
 	rlf := ramledger.New(2)
 	l, err := rlf.GetOrCreate("mychannel")
@@ -76,7 +106,7 @@ func TestBlockSignature(t *testing.T) {
 	}
 
 	consensusMetadata := []byte("bar")
-	bw.commitBlock(consensusMetadata)
+	bw.commitBlock(consensusMetadata, true)
 
 	it, seq := l.Iterator(&orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{}})
 	assert.Equal(t, uint64(1), seq)
@@ -94,6 +124,49 @@ func TestBlockSignature(t *testing.T) {
 	assert.NotNil(t, md.Signatures, "Should have signature")
 }
 
+func TestSignedBlock(t *testing.T) {
+	rlf := ramledger.New(2)
+	l, err := rlf.GetOrCreate("mychannel")
+	assert.NoError(t, err)
+	lastBlock := cb.NewBlock(0, nil)
+	l.Append(lastBlock)
+
+	bw := &BlockWriter{
+		lastConfigBlockNum: 42,
+		support: &mockBlockWriterSupport{
+			LocalSigner: mockCrypto(),
+			Validator:   &mockconfigtx.Validator{},
+			ReadWriter:  l,
+		},
+		lastBlock: lastBlock,
+	}
+
+	signatures := &cb.Metadata{
+		Value: []byte("signed value"),
+		Signatures: []*cb.MetadataSignature{
+			{SignatureHeader: []byte("header1"), Signature: []byte("signature1")},
+			{SignatureHeader: []byte("header2"), Signature: []byte("signature2")},
+		},
+	}
+	block := cb.NewBlock(1, lastBlock.Header.Hash())
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(signatures)
+
+	bw.WriteSignedBlock(block, []byte("bar"))
+	bw.committingBlock.Lock()
+	bw.committingBlock.Unlock()
+
+	it, seq := l.Iterator(&orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{}})
+	assert.Equal(t, uint64(1), seq)
+	committedBlock, status := it.Next()
+	assert.Equal(t, cb.Status_SUCCESS, status)
+
+	md := utils.GetMetadataFromBlockOrPanic(committedBlock, cb.BlockMetadataIndex_SIGNATURES)
+	assert.True(t, proto.Equal(signatures, md), "Signatures are kept as they were gathered")
+	md = utils.GetMetadataFromBlockOrPanic(committedBlock, cb.BlockMetadataIndex_ORDERER)
+	assert.Equal(t, []byte("bar"), md.Value)
+	assert.Equal(t, uint64(42), utils.GetLastConfigIndexFromBlockOrPanic(committedBlock))
+}
+
 func TestBlockLastConfig(t *testing.T) {
 	lastConfigSeq := uint64(6)
 	newConfigSeq := lastConfigSeq + 1
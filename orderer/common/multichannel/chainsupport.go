@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -27,6 +28,7 @@ type ChainSupport struct {
 	consensus.Chain
 	cutter blockcutter.Receiver
 	crypto.LocalSigner
+	statusReporter consensus.StatusReporter
 }
 
 func newChainSupport(
@@ -74,6 +76,11 @@ func newChainSupport(
 		logger.Panicf("[channel: %s] Error creating consenter: %s", cs.ChainID(), err)
 	}
 
+	cs.statusReporter, ok = cs.Chain.(consensus.StatusReporter)
+	if !ok { // Non-cluster types: solo, kafka
+		cs.statusReporter = consensus.StaticStatusReporter{ClusterRelation: types.ClusterRelationNone, Status: types.StatusActive}
+	}
+
 	logger.Debugf("[channel: %s] Done creating channel support resources", cs.ChainID())
 
 	return cs
@@ -101,6 +108,11 @@ func (cs *ChainSupport) start() {
 	cs.Chain.Start()
 }
 
+// StatusReport returns the cluster relation of the node in this channel, and its status in it.
+func (cs *ChainSupport) StatusReport() (types.ClusterRelation, types.Status) {
+	return cs.statusReporter.StatusReport()
+}
+
 // BlockCutter returns the blockcutter.Receiver instance for this channel.
 func (cs *ChainSupport) BlockCutter() blockcutter.Receiver {
 	return cs.cutter
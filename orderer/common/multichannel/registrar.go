@@ -11,6 +11,7 @@ package multichannel
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
@@ -22,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -186,7 +188,7 @@ func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 				logger.Panicf("Error reading genesis block of system channel '%s'", chainID)
 			}
 			logger.Infof("Starting system channel '%s' with genesis block hash %x and orderer type %s",
-				chainID, genesisBlock.Header.Hash(), chain.SharedConfig().ConsensusType())
+				chainID, chain.ChannelConfig().BlockHashingAlgorithm()(genesisBlock.Header.Bytes()), chain.SharedConfig().ConsensusType())
 
 			r.chains[chainID] = chain
 			r.systemChannelID = chainID
@@ -208,7 +210,7 @@ func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 
 	}
 
-	if r.systemChannelID == "" {
+	if r.systemChannelID == "" && !r.config.ChannelParticipation.Enabled {
 		logger.Panicf("No system chain found.  If bootstrapping, does your system channel contain a consortiums group definition?")
 	}
 }
@@ -230,6 +232,9 @@ func (r *Registrar) BroadcastChannelSupport(msg *cb.Envelope) (*cb.ChannelHeader
 	cs := r.GetChain(chdr.ChannelId)
 	// New channel creation
 	if cs == nil {
+		if r.systemChannel == nil {
+			return nil, false, nil, types.ErrChannelNotExist
+		}
 		cs = r.systemChannel
 	}
 
@@ -312,10 +317,18 @@ func (r *Registrar) newChain(configtx *cb.Envelope) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
+	r.createChain(configtx)
+}
+
+// createChain creates and starts the chain of the given config transaction.
+// It must be called while holding the registrar lock.
+func (r *Registrar) createChain(configtx *cb.Envelope) *ChainSupport {
 	ledgerResources := r.newLedgerResources(configtx)
-	// If we have no blocks, we need to create the genesis block ourselves.
+	// If we have no blocks, we need to create the genesis block ourselves,
+	// hashed with the block hashing algorithm of the channel.
 	if ledgerResources.Height() == 0 {
-		ledgerResources.Append(blockledger.CreateNextBlock(ledgerResources, []*cb.Envelope{configtx}))
+		hash := ledgerResources.ChannelConfig().BlockHashingAlgorithm()
+		ledgerResources.Append(blockledger.CreateNextBlockWithHashingAlgorithm(ledgerResources, []*cb.Envelope{configtx}, hash))
 	}
 
 	// Copy the map to allow concurrent reads from broadcast/deliver while the new chainSupport is
@@ -333,6 +346,156 @@ func (r *Registrar) newChain(configtx *cb.Envelope) {
 	cs.start()
 
 	r.chains = newChains
+	return cs
+}
+
+// ChannelList returns the names of the channels the orderer is a part of, sorted by name.
+// The system channel, if it exists, is listed separately from the application channels.
+func (r *Registrar) ChannelList() types.ChannelList {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	list := types.ChannelList{}
+	for name := range r.chains {
+		if name == r.systemChannelID {
+			list.SystemChannel = &types.ChannelInfoShort{Name: name}
+			continue
+		}
+		list.Channels = append(list.Channels, types.ChannelInfoShort{Name: name})
+	}
+	sort.Slice(list.Channels, func(i, j int) bool {
+		return list.Channels[i].Name < list.Channels[j].Name
+	})
+
+	return list
+}
+
+// ChannelInfo returns the info of the channel with the given name, or
+// types.ErrChannelNotExist if the orderer is not a part of it.
+func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
+	cs := r.GetChain(channelID)
+	if cs == nil {
+		return types.ChannelInfo{}, types.ErrChannelNotExist
+	}
+
+	info := types.ChannelInfo{
+		Name:   channelID,
+		Height: cs.Height(),
+	}
+	info.ClusterRelation, info.Status = cs.StatusReport()
+	return info, nil
+}
+
+// JoinChannel creates the channel of the given genesis block, and starts servicing it.
+// A channel can only be joined when the orderer is not bootstrapped with a system channel.
+//
+// Only the genesis block of a channel is accepted, as the ledger of the channel is built
+// from it. An orderer joining a channel which has already progressed joins it with its
+// genesis block too, and then replicates the rest of the channel from the cluster: as a
+// follower of an etcdraft channel until it is added to the consenters, or by catching up
+// with the other consenters when it is one of the consenters of the genesis block.
+func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block) (types.ChannelInfo, error) {
+	if r.SystemChannelID() != "" {
+		return types.ChannelInfo{}, types.ErrSystemChannelExists
+	}
+
+	if configBlock == nil || configBlock.Header == nil || configBlock.Data == nil {
+		return types.ChannelInfo{}, errors.New("invalid config block: missing header or data")
+	}
+	if configBlock.Header.Number != 0 {
+		return types.ChannelInfo{}, errors.Errorf("invalid config block: only the genesis block can be joined, got block number %d, the blocks following the genesis block are replicated from the cluster", configBlock.Header.Number)
+	}
+	if !utils.IsConfigBlock(configBlock) {
+		return types.ChannelInfo{}, errors.New("invalid config block: block is not a config block")
+	}
+
+	env, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "invalid config block")
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "invalid config block")
+	}
+	if id := bundle.ConfigtxValidator().ChainID(); id != channelID {
+		return types.ChannelInfo{}, errors.Errorf("invalid config block: channel ID mismatch, expected %s, got %s", channelID, id)
+	}
+	if _, ok := bundle.ConsortiumsConfig(); ok {
+		return types.ChannelInfo{}, errors.New("invalid config block: a system channel cannot be joined")
+	}
+	if err := checkResources(bundle); err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "invalid config block")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, exists := r.chains[channelID]; exists {
+		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+	}
+
+	ledger, err := r.ledgerFactory.GetOrCreate(channelID)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "failed obtaining the ledger")
+	}
+	if ledger.Height() > 0 {
+		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+	}
+	// The genesis block is appended as is, as it is hashed according to the channel config
+	if err := ledger.Append(configBlock); err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "failed appending the config block")
+	}
+
+	logger.Infof("Joining channel %s", channelID)
+	cs := r.createChain(env)
+
+	info := types.ChannelInfo{
+		Name:   channelID,
+		Height: cs.Height(),
+	}
+	info.ClusterRelation, info.Status = cs.StatusReport()
+	return info, nil
+}
+
+// RemoveChannel halts the chain of the channel with the given name, and removes its ledger
+// as well as the storage of its consenter, if any.
+// A channel can only be removed when the orderer is not bootstrapped with a system channel.
+func (r *Registrar) RemoveChannel(channelID string) error {
+	if r.SystemChannelID() != "" {
+		return types.ErrSystemChannelExists
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cs, exists := r.chains[channelID]
+	if !exists {
+		return types.ErrChannelNotExist
+	}
+
+	// Copy the map to allow concurrent reads from broadcast/deliver while the chain is removed
+	newChains := make(map[string]*ChainSupport)
+	for key, value := range r.chains {
+		if key != channelID {
+			newChains[key] = value
+		}
+	}
+	r.chains = newChains
+
+	cs.Halt()
+	if err := r.ledgerFactory.Remove(channelID); err != nil {
+		return errors.WithMessagef(err, "failed removing the ledger of channel %s", channelID)
+	}
+	// The consensus state of the chain is removed as well, as it would otherwise
+	// be restored if the channel is joined again
+	if remover, ok := r.consenters[cs.SharedConfig().ConsensusType()].(consensus.ChainRemover); ok {
+		if err := remover.RemoveChain(channelID); err != nil {
+			return errors.WithMessagef(err, "failed removing the consensus storage of channel %s", channelID)
+		}
+	}
+
+	logger.Infof("Removed channel %s", channelID)
+	return nil
 }
 
 // ChannelsCount returns the count of the current total number of channels.
@@ -22,6 +22,7 @@ import (
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -390,3 +391,85 @@ func TestBroadcastChannelSupportRejection(t *testing.T) {
 		assert.Error(t, err, "Messages of type HeaderType_CONFIG should return an error.")
 	})
 }
+
+func TestChannelParticipation(t *testing.T) {
+	conf := localconfig.TopLevel{ChannelParticipation: localconfig.ChannelParticipation{Enabled: true}}
+	// application channel, with an orderer section and no consortiums
+	confApp := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
+	confApp.Consortiums = nil
+	genesisBlockApp := encoder.New(confApp).GenesisBlockForChannel("my-channel")
+	consenter := &mockConsenter{}
+	consenters := map[string]consensus.Consenter{confApp.Orderer.OrdererType: consenter}
+
+	t.Run("Join and remove without a system channel", func(t *testing.T) {
+		lf := ramledger.New(10)
+		registrar := NewRegistrar(conf, lf, mockCrypto(), &disabled.Provider{})
+		assert.NotPanics(t, func() { registrar.Initialize(consenters) }, "Should start without a system channel")
+		assert.Equal(t, types.ChannelList{}, registrar.ChannelList())
+
+		_, _, _, err := registrar.BroadcastChannelSupport(makeNormalTx("my-channel", 0))
+		assert.Equal(t, types.ErrChannelNotExist, err)
+
+		info, err := registrar.JoinChannel("my-channel", genesisBlockApp)
+		assert.NoError(t, err)
+		assert.Equal(t, types.ChannelInfo{
+			Name:            "my-channel",
+			ClusterRelation: types.ClusterRelationNone,
+			Status:          types.StatusActive,
+			Height:          1,
+		}, info)
+		assert.Equal(t, types.ChannelList{Channels: []types.ChannelInfoShort{{Name: "my-channel"}}}, registrar.ChannelList())
+
+		info, err = registrar.ChannelInfo("my-channel")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), info.Height)
+		assert.NotNil(t, registrar.GetChain("my-channel"))
+
+		_, err = registrar.JoinChannel("my-channel", genesisBlockApp)
+		assert.Equal(t, types.ErrChannelAlreadyExists, err)
+
+		assert.NoError(t, registrar.RemoveChannel("my-channel"))
+		assert.Nil(t, registrar.GetChain("my-channel"))
+		assert.Empty(t, lf.ChainIDs())
+		assert.Equal(t, []string{"my-channel"}, consenter.removed)
+		_, err = registrar.ChannelInfo("my-channel")
+		assert.Equal(t, types.ErrChannelNotExist, err)
+		assert.Equal(t, types.ErrChannelNotExist, registrar.RemoveChannel("my-channel"))
+	})
+
+	t.Run("Join with an invalid config block", func(t *testing.T) {
+		registrar := NewRegistrar(conf, ramledger.New(10), mockCrypto(), &disabled.Provider{})
+		registrar.Initialize(consenters)
+
+		_, err := registrar.JoinChannel("other-channel", genesisBlockApp)
+		assert.EqualError(t, err, "invalid config block: channel ID mismatch, expected other-channel, got my-channel")
+
+		block := proto.Clone(genesisBlockApp).(*cb.Block)
+		block.Header.Number = 1
+		_, err = registrar.JoinChannel("my-channel", block)
+		assert.EqualError(t, err, "invalid config block: only the genesis block can be joined, got block number 1, the blocks following the genesis block are replicated from the cluster")
+
+		_, err = registrar.JoinChannel("my-channel", cb.NewBlock(0, nil))
+		assert.EqualError(t, err, "invalid config block: block is not a config block")
+
+		confSys := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+		_, err = registrar.JoinChannel("sys-channel", encoder.New(confSys).GenesisBlockForChannel("sys-channel"))
+		assert.EqualError(t, err, "invalid config block: a system channel cannot be joined")
+
+		assert.Equal(t, types.ChannelList{}, registrar.ChannelList())
+	})
+
+	t.Run("With a system channel", func(t *testing.T) {
+		confSys := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+		lf, _ := newRAMLedgerAndFactory(10, genesisconfig.TestChainID, encoder.New(confSys).GenesisBlock())
+		registrar := NewRegistrar(conf, lf, mockCrypto(), &disabled.Provider{})
+		registrar.Initialize(consenters)
+
+		assert.Equal(t, types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: genesisconfig.TestChainID}}, registrar.ChannelList())
+
+		_, err := registrar.JoinChannel("my-channel", genesisBlockApp)
+		assert.Equal(t, types.ErrSystemChannelExists, err)
+		assert.Equal(t, types.ErrSystemChannelExists, registrar.RemoveChannel(genesisconfig.TestChainID))
+		assert.NotNil(t, registrar.GetChain(genesisconfig.TestChainID))
+	})
+}
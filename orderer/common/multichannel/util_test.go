@@ -23,6 +23,13 @@ import (
 )
 
 type mockConsenter struct {
+	removed []string
+}
+
+// RemoveChain records the channels whose consensus storage is removed
+func (mc *mockConsenter) RemoveChain(channelID string) error {
+	mc.removed = append(mc.removed, channelID)
+	return nil
 }
 
 func (mc *mockConsenter) HandleChain(support consensus.ConsenterSupport, metadata *cb.Metadata) (consensus.Chain, error) {
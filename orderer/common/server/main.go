@@ -40,11 +40,13 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/bft"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/kafka"
 	"github.com/hyperledger/fabric/orderer/consensus/solo"
@@ -66,7 +68,7 @@ var (
 	version   = app.Command("version", "Show version information")
 	benchmark = app.Command("benchmark", "Run orderer in benchmark mode")
 
-	clusterTypes = map[string]struct{}{"etcdraft": {}}
+	clusterTypes = map[string]struct{}{"etcdraft": {}, "bft": {}}
 )
 
 // Main is the entry point of orderer process
@@ -94,7 +96,9 @@ func Main() {
 // Start provides a layer of abstraction for benchmark test
 func Start(cmd string, conf *localconfig.TopLevel) {
 	bootstrapBlock := extractBootstrapBlock(conf)
-	if err := ValidateBootstrapBlock(bootstrapBlock); err != nil {
+	if bootstrapBlock == nil {
+		logger.Info("Starting without a system channel")
+	} else if err := ValidateBootstrapBlock(bootstrapBlock); err != nil {
 		logger.Panicf("Failed validating bootstrap block: %v", err)
 	}
 
@@ -106,9 +110,18 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 	defer opsSystem.Stop()
 	metricsProvider := opsSystem.Provider
 
-	lf, _ := createLedgerFactory(conf, metricsProvider)
-	sysChanLastConfigBlock := extractSysChanLastConfig(lf, bootstrapBlock)
-	clusterBootBlock := selectClusterBootBlock(bootstrapBlock, sysChanLastConfigBlock)
+	var systemChannelID string
+	if bootstrapBlock != nil {
+		if systemChannelID, err = utils.GetChainIDFromBlock(bootstrapBlock); err != nil {
+			logger.Panicf("Failed to parse the system channel ID from the bootstrap block: %v", err)
+		}
+	}
+	lf, _ := createLedgerFactory(conf, systemChannelID, metricsProvider)
+	var clusterBootBlock *cb.Block
+	if bootstrapBlock != nil {
+		sysChanLastConfigBlock := extractSysChanLastConfig(lf, bootstrapBlock)
+		clusterBootBlock = selectClusterBootBlock(bootstrapBlock, sysChanLastConfigBlock)
+	}
 
 	signer := localmsp.NewSigner()
 
@@ -130,10 +143,18 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 	var clusterDialer *cluster.PredicateDialer
 
 	var reuseGrpcListener bool
-	typ := consensusType(bootstrapBlock)
 	var serversToUpdate []*comm.GRPCServer
 
-	clusterType := isClusterType(clusterBootBlock)
+	// Without a system channel, the channels joined may be of a cluster type
+	typ := "etcdraft"
+	clusterType := true
+	if bootstrapBlock != nil {
+		typ = consensusType(bootstrapBlock)
+		clusterType = isClusterType(clusterBootBlock)
+	}
+	if err := checkRetention(conf, clusterType, typ); err != nil {
+		logger.Panicf("Failed validating the retention of the file ledger: %v", err)
+	}
 	if clusterType {
 		logger.Infof("Setting up cluster for orderer type %s", typ)
 
@@ -142,10 +163,13 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 			ClientConfig: clusterClientConfig,
 		}
 
-		r = createReplicator(lf, bootstrapBlock, conf, clusterClientConfig.SecOpts, signer)
-		// Only clusters that are equipped with a recent config block can replicate.
-		if conf.General.GenesisMethod == "file" {
-			r.replicateIfNeeded(bootstrapBlock)
+		// Channels joined without a system channel are not replicated on boot
+		if bootstrapBlock != nil {
+			r = createReplicator(lf, bootstrapBlock, conf, clusterClientConfig.SecOpts, signer)
+			// Only clusters that are equipped with a recent config block can replicate.
+			if conf.General.GenesisMethod == "file" {
+				r.replicateIfNeeded(bootstrapBlock)
+			}
 		}
 
 		if reuseGrpcListener = reuseListener(conf, typ); !reuseGrpcListener {
@@ -188,9 +212,15 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 		time.AfterFunc)
 
 	manager := initializeMultichannelRegistrar(clusterBootBlock, r, clusterDialer, clusterServerConfig, clusterGRPCServer, conf, signer, metricsProvider, opsSystem, lf, tlsCallback)
+	if conf.ChannelParticipation.Enabled {
+		opsSystem.RegisterHandler(
+			channelparticipation.URLBaseV1,
+			channelparticipation.NewHTTPHandler(conf.ChannelParticipation, manager),
+		)
+	}
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
 	expiration := conf.General.Authentication.NoExpirationChecks
-	server := NewServer(manager, metricsProvider, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS, expiration)
+	server := NewServer(manager, metricsProvider, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS, expiration, conf.General.Throttling)
 
 	logger.Infof("Starting %s", metadata.GetVersionInfo())
 	go handleSignals(addPlatformSignals(map[os.Signal]func(){
@@ -381,6 +411,18 @@ func configureClusterListener(conf *localconfig.TopLevel, generalConf comm.Serve
 		logger.Panicf("Failed to load cluster server key from '%s' (%s)", clusterConf.ServerPrivateKey, err)
 	}
 
+	var encCert, encKey []byte
+	if clusterConf.ServerEncCertificate != "" || clusterConf.ServerEncPrivateKey != "" {
+		encCert, err = loadPEM(clusterConf.ServerEncCertificate)
+		if err != nil {
+			logger.Panicf("Failed to load cluster server encryption certificate from '%s' (%s)", clusterConf.ServerEncCertificate, err)
+		}
+		encKey, err = loadPEM(clusterConf.ServerEncPrivateKey)
+		if err != nil {
+			logger.Panicf("Failed to load cluster server encryption key from '%s' (%s)", clusterConf.ServerEncPrivateKey, err)
+		}
+	}
+
 	port := fmt.Sprintf("%d", clusterConf.ListenPort)
 	bindAddr := net.JoinHostPort(clusterConf.ListenAddress, port)
 
@@ -409,6 +451,8 @@ func configureClusterListener(conf *localconfig.TopLevel, generalConf comm.Serve
 			Certificate:       cert,
 			UseTLS:            true,
 			Key:               key,
+			EncCertificate:    encCert,
+			EncKey:            encKey,
 		},
 	}
 
@@ -519,6 +563,20 @@ func initializeServerConfig(conf *localconfig.TopLevel, metricsProvider metrics.
 		}
 		secureOpts.Key = serverKey
 		secureOpts.Certificate = serverCertificate
+		if conf.General.TLS.EncCertificate != "" || conf.General.TLS.EncPrivateKey != "" {
+			encCertificate, err := ioutil.ReadFile(conf.General.TLS.EncCertificate)
+			if err != nil {
+				logger.Fatalf("Failed to load server EncCertificate file '%s' (%s)",
+					conf.General.TLS.EncCertificate, err)
+			}
+			encKey, err := ioutil.ReadFile(conf.General.TLS.EncPrivateKey)
+			if err != nil {
+				logger.Fatalf("Failed to load EncPrivateKey file '%s' (%s)",
+					conf.General.TLS.EncPrivateKey, err)
+			}
+			secureOpts.EncCertificate = encCertificate
+			secureOpts.EncKey = encKey
+		}
 		secureOpts.ClientRootCAs = clientRootCAs
 		logger.Infof("Starting orderer with %s enabled", msg)
 	}
@@ -575,6 +633,8 @@ func extractBootstrapBlock(conf *localconfig.TopLevel) *cb.Block {
 		bootstrapBlock = encoder.New(genesisconfig.Load(conf.General.GenesisProfile)).GenesisBlockForChannel(conf.General.SystemChannel)
 	case "file":
 		bootstrapBlock = file.New(conf.General.GenesisFile).GenesisBlock()
+	case "none":
+		// The orderer starts without a system channel, and channels are joined by the channel participation API
 	default:
 		logger.Panic("Unknown genesis method:", conf.General.GenesisMethod)
 	}
@@ -666,7 +726,9 @@ func initializeMultichannelRegistrar(
 ) *multichannel.Registrar {
 	genesisBlock := extractBootstrapBlock(conf)
 	// Are we bootstrapping?
-	if len(lf.ChainIDs()) == 0 {
+	if genesisBlock == nil {
+		logger.Info("Not bootstrapping because there is no system channel")
+	} else if len(lf.ChainIDs()) == 0 {
 		initializeBootstrapChannel(genesisBlock, lf)
 	} else {
 		logger.Info("Not bootstrapping because of existing channels")
@@ -677,9 +739,11 @@ func initializeMultichannelRegistrar(
 	registrar := multichannel.NewRegistrar(*conf, lf, signer, metricsProvider, callbacks...)
 
 	var icr etcdraft.InactiveChainRegistry
-	if isClusterType(bootstrapBlock) {
+	if bootstrapBlock == nil || isClusterType(bootstrapBlock) {
 		etcdConsenter := initializeEtcdraftConsenter(consenters, conf, lf, clusterDialer, bootstrapBlock, ri, srvConf, srv, registrar, metricsProvider)
 		icr = etcdConsenter.InactiveChainRegistry
+		// BFT chains communicate through the cluster service of the etcdraft consenter
+		consenters["bft"] = bft.New(clusterDialer, etcdConsenter.Communication, conf, srvConf, registrar, icr, metricsProvider)
 	}
 
 	consenters["solo"] = solo.New()
@@ -705,6 +769,18 @@ func initializeEtcdraftConsenter(
 	registrar *multichannel.Registrar,
 	metricsProvider metrics.Provider,
 ) *etcdraft.Consenter {
+	if bootstrapBlock == nil {
+		// Without a system channel there is nothing to replicate inactive chains from,
+		// so they are only tracked.
+		icr := &inactiveChainReplicator{
+			logger:                   logger,
+			chains2CreationCallbacks: make(map[string]chainCreation),
+		}
+		raftConsenter := etcdraft.New(clusterDialer, conf, srvConf, srv, registrar, icr, metricsProvider)
+		consenters["etcdraft"] = raftConsenter
+		return raftConsenter
+	}
+
 	replicationRefreshInterval := conf.General.Cluster.ReplicationBackgroundRefreshInterval
 	if replicationRefreshInterval == 0 {
 		replicationRefreshInterval = defaultReplicationBackgroundRefreshInterval
@@ -211,6 +211,7 @@ func TestInitializeBootstrapChannel(t *testing.T) {
 						Location: fileLedgerLocation,
 					},
 				},
+				"",
 				&disabled.Provider{},
 			)
 
@@ -346,12 +347,44 @@ func TestInitializeMultiChainManager(t *testing.T) {
 	conf := genesisConfig(t)
 	assert.NotPanics(t, func() {
 		initializeLocalMsp(conf)
-		lf, _ := createLedgerFactory(conf, &disabled.Provider{})
+		lf, _ := createLedgerFactory(conf, "", &disabled.Provider{})
 		bootBlock := encoder.New(genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel("system")
 		initializeMultichannelRegistrar(bootBlock, &replicationInitiator{}, &cluster.PredicateDialer{}, comm.ServerConfig{}, nil, conf, localmsp.NewSigner(), &disabled.Provider{}, &mocks.HealthChecker{}, lf)
 	})
 }
 
+func TestInitializeMultiChainManagerWithoutSystemChannel(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	conf := genesisConfig(t)
+	conf.General.GenesisMethod = "none"
+	conf.ChannelParticipation.Enabled = true
+
+	initializeLocalMsp(conf)
+	lf, _ := createLedgerFactory(conf, "", &disabled.Provider{})
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	crt, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	srvConf := comm.ServerConfig{
+		SecOpts: &comm.SecureOptions{
+			Certificate: crt.Cert,
+			Key:         crt.Key,
+			UseTLS:      true,
+		},
+	}
+	srv, err := comm.NewGRPCServer("127.0.0.1:0", comm.ServerConfig{})
+	require.NoError(t, err)
+
+	var registrar *multichannel.Registrar
+	assert.NotPanics(t, func() {
+		registrar = initializeMultichannelRegistrar(nil, nil, &cluster.PredicateDialer{}, srvConf, srv, conf, localmsp.NewSigner(), &disabled.Provider{}, &mocks.HealthChecker{}, lf)
+	})
+	assert.Empty(t, registrar.SystemChannelID())
+	assert.Empty(t, lf.ChainIDs())
+}
+
 func TestInitializeGrpcServer(t *testing.T) {
 	// get a free random port
 	listenAddr := func() string {
@@ -409,7 +442,7 @@ func TestUpdateTrustedRoots(t *testing.T) {
 			updateTrustedRoots(caSupport, bundle, grpcServer)
 		}
 	}
-	lf, _ := createLedgerFactory(conf, &disabled.Provider{})
+	lf, _ := createLedgerFactory(conf, "", &disabled.Provider{})
 	bootBlock := encoder.New(genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel("system")
 	initializeMultichannelRegistrar(bootBlock, &replicationInitiator{}, &cluster.PredicateDialer{}, comm.ServerConfig{}, nil, genesisConfig(t), localmsp.NewSigner(), &disabled.Provider{}, &mocks.HealthChecker{}, lf, callback)
 	t.Logf("# app CAs: %d", len(caSupport.AppRootCAsByChain[genesisconfig.TestChainID]))
@@ -715,6 +748,27 @@ func TestInitializeEtcdraftConsenter(t *testing.T) {
 			},
 		}, srv, &multichannel.Registrar{}, &disabled.Provider{})
 	assert.NotNil(t, consenters["etcdraft"])
+
+	t.Run("without a system channel", func(t *testing.T) {
+		srv, err := comm.NewGRPCServer("127.0.0.1:0", comm.ServerConfig{})
+		assert.NoError(t, err)
+
+		consenters := make(map[string]consensus.Consenter)
+		consenter := initializeEtcdraftConsenter(consenters,
+			&localconfig.TopLevel{},
+			rlf,
+			&cluster.PredicateDialer{},
+			nil, nil,
+			comm.ServerConfig{
+				SecOpts: &comm.SecureOptions{
+					Certificate: crt.Cert,
+					Key:         crt.Key,
+					UseTLS:      true,
+				},
+			}, srv, &multichannel.Registrar{}, &disabled.Provider{})
+		assert.NotNil(t, consenters["etcdraft"])
+		assert.Empty(t, consenter.InactiveChainRegistry.(*inactiveChainReplicator).Channels())
+	})
 }
 
 func genesisConfig(t *testing.T) *localconfig.TopLevel {
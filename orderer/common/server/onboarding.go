@@ -18,7 +18,7 @@ import (
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
-	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/orderer/consensus/bft"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -49,7 +49,7 @@ func (ri *replicationInitiator) replicateIfNeeded(bootstrapBlock *common.Block)
 }
 
 func (ri *replicationInitiator) createReplicator(bootstrapBlock *common.Block, filter func(string) bool) *cluster.Replicator {
-	consenterCert := etcdraft.ConsenterCertificate(ri.secOpts.Certificate)
+	consenterCert := bft.ConsenterCertificate(ri.secOpts.Certificate)
 	systemChannelName, err := utils.GetChainIDFromBlock(bootstrapBlock)
 	if err != nil {
 		ri.logger.Panicf("Failed extracting system channel name from bootstrap block: %v", err)
@@ -255,6 +255,9 @@ type Factory interface {
 	// ChainIDs returns the chain IDs the Factory is aware of
 	ChainIDs() []string
 
+	// Remove removes the ledger of the given chain ID
+	Remove(chainID string) error
+
 	// Close releases all resources acquired by the factory
 	Close()
 }
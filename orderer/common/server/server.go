@@ -13,6 +13,7 @@ import (
 	"runtime/debug"
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/metrics"
@@ -79,6 +80,7 @@ func NewServer(
 	timeWindow time.Duration,
 	mutualTLS bool,
 	expirationCheckDisabled bool,
+	throttling localconfig.Throttling,
 ) ab.AtomicBroadcastServer {
 	s := &server{
 		dh: deliver.NewHandler(
@@ -95,6 +97,16 @@ func NewServer(
 		debug:     debug,
 		Registrar: r,
 	}
+	if throttling.Enabled {
+		logger.Infof("Rate limiting broadcast clients to %d tx/s and organizations to %d tx/s per channel", throttling.ClientRate, throttling.OrgRate)
+		s.bh.Limiter = broadcast.NewLimiter(broadcast.LimiterConfig{
+			ClientRate:        throttling.ClientRate,
+			ClientBurst:       throttling.ClientBurst,
+			OrgRate:           throttling.OrgRate,
+			OrgBurst:          throttling.OrgBurst,
+			InactivityTimeout: throttling.InactivityTimeout,
+		}, clock.NewClock())
+	}
 	return s
 }
 
@@ -18,9 +18,10 @@ import (
 	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
 	"github.com/hyperledger/fabric/common/metrics"
 	config "github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/pkg/errors"
 )
 
-func createLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider) (blockledger.Factory, string) {
+func createLedgerFactory(conf *config.TopLevel, systemChannelID string, metricsProvider metrics.Provider) (blockledger.Factory, string) {
 	var lf blockledger.Factory
 	var ld string
 	switch conf.General.LedgerType {
@@ -30,7 +31,7 @@ func createLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider
 			ld = createTempDir(conf.FileLedger.Prefix)
 		}
 		logger.Debug("Ledger dir:", ld)
-		lf = fileledger.New(ld, metricsProvider)
+		lf = fileledger.NewWithRetention(ld, retentionPolicy(conf.FileLedger.Retention, systemChannelID), metricsProvider)
 		// The file-based ledger stores the blocks for each channel
 		// in a fsblkstorage.ChainsDir sub-directory that we have
 		// to create separately. Otherwise the call to the ledger
@@ -51,6 +52,45 @@ func createLedgerFactory(conf *config.TopLevel, metricsProvider metrics.Provider
 	return lf, ld
 }
 
+// retentionPolicy returns the policy pruning the block files of the file
+// ledger, or nil if the block files are kept forever. The system channel is
+// never pruned, as it is replicated from its genesis block by the orderers
+// joining the cluster once the ordering service is migrated to etcdraft
+func retentionPolicy(retention config.Retention, systemChannelID string) *fsblkstorage.RetentionPolicy {
+	if !retentionEnabled(retention) {
+		return nil
+	}
+	policy := &fsblkstorage.RetentionPolicy{
+		MaxBlocks: retention.MaxBlocks,
+		MaxAge:    retention.MaxAge,
+		Exempt: func(ledgerID string) bool {
+			return ledgerID == systemChannelID
+		},
+	}
+	if retention.ArchiveDir != "" {
+		policy.Archiver = fsblkstorage.NewDirArchiver(retention.ArchiveDir)
+	}
+	logger.Infof("Pruning block files with MaxBlocks=[%d], MaxAge=[%s]", retention.MaxBlocks, retention.MaxAge)
+	return policy
+}
+
+func retentionEnabled(retention config.Retention) bool {
+	return retention.MaxBlocks != 0 || retention.MaxAge > 0
+}
+
+// checkRetention returns an error if the block files of the file ledger are
+// pruned while the channels are ordered by a cluster. The ordering nodes joining
+// a channel of the cluster, or following it, replicate it from its first blocks,
+// and the lagging consenters catch up from their height, so none of the blocks
+// of the channels of a cluster can be pruned
+func checkRetention(conf *config.TopLevel, clusterType bool, consensusType string) error {
+	if !clusterType || conf.General.LedgerType != "file" || !retentionEnabled(conf.FileLedger.Retention) {
+		return nil
+	}
+	return errors.Errorf("the block files of the channels of orderer type %s can't be pruned, "+
+		"as ordering nodes replicate them from their first blocks: FileLedger.Retention must be unset", consensusType)
+}
+
 func createTempDir(dirPrefix string) string {
 	dirPath, err := ioutil.TempDir("", dirPrefix)
 	if err != nil {
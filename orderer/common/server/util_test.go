@@ -7,14 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package server
 
 import (
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	fileledger "github.com/hyperledger/fabric/common/ledger/blockledger/file"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	config "github.com/hyperledger/fabric/orderer/common/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateLedgerFactory(t *testing.T) {
@@ -54,7 +66,7 @@ func TestCreateLedgerFactory(t *testing.T) {
 			conf.General.LedgerType = tc.ledgerType
 			conf.FileLedger.Location = tc.ledgerDir
 			conf.FileLedger.Prefix = tc.ledgerDirPrefix
-			lf, ld := createLedgerFactory(conf, &disabled.Provider{})
+			lf, ld := createLedgerFactory(conf, "", &disabled.Provider{})
 
 			defer func() {
 				if ld != "" {
@@ -67,6 +79,140 @@ func TestCreateLedgerFactory(t *testing.T) {
 	}
 }
 
+func TestRetentionPolicy(t *testing.T) {
+	assert.Nil(t, retentionPolicy(config.Retention{}, "system"))
+	assert.Nil(t, retentionPolicy(config.Retention{ArchiveDir: "archive"}, "system"))
+
+	policy := retentionPolicy(config.Retention{MaxBlocks: 100}, "system")
+	assert.Equal(t, uint64(100), policy.MaxBlocks)
+	assert.Nil(t, policy.Archiver)
+	assert.True(t, policy.Exempt("system"))
+	assert.False(t, policy.Exempt("mychannel"))
+
+	policy = retentionPolicy(config.Retention{MaxAge: time.Hour, ArchiveDir: "archive"}, "")
+	assert.Equal(t, time.Hour, policy.MaxAge)
+	assert.Equal(t, fsblkstorage.NewDirArchiver("archive"), policy.Archiver)
+	assert.False(t, policy.Exempt("system"))
+}
+
+func TestCheckRetention(t *testing.T) {
+	conf := &config.TopLevel{
+		General:    config.General{LedgerType: "file"},
+		FileLedger: config.FileLedger{Retention: config.Retention{MaxBlocks: 100}},
+	}
+	assert.NoError(t, checkRetention(conf, false, "kafka"))
+	assert.EqualError(t, checkRetention(conf, true, "etcdraft"), "the block files of the channels of orderer type etcdraft can't be pruned, "+
+		"as ordering nodes replicate them from their first blocks: FileLedger.Retention must be unset")
+
+	conf.FileLedger.Retention = config.Retention{ArchiveDir: "archive"}
+	assert.NoError(t, checkRetention(conf, true, "etcdraft"))
+	conf.FileLedger.Retention = config.Retention{MaxAge: time.Hour}
+	conf.General.LedgerType = "ram"
+	assert.NoError(t, checkRetention(conf, true, "etcdraft"))
+}
+
+func TestJoinPrunedChannel(t *testing.T) {
+	ledgerDir, err := ioutil.TempDir("", "retention")
+	require.NoError(t, err)
+	defer os.RemoveAll(ledgerDir)
+
+	// every block is written to its own block file, which can be pruned
+	provider := fsblkstorage.NewProvider(
+		fsblkstorage.NewConfWithRetention(ledgerDir, 1, retentionPolicy(config.Retention{MaxBlocks: 2}, "system")),
+		&blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
+		&disabled.Provider{},
+	)
+	defer provider.Close()
+	store, err := provider.CreateBlockStore("mychannel")
+	require.NoError(t, err)
+	fl := fileledger.NewFileLedger(store)
+	require.NoError(t, fl.Append(blockledger.CreateNextBlock(fl, []*cb.Envelope{{Payload: []byte("genesis")}})))
+	for i := 1; i < 10; i++ {
+		block := blockledger.CreateNextBlock(fl, []*cb.Envelope{{Payload: []byte("payload")}})
+		block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+			Value: utils.MarshalOrPanic(&cb.LastConfig{Index: block.Header.Number}),
+		})
+		require.NoError(t, fl.Append(block))
+	}
+
+	// an ordering node joining the channel pulls it from block 1, which was pruned
+	it, _ := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 1}}})
+	defer it.Close()
+	_, status := it.Next()
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+
+	// so pruning is refused for the orderer types whose ordering nodes replicate the channels
+	conf := &config.TopLevel{
+		General:    config.General{LedgerType: "file"},
+		FileLedger: config.FileLedger{Retention: config.Retention{MaxBlocks: 2}},
+	}
+	assert.Error(t, checkRetention(conf, true, "etcdraft"))
+}
+
+func TestSystemChannelNotPrunedOnRestart(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+
+	ledgerDir, err := ioutil.TempDir("", "retention")
+	require.NoError(t, err)
+	defer os.RemoveAll(ledgerDir)
+
+	policy := retentionPolicy(config.Retention{MaxBlocks: 2}, "system")
+	// every block is written to its own block file, which can be pruned
+	newProvider := func() blkstorage.BlockStoreProvider {
+		return fsblkstorage.NewProvider(
+			fsblkstorage.NewConfWithRetention(ledgerDir, 1, policy),
+			&blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
+			&disabled.Provider{},
+		)
+	}
+
+	genesisBlocks := map[string]*cb.Block{}
+	provider := newProvider()
+	for _, channelID := range []string{"system", "mychannel"} {
+		genesisBlock := encoder.New(genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel(channelID)
+		genesisBlocks[channelID] = genesisBlock
+		store, err := provider.CreateBlockStore(channelID)
+		require.NoError(t, err)
+		fl := fileledger.NewFileLedger(store)
+		require.NoError(t, fl.Append(genesisBlock))
+		for i := 1; i < 10; i++ {
+			block := blockledger.CreateNextBlock(fl, []*cb.Envelope{{Payload: []byte("payload")}})
+			block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+				Value: utils.MarshalOrPanic(&cb.LastConfig{Index: block.Header.Number}),
+			})
+			require.NoError(t, fl.Append(block))
+		}
+	}
+	provider.Close()
+
+	// the registrar reads the genesis block of the system channel from the oldest position on restart
+	provider = newProvider()
+	defer provider.Close()
+	for _, channelID := range []string{"system", "mychannel"} {
+		store, err := provider.OpenBlockStore(channelID)
+		require.NoError(t, err)
+		fl := fileledger.NewFileLedger(store)
+		assert.Equal(t, uint64(10), fl.Height())
+
+		it, pos := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})
+		assert.Equal(t, uint64(0), pos)
+		block, status := it.Next()
+		assert.Equal(t, cb.Status_SUCCESS, status)
+		assert.Equal(t, genesisBlocks[channelID].Header.Bytes(), block.Header.Bytes())
+		block, status = it.Next()
+		if channelID == "system" {
+			assert.Equal(t, cb.Status_SUCCESS, status)
+			assert.Equal(t, uint64(1), block.Header.Number)
+		} else {
+			// the pruned blocks of the other channels are not found
+			assert.Equal(t, cb.Status_NOT_FOUND, status)
+		}
+		it.Close()
+		assert.Equal(t, genesisBlocks[channelID].Header.Bytes(), blockledger.GetBlock(fl, 0).Header.Bytes())
+	}
+}
+
 func TestCreateSubDir(t *testing.T) {
 	testCases := []struct {
 		name          string
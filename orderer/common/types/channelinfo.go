@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package types
+
+// ErrorResponse carries the error of a failed channel participation request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// ChannelList carries the response to an HTTP request to List all the channels.
+// This is marshaled into the body of the HTTP response.
+type ChannelList struct {
+	// The system channel info, nil if it does not exist.
+	SystemChannel *ChannelInfoShort `json:"systemChannel"`
+	// Application channels only, nil or empty if no channels defined.
+	Channels []ChannelInfoShort `json:"channels"`
+}
+
+// ChannelInfoShort carries a short info of a single channel.
+type ChannelInfoShort struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The channel relative URL (no Host:Port, only path), e.g.: "/participation/v1/channels/my-channel".
+	URL string `json:"url"`
+}
+
+// ClusterRelation represents the relationship between the orderer and the channel's consensus cluster.
+type ClusterRelation string
+
+const (
+	// ClusterRelationMember means the orderer is a consenter of the channel's cluster.
+	ClusterRelationMember ClusterRelation = "member"
+	// ClusterRelationConfigTracker means the orderer is not a consenter, and only tracks the channel's config.
+	ClusterRelationConfigTracker ClusterRelation = "config-tracker"
+	// ClusterRelationFollower means the orderer is not a consenter, and replicates the channel's blocks.
+	ClusterRelationFollower ClusterRelation = "follower"
+	// ClusterRelationNone means the channel's consensus type is not a cluster, e.g. solo or kafka.
+	ClusterRelationNone ClusterRelation = "none"
+)
+
+// Status represents the degree by which the orderer takes part in ordering the channel.
+type Status string
+
+const (
+	// StatusActive means the orderer orders the transactions of the channel.
+	StatusActive Status = "active"
+	// StatusInactive means the orderer does not order the transactions of the channel.
+	StatusInactive Status = "inactive"
+	// StatusOnBoarding means the orderer is catching up with the blocks of the channel's cluster.
+	StatusOnBoarding Status = "onboarding"
+)
+
+// ChannelInfo carries the response to an HTTP request to List a single channel.
+// This is marshaled into the body of the HTTP response.
+type ChannelInfo struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The channel relative URL (no Host:Port, only path), e.g.: "/participation/v1/channels/my-channel".
+	URL string `json:"url"`
+	// Whether the orderer is a "member", a "config-tracker" or has no relation ("none") to the channel's cluster.
+	ClusterRelation ClusterRelation `json:"clusterRelation"`
+	// Whether the orderer is "active" or "inactive" in ordering the channel.
+	Status Status `json:"status"`
+	// Current block height.
+	Height uint64 `json:"height"`
+}
@@ -0,0 +1,19 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package types
+
+import "errors"
+
+// ErrSystemChannelExists denotes that a channel participation request cannot be served
+// because the ordering node is bootstrapped with a system channel.
+var ErrSystemChannelExists = errors.New("system channel exists")
+
+// ErrChannelAlreadyExists denotes that the channel to join already exists.
+var ErrChannelAlreadyExists = errors.New("channel already exists")
+
+// ErrChannelNotExist denotes that the channel a request refers to does not exist.
+var ErrChannelNotExist = errors.New("channel does not exist")
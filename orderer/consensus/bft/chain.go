@@ -0,0 +1,963 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+//go:generate counterfeiter -o mocks/configurator.go . Configurator
+
+// Configurator is used to configure the communication layer
+// when the chain starts.
+type Configurator interface {
+	Configure(channel string, newNodes []cluster.RemoteNode)
+}
+
+//go:generate counterfeiter -o mocks/mock_rpc.go . RPC
+
+// RPC is used to mock the transport layer in tests.
+type RPC interface {
+	SendConsensus(dest uint64, msg *orderer.ConsensusRequest) error
+	SendSubmit(dest uint64, request *orderer.SubmitRequest) error
+}
+
+//go:generate counterfeiter -o mocks/mock_blockpuller.go . BlockPuller
+
+// BlockPuller is used to pull blocks from other OSN
+type BlockPuller interface {
+	PullBlock(seq uint64) *common.Block
+	Close()
+}
+
+// CreateBlockPuller is a function to create BlockPuller on demand.
+// It is passed into chain initializer so that tests could mock this.
+type CreateBlockPuller func() (BlockPuller, error)
+
+// Options contains all the configurations relevant to the chain.
+type Options struct {
+	ID uint64
+
+	Clock  clock.Clock
+	Logger *flogging.FabricLogger
+
+	// TickInterval is the interval at which the timeouts are checked
+	TickInterval time.Duration
+	// RequestTimeout is the time a request waits to be ordered before the
+	// node forwards it to all the nodes, and then suspects the leader
+	RequestTimeout time.Duration
+	// ViewChangeTimeout is the time a view change waits for a quorum before
+	// the node moves on to the next view
+	ViewChangeTimeout time.Duration
+
+	// View is the view in which the last block was agreed upon
+	View       uint64
+	Consenters map[uint64]*bft.Consenter
+
+	// LockPath is the file the proposal locked by the node is persisted to,
+	// so that the node does not sign another block of the sequence after a restart
+	LockPath string
+
+	Metrics *Metrics
+}
+
+type submit struct {
+	req    *orderer.SubmitRequest
+	sender uint64
+	leader chan uint64
+}
+
+type message struct {
+	sender uint64
+	msg    *bft.ConsensusMessage
+}
+
+// Chain implements consensus.Chain interface.
+type Chain struct {
+	configurator Configurator
+
+	rpc RPC
+
+	id        uint64
+	channelID string
+
+	submitC    chan *submit
+	consensusC chan *message
+	haltC      chan struct{} // Signals to goroutines that the chain is halting
+	doneC      chan struct{} // Closes when the chain halts
+	startC     chan struct{} // Closes when the node is started
+
+	errorCLock sync.RWMutex
+	errorC     chan struct{} // returned by Errored()
+
+	clock clock.Clock // Tests can inject a fake clock
+
+	support consensus.ConsenterSupport
+
+	createPuller CreateBlockPuller // func used to create BlockPuller on demand
+
+	opts Options
+
+	Metrics *Metrics
+	logger  *flogging.FabricLogger
+
+	haltCallback func()
+
+	// The fields below are only accessed by the serveRequest go routine
+	consenters      map[uint64]*bft.Consenter
+	nodes           []uint64 // sorted IDs of the consenters
+	view            uint64
+	lastBlock       *common.Block
+	lastConfigIndex uint64
+
+	pool    *requestPool
+	batches [][]*common.Envelope // batches cut by the leader, waiting to be proposed
+	pending bool                 // whether the block cutter has pending requests
+
+	round  *round
+	locked *bft.Prepared // proposal prepared by a quorum but not committed yet
+
+	viewChange  *viewChange
+	viewChanges map[uint64]*bft.ViewChange // latest view change of each node
+
+	heights       map[uint64]uint64 // highest sequence seen in the messages of each node
+	views         map[uint64]uint64 // highest view seen in the messages of each node
+	future        []*message        // messages received ahead of this node
+	syncCandidate uint64            // sequence at which this node was found behind at the last tick
+}
+
+// NewChain constructs a chain object.
+func NewChain(
+	support consensus.ConsenterSupport,
+	opts Options,
+	conf Configurator,
+	rpc RPC,
+	f CreateBlockPuller,
+	haltCallback func()) (*Chain, error) {
+
+	lg := opts.Logger.With("channel", support.ChainID(), "node", opts.ID)
+
+	b := support.Block(support.Height() - 1)
+	if b == nil {
+		return nil, errors.Errorf("failed to get last block")
+	}
+
+	lastConfigIndex, err := utils.GetLastConfigIndexFromBlock(b)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to read last config index of last block")
+	}
+
+	locked, err := readLock(opts.LockPath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to restore locked proposal")
+	}
+	if locked.GetPrePrepare().GetBlock().GetHeader().GetNumber() != b.Header.Number+1 {
+		// the locked proposal was committed before the node stopped
+		locked = nil
+	}
+
+	c := &Chain{
+		configurator:    conf,
+		rpc:             rpc,
+		id:              opts.ID,
+		channelID:       support.ChainID(),
+		submitC:         make(chan *submit),
+		consensusC:      make(chan *message),
+		haltC:           make(chan struct{}),
+		doneC:           make(chan struct{}),
+		startC:          make(chan struct{}),
+		errorC:          make(chan struct{}),
+		clock:           opts.Clock,
+		support:         support,
+		createPuller:    f,
+		opts:            opts,
+		haltCallback:    haltCallback,
+		consenters:      opts.Consenters,
+		nodes:           sortedIDs(opts.Consenters),
+		view:            opts.View,
+		lastBlock:       b,
+		lastConfigIndex: lastConfigIndex,
+		locked:          locked,
+		pool:            newRequestPool(),
+		viewChanges:     make(map[uint64]*bft.ViewChange),
+		heights:         make(map[uint64]uint64),
+		views:           make(map[uint64]uint64),
+		Metrics: &Metrics{
+			ClusterSize:          opts.Metrics.ClusterSize.With("channel", support.ChainID()),
+			IsLeader:             opts.Metrics.IsLeader.With("channel", support.ChainID()),
+			View:                 opts.Metrics.View.With("channel", support.ChainID()),
+			CommittedBlockNumber: opts.Metrics.CommittedBlockNumber.With("channel", support.ChainID()),
+			ViewChanges:          opts.Metrics.ViewChanges.With("channel", support.ChainID()),
+			ProposalFailures:     opts.Metrics.ProposalFailures.With("channel", support.ChainID()),
+		},
+		logger: lg,
+	}
+
+	// Sets initial values for metrics
+	c.Metrics.ClusterSize.Set(float64(len(c.nodes)))
+	c.Metrics.IsLeader.Set(float64(0))
+	c.Metrics.View.Set(float64(c.view))
+	c.Metrics.CommittedBlockNumber.Set(float64(c.lastBlock.Header.Number))
+
+	return c, nil
+}
+
+// Start instructs the orderer to begin serving the chain and keep it current.
+func (c *Chain) Start() {
+	c.logger.Infof("Starting BFT node in view %d", c.view)
+
+	if err := c.configureComm(); err != nil {
+		c.logger.Errorf("Failed to start chain, aborting: +%v", err)
+		close(c.doneC)
+		return
+	}
+
+	close(c.startC)
+
+	go c.serveRequest()
+}
+
+// Order submits normal type transactions for ordering.
+func (c *Chain) Order(env *common.Envelope, configSeq uint64) error {
+	return c.Submit(&orderer.SubmitRequest{LastValidationSeq: configSeq, Payload: env, Channel: c.channelID}, 0)
+}
+
+// Configure submits config type transactions for ordering.
+func (c *Chain) Configure(env *common.Envelope, configSeq uint64) error {
+	if err := c.checkConfigUpdateValidity(env); err != nil {
+		c.logger.Warnf("Rejected config: %s", err)
+		c.Metrics.ProposalFailures.Add(1)
+		return err
+	}
+	return c.Submit(&orderer.SubmitRequest{LastValidationSeq: configSeq, Payload: env, Channel: c.channelID}, 0)
+}
+
+// checkConfigUpdateValidity validates the BFT metadata set by the config update, if any.
+func (c *Chain) checkConfigUpdateValidity(ctx *common.Envelope) error {
+	payload, err := utils.UnmarshalPayload(ctx.Payload)
+	if err != nil {
+		return err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+
+	if chdr.Type != int32(common.HeaderType_ORDERER_TRANSACTION) &&
+		chdr.Type != int32(common.HeaderType_CONFIG) {
+		return errors.Errorf("config transaction has unknown header type: %s", common.HeaderType(chdr.Type))
+	}
+
+	if chdr.Type == int32(common.HeaderType_ORDERER_TRANSACTION) {
+		newChannelConfig, err := utils.UnmarshalEnvelope(payload.Data)
+		if err != nil {
+			return err
+		}
+
+		payload, err = utils.UnmarshalPayload(newChannelConfig.Payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	configUpdate, err := configtx.UnmarshalConfigUpdateFromPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := MetadataFromConfigUpdate(configUpdate)
+	if err != nil {
+		return err
+	}
+
+	if metadata != nil {
+		if err := CheckConfigMetadata(metadata); err != nil {
+			return err
+		}
+	} else if !BlockValidationPolicyUpdated(configUpdate) {
+		return nil // Neither the ConsensusType nor the BlockValidation policy are updated
+	}
+
+	// The BlockValidation policy must keep requiring a quorum of the consenters
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return err
+	}
+	return CheckBlockValidationPolicy(configEnv.Config)
+}
+
+// WaitReady returns once the chain is able to accept requests.
+func (c *Chain) WaitReady() error {
+	if err := c.isRunning(); err != nil {
+		return err
+	}
+
+	select {
+	case c.submitC <- nil:
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	}
+
+	return nil
+}
+
+// Errored returns a channel that closes when the chain stops,
+// or while the nodes change the view.
+func (c *Chain) Errored() <-chan struct{} {
+	c.errorCLock.RLock()
+	defer c.errorCLock.RUnlock()
+	return c.errorC
+}
+
+// Halt stops the chain.
+func (c *Chain) Halt() {
+	c.stop()
+}
+
+// stop stops the chain, and returns whether it was stopped by this call.
+func (c *Chain) stop() bool {
+	select {
+	case <-c.startC:
+	default:
+		c.logger.Warnf("Attempted to halt a chain that has not started")
+		return false
+	}
+
+	select {
+	case c.haltC <- struct{}{}:
+	case <-c.doneC:
+		return false
+	}
+	<-c.doneC
+
+	return true
+}
+
+// haltEvicted stops the chain once this node is removed from the channel, and
+// calls haltCallback so that the channel is tracked from then on. The callback
+// is not called when the chain is halted otherwise, e.g. when it is removed.
+func (c *Chain) haltEvicted() {
+	if c.stop() && c.haltCallback != nil {
+		c.haltCallback()
+	}
+}
+
+// StatusReport returns the ClusterRelation & Status
+func (c *Chain) StatusReport() (types.ClusterRelation, types.Status) {
+	return types.ClusterRelationMember, types.StatusActive
+}
+
+func (c *Chain) isRunning() error {
+	select {
+	case <-c.startC:
+	default:
+		return errors.Errorf("chain is not started")
+	}
+
+	select {
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	default:
+	}
+
+	return nil
+}
+
+// Consensus passes the given ConsensusRequest message to the chain
+func (c *Chain) Consensus(req *orderer.ConsensusRequest, sender uint64) error {
+	if err := c.isRunning(); err != nil {
+		return err
+	}
+
+	msg := &bft.ConsensusMessage{}
+	if err := proto.Unmarshal(req.Payload, msg); err != nil {
+		return fmt.Errorf("failed to unmarshal ConsensusRequest payload to BFT message: %s", err)
+	}
+
+	select {
+	case c.consensusC <- &message{sender: sender, msg: msg}:
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	}
+
+	return nil
+}
+
+// Submit pools the incoming request, and forwards it to the leader if it
+// was submitted to this node by a client. Requests forwarded by other nodes
+// are only ordered by the leader, the other nodes watch them in order to
+// detect a leader which does not order them.
+func (c *Chain) Submit(req *orderer.SubmitRequest, sender uint64) error {
+	if err := c.isRunning(); err != nil {
+		c.Metrics.ProposalFailures.Add(1)
+		return err
+	}
+
+	leadC := make(chan uint64, 1)
+	select {
+	case c.submitC <- &submit{req: req, sender: sender, leader: leadC}:
+		lead := <-leadC
+		// During a view change the request stays in the pool,
+		// and is forwarded to the next leader once it is elected
+		if sender == 0 && lead != 0 && lead != c.id {
+			if err := c.rpc.SendSubmit(lead, req); err != nil {
+				c.Metrics.ProposalFailures.Add(1)
+				return err
+			}
+		}
+
+	case <-c.doneC:
+		c.Metrics.ProposalFailures.Add(1)
+		return errors.Errorf("chain is stopped")
+	}
+
+	return nil
+}
+
+func (c *Chain) serveRequest() {
+	ticking := false
+	timer := c.clock.NewTimer(time.Second)
+	// we need a stopped timer rather than nil,
+	// because we will be select waiting on timer.C()
+	if !timer.Stop() {
+		<-timer.C()
+	}
+
+	var deadline time.Time
+
+	stopTimer := func() {
+		if !timer.Stop() && ticking {
+			// we only need to drain the channel if the timer expired (not explicitly stopped)
+			<-timer.C()
+		}
+		ticking = false
+	}
+
+	// if timer is already started and expires within timeout, this is a no-op
+	startTimer := func(timeout time.Duration) {
+		now := c.clock.Now()
+		if ticking {
+			if !now.Add(timeout).Before(deadline) {
+				return
+			}
+			stopTimer()
+		}
+		ticking = true
+		deadline = now.Add(timeout)
+		timer.Reset(timeout)
+	}
+
+	ticker := c.clock.NewTicker(c.opts.TickInterval)
+	defer ticker.Stop()
+
+	c.Metrics.IsLeader.Set(boolToFloat(c.isLeader()))
+
+	for {
+		select {
+		case s := <-c.submitC:
+			if s == nil {
+				// polled by `WaitReady`
+				continue
+			}
+			s.leader <- c.submit(s.req, s.sender)
+
+		case m := <-c.consensusC:
+			c.handleMessage(m.sender, m.msg)
+
+		case <-timer.C():
+			ticking = false
+			if !c.isLeader() {
+				continue
+			}
+
+			batch := c.support.BlockCutter().Cut()
+			if len(batch) == 0 {
+				c.logger.Warningf("Batch timer expired with no pending requests, this might indicate a bug")
+			} else {
+				c.logger.Debugf("Batch timer expired, creating block")
+				c.batches = append(c.batches, batch)
+			}
+			c.pending = false
+
+		case <-ticker.C():
+			c.tick()
+
+		case <-c.haltC:
+			stopTimer()
+			c.setErrored(true)
+			close(c.doneC)
+			c.logger.Infof("Stop serving requests")
+			return
+		}
+
+		c.proposeBatch()
+
+		if c.pending && c.isLeader() {
+			startTimer(blockcutter.PendingBatchTimeout(c.support.SharedConfig(), c.support.BlockCutter())) // no-op if timer expires sooner
+		} else {
+			stopTimer()
+		}
+	}
+}
+
+// submit pools the request, orders it if this node is the leader, and
+// returns the current leader, or 0 during a view change.
+func (c *Chain) submit(req *orderer.SubmitRequest, sender uint64) uint64 {
+	if sender != 0 {
+		if _, exists := c.consenters[sender]; !exists {
+			c.logger.Warningf("Discarding request submitted by %d which is not a consenter", sender)
+			return 0
+		}
+	}
+
+	if r := c.pool.add(req, c.clock.Now()); r != nil && c.isLeader() {
+		c.order(r)
+	}
+
+	if c.viewChange != nil {
+		return 0
+	}
+	return c.leader()
+}
+
+// order passes the request to the block cutter, it must only be called by the leader.
+func (c *Chain) order(r *request) {
+	batches, pending, err := c.ordered(r.req)
+	if err != nil {
+		c.logger.Errorf("Failed to order message: %s", err)
+		c.pool.remove(r.key)
+		return
+	}
+	c.batches = append(c.batches, batches...)
+	c.pending = pending
+}
+
+// ordered orders the envelope of the request and returns the batches cut and whether
+// envelopes are pending to be ordered. It takes care of config messages as well as
+// the revalidation of messages if the config sequence has advanced.
+func (c *Chain) ordered(msg *orderer.SubmitRequest) (batches [][]*common.Envelope, pending bool, err error) {
+	seq := c.support.Sequence()
+
+	if isConfig(msg.Payload) {
+		// ConfigMsg
+		if msg.LastValidationSeq < seq {
+			c.logger.Warnf("Config message was validated against %d, although current config seq has advanced (%d)", msg.LastValidationSeq, seq)
+			msg.Payload, _, err = c.support.ProcessConfigMsg(msg.Payload)
+			if err != nil {
+				c.Metrics.ProposalFailures.Add(1)
+				return nil, false, errors.Errorf("bad config message: %s", err)
+			}
+			msg.LastValidationSeq = seq
+		}
+		batch := c.support.BlockCutter().Cut()
+		batches = [][]*common.Envelope{}
+		if len(batch) != 0 {
+			batches = append(batches, batch)
+		}
+		batches = append(batches, []*common.Envelope{msg.Payload})
+		return batches, false, nil
+	}
+	// it is a normal message
+	if msg.LastValidationSeq < seq {
+		c.logger.Warnf("Normal message was validated against %d, although current config seq has advanced (%d)", msg.LastValidationSeq, seq)
+		if _, err := c.support.ProcessNormalMsg(msg.Payload); err != nil {
+			c.Metrics.ProposalFailures.Add(1)
+			return nil, false, errors.Errorf("bad normal message: %s", err)
+		}
+		msg.LastValidationSeq = seq
+	}
+	batches, pending = c.support.BlockCutter().Ordered(msg.Payload)
+	return batches, pending, nil
+}
+
+// resetOrdering discards the batches which were not proposed yet, and
+// orders again the pending requests if this node is the leader.
+func (c *Chain) resetOrdering() {
+	_ = c.support.BlockCutter().Cut()
+	c.batches = nil
+	c.pending = false
+
+	if !c.isLeader() {
+		return
+	}
+
+	// The requests of a proposal in flight are not ordered twice
+	proposed := make(map[string]struct{})
+	if c.round != nil && c.round.prePrepare != nil {
+		for _, data := range c.round.prePrepare.Block.Data.Data {
+			proposed[string(util.ComputeSHA256(data))] = struct{}{}
+		}
+	}
+
+	for _, r := range c.pool.list() {
+		if _, exists := proposed[r.key]; exists {
+			continue
+		}
+		c.order(r)
+	}
+}
+
+// revalidatePool validates the pending requests against the current config,
+// and discards the requests which are not valid anymore.
+func (c *Chain) revalidatePool() {
+	seq := c.support.Sequence()
+	for _, r := range c.pool.list() {
+		if r.req.LastValidationSeq >= seq {
+			continue
+		}
+
+		var err error
+		if isConfig(r.req.Payload) {
+			r.req.Payload, _, err = c.support.ProcessConfigMsg(r.req.Payload)
+		} else {
+			_, err = c.support.ProcessNormalMsg(r.req.Payload)
+		}
+		if err != nil {
+			c.logger.Debugf("Discarding request which is not valid under config sequence %d: %s", seq, err)
+			c.pool.remove(r.key)
+			continue
+		}
+		r.req.LastValidationSeq = seq
+	}
+}
+
+// tick checks the timeouts of the pending requests and of the view change
+// in progress, and whether this node fell behind the other nodes.
+func (c *Chain) tick() {
+	now := c.clock.Now()
+
+	switch {
+	case c.viewChange != nil:
+		if now.Sub(c.viewChange.start) >= c.opts.ViewChangeTimeout {
+			c.logger.Warningf("View change to view %d timed out", c.viewChange.nextView)
+			c.startViewChange(c.viewChange.nextView + 1)
+		}
+
+	case !c.isLeader():
+		for _, r := range c.pool.list() {
+			if now.Sub(r.timestamp) < c.opts.RequestTimeout {
+				continue
+			}
+
+			if !r.forwarded {
+				// The leader might not have received the request: forward it to all the nodes,
+				// so that the other followers suspect the leader as well if it is not ordered.
+				c.logger.Infof("Request was not ordered in time, forwarding it to all the nodes")
+				r.forwarded = true
+				r.timestamp = now
+				for _, id := range c.nodes {
+					if id == c.id {
+						continue
+					}
+					if err := c.rpc.SendSubmit(id, r.req); err != nil {
+						c.logger.Debugf("Failed forwarding request to %d: %s", id, err)
+					}
+				}
+				continue
+			}
+
+			c.logger.Warningf("Request was not ordered in time by leader %d, suspecting it", c.leader())
+			c.startViewChange(c.view + 1)
+			break
+		}
+	}
+
+	nextSeq := c.lastBlock.Header.Number + 1
+	target := c.syncTarget()
+	if target < nextSeq {
+		c.syncCandidate = 0
+		return
+	}
+	// Messages might be still on their way: only catch up
+	// if this node was already behind at the previous tick
+	if c.syncCandidate != nextSeq {
+		c.syncCandidate = nextSeq
+		return
+	}
+	c.catchUp(target)
+}
+
+// catchUp pulls the blocks up to the target from the other nodes and commits them.
+func (c *Chain) catchUp(target uint64) {
+	next := c.lastBlock.Header.Number + 1
+	c.logger.Infof("This node is behind, pulling blocks [%d-%d] from the other nodes", next, target)
+
+	puller, err := c.createPuller()
+	if err != nil {
+		c.logger.Errorf("Failed creating block puller: %s", err)
+		return
+	}
+	defer puller.Close()
+
+	for ; next <= target; next++ {
+		block := puller.PullBlock(next)
+		if block == nil {
+			c.logger.Errorf("Failed pulling block [%d]", next)
+			return
+		}
+
+		md, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_ORDERER)
+		if err != nil {
+			c.logger.Errorf("Failed reading metadata of block [%d]: %s", next, err)
+			return
+		}
+		bm, err := ReadBlockMetadata(md)
+		if err != nil {
+			c.logger.Errorf("Failed reading BFT metadata of block [%d]: %s", next, err)
+			return
+		}
+
+		if err := c.verifyPulledBlock(block, md.Value); err != nil {
+			// closing the puller drops the node which sent the block, the
+			// blocks are pulled again the next time this node catches up
+			c.logger.Errorf("Discarding pulled block [%d]: %s", next, err)
+			return
+		}
+
+		c.writeBlock(block, md.Value)
+		if bm.ViewId > c.view {
+			c.enterView(bm.ViewId, nil)
+		}
+	}
+	c.replayFuture()
+}
+
+// verifyPulledBlock checks that the block pulled from another node extends the
+// last block, and that it carries the signatures of a quorum of consenters
+// which satisfy the block validation policy.
+func (c *Chain) verifyPulledBlock(block *common.Block, metadata []byte) error {
+	if block.Header == nil || block.Data == nil {
+		return errors.New("malformed block")
+	}
+	if block.Header.Number != c.lastBlock.Header.Number+1 {
+		return errors.Errorf("expected block [%d] but got block [%d]", c.lastBlock.Header.Number+1, block.Header.Number)
+	}
+	err := cluster.VerifyBlockHashWithHashingAlgorithm(1, []*common.Block{c.lastBlock, block}, blockHashingAlgorithm(c.support))
+	if err != nil {
+		return err
+	}
+
+	signatures, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessage(err, "failed reading signatures")
+	}
+	value := c.signatureValue(&bft.PrePrepare{Block: block, Metadata: metadata})
+	if !bytes.Equal(signatures.Value, value) {
+		return errors.New("signed metadata does not match the metadata of the block")
+	}
+
+	header := block.Header.Bytes()
+	signers := make(map[uint64]struct{})
+	var signedData []*common.SignedData
+	for _, signature := range signatures.Signatures {
+		sigHeader, err := utils.GetSignatureHeader(signature.SignatureHeader)
+		if err != nil {
+			continue
+		}
+		id, exists := c.signer(sigHeader)
+		if !exists {
+			continue
+		}
+		if _, exists := signers[id]; exists {
+			continue
+		}
+		signers[id] = struct{}{}
+		signedData = append(signedData, &common.SignedData{
+			Identity:  sigHeader.Creator,
+			Data:      util.ConcatenateBytes(value, signature.SignatureHeader, header),
+			Signature: signature.Signature,
+		})
+	}
+	if len(signers) < c.quorum() {
+		return errors.Errorf("block is signed by %d consenters instead of %d", len(signers), c.quorum())
+	}
+
+	if err := c.support.VerifyBlockSignature(signedData, nil); err != nil {
+		return errors.WithMessage(err, "signatures do not satisfy the block validation policy")
+	}
+	return nil
+}
+
+// writeBlock commits the block, which carries the signatures of a quorum of nodes.
+func (c *Chain) writeBlock(block *common.Block, metadata []byte) {
+	if isConfigUpdate(block) {
+		c.lastConfigIndex = block.Header.Number
+	}
+
+	c.support.WriteSignedBlock(block, metadata)
+	c.logger.Infof("Writing block [%d] to ledger", block.Header.Number)
+
+	c.lastBlock = block
+	c.Metrics.CommittedBlockNumber.Set(float64(block.Header.Number))
+	c.pool.removeBlock(block)
+	c.round = nil
+	if c.locked != nil && c.locked.PrePrepare.Block.Header.Number <= block.Header.Number {
+		c.unlock()
+	}
+	c.syncCandidate = 0
+
+	if !utils.IsConfigBlock(block) {
+		return
+	}
+
+	c.reconfigure()
+	c.revalidatePool()
+	// the batches which were cut before the config was
+	// committed need to be validated against the new config
+	c.resetOrdering()
+}
+
+// reconfigure updates the consenters and the options of the chain after a config block
+func (c *Chain) reconfigure() {
+	m := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(c.support.SharedConfig().ConsensusMetadata(), m); err != nil {
+		c.logger.Panicf("Failed to unmarshal consensus metadata: %s", err)
+	}
+
+	consenters := ConsentersToMap(m.Consenters)
+	if _, exists := consenters[c.id]; !exists {
+		c.logger.Infof("This node has been removed from the channel, halting the chain")
+		go c.haltEvicted()
+		return
+	}
+
+	if requestTimeout, err := time.ParseDuration(m.Options.RequestTimeout); err == nil {
+		c.opts.RequestTimeout = requestTimeout
+	}
+	if viewChangeTimeout, err := time.ParseDuration(m.Options.ViewChangeTimeout); err == nil {
+		c.opts.ViewChangeTimeout = viewChangeTimeout
+	}
+
+	c.consenters = consenters
+	c.nodes = sortedIDs(consenters)
+	for id := range c.heights {
+		if _, exists := consenters[id]; !exists {
+			delete(c.heights, id)
+			delete(c.views, id)
+			delete(c.viewChanges, id)
+		}
+	}
+	c.Metrics.ClusterSize.Set(float64(len(c.nodes)))
+	c.Metrics.IsLeader.Set(boolToFloat(c.isLeader()))
+
+	if err := c.configureComm(); err != nil {
+		c.logger.Panicf("Failed to configure communication: %s", err)
+	}
+}
+
+func (c *Chain) configureComm() error {
+	nodes, err := c.remotePeers()
+	if err != nil {
+		return err
+	}
+
+	c.configurator.Configure(c.channelID, nodes)
+	return nil
+}
+
+func (c *Chain) remotePeers() ([]cluster.RemoteNode, error) {
+	var nodes []cluster.RemoteNode
+	for id, consenter := range c.consenters {
+		// No need to know yourself
+		if id == c.id {
+			continue
+		}
+		serverCertAsDER, err := pemToDER(consenter.ServerTlsCert, id, "server", c.logger)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		clientCertAsDER, err := pemToDER(consenter.ClientTlsCert, id, "client", c.logger)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		nodes = append(nodes, cluster.RemoteNode{
+			ID:            id,
+			Endpoint:      fmt.Sprintf("%s:%d", consenter.Host, consenter.Port),
+			ServerTLSCert: serverCertAsDER,
+			ClientTLSCert: clientCertAsDER,
+		})
+	}
+	return nodes, nil
+}
+
+// setErrored closes the channel returned by Errored, or replaces it with an open one.
+func (c *Chain) setErrored(errored bool) {
+	c.errorCLock.Lock()
+	defer c.errorCLock.Unlock()
+
+	select {
+	case <-c.errorC:
+		if !errored {
+			c.errorC = make(chan struct{})
+		}
+	default:
+		if errored {
+			close(c.errorC)
+		}
+	}
+}
+
+func sortedIDs(consenters map[uint64]*bft.Consenter) []uint64 {
+	ids := make([]uint64, 0, len(consenters))
+	for id := range consenters {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func isConfig(env *common.Envelope) bool {
+	h, err := utils.ChannelHeader(env)
+	if err != nil {
+		return false
+	}
+
+	return h.Type == int32(common.HeaderType_CONFIG) || h.Type == int32(common.HeaderType_ORDERER_TRANSACTION)
+}
+
+// isConfigUpdate returns whether the block updates the config of its channel,
+// unlike blocks creating new channels on the system channel
+func isConfigUpdate(block *common.Block) bool {
+	if !utils.IsConfigBlock(block) {
+		return false
+	}
+	envelope, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return false
+	}
+	chdr, err := utils.ChannelHeader(envelope)
+	if err != nil {
+		return false
+	}
+	return chdr.Type == int32(common.HeaderType_CONFIG)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,568 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/mocks/common/multichannel"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testChannel        = "mychannel"
+	testRequestTimeout = time.Second
+)
+
+// testSupport creates blocks which extend the blocks it wrote, and
+// signs them with the identity of its node
+type testSupport struct {
+	*multichannel.ConsenterSupport
+	lastBlock *common.Block
+	consenter *bft.Consenter
+}
+
+func (s *testSupport) CreateNextBlock(envs []*common.Envelope) *common.Block {
+	data := &common.BlockData{Data: make([][]byte, len(envs))}
+	for i, env := range envs {
+		data.Data[i] = utils.MarshalOrPanic(env)
+	}
+	block := common.NewBlock(s.lastBlock.Header.Number+1, s.lastBlock.Header.Hash())
+	block.Header.DataHash = data.Hash()
+	block.Data = data
+	return block
+}
+
+func (s *testSupport) WriteSignedBlock(block *common.Block, encodedMetadataValue []byte) {
+	s.lastBlock = block
+	s.ConsenterSupport.WriteSignedBlock(block, encodedMetadataValue)
+}
+
+func (s *testSupport) NewSignatureHeader() (*common.SignatureHeader, error) {
+	return &common.SignatureHeader{
+		Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: s.consenter.MspId, IdBytes: s.consenter.Identity}),
+	}, nil
+}
+
+type noopConfigurator struct{}
+
+func (noopConfigurator) Configure(channel string, newNodes []cluster.RemoteNode) {}
+
+type testNode struct {
+	chain   *Chain
+	support *testSupport
+	inbox   chan func()
+}
+
+// network delivers the messages between the chains in order, and drops
+// the messages from and to disconnected nodes
+type network struct {
+	sync.RWMutex
+	nodes        map[uint64]*testNode
+	disconnected map[uint64]bool
+}
+
+func (n *network) deliver(sender, dest uint64, f func(c *Chain)) error {
+	n.RLock()
+	defer n.RUnlock()
+
+	node, exists := n.nodes[dest]
+	if !exists {
+		return cluster.ErrNotInChannel
+	}
+	if n.disconnected[sender] || n.disconnected[dest] {
+		return nil
+	}
+	node.inbox <- func() { f(node.chain) }
+	return nil
+}
+
+func (n *network) disconnect(id uint64) {
+	n.Lock()
+	defer n.Unlock()
+	n.disconnected[id] = true
+}
+
+type testRPC struct {
+	sender  uint64
+	network *network
+}
+
+func (r *testRPC) SendConsensus(dest uint64, msg *orderer.ConsensusRequest) error {
+	return r.network.deliver(r.sender, dest, func(c *Chain) {
+		c.Consensus(msg, r.sender)
+	})
+}
+
+func (r *testRPC) SendSubmit(dest uint64, request *orderer.SubmitRequest) error {
+	// every node owns its copy of the request, as chains update the requests they pool
+	request = proto.Clone(request).(*orderer.SubmitRequest)
+	return r.network.deliver(r.sender, dest, func(c *Chain) {
+		c.Submit(request, r.sender)
+	})
+}
+
+func newNetwork(t *testing.T, size int, clock *fakeclock.FakeClock) *network {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	consenters := map[uint64]*bft.Consenter{}
+	for id := uint64(1); id <= uint64(size); id++ {
+		consenters[id] = newTestConsenter(t, ca, id)
+	}
+
+	genesis := common.NewBlock(0, nil)
+	n := &network{
+		nodes:        map[uint64]*testNode{},
+		disconnected: map[uint64]bool{},
+	}
+	for id := range consenters {
+		cutter := mockblockcutter.NewReceiver()
+		cutter.CutNext = true
+		close(cutter.Block)
+
+		support := &testSupport{
+			ConsenterSupport: &multichannel.ConsenterSupport{
+				ChainIDVal:       testChannel,
+				HeightVal:        1,
+				BlockByIndex:     map[uint64]*common.Block{0: genesis},
+				Blocks:           make(chan *common.Block, 10),
+				BlockCutterVal:   cutter,
+				SharedConfigVal:  &mockconfig.Orderer{BatchTimeoutVal: time.Second},
+				ChannelConfigVal: &mockconfig.Channel{},
+			},
+			lastBlock: genesis,
+			consenter: consenters[id],
+		}
+
+		opts := Options{
+			ID:                id,
+			Clock:             clock,
+			Logger:            flogging.MustGetLogger("orderer.consensus.bft.test"),
+			TickInterval:      100 * time.Millisecond,
+			RequestTimeout:    testRequestTimeout,
+			ViewChangeTimeout: time.Minute,
+			Consenters:        consenters,
+			Metrics:           NewMetrics(&disabled.Provider{}),
+		}
+
+		chain, err := NewChain(support, opts, noopConfigurator{}, &testRPC{sender: id, network: n}, nil, nil)
+		require.NoError(t, err)
+
+		n.nodes[id] = &testNode{chain: chain, support: support, inbox: make(chan func(), 1000)}
+	}
+
+	for _, node := range n.nodes {
+		node.chain.Start()
+	}
+	for _, node := range n.nodes {
+		go func(inbox chan func()) {
+			for f := range inbox {
+				f()
+			}
+		}(node.inbox)
+		require.NoError(t, node.chain.WaitReady())
+	}
+
+	return n
+}
+
+func (n *network) halt() {
+	for _, node := range n.nodes {
+		node.chain.Halt()
+	}
+}
+
+func makeEnvelope(txID string) *common.Envelope {
+	return &common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: testChannel,
+					TxId:      txID,
+				}),
+			},
+		}),
+	}
+}
+
+func waitForBlock(t *testing.T, node *testNode) *common.Block {
+	select {
+	case block := <-node.support.Blocks:
+		return block
+	case <-time.After(10 * time.Second):
+		t.Fatalf("node %d did not write a block in time", node.chain.id)
+		return nil
+	}
+}
+
+// assertSignedBlock checks the block carries the signatures of a quorum of nodes
+// and the view it was agreed upon in
+func assertSignedBlock(t *testing.T, block *common.Block, number, view uint64) {
+	assert.Equal(t, number, block.Header.Number)
+
+	signatures, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	require.NoError(t, err)
+	assert.True(t, len(signatures.Signatures) >= bft.Quorum(4), "block has %d signatures", len(signatures.Signatures))
+
+	md, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_ORDERER)
+	require.NoError(t, err)
+	bm, err := ReadBlockMetadata(md)
+	require.NoError(t, err)
+	assert.Equal(t, view, bm.ViewId)
+}
+
+func TestChainOrdersThroughLeader(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	n := newNetwork(t, 4, clock)
+	defer n.halt()
+
+	require.NoError(t, n.nodes[1].chain.Order(makeEnvelope("tx1"), 0))
+	for _, node := range n.nodes {
+		assertSignedBlock(t, waitForBlock(t, node), 1, 0)
+	}
+
+	// requests submitted to a follower are forwarded to the leader
+	require.NoError(t, n.nodes[3].chain.Order(makeEnvelope("tx2"), 0))
+	for _, node := range n.nodes {
+		block := waitForBlock(t, node)
+		assertSignedBlock(t, block, 2, 0)
+		assert.Equal(t, utils.MarshalOrPanic(makeEnvelope("tx2")), block.Data.Data[0])
+	}
+}
+
+func TestChainChangesViewWhenLeaderIsUnresponsive(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	n := newNetwork(t, 4, clock)
+	defer n.halt()
+
+	n.disconnect(1)
+	require.NoError(t, n.nodes[2].chain.Order(makeEnvelope("tx1"), 0))
+
+	// the followers forward the request to all the nodes once it times out,
+	// and then suspect the leader, until the next leader orders it
+	var block *common.Block
+	for i := 0; i < 20 && block == nil; i++ {
+		clock.Increment(testRequestTimeout)
+		select {
+		case block = <-n.nodes[2].support.Blocks:
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+	require.NotNil(t, block, "block was not committed after the view change")
+	assertSignedBlock(t, block, 1, 1)
+
+	for _, id := range []uint64{3, 4} {
+		assertSignedBlock(t, waitForBlock(t, n.nodes[id]), 1, 1)
+	}
+
+	select {
+	case <-n.nodes[1].support.Blocks:
+		t.Fatal("disconnected node wrote a block")
+	default:
+	}
+}
+
+func TestChainDiscardsRequestsFromNonConsenters(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	n := newNetwork(t, 4, clock)
+	defer n.halt()
+
+	err := n.nodes[1].chain.Submit(&orderer.SubmitRequest{Channel: testChannel, Payload: makeEnvelope("tx1")}, 5)
+	require.NoError(t, err)
+
+	select {
+	case <-n.nodes[1].support.Blocks:
+		t.Fatal("request forwarded by a node which is not a consenter was ordered")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestChainRequiresBlockValidationPolicyUpdateWhenAddingConsenter(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	n := newNetwork(t, 4, clock)
+	defer n.halt()
+
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	var consenters []*bft.Consenter
+	for id := uint64(1); id <= 4; id++ {
+		consenters = append(consenters, n.nodes[id].support.consenter)
+	}
+	config := func(consenters []*bft.Consenter) *common.Config {
+		ordererGroup := &common.ConfigGroup{
+			Values: map[string]*common.ConfigValue{
+				"ConsensusType": {Value: utils.MarshalOrPanic(&orderer.ConsensusType{
+					Type: "bft",
+					Metadata: utils.MarshalOrPanic(&bft.ConfigMetadata{
+						Options:    &bft.Options{RequestTimeout: "10s", ViewChangeTimeout: "20s"},
+						Consenters: consenters,
+					}),
+				}), ModPolicy: "Admins"},
+			},
+			Policies: map[string]*common.ConfigPolicy{},
+		}
+		return &common.Config{ChannelGroup: &common.ConfigGroup{Groups: map[string]*common.ConfigGroup{"Orderer": ordererGroup}}}
+	}
+	original := config(consenters)
+	require.NoError(t, encoder.UpdateBFTBlockValidationPolicy(original))
+
+	// configEnvelope returns the config transaction of the config update from the original config
+	configEnvelope := func(updated *common.Config) *common.Envelope {
+		configUpdate, err := update.Compute(original, updated)
+		require.NoError(t, err)
+		configUpdate.ChannelId = testChannel
+		lastUpdate, err := utils.CreateSignedEnvelope(common.HeaderType_CONFIG_UPDATE, testChannel, nil,
+			&common.ConfigUpdateEnvelope{ConfigUpdate: utils.MarshalOrPanic(configUpdate)}, 0, 0)
+		require.NoError(t, err)
+		env, err := utils.CreateSignedEnvelope(common.HeaderType_CONFIG, testChannel, nil,
+			&common.ConfigEnvelope{Config: updated, LastUpdate: lastUpdate}, 0, 0)
+		require.NoError(t, err)
+		return env
+	}
+
+	updated := config(append(consenters, newTestConsenter(t, ca, 5)))
+	updated.ChannelGroup.Groups["Orderer"].Policies = original.ChannelGroup.Groups["Orderer"].Policies
+	err = n.nodes[1].chain.Configure(configEnvelope(updated), 0)
+	assert.EqualError(t, err, "BlockValidation policy does not require 4 signatures out of the 5 consenters")
+
+	updated = config(append(consenters, newTestConsenter(t, ca, 5)))
+	require.NoError(t, encoder.UpdateBFTBlockValidationPolicy(updated))
+	assert.NoError(t, n.nodes[1].chain.checkConfigUpdateValidity(configEnvelope(updated)))
+}
+
+// newPrepared returns the next block proposed in the given view,
+// along with the prepares of the given nodes
+func newPrepared(n *network, view uint64, ids ...uint64) *bft.Prepared {
+	leader := n.nodes[1]
+	block := leader.support.CreateNextBlock([]*common.Envelope{makeEnvelope("tx1")})
+	prepared := &bft.Prepared{
+		PrePrepare: &bft.PrePrepare{
+			Block:    block,
+			Metadata: utils.MarshalOrPanic(&bft.BlockMetadata{ViewId: view}),
+		},
+	}
+	digest := leader.chain.digest(block)
+	for _, id := range ids {
+		prepared.Prepares = append(prepared.Prepares, n.nodes[id].chain.newPrepare(view, block.Header.Number, digest))
+	}
+	return prepared
+}
+
+func TestChainVerifiesPreparedCertificate(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	n := newNetwork(t, 4, clock)
+	defer n.halt()
+	c := n.nodes[1].chain
+
+	view, err := c.verifyPrepared(newPrepared(n, 2, 1, 2, 3))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), view)
+
+	_, err = c.verifyPrepared(newPrepared(n, 2, 1, 2))
+	assert.EqualError(t, err, "proposal is prepared by 2 consenters instead of 3")
+
+	// prepares of the same node are counted once
+	_, err = c.verifyPrepared(newPrepared(n, 2, 1, 2, 2))
+	assert.EqualError(t, err, "proposal is prepared by 2 consenters instead of 3")
+
+	// the signatures of the prepares must satisfy the block validation policy
+	n.nodes[1].support.ConsenterSupport.BlockVerificationErr = errors.New("bad signature")
+	_, err = c.verifyPrepared(newPrepared(n, 2, 1, 2, 3))
+	assert.EqualError(t, err, "prepares do not satisfy the block validation policy: bad signature")
+	n.nodes[1].support.ConsenterSupport.BlockVerificationErr = nil
+
+	// prepares of another block do not certify the proposal
+	prepared := newPrepared(n, 2, 1, 2, 3)
+	prepared.Prepares[0].Digest = []byte("another digest")
+	_, err = c.verifyPrepared(prepared)
+	assert.EqualError(t, err, "proposal is prepared by 2 consenters instead of 3")
+
+	// prepares signed by another identity than a consenter's are discarded
+	prepared = newPrepared(n, 2, 1, 2, 3)
+	prepared.Prepares[0].Signature.SignatureHeader = utils.MarshalOrPanic(&common.SignatureHeader{
+		Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "OtherMSP", IdBytes: []byte("other")}),
+	})
+	_, err = c.verifyPrepared(prepared)
+	assert.EqualError(t, err, "proposal is prepared by 2 consenters instead of 3")
+
+	// the proposal reported without a certificate by a view change is not adopted
+	votes := []*bft.ViewChange{
+		{NextView: 3, Prepared: newPrepared(n, 2, 1, 2)},
+		{NextView: 3, Prepared: &bft.Prepared{PrePrepare: newPrepared(n, 2).PrePrepare}},
+	}
+	assert.Nil(t, c.preparedProposal(votes))
+
+	certified := newPrepared(n, 1, 1, 2, 3)
+	votes = append(votes, &bft.ViewChange{NextView: 3, Prepared: certified})
+	assert.Equal(t, certified, c.preparedProposal(votes))
+}
+
+func TestChainRestoresLockedProposal(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	n := newNetwork(t, 4, clock)
+	defer n.halt()
+
+	dir, err := ioutil.TempDir("", "bft-lock")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	node := n.nodes[2]
+	opts := node.chain.opts
+	opts.LockPath = filepath.Join(dir, testChannel)
+
+	prepared := newPrepared(n, 1, 1, 2, 3)
+	require.NoError(t, writeLock(opts.LockPath, prepared))
+	chain, err := NewChain(node.support, opts, noopConfigurator{}, &testRPC{sender: 2, network: n}, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(prepared, chain.locked))
+
+	// the proposal locked before the last block was committed is not restored
+	node.support.HeightVal = 2
+	node.support.BlockByIndex[1] = prepared.PrePrepare.Block
+	chain, err = NewChain(node.support, opts, noopConfigurator{}, &testRPC{sender: 2, network: n}, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, chain.locked)
+
+	require.NoError(t, ioutil.WriteFile(opts.LockPath, []byte("garbage"), 0640))
+	_, err = NewChain(node.support, opts, noopConfigurator{}, &testRPC{sender: 2, network: n}, nil, nil)
+	assert.Contains(t, err.Error(), "failed to restore locked proposal")
+}
+
+type fakePuller struct {
+	blocks map[uint64]*common.Block
+	closed bool
+}
+
+func (p *fakePuller) PullBlock(seq uint64) *common.Block {
+	return p.blocks[seq]
+}
+
+func (p *fakePuller) Close() {
+	p.closed = true
+}
+
+// newPulledBlock returns the next block, signed by the given nodes
+func newPulledBlock(n *network, ids ...uint64) *common.Block {
+	block := n.nodes[1].support.CreateNextBlock([]*common.Envelope{makeEnvelope("tx1")})
+	metadata := utils.MarshalOrPanic(&bft.BlockMetadata{ViewId: 1})
+	block.Metadata.Metadata[common.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&common.Metadata{Value: metadata})
+
+	value := n.nodes[1].chain.signatureValue(&bft.PrePrepare{Block: block, Metadata: metadata})
+	signatures := &common.Metadata{Value: value}
+	for _, id := range ids {
+		sigHeader := utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(n.nodes[id].support))
+		signatures.Signatures = append(signatures.Signatures, &common.MetadataSignature{
+			SignatureHeader: sigHeader,
+			Signature:       utils.SignOrPanic(n.nodes[id].support, util.ConcatenateBytes(value, sigHeader, block.Header.Bytes())),
+		})
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(signatures)
+	return block
+}
+
+func TestChainVerifiesPulledBlocks(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	n := newNetwork(t, 4, clock)
+	defer n.halt()
+	node := n.nodes[2]
+
+	for _, testCase := range []struct {
+		name  string
+		block func() *common.Block
+	}{
+		{
+			name:  "signed by too few consenters",
+			block: func() *common.Block { return newPulledBlock(n, 1, 3) },
+		},
+		{
+			name:  "signed twice by the same consenter",
+			block: func() *common.Block { return newPulledBlock(n, 1, 3, 3) },
+		},
+		{
+			name: "not extending the last block",
+			block: func() *common.Block {
+				block := newPulledBlock(n, 1, 3, 4)
+				block.Header.PreviousHash = []byte("forged")
+				return block
+			},
+		},
+		{
+			name: "with forged data",
+			block: func() *common.Block {
+				block := newPulledBlock(n, 1, 3, 4)
+				block.Data.Data[0] = []byte("forged")
+				return block
+			},
+		},
+		{
+			name: "with other metadata than the signed metadata",
+			block: func() *common.Block {
+				block := newPulledBlock(n, 1, 3, 4)
+				block.Metadata.Metadata[common.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&common.Metadata{
+					Value: utils.MarshalOrPanic(&bft.BlockMetadata{ViewId: 2}),
+				})
+				return block
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			puller := &fakePuller{blocks: map[uint64]*common.Block{1: testCase.block()}}
+			chain, err := NewChain(node.support, node.chain.opts, noopConfigurator{}, &testRPC{sender: 2, network: n},
+				func() (BlockPuller, error) { return puller, nil }, nil)
+			require.NoError(t, err)
+
+			chain.catchUp(1)
+			assert.True(t, puller.closed)
+			assert.Equal(t, uint64(0), chain.lastBlock.Header.Number)
+			select {
+			case <-node.support.Blocks:
+				t.Fatal("forged block was written")
+			default:
+			}
+		})
+	}
+
+	// the signatures of a quorum must satisfy the block validation policy
+	puller := &fakePuller{blocks: map[uint64]*common.Block{1: newPulledBlock(n, 1, 3, 4)}}
+	chain, err := NewChain(node.support, node.chain.opts, noopConfigurator{}, &testRPC{sender: 2, network: n},
+		func() (BlockPuller, error) { return puller, nil }, nil)
+	require.NoError(t, err)
+	node.support.BlockVerificationErr = errors.New("bad signature")
+	chain.catchUp(1)
+	assert.Equal(t, uint64(0), chain.lastBlock.Header.Number)
+
+	node.support.BlockVerificationErr = nil
+	chain.catchUp(1)
+	assertSignedBlock(t, waitForBlock(t, node), 1, 1)
+	assert.Equal(t, uint64(1), chain.lastBlock.Header.Number)
+}
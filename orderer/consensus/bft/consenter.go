@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"path"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/viperutil"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/multichannel"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/orderer/consensus/inactive"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/pkg/errors"
+)
+
+// DefaultTickInterval is the interval at which a chain checks the timeouts
+// of its pending requests and of the view change in progress.
+const DefaultTickInterval = 500 * time.Millisecond
+
+// Config contains BFT configurations
+type Config struct {
+	BFTLockDir string // Proposal locked by the node in <my-channel> is persisted in BFTLockDir/<my-channel>
+}
+
+// Consenter implements the BFT consenter. It shares the cluster communication
+// of the etcdraft consenter, which dispatches the messages of BFT channels
+// to their chains.
+type Consenter struct {
+	CreateChain           func(chainName string)
+	InactiveChainRegistry etcdraft.InactiveChainRegistry
+	Dialer                *cluster.PredicateDialer
+	Communication         cluster.Communicator
+	Logger                *flogging.FabricLogger
+	OrdererConfig         localconfig.TopLevel
+	BFTConfig             Config
+	Cert                  []byte
+	Metrics               *Metrics
+}
+
+func (c *Consenter) detectSelfID(consenters map[uint64]*bft.Consenter) (uint64, error) {
+	thisNodeCertAsDER, err := pemToDER(c.Cert, 0, "server", c.Logger)
+	if err != nil {
+		return 0, err
+	}
+
+	var serverCertificates []string
+	for nodeID, cst := range consenters {
+		serverCertificates = append(serverCertificates, string(cst.ServerTlsCert))
+
+		certAsDER, err := pemToDER(cst.ServerTlsCert, nodeID, "server", c.Logger)
+		if err != nil {
+			return 0, err
+		}
+
+		if bytes.Equal(thisNodeCertAsDER, certAsDER) {
+			return nodeID, nil
+		}
+	}
+
+	c.Logger.Warning("Could not find", string(c.Cert), "among", serverCertificates)
+	return 0, cluster.ErrNotInChannel
+}
+
+// HandleChain returns a new Chain instance or an error upon failure
+func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *common.Metadata) (consensus.Chain, error) {
+	m := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(support.SharedConfig().ConsensusMetadata(), m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus metadata")
+	}
+
+	if err := CheckConfigMetadata(m); err != nil {
+		return nil, errors.WithMessage(err, "invalid BFT config metadata")
+	}
+
+	blockMetadata, err := ReadBlockMetadata(metadata)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read BFT metadata")
+	}
+
+	consenters := ConsentersToMap(m.Consenters)
+
+	id, err := c.detectSelfID(consenters)
+	if err != nil {
+		c.InactiveChainRegistry.TrackChain(support.ChainID(), support.Block(0), func() {
+			c.CreateChain(support.ChainID())
+		})
+		return &inactive.Chain{Err: errors.Errorf("channel %s is not serviced by me", support.ChainID())}, nil
+	}
+
+	// CheckConfigMetadata made sure the timeouts are valid durations
+	requestTimeout, _ := time.ParseDuration(m.Options.RequestTimeout)
+	viewChangeTimeout, _ := time.ParseDuration(m.Options.ViewChangeTimeout)
+
+	opts := Options{
+		ID:     id,
+		Clock:  clock.NewClock(),
+		Logger: c.Logger,
+
+		TickInterval:      DefaultTickInterval,
+		RequestTimeout:    requestTimeout,
+		ViewChangeTimeout: viewChangeTimeout,
+
+		View:       blockMetadata.ViewId,
+		Consenters: consenters,
+		Metrics:    c.Metrics,
+	}
+	if c.BFTConfig.BFTLockDir != "" {
+		opts.LockPath = path.Join(c.BFTConfig.BFTLockDir, support.ChainID())
+	}
+
+	rpc := &cluster.RPC{
+		Timeout:       c.OrdererConfig.General.Cluster.RPCTimeout,
+		Logger:        c.Logger,
+		Channel:       support.ChainID(),
+		Comm:          c.Communication,
+		StreamsByType: cluster.NewStreamsByType(),
+	}
+	return NewChain(
+		support,
+		opts,
+		c.Communication,
+		rpc,
+		func() (BlockPuller, error) { return newBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster) },
+		func() {
+			c.InactiveChainRegistry.TrackChain(support.ChainID(), nil, func() { c.CreateChain(support.ChainID()) })
+		},
+	)
+}
+
+// RemoveChain removes the proposal locked by the node in the given channel,
+// so that it is not restored if the node joins the channel again.
+func (c *Consenter) RemoveChain(channelID string) error {
+	if c.BFTConfig.BFTLockDir == "" {
+		return nil
+	}
+	return removeLock(path.Join(c.BFTConfig.BFTLockDir, channelID))
+}
+
+// New creates a BFT Consenter which communicates with the other ordering
+// nodes through the given cluster communication.
+func New(
+	clusterDialer *cluster.PredicateDialer,
+	communication cluster.Communicator,
+	conf *localconfig.TopLevel,
+	srvConf comm.ServerConfig,
+	r *multichannel.Registrar,
+	icr etcdraft.InactiveChainRegistry,
+	metricsProvider metrics.Provider,
+) *Consenter {
+	logger := flogging.MustGetLogger("orderer.consensus.bft")
+
+	var cfg Config
+	if err := viperutil.Decode(conf.Consensus, &cfg); err != nil {
+		logger.Panicf("Failed to decode BFT configuration: %s", err)
+	}
+
+	return &Consenter{
+		CreateChain:           r.CreateChain,
+		InactiveChainRegistry: icr,
+		Dialer:                clusterDialer,
+		Communication:         communication,
+		Logger:                logger,
+		OrdererConfig:         *conf,
+		BFTConfig:             cfg,
+		Cert:                  srvConf.SecOpts.Certificate,
+		Metrics:               NewMetrics(metricsProvider),
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// readLock returns the proposal persisted at the given path,
+// nil if the path is empty or no proposal is persisted.
+func readLock(path string) (*bft.Prepared, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read locked proposal from %s", path)
+	}
+
+	prepared := &bft.Prepared{}
+	if err := proto.Unmarshal(b, prepared); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal locked proposal from %s", path)
+	}
+	return prepared, nil
+}
+
+// writeLock persists the proposal at the given path, replacing the
+// proposal persisted before only once the new one is synced to disk.
+// Nothing is persisted if the path is empty.
+func writeLock(path string, prepared *bft.Prepared) error {
+	if path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return errors.Wrapf(err, "failed to create directory of %s", path)
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", tmp)
+	}
+	_, err = f.Write(utils.MarshalOrPanic(prepared))
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to write %s", tmp)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrapf(err, "failed to rename %s to %s", tmp, path)
+	}
+	return nil
+}
+
+// removeLock removes the proposal persisted at the given path, if any.
+func removeLock(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove %s", path)
+	}
+	return nil
+}
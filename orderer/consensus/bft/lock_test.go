@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bft-lock")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "lock", testChannel)
+	prepared := &bft.Prepared{
+		PrePrepare: &bft.PrePrepare{Block: common.NewBlock(1, []byte("previous hash"))},
+		Prepares:   []*bft.Prepare{{Digest: []byte("digest")}},
+	}
+
+	locked, err := readLock(path)
+	require.NoError(t, err)
+	assert.Nil(t, locked)
+
+	require.NoError(t, writeLock(path, prepared))
+	locked, err = readLock(path)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(prepared, locked))
+
+	// the lock is replaced by the next one
+	prepared.PrePrepare.Block = common.NewBlock(2, []byte("previous hash"))
+	require.NoError(t, writeLock(path, prepared))
+	locked, err = readLock(path)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(prepared, locked))
+
+	require.NoError(t, removeLock(path))
+	require.NoError(t, removeLock(path))
+	locked, err = readLock(path)
+	require.NoError(t, err)
+	assert.Nil(t, locked)
+
+	// nothing is persisted without a path
+	require.NoError(t, writeLock("", prepared))
+	locked, err = readLock("")
+	require.NoError(t, err)
+	assert.Nil(t, locked)
+	require.NoError(t, removeLock(""))
+}
+
+func TestConsenterRemoveChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bft-lock")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	prepared := &bft.Prepared{PrePrepare: &bft.PrePrepare{Block: common.NewBlock(1, []byte("previous hash"))}}
+	require.NoError(t, writeLock(filepath.Join(dir, testChannel), prepared))
+	require.NoError(t, writeLock(filepath.Join(dir, "other-channel"), prepared))
+
+	c := &Consenter{BFTConfig: Config{BFTLockDir: dir}}
+	require.NoError(t, c.RemoveChain(testChannel))
+	locked, err := readLock(filepath.Join(dir, testChannel))
+	require.NoError(t, err)
+	assert.Nil(t, locked)
+
+	// the locks of the other channels are kept
+	locked, err = readLock(filepath.Join(dir, "other-channel"))
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(prepared, locked))
+
+	// nothing is removed without a lock directory
+	require.NoError(t, (&Consenter{}).RemoveChain(testChannel))
+}
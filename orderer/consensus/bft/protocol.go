@@ -0,0 +1,733 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// The nodes agree on each block in three phases, as in PBFT:
+//  - the leader of the view proposes the block in a PrePrepare,
+//  - each node validates the block and broadcasts a Prepare carrying its signature
+//    of the digest of the block in the view,
+//  - once a quorum of nodes prepared the block, each node locks it along with the
+//    prepares certifying it, persists the lock, and broadcasts a Commit carrying its
+//    signature of the block,
+//  - once a quorum of signatures satisfies the block validation policy of the
+//    channel, the block is written along with these signatures.
+// When the leader does not order the requests in time or misbehaves, the nodes
+// move to the next view, which has the next node as leader. The locked block
+// certified in the latest view is then proposed again by the new leader.
+
+// round holds the messages of the nodes about the block of a sequence in a view
+type round struct {
+	view       uint64
+	seq        uint64
+	prePrepare *bft.PrePrepare
+	digest     []byte
+	prepares   map[uint64]*bft.Prepare
+	commits    map[uint64]*bft.Commit
+	prepared   bool
+}
+
+type viewChange struct {
+	nextView uint64
+	start    time.Time
+}
+
+// maxFutureMessages bounds the number of messages kept per node
+// until this node catches up with the sequence or the view of the message
+const maxFutureMessages = 4
+
+func (c *Chain) faults() int {
+	return (len(c.nodes) - 1) / 3
+}
+
+func (c *Chain) quorum() int {
+	return bft.Quorum(len(c.nodes))
+}
+
+func (c *Chain) leaderOf(view uint64) uint64 {
+	return c.nodes[view%uint64(len(c.nodes))]
+}
+
+func (c *Chain) leader() uint64 {
+	return c.leaderOf(c.view)
+}
+
+func (c *Chain) isLeader() bool {
+	return c.viewChange == nil && c.leader() == c.id
+}
+
+func (c *Chain) currentRound() *round {
+	seq := c.lastBlock.Header.Number + 1
+	if c.round == nil || c.round.view != c.view || c.round.seq != seq {
+		c.round = &round{
+			view:     c.view,
+			seq:      seq,
+			prepares: make(map[uint64]*bft.Prepare),
+			commits:  make(map[uint64]*bft.Commit),
+		}
+	}
+	return c.round
+}
+
+func (c *Chain) digest(block *common.Block) []byte {
+	return blockHashingAlgorithm(c.support)(block.Header.Bytes())
+}
+
+func (c *Chain) broadcast(msg *bft.ConsensusMessage) {
+	payload := utils.MarshalOrPanic(msg)
+	for _, id := range c.nodes {
+		if id == c.id {
+			continue
+		}
+		if err := c.rpc.SendConsensus(id, &orderer.ConsensusRequest{Channel: c.channelID, Payload: payload}); err != nil {
+			c.logger.Debugf("Failed sending message to %d: %s", id, err)
+		}
+	}
+}
+
+// handleMessage processes a message sent by another node
+func (c *Chain) handleMessage(sender uint64, msg *bft.ConsensusMessage) {
+	if _, exists := c.consenters[sender]; !exists {
+		c.logger.Warningf("Discarding message from %d which is not a consenter", sender)
+		return
+	}
+
+	if vc := msg.GetViewChange(); vc != nil {
+		c.trackProgress(sender, msg.View, msg.Seq, false)
+		c.onViewChange(sender, vc)
+		return
+	}
+
+	c.trackProgress(sender, msg.View, msg.Seq, true)
+
+	nextSeq := c.lastBlock.Header.Number + 1
+	switch {
+	case msg.Seq < nextSeq || msg.View < c.view:
+		return
+	case msg.Seq > nextSeq+1:
+		return
+	case msg.Seq == nextSeq+1 || msg.View > c.view:
+		c.keepForLater(sender, msg)
+		return
+	case c.viewChange != nil:
+		return
+	}
+
+	switch {
+	case msg.GetPrePrepare() != nil:
+		c.onPrePrepare(sender, msg.PrePrepare)
+	case msg.GetPrepare() != nil:
+		c.onPrepare(sender, msg.Prepare)
+	case msg.GetCommit() != nil:
+		c.onCommit(sender, msg.Commit)
+	default:
+		c.logger.Warningf("Discarding empty message from %d", sender)
+	}
+}
+
+func (c *Chain) keepForLater(sender uint64, msg *bft.ConsensusMessage) {
+	kept := 0
+	for _, m := range c.future {
+		if m.sender == sender {
+			kept++
+		}
+	}
+	if kept >= maxFutureMessages {
+		return
+	}
+	c.future = append(c.future, &message{sender: sender, msg: msg})
+}
+
+// replayFuture processes again the messages received ahead
+// of this node, once it committed a block or changed the view
+func (c *Chain) replayFuture() {
+	future := c.future
+	c.future = nil
+	for _, m := range future {
+		c.handleMessage(m.sender, m.msg)
+	}
+}
+
+// trackProgress records the sequence and the view a node is in. When f+1 nodes,
+// thus at least one correct node, are in a later view, this node moves to this view.
+func (c *Chain) trackProgress(sender, view, seq uint64, inView bool) {
+	if seq > c.heights[sender] {
+		c.heights[sender] = seq
+	}
+
+	if !inView || view <= c.views[sender] {
+		return
+	}
+	c.views[sender] = view
+
+	var later []uint64
+	for id, v := range c.views {
+		if id != c.id && v > c.view {
+			later = append(later, v)
+		}
+	}
+	f := c.faults()
+	if len(later) < f+1 {
+		return
+	}
+	sort.Slice(later, func(i, j int) bool { return later[i] > later[j] })
+	c.logger.Infof("Nodes of the channel moved to view %d", later[f])
+	c.enterView(later[f], nil)
+	c.replayFuture()
+}
+
+// syncTarget returns the last block committed by at least one correct node
+// if this node is behind, 0 otherwise
+func (c *Chain) syncTarget() uint64 {
+	nextSeq := c.lastBlock.Header.Number + 1
+	var ahead []uint64
+	for id, seq := range c.heights {
+		if id != c.id && seq > nextSeq {
+			ahead = append(ahead, seq)
+		}
+	}
+	f := c.faults()
+	if len(ahead) < f+1 {
+		return 0
+	}
+	sort.Slice(ahead, func(i, j int) bool { return ahead[i] > ahead[j] })
+	return ahead[f] - 1
+}
+
+// proposeBatch proposes the next batch if this node is the leader
+// and no block is in flight
+func (c *Chain) proposeBatch() {
+	if !c.isLeader() || len(c.batches) == 0 {
+		return
+	}
+	if r := c.currentRound(); r.prePrepare != nil {
+		return
+	}
+
+	batch := c.batches[0]
+	c.batches = c.batches[1:]
+
+	block := c.support.CreateNextBlock(batch)
+	c.logger.Debugf("Proposing block [%d] with %d transactions in view %d", block.Header.Number, len(batch), c.view)
+	c.propose(&bft.PrePrepare{
+		Block:    block,
+		Metadata: utils.MarshalOrPanic(&bft.BlockMetadata{ViewId: c.view}),
+	})
+}
+
+func (c *Chain) propose(prePrepare *bft.PrePrepare) {
+	r := c.currentRound()
+	r.prePrepare = prePrepare
+	r.digest = c.digest(prePrepare.Block)
+
+	c.broadcast(&bft.ConsensusMessage{View: r.view, Seq: r.seq, PrePrepare: prePrepare})
+	c.prepare(r)
+}
+
+func (c *Chain) onPrePrepare(sender uint64, prePrepare *bft.PrePrepare) {
+	if sender != c.leader() {
+		c.logger.Warningf("Discarding proposal from %d which is not the leader of view %d", sender, c.view)
+		return
+	}
+
+	r := c.currentRound()
+	if err := c.verifyProposal(r.view, r.seq, prePrepare); err != nil {
+		c.logger.Warningf("Rejecting proposal of block [%d] from leader %d: %s", r.seq, sender, err)
+		c.Metrics.ProposalFailures.Add(1)
+		c.startViewChange(c.view + 1)
+		return
+	}
+
+	digest := c.digest(prePrepare.Block)
+	if r.prePrepare != nil {
+		if !bytes.Equal(digest, r.digest) {
+			c.logger.Warningf("Leader %d proposed two different blocks [%d]", sender, r.seq)
+			c.startViewChange(c.view + 1)
+		}
+		return
+	}
+
+	if c.locked != nil && c.locked.PrePrepare.Block.Header.Number == r.seq && !bytes.Equal(digest, c.digest(c.locked.PrePrepare.Block)) {
+		c.logger.Warningf("Leader %d proposed block [%d] which conflicts with the block prepared by a quorum", sender, r.seq)
+		c.startViewChange(c.view + 1)
+		return
+	}
+
+	r.prePrepare = prePrepare
+	r.digest = digest
+	c.prepare(r)
+	c.checkCommitted(r)
+}
+
+// verifyProposal checks that the proposed block extends the ledger of this
+// node and contains valid transactions.
+func (c *Chain) verifyProposal(view, seq uint64, prePrepare *bft.PrePrepare) error {
+	block := prePrepare.Block
+	if block == nil || block.Header == nil || block.Data == nil || block.Metadata == nil {
+		return errors.New("malformed block")
+	}
+
+	if block.Header.Number != seq {
+		return errors.Errorf("expected block [%d] but got block [%d]", seq, block.Header.Number)
+	}
+
+	hash := blockHashingAlgorithm(c.support)
+	if !bytes.Equal(block.Header.PreviousHash, hash(c.lastBlock.Header.Bytes())) {
+		return errors.New("previous hash does not match the last block")
+	}
+	if !bytes.Equal(block.Header.DataHash, hash(block.Data.Bytes())) {
+		return errors.New("data hash does not match the data of the block")
+	}
+
+	if len(block.Metadata.Metadata) != len(common.BlockMetadataIndex_name) {
+		return errors.Errorf("block has %d metadata entries instead of %d", len(block.Metadata.Metadata), len(common.BlockMetadataIndex_name))
+	}
+	for _, metadata := range block.Metadata.Metadata {
+		if len(metadata) != 0 {
+			return errors.New("block metadata is not empty")
+		}
+	}
+
+	bm := &bft.BlockMetadata{}
+	if err := proto.Unmarshal(prePrepare.Metadata, bm); err != nil {
+		return errors.Wrap(err, "failed to unmarshal block's metadata")
+	}
+	if bm.ViewId != view {
+		return errors.Errorf("block is proposed in view %d but its metadata is of view %d", view, bm.ViewId)
+	}
+
+	if len(block.Data.Data) == 0 {
+		return errors.New("block is empty")
+	}
+	for i, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			return errors.WithMessagef(err, "transaction %d is not an envelope", i)
+		}
+
+		if !isConfig(env) {
+			if _, err := c.support.ProcessNormalMsg(env); err != nil {
+				return errors.WithMessagef(err, "transaction %d is not valid", i)
+			}
+			continue
+		}
+
+		if len(block.Data.Data) != 1 {
+			return errors.New("config transaction is not alone in its block")
+		}
+		if _, _, err := c.support.ProcessConfigMsg(env); err != nil {
+			return errors.WithMessage(err, "config transaction is not valid")
+		}
+	}
+
+	return nil
+}
+
+// prepareValue returns the value signed along with the signature header
+// of a node which prepared the block with the given digest in a view.
+func prepareValue(view, seq uint64, digest []byte) []byte {
+	return utils.MarshalOrPanic(&bft.ConsensusMessage{View: view, Seq: seq, Prepare: &bft.Prepare{Digest: digest}})
+}
+
+// newPrepare returns the prepare of this node for the block with the given digest in a view
+func (c *Chain) newPrepare(view, seq uint64, digest []byte) *bft.Prepare {
+	sigHeader := utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(c.support))
+	return &bft.Prepare{
+		Digest: digest,
+		Signature: &common.MetadataSignature{
+			SignatureHeader: sigHeader,
+			Signature:       utils.SignOrPanic(c.support, util.ConcatenateBytes(prepareValue(view, seq, digest), sigHeader)),
+		},
+	}
+}
+
+func (c *Chain) prepare(r *round) {
+	prepare := c.newPrepare(r.view, r.seq, r.digest)
+	r.prepares[c.id] = prepare
+	c.broadcast(&bft.ConsensusMessage{View: r.view, Seq: r.seq, Prepare: prepare})
+	c.checkPrepared(r)
+}
+
+func (c *Chain) onPrepare(sender uint64, prepare *bft.Prepare) {
+	if !c.isSignedBySender(sender, prepare.Signature) {
+		c.logger.Warningf("Discarding prepare from %d which is not signed by its identity", sender)
+		return
+	}
+
+	r := c.currentRound()
+	r.prepares[sender] = prepare
+	c.checkPrepared(r)
+}
+
+// checkPrepared locks the proposal and signs it once the prepares
+// of a quorum certify it
+func (c *Chain) checkPrepared(r *round) {
+	if r.prePrepare == nil || r.prepared {
+		return
+	}
+
+	var prepares []*bft.Prepare
+	for _, id := range c.nodes {
+		if prepare, exists := r.prepares[id]; exists && bytes.Equal(prepare.Digest, r.digest) {
+			prepares = append(prepares, prepare)
+		}
+	}
+	if len(prepares) < c.quorum() {
+		return
+	}
+
+	prepared := &bft.Prepared{PrePrepare: r.prePrepare, Prepares: prepares}
+	if _, err := c.verifyPrepared(prepared); err != nil {
+		c.logger.Debugf("Prepares of block [%d] do not certify it yet: %s", r.seq, err)
+		return
+	}
+
+	r.prepared = true
+	c.lock(prepared)
+
+	sigHeader := utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(c.support))
+	commit := &bft.Commit{
+		Digest: r.digest,
+		Signature: &common.MetadataSignature{
+			SignatureHeader: sigHeader,
+			Signature:       utils.SignOrPanic(c.support, util.ConcatenateBytes(c.signatureValue(r.prePrepare), sigHeader, r.prePrepare.Block.Header.Bytes())),
+		},
+	}
+	r.commits[c.id] = commit
+	c.broadcast(&bft.ConsensusMessage{View: r.view, Seq: r.seq, Commit: commit})
+	c.checkCommitted(r)
+}
+
+func (c *Chain) onCommit(sender uint64, commit *bft.Commit) {
+	if !c.isSignedBySender(sender, commit.Signature) {
+		c.logger.Warningf("Discarding commit from %d which is not signed by its identity", sender)
+		return
+	}
+
+	r := c.currentRound()
+	r.commits[sender] = commit
+	c.checkCommitted(r)
+}
+
+// checkCommitted writes the proposal once the signatures of a quorum
+// satisfy the block validation policy
+func (c *Chain) checkCommitted(r *round) {
+	if r.prePrepare == nil {
+		return
+	}
+
+	value := c.signatureValue(r.prePrepare)
+	header := r.prePrepare.Block.Header.Bytes()
+
+	var signatures []*common.MetadataSignature
+	var signedData []*common.SignedData
+	for _, id := range c.nodes {
+		commit, exists := r.commits[id]
+		if !exists || !bytes.Equal(commit.Digest, r.digest) {
+			continue
+		}
+		sigHeader, err := utils.GetSignatureHeader(commit.Signature.SignatureHeader)
+		if err != nil {
+			continue
+		}
+		signatures = append(signatures, commit.Signature)
+		signedData = append(signedData, &common.SignedData{
+			Identity:  sigHeader.Creator,
+			Data:      util.ConcatenateBytes(value, commit.Signature.SignatureHeader, header),
+			Signature: commit.Signature.Signature,
+		})
+	}
+	if len(signatures) < c.quorum() {
+		return
+	}
+
+	if err := c.support.VerifyBlockSignature(signedData, nil); err != nil {
+		c.logger.Debugf("Signatures of block [%d] do not satisfy the block validation policy yet: %s", r.seq, err)
+		return
+	}
+
+	block := r.prePrepare.Block
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&common.Metadata{
+		Value:      value,
+		Signatures: signatures,
+	})
+	c.writeBlock(block, r.prePrepare.Metadata)
+	c.replayFuture()
+}
+
+// isSignedBySender returns whether the signature header names the identity of the sender
+func (c *Chain) isSignedBySender(sender uint64, signature *common.MetadataSignature) bool {
+	if signature == nil {
+		return false
+	}
+	sigHeader, err := utils.GetSignatureHeader(signature.SignatureHeader)
+	return err == nil && isSignedBy(sigHeader, c.consenters[sender])
+}
+
+// signer returns the consenter whose identity is named by the signature header
+func (c *Chain) signer(sigHeader *common.SignatureHeader) (uint64, bool) {
+	for _, id := range c.nodes {
+		if isSignedBy(sigHeader, c.consenters[id]) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// verifyPrepared checks that the prepares of a quorum of consenters certify the
+// proposal in the view it was proposed in, and returns this view.
+func (c *Chain) verifyPrepared(prepared *bft.Prepared) (uint64, error) {
+	prePrepare := prepared.GetPrePrepare()
+	if prePrepare.GetBlock().GetHeader() == nil {
+		return 0, errors.New("malformed proposal")
+	}
+	bm := &bft.BlockMetadata{}
+	if err := proto.Unmarshal(prePrepare.Metadata, bm); err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal block's metadata")
+	}
+
+	digest := c.digest(prePrepare.Block)
+	value := prepareValue(bm.ViewId, prePrepare.Block.Header.Number, digest)
+
+	signers := make(map[uint64]struct{})
+	var signedData []*common.SignedData
+	for _, prepare := range prepared.Prepares {
+		if prepare.Signature == nil || !bytes.Equal(prepare.Digest, digest) {
+			continue
+		}
+		sigHeader, err := utils.GetSignatureHeader(prepare.Signature.SignatureHeader)
+		if err != nil {
+			continue
+		}
+		id, exists := c.signer(sigHeader)
+		if !exists {
+			continue
+		}
+		if _, exists := signers[id]; exists {
+			continue
+		}
+		signers[id] = struct{}{}
+		signedData = append(signedData, &common.SignedData{
+			Identity:  sigHeader.Creator,
+			Data:      util.ConcatenateBytes(value, prepare.Signature.SignatureHeader),
+			Signature: prepare.Signature.Signature,
+		})
+	}
+	if len(signers) < c.quorum() {
+		return 0, errors.Errorf("proposal is prepared by %d consenters instead of %d", len(signers), c.quorum())
+	}
+
+	if err := c.support.VerifyBlockSignature(signedData, nil); err != nil {
+		return 0, errors.WithMessage(err, "prepares do not satisfy the block validation policy")
+	}
+	return bm.ViewId, nil
+}
+
+// lock makes this node only accept the given proposal for its sequence,
+// until it is committed. The lock is persisted before this node signs
+// the proposal, so that it survives a restart.
+func (c *Chain) lock(prepared *bft.Prepared) {
+	if err := writeLock(c.opts.LockPath, prepared); err != nil {
+		c.logger.Panicf("Failed to persist the lock on block [%d]: %s", prepared.PrePrepare.Block.Header.Number, err)
+	}
+	c.locked = prepared
+}
+
+// unlock releases the lock once its proposal is committed
+func (c *Chain) unlock() {
+	c.locked = nil
+	if err := removeLock(c.opts.LockPath); err != nil {
+		c.logger.Warningf("Failed to remove the persisted lock: %s", err)
+	}
+}
+
+// signatureValue returns the value signed along with the header of the block,
+// which is the one the block writer sets for blocks signed by a single node.
+func (c *Chain) signatureValue(prePrepare *bft.PrePrepare) []byte {
+	lastConfig := c.lastConfigIndex
+	if isConfigUpdate(prePrepare.Block) {
+		lastConfig = prePrepare.Block.Header.Number
+	}
+	return utils.MarshalOrPanic(&common.OrdererBlockMetadata{
+		LastConfig:        &common.LastConfig{Index: lastConfig},
+		ConsenterMetadata: utils.MarshalOrPanic(&common.Metadata{Value: prePrepare.Metadata}),
+	})
+}
+
+// startViewChange makes this node stop following the leader of the current view,
+// and ask the other nodes to move to the given view.
+func (c *Chain) startViewChange(nextView uint64) {
+	if nextView <= c.view || (c.viewChange != nil && c.viewChange.nextView >= nextView) {
+		return
+	}
+
+	c.logger.Infof("Starting view change to view %d", nextView)
+	c.Metrics.ViewChanges.Add(1)
+	c.Metrics.IsLeader.Set(0)
+
+	c.viewChange = &viewChange{nextView: nextView, start: c.clock.Now()}
+	_ = c.support.BlockCutter().Cut()
+	c.batches = nil
+	c.pending = false
+	c.setErrored(true)
+
+	vc := &bft.ViewChange{NextView: nextView}
+	nextSeq := c.lastBlock.Header.Number + 1
+	if c.locked != nil && c.locked.PrePrepare.Block.Header.Number == nextSeq {
+		vc.Prepared = c.locked
+	}
+	c.viewChanges[c.id] = vc
+	c.broadcast(&bft.ConsensusMessage{View: c.view, Seq: nextSeq, ViewChange: vc})
+
+	c.checkViewChange()
+}
+
+func (c *Chain) onViewChange(sender uint64, vc *bft.ViewChange) {
+	if vc.NextView <= c.view {
+		return
+	}
+	if prev, exists := c.viewChanges[sender]; exists && prev.NextView >= vc.NextView {
+		return
+	}
+	c.viewChanges[sender] = vc
+	c.checkViewChange()
+}
+
+// checkViewChange joins the view change to a view requested by f+1 nodes, thus
+// by at least one correct node, and enters the view once a quorum requested it.
+func (c *Chain) checkViewChange() {
+	var requested []uint64
+	for id, vc := range c.viewChanges {
+		if id != c.id && vc.NextView > c.view {
+			requested = append(requested, vc.NextView)
+		}
+	}
+	f := c.faults()
+	if len(requested) >= f+1 {
+		sort.Slice(requested, func(i, j int) bool { return requested[i] > requested[j] })
+		if c.viewChange == nil || c.viewChange.nextView < requested[f] {
+			c.startViewChange(requested[f])
+			return
+		}
+	}
+
+	if c.viewChange == nil {
+		return
+	}
+
+	nextView := c.viewChange.nextView
+	var votes []*bft.ViewChange
+	for _, vc := range c.viewChanges {
+		if vc.NextView == nextView {
+			votes = append(votes, vc)
+		}
+	}
+	if len(votes) < c.quorum() {
+		return
+	}
+
+	c.logger.Infof("A quorum of nodes moved to view %d", nextView)
+	prepared := c.preparedProposal(votes)
+	if prepared != nil && prepared != c.locked {
+		c.lock(prepared)
+	}
+	c.enterView(nextView, prepared)
+	c.replayFuture()
+}
+
+// preparedProposal returns the proposal the next view needs to agree upon again,
+// as it might have been committed by some nodes: among the proposal locked by this
+// node and the ones reported by the view changes, the valid proposal certified
+// as prepared by a quorum in the latest view.
+func (c *Chain) preparedProposal(votes []*bft.ViewChange) *bft.Prepared {
+	nextSeq := c.lastBlock.Header.Number + 1
+	candidates := []*bft.Prepared{c.locked}
+	for _, vc := range votes {
+		candidates = append(candidates, vc.Prepared)
+	}
+
+	var prepared *bft.Prepared
+	var preparedView uint64
+	for _, candidate := range candidates {
+		if candidate.GetPrePrepare().GetBlock().GetHeader().GetNumber() != nextSeq {
+			continue
+		}
+		view, err := c.verifyPrepared(candidate)
+		if err != nil {
+			c.logger.Warningf("Ignoring block reported as prepared without a valid certificate: %s", err)
+			continue
+		}
+		if prepared != nil && view <= preparedView {
+			continue
+		}
+		if err := c.verifyProposal(view, nextSeq, candidate.PrePrepare); err != nil {
+			c.logger.Warningf("Ignoring invalid block reported as prepared: %s", err)
+			continue
+		}
+		prepared, preparedView = candidate, view
+	}
+	return prepared
+}
+
+// enterView makes this node follow the leader of the given view. If this node
+// is the new leader, it proposes the prepared block again before any other one.
+func (c *Chain) enterView(view uint64, prepared *bft.Prepared) {
+	c.logger.Infof("Entering view %d, leader is %d", view, c.leaderOf(view))
+
+	c.view = view
+	c.viewChange = nil
+	for id, vc := range c.viewChanges {
+		if vc.NextView <= view {
+			delete(c.viewChanges, id)
+		}
+	}
+	c.round = nil
+	c.setErrored(false)
+	c.Metrics.View.Set(float64(view))
+	c.Metrics.IsLeader.Set(boolToFloat(c.isLeader()))
+
+	// Give the new leader a whole timeout to order the pending requests
+	c.pool.restartTimers(c.clock.Now())
+
+	if c.isLeader() {
+		nextSeq := c.lastBlock.Header.Number + 1
+		if prepared == nil && c.locked != nil && c.locked.PrePrepare.Block.Header.Number == nextSeq {
+			prepared = c.locked
+		}
+		if prepared != nil {
+			c.logger.Infof("Proposing again block [%d] prepared in a previous view", prepared.PrePrepare.Block.Header.Number)
+			c.propose(&bft.PrePrepare{
+				Block:    prepared.PrePrepare.Block,
+				Metadata: utils.MarshalOrPanic(&bft.BlockMetadata{ViewId: view}),
+			})
+		}
+		c.resetOrdering()
+		return
+	}
+
+	_ = c.support.BlockCutter().Cut()
+	c.batches = nil
+	c.pending = false
+	for _, r := range c.pool.list() {
+		if err := c.rpc.SendSubmit(c.leader(), r.req); err != nil {
+			c.logger.Debugf("Failed forwarding request to leader %d: %s", c.leader(), err)
+		}
+	}
+}
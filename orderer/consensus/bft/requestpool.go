@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// request is a request pending in the pool until the block containing it is committed
+type request struct {
+	req       *orderer.SubmitRequest
+	key       string
+	index     uint64    // order of arrival in the pool
+	timestamp time.Time // time of arrival, or of the last complaint about the request
+	forwarded bool      // whether the request has been forwarded to all the nodes after it timed out
+}
+
+// requestPool keeps the requests received by a node, so that it can detect
+// the requests which are not ordered in time and suspect the leader.
+type requestPool struct {
+	requests  map[string]*request
+	nextIndex uint64
+}
+
+func newRequestPool() *requestPool {
+	return &requestPool{requests: make(map[string]*request)}
+}
+
+// requestKey identifies the envelope of a request
+func requestKey(env *common.Envelope) string {
+	return string(util.ComputeSHA256(utils.MarshalOrPanic(env)))
+}
+
+// add pools the request and returns it, or returns nil if it is already pooled
+func (rp *requestPool) add(req *orderer.SubmitRequest, now time.Time) *request {
+	key := requestKey(req.Payload)
+	if _, exists := rp.requests[key]; exists {
+		return nil
+	}
+	r := &request{req: req, key: key, index: rp.nextIndex, timestamp: now}
+	rp.nextIndex++
+	rp.requests[key] = r
+	return r
+}
+
+func (rp *requestPool) remove(key string) {
+	delete(rp.requests, key)
+}
+
+// removeBlock removes the requests whose envelopes are in the block
+func (rp *requestPool) removeBlock(block *common.Block) {
+	for _, data := range block.Data.Data {
+		delete(rp.requests, string(util.ComputeSHA256(data)))
+	}
+}
+
+// list returns the pending requests in their order of arrival
+func (rp *requestPool) list() []*request {
+	requests := make([]*request, 0, len(rp.requests))
+	for _, r := range rp.requests {
+		requests = append(requests, r)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].index < requests[j].index
+	})
+	return requests
+}
+
+// restartTimers gives the requests a whole timeout to be ordered from now on,
+// e.g. by a newly elected leader
+func (rp *requestPool) restartTimers(now time.Time) {
+	for _, r := range rp.requests {
+		r.timestamp = now
+		r.forwarded = false
+	}
+}
+
+func (rp *requestPool) size() int {
+	return len(rp.requests)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestPool(t *testing.T) {
+	pool := newRequestPool()
+	envelopes := []*common.Envelope{{Payload: []byte("tx1")}, {Payload: []byte("tx2")}, {Payload: []byte("tx3")}}
+
+	for i := len(envelopes) - 1; i >= 0; i-- {
+		assert.NotNil(t, pool.add(&orderer.SubmitRequest{Payload: envelopes[i]}, time.Unix(0, 0)))
+	}
+	assert.Nil(t, pool.add(&orderer.SubmitRequest{Payload: envelopes[0]}, time.Unix(0, 0)), "a request is only pooled once")
+	assert.Equal(t, 3, pool.size())
+
+	requests := pool.list()
+	assert.Equal(t, envelopes[2], requests[0].req.Payload, "requests are listed in their order of arrival")
+	assert.Equal(t, envelopes[0], requests[2].req.Payload)
+
+	requests[0].forwarded = true
+	pool.restartTimers(time.Unix(10, 0))
+	for _, r := range pool.list() {
+		assert.False(t, r.forwarded)
+		assert.Equal(t, time.Unix(10, 0), r.timestamp)
+	}
+
+	pool.removeBlock(&common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(envelopes[1])}}})
+	assert.Equal(t, 2, pool.size())
+
+	pool.remove(requestKey(envelopes[0]))
+	assert.Equal(t, 1, pool.size())
+	assert.Equal(t, envelopes[2], pool.list()[0].req.Payload)
+}
@@ -0,0 +1,340 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	x509GM "github.com/Hyperledger-TWGC/tjfoc-gm/x509"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// blockHashingAlgorithm returns the algorithm hashing the blocks of the
+// channel of support.
+func blockHashingAlgorithm(support consensus.ConsenterSupport) func([]byte) []byte {
+	return support.ChannelConfig().BlockHashingAlgorithm()
+}
+
+// newBlockPuller creates a new block puller verifying the signatures of the
+// pulled blocks with the block validation policy of the channel
+func newBlockPuller(support consensus.ConsenterSupport,
+	baseDialer *cluster.PredicateDialer,
+	clusterConfig localconfig.Cluster) (BlockPuller, error) {
+
+	hashingAlgorithm := blockHashingAlgorithm(support)
+	verifyBlockSequence := func(blocks []*common.Block, _ string) error {
+		return cluster.VerifyBlocksWithHashingAlgorithm(blocks, support, hashingAlgorithm)
+	}
+
+	stdDialer := &cluster.StandardDialer{
+		ClientConfig: baseDialer.ClientConfig.Clone(),
+	}
+	stdDialer.ClientConfig.AsyncConnect = false
+	stdDialer.ClientConfig.SecOpts.VerifyCertificate = nil
+
+	// Extract the TLS CA certs and endpoints from the configuration,
+	endpoints, err := etcdraft.EndpointconfigFromFromSupport(support)
+	if err != nil {
+		return nil, err
+	}
+
+	der, _ := pem.Decode(stdDialer.ClientConfig.SecOpts.Certificate)
+	if der == nil {
+		return nil, errors.Errorf("client certificate isn't in PEM format: %v",
+			string(stdDialer.ClientConfig.SecOpts.Certificate))
+	}
+
+	return &cluster.BlockPuller{
+		VerifyBlockSequence: verifyBlockSequence,
+		Logger:              flogging.MustGetLogger("orderer.common.cluster.puller"),
+		RetryTimeout:        clusterConfig.ReplicationRetryTimeout,
+		MaxTotalBufferBytes: clusterConfig.ReplicationBufferSize,
+		FetchTimeout:        clusterConfig.ReplicationPullTimeout,
+		Endpoints:           endpoints,
+		Signer:              support,
+		TLSCert:             der.Bytes,
+		Channel:             support.ChainID(),
+		Dialer:              stdDialer,
+	}, nil
+}
+
+// ReadBlockMetadata reads the BFT metadata of a block from its orderer metadata.
+// A block without it, such as the genesis block, is considered agreed upon in view 0.
+func ReadBlockMetadata(blockMetadata *common.Metadata) (*bft.BlockMetadata, error) {
+	m := &bft.BlockMetadata{}
+	if blockMetadata == nil || len(blockMetadata.Value) == 0 {
+		return m, nil
+	}
+	if err := proto.Unmarshal(blockMetadata.Value, m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal block's metadata")
+	}
+	return m, nil
+}
+
+// ConsentersToMap maps the consenters of the metadata to their IDs
+func ConsentersToMap(consenters []*bft.Consenter) map[uint64]*bft.Consenter {
+	set := map[uint64]*bft.Consenter{}
+	for _, c := range consenters {
+		set[c.ConsenterId] = c
+	}
+	return set
+}
+
+// MetadataFromConfigValue reads and translates configuration updates from config value into BFT metadata
+func MetadataFromConfigValue(configValue *common.ConfigValue) (*bft.ConfigMetadata, error) {
+	consensusTypeValue := &orderer.ConsensusType{}
+	if err := proto.Unmarshal(configValue.Value, consensusTypeValue); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensusType config update")
+	}
+
+	if consensusTypeValue.Type != bft.TypeKey {
+		return nil, errors.Errorf("consensus type cannot be changed from %s to %s", bft.TypeKey, consensusTypeValue.Type)
+	}
+
+	updatedMetadata := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusTypeValue.Metadata, updatedMetadata); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal updated (new) BFT metadata configuration")
+	}
+
+	return updatedMetadata, nil
+}
+
+// MetadataFromConfigUpdate extracts consensus metadata from config update
+func MetadataFromConfigUpdate(update *common.ConfigUpdate) (*bft.ConfigMetadata, error) {
+	var baseVersion uint64
+	if update.ReadSet != nil && update.ReadSet.Groups != nil {
+		if ordererConfigGroup, ok := update.ReadSet.Groups["Orderer"]; ok {
+			if val, ok := ordererConfigGroup.Values["ConsensusType"]; ok {
+				baseVersion = val.Version
+			}
+		}
+	}
+
+	if update.WriteSet != nil && update.WriteSet.Groups != nil {
+		if ordererConfigGroup, ok := update.WriteSet.Groups["Orderer"]; ok {
+			if val, ok := ordererConfigGroup.Values["ConsensusType"]; ok {
+				if baseVersion == val.Version {
+					// Only if the version in the write set differs from the read-set
+					// should we consider this to be an update to the consensus type
+					return nil, nil
+				}
+				return MetadataFromConfigValue(val)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// BlockValidationPolicyUpdated returns whether the config update modifies the
+// BlockValidation policy of the orderer group
+func BlockValidationPolicyUpdated(update *common.ConfigUpdate) bool {
+	var baseVersion uint64
+	if ordererConfigGroup, ok := update.GetReadSet().GetGroups()[channelconfig.OrdererGroupKey]; ok {
+		if policy, ok := ordererConfigGroup.Policies[encoder.BlockValidationPolicyKey]; ok {
+			baseVersion = policy.Version
+		}
+	}
+
+	if ordererConfigGroup, ok := update.GetWriteSet().GetGroups()[channelconfig.OrdererGroupKey]; ok {
+		if policy, ok := ordererConfigGroup.Policies[encoder.BlockValidationPolicyKey]; ok {
+			return policy.Version != baseVersion
+		}
+	}
+	return false
+}
+
+// CheckBlockValidationPolicy checks that the BlockValidation policy of the orderer
+// group of the config requires the signatures of a quorum of the BFT consenters of
+// the config, as set by configtxgen.
+func CheckBlockValidationPolicy(config *common.Config) error {
+	ordererConfigGroup, ok := config.GetChannelGroup().GetGroups()[channelconfig.OrdererGroupKey]
+	if !ok {
+		return errors.New("config has no orderer group")
+	}
+
+	consensusType, ok := ordererConfigGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return errors.New("config has no consensus type")
+	}
+	metadata, err := MetadataFromConfigValue(consensusType)
+	if err != nil {
+		return err
+	}
+
+	expected, err := bft.BlockValidationPolicy(metadata.Consenters)
+	if err != nil {
+		return err
+	}
+
+	configPolicy, ok := ordererConfigGroup.Policies[encoder.BlockValidationPolicyKey]
+	if !ok || configPolicy.Policy == nil || configPolicy.Policy.Type != int32(common.Policy_SIGNATURE) {
+		return errors.Errorf("%s policy must be a signature policy requiring %d signatures out of the %d consenters",
+			encoder.BlockValidationPolicyKey, bft.Quorum(len(metadata.Consenters)), len(metadata.Consenters))
+	}
+	policy := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(configPolicy.Policy.Value, policy); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s policy", encoder.BlockValidationPolicyKey)
+	}
+	if !proto.Equal(policy, expected) {
+		return errors.Errorf("%s policy does not require %d signatures out of the %d consenters",
+			encoder.BlockValidationPolicyKey, bft.Quorum(len(metadata.Consenters)), len(metadata.Consenters))
+	}
+	return nil
+}
+
+// CheckConfigMetadata validates BFT config metadata
+func CheckConfigMetadata(metadata *bft.ConfigMetadata) error {
+	if metadata == nil {
+		return errors.Errorf("nil BFT config metadata")
+	}
+
+	if metadata.Options == nil {
+		return errors.Errorf("nil BFT config metadata options")
+	}
+
+	requestTimeout, err := time.ParseDuration(metadata.Options.RequestTimeout)
+	if err != nil {
+		return errors.Errorf("failed to parse RequestTimeout (%s) to time duration: %s", metadata.Options.RequestTimeout, err)
+	}
+	viewChangeTimeout, err := time.ParseDuration(metadata.Options.ViewChangeTimeout)
+	if err != nil {
+		return errors.Errorf("failed to parse ViewChangeTimeout (%s) to time duration: %s", metadata.Options.ViewChangeTimeout, err)
+	}
+	if requestTimeout <= 0 || viewChangeTimeout <= 0 {
+		return errors.Errorf("RequestTimeout (%s) and ViewChangeTimeout (%s) must be positive",
+			metadata.Options.RequestTimeout, metadata.Options.ViewChangeTimeout)
+	}
+
+	if len(metadata.Consenters) == 0 {
+		return errors.Errorf("empty consenter set")
+	}
+
+	ids := make(map[uint64]struct{})
+	certs := make(map[string]struct{})
+	for _, consenter := range metadata.Consenters {
+		if consenter == nil {
+			return errors.New("nil consenter in metadata")
+		}
+		if consenter.ConsenterId == 0 {
+			return errors.Errorf("consenter %s:%d has no ID", consenter.Host, consenter.Port)
+		}
+		if _, exists := ids[consenter.ConsenterId]; exists {
+			return errors.Errorf("duplicate consenter ID: %d", consenter.ConsenterId)
+		}
+		ids[consenter.ConsenterId] = struct{}{}
+
+		if consenter.MspId == "" {
+			return errors.Errorf("consenter %d has no MSP ID", consenter.ConsenterId)
+		}
+		if err := validateCert(consenter.Identity, "identity"); err != nil {
+			return err
+		}
+		if err := validateCert(consenter.ServerTlsCert, "server TLS"); err != nil {
+			return err
+		}
+		if err := validateCert(consenter.ClientTlsCert, "client TLS"); err != nil {
+			return err
+		}
+
+		for _, cert := range [][]byte{consenter.ServerTlsCert, consenter.ClientTlsCert} {
+			if _, exists := certs[string(cert)]; exists {
+				return errors.Errorf("duplicate consenter TLS certificate: %s", string(cert))
+			}
+			certs[string(cert)] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func validateCert(pemData []byte, certRole string) error {
+	bl, _ := pem.Decode(pemData)
+
+	if bl == nil {
+		return errors.Errorf("%s certificate is not PEM encoded: %s", certRole, string(pemData))
+	}
+
+	if _, err := x509.ParseCertificate(bl.Bytes); err != nil {
+		if _, err := x509GM.ParseCertificate(bl.Bytes); err != nil {
+			return errors.Errorf("%s certificate has invalid ASN1 structure, %v: %s", certRole, err, string(pemData))
+		}
+	}
+	return nil
+}
+
+func pemToDER(pemBytes []byte, id uint64, certType string, logger *flogging.FabricLogger) ([]byte, error) {
+	bl, _ := pem.Decode(pemBytes)
+	if bl == nil {
+		logger.Errorf("Rejecting PEM block of %s TLS cert for node %d, offending PEM is: %s", certType, id, string(pemBytes))
+		return nil, errors.Errorf("invalid PEM block")
+	}
+	return bl.Bytes, nil
+}
+
+// isSignedBy returns whether the signature header is created by the consenter
+func isSignedBy(signatureHeader *common.SignatureHeader, consenter *bft.Consenter) bool {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(signatureHeader.Creator, sID); err != nil {
+		return false
+	}
+	return sID.Mspid == consenter.MspId && bytes.Equal(sID.IdBytes, consenter.Identity)
+}
+
+// ConsenterCertificate denotes a TLS certificate of a consenter
+type ConsenterCertificate []byte
+
+// IsConsenterOfChannel returns whether the caller is a consenter of a channel
+// by inspecting the given configuration block. Channels of other cluster types
+// than BFT are inspected as etcdraft channels.
+// It returns nil if true, else returns an error.
+func (conCert ConsenterCertificate) IsConsenterOfChannel(configBlock *common.Block) error {
+	if configBlock == nil {
+		return errors.New("nil block")
+	}
+	envelopeConfig, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return err
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelopeConfig)
+	if err != nil {
+		return err
+	}
+	oc, exists := bundle.OrdererConfig()
+	if !exists {
+		return errors.New("no orderer config in bundle")
+	}
+	if oc.ConsensusType() != bft.TypeKey {
+		return etcdraft.ConsenterCertificate(conCert).IsConsenterOfChannel(configBlock)
+	}
+	m := &bft.ConfigMetadata{}
+	if err := proto.Unmarshal(oc.ConsensusMetadata(), m); err != nil {
+		return err
+	}
+
+	for _, consenter := range m.Consenters {
+		if bytes.Equal(conCert, consenter.ServerTlsCert) || bytes.Equal(conCert, consenter.ClientTlsCert) {
+			return nil
+		}
+	}
+	return cluster.ErrNotInChannel
+}
@@ -0,0 +1,191 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConsenter(t *testing.T, ca tlsgen.CA, id uint64) *bft.Consenter {
+	serverPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	clientPair, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+
+	return &bft.Consenter{
+		ConsenterId:   id,
+		Host:          "127.0.0.1",
+		Port:          uint32(7050 + id),
+		MspId:         "SampleOrg",
+		Identity:      clientPair.Cert,
+		ServerTlsCert: serverPair.Cert,
+		ClientTlsCert: clientPair.Cert,
+	}
+}
+
+func TestCheckConfigMetadata(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	options := &bft.Options{RequestTimeout: "10s", ViewChangeTimeout: "20s"}
+	consenter := newTestConsenter(t, ca, 1)
+
+	tests := []struct {
+		metadata *bft.ConfigMetadata
+		err      string
+	}{
+		{nil, "nil BFT config metadata"},
+		{&bft.ConfigMetadata{}, "nil BFT config metadata options"},
+		{&bft.ConfigMetadata{Options: &bft.Options{RequestTimeout: "q", ViewChangeTimeout: "20s"}}, "failed to parse RequestTimeout (q) to time duration"},
+		{&bft.ConfigMetadata{Options: &bft.Options{RequestTimeout: "10s", ViewChangeTimeout: "0"}}, "RequestTimeout (10s) and ViewChangeTimeout (0) must be positive"},
+		{&bft.ConfigMetadata{Options: options}, "empty consenter set"},
+		{&bft.ConfigMetadata{Options: options, Consenters: []*bft.Consenter{{Host: "node-1", Port: 7050}}}, "consenter node-1:7050 has no ID"},
+		{&bft.ConfigMetadata{Options: options, Consenters: []*bft.Consenter{consenter, consenter}}, "duplicate consenter ID: 1"},
+		{&bft.ConfigMetadata{Options: options, Consenters: []*bft.Consenter{{ConsenterId: 1}}}, "consenter 1 has no MSP ID"},
+		{&bft.ConfigMetadata{Options: options, Consenters: []*bft.Consenter{consenter}}, ""},
+	}
+
+	for _, tc := range tests {
+		err := CheckConfigMetadata(tc.metadata)
+		if tc.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Contains(t, err.Error(), tc.err)
+		}
+	}
+
+	duplicate := newTestConsenter(t, ca, 2)
+	duplicate.ClientTlsCert = consenter.ServerTlsCert
+	err = CheckConfigMetadata(&bft.ConfigMetadata{Options: options, Consenters: []*bft.Consenter{consenter, duplicate}})
+	assert.Contains(t, err.Error(), "duplicate consenter TLS certificate")
+
+	invalid := newTestConsenter(t, ca, 2)
+	invalid.Identity = []byte("not a certificate")
+	err = CheckConfigMetadata(&bft.ConfigMetadata{Options: options, Consenters: []*bft.Consenter{consenter, invalid}})
+	assert.EqualError(t, err, "identity certificate is not PEM encoded: not a certificate")
+}
+
+func TestReadBlockMetadata(t *testing.T) {
+	bm, err := ReadBlockMetadata(&common.Metadata{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), bm.ViewId)
+
+	bm, err = ReadBlockMetadata(&common.Metadata{Value: utils.MarshalOrPanic(&bft.BlockMetadata{ViewId: 3})})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), bm.ViewId)
+
+	_, err = ReadBlockMetadata(&common.Metadata{Value: []byte{1, 2, 3}})
+	assert.Contains(t, err.Error(), "failed to unmarshal block's metadata")
+}
+
+func TestMetadataFromConfigValue(t *testing.T) {
+	metadata := &bft.ConfigMetadata{Options: &bft.Options{RequestTimeout: "10s", ViewChangeTimeout: "20s"}}
+	configValue := func(consensusType string) *common.ConfigValue {
+		return &common.ConfigValue{Value: utils.MarshalOrPanic(&orderer.ConsensusType{
+			Type:     consensusType,
+			Metadata: utils.MarshalOrPanic(metadata),
+		})}
+	}
+
+	m, err := MetadataFromConfigValue(configValue("bft"))
+	assert.NoError(t, err)
+	assert.Equal(t, "20s", m.Options.ViewChangeTimeout)
+
+	_, err = MetadataFromConfigValue(configValue("etcdraft"))
+	assert.EqualError(t, err, "consensus type cannot be changed from bft to etcdraft")
+}
+
+func TestCheckBlockValidationPolicy(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	consenters := []*bft.Consenter{
+		newTestConsenter(t, ca, 1),
+		newTestConsenter(t, ca, 2),
+		newTestConsenter(t, ca, 3),
+		newTestConsenter(t, ca, 4),
+	}
+	config := func(consenters []*bft.Consenter, policy *common.Policy) *common.Config {
+		ordererGroup := &common.ConfigGroup{
+			Values: map[string]*common.ConfigValue{
+				"ConsensusType": {Value: utils.MarshalOrPanic(&orderer.ConsensusType{
+					Type: "bft",
+					Metadata: utils.MarshalOrPanic(&bft.ConfigMetadata{
+						Options:    &bft.Options{RequestTimeout: "10s", ViewChangeTimeout: "20s"},
+						Consenters: consenters,
+					}),
+				})},
+			},
+			Policies: map[string]*common.ConfigPolicy{},
+		}
+		if policy != nil {
+			ordererGroup.Policies["BlockValidation"] = &common.ConfigPolicy{Policy: policy}
+		}
+		return &common.Config{ChannelGroup: &common.ConfigGroup{Groups: map[string]*common.ConfigGroup{"Orderer": ordererGroup}}}
+	}
+	signaturePolicy := func(consenters []*bft.Consenter) *common.Policy {
+		envelope, err := bft.BlockValidationPolicy(consenters)
+		require.NoError(t, err)
+		return &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: utils.MarshalOrPanic(envelope)}
+	}
+
+	err = CheckBlockValidationPolicy(config(consenters, signaturePolicy(consenters)))
+	assert.NoError(t, err)
+
+	err = CheckBlockValidationPolicy(config(consenters, signaturePolicy(consenters[:3])))
+	assert.EqualError(t, err, "BlockValidation policy does not require 3 signatures out of the 4 consenters")
+
+	err = CheckBlockValidationPolicy(config(consenters, &common.Policy{Type: int32(common.Policy_IMPLICIT_META)}))
+	assert.EqualError(t, err, "BlockValidation policy must be a signature policy requiring 3 signatures out of the 4 consenters")
+
+	err = CheckBlockValidationPolicy(config(consenters, nil))
+	assert.EqualError(t, err, "BlockValidation policy must be a signature policy requiring 3 signatures out of the 4 consenters")
+
+	err = CheckBlockValidationPolicy(&common.Config{ChannelGroup: &common.ConfigGroup{}})
+	assert.EqualError(t, err, "config has no orderer group")
+}
+
+func TestBlockValidationPolicyUpdated(t *testing.T) {
+	update := func(readVersion, writeVersion uint64) *common.ConfigUpdate {
+		group := func(version uint64) *common.ConfigGroup {
+			return &common.ConfigGroup{Groups: map[string]*common.ConfigGroup{
+				"Orderer": {Policies: map[string]*common.ConfigPolicy{"BlockValidation": {Version: version}}},
+			}}
+		}
+		return &common.ConfigUpdate{ReadSet: group(readVersion), WriteSet: group(writeVersion)}
+	}
+
+	assert.False(t, BlockValidationPolicyUpdated(update(0, 0)))
+	assert.True(t, BlockValidationPolicyUpdated(update(0, 1)))
+	assert.False(t, BlockValidationPolicyUpdated(&common.ConfigUpdate{}))
+}
+
+func TestIsSignedBy(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	consenter := newTestConsenter(t, ca, 1)
+
+	signatureHeader := func(mspID string, idBytes []byte) *common.SignatureHeader {
+		return &common.SignatureHeader{
+			Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: idBytes}),
+		}
+	}
+
+	assert.True(t, isSignedBy(signatureHeader("SampleOrg", consenter.Identity), consenter))
+	assert.False(t, isSignedBy(signatureHeader("OtherOrg", consenter.Identity), consenter))
+	assert.False(t, isSignedBy(signatureHeader("SampleOrg", consenter.ServerTlsCert), consenter))
+	assert.False(t, isSignedBy(&common.SignatureHeader{Creator: []byte{1, 2, 3}}, consenter))
+}
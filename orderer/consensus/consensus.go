@@ -11,6 +11,7 @@ import (
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	cb "github.com/hyperledger/fabric/protos/common"
 )
 
@@ -67,6 +68,39 @@ type Chain interface {
 	Halt()
 }
 
+// ChainRemover is implemented by consenters which persist the state of a chain
+// outside of its ledger, such as the etcdraft WAL and snapshots. It allows the node
+// to delete this state once a channel is removed, so that it is not restored
+// if the node joins the channel again.
+type ChainRemover interface {
+	// RemoveChain deletes the storage of the chain of the given channel.
+	// It is only invoked after the chain has been halted.
+	RemoveChain(channelID string) error
+}
+
+// StatusReporter is implemented by cluster-type Chain implementations.
+// It allows the node to report its cluster relation and its status within that relation.
+// This information is used to generate the channelparticipation.ChannelInfo in response
+// to a "List" request on a particular channel.
+//
+// Not all chains must implement this, in particular non-cluster-type (solo, kafka) are
+// assigned a StaticStatusReporter at construction time.
+type StatusReporter interface {
+	// StatusReport provides the cluster relation and status.
+	StatusReport() (types.ClusterRelation, types.Status)
+}
+
+// StaticStatusReporter is intended for chains that do not implement the StatusReporter interface.
+type StaticStatusReporter struct {
+	ClusterRelation types.ClusterRelation
+	Status          types.Status
+}
+
+// StatusReport returns the cluster relation and status it was constructed with.
+func (s StaticStatusReporter) StatusReport() (types.ClusterRelation, types.Status) {
+	return s.ClusterRelation, s.Status
+}
+
 //go:generate counterfeiter -o mocks/mock_consenter_support.go . ConsenterSupport
 
 // ConsenterSupport provides the resources available to a Consenter implementation.
@@ -101,6 +135,10 @@ type ConsenterSupport interface {
 	// WriteConfigBlock commits a block to the ledger, and applies the config update inside.
 	WriteConfigBlock(block *cb.Block, encodedMetadataValue []byte)
 
+	// WriteSignedBlock commits a block carrying the signatures gathered by the consenter
+	// in its SIGNATURES metadata, and applies the config update inside if there is one.
+	WriteSignedBlock(block *cb.Block, encodedMetadataValue []byte)
+
 	// Sequence returns the current config squence.
 	Sequence() uint64
 
@@ -18,6 +18,9 @@ type blockCreator struct {
 	hash   []byte
 	number uint64
 
+	// hashingAlgorithm hashes the blocks of the channel
+	hashingAlgorithm func([]byte) []byte
+
 	logger *flogging.FabricLogger
 }
 
@@ -37,9 +40,9 @@ func (bc *blockCreator) createNextBlock(envs []*cb.Envelope) *cb.Block {
 	bc.number++
 
 	block := cb.NewBlock(bc.number, bc.hash)
-	block.Header.DataHash = data.Hash()
+	block.Header.DataHash = bc.hashingAlgorithm(data.Bytes())
 	block.Data = data
 
-	bc.hash = block.Header.Hash()
+	bc.hash = bc.hashingAlgorithm(block.Header.Bytes())
 	return block
 }
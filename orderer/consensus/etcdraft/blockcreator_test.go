@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -24,9 +25,10 @@ func getSeedBlock() *cb.Block {
 func TestCreateNextBlock(t *testing.T) {
 	first := cb.NewBlock(0, []byte("firsthash"))
 	bc := &blockCreator{
-		hash:   first.Header.Hash(),
-		number: first.Header.Number,
-		logger: flogging.NewFabricLogger(zap.NewNop()),
+		hash:             first.Header.Hash(),
+		number:           first.Header.Number,
+		hashingAlgorithm: util.ComputeSHA256,
+		logger:           flogging.NewFabricLogger(zap.NewNop()),
 	}
 
 	second := bc.createNextBlock([]*cb.Envelope{{Payload: []byte("some other bytes")}})
@@ -39,3 +41,21 @@ func TestCreateNextBlock(t *testing.T) {
 	assert.Equal(t, third.Data.Hash(), third.Header.DataHash)
 	assert.Equal(t, second.Header.Hash(), third.Header.PreviousHash)
 }
+
+func TestCreateNextBlockWithBlockHashingAlgorithm(t *testing.T) {
+	first := cb.NewBlock(0, []byte("firsthash"))
+	bc := &blockCreator{
+		hash:             util.ComputeSM3(first.Header.Bytes()),
+		number:           first.Header.Number,
+		hashingAlgorithm: util.ComputeSM3,
+		logger:           flogging.NewFabricLogger(zap.NewNop()),
+	}
+
+	second := bc.createNextBlock([]*cb.Envelope{{Payload: []byte("some other bytes")}})
+	assert.Equal(t, util.ComputeSM3(second.Data.Bytes()), second.Header.DataHash)
+	assert.Equal(t, util.ComputeSM3(first.Header.Bytes()), second.Header.PreviousHash)
+
+	third := bc.createNextBlock([]*cb.Envelope{{Payload: []byte("some other bytes")}})
+	assert.Equal(t, util.ComputeSM3(third.Data.Bytes()), third.Header.DataHash)
+	assert.Equal(t, util.ComputeSM3(second.Header.Bytes()), third.Header.PreviousHash)
+}
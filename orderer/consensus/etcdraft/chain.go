@@ -17,8 +17,11 @@ import (
 	"code.cloudfoundry.org/clock"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/orderer"
@@ -122,6 +125,9 @@ type Options struct {
 	Metrics *Metrics
 	Cert    []byte
 
+	// Encryptor encrypts the WAL entries and snapshots at rest, nil if they are persisted in plaintext
+	Encryptor *atrest.Encryptor
+
 	EvictionSuspicion   time.Duration
 	LeaderCheckInterval time.Duration
 }
@@ -156,6 +162,7 @@ type Chain struct {
 	startC   chan struct{}         // Closes when the node is started
 	snapC    chan *raftpb.Snapshot // Signal to catch up with snapshot
 	gcC      chan *gc              // Signal to take snapshot
+	promoteC chan uint64           // Signals the leader that a learner has caught up
 
 	errorCLock sync.RWMutex
 	errorC     chan struct{} // returned by Errored()
@@ -208,7 +215,7 @@ func NewChain(
 	lg := opts.Logger.With("channel", support.ChainID(), "node", opts.RaftID)
 
 	fresh := !wal.Exist(opts.WALDir)
-	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage)
+	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage, opts.Encryptor)
 	if err != nil {
 		return nil, errors.Errorf("failed to restore persisted raft data: %s", err)
 	}
@@ -231,6 +238,7 @@ func NewChain(
 		b := utils.UnmarshalBlockOrPanic(s.Data)
 		snapBlkNum = b.Header.Number
 		cc = s.Metadata.ConfState
+		RemovePromotedLearners(opts.BlockMetadata, cc)
 	}
 
 	b := support.Block(support.Height() - 1)
@@ -251,6 +259,7 @@ func NewChain(
 		snapC:            make(chan *raftpb.Snapshot),
 		errorC:           make(chan struct{}),
 		gcC:              make(chan *gc),
+		promoteC:         make(chan uint64),
 		observeC:         observeC,
 		support:          support,
 		fresh:            fresh,
@@ -476,25 +485,42 @@ func (c *Chain) Errored() <-chan struct{} {
 
 // Halt stops the chain.
 func (c *Chain) Halt() {
+	c.stop()
+}
+
+// stop stops the chain, and returns whether it was stopped by this call.
+func (c *Chain) stop() bool {
 	select {
 	case <-c.startC:
 	default:
 		c.logger.Warnf("Attempted to halt a chain that has not started")
-		return
+		return false
 	}
 
 	select {
 	case c.haltC <- struct{}{}:
 	case <-c.doneC:
-		return
+		return false
 	}
 	<-c.doneC
 
-	if c.haltCallback != nil {
+	return true
+}
+
+// haltEvicted stops the chain once this node is removed from the channel, and
+// calls haltCallback so that the channel is followed from then on. The callback
+// is not called when the chain is halted otherwise, e.g. when it is removed.
+func (c *Chain) haltEvicted() {
+	if c.stop() && c.haltCallback != nil {
 		c.haltCallback()
 	}
 }
 
+// StatusReport returns the ClusterRelation & Status
+func (c *Chain) StatusReport() (types.ClusterRelation, types.Status) {
+	return types.ClusterRelationMember, types.StatusActive
+}
+
 func (c *Chain) isRunning() error {
 	select {
 	case <-c.startC:
@@ -581,13 +607,7 @@ func (c *Chain) serveRequest() {
 		<-timer.C()
 	}
 
-	// if timer is already started, this is a no-op
-	startTimer := func() {
-		if !ticking {
-			ticking = true
-			timer.Reset(c.support.SharedConfig().BatchTimeout())
-		}
-	}
+	var deadline time.Time
 
 	stopTimer := func() {
 		if !timer.Stop() && ticking {
@@ -597,6 +617,20 @@ func (c *Chain) serveRequest() {
 		ticking = false
 	}
 
+	// if timer is already started and expires within timeout, this is a no-op
+	startTimer := func(timeout time.Duration) {
+		now := c.clock.Now()
+		if ticking {
+			if !now.Add(timeout).Before(deadline) {
+				return
+			}
+			stopTimer()
+		}
+		ticking = true
+		deadline = now.Add(timeout)
+		timer.Reset(timeout)
+	}
+
 	var soft raft.SoftState
 	submitC := c.submitC
 	var bc *blockCreator
@@ -686,7 +720,7 @@ func (c *Chain) serveRequest() {
 				continue
 			}
 			if pending {
-				startTimer() // no-op if timer is already started
+				startTimer(blockcutter.PendingBatchTimeout(c.support.SharedConfig(), c.support.BlockCutter())) // no-op if timer expires sooner
 			} else {
 				stopTimer()
 			}
@@ -734,7 +768,8 @@ func (c *Chain) serveRequest() {
 					select {
 					case <-c.errorC:
 					default:
-						nodeCount := len(c.opts.BlockMetadata.ConsenterIds)
+						// learners do not take part in elections
+						nodeCount := len(c.opts.BlockMetadata.ConsenterIds) - len(c.opts.BlockMetadata.LearnerIds)
 						// Only close the error channel (to signal the broadcast/deliver front-end a consensus backend error)
 						// If we are a cluster of size 3 or more, otherwise we can't expand a cluster of size 1 to 2 nodes.
 						if nodeCount > 2 {
@@ -769,10 +804,12 @@ func (c *Chain) serveRequest() {
 				}
 
 				c.logger.Infof("Start accepting requests as Raft leader at block [%d]", c.lastBlock.Header.Number)
+				hashingAlgorithm := blockHashingAlgorithm(c.support)
 				bc = &blockCreator{
-					hash:   c.lastBlock.Header.Hash(),
-					number: c.lastBlock.Header.Number,
-					logger: c.logger,
+					hash:             hashingAlgorithm(c.lastBlock.Header.Bytes()),
+					number:           c.lastBlock.Header.Number,
+					hashingAlgorithm: hashingAlgorithm,
+					logger:           c.logger,
 				}
 				submitC = c.submitC
 				c.justElected = false
@@ -795,6 +832,16 @@ func (c *Chain) serveRequest() {
 			c.logger.Debugf("Batch timer expired, creating block")
 			c.propose(propC, bc, batch) // we are certain this is normal block, no need to block
 
+		case id := <-c.promoteC:
+			if soft.RaftState != raft.StateLeader || c.justElected || c.configInflight {
+				// a config block or ConfChange is in flight, and raft accepts a
+				// single pending ConfChange, so the learner is promoted later on.
+				continue
+			}
+
+			c.promoteLearner(id)
+			submitC = nil
+
 		case sn := <-c.snapC:
 			if sn.Metadata.Index != 0 {
 				if sn.Metadata.Index <= c.appliedIndex {
@@ -804,6 +851,10 @@ func (c *Chain) serveRequest() {
 
 				c.confState = sn.Metadata.ConfState
 				c.appliedIndex = sn.Metadata.Index
+
+				c.raftMetadataLock.Lock()
+				RemovePromotedLearners(c.opts.BlockMetadata, c.confState)
+				c.raftMetadataLock.Unlock()
 			} else {
 				c.logger.Infof("Received artificial snapshot to trigger catchup")
 			}
@@ -955,12 +1006,14 @@ func (c *Chain) catchUp(snap *raftpb.Snapshot) error {
 
 			configMembership := c.detectConfChange(block)
 
-			if configMembership != nil && configMembership.Changed() {
+			if configMembership != nil && (configMembership.Changed() || configMembership.RotatedWithGracePeriod() || configMembership.RotationCompleted()) {
 				c.logger.Infof("Config block [%d] changes consenter set, communication should be reconfigured", block.Header.Number)
 
 				c.raftMetadataLock.Lock()
 				c.opts.BlockMetadata = configMembership.NewBlockMetadata
 				c.opts.Consenters = configMembership.NewConsenters
+				// learners promoted since this block are voters in the snapshot
+				RemovePromotedLearners(c.opts.BlockMetadata, c.confState)
 				c.raftMetadataLock.Unlock()
 
 				if err := c.configureComm(); err != nil {
@@ -1005,6 +1058,14 @@ func (c *Chain) detectConfChange(block *common.Block) *MembershipChanges {
 		c.logger.Infof("Config block [%d] rotates TLS certificate of node %d", block.Header.Number, changes.RotatedNode)
 	}
 
+	if changes.RotatedWithGracePeriod() {
+		c.logger.Infof("Config block [%d] rotates TLS certificates of nodes %v with a grace period", block.Header.Number, changes.RotatedNodes)
+	}
+
+	if changes.RotationCompleted() {
+		c.logger.Infof("Config block [%d] completes the TLS certificate rotation of nodes %v", block.Header.Number, changes.CompletedRotationNodes)
+	}
+
 	return changes
 }
 
@@ -1050,7 +1111,18 @@ func (c *Chain) apply(ents []raftpb.Entry) {
 
 			switch cc.Type {
 			case raftpb.ConfChangeAddNode:
-				c.logger.Infof("Applied config change to add node %d, current nodes in channel: %+v", cc.NodeID, c.confState.Nodes)
+				c.raftMetadataLock.Lock()
+				promoted := NodeExists(cc.NodeID, c.opts.BlockMetadata.LearnerIds)
+				c.opts.BlockMetadata.LearnerIds = RemoveNode(cc.NodeID, c.opts.BlockMetadata.LearnerIds)
+				c.raftMetadataLock.Unlock()
+
+				if promoted {
+					c.logger.Infof("Applied config change to promote learner %d, current nodes in channel: %+v", cc.NodeID, c.confState.Nodes)
+				} else {
+					c.logger.Infof("Applied config change to add node %d, current nodes in channel: %+v", cc.NodeID, c.confState.Nodes)
+				}
+			case raftpb.ConfChangeAddLearnerNode:
+				c.logger.Infof("Applied config change to add learner %d, current learners in channel: %+v", cc.NodeID, c.confState.Learners)
 			case raftpb.ConfChangeRemoveNode:
 				c.logger.Infof("Applied config change to remove node %d, current nodes in channel: %+v", cc.NodeID, c.confState.Nodes)
 			default:
@@ -1087,10 +1159,10 @@ func (c *Chain) apply(ents []raftpb.Entry) {
 						case <-c.doneC:
 						}
 
-						c.Halt()
+						c.haltEvicted()
 					}()
 				} else {
-					go c.Halt()
+					go c.haltEvicted()
 				}
 			}
 		}
@@ -1173,12 +1245,25 @@ func (c *Chain) remotePeers() ([]cluster.RemoteNode, error) {
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
-		nodes = append(nodes, cluster.RemoteNode{
+		node := cluster.RemoteNode{
 			ID:            raftID,
 			Endpoint:      fmt.Sprintf("%s:%d", consenter.Host, consenter.Port),
 			ServerTLSCert: serverCertAsDER,
 			ClientTLSCert: clientCertAsDER,
-		})
+		}
+		if consenter.Rotation != nil {
+			// the previous certificates are accepted until the end of the grace period
+			if node.GracePeriodEnd, err = GracePeriodEnd(consenter.Rotation); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if node.PreviousServerTLSCert, err = pemToDER(consenter.Rotation.ServerTlsCert, raftID, "previous server", c.logger); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if node.PreviousClientTLSCert, err = pemToDER(consenter.Rotation.ClientTlsCert, raftID, "previous client", c.logger); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		nodes = append(nodes, node)
 	}
 	return nodes, nil
 }
@@ -1241,6 +1326,8 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 			switch configMembership.ConfChange.Type {
 			case raftpb.ConfChangeAddNode:
 				c.logger.Infof("Config block just committed adds node %d, pause accepting transactions till config change is applied", configMembership.ConfChange.NodeID)
+			case raftpb.ConfChangeAddLearnerNode:
+				c.logger.Infof("Config block just committed adds learner %d, pause accepting transactions till config change is applied", configMembership.ConfChange.NodeID)
 			case raftpb.ConfChangeRemoveNode:
 				c.logger.Infof("Config block just committed removes node %d, pause accepting transactions till config change is applied", configMembership.ConfChange.NodeID)
 			default:
@@ -1263,6 +1350,13 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 					c.logger.Panicf("Failed to configure communication: %s", err)
 				}
 			}
+		} else if configMembership.RotatedWithGracePeriod() || configMembership.RotationCompleted() {
+			// the nodes keep accepting the previous certificates during the grace
+			// period, hence the communication is reconfigured without a leader transfer.
+			// Once the rotation is completed, the previous certificates are rejected.
+			if err := c.configureComm(); err != nil {
+				c.logger.Panicf("Failed to configure communication: %s", err)
+			}
 		}
 
 	case common.HeaderType_ORDERER_TRANSACTION:
@@ -1298,7 +1392,7 @@ func (c *Chain) getInFlightConfChange() *raftpb.ConfChange {
 	// extracting current Raft configuration state
 	confState := c.Node.ApplyConfChange(raftpb.ConfChange{})
 
-	if len(confState.Nodes) == len(c.opts.BlockMetadata.ConsenterIds) {
+	if len(confState.Nodes)+len(confState.Learners) == len(c.opts.BlockMetadata.ConsenterIds) {
 		// Raft configuration change could only add one node or
 		// remove one node at a time, if raft conf state size is
 		// equal to membership stored in block metadata field,
@@ -1310,6 +1404,33 @@ func (c *Chain) getInFlightConfChange() *raftpb.ConfChange {
 	return ConfChange(c.opts.BlockMetadata, confState)
 }
 
+// promoteLearner proposes to promote a learner which has caught up with
+// the leader to a voter, and pauses accepting transactions till the
+// ConfChange is applied, same as for the ConfChanges of config blocks.
+func (c *Chain) promoteLearner(id uint64) {
+	c.raftMetadataLock.RLock()
+	learner := NodeExists(id, c.opts.BlockMetadata.LearnerIds)
+	c.raftMetadataLock.RUnlock()
+
+	if !learner {
+		c.logger.Warnf("Node %d is not a learner, skip promotion", id)
+		return
+	}
+
+	cc := &raftpb.ConfChange{NodeID: id, Type: raftpb.ConfChangeAddNode}
+
+	// The reason `ProposeConfChange` should be called in go routine is documented in `writeConfigBlock` method.
+	go func() {
+		if err := c.Node.ProposeConfChange(context.TODO(), *cc); err != nil {
+			c.logger.Warnf("Failed to propose promotion of learner %d to Raft node: %s", id, err)
+		}
+	}()
+
+	c.logger.Infof("Learner %d caught up with the leader, pause accepting transactions till it is promoted to voter", id)
+	c.confChangeInProgress = cc
+	c.configInflight = true
+}
+
 // newMetadata extract config metadata from the configuration block
 func (c *Chain) newConfigMetadata(block *common.Block) *etcdraft.ConfigMetadata {
 	metadata, err := ConsensusMetadataFromConfigBlock(block)
@@ -1337,7 +1458,7 @@ func (c *Chain) newEvictionSuspector() *evictionSuspector {
 		triggerCatchUp:             c.triggerCatchup,
 		logger:                     c.logger,
 		halt: func() {
-			c.Halt()
+			c.haltEvicted()
 		},
 	}
 }
@@ -120,6 +120,7 @@ var _ = Describe("Chain", func() {
 
 			support = &consensusmocks.FakeConsenterSupport{}
 			support.ChainIDReturns(channelID)
+			support.ChannelConfigReturns(&mockconfig.Channel{})
 			consenterMetadata = createMetadata(1, tlsCA)
 			support.SharedConfigReturns(&mockconfig.Orderer{
 				BatchTimeoutVal:      time.Hour,
@@ -2169,6 +2170,68 @@ var _ = Describe("Chain", func() {
 					Expect(err.Error()).To(ContainSubstring(string(duplicatedMetadata.Consenters[1].ClientTlsCert)))
 				})
 
+				It("adding learner to the cluster and promoting it once caught up", func() {
+					metadata := &raftprotos.ConfigMetadata{Options: options}
+					for _, consenter := range consenters {
+						metadata.Consenters = append(metadata.Consenters, consenter)
+					}
+					metadata.Consenters = append(metadata.Consenters, &raftprotos.Consenter{
+						Host:          "localhost",
+						Port:          7050,
+						ServerTlsCert: serverTLSCert(tlsCA),
+						ClientTlsCert: clientTLSCert(tlsCA),
+						Learner:       true,
+					})
+					configEnv := newConfigEnv(channelID, common.HeaderType_CONFIG, newConfigUpdateEnv(channelID, nil, updateRaftConfigValue(metadata)))
+					c1.cutter.CutNext = true
+
+					By("sending config transaction")
+					err := c1.Configure(configEnv, 0)
+					Expect(err).ToNot(HaveOccurred())
+
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteConfigBlockCallCount, defaultTimeout).Should(Equal(1))
+					})
+
+					_, raftmetabytes := c1.support.WriteConfigBlockArgsForCall(0)
+					raftmeta, err := etcdraft.ReadBlockMetadata(&common.Metadata{Value: raftmetabytes}, nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(raftmeta.LearnerIds).To(Equal([]uint64{4}))
+
+					Eventually(func() []uint64 {
+						return c1.Node.ApplyConfChange(raftpb.ConfChange{}).Learners
+					}, defaultTimeout).Should(Equal([]uint64{4}))
+
+					c4 := newChain(timeout, channelID, dataDir, 4, raftmeta, consenters)
+					c4.support.WriteBlock(c1.support.WriteBlockArgsForCall(0))
+					c4.support.WriteConfigBlock(c1.support.WriteConfigBlockArgsForCall(0))
+					c4.init()
+
+					network.addChain(c4)
+					c4.Start()
+
+					By("promoting the learner once it caught up with the leader")
+					Eventually(func() bool {
+						c1.clock.Increment(interval)
+						pr, exists := c1.Node.Status().Progress[4]
+						return exists && !pr.IsLearner
+					}, defaultTimeout).Should(BeTrue())
+
+					By("submitting new transaction once learner is promoted")
+					c1.cutter.CutNext = true
+					err = c1.Order(env, 0)
+					Expect(err).ToNot(HaveOccurred())
+
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteBlockCallCount, defaultTimeout).Should(Equal(2))
+					})
+
+					_, raftmetabytes = c1.support.WriteBlockArgsForCall(1)
+					raftmeta, err = etcdraft.ReadBlockMetadata(&common.Metadata{Value: raftmetabytes}, nil)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(raftmeta.LearnerIds).To(BeEmpty())
+				})
+
 				It("does not reconfigure raft cluster if it's a channel creation tx", func() {
 					configEnv := newConfigEnv("another-channel",
 						common.HeaderType_CONFIG,
@@ -3503,6 +3566,7 @@ func newChain(timeout time.Duration, channel string, dataDir string, id uint64,
 
 	support := &consensusmocks.FakeConsenterSupport{}
 	support.ChainIDReturns(channel)
+	support.ChannelConfigReturns(&mockconfig.Channel{})
 	support.SharedConfigReturns(&mockconfig.Orderer{BatchTimeoutVal: timeout})
 
 	cutter := mockblockcutter.NewReceiver()
@@ -8,21 +8,24 @@ package etcdraft
 
 import (
 	"bytes"
+	"os"
 	"path"
 	"reflect"
 	"time"
 
 	"code.cloudfoundry.org/clock"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/viperutil"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/follower"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/consensus"
-	"github.com/hyperledger/fabric/orderer/consensus/inactive"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
@@ -54,6 +57,27 @@ type Config struct {
 	WALDir            string // WAL data of <my-channel> is stored in WALDir/<my-channel>
 	SnapDir           string // Snapshots of <my-channel> are stored in SnapDir/<my-channel>
 	EvictionSuspicion string // Duration threshold that the node samples in order to suspect its eviction from the channel.
+	Encryption        EncryptionConfig
+}
+
+// EncryptionConfig contains the configuration of the encryption of the WAL
+// entries and snapshots at rest
+type EncryptionConfig struct {
+	Enabled      bool
+	Key          string   // Hex encoded SKI of the symmetric key encrypting the new data
+	PreviousKeys []string // Hex encoded SKIs of the keys only decrypting the data written before a key rotation
+}
+
+// atRestConfig returns the keys encrypting the WAL entries and snapshots,
+// nil if the encryption is not enabled
+func (c EncryptionConfig) atRestConfig() *atrest.Config {
+	if !c.Enabled {
+		return nil
+	}
+	return &atrest.Config{
+		Key:          c.Key,
+		PreviousKeys: c.PreviousKeys,
+	}
 }
 
 // Consenter implements etcdraft consenter
@@ -69,6 +93,7 @@ type Consenter struct {
 	OrdererConfig  localconfig.TopLevel
 	Cert           []byte
 	Metrics        *Metrics
+	Encryptor      *atrest.Encryptor
 }
 
 // TargetChannel extracts the channel from the given proto.Message.
@@ -94,10 +119,12 @@ func (c *Consenter) ReceiverByChain(channelID string) MessageReceiver {
 	if cs.Chain == nil {
 		c.Logger.Panicf("Programming error - Chain %s is nil although it exists in the mapping", channelID)
 	}
-	if etcdRaftChain, isEtcdRaftChain := cs.Chain.(*Chain); isEtcdRaftChain {
-		return etcdRaftChain
+	// The chains of other cluster types, such as BFT, share the communication
+	// of the etcdraft consenter, thus any chain receiving messages is looked up
+	if receiver, isReceiver := cs.Chain.(MessageReceiver); isReceiver {
+		return receiver
 	}
-	c.Logger.Warningf("Chain %s is of type %v and not etcdraft.Chain", channelID, reflect.TypeOf(cs.Chain))
+	c.Logger.Warningf("Chain %s is of type %v and does not receive cluster messages", channelID, reflect.TypeOf(cs.Chain))
 	return nil
 }
 
@@ -119,6 +146,19 @@ func (c *Consenter) detectSelfID(consenters map[uint64]*etcdraft.Consenter) (uin
 		if bytes.Equal(thisNodeCertAsDER, certAsDER) {
 			return nodeID, nil
 		}
+
+		// a node rotating its certificates may still use its previous
+		// certificate until it is restarted with the new one
+		if !inGracePeriod(cst.Rotation, time.Now()) {
+			continue
+		}
+		previousCertAsDER, err := pemToDER(cst.Rotation.ServerTlsCert, nodeID, "previous server", c.Logger)
+		if err != nil {
+			return 0, err
+		}
+		if bytes.Equal(thisNodeCertAsDER, previousCertAsDER) {
+			return nodeID, nil
+		}
 	}
 
 	c.Logger.Warning("Could not find", string(c.Cert), "among", serverCertificates)
@@ -159,10 +199,7 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 
 	id, err := c.detectSelfID(consenters)
 	if err != nil {
-		c.InactiveChainRegistry.TrackChain(support.ChainID(), support.Block(0), func() {
-			c.CreateChain(support.ChainID())
-		})
-		return &inactive.Chain{Err: errors.Errorf("channel %s is not serviced by me", support.ChainID())}, nil
+		return c.newFollower(support), nil
 	}
 
 	var evictionSuspicion time.Duration
@@ -204,6 +241,7 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		EvictionSuspicion: evictionSuspicion,
 		Cert:              c.Cert,
 		Metrics:           c.Metrics,
+		Encryptor:         c.Encryptor,
 	}
 
 	rpc := &cluster.RPC{
@@ -220,12 +258,48 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		rpc,
 		func() (BlockPuller, error) { return newBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster) },
 		func() {
-			c.InactiveChainRegistry.TrackChain(support.ChainID(), nil, func() { c.CreateChain(support.ChainID()) })
+			// the chain is re-created as a follower of the channel
+			c.CreateChain(support.ChainID())
 		},
 		nil,
 	)
 }
 
+// newFollower returns a chain which follows the channel while this node is not
+// one of its consenters, and switches to a Raft chain once it is added to them.
+func (c *Consenter) newFollower(support consensus.ConsenterSupport) *follower.Chain {
+	return follower.NewChain(
+		support,
+		follower.Options{
+			Logger:       c.Logger,
+			PullInterval: c.OrdererConfig.General.Cluster.ReplicationRetryTimeout,
+			IsConsenter:  ConsenterCertificate(c.Cert).IsConsenterOfChannel,
+			Join: func() {
+				c.CreateChain(support.ChainID())
+			},
+		},
+		func() (follower.BlockPuller, error) {
+			return newBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster)
+		},
+	)
+}
+
+// RemoveChain removes the WAL and the snapshots of the given channel. As a new
+// chain starts from its WAL when it exists, a channel which is joined again
+// would otherwise restore the Raft state it had before it was removed.
+func (c *Consenter) RemoveChain(channelID string) error {
+	for _, dir := range []string{
+		path.Join(c.EtcdRaftConfig.WALDir, channelID),
+		path.Join(c.EtcdRaftConfig.SnapDir, channelID),
+	} {
+		if err := os.RemoveAll(dir); err != nil {
+			return errors.Wrapf(err, "failed to remove %s", dir)
+		}
+	}
+	c.Logger.Infof("Removed the WAL and the snapshots of channel %s", channelID)
+	return nil
+}
+
 // ReadBlockMetadata attempts to read raft metadata from block metadata, if available.
 // otherwise, it reads raft metadata from config metadata supplied.
 func ReadBlockMetadata(blockMetadata *common.Metadata, configMetadata *etcdraft.ConfigMetadata) (*etcdraft.BlockMetadata, error) {
@@ -267,6 +341,11 @@ func New(
 		logger.Panicf("Failed to decode etcdraft configuration: %s", err)
 	}
 
+	encryptor, err := atrest.New(factory.GetDefault(), cfg.Encryption.atRestConfig())
+	if err != nil {
+		logger.Panicf("Failed to create the encryptor of the WAL and snapshots: %s", err)
+	}
+
 	consenter := &Consenter{
 		CreateChain:           r.CreateChain,
 		Cert:                  srvConf.SecOpts.Certificate,
@@ -277,6 +356,7 @@ func New(
 		Dialer:                clusterDialer,
 		Metrics:               NewMetrics(metricsProvider),
 		InactiveChainRegistry: icr,
+		Encryptor:             encryptor,
 	}
 	consenter.Dispatcher = &Dispatcher{
 		Logger:        logger,
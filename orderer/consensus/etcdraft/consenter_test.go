@@ -20,7 +20,9 @@ import (
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	clustermocks "github.com/hyperledger/fabric/orderer/common/cluster/mocks"
+	"github.com/hyperledger/fabric/orderer/common/follower"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
+	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft/mocks"
 	consensusmocks "github.com/hyperledger/fabric/orderer/consensus/mocks"
@@ -50,6 +52,7 @@ var _ = Describe("Consenter", func() {
 		certAsPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("cert bytes")})
 		chainGetter = &mocks.ChainGetter{}
 		support = &consensusmocks.FakeConsenterSupport{}
+		support.ChannelConfigReturns(&mockconfig.Channel{})
 		dataDir, err = ioutil.TempDir("", "snap-")
 		Expect(err).NotTo(HaveOccurred())
 		walDir = path.Join(dataDir, "wal-")
@@ -100,6 +103,7 @@ var _ = Describe("Consenter", func() {
 
 	When("the consenter is asked for a chain", func() {
 		chainInstance := &etcdraft.Chain{}
+		otherClusterChain := &receivingChain{MessageReceiver: &mocks.MessageReceiver{}}
 		cs := &multichannel.ChainSupport{
 			Chain: chainInstance,
 		}
@@ -110,6 +114,9 @@ var _ = Describe("Consenter", func() {
 			chainGetter.On("GetChain", "notraftchain").Return(&multichannel.ChainSupport{
 				Chain: &multichannel.ChainSupport{},
 			})
+			chainGetter.On("GetChain", "otherclusterchain").Return(&multichannel.ChainSupport{
+				Chain: otherClusterChain,
+			})
 		})
 		It("calls the chain getter and returns the reference when it is found", func() {
 			consenter := newConsenter(chainGetter)
@@ -133,6 +140,13 @@ var _ = Describe("Consenter", func() {
 			chain := consenter.ReceiverByChain("notraftchain")
 			Expect(chain).To(BeNil())
 		})
+		It("calls the chain getter and returns the reference of a chain of another cluster type", func() {
+			consenter := newConsenter(chainGetter)
+			Expect(consenter).NotTo(BeNil())
+
+			chain := consenter.ReceiverByChain("otherclusterchain")
+			Expect(chain).To(BeIdenticalTo(otherClusterChain))
+		})
 		It("calls the chain getter and panics when the chain has a bad internal state", func() {
 			consenter := newConsenter(chainGetter)
 			Expect(consenter).NotTo(BeNil())
@@ -185,7 +199,7 @@ var _ = Describe("Consenter", func() {
 		Expect(defaultSuspicionFallback).To(BeTrue())
 	})
 
-	It("fails to handle chain if no matching cert found", func() {
+	It("follows the chain if no matching cert found", func() {
 		m := &etcdraftproto.ConfigMetadata{
 			Consenters: []*etcdraftproto.Consenter{
 				{ServerTlsCert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("foo")})},
@@ -199,6 +213,7 @@ var _ = Describe("Consenter", func() {
 		}
 		metadata := utils.MarshalOrPanic(m)
 		support := &consensusmocks.FakeConsenterSupport{}
+		support.ChannelConfigReturns(&mockconfig.Channel{})
 		support.SharedConfigReturns(&mockconfig.Orderer{
 			ConsensusMetadataVal: metadata,
 			BatchSizeVal:         &orderer.BatchSize{PreferredMaxBytes: 2 * 1024 * 1024},
@@ -210,8 +225,29 @@ var _ = Describe("Consenter", func() {
 		chain, err := consenter.HandleChain(support, &common.Metadata{})
 		Expect(chain).To(Not(BeNil()))
 		Expect(err).To(Not(HaveOccurred()))
-		Expect(chain.Order(nil, 0).Error()).To(Equal("channel foo is not serviced by me"))
-		consenter.icr.AssertNumberOfCalls(testingInstance, "TrackChain", 1)
+		Expect(chain).To(BeAssignableToTypeOf(&follower.Chain{}))
+		Expect(chain.Order(nil, 0).Error()).To(Equal("orderer is a follower of channel foo, and is not a consenter of it"))
+		consenter.icr.AssertNumberOfCalls(testingInstance, "TrackChain", 0)
+	})
+
+	It("removes the WAL and the snapshots of a channel", func() {
+		for _, dir := range []string{walDir, snapDir} {
+			Expect(os.MkdirAll(path.Join(dir, "foo"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(path.Join(dir, "bar"), 0755)).To(Succeed())
+		}
+
+		consenter := newConsenter(chainGetter)
+		consenter.EtcdRaftConfig.WALDir = walDir
+		consenter.EtcdRaftConfig.SnapDir = snapDir
+
+		Expect(consenter.RemoveChain("foo")).To(Succeed())
+		for _, dir := range []string{walDir, snapDir} {
+			Expect(path.Join(dir, "foo")).NotTo(BeADirectory())
+			Expect(path.Join(dir, "bar")).To(BeADirectory())
+		}
+
+		// removing a channel without storage is a no-op
+		Expect(consenter.RemoveChain("foo")).To(Succeed())
 	})
 
 	It("fails to handle chain if etcdraft options have not been provided", func() {
@@ -296,3 +332,9 @@ func newConsenter(chainGetter *mocks.ChainGetter) *consenter {
 		icr:       icr,
 	}
 }
+
+// receivingChain is a chain of another cluster type receiving cluster messages
+type receivingChain struct {
+	consensus.Chain
+	*mocks.MessageReceiver
+}
@@ -118,6 +118,16 @@ func (n *node) run(campaign bool) {
 		case <-raftTicker.C():
 			n.Tick()
 
+			if atomic.LoadUint64(&n.chain.lastKnownLeader) == n.config.ID {
+				if id := n.caughtUpLearner(); id != raft.None {
+					// non-blocking, the leader checks the learners again on next tick
+					select {
+					case n.chain.promoteC <- id:
+					default:
+					}
+				}
+			}
+
 		case rd := <-n.Ready():
 			startStoring := n.clock.Now()
 			if err := n.storage.Store(rd.Entries, rd.HardState, rd.Snapshot); err != nil {
@@ -216,6 +226,10 @@ func (n *node) abdicateLeader(currentLead uint64) {
 				continue // skip self
 			}
 
+			if pr.IsLearner {
+				continue // learners cannot be elected
+			}
+
 			if pr.RecentActive && !pr.Paused {
 				transferee = id
 				break
@@ -249,6 +263,24 @@ func (n *node) abdicateLeader(currentLead uint64) {
 	n.logger.Infof("Leader has been transferred from %d to %d", currentLead, newLeader)
 }
 
+// caughtUpLearner returns the ID of a learner which has replicated all the
+// entries committed by the leader, or raft.None if there is no such learner.
+// It only reports learners when called on leader.
+func (n *node) caughtUpLearner() uint64 {
+	status := n.Status()
+	if status.RaftState != raft.StateLeader {
+		return raft.None
+	}
+
+	for id, pr := range status.Progress {
+		if pr.IsLearner && pr.Match >= status.Commit {
+			return id
+		}
+	}
+
+	return raft.None
+}
+
 func (n *node) logSendFailure(dest uint64, err error) {
 	if _, ok := n.unreachable[dest]; ok {
 		n.logger.Debugf("Failed to send StepRequest to %d, because: %s", dest, err)
@@ -14,6 +14,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/etcdserver/api/snap"
@@ -57,17 +58,25 @@ type RaftStorage struct {
 	wal  *wal.WAL
 	snap *snap.Snapshotter
 
+	// encrypts the data of the entries and snapshots persisted to disk,
+	// nil if they are persisted in plaintext
+	encryptor *atrest.Encryptor
+
 	// a queue that keeps track of indices of snapshots on disk
 	snapshotIndex []uint64
 }
 
 // CreateStorage attempts to create a storage to persist etcd/raft data.
 // If data presents in specified disk, they are loaded to reconstruct storage state.
+// The data of the entries and snapshots is encrypted on disk by the encryptor, if
+// any. Data persisted in plaintext, e.g. before the encryption was enabled, is
+// read as is.
 func CreateStorage(
 	lg *flogging.FabricLogger,
 	walDir string,
 	snapDir string,
 	ram MemoryStorage,
+	encryptor *atrest.Encryptor,
 ) (*RaftStorage, error) {
 
 	sn, err := createSnapshotter(lg, snapDir)
@@ -86,6 +95,9 @@ func CreateStorage(
 		// snapshot found
 		lg.Debugf("Loaded snapshot at Term %d and Index %d, Nodes: %+v",
 			snapshot.Metadata.Term, snapshot.Metadata.Index, snapshot.Metadata.ConfState.Nodes)
+		if snapshot.Data, err = encryptor.Decrypt(snapshot.Data); err != nil {
+			return nil, errors.Errorf("failed to decrypt snapshot: %s", err)
+		}
 	}
 
 	w, st, ents, err := createOrReadWAL(lg, walDir, snapshot)
@@ -93,6 +105,13 @@ func CreateStorage(
 		return nil, errors.Errorf("failed to create or read WAL: %s", err)
 	}
 
+	for i := range ents {
+		if ents[i].Data, err = encryptor.Decrypt(ents[i].Data); err != nil {
+			w.Close()
+			return nil, errors.Errorf("failed to decrypt WAL entry at Term %d and Index %d: %s", ents[i].Term, ents[i].Index, err)
+		}
+	}
+
 	if snapshot != nil {
 		lg.Debugf("Applying snapshot to raft MemoryStorage")
 		if err := ram.ApplySnapshot(*snapshot); err != nil {
@@ -114,6 +133,7 @@ func CreateStorage(
 		walDir:        walDir,
 		snapDir:       snapDir,
 		snapshotIndex: ListSnapshots(lg, snapDir),
+		encryptor:     encryptor,
 	}, nil
 }
 
@@ -242,7 +262,12 @@ func (rs *RaftStorage) Snapshot() raftpb.Snapshot {
 
 // Store persists etcd/raft data
 func (rs *RaftStorage) Store(entries []raftpb.Entry, hardstate raftpb.HardState, snapshot raftpb.Snapshot) error {
-	if err := rs.wal.Save(hardstate, entries); err != nil {
+	walEntries, err := rs.encryptEntries(entries)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.wal.Save(hardstate, walEntries); err != nil {
 		return err
 	}
 
@@ -283,6 +308,13 @@ func (rs *RaftStorage) saveSnap(snap raftpb.Snapshot) error {
 		return errors.Errorf("failed to save snapshot to WAL: %s", err)
 	}
 
+	data, err := rs.encryptor.Encrypt(snap.Data)
+	if err != nil {
+		return errors.Errorf("failed to encrypt snapshot: %s", err)
+	}
+	// the snapshot is passed by value, only the copy persisted to disk is encrypted
+	snap.Data = data
+
 	if err := rs.snap.SaveSnap(snap); err != nil {
 		return errors.Errorf("failed to save snapshot to disk: %s", err)
 	}
@@ -295,6 +327,31 @@ func (rs *RaftStorage) saveSnap(snap raftpb.Snapshot) error {
 	return nil
 }
 
+// encryptEntries returns copies of the entries with their data encrypted,
+// or the entries themselves when they are persisted in plaintext
+func (rs *RaftStorage) encryptEntries(entries []raftpb.Entry) ([]raftpb.Entry, error) {
+	if rs.encryptor.KeyID() == nil {
+		return entries, nil
+	}
+
+	encrypted := make([]raftpb.Entry, len(entries))
+	for i, ent := range entries {
+		encrypted[i] = ent
+		// empty entries, e.g. appended by a new leader, are left empty
+		if len(ent.Data) == 0 {
+			continue
+		}
+
+		data, err := rs.encryptor.Encrypt(ent.Data)
+		if err != nil {
+			return nil, errors.Errorf("failed to encrypt entry at Term %d and Index %d: %s", ent.Term, ent.Index, err)
+		}
+		encrypted[i].Data = data
+	}
+
+	return encrypted, nil
+}
+
 // TakeSnapshot takes a snapshot at index i from MemoryStorage, and persists it to wal and disk.
 func (rs *RaftStorage) TakeSnapshot(i uint64, cs raftpb.ConfState, data []byte) error {
 	rs.lg.Debugf("Creating snapshot at index %d from MemoryStorage", i)
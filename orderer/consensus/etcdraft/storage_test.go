@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package etcdraft
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path"
 	"path/filepath"
@@ -15,6 +18,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/atrest"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,7 +47,7 @@ func setup(t *testing.T) {
 	dataDir, err = ioutil.TempDir("", "etcdraft-")
 	assert.NoError(t, err)
 	walDir, snapDir = path.Join(dataDir, "wal"), path.Join(dataDir, "snapshot")
-	store, err = CreateStorage(logger, walDir, snapDir, ram)
+	store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 	assert.NoError(t, err)
 }
 
@@ -108,7 +114,7 @@ func TestOpenWAL(t *testing.T) {
 
 		// create new storage
 		ram = raft.NewMemoryStorage()
-		store, err = CreateStorage(logger, walDir, snapDir, ram)
+		store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 		require.NoError(t, err)
 		lastI, _ := store.ram.LastIndex()
 		assert.True(t, lastI > 0)     // we are still able to read some entries
@@ -177,7 +183,7 @@ func TestTakeSnapshot(t *testing.T) {
 			err = store.Close()
 			assert.NoError(t, err)
 			ram := raft.NewMemoryStorage()
-			store, err = CreateStorage(logger, walDir, snapDir, ram)
+			store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 			assert.NoError(t, err)
 
 			store.TakeSnapshot(uint64(7), raftpb.ConfState{Nodes: []uint64{1}}, make([]byte, 10))
@@ -233,7 +239,7 @@ func TestTakeSnapshot(t *testing.T) {
 			err = store.Close()
 			assert.NoError(t, err)
 			ram := raft.NewMemoryStorage()
-			store, err = CreateStorage(logger, walDir, snapDir, ram)
+			store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 			assert.NoError(t, err)
 
 			// Two snapshots at index 5, 7. And we keep one extra wal file prior to oldest snapshot.
@@ -309,7 +315,7 @@ func TestTakeSnapshot(t *testing.T) {
 			err = store.Close()
 			assert.NoError(t, err)
 			ram := raft.NewMemoryStorage()
-			store, err = CreateStorage(logger, walDir, snapDir, ram)
+			store, err = CreateStorage(logger, walDir, snapDir, ram, nil)
 			assert.NoError(t, err)
 
 			// Corrupted snapshot file should've been renamed
@@ -327,3 +333,99 @@ func TestTakeSnapshot(t *testing.T) {
 		})
 	})
 }
+
+func TestEncryptedStorage(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	k, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	encryptor, err := atrest.NewEncryptor(csp, k)
+	require.NoError(t, err)
+
+	// fileContains returns whether any of the files in dir contains data
+	fileContains := func(dir string, data []byte) bool {
+		files, err := fileutil.ReadDir(dir)
+		require.NoError(t, err)
+		for _, f := range files {
+			content, err := ioutil.ReadFile(filepath.Join(dir, f))
+			require.NoError(t, err)
+			if bytes.Contains(content, data) {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("Entries and snapshots are encrypted on disk", func(t *testing.T) {
+		setup(t)
+		defer clean(t)
+		err = store.Close()
+		require.NoError(t, err)
+
+		ram = raft.NewMemoryStorage()
+		store, err = CreateStorage(logger, walDir, snapDir, ram, encryptor)
+		require.NoError(t, err)
+
+		for i := 1; i <= 5; i++ {
+			err = store.Store(
+				[]raftpb.Entry{{Index: uint64(i), Data: []byte(fmt.Sprintf("plaintext entry %d", i))}},
+				raftpb.HardState{},
+				raftpb.Snapshot{},
+			)
+			require.NoError(t, err)
+		}
+		err = store.TakeSnapshot(uint64(3), raftpb.ConfState{Nodes: []uint64{1}}, []byte("plaintext snapshot"))
+		require.NoError(t, err)
+
+		// the entries in memory are left in plaintext
+		ents, err := store.ram.Entries(4, 6, math.MaxUint64)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("plaintext entry 4"), ents[0].Data)
+		assert.False(t, fileContains(walDir, []byte("plaintext entry")))
+		assert.False(t, fileContains(snapDir, []byte("plaintext snapshot")))
+
+		err = store.Close()
+		require.NoError(t, err)
+
+		// the data cannot be read without the key
+		_, err = CreateStorage(logger, walDir, snapDir, raft.NewMemoryStorage(), nil)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "value is encrypted but no at-rest encryption key is configured")
+
+		ram = raft.NewMemoryStorage()
+		store, err = CreateStorage(logger, walDir, snapDir, ram, encryptor)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("plaintext snapshot"), store.Snapshot().Data)
+		ents, err = store.ram.Entries(4, 6, math.MaxUint64)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("plaintext entry 4"), ents[0].Data)
+		assert.Equal(t, []byte("plaintext entry 5"), ents[1].Data)
+	})
+
+	t.Run("Data persisted in plaintext is read as is", func(t *testing.T) {
+		setup(t)
+		defer clean(t)
+
+		for i := 1; i <= 3; i++ {
+			err = store.Store(
+				[]raftpb.Entry{{Index: uint64(i), Data: []byte(fmt.Sprintf("plaintext entry %d", i))}},
+				raftpb.HardState{},
+				raftpb.Snapshot{},
+			)
+			require.NoError(t, err)
+		}
+		err = store.TakeSnapshot(uint64(1), raftpb.ConfState{Nodes: []uint64{1}}, []byte("plaintext snapshot"))
+		require.NoError(t, err)
+		err = store.Close()
+		require.NoError(t, err)
+
+		ram = raft.NewMemoryStorage()
+		store, err = CreateStorage(logger, walDir, snapDir, ram, encryptor)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("plaintext snapshot"), store.Snapshot().Data)
+		ents, err := store.ram.Entries(2, 4, math.MaxUint64)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("plaintext entry 2"), ents[0].Data)
+		assert.Equal(t, []byte("plaintext entry 3"), ents[1].Data)
+	})
+}
@@ -32,6 +32,12 @@ import (
 	"go.etcd.io/etcd/raft/raftpb"
 )
 
+// blockHashingAlgorithm returns the algorithm hashing the blocks of the
+// channel of support.
+func blockHashingAlgorithm(support consensus.ConsenterSupport) func([]byte) []byte {
+	return support.ChannelConfig().BlockHashingAlgorithm()
+}
+
 // MembershipChanges keeps information about membership
 // changes introduced during configuration update
 type MembershipChanges struct {
@@ -41,11 +47,18 @@ type MembershipChanges struct {
 	RemovedNodes     []*etcdraft.Consenter
 	ConfChange       *raftpb.ConfChange
 	RotatedNode      uint64
+	// RotatedNodes are the nodes whose TLS certificates are rotated with a grace
+	// period, and which keep their Raft IDs regardless of the other changes.
+	RotatedNodes []uint64
+	// CompletedRotationNodes are the nodes whose certificate rotation is cleared,
+	// their previous TLS certificates are no longer accepted.
+	CompletedRotationNodes []uint64
 }
 
 // Stringer implements fmt.Stringer interface
 func (mc *MembershipChanges) String() string {
-	return fmt.Sprintf("add %d node(s), remove %d node(s)", len(mc.AddedNodes), len(mc.RemovedNodes))
+	return fmt.Sprintf("add %d node(s), remove %d node(s), rotate certificates of %d node(s)",
+		len(mc.AddedNodes), len(mc.RemovedNodes), len(mc.RotatedNodes))
 }
 
 // Changed indicates whether these changes actually do anything
@@ -58,6 +71,18 @@ func (mc *MembershipChanges) Rotated() bool {
 	return len(mc.AddedNodes) == 1 && len(mc.RemovedNodes) == 1
 }
 
+// RotatedWithGracePeriod indicates whether the change rotates the TLS
+// certificates of nodes with a grace period
+func (mc *MembershipChanges) RotatedWithGracePeriod() bool {
+	return len(mc.RotatedNodes) > 0
+}
+
+// RotationCompleted indicates whether the change clears the certificate
+// rotation of nodes, which then only use their new TLS certificates
+func (mc *MembershipChanges) RotationCompleted() bool {
+	return len(mc.CompletedRotationNodes) > 0
+}
+
 // EndpointconfigFromFromSupport extracts TLS CA certificates and endpoints from the ConsenterSupport
 func EndpointconfigFromFromSupport(support consensus.ConsenterSupport) ([]cluster.EndpointCriteria, error) {
 	lastConfigBlock, err := lastConfigBlockFromSupport(support)
@@ -89,8 +114,9 @@ func newBlockPuller(support consensus.ConsenterSupport,
 	baseDialer *cluster.PredicateDialer,
 	clusterConfig localconfig.Cluster) (BlockPuller, error) {
 
+	hashingAlgorithm := blockHashingAlgorithm(support)
 	verifyBlockSequence := func(blocks []*common.Block, _ string) error {
-		return cluster.VerifyBlocks(blocks, support)
+		return cluster.VerifyBlocksWithHashingAlgorithm(blocks, support, hashingAlgorithm)
 	}
 
 	stdDialer := &cluster.StandardDialer{
@@ -178,16 +204,28 @@ func ComputeMembershipChanges(oldMetadata *etcdraft.BlockMetadata, oldConsenters
 		if nodeID, exists := currentConsentersSet[string(c.ClientTlsCert)]; exists {
 			result.NewBlockMetadata.ConsenterIds[i] = nodeID
 			result.NewConsenters[nodeID] = c
+			if oldConsenters[nodeID].Rotation != nil && c.Rotation == nil {
+				result.CompletedRotationNodes = append(result.CompletedRotationNodes, nodeID)
+			}
 			continue
 		}
+		// a consenter rotating its certificates with a grace period
+		// is identified by the certificate it replaces
+		if c.Rotation != nil {
+			if nodeID, exists := currentConsentersSet[string(c.Rotation.ClientTlsCert)]; exists {
+				result.NewBlockMetadata.ConsenterIds[i] = nodeID
+				result.NewConsenters[nodeID] = c
+				result.RotatedNodes = append(result.RotatedNodes, nodeID)
+				continue
+			}
+		}
 		addedNodeIndex = i
 		result.AddedNodes = append(result.AddedNodes, c)
 	}
 
 	var deletedNodeID uint64
-	newConsentersSet := ConsentersToMap(newConsenters)
 	for nodeID, c := range oldConsenters {
-		if _, exists := newConsentersSet[string(c.ClientTlsCert)]; !exists {
+		if _, exists := result.NewConsenters[nodeID]; !exists {
 			result.RemovedNodes = append(result.RemovedNodes, c)
 			deletedNodeID = nodeID
 		}
@@ -209,6 +247,11 @@ func ComputeMembershipChanges(oldMetadata *etcdraft.BlockMetadata, oldConsenters
 			NodeID: nodeID,
 			Type:   raftpb.ConfChangeAddNode,
 		}
+		if result.AddedNodes[0].Learner {
+			// the node does not count toward quorum till it catches up
+			result.ConfChange.Type = raftpb.ConfChangeAddLearnerNode
+			result.NewBlockMetadata.LearnerIds = append(result.NewBlockMetadata.LearnerIds, nodeID)
+		}
 	case len(result.AddedNodes) == 0 && len(result.RemovedNodes) == 1:
 		// removed node
 		nodeID := deletedNodeID
@@ -217,6 +260,7 @@ func ComputeMembershipChanges(oldMetadata *etcdraft.BlockMetadata, oldConsenters
 			NodeID: nodeID,
 		}
 		delete(result.NewConsenters, nodeID)
+		result.NewBlockMetadata.LearnerIds = RemoveNode(nodeID, result.NewBlockMetadata.LearnerIds)
 	case len(result.AddedNodes) == 0 && len(result.RemovedNodes) == 0:
 		// no change
 	default:
@@ -254,9 +298,54 @@ func MetadataHasDuplication(md *etcdraft.ConfigMetadata) error {
 		seen[serverKey] = struct{}{}
 		seen[clientKey] = struct{}{}
 	}
+
+	// the certificates replaced by a rotation must not belong to another
+	// consenter, but may be kept by the consenter rotating them
+	for _, consenter := range md.Consenters {
+		if consenter.Rotation == nil {
+			continue
+		}
+		own := map[string]struct{}{
+			string(consenter.ServerTlsCert): {},
+			string(consenter.ClientTlsCert): {},
+		}
+		for _, previousCert := range [][]byte{consenter.Rotation.ServerTlsCert, consenter.Rotation.ClientTlsCert} {
+			key := string(previousCert)
+			if _, isOwn := own[key]; isOwn {
+				continue
+			}
+			if _, duplicate := seen[key]; duplicate {
+				return errors.Errorf("duplicate consenter: previous cert of rotation: %s", key)
+			}
+			seen[key] = struct{}{}
+			own[key] = struct{}{}
+		}
+	}
 	return nil
 }
 
+// GracePeriodEnd returns the end of the grace period of the certificate rotation
+func GracePeriodEnd(rotation *etcdraft.CertRotation) (time.Time, error) {
+	end, err := time.Parse(time.RFC3339, rotation.GracePeriodEnd)
+	if err != nil {
+		return time.Time{}, errors.Errorf("failed to parse GracePeriodEnd (%s) of certificate rotation: %s", rotation.GracePeriodEnd, err)
+	}
+	return end, nil
+}
+
+// inGracePeriod returns whether the previous certificates of a consenter
+// rotating its certificates are accepted at the given time
+func inGracePeriod(rotation *etcdraft.CertRotation, now time.Time) bool {
+	if rotation == nil {
+		return false
+	}
+	end, err := GracePeriodEnd(rotation)
+	if err != nil {
+		return false
+	}
+	return now.Before(end)
+}
+
 // MetadataFromConfigValue reads and translates configuration updates from config value into raft metadata
 func MetadataFromConfigValue(configValue *common.ConfigValue) (*etcdraft.ConfigMetadata, error) {
 	consensusTypeValue := &orderer.ConsensusType{}
@@ -422,6 +511,18 @@ func CheckConfigMetadata(metadata *etcdraft.ConfigMetadata) error {
 		if err := validateCert(consenter.ClientTlsCert, "client"); err != nil {
 			return err
 		}
+		if consenter.Rotation == nil {
+			continue
+		}
+		if err := validateCert(consenter.Rotation.ServerTlsCert, "previous server"); err != nil {
+			return err
+		}
+		if err := validateCert(consenter.Rotation.ClientTlsCert, "previous client"); err != nil {
+			return err
+		}
+		if _, err := GracePeriodEnd(consenter.Rotation); err != nil {
+			return err
+		}
 	}
 
 	if err := MetadataHasDuplication(metadata); err != nil {
@@ -473,10 +574,17 @@ func (conCert ConsenterCertificate) IsConsenterOfChannel(configBlock *common.Blo
 		return err
 	}
 
+	now := time.Now()
 	for _, consenter := range m.Consenters {
 		if bytes.Equal(conCert, consenter.ServerTlsCert) || bytes.Equal(conCert, consenter.ClientTlsCert) {
 			return nil
 		}
+		if !inGracePeriod(consenter.Rotation, now) {
+			continue
+		}
+		if bytes.Equal(conCert, consenter.Rotation.ServerTlsCert) || bytes.Equal(conCert, consenter.Rotation.ClientTlsCert) {
+			return nil
+		}
 	}
 	return cluster.ErrNotInChannel
 }
@@ -492,25 +600,52 @@ func NodeExists(id uint64, nodes []uint64) bool {
 	return false
 }
 
+// RemoveNode returns the slice of node ids without the given id
+func RemoveNode(id uint64, nodes []uint64) []uint64 {
+	var result []uint64
+	for _, nodeID := range nodes {
+		if nodeID != id {
+			result = append(result, nodeID)
+		}
+	}
+	return result
+}
+
+// RemovePromotedLearners removes from the learners of the block metadata the nodes
+// which are voters in the Raft configuration state. The promotion of a learner is
+// not carried by a config block, but by a ConfChange persisted in the Raft WAL and
+// snapshots, so the metadata of the last block may still list promoted learners.
+func RemovePromotedLearners(blockMetadata *etcdraft.BlockMetadata, confState raftpb.ConfState) {
+	for _, nodeID := range confState.Nodes {
+		blockMetadata.LearnerIds = RemoveNode(nodeID, blockMetadata.LearnerIds)
+	}
+}
+
 // ConfChange computes Raft configuration changes based on current Raft
 // configuration state and consenters IDs stored in RaftMetadata.
 func ConfChange(blockMetadata *etcdraft.BlockMetadata, confState *raftpb.ConfState) *raftpb.ConfChange {
 	raftConfChange := &raftpb.ConfChange{}
 
+	// learners are members of the Raft cluster as well
+	members := append(append([]uint64{}, confState.Nodes...), confState.Learners...)
+
 	// need to compute conf changes to propose
-	if len(confState.Nodes) < len(blockMetadata.ConsenterIds) {
+	if len(members) < len(blockMetadata.ConsenterIds) {
 		// adding new node
 		raftConfChange.Type = raftpb.ConfChangeAddNode
 		for _, consenterID := range blockMetadata.ConsenterIds {
-			if NodeExists(consenterID, confState.Nodes) {
+			if NodeExists(consenterID, members) {
 				continue
 			}
 			raftConfChange.NodeID = consenterID
+			if NodeExists(consenterID, blockMetadata.LearnerIds) {
+				raftConfChange.Type = raftpb.ConfChangeAddLearnerNode
+			}
 		}
 	} else {
 		// removing node
 		raftConfChange.Type = raftpb.ConfChangeRemoveNode
-		for _, nodeID := range confState.Nodes {
+		for _, nodeID := range members {
 			if NodeExists(nodeID, blockMetadata.ConsenterIds) {
 				continue
 			}
@@ -18,6 +18,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/flogging"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/cluster/mocks"
@@ -60,6 +61,196 @@ func TestCheckConfigMetadata(t *testing.T) {
 
 }
 
+func TestComputeMembershipChangesLearner(t *testing.T) {
+	oldMetadata := &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2}, NextConsenterId: 3}
+	oldConsenters := map[uint64]*etcdraft.Consenter{
+		1: {Host: "node-1", ClientTlsCert: []byte("cert-1")},
+		2: {Host: "node-2", ClientTlsCert: []byte("cert-2")},
+	}
+	learner := &etcdraft.Consenter{Host: "node-3", ClientTlsCert: []byte("cert-3"), Learner: true}
+
+	changes, err := ComputeMembershipChanges(oldMetadata, oldConsenters, []*etcdraft.Consenter{oldConsenters[1], oldConsenters[2], learner})
+	assert.NoError(t, err)
+	assert.Equal(t, &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeAddLearnerNode}, changes.ConfChange)
+	assert.Equal(t, []uint64{1, 2, 3}, changes.NewBlockMetadata.ConsenterIds)
+	assert.Equal(t, []uint64{3}, changes.NewBlockMetadata.LearnerIds)
+	assert.Empty(t, oldMetadata.LearnerIds)
+
+	// the flag has no effect on consenters which are already members
+	member := proto.Clone(oldConsenters[2]).(*etcdraft.Consenter)
+	member.Learner = true
+	changes, err = ComputeMembershipChanges(oldMetadata, oldConsenters, []*etcdraft.Consenter{oldConsenters[1], member})
+	assert.NoError(t, err)
+	assert.False(t, changes.Changed())
+	assert.Empty(t, changes.NewBlockMetadata.LearnerIds)
+
+	// removing a learner drops it from the learners
+	oldMetadata = &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2, 3}, NextConsenterId: 4, LearnerIds: []uint64{3}}
+	oldConsenters[3] = learner
+	changes, err = ComputeMembershipChanges(oldMetadata, oldConsenters, []*etcdraft.Consenter{oldConsenters[1], oldConsenters[2]})
+	assert.NoError(t, err)
+	assert.Equal(t, &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeRemoveNode}, changes.ConfChange)
+	assert.Empty(t, changes.NewBlockMetadata.LearnerIds)
+}
+
+func TestComputeMembershipChangesCertRotation(t *testing.T) {
+	oldMetadata := &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2, 3}, NextConsenterId: 4}
+	oldConsenters := map[uint64]*etcdraft.Consenter{
+		1: {Host: "node-1", ClientTlsCert: []byte("cert-1"), ServerTlsCert: []byte("server-cert-1")},
+		2: {Host: "node-2", ClientTlsCert: []byte("cert-2"), ServerTlsCert: []byte("server-cert-2")},
+		3: {Host: "node-3", ClientTlsCert: []byte("cert-3"), ServerTlsCert: []byte("server-cert-3")},
+	}
+	rotated := func(id int) *etcdraft.Consenter {
+		old := oldConsenters[uint64(id)]
+		return &etcdraft.Consenter{
+			Host:          old.Host,
+			ClientTlsCert: []byte("new-" + string(old.ClientTlsCert)),
+			ServerTlsCert: []byte("new-" + string(old.ServerTlsCert)),
+			Rotation: &etcdraft.CertRotation{
+				ClientTlsCert:  old.ClientTlsCert,
+				ServerTlsCert:  old.ServerTlsCert,
+				GracePeriodEnd: "2030-01-01T00:00:00Z",
+			},
+		}
+	}
+
+	// the certificates of all the nodes are rotated at once
+	newConsenters := []*etcdraft.Consenter{rotated(1), rotated(2), rotated(3)}
+	changes, err := ComputeMembershipChanges(oldMetadata, oldConsenters, newConsenters)
+	assert.NoError(t, err)
+	assert.False(t, changes.Changed())
+	assert.False(t, changes.Rotated())
+	assert.True(t, changes.RotatedWithGracePeriod())
+	assert.Nil(t, changes.ConfChange)
+	assert.ElementsMatch(t, []uint64{1, 2, 3}, changes.RotatedNodes)
+	assert.Equal(t, []uint64{1, 2, 3}, changes.NewBlockMetadata.ConsenterIds)
+	assert.Equal(t, uint64(4), changes.NewBlockMetadata.NextConsenterId)
+	for i, c := range newConsenters {
+		assert.Equal(t, c, changes.NewConsenters[uint64(i+1)])
+	}
+
+	// the certificates of nodes are rotated while another node is added
+	added := &etcdraft.Consenter{Host: "node-4", ClientTlsCert: []byte("cert-4")}
+	changes, err = ComputeMembershipChanges(oldMetadata, oldConsenters, []*etcdraft.Consenter{rotated(1), rotated(2), oldConsenters[3], added})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []uint64{1, 2}, changes.RotatedNodes)
+	assert.Equal(t, &raftpb.ConfChange{NodeID: 4, Type: raftpb.ConfChangeAddNode}, changes.ConfChange)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, changes.NewBlockMetadata.ConsenterIds)
+
+	// a rotation of certificates which do not belong to a node adds a node
+	unknown := rotated(3)
+	unknown.Rotation.ClientTlsCert = []byte("cert-5")
+	changes, err = ComputeMembershipChanges(oldMetadata, oldConsenters, []*etcdraft.Consenter{oldConsenters[1], oldConsenters[2], oldConsenters[3], unknown})
+	assert.NoError(t, err)
+	assert.Empty(t, changes.RotatedNodes)
+	assert.Equal(t, []*etcdraft.Consenter{unknown}, changes.AddedNodes)
+
+	// the rotation kept in the consenters by later updates is not a change
+	rotatedConsenters := map[uint64]*etcdraft.Consenter{1: rotated(1), 2: rotated(2), 3: rotated(3)}
+	changes, err = ComputeMembershipChanges(oldMetadata, rotatedConsenters, newConsenters)
+	assert.NoError(t, err)
+	assert.False(t, changes.Changed())
+	assert.False(t, changes.RotatedWithGracePeriod())
+	assert.False(t, changes.RotationCompleted())
+
+	// the rotation is cleared once the nodes use their new certificates
+	completed := []*etcdraft.Consenter{rotated(1), rotated(2), rotated(3)}
+	completed[0].Rotation = nil
+	completed[2].Rotation = nil
+	changes, err = ComputeMembershipChanges(oldMetadata, rotatedConsenters, completed)
+	assert.NoError(t, err)
+	assert.False(t, changes.Changed())
+	assert.False(t, changes.RotatedWithGracePeriod())
+	assert.True(t, changes.RotationCompleted())
+	assert.ElementsMatch(t, []uint64{1, 3}, changes.CompletedRotationNodes)
+	assert.Equal(t, []uint64{1, 2, 3}, changes.NewBlockMetadata.ConsenterIds)
+}
+
+func TestMetadataHasDuplicationCertRotation(t *testing.T) {
+	metadata := &etcdraft.ConfigMetadata{
+		Consenters: []*etcdraft.Consenter{
+			{
+				ClientTlsCert: []byte("new-cert-1"),
+				ServerTlsCert: []byte("server-cert-1"),
+				// only the client certificate is rotated
+				Rotation: &etcdraft.CertRotation{ClientTlsCert: []byte("cert-1"), ServerTlsCert: []byte("server-cert-1")},
+			},
+			{ClientTlsCert: []byte("cert-2"), ServerTlsCert: []byte("server-cert-2")},
+		},
+	}
+	assert.NoError(t, MetadataHasDuplication(metadata))
+
+	metadata.Consenters[0].Rotation.ClientTlsCert = []byte("cert-2")
+	assert.EqualError(t, MetadataHasDuplication(metadata), "duplicate consenter: previous cert of rotation: cert-2")
+}
+
+func TestInGracePeriod(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.False(t, inGracePeriod(nil, now))
+	assert.True(t, inGracePeriod(&etcdraft.CertRotation{GracePeriodEnd: "2020-01-01T01:00:00Z"}, now))
+	assert.False(t, inGracePeriod(&etcdraft.CertRotation{GracePeriodEnd: "2019-12-31T23:00:00Z"}, now))
+	assert.False(t, inGracePeriod(&etcdraft.CertRotation{GracePeriodEnd: "tomorrow"}, now))
+
+	_, err := GracePeriodEnd(&etcdraft.CertRotation{GracePeriodEnd: "tomorrow"})
+	assert.EqualError(t, err, `failed to parse GracePeriodEnd (tomorrow) of certificate rotation: parsing time "tomorrow" as "2006-01-02T15:04:05Z07:00": cannot parse "tomorrow" as "2006"`)
+}
+
+func TestConfChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		metadata   *etcdraft.BlockMetadata
+		confState  *raftpb.ConfState
+		confChange *raftpb.ConfChange
+	}{
+		{
+			name:       "add node",
+			metadata:   &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2, 3}},
+			confState:  &raftpb.ConfState{Nodes: []uint64{1, 2}},
+			confChange: &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeAddNode},
+		},
+		{
+			name:       "add learner",
+			metadata:   &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2, 3}, LearnerIds: []uint64{3}},
+			confState:  &raftpb.ConfState{Nodes: []uint64{1, 2}},
+			confChange: &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeAddLearnerNode},
+		},
+		{
+			name:       "remove node",
+			metadata:   &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2}},
+			confState:  &raftpb.ConfState{Nodes: []uint64{1, 2, 3}},
+			confChange: &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeRemoveNode},
+		},
+		{
+			name:       "remove learner",
+			metadata:   &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2}},
+			confState:  &raftpb.ConfState{Nodes: []uint64{1, 2}, Learners: []uint64{3}},
+			confChange: &raftpb.ConfChange{NodeID: 3, Type: raftpb.ConfChangeRemoveNode},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.confChange, ConfChange(tc.metadata, tc.confState))
+		})
+	}
+}
+
+func TestRemovePromotedLearners(t *testing.T) {
+	metadata := &etcdraft.BlockMetadata{ConsenterIds: []uint64{1, 2, 3, 4}, LearnerIds: []uint64{3, 4}}
+
+	// learners whose ConfChange is not applied yet are kept
+	RemovePromotedLearners(metadata, raftpb.ConfState{Nodes: []uint64{1, 2}, Learners: []uint64{3}})
+	assert.Equal(t, []uint64{3, 4}, metadata.LearnerIds)
+
+	RemovePromotedLearners(metadata, raftpb.ConfState{Nodes: []uint64{1, 2, 3}, Learners: []uint64{4}})
+	assert.Equal(t, []uint64{4}, metadata.LearnerIds)
+
+	RemovePromotedLearners(metadata, raftpb.ConfState{Nodes: []uint64{1, 2, 3, 4}})
+	assert.Empty(t, metadata.LearnerIds)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, metadata.ConsenterIds)
+}
+
 func TestIsConsenterOfChannel(t *testing.T) {
 	certInsideConfigBlock, err := base64.StdEncoding.DecodeString("LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUNmekNDQWlhZ0F3SUJBZ0l" +
 		"SQUo4bjFLYTVzS1ZaTXRMTHJ1dldERDB3Q2dZSUtvWkl6ajBFQXdJd2JERUwKTUFrR0ExVUVCaE1DVlZNeEV6QVJCZ05WQkFnVENrTmhiR" +
@@ -241,6 +432,7 @@ func TestNewBlockPuller(t *testing.T) {
 			42: goodConfigBlock,
 			99: lastBlock,
 		},
+		ChannelConfigVal: &mockconfig.Channel{},
 	}
 
 	dialer := &cluster.PredicateDialer{
@@ -266,7 +458,8 @@ func TestNewBlockPuller(t *testing.T) {
 		{
 			name: "Unable to retrieve block",
 			cs: &multichannel.ConsenterSupport{
-				HeightVal: 100,
+				HeightVal:        100,
+				ChannelConfigVal: &mockconfig.Channel{},
 			},
 			certificate:   ca.CertBytes(),
 			expectedError: "unable to retrieve block [99]",
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package inactive
 
 import (
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -42,3 +43,8 @@ func (c *Chain) Start() {
 func (c *Chain) Halt() {
 
 }
+
+// StatusReport returns the ClusterRelation & Status
+func (c *Chain) StatusReport() (types.ClusterRelation, types.Status) {
+	return types.ClusterRelationConfigTracker, types.StatusInactive
+}
@@ -9,6 +9,7 @@ package inactive_test
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus/inactive"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -25,4 +26,8 @@ func TestInactiveChain(t *testing.T) {
 	assert.NotPanics(t, chain.Halt)
 	_, open := <-chain.Errored()
 	assert.False(t, open)
+
+	clusterRelation, status := chain.StatusReport()
+	assert.Equal(t, types.ClusterRelationConfigTracker, clusterRelation)
+	assert.Equal(t, types.StatusInactive, status)
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -120,6 +121,8 @@ type chainImpl struct {
 	startChan chan struct{}
 	// timer controls the batch timeout of cutting pending messages into block
 	timer <-chan time.Time
+	// deadline is the time the timer expires at
+	deadline time.Time
 
 	replicaIDs []int32
 }
@@ -618,17 +621,28 @@ func (chain *chainImpl) processRegular(regularMessage *ab.KafkaMessageRegular, r
 		batches, pending := chain.BlockCutter().Ordered(message)
 		logger.Debugf("[channel: %s] Ordering results: items in batch = %d, pending = %v", chain.ChainID(), len(batches), pending)
 
+		var timeout time.Duration
+		if pending {
+			timeout = blockcutter.PendingBatchTimeout(chain.SharedConfig(), chain.BlockCutter())
+		}
+
 		switch {
 		case chain.timer != nil && !pending:
 			// Timer is already running but there are no messages pending, stop the timer
 			chain.timer = nil
 		case chain.timer == nil && pending:
 			// Timer is not already running and there are messages pending, so start it
-			chain.timer = time.After(chain.SharedConfig().BatchTimeout())
-			logger.Debugf("[channel: %s] Just began %s batch timer", chain.ChainID(), chain.SharedConfig().BatchTimeout().String())
+			chain.timer = time.After(timeout)
+			chain.deadline = time.Now().Add(timeout)
+			logger.Debugf("[channel: %s] Just began %s batch timer", chain.ChainID(), timeout.String())
+		case chain.timer != nil && time.Now().Add(timeout).Before(chain.deadline):
+			// Timer is already running but the max pending age of the message expires sooner, so restart it
+			chain.timer = time.After(timeout)
+			chain.deadline = time.Now().Add(timeout)
+			logger.Debugf("[channel: %s] Shortened batch timer to %s", chain.ChainID(), timeout.String())
 		default:
 			// Do nothing when:
-			// 1. Timer is already running and there are messages pending
+			// 1. Timer is already running and expires before the max pending age of the message
 			// 2. Timer is not set and there are no messages pending
 		}
 
@@ -3747,6 +3747,10 @@ func (r *mockReceiver) Cut() []*cb.Envelope {
 	return args.Get(0).([]*cb.Envelope)
 }
 
+func (r *mockReceiver) MaxPendingAge() (time.Duration, bool) {
+	return 0, false
+}
+
 type mockConsenterSupport struct {
 	mock.Mock
 }
@@ -3819,6 +3823,11 @@ func (c *mockConsenterSupport) WriteConfigBlock(block *cb.Block, encodedMetadata
 	return
 }
 
+func (c *mockConsenterSupport) WriteSignedBlock(block *cb.Block, encodedMetadataValue []byte) {
+	c.Called(block, encodedMetadataValue)
+	return
+}
+
 func (c *mockConsenterSupport) Sequence() uint64 {
 	args := c.Called()
 	return args.Get(0).(uint64)
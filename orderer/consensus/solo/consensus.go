@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 )
@@ -101,6 +102,7 @@ func (ch *chain) Errored() <-chan struct{} {
 
 func (ch *chain) main() {
 	var timer <-chan time.Time
+	var deadline time.Time
 	var err error
 
 	for {
@@ -124,17 +126,28 @@ func (ch *chain) main() {
 					ch.support.WriteBlock(block, nil)
 				}
 
+				var timeout time.Duration
+				if pending {
+					timeout = blockcutter.PendingBatchTimeout(ch.support.SharedConfig(), ch.support.BlockCutter())
+				}
+
 				switch {
 				case timer != nil && !pending:
 					// Timer is already running but there are no messages pending, stop the timer
 					timer = nil
 				case timer == nil && pending:
 					// Timer is not already running and there are messages pending, so start it
-					timer = time.After(ch.support.SharedConfig().BatchTimeout())
-					logger.Debugf("Just began %s batch timer", ch.support.SharedConfig().BatchTimeout().String())
+					timer = time.After(timeout)
+					deadline = time.Now().Add(timeout)
+					logger.Debugf("Just began %s batch timer", timeout.String())
+				case timer != nil && time.Now().Add(timeout).Before(deadline):
+					// Timer is already running but the max pending age of the message expires sooner, so restart it
+					timer = time.After(timeout)
+					deadline = time.Now().Add(timeout)
+					logger.Debugf("Shortened batch timer to %s", timeout.String())
 				default:
 					// Do nothing when:
-					// 1. Timer is already running and there are messages pending
+					// 1. Timer is already running and expires before the max pending age of the message
 					// 2. Timer is not set and there are no messages pending
 				}
 
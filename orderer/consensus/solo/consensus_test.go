@@ -16,6 +16,7 @@ import (
 	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/common/blockcutter"
 	mockmultichannel "github.com/hyperledger/fabric/orderer/mocks/common/multichannel"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -155,6 +156,55 @@ func TestBatchTimer(t *testing.T) {
 	}
 }
 
+func TestBatchTimerShortenedByMaxPendingAge(t *testing.T) {
+	support := &mockmultichannel.ConsenterSupport{
+		Blocks:         make(chan *cb.Block),
+		BlockCutterVal: mockblockcutter.NewReceiver(),
+		SharedConfigVal: &mockconfig.Orderer{
+			BatchTimeoutVal: time.Hour,
+			BatchPriorityVal: &ab.BatchPriority{
+				Lanes: []*ab.PriorityLane{{Name: "urgent", HeaderTypes: []string{"ENDORSER_TRANSACTION"}, MaxPendingAge: "1ms"}},
+			},
+		},
+	}
+	defer close(support.BlockCutterVal.Block)
+	bs := newChain(support)
+	wg := goWithWait(bs.main)
+	defer bs.Halt()
+
+	syncQueueMessage(testMessage, bs, support.BlockCutterVal)
+	select {
+	case <-support.Blocks:
+		t.Fatalf("Created a batch before the batch timer expired")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	urgentMessage := &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+				Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+				ChannelId: "foo",
+			})},
+			Data: []byte("URGENT_MESSAGE"),
+		}),
+	}
+	support.BlockCutterVal.MaxPendingAgeVal = time.Millisecond
+	syncQueueMessage(urgentMessage, bs, support.BlockCutterVal)
+	select {
+	case block := <-support.Blocks:
+		assert.Len(t, block.Data.Data, 2)
+	case <-time.After(time.Second):
+		t.Fatalf("Expected a block to be cut because of the max pending age of the message but did not")
+	}
+
+	bs.Halt()
+	select {
+	case <-support.Blocks:
+		t.Fatalf("Expected no invocations of Append")
+	case <-wg.done:
+	}
+}
+
 func TestBatchTimerHaltOnFilledBatch(t *testing.T) {
 	batchTimeout, _ := time.ParseDuration("1h")
 	support := &mockmultichannel.ConsenterSupport{
@@ -8,6 +8,7 @@ package blockcutter
 
 import (
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -29,6 +30,9 @@ type Receiver struct {
 	// SkipAppendCurBatch causes Ordered to skip appending to curBatch
 	SkipAppendCurBatch bool
 
+	// MaxPendingAgeVal is the max pending age returned by MaxPendingAge, none if zero
+	MaxPendingAgeVal time.Duration
+
 	// Lock to serialize writes access to curBatch
 	mutex sync.Mutex
 
@@ -98,6 +102,13 @@ func (mbc *Receiver) Cut() []*cb.Envelope {
 	return res
 }
 
+// MaxPendingAge returns MaxPendingAgeVal, and false if it is zero
+func (mbc *Receiver) MaxPendingAge() (time.Duration, bool) {
+	mbc.mutex.Lock()
+	defer mbc.mutex.Unlock()
+	return mbc.MaxPendingAgeVal, mbc.MaxPendingAgeVal > 0
+}
+
 func (mbc *Receiver) CurBatch() []*cb.Envelope {
 	mbc.mutex.Lock()
 	defer mbc.mutex.Unlock()
@@ -116,6 +116,11 @@ func (mcs *ConsenterSupport) WriteConfigBlock(block *cb.Block, encodedMetadataVa
 	mcs.WriteBlock(block, encodedMetadataValue)
 }
 
+// WriteSignedBlock calls WriteBlock
+func (mcs *ConsenterSupport) WriteSignedBlock(block *cb.Block, encodedMetadataValue []byte) {
+	mcs.WriteBlock(block, encodedMetadataValue)
+}
+
 // ChainID returns the chain ID this specific consenter instance is associated with
 func (mcs *ConsenterSupport) ChainID() string {
 	return mcs.ChainIDVal
@@ -84,6 +84,7 @@ var (
 	connectionProfile     string
 	waitForEvent          bool
 	waitForEventTimeout   time.Duration
+	hashingAlgorithm      string
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -142,6 +143,8 @@ func resetFlags() {
 		fmt.Sprint("Whether to wait for the event from each peer's deliver filtered service signifying that the 'invoke' transaction has been committed successfully"))
 	flags.DurationVar(&waitForEventTimeout, "waitForEventTimeout", 30*time.Second,
 		fmt.Sprint("Time to wait for the event from each peer's deliver filtered service signifying that the 'invoke' transaction has been committed successfully"))
+	flags.StringVarP(&hashingAlgorithm, "hashingAlgorithm", "", "",
+		fmt.Sprint("The hashing algorithm of the channel, used to compute the transaction ID. Must be set to SM3 on channels hashing their blocks with SM3"))
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
@@ -460,6 +460,13 @@ func ChaincodeInvokeOrQuery(
 		return nil, errors.WithMessage(err, fmt.Sprintf("error creating proposal for %s", funcName))
 	}
 
+	if txID == "" {
+		txid, err = setProposalTxID(prop, txid)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error computing transaction ID for %s", funcName))
+		}
+	}
+
 	signedProp, err := putils.GetSignedProposal(prop, signer)
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("error creating signed proposal for %s", funcName))
@@ -730,3 +737,13 @@ func createDeliverEnvelope(channelID string, certificate tls.Certificate) *pcomm
 
 	return env
 }
+
+// setProposalTxID recomputes the transaction ID of the unsigned proposal prop
+// with the hashing algorithm selected by the hashingAlgorithm flag, if any.
+// It returns the transaction ID of the proposal, txid if it is left unchanged.
+func setProposalTxID(prop *pb.Proposal, txid string) (string, error) {
+	if hashingAlgorithm == "" {
+		return txid, nil
+	}
+	return putils.SetProposalTxIDWithHash(prop, util.BlockHashingFunction(hashingAlgorithm))
+}
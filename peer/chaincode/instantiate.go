@@ -47,6 +47,7 @@ func instantiateCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
+		"hashingAlgorithm",
 	}
 	attachFlags(chaincodeInstantiateCmd, flagList)
 
@@ -75,6 +76,11 @@ func instantiate(cmd *cobra.Command, cf *ChaincodeCmdFactory) (*protcommon.Envel
 		return nil, fmt.Errorf("error creating proposal  %s: %s", chainFuncName, err)
 	}
 
+	_, err = setProposalTxID(prop, "")
+	if err != nil {
+		return nil, fmt.Errorf("error computing transaction ID %s: %s", chainFuncName, err)
+	}
+
 	var signedProp *pb.SignedProposal
 	signedProp, err = utils.GetSignedProposal(prop, cf.Signer)
 	if err != nil {
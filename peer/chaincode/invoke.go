@@ -35,6 +35,7 @@ func invokeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"connectionProfile",
 		"waitForEvent",
 		"waitForEventTimeout",
+		"hashingAlgorithm",
 	}
 	attachFlags(chaincodeInvokeCmd, flagList)
 
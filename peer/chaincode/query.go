@@ -33,6 +33,7 @@ func queryCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"peerAddresses",
 		"tlsRootCertFiles",
 		"connectionProfile",
+		"hashingAlgorithm",
 	}
 	attachFlags(chaincodeQueryCmd, flagList)
 
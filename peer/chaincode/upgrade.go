@@ -46,6 +46,7 @@ func upgradeCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"tlsRootCertFiles",
 		"connectionProfile",
 		"collections-config",
+		"hashingAlgorithm",
 	}
 	attachFlags(chaincodeUpgradeCmd, flagList)
 
@@ -75,6 +76,11 @@ func upgrade(cmd *cobra.Command, cf *ChaincodeCmdFactory) (*protcommon.Envelope,
 	}
 	logger.Debugf("Get upgrade proposal for chaincode <%v>", spec.ChaincodeId)
 
+	_, err = setProposalTxID(prop, "")
+	if err != nil {
+		return nil, fmt.Errorf("error computing transaction ID %s: %s", chainFuncName, err)
+	}
+
 	var signedProp *pb.SignedProposal
 	signedProp, err = utils.GetSignedProposal(prop, cf.Signer)
 	if err != nil {
@@ -34,6 +34,7 @@ const (
 var (
 	// join related variables.
 	genesisBlockPath string
+	snapshotPath     string
 
 	// create related variables
 	channelID     string
@@ -43,6 +44,22 @@ var (
 
 	// fetch related variables
 	bestEffort bool
+
+	// migrate related variables
+	raftProfile            string
+	dryRun                 bool
+	rollback               bool
+	participationEndpoints []string
+	consenterEndpoints     []string
+	outputDir              string
+
+	// rotatecert related variables
+	currentServerCerts []string
+	newServerCerts     []string
+	newClientCerts     []string
+	gracePeriod        time.Duration
+	outputUpdate       string
+	completeRotation   bool
 )
 
 // Cmd returns the cobra command for Node
@@ -52,10 +69,13 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 	channelCmd.AddCommand(createCmd(cf))
 	channelCmd.AddCommand(fetchCmd(cf))
 	channelCmd.AddCommand(joinCmd(cf))
+	channelCmd.AddCommand(joinBySnapshotCmd(cf))
 	channelCmd.AddCommand(listCmd(cf))
 	channelCmd.AddCommand(updateCmd(cf))
 	channelCmd.AddCommand(signconfigtxCmd(cf))
 	channelCmd.AddCommand(getinfoCmd(cf))
+	channelCmd.AddCommand(migrateCmd(cf))
+	channelCmd.AddCommand(rotatecertCmd(cf))
 
 	return channelCmd
 }
@@ -76,11 +96,24 @@ func resetFlags() {
 	flags = &pflag.FlagSet{}
 
 	flags.StringVarP(&genesisBlockPath, "blockpath", "b", common.UndefinedParamValue, "Path to file containing genesis block")
+	flags.StringVarP(&snapshotPath, "snapshotpath", "", common.UndefinedParamValue, "Path on the peer to the directory holding the snapshot of the ledger of the channel")
 	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "In case of a newChain command, the channel ID to create. It must be all lower case, less than 250 characters long and match the regular expression: [a-z][a-z0-9.-]*")
 	flags.StringVarP(&channelTxFile, "file", "f", "", "Configuration transaction file generated by a tool such as configtxgen for submitting to orderer")
 	flags.StringVarP(&outputBlock, "outputBlock", "", common.UndefinedParamValue, `The path to write the genesis block for the channel. (default ./<channelID>.block)`)
 	flags.DurationVarP(&timeout, "timeout", "t", 10*time.Second, "Channel creation timeout")
 	flags.BoolVarP(&bestEffort, "bestEffort", "", false, "Whether fetch requests should ignore errors and return blocks on a best effort basis")
+	flags.StringVarP(&raftProfile, "profile", "", "", "The profile in configtx.yaml holding the etcdraft configuration the channels migrate to")
+	flags.BoolVarP(&dryRun, "dryRun", "", false, "Whether the migration should only log the config updates it would submit")
+	flags.BoolVarP(&rollback, "rollback", "", false, "Whether the channels which have not exited maintenance mode should be switched back to kafka")
+	flags.StringSliceVarP(&participationEndpoints, "participationEndpoint", "", nil, "The URLs of the channel participation API of the ordering nodes, e.g. https://orderer0.example.com:8443, checked for the chains of the channels to be switched")
+	flags.StringSliceVarP(&consenterEndpoints, "consenterParticipationEndpoint", "", nil, "The URLs of the channel participation API of the ordering nodes which are consenters of the etcdraft profile, among those of '--participationEndpoint', the other ordering nodes being checked to follow the channels (default all the ordering nodes)")
+	flags.StringVarP(&outputDir, "outputDir", "", "", "The directory to write the signed config updates of the migration step to, as <channelID>.tx, instead of submitting them")
+	flags.StringSliceVarP(&currentServerCerts, "currentServerCert", "", nil, "The files holding the current TLS server certificates of the consenters whose certificates are rotated")
+	flags.StringSliceVarP(&newServerCerts, "newServerCert", "", nil, "The files holding the new TLS server certificates of the consenters, in the order of their current certificates")
+	flags.StringSliceVarP(&newClientCerts, "newClientCert", "", nil, "The files holding the new TLS client certificates of the consenters, in the order of their current certificates (default the new server certificates)")
+	flags.DurationVarP(&gracePeriod, "gracePeriod", "", 24*time.Hour, "The duration during which the current certificates of the consenters are still accepted")
+	flags.StringVarP(&outputUpdate, "outputUpdate", "", "", "The file to write the signed config update to, instead of submitting it")
+	flags.BoolVarP(&completeRotation, "completeRotation", "", false, "Whether to clear the rotation of the consenters identified by their new server certificates, which then no longer accept their previous certificates")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
@@ -96,8 +129,8 @@ func attachFlags(cmd *cobra.Command, names []string) {
 
 var channelCmd = &cobra.Command{
 	Use:   "channel",
-	Short: "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
-	Long:  "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo.",
+	Short: "Operate a channel: create|fetch|join|joinbysnapshot|list|update|signconfigtx|getinfo|migrate|rotatecert.",
+	Long:  "Operate a channel: create|fetch|join|joinbysnapshot|list|update|signconfigtx|getinfo|migrate|rotatecert.",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		common.InitCmd(cmd, args)
 		common.SetOrdererEnv(cmd, args)
@@ -106,6 +139,8 @@ var channelCmd = &cobra.Command{
 
 type BroadcastClientFactory func() (common.BroadcastClient, error)
 
+type DeliverClientFactory func(channelID string, bestEffort bool) (deliverClientIntf, error)
+
 type deliverClientIntf interface {
 	GetSpecifiedBlock(num uint64) (*cb.Block, error)
 	GetOldestBlock() (*cb.Block, error)
@@ -120,6 +155,7 @@ type ChannelCmdFactory struct {
 	BroadcastClient  common.BroadcastClient
 	DeliverClient    deliverClientIntf
 	BroadcastFactory BroadcastClientFactory
+	DeliverFactory   DeliverClientFactory
 }
 
 // InitCmdFactory init the ChannelCmdFactory with clients to endorser and orderer according to params
@@ -169,6 +205,10 @@ func InitCmdFactory(isEndorserRequired, isPeerDeliverRequired, isOrdererRequired
 		if err != nil {
 			return nil, err
 		}
+		// for commands which operate on several channels
+		cf.DeliverFactory = func(channelID string, bestEffort bool) (deliverClientIntf, error) {
+			return common.NewDeliverClientForOrderer(channelID, bestEffort)
+		}
 	}
 
 	logger.Infof("Endorser and orderer connections initialized")
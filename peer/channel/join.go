@@ -91,6 +91,12 @@ func executeJoin(cf *ChannelCmdFactory) (err error) {
 		return err
 	}
 
+	return submitJoinProposal(cf, spec)
+}
+
+// submitJoinProposal sends the proposal to join a channel built from the
+// given spec to the peer
+func submitJoinProposal(cf *ChannelCmdFactory, spec *pb.ChaincodeSpec) (err error) {
 	// Build the ChaincodeInvocationSpec message
 	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
 
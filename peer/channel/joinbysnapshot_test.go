@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJoinBySnapshot(t *testing.T) {
+	defer resetFlags()
+
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Endorsement: &pb.Endorsement{},
+	}
+	mockCF := &ChannelCmdFactory{
+		EndorserClient:   common.GetMockEndorserClient(mockResponse, nil),
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+
+	cmd := joinBySnapshotCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"--snapshotpath", "/var/hyperledger/snapshots/completed/mychannel/100"})
+
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestJoinBySnapshotMissingPath(t *testing.T) {
+	defer resetFlags()
+
+	resetFlags()
+
+	cmd := joinBySnapshotCmd(nil)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{})
+
+	err := cmd.Execute()
+	assert.EqualError(t, err, "Must supply snapshot path")
+}
+
+func TestJoinBySnapshotBadProposalResponse(t *testing.T) {
+	defer resetFlags()
+
+	InitMSP()
+	resetFlags()
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 500, Message: "snapshot not found"},
+		Endorsement: &pb.Endorsement{},
+	}
+	mockCF := &ChannelCmdFactory{
+		EndorserClient:   common.GetMockEndorserClient(mockResponse, nil),
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+
+	cmd := joinBySnapshotCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"--snapshotpath", "/var/hyperledger/snapshots/completed/mychannel/100"})
+
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.IsType(t, ProposalFailedErr(err.Error()), err)
+	assert.Contains(t, err.Error(), "snapshot not found")
+}
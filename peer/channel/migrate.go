@@ -0,0 +1,686 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto"
+	localsigner "github.com/hyperledger/fabric/common/localmsp"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	configupdate "github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	kafkaConsensusType    = "kafka"
+	etcdraftConsensusType = "etcdraft"
+
+	// migrationPollInterval is the interval at which the migration polls
+	// the orderer for the config updates it submitted to be committed.
+	migrationPollInterval = time.Second
+)
+
+func migrateCmd(cf *ChannelCmdFactory) *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the channels of a kafka ordering service to etcdraft.",
+		Long: "Migrates all the channels of a kafka ordering service to etcdraft: puts them in maintenance mode, and once the " +
+			"ordering nodes are backed up, switches their consensus type to etcdraft with the consenters of the given configtx.yaml " +
+			"profile, and once the ordering nodes are restarted and the channel participation API of each of them reports the " +
+			"channels as ordered by etcdraft, exits maintenance mode, starting with the system channel. The command must be run " +
+			"again after the ordering nodes are backed up, and after they are restarted, and resumes the migration " +
+			"when run again after a failure. With '--outputDir', the config updates of each step are written there to collect " +
+			"further signatures instead of being submitted, and the command must be run again once they are submitted. With " +
+			"'--rollback', channels which have not exited maintenance mode are switched back to kafka instead. Requires '-o', " +
+			"'-c' with the system channel ID, '--participationEndpoint' for each ordering node unless it is a dry run, and " +
+			"'--profile' unless rolling back. When some ordering nodes are not consenters of the profile, " +
+			"'--consenterParticipationEndpoint' lists the endpoints of those which are, the others following the channels.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrate(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"profile",
+		"dryRun",
+		"rollback",
+		"timeout",
+		"participationEndpoint",
+		"consenterParticipationEndpoint",
+		"outputDir",
+	}
+	attachFlags(migrateCmd, flagList)
+
+	return migrateCmd
+}
+
+func migrate(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply the system channel ID")
+	}
+	if raftProfile == "" && !rollback {
+		return errors.New("Must supply the profile holding the etcdraft configuration")
+	}
+	if len(participationEndpoints) == 0 && !dryRun {
+		return errors.New("Must supply the channel participation API endpoint of each ordering node")
+	}
+	for _, endpoint := range consenterEndpoints {
+		if !containsEndpoint(participationEndpoints, endpoint) {
+			return errors.Errorf("Consenter participation endpoint %s is not among the participation endpoints", endpoint)
+		}
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var raftMetadata []byte
+	if !rollback {
+		profile := genesisconfig.Load(raftProfile)
+		if profile.Orderer == nil || profile.Orderer.OrdererType != etcdraftConsensusType {
+			return errors.Errorf("profile %s does not hold an etcdraft orderer configuration", raftProfile)
+		}
+		var err error
+		if raftMetadata, err = etcdraft.Marshal(profile.Orderer.EtcdRaft); err != nil {
+			return errors.WithMessage(err, "failed to marshal etcdraft metadata")
+		}
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserNotRequired, PeerDeliverNotRequired, OrdererRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	participationClient, err := newParticipationClient()
+	if err != nil {
+		return err
+	}
+
+	m := &migrator{
+		systemChannel:          channelID,
+		raftMetadata:           raftMetadata,
+		dryRun:                 dryRun,
+		outputDir:              outputDir,
+		timeout:                timeout,
+		pollInterval:           migrationPollInterval,
+		participationEndpoints: participationEndpoints,
+		consenterEndpoints:     consenterEndpoints,
+		channelInfo:            participationClient.channelInfo,
+		signer:                 localsigner.NewSigner(),
+		broadcastFactory:       cf.BroadcastFactory,
+		deliverFactory:         cf.DeliverFactory,
+	}
+	if rollback {
+		return m.rollback()
+	}
+	return m.migrate()
+}
+
+// migratingChannel is a channel along with its latest config
+type migratingChannel struct {
+	name          string
+	config        *cb.Config
+	consensusType *ab.ConsensusType
+}
+
+func (c *migratingChannel) is(consensusType string, state ab.ConsensusType_State) bool {
+	return c.consensusType.Type == consensusType && c.consensusType.State == state
+}
+
+// channelInfoFunc fetches the info of a channel from the channel participation API at the endpoint
+type channelInfoFunc func(endpoint, channel string) (types.ChannelInfo, error)
+
+// migrator drives the consensus-type migration of the channels of an ordering service.
+// It does not keep any state of its own, the step each channel is at is derived from
+// the ConsensusType of its latest config, so that the migration can be resumed.
+type migrator struct {
+	systemChannel          string
+	raftMetadata           []byte
+	dryRun                 bool
+	outputDir              string
+	timeout                time.Duration
+	pollInterval           time.Duration
+	participationEndpoints []string
+	consenterEndpoints     []string // all the participation endpoints are consenters when empty
+	channelInfo            channelInfoFunc
+	signer                 crypto.LocalSigner
+	broadcastFactory       BroadcastClientFactory
+	deliverFactory         DeliverClientFactory
+
+	written []string // channels whose config update was written to outputDir
+}
+
+// migrate migrates the channels from kafka to etcdraft. The system channel enters maintenance
+// mode first, so that no channel is created during the migration, and exits it first. The
+// migration stops once the channels enter maintenance mode, so that the ordering nodes are
+// backed up, and once their consensus type is switched, so that the ordering nodes are restarted.
+func (m *migrator) migrate() error {
+	systemChannel, err := m.channel(m.systemChannel)
+	if err != nil {
+		return err
+	}
+	channels, err := m.channels(systemChannel)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range channels {
+		switch c.consensusType.Type {
+		case kafkaConsensusType, etcdraftConsensusType:
+		default:
+			return errors.Errorf("channel %s has consensus type %s, only kafka channels can be migrated", c.name, c.consensusType.Type)
+		}
+	}
+
+	var entered bool
+	for _, c := range channels {
+		if !c.is(kafkaConsensusType, ab.ConsensusType_STATE_NORMAL) {
+			continue
+		}
+		if err := m.enterMaintenance(c); err != nil {
+			return err
+		}
+		// the other channels enter maintenance mode once the system channel did
+		if c.name == m.systemChannel && m.awaitingSubmission() {
+			return nil
+		}
+		entered = true
+	}
+	if m.awaitingSubmission() {
+		return nil
+	}
+	if entered {
+		logger.Infof("The channels entered maintenance mode, back up the ordering nodes and run the command again to switch their consensus type to %s", etcdraftConsensusType)
+		return nil
+	}
+
+	var switched bool
+	for _, c := range channels {
+		if !c.is(kafkaConsensusType, ab.ConsensusType_STATE_MAINTENANCE) {
+			continue
+		}
+		if err := m.update(c, etcdraftConsensusType, m.raftMetadata, ab.ConsensusType_STATE_MAINTENANCE); err != nil {
+			return err
+		}
+		switched = true
+	}
+	if m.awaitingSubmission() {
+		return nil
+	}
+	if switched {
+		logger.Infof("Switched the consensus type of the channels to %s, restart all the ordering nodes and run the command again to exit maintenance mode", etcdraftConsensusType)
+		return nil
+	}
+
+	return m.exitMaintenance(channels, etcdraftConsensusType)
+}
+
+// rollback switches the channels which have not exited maintenance mode back to kafka.
+func (m *migrator) rollback() error {
+	systemChannel, err := m.channel(m.systemChannel)
+	if err != nil {
+		return err
+	}
+	channels, err := m.channels(systemChannel)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range channels {
+		if c.is(etcdraftConsensusType, ab.ConsensusType_STATE_NORMAL) {
+			return errors.Errorf("channel %s already exited maintenance mode with consensus type %s, it cannot be rolled back", c.name, etcdraftConsensusType)
+		}
+	}
+
+	var switched bool
+	for _, c := range channels {
+		if !c.is(etcdraftConsensusType, ab.ConsensusType_STATE_MAINTENANCE) {
+			continue
+		}
+		if err := m.update(c, kafkaConsensusType, nil, ab.ConsensusType_STATE_MAINTENANCE); err != nil {
+			return err
+		}
+		switched = true
+	}
+	if m.awaitingSubmission() {
+		return nil
+	}
+	if switched {
+		logger.Infof("Switched the consensus type of the channels back to %s, restart all the ordering nodes and run the command again to exit maintenance mode", kafkaConsensusType)
+		return nil
+	}
+
+	return m.exitMaintenance(channels, kafkaConsensusType)
+}
+
+func (m *migrator) enterMaintenance(c *migratingChannel) error {
+	return m.update(c, c.consensusType.Type, c.consensusType.Metadata, ab.ConsensusType_STATE_MAINTENANCE)
+}
+
+// exitMaintenance makes the channels in maintenance mode with the given consensus type exit it,
+// once each of them is served by the ordering node again.
+func (m *migrator) exitMaintenance(channels []*migratingChannel, consensusType string) error {
+	for _, c := range channels {
+		if !c.is(consensusType, ab.ConsensusType_STATE_MAINTENANCE) {
+			continue
+		}
+		if err := m.waitServed(c); err != nil {
+			return err
+		}
+		if err := m.update(c, c.consensusType.Type, c.consensusType.Metadata, ab.ConsensusType_STATE_NORMAL); err != nil {
+			return err
+		}
+	}
+	if m.awaitingSubmission() {
+		return nil
+	}
+
+	logger.Infof("All the channels have consensus type %s and are out of maintenance mode", consensusType)
+	return nil
+}
+
+// awaitingSubmission returns whether config updates were written to outputDir, in which
+// case the migration stops until they are signed and submitted.
+func (m *migrator) awaitingSubmission() bool {
+	if len(m.written) == 0 {
+		return false
+	}
+	logger.Infof("Collect the signatures of the config updates of channels %v written to %s with 'peer channel signconfigtx', "+
+		"submit them with 'peer channel update', and run the command again", m.written, m.outputDir)
+	return true
+}
+
+// waitServed waits until the ordering node serves the channel, and the ordering nodes order
+// it with its consensus type. The orderer rejects deliver requests while the chain of the
+// channel is errored, and in particular while a raft chain has no leader, but a kafka chain
+// keeps serving the channel till the ordering node is restarted.
+func (m *migrator) waitServed(c *migratingChannel) error {
+	if m.dryRun {
+		logger.Infof("[dry run] Would check that channel %s is served by the ordering node", c.name)
+		return nil
+	}
+
+	deadline := time.Now().Add(m.timeout)
+	for {
+		err := m.served(c.name)
+		if err == nil {
+			err = m.switched(c)
+		}
+		if err == nil {
+			logger.Infof("Channel %s is served by the ordering node", c.name)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.WithMessagef(err, "channel %s is not served by the ordering node, check that the ordering nodes were restarted", c.name)
+		}
+		time.Sleep(m.pollInterval)
+	}
+}
+
+func (m *migrator) served(channel string) error {
+	deliverClient, err := m.deliverFactory(channel, false)
+	if err != nil {
+		return errors.WithMessagef(err, "error getting deliver client for channel %s", channel)
+	}
+	defer deliverClient.Close()
+
+	_, err = deliverClient.GetNewestBlock()
+	return err
+}
+
+// switched checks that the channel participation API of each ordering node reports
+// the chain of the channel as the one of its consensus type. The ordering nodes which
+// are not consenters of an etcdraft channel follow it.
+func (m *migrator) switched(c *migratingChannel) error {
+	for _, endpoint := range m.participationEndpoints {
+		expected := types.ClusterRelationNone
+		if c.consensusType.Type == etcdraftConsensusType {
+			expected = types.ClusterRelationMember
+			if len(m.consenterEndpoints) > 0 && !containsEndpoint(m.consenterEndpoints, endpoint) {
+				expected = types.ClusterRelationFollower
+			}
+		}
+
+		info, err := m.channelInfo(endpoint, c.name)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to fetch the info of channel %s from %s", c.name, endpoint)
+		}
+		if info.ClusterRelation != expected {
+			return errors.Errorf("ordering node %s has cluster relation %s to channel %s instead of %s, it still runs the chain of the previous consensus type",
+				endpoint, info.ClusterRelation, c.name, expected)
+		}
+	}
+	return nil
+}
+
+func containsEndpoint(endpoints []string, endpoint string) bool {
+	for _, e := range endpoints {
+		if e == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// update submits a config update changing the ConsensusType of the channel,
+// and waits for it to be committed.
+func (m *migrator) update(c *migratingChannel, consensusType string, metadata []byte, state ab.ConsensusType_State) error {
+	next := &ab.ConsensusType{
+		Type:     consensusType,
+		Metadata: metadata,
+		State:    state,
+	}
+
+	logger.Infof("Changing the consensus type of channel %s from %s in %s to %s in %s",
+		c.name, c.consensusType.Type, c.consensusType.State, next.Type, next.State)
+
+	updated := proto.Clone(c.config).(*cb.Config)
+	updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey].Value = utils.MarshalOrPanic(next)
+
+	if m.dryRun {
+		logger.Infof("[dry run] Would submit the config update to channel %s", c.name)
+		c.config, c.consensusType = updated, next
+		return nil
+	}
+
+	configUpdate, err := configupdate.Compute(c.config, updated)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to compute config update of channel %s", c.name)
+	}
+	configUpdate.ChannelId = c.name
+
+	env, err := signConfigUpdate(c.name, configUpdate, m.signer)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to sign config update of channel %s", c.name)
+	}
+
+	if m.outputDir != "" {
+		file := filepath.Join(m.outputDir, c.name+".tx")
+		if err := ioutil.WriteFile(file, utils.MarshalOrPanic(env), 0644); err != nil {
+			return errors.Wrapf(err, "failed to write config update to %s", file)
+		}
+		logger.Infof("Wrote the config update of channel %s to %s", c.name, file)
+		m.written = append(m.written, c.name)
+		return nil
+	}
+
+	broadcastClient, err := m.broadcastFactory()
+	if err != nil {
+		return errors.WithMessage(err, "error getting broadcast client")
+	}
+	defer broadcastClient.Close()
+	if err := broadcastClient.Send(env); err != nil {
+		return errors.WithMessagef(err, "failed to submit config update of channel %s", c.name)
+	}
+
+	return m.waitCommitted(c, next)
+}
+
+// waitCommitted waits until the latest config of the channel has the given ConsensusType.
+func (m *migrator) waitCommitted(c *migratingChannel, next *ab.ConsensusType) error {
+	deadline := time.Now().Add(m.timeout)
+	for {
+		config, err := m.config(c.name)
+		if err == nil {
+			consensusType, err := consensusTypeOf(config)
+			if err != nil {
+				return err
+			}
+			if proto.Equal(consensusType, next) {
+				c.config, c.consensusType = config, consensusType
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for the config update of channel %s to be committed", c.name)
+		}
+		time.Sleep(m.pollInterval)
+	}
+}
+
+// channels returns the system channel, followed by the channels created through it
+func (m *migrator) channels(systemChannel *migratingChannel) ([]*migratingChannel, error) {
+	deliverClient, err := m.deliverFactory(m.systemChannel, true)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting deliver client for the system channel")
+	}
+	defer deliverClient.Close()
+
+	newest, err := deliverClient.GetNewestBlock()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to fetch the newest block of the system channel")
+	}
+
+	channels := []*migratingChannel{systemChannel}
+	for seq := uint64(1); seq <= newest.Header.Number; seq++ {
+		block, err := deliverClient.GetSpecifiedBlock(seq)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to fetch block [%d] of the system channel", seq)
+		}
+		names, err := createdChannels(block)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse block [%d] of the system channel", seq)
+		}
+		for _, name := range names {
+			c, err := m.channel(name)
+			if err != nil {
+				return nil, err
+			}
+			channels = append(channels, c)
+		}
+	}
+
+	return channels, nil
+}
+
+// channel returns the channel along with its latest config
+func (m *migrator) channel(name string) (*migratingChannel, error) {
+	config, err := m.config(name)
+	if err != nil {
+		return nil, err
+	}
+	consensusType, err := consensusTypeOf(config)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid config of channel %s", name)
+	}
+	return &migratingChannel{name: name, config: config, consensusType: consensusType}, nil
+}
+
+// config fetches the latest config of the channel from the ordering node. The blocks are
+// fetched on a best effort basis, as chains do not serve blocks while they are restarted.
+func (m *migrator) config(channel string) (*cb.Config, error) {
+	return fetchConfig(m.deliverFactory, channel)
+}
+
+// fetchConfig fetches the latest config of the channel from the ordering node on a best effort basis
+func fetchConfig(deliverFactory DeliverClientFactory, channel string) (*cb.Config, error) {
+	deliverClient, err := deliverFactory(channel, true)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error getting deliver client for channel %s", channel)
+	}
+	defer deliverClient.Close()
+
+	newest, err := deliverClient.GetNewestBlock()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to fetch the newest block of channel %s", channel)
+	}
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(newest)
+	if err != nil {
+		return nil, err
+	}
+	configBlock, err := deliverClient.GetSpecifiedBlock(lastConfig)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to fetch the config block of channel %s", channel)
+	}
+
+	env, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return configEnv.Config, nil
+}
+
+// consensusTypeOf returns the ConsensusType of the config
+func consensusTypeOf(config *cb.Config) (*ab.ConsensusType, error) {
+	if config.ChannelGroup == nil {
+		return nil, errors.New("config has no channel group")
+	}
+	ordererGroup, exists := config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if !exists {
+		return nil, errors.New("config has no orderer group")
+	}
+	value, exists := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !exists {
+		return nil, errors.New("config has no consensus type")
+	}
+
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	return consensusType, nil
+}
+
+// createdChannels returns the channels the ORDERER_TRANSACTION messages of a system channel block create
+func createdChannels(block *cb.Block) ([]string, error) {
+	var channels []string
+	for _, data := range block.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := utils.ChannelHeader(env)
+		if err != nil {
+			return nil, err
+		}
+		if chdr.Type != int32(cb.HeaderType_ORDERER_TRANSACTION) {
+			continue
+		}
+
+		payload, err := utils.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return nil, err
+		}
+		configEnv, err := utils.UnmarshalEnvelope(payload.Data)
+		if err != nil {
+			return nil, err
+		}
+		configChdr, err := utils.ChannelHeader(configEnv)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, configChdr.ChannelId)
+	}
+	return channels, nil
+}
+
+// signConfigUpdate signs the config update and wraps it in an envelope signed by the signer
+func signConfigUpdate(channel string, configUpdate *cb.ConfigUpdate, signer crypto.LocalSigner) (*cb.Envelope, error) {
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: utils.MarshalOrPanic(configUpdate),
+	}
+
+	sigHeader, err := signer.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+	configSig := &cb.ConfigSignature{
+		SignatureHeader: utils.MarshalOrPanic(sigHeader),
+	}
+	configSig.Signature, err = signer.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdateEnv.ConfigUpdate))
+	if err != nil {
+		return nil, err
+	}
+	configUpdateEnv.Signatures = []*cb.ConfigSignature{configSig}
+
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channel, signer, configUpdateEnv, 0, 0)
+}
+
+// participationClient queries the channel participation API of the ordering nodes,
+// with the TLS settings of the orderer flags.
+type participationClient struct {
+	client *http.Client
+}
+
+func newParticipationClient() (*participationClient, error) {
+	tlsConfig := &tls.Config{}
+	if viper.GetString("orderer.tls.rootcert.file") != "" {
+		caPEM, err := ioutil.ReadFile(config.GetPath("orderer.tls.rootcert.file"))
+		if err != nil {
+			return nil, errors.WithMessage(err, "unable to load orderer.tls.rootcert.file")
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		tlsConfig.RootCAs.AppendCertsFromPEM(caPEM)
+	}
+	if viper.GetBool("orderer.tls.clientAuthRequired") {
+		cert, err := tls.LoadX509KeyPair(config.GetPath("orderer.tls.clientCert.file"), config.GetPath("orderer.tls.clientKey.file"))
+		if err != nil {
+			return nil, errors.WithMessage(err, "unable to load the orderer TLS client key pair")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &participationClient{
+		client: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   migrationPollInterval * 10,
+		},
+	}, nil
+}
+
+func (p *participationClient) channelInfo(endpoint, channel string) (types.ChannelInfo, error) {
+	resp, err := p.client.Get(strings.TrimSuffix(endpoint, "/") + channelparticipation.URLBaseV1Channels + "/" + channel)
+	if err != nil {
+		return types.ChannelInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := &types.ErrorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(errResp); err != nil || errResp.Error == "" {
+			return types.ChannelInfo{}, errors.Errorf("unexpected response status %s", resp.Status)
+		}
+		return types.ChannelInfo{}, errors.Errorf("%s: %s", resp.Status, errResp.Error)
+	}
+
+	info := types.ChannelInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return types.ChannelInfo{}, errors.Wrap(err, "failed to decode channel info")
+	}
+	return info, nil
+}
@@ -0,0 +1,539 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSystemChannel = "systemchannel"
+
+var testRaftMetadata = []byte("raft metadata")
+
+// fakeOrderingService holds the blocks of the channels, and commits
+// the consensus type changes broadcast to it right away
+type fakeOrderingService struct {
+	sync.Mutex
+	blocks      map[string][]*cb.Block
+	unavailable map[string]bool
+	chains      map[string]string // the consensus type of the chains run by the ordering nodes
+	followers   map[string]bool   // the endpoints of the ordering nodes which are not etcdraft consenters
+	updates     int
+}
+
+func newFakeOrderingService(channels ...string) *fakeOrderingService {
+	s := &fakeOrderingService{
+		blocks:      map[string][]*cb.Block{},
+		unavailable: map[string]bool{},
+		chains:      map[string]string{},
+		followers:   map[string]bool{},
+	}
+	s.appendConfigBlock(testSystemChannel, consensusTypeConfig(&ab.ConsensusType{Type: kafkaConsensusType}))
+	for _, channel := range channels {
+		s.appendConfigBlock(channel, consensusTypeConfig(&ab.ConsensusType{Type: kafkaConsensusType}))
+
+		// the channel is created through the system channel
+		s.appendBlock(testSystemChannel, &cb.Envelope{
+			Payload: utils.MarshalOrPanic(&cb.Payload{
+				Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ORDERER_TRANSACTION),
+					ChannelId: testSystemChannel,
+				})},
+				Data: s.blocks[channel][0].Data.Data[0],
+			}),
+		}, 0)
+	}
+	return s
+}
+
+func consensusTypeConfig(consensusType *ab.ConsensusType) *cb.Config {
+	return &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				channelconfig.OrdererGroupKey: {
+					Values: map[string]*cb.ConfigValue{
+						channelconfig.ConsensusTypeKey: {
+							Value:     utils.MarshalOrPanic(consensusType),
+							ModPolicy: "Admins",
+						},
+					},
+					ModPolicy: "Admins",
+				},
+			},
+		},
+	}
+}
+
+func (s *fakeOrderingService) appendBlock(channel string, env *cb.Envelope, lastConfig uint64) {
+	block := cb.NewBlock(uint64(len(s.blocks[channel])), nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&cb.LastConfig{Index: lastConfig}),
+	})
+	s.blocks[channel] = append(s.blocks[channel], block)
+}
+
+func (s *fakeOrderingService) appendConfigBlock(channel string, config *cb.Config) {
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, channel, nil, &cb.ConfigEnvelope{Config: config}, 0, 0)
+	if err != nil {
+		panic(err)
+	}
+	s.appendBlock(channel, env, uint64(len(s.blocks[channel])))
+}
+
+// lastConfig returns the config of the channel, read from the config block
+// the LAST_CONFIG metadata of the newest block points to
+func (s *fakeOrderingService) lastConfig(channel string) (*cb.Config, error) {
+	blocks := s.blocks[channel]
+	lastConfigIndex, err := utils.GetLastConfigIndexFromBlock(blocks[len(blocks)-1])
+	if err != nil {
+		return nil, err
+	}
+	env, err := utils.ExtractEnvelope(blocks[lastConfigIndex], 0)
+	if err != nil {
+		return nil, err
+	}
+	configEnv := &cb.ConfigEnvelope{}
+	if _, err := utils.UnmarshalEnvelopeOfType(env, cb.HeaderType_CONFIG, configEnv); err != nil {
+		return nil, err
+	}
+	return configEnv.Config, nil
+}
+
+func (s *fakeOrderingService) consensusType(t *testing.T, channel string) *ab.ConsensusType {
+	s.Lock()
+	defer s.Unlock()
+
+	config, err := s.lastConfig(channel)
+	require.NoError(t, err)
+	consensusType, err := consensusTypeOf(config)
+	require.NoError(t, err)
+	return consensusType
+}
+
+func (s *fakeOrderingService) Send(env *cb.Envelope) error {
+	s.Lock()
+	defer s.Unlock()
+
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	chdr, err := utils.UnmarshalEnvelopeOfType(env, cb.HeaderType_CONFIG_UPDATE, configUpdateEnv)
+	if err != nil {
+		return err
+	}
+	configUpdate := &cb.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdateEnv.ConfigUpdate, configUpdate); err != nil {
+		return err
+	}
+	if configUpdate.ChannelId != chdr.ChannelId || len(configUpdateEnv.Signatures) != 1 {
+		return errors.New("invalid config update")
+	}
+
+	lastConfig, err := s.lastConfig(chdr.ChannelId)
+	if err != nil {
+		return err
+	}
+
+	config := proto.Clone(lastConfig).(*cb.Config)
+	value := configUpdate.WriteSet.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey]
+	config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey] = value
+	s.appendConfigBlock(chdr.ChannelId, config)
+	s.updates++
+	return nil
+}
+
+func (s *fakeOrderingService) Close() error {
+	return nil
+}
+
+// restart makes the ordering nodes run the chains of the current consensus types of the channels
+func (s *fakeOrderingService) restart(t *testing.T) {
+	for channel := range s.blocks {
+		consensusType := s.consensusType(t, channel)
+		s.Lock()
+		s.chains[channel] = consensusType.Type
+		s.Unlock()
+	}
+}
+
+func (s *fakeOrderingService) channelInfo(endpoint, channel string) (types.ChannelInfo, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	info := types.ChannelInfo{Name: channel, ClusterRelation: types.ClusterRelationNone, Status: types.StatusActive}
+	if s.chains[channel] == etcdraftConsensusType {
+		info.ClusterRelation = types.ClusterRelationMember
+		if s.followers[endpoint] {
+			info.ClusterRelation = types.ClusterRelationFollower
+		}
+	}
+	return info, nil
+}
+
+type fakeDeliverClient struct {
+	service    *fakeOrderingService
+	channel    string
+	bestEffort bool
+}
+
+func (d *fakeDeliverClient) GetSpecifiedBlock(num uint64) (*cb.Block, error) {
+	d.service.Lock()
+	defer d.service.Unlock()
+
+	if d.service.unavailable[d.channel] && !d.bestEffort {
+		return nil, errors.New("can't read the block: SERVICE_UNAVAILABLE")
+	}
+	return d.service.blocks[d.channel][num], nil
+}
+
+func (d *fakeDeliverClient) GetOldestBlock() (*cb.Block, error) {
+	return d.GetSpecifiedBlock(0)
+}
+
+func (d *fakeDeliverClient) GetNewestBlock() (*cb.Block, error) {
+	d.service.Lock()
+	height := len(d.service.blocks[d.channel])
+	d.service.Unlock()
+	return d.GetSpecifiedBlock(uint64(height - 1))
+}
+
+func (d *fakeDeliverClient) Close() error {
+	return nil
+}
+
+func newMigrator(service *fakeOrderingService) *migrator {
+	return &migrator{
+		systemChannel: testSystemChannel,
+		raftMetadata:  testRaftMetadata,
+		timeout:       time.Second,
+		pollInterval:  10 * time.Millisecond,
+		signer:        mockcrypto.FakeLocalSigner,
+		broadcastFactory: func() (common.BroadcastClient, error) {
+			return service, nil
+		},
+		deliverFactory: func(channelID string, bestEffort bool) (deliverClientIntf, error) {
+			return &fakeDeliverClient{service: service, channel: channelID, bestEffort: bestEffort}, nil
+		},
+		participationEndpoints: []string{"https://orderer0:8443", "https://orderer1:8443"},
+		channelInfo:            service.channelInfo,
+	}
+}
+
+func assertConsensusType(t *testing.T, service *fakeOrderingService, consensusType *ab.ConsensusType) {
+	for _, channel := range []string{testSystemChannel, "channel1", "channel2"} {
+		assert.True(t, proto.Equal(consensusType, service.consensusType(t, channel)), "channel %s has consensus type %v", channel, service.consensusType(t, channel))
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	service := newFakeOrderingService("channel1", "channel2")
+	m := newMigrator(service)
+
+	// the migration stops once the channels enter maintenance mode,
+	// so that the ordering nodes are backed up
+	require.NoError(t, m.migrate())
+	assert.Equal(t, 3, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:  kafkaConsensusType,
+		State: ab.ConsensusType_STATE_MAINTENANCE,
+	})
+
+	// and once the consensus types are switched, so that the ordering nodes are restarted
+	require.NoError(t, m.migrate())
+	assert.Equal(t, 6, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_MAINTENANCE,
+	})
+
+	service.restart(t)
+	require.NoError(t, m.migrate())
+	assert.Equal(t, 9, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_NORMAL,
+	})
+
+	// the migration has completed
+	require.NoError(t, m.migrate())
+	assert.Equal(t, 9, service.updates)
+}
+
+func TestMigrateResumes(t *testing.T) {
+	service := newFakeOrderingService("channel1", "channel2")
+	m := newMigrator(service)
+
+	// the migration failed after the system channel and the first channel
+	// entered maintenance mode
+	for _, channel := range []string{testSystemChannel, "channel1"} {
+		service.appendConfigBlock(channel, consensusTypeConfig(&ab.ConsensusType{
+			Type:  kafkaConsensusType,
+			State: ab.ConsensusType_STATE_MAINTENANCE,
+		}))
+	}
+
+	require.NoError(t, m.migrate())
+	assert.Equal(t, 1, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:  kafkaConsensusType,
+		State: ab.ConsensusType_STATE_MAINTENANCE,
+	})
+
+	// the consensus type of the system channel was switched before the migration failed again
+	service.appendConfigBlock(testSystemChannel, consensusTypeConfig(&ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_MAINTENANCE,
+	}))
+
+	require.NoError(t, m.migrate())
+	assert.Equal(t, 3, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_MAINTENANCE,
+	})
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	service := newFakeOrderingService("channel1", "channel2")
+	m := newMigrator(service)
+	m.dryRun = true
+
+	require.NoError(t, m.migrate())
+	assert.Equal(t, 0, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{Type: kafkaConsensusType})
+}
+
+func TestMigrateChecksChannelsAreServed(t *testing.T) {
+	service := newFakeOrderingService("channel1", "channel2")
+	m := newMigrator(service)
+	require.NoError(t, m.migrate())
+	require.NoError(t, m.migrate())
+	service.restart(t)
+
+	// the raft chain of the channel has no leader after the restart
+	service.unavailable["channel2"] = true
+	m.timeout = 100 * time.Millisecond
+	err := m.migrate()
+	assert.EqualError(t, err, "channel channel2 is not served by the ordering node, check that the ordering nodes were restarted: can't read the block: SERVICE_UNAVAILABLE")
+	assert.Equal(t, ab.ConsensusType_STATE_NORMAL, service.consensusType(t, "channel1").State)
+	assert.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, service.consensusType(t, "channel2").State)
+	assert.Equal(t, ab.ConsensusType_STATE_NORMAL, service.consensusType(t, testSystemChannel).State)
+
+	delete(service.unavailable, "channel2")
+	require.NoError(t, m.migrate())
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_NORMAL,
+	})
+}
+
+func TestMigrateChecksChainsAreSwitched(t *testing.T) {
+	service := newFakeOrderingService("channel1", "channel2")
+	m := newMigrator(service)
+	require.NoError(t, m.migrate())
+	require.NoError(t, m.migrate())
+
+	// the kafka chains keep serving the channels till the ordering nodes are restarted
+	m.timeout = 100 * time.Millisecond
+	err := m.migrate()
+	assert.EqualError(t, err, "channel systemchannel is not served by the ordering node, check that the ordering nodes were restarted: "+
+		"ordering node https://orderer0:8443 has cluster relation none to channel systemchannel instead of member, it still runs the chain of the previous consensus type")
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_MAINTENANCE,
+	})
+
+	m.channelInfo = func(endpoint, channel string) (types.ChannelInfo, error) {
+		return types.ChannelInfo{}, errors.New("connection refused")
+	}
+	err = m.migrate()
+	assert.EqualError(t, err, "channel systemchannel is not served by the ordering node, check that the ordering nodes were restarted: "+
+		"failed to fetch the info of channel systemchannel from https://orderer0:8443: connection refused")
+}
+
+func TestMigrateWithFollowers(t *testing.T) {
+	service := newFakeOrderingService("channel1", "channel2")
+	service.followers["https://orderer2:8443"] = true
+	m := newMigrator(service)
+	m.participationEndpoints = append(m.participationEndpoints, "https://orderer2:8443")
+	require.NoError(t, m.migrate())
+	require.NoError(t, m.migrate())
+	service.restart(t)
+
+	// the ordering node which is not a consenter follows the channels
+	m.timeout = 100 * time.Millisecond
+	err := m.migrate()
+	assert.EqualError(t, err, "channel systemchannel is not served by the ordering node, check that the ordering nodes were restarted: "+
+		"ordering node https://orderer2:8443 has cluster relation follower to channel systemchannel instead of member, it still runs the chain of the previous consensus type")
+
+	m.consenterEndpoints = []string{"https://orderer0:8443", "https://orderer1:8443"}
+	require.NoError(t, m.migrate())
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_NORMAL,
+	})
+
+	// a consenter following the channels still runs the chain of the previous consensus type
+	service.followers["https://orderer1:8443"] = true
+	channel1, err := m.channel("channel1")
+	require.NoError(t, err)
+	assert.EqualError(t, m.switched(channel1), "ordering node https://orderer1:8443 has cluster relation follower to channel channel1 "+
+		"instead of member, it still runs the chain of the previous consensus type")
+}
+
+func TestMigrateOutputDir(t *testing.T) {
+	outputDir, err := ioutil.TempDir("", "migrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(outputDir)
+
+	service := newFakeOrderingService("channel1", "channel2")
+	m := newMigrator(service)
+	m.outputDir = outputDir
+
+	// submit submits the config updates written to the output directory, as once they are signed
+	submit := func(channels ...string) {
+		for _, channel := range channels {
+			file := filepath.Join(outputDir, channel+".tx")
+			envBytes, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+			require.NoError(t, service.Send(utils.UnmarshalEnvelopeOrPanic(envBytes)))
+			require.NoError(t, os.Remove(file))
+		}
+		m.written = nil
+	}
+
+	// the system channel enters maintenance mode first
+	require.NoError(t, m.migrate())
+	assert.Equal(t, []string{testSystemChannel}, m.written)
+	assert.Equal(t, 0, service.updates)
+	submit(testSystemChannel)
+
+	// once the config updates are submitted, the ordering nodes are backed up
+	require.NoError(t, m.migrate())
+	assert.Equal(t, []string{"channel1", "channel2"}, m.written)
+	submit("channel1", "channel2")
+
+	require.NoError(t, m.migrate())
+	assert.Equal(t, []string{testSystemChannel, "channel1", "channel2"}, m.written)
+	submit(testSystemChannel, "channel1", "channel2")
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_MAINTENANCE,
+	})
+
+	service.restart(t)
+	require.NoError(t, m.migrate())
+	assert.Equal(t, []string{testSystemChannel, "channel1", "channel2"}, m.written)
+	submit(testSystemChannel, "channel1", "channel2")
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: testRaftMetadata,
+		State:    ab.ConsensusType_STATE_NORMAL,
+	})
+	assert.Equal(t, 9, service.updates)
+}
+
+func TestMigrateRejectsOtherConsensusTypes(t *testing.T) {
+	service := newFakeOrderingService("channel1")
+	service.appendConfigBlock("channel1", consensusTypeConfig(&ab.ConsensusType{Type: "solo"}))
+	m := newMigrator(service)
+
+	// the system channel doesn't enter maintenance mode
+	err := m.migrate()
+	assert.EqualError(t, err, "channel channel1 has consensus type solo, only kafka channels can be migrated")
+	assert.Equal(t, 0, service.updates)
+	assert.Equal(t, ab.ConsensusType_STATE_NORMAL, service.consensusType(t, testSystemChannel).State)
+}
+
+func TestMigrateRollback(t *testing.T) {
+	service := newFakeOrderingService("channel1", "channel2")
+	m := newMigrator(service)
+	require.NoError(t, m.migrate())
+	require.NoError(t, m.migrate())
+	service.restart(t)
+	service.updates = 0
+
+	require.NoError(t, m.rollback())
+	assert.Equal(t, 3, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{
+		Type:  kafkaConsensusType,
+		State: ab.ConsensusType_STATE_MAINTENANCE,
+	})
+
+	service.restart(t)
+	require.NoError(t, m.rollback())
+	assert.Equal(t, 6, service.updates)
+	assertConsensusType(t, service, &ab.ConsensusType{Type: kafkaConsensusType})
+
+	t.Run("after the migration completed", func(t *testing.T) {
+		require.NoError(t, m.migrate())
+		require.NoError(t, m.migrate())
+		service.restart(t)
+		require.NoError(t, m.migrate())
+
+		err := m.rollback()
+		assert.EqualError(t, err, "channel systemchannel already exited maintenance mode with consensus type etcdraft, it cannot be rolled back")
+	})
+}
+
+func TestMigrateCmdMissingFlags(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	mockCF := &ChannelCmdFactory{
+		BroadcastFactory: mockBroadcastClientFactory,
+		DeliverClient:    &mockDeliverClient{},
+	}
+
+	cmd := migrateCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-o", "localhost:7050", "--profile", "SampleDevModeEtcdRaft"})
+	assert.EqualError(t, cmd.Execute(), "Must supply the system channel ID")
+
+	resetFlags()
+	cmd = migrateCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-o", "localhost:7050", "-c", testSystemChannel})
+	assert.EqualError(t, cmd.Execute(), "Must supply the profile holding the etcdraft configuration")
+
+	resetFlags()
+	cmd = migrateCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-o", "localhost:7050", "-c", testSystemChannel, "--rollback"})
+	assert.EqualError(t, cmd.Execute(), "Must supply the channel participation API endpoint of each ordering node")
+
+	resetFlags()
+	cmd = migrateCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-o", "localhost:7050", "-c", testSystemChannel, "--rollback",
+		"--participationEndpoint", "https://orderer0:8443", "--consenterParticipationEndpoint", "https://orderer1:8443"})
+	assert.EqualError(t, cmd.Execute(), "Consenter participation endpoint https://orderer1:8443 is not among the participation endpoints")
+}
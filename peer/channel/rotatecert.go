@@ -0,0 +1,300 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"encoding/pem"
+	"io/ioutil"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto"
+	localsigner "github.com/hyperledger/fabric/common/localmsp"
+	configupdate "github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func rotatecertCmd(cf *ChannelCmdFactory) *cobra.Command {
+	rotatecertCmd := &cobra.Command{
+		Use:   "rotatecert",
+		Short: "Rotate the TLS certificates of etcdraft consenters.",
+		Long: "Generates the config update rotating the TLS certificates of the etcdraft consenters identified by their " +
+			"current server certificates, and submits it to the channel, or writes it to '--outputUpdate' to collect further " +
+			"signatures. The current certificates keep being accepted by the consenters for '--gracePeriod', so that the " +
+			"certificates of all the consenters can be rotated by a single config update, and the ordering nodes restarted " +
+			"with their new certificates in any order. Requires '-o', '-c', '--currentServerCert' and '--newServerCert', " +
+			"repeated or comma separated for each consenter, and '--newClientCert' unless the new server certificates are also " +
+			"the new client certificates. Once the ordering nodes run with their new certificates, '--completeRotation' with " +
+			"their '--newServerCert' clears the rotation, and the current certificates are no longer accepted.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return rotatecert(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"currentServerCert",
+		"newServerCert",
+		"newClientCert",
+		"gracePeriod",
+		"outputUpdate",
+		"completeRotation",
+	}
+	attachFlags(rotatecertCmd, flagList)
+
+	return rotatecertCmd
+}
+
+func rotatecert(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if completeRotation {
+		return completeCertRotation(cmd, cf)
+	}
+	if len(currentServerCerts) == 0 || len(currentServerCerts) != len(newServerCerts) {
+		return errors.New("Must supply the current and the new server certificate of each consenter")
+	}
+	if len(newClientCerts) != 0 && len(newClientCerts) != len(newServerCerts) {
+		return errors.New("Must supply the new client certificate of each consenter")
+	}
+	if gracePeriod <= 0 {
+		return errors.New("Must supply a positive grace period")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var rotations []*certRotation
+	for i := range currentServerCerts {
+		rotation := &certRotation{}
+		var err error
+		if rotation.currentServerCert, err = readCertFile(currentServerCerts[i]); err != nil {
+			return err
+		}
+		if rotation.newServerCert, err = readCertFile(newServerCerts[i]); err != nil {
+			return err
+		}
+		rotation.newClientCert = rotation.newServerCert
+		if len(newClientCerts) != 0 {
+			if rotation.newClientCert, err = readCertFile(newClientCerts[i]); err != nil {
+				return err
+			}
+		}
+		rotations = append(rotations, rotation)
+	}
+
+	r, err := newCertRotatorFromFactory(cf)
+	if err != nil {
+		return err
+	}
+	return r.rotate(rotations, time.Now())
+}
+
+func completeCertRotation(cmd *cobra.Command, cf *ChannelCmdFactory) error {
+	if len(newServerCerts) == 0 {
+		return errors.New("Must supply the new server certificate of each consenter whose rotation is completed")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var certs [][]byte
+	for _, certFile := range newServerCerts {
+		cert, err := readCertFile(certFile)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+
+	r, err := newCertRotatorFromFactory(cf)
+	if err != nil {
+		return err
+	}
+	return r.complete(certs)
+}
+
+func newCertRotatorFromFactory(cf *ChannelCmdFactory) (*certRotator, error) {
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserNotRequired, PeerDeliverNotRequired, OrdererRequired)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &certRotator{
+		channel:          channelID,
+		gracePeriod:      gracePeriod,
+		outputFile:       outputUpdate,
+		signer:           localsigner.NewSigner(),
+		broadcastFactory: cf.BroadcastFactory,
+		deliverFactory:   cf.DeliverFactory,
+	}, nil
+}
+
+// certRotation is the rotation of the TLS certificates of a consenter,
+// identified by its current server certificate
+type certRotation struct {
+	currentServerCert []byte
+	newServerCert     []byte
+	newClientCert     []byte
+}
+
+// certRotator generates and submits the config updates rotating
+// the TLS certificates of the consenters of an etcdraft channel
+type certRotator struct {
+	channel          string
+	gracePeriod      time.Duration
+	outputFile       string
+	signer           crypto.LocalSigner
+	broadcastFactory BroadcastClientFactory
+	deliverFactory   DeliverClientFactory
+}
+
+// rotate rotates the TLS certificates of the consenters, with a grace period starting at now
+func (r *certRotator) rotate(rotations []*certRotation, now time.Time) error {
+	config, consensusType, metadata, err := r.fetchRaftMetadata()
+	if err != nil {
+		return err
+	}
+
+	gracePeriodEnd := now.Add(r.gracePeriod).UTC().Format(time.RFC3339)
+	for _, rotation := range rotations {
+		consenter, err := consenterByServerCert(metadata, rotation.currentServerCert)
+		if err != nil {
+			return errors.WithMessagef(err, "channel %s", r.channel)
+		}
+		logger.Infof("Rotating the TLS certificates of consenter %s:%d of channel %s, the current certificates are accepted until %s",
+			consenter.Host, consenter.Port, r.channel, gracePeriodEnd)
+		consenter.Rotation = &etcdraft.CertRotation{
+			ClientTlsCert:  consenter.ClientTlsCert,
+			ServerTlsCert:  consenter.ServerTlsCert,
+			GracePeriodEnd: gracePeriodEnd,
+		}
+		consenter.ServerTlsCert = rotation.newServerCert
+		consenter.ClientTlsCert = rotation.newClientCert
+	}
+
+	return r.update(config, consensusType, metadata)
+}
+
+// complete clears the rotation of the consenters with the given new PEM encoded server
+// certificates, which then no longer accept the certificates the rotation replaced
+func (r *certRotator) complete(newServerCerts [][]byte) error {
+	config, consensusType, metadata, err := r.fetchRaftMetadata()
+	if err != nil {
+		return err
+	}
+
+	for _, cert := range newServerCerts {
+		consenter, err := consenterByServerCert(metadata, cert)
+		if err != nil {
+			return errors.WithMessagef(err, "channel %s", r.channel)
+		}
+		if consenter.Rotation == nil {
+			return errors.Errorf("consenter %s:%d of channel %s is not rotating its certificates", consenter.Host, consenter.Port, r.channel)
+		}
+		logger.Infof("Completing the rotation of the TLS certificates of consenter %s:%d of channel %s", consenter.Host, consenter.Port, r.channel)
+		consenter.Rotation = nil
+	}
+
+	return r.update(config, consensusType, metadata)
+}
+
+// fetchRaftMetadata returns the config of the channel, along with its consensus type and etcdraft metadata
+func (r *certRotator) fetchRaftMetadata() (*cb.Config, *ab.ConsensusType, *etcdraft.ConfigMetadata, error) {
+	config, err := fetchConfig(r.deliverFactory, r.channel)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	consensusType, err := consensusTypeOf(config)
+	if err != nil {
+		return nil, nil, nil, errors.WithMessagef(err, "invalid config of channel %s", r.channel)
+	}
+	if consensusType.Type != etcdraftConsensusType {
+		return nil, nil, nil, errors.Errorf("channel %s has consensus type %s, only the certificates of etcdraft consenters can be rotated", r.channel, consensusType.Type)
+	}
+
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "failed to unmarshal etcdraft metadata of channel %s", r.channel)
+	}
+	return config, consensusType, metadata, nil
+}
+
+// update submits the config update setting the etcdraft metadata of the
+// channel, or writes it to the output file
+func (r *certRotator) update(config *cb.Config, consensusType *ab.ConsensusType, metadata *etcdraft.ConfigMetadata) error {
+	var err error
+	consensusType.Metadata, err = proto.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal etcdraft metadata")
+	}
+	updated := proto.Clone(config).(*cb.Config)
+	updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey].Value = utils.MarshalOrPanic(consensusType)
+
+	configUpdate, err := configupdate.Compute(config, updated)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to compute config update of channel %s", r.channel)
+	}
+	configUpdate.ChannelId = r.channel
+
+	env, err := signConfigUpdate(r.channel, configUpdate, r.signer)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to sign config update of channel %s", r.channel)
+	}
+
+	if r.outputFile != "" {
+		if err := ioutil.WriteFile(r.outputFile, utils.MarshalOrPanic(env), 0644); err != nil {
+			return errors.Wrapf(err, "failed to write config update to %s", r.outputFile)
+		}
+		logger.Infof("Wrote the config update of channel %s to %s", r.channel, r.outputFile)
+		return nil
+	}
+
+	broadcastClient, err := r.broadcastFactory()
+	if err != nil {
+		return errors.WithMessage(err, "error getting broadcast client")
+	}
+	defer broadcastClient.Close()
+	if err := broadcastClient.Send(env); err != nil {
+		return errors.WithMessagef(err, "failed to submit config update of channel %s", r.channel)
+	}
+
+	logger.Info("Successfully submitted channel update")
+	return nil
+}
+
+// consenterByServerCert returns the consenter of the metadata with the given PEM encoded server certificate
+func consenterByServerCert(metadata *etcdraft.ConfigMetadata, serverCert []byte) (*etcdraft.Consenter, error) {
+	der, _ := pem.Decode(serverCert)
+	for _, consenter := range metadata.Consenters {
+		if consenterDER, _ := pem.Decode(consenter.ServerTlsCert); consenterDER != nil && bytes.Equal(consenterDER.Bytes, der.Bytes) {
+			return consenter, nil
+		}
+	}
+	return nil, errors.New("no consenter has the given current server certificate")
+}
+
+// readCertFile reads a PEM encoded certificate from a file
+func readCertFile(path string) ([]byte, error) {
+	certBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read certificate file %s", path)
+	}
+	if bl, _ := pem.Decode(certBytes); bl == nil || bl.Type != "CERTIFICATE" {
+		return nil, errors.Errorf("certificate file %s is not PEM encoded", path)
+	}
+	return certBytes, nil
+}
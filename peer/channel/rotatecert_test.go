@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCert(t *testing.T, ca tlsgen.CA) []byte {
+	keyPair, err := ca.NewServerCertKeyPair("localhost")
+	require.NoError(t, err)
+	return keyPair.Cert
+}
+
+func newCertRotator(service *fakeOrderingService) *certRotator {
+	return &certRotator{
+		channel:     "channel1",
+		gracePeriod: time.Hour,
+		signer:      mockcrypto.FakeLocalSigner,
+		broadcastFactory: func() (common.BroadcastClient, error) {
+			return service, nil
+		},
+		deliverFactory: func(channelID string, bestEffort bool) (deliverClientIntf, error) {
+			return &fakeDeliverClient{service: service, channel: channelID, bestEffort: bestEffort}, nil
+		},
+	}
+}
+
+func raftMetadataOf(t *testing.T, service *fakeOrderingService, channel string) *etcdraft.ConfigMetadata {
+	metadata := &etcdraft.ConfigMetadata{}
+	require.NoError(t, proto.Unmarshal(service.consensusType(t, channel).Metadata, metadata))
+	return metadata
+}
+
+func TestRotateCert(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	consenters := []*etcdraft.Consenter{
+		{Host: "node1", Port: 7050, ServerTlsCert: newTestCert(t, ca), ClientTlsCert: newTestCert(t, ca)},
+		{Host: "node2", Port: 7050, ServerTlsCert: newTestCert(t, ca), ClientTlsCert: newTestCert(t, ca)},
+		{Host: "node3", Port: 7050, ServerTlsCert: newTestCert(t, ca), ClientTlsCert: newTestCert(t, ca)},
+	}
+	service := newFakeOrderingService()
+	service.appendConfigBlock("channel1", consensusTypeConfig(&ab.ConsensusType{
+		Type:     etcdraftConsensusType,
+		Metadata: utils.MarshalOrPanic(&etcdraft.ConfigMetadata{Consenters: consenters}),
+	}))
+
+	rotations := []*certRotation{
+		{currentServerCert: consenters[0].ServerTlsCert, newServerCert: newTestCert(t, ca), newClientCert: newTestCert(t, ca)},
+		{currentServerCert: consenters[2].ServerTlsCert, newServerCert: newTestCert(t, ca), newClientCert: newTestCert(t, ca)},
+	}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("output update", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "rotatecert")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		r := newCertRotator(service)
+		r.outputFile = filepath.Join(dir, "update.tx")
+		require.NoError(t, r.rotate(rotations, now))
+		assert.Equal(t, 0, service.updates)
+
+		envBytes, err := ioutil.ReadFile(r.outputFile)
+		require.NoError(t, err)
+		env, err := utils.UnmarshalEnvelope(envBytes)
+		require.NoError(t, err)
+		chdr, err := utils.UnmarshalEnvelopeOfType(env, cb.HeaderType_CONFIG_UPDATE, &cb.ConfigUpdateEnvelope{})
+		require.NoError(t, err)
+		assert.Equal(t, "channel1", chdr.ChannelId)
+	})
+
+	t.Run("submit update", func(t *testing.T) {
+		require.NoError(t, newCertRotator(service).rotate(rotations, now))
+		assert.Equal(t, 1, service.updates)
+
+		metadata := raftMetadataOf(t, service, "channel1")
+		require.Len(t, metadata.Consenters, 3)
+		for i, rotated := range []int{0, 2} {
+			consenter := metadata.Consenters[rotated]
+			assert.Equal(t, rotations[i].newServerCert, consenter.ServerTlsCert)
+			assert.Equal(t, rotations[i].newClientCert, consenter.ClientTlsCert)
+			assert.True(t, proto.Equal(&etcdraft.CertRotation{
+				ServerTlsCert:  consenters[rotated].ServerTlsCert,
+				ClientTlsCert:  consenters[rotated].ClientTlsCert,
+				GracePeriodEnd: "2020-01-01T01:00:00Z",
+			}, consenter.Rotation))
+		}
+		assert.True(t, proto.Equal(consenters[1], metadata.Consenters[1]))
+	})
+
+	t.Run("complete rotation", func(t *testing.T) {
+		require.NoError(t, newCertRotator(service).complete([][]byte{rotations[1].newServerCert}))
+		assert.Equal(t, 2, service.updates)
+
+		metadata := raftMetadataOf(t, service, "channel1")
+		assert.NotNil(t, metadata.Consenters[0].Rotation)
+		assert.Nil(t, metadata.Consenters[2].Rotation)
+		assert.Equal(t, rotations[1].newServerCert, metadata.Consenters[2].ServerTlsCert)
+
+		err := newCertRotator(service).complete([][]byte{rotations[1].newServerCert})
+		assert.EqualError(t, err, "consenter node3:7050 of channel channel1 is not rotating its certificates")
+	})
+
+	t.Run("unknown consenter", func(t *testing.T) {
+		err := newCertRotator(service).rotate([]*certRotation{{currentServerCert: newTestCert(t, ca)}}, now)
+		assert.EqualError(t, err, "channel channel1: no consenter has the given current server certificate")
+	})
+}
+
+func TestRotateCertRejectsOtherConsensusTypes(t *testing.T) {
+	service := newFakeOrderingService()
+	service.appendConfigBlock("channel1", consensusTypeConfig(&ab.ConsensusType{Type: kafkaConsensusType}))
+
+	err := newCertRotator(service).rotate(nil, time.Now())
+	assert.EqualError(t, err, "channel channel1 has consensus type kafka, only the certificates of etcdraft consenters can be rotated")
+}
+
+func TestRotateCertCmdMissingFlags(t *testing.T) {
+	InitMSP()
+
+	mockCF := &ChannelCmdFactory{
+		BroadcastFactory: mockBroadcastClientFactory,
+		DeliverClient:    &mockDeliverClient{},
+	}
+
+	for _, testCase := range []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"-o", "localhost:7050", "--currentServerCert", "old.pem", "--newServerCert", "new.pem"},
+			expectedError: "Must supply channel ID",
+		},
+		{
+			args:          []string{"-o", "localhost:7050", "-c", "channel1", "--currentServerCert", "old.pem"},
+			expectedError: "Must supply the current and the new server certificate of each consenter",
+		},
+		{
+			args:          []string{"-o", "localhost:7050", "-c", "channel1", "--currentServerCert", "old.pem", "--newServerCert", "new.pem", "--newClientCert", "a.pem,b.pem"},
+			expectedError: "Must supply the new client certificate of each consenter",
+		},
+		{
+			args:          []string{"-o", "localhost:7050", "-c", "channel1", "--currentServerCert", "old.pem", "--newServerCert", "new.pem", "--gracePeriod", "0s"},
+			expectedError: "Must supply a positive grace period",
+		},
+		{
+			args:          []string{"-o", "localhost:7050", "-c", "channel1", "--currentServerCert", "old.pem", "--newServerCert", "new.pem"},
+			expectedError: "failed to read certificate file old.pem: open old.pem: no such file or directory",
+		},
+		{
+			args:          []string{"-o", "localhost:7050", "-c", "channel1", "--completeRotation"},
+			expectedError: "Must supply the new server certificate of each consenter whose rotation is completed",
+		},
+		{
+			args:          []string{"-o", "localhost:7050", "-c", "channel1", "--completeRotation", "--newServerCert", "new.pem"},
+			expectedError: "failed to read certificate file new.pem: open new.pem: no such file or directory",
+		},
+	} {
+		resetFlags()
+		cmd := rotatecertCmd(mockCF)
+		AddFlags(cmd)
+		cmd.SetArgs(testCase.args)
+		assert.EqualError(t, cmd.Execute(), testCase.expectedError)
+	}
+}
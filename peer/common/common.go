@@ -148,11 +148,15 @@ func InitCrypto(mspMgrConfigDir, localMSPID, localMSPType string) error {
 	return nil
 }
 
-// SetBCCSPKeystorePath sets the file keystore path for the SW BCCSP provider
-// to an absolute path relative to the config file
+// SetBCCSPKeystorePath sets the file keystore path for the SW and GM BCCSP
+// providers to an absolute path relative to the config file
 func SetBCCSPKeystorePath() {
 	viper.Set("peer.BCCSP.SW.FileKeyStore.KeyStore",
 		config.GetPath("peer.BCCSP.SW.FileKeyStore.KeyStore"))
+	if viper.IsSet("peer.BCCSP.GM") {
+		viper.Set("peer.BCCSP.GM.FileKeyStore.KeyStore",
+			config.GetPath("peer.BCCSP.GM.FileKeyStore.KeyStore"))
+	}
 }
 
 // GetDefaultSigner return a default Signer(Default/PERR) for cli
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/peer/clilogging"
 	"github.com/hyperledger/fabric/peer/common"
 	"github.com/hyperledger/fabric/peer/node"
+	"github.com/hyperledger/fabric/peer/snapshot"
 	"github.com/hyperledger/fabric/peer/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -47,6 +48,7 @@ func main() {
 	mainCmd.AddCommand(chaincode.Cmd(nil))
 	mainCmd.AddCommand(clilogging.Cmd(nil))
 	mainCmd.AddCommand(channel.Cmd(nil))
+	mainCmd.AddCommand(snapshot.Cmd(nil))
 
 	// On failure Cobra prints the usage message and error string, so we only
 	// need to exit with a non-0 status
@@ -26,7 +26,7 @@ import (
 
 const (
 	nodeFuncName = "node"
-	nodeCmdDes   = "Operate a peer node: start|status|reset|rollback."
+	nodeCmdDes   = "Operate a peer node: start|status|reset|rollback|rebuild-dbs."
 )
 
 var logger = flogging.MustGetLogger("nodeCmd")
@@ -37,6 +37,7 @@ func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(statusCmd())
 	nodeCmd.AddCommand(resetCmd())
 	nodeCmd.AddCommand(rollbackCmd())
+	nodeCmd.AddCommand(rebuildDBsCmd())
 
 	return nodeCmd
 }
@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/customtx"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+)
+
+var dbType string
+
+func rebuildDBsCmd() *cobra.Command {
+	nodeRebuildDBsCmd.ResetFlags()
+	flags := nodeRebuildDBsCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose databases are rebuilt. The databases of all the channels are rebuilt if not specified.")
+	flags.StringVarP(&dbType, "dbType", "t", "", "Type of the databases to rebuild, either state or history. All the databases are rebuilt if not specified.")
+
+	return nodeRebuildDBsCmd
+}
+
+var nodeRebuildDBsCmd = &cobra.Command{
+	Use:   "rebuild-dbs",
+	Short: "Rebuilds the databases.",
+	Long:  `Drops the databases of all channels, or of a specified channel, and rebuilds them from the block store. When the command is executed, the peer must be offline. Either the state database or the history database can be rebuilt alone. The config history database, and the bookkeeping of the expiry of private data, are rebuilt along with the state database. The channels are rebuilt in parallel and the progress is logged. The CouchDB databases of a channel are dropped before its state database is rebuilt. The databases of a channel joined from a snapshot cannot be rebuilt.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ledgerID := channelID
+		if ledgerID == common.UndefinedParamValue {
+			ledgerID = ""
+		}
+		// config transactions write the channel config to the state
+		customtx.Initialize(peer.ConfigTxProcessors)
+		return kvledger.RebuildDBs(ledgerID, dbType, &ledger.Initializer{
+			DeployedChaincodeInfoProvider: &lscc.DeployedCCInfoProvider{},
+			MetricsProvider:               &disabled.Provider{},
+		})
+	},
+}
@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildDBsCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rebuilddbs")
+	require.NoError(t, err)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+	defer os.RemoveAll(testPath)
+
+	t.Run("when the specified channelID does not exist", func(t *testing.T) {
+		cmd := rebuildDBsCmd()
+		args := []string{"-c", "ch1"}
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		assert.Equal(t, "ledger [ch1] does not exist", err.Error())
+	})
+
+	t.Run("when the specified database type is unknown", func(t *testing.T) {
+		cmd := rebuildDBsCmd()
+		args := []string{"-c", "ch1", "-t", "blocks"}
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		assert.Equal(t, "unknown database type [blocks], the type must be either state or history", err.Error())
+	})
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func listPendingCmd(cf *SnapshotCmdFactory) *cobra.Command {
+	listPendingCmd := &cobra.Command{
+		Use:   "listpending",
+		Short: "List the pending requests to generate snapshots of the ledger of a channel.",
+		Long:  "List the block numbers of the pending requests to generate snapshots of the ledger of a channel. Requires '-c'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listPending(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+	}
+	attachFlags(listPendingCmd, flagList)
+
+	return listPendingCmd
+}
+
+func listPending(cmd *cobra.Command, args []string, cf *SnapshotCmdFactory) error {
+	if len(args) != 0 {
+		return errors.Errorf("trailing args detected: %s", args)
+	}
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory()
+		if err != nil {
+			return err
+		}
+	}
+
+	payload, err := cf.invokeCSCC([]byte(cscc.PendingSnapshotRequests), []byte(channelID))
+	if err != nil {
+		return err
+	}
+
+	pendingRequests := &pb.PendingSnapshotRequests{}
+	if err := proto.Unmarshal(payload, pendingRequests); err != nil {
+		return errors.Wrap(err, "cannot read the pending snapshot requests")
+	}
+
+	fmt.Printf("Pending snapshot requests of channel %s: %v\n", channelID, pendingRequests.BlockNumbers)
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	if err := msptesttools.LoadMSPSetupForTesting(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+func newMockCF(t *testing.T, response *pb.ProposalResponse, err error) *SnapshotCmdFactory {
+	signer, signerErr := common.GetDefaultSigner()
+	assert.NoError(t, signerErr)
+	return &SnapshotCmdFactory{
+		EndorserClient: common.GetMockEndorserClient(response, err),
+		Signer:         signer,
+	}
+}
+
+func TestSnapshotCmd(t *testing.T) {
+	psrBytes, err := proto.Marshal(&pb.PendingSnapshotRequests{BlockNumbers: []uint64{10, 20}})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		cmd         func(*SnapshotCmdFactory) *cobra.Command
+		args        []string
+		response    *pb.ProposalResponse
+		responseErr error
+		expectedErr string
+	}{
+		{
+			name:     "submitrequest",
+			cmd:      submitRequestCmd,
+			args:     []string{"-c", "mychannel", "-b", "10"},
+			response: &pb.ProposalResponse{Response: &pb.Response{Status: 200}},
+		},
+		{
+			name:     "submitrequest at the last committed block",
+			cmd:      submitRequestCmd,
+			args:     []string{"-c", "mychannel"},
+			response: &pb.ProposalResponse{Response: &pb.Response{Status: 200}},
+		},
+		{
+			name:        "submitrequest missing channel",
+			cmd:         submitRequestCmd,
+			args:        []string{"-b", "10"},
+			expectedErr: "Must supply channel ID",
+		},
+		{
+			name:        "submitrequest trailing args",
+			cmd:         submitRequestCmd,
+			args:        []string{"-c", "mychannel", "extra"},
+			expectedErr: "trailing args detected: [extra]",
+		},
+		{
+			name:        "submitrequest bad response",
+			cmd:         submitRequestCmd,
+			args:        []string{"-c", "mychannel", "-b", "10"},
+			response:    &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "duplicate snapshot request for block number 10"}},
+			expectedErr: "received bad response, status 500: duplicate snapshot request for block number 10",
+		},
+		{
+			name:        "submitrequest endorser error",
+			cmd:         submitRequestCmd,
+			args:        []string{"-c", "mychannel", "-b", "10"},
+			responseErr: errors.New("connection refused"),
+			expectedErr: "failed sending proposal: connection refused",
+		},
+		{
+			name:     "listpending",
+			cmd:      listPendingCmd,
+			args:     []string{"-c", "mychannel"},
+			response: &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: psrBytes}},
+		},
+		{
+			name:        "listpending missing channel",
+			cmd:         listPendingCmd,
+			args:        []string{},
+			expectedErr: "Must supply channel ID",
+		},
+		{
+			name:        "listpending bad payload",
+			cmd:         listPendingCmd,
+			args:        []string{"-c", "mychannel"},
+			response:    &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: []byte("garbage")}},
+			expectedErr: "cannot read the pending snapshot requests",
+		},
+		{
+			name:        "listpending nil response",
+			cmd:         listPendingCmd,
+			args:        []string{"-c", "mychannel"},
+			response:    &pb.ProposalResponse{},
+			expectedErr: "received nil response",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetFlags()
+			defer resetFlags()
+
+			cmd := tc.cmd(newMockCF(t, tc.response, tc.responseErr))
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
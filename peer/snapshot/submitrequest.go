@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func submitRequestCmd(cf *SnapshotCmdFactory) *cobra.Command {
+	submitRequestCmd := &cobra.Command{
+		Use:   "submitrequest",
+		Short: "Submit a request to generate a snapshot of the ledger of a channel.",
+		Long:  "Submit a request to generate a snapshot of the ledger of a channel at a block number. Requires '-c'. The snapshot is generated when the block is committed, or immediately when the block number is the last committed block or 0.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return submitRequest(cmd, args, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"blockNumber",
+	}
+	attachFlags(submitRequestCmd, flagList)
+
+	return submitRequestCmd
+}
+
+func submitRequest(cmd *cobra.Command, args []string, cf *SnapshotCmdFactory) error {
+	if len(args) != 0 {
+		return errors.Errorf("trailing args detected: %s", args)
+	}
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = cf.invokeCSCC(
+		[]byte(cscc.SubmitSnapshotRequest),
+		[]byte(channelID),
+		[]byte(strconv.FormatUint(blockNumber, 10)),
+	)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Snapshot request submitted successfully")
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+)
+
+// TypeKey is the string with which this consensus implementation is identified across Fabric.
+const TypeKey = "bft"
+
+func init() {
+	orderer.ConsensusTypeMetadataMap[TypeKey] = ConsensusTypeMetadataFactory{}
+}
+
+// ConsensusTypeMetadataFactory allows this implementation's proto messages to register
+// their type with the orderer's proto messages. This is needed for protolator to work.
+type ConsensusTypeMetadataFactory struct{}
+
+// NewMessage implements the Orderer.ConsensusTypeMetadataFactory interface.
+func (dogf ConsensusTypeMetadataFactory) NewMessage() proto.Message {
+	return &ConfigMetadata{}
+}
+
+// Marshal serializes this implementation's proto messages. It is called by the encoder package
+// during the creation of the Orderer ConfigGroup.
+func Marshal(md *ConfigMetadata) ([]byte, error) {
+	copyMd := proto.Clone(md).(*ConfigMetadata)
+	for _, c := range copyMd.Consenters {
+		// Expect the user to set the config value for the identity and the client/server
+		// certs to the path where they are persisted locally, then load these files to memory.
+		identity, err := ioutil.ReadFile(string(c.GetIdentity()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load identity for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.Identity = identity
+
+		clientCert, err := ioutil.ReadFile(string(c.GetClientTlsCert()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client cert for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.ClientTlsCert = clientCert
+
+		serverCert, err := ioutil.ReadFile(string(c.GetServerTlsCert()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load server cert for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.ServerTlsCert = serverCert
+	}
+	return proto.Marshal(copyMd)
+}
+
+// Quorum returns the number of the given consenters which need to agree on a block,
+// so that any two quorums intersect in at least one honest consenter when up to
+// f = (n-1)/3 of the n consenters are faulty.
+func Quorum(n int) int {
+	f := (n - 1) / 3
+	return (n + f + 2) / 2
+}
+
+// BlockValidationPolicy returns the signature policy requiring the signatures of a Quorum
+// of the given consenters. It is the BlockValidation policy of the orderer group of the
+// channels ordered by them, so that blocks are only accepted once the consenters agreed on them.
+func BlockValidationPolicy(consenters []*Consenter) (*common.SignaturePolicyEnvelope, error) {
+	identities := make([]*msp.MSPPrincipal, len(consenters))
+	signedBy := make([]*common.SignaturePolicy, len(consenters))
+	for i, c := range consenters {
+		identity, err := proto.Marshal(&msp.SerializedIdentity{Mspid: c.MspId, IdBytes: c.Identity})
+		if err != nil {
+			return nil, err
+		}
+		identities[i] = &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_IDENTITY, Principal: identity}
+		signedBy[i] = &common.SignaturePolicy{Type: &common.SignaturePolicy_SignedBy{SignedBy: int32(i)}}
+	}
+
+	return &common.SignaturePolicyEnvelope{
+		Rule: &common.SignaturePolicy{
+			Type: &common.SignaturePolicy_NOutOf_{
+				NOutOf: &common.SignaturePolicy_NOutOf{N: int32(Quorum(len(consenters))), Rules: signedBy},
+			},
+		},
+		Identities: identities,
+	}, nil
+}
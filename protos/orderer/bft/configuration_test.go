@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	md := &bft.ConfigMetadata{
+		Options: &bft.Options{
+			RequestTimeout:    "10s",
+			ViewChangeTimeout: "20s",
+		},
+	}
+	for i := 1; i <= 4; i++ {
+		md.Consenters = append(md.Consenters, &bft.Consenter{
+			ConsenterId:   uint64(i),
+			Host:          fmt.Sprintf("node-%d.example.com", i),
+			Port:          7050,
+			MspId:         "OrdererMSP",
+			Identity:      []byte(fmt.Sprintf("testdata/tls-server-%d.pem", i)),
+			ClientTlsCert: []byte(fmt.Sprintf("testdata/tls-client-%d.pem", i)),
+			ServerTlsCert: []byte(fmt.Sprintf("testdata/tls-server-%d.pem", i)),
+		})
+	}
+
+	packed, err := bft.Marshal(md)
+	require.Nil(t, err, "marshalling should succeed")
+
+	packed, err = bft.Marshal(md)
+	require.Nil(t, err, "marshalling should succeed a second time because we did not mutate ourselves")
+
+	unpacked := &bft.ConfigMetadata{}
+	require.Nil(t, proto.Unmarshal(packed, unpacked), "unmarshalling should succeed")
+
+	for i, c := range unpacked.GetConsenters() {
+		clientCert, _ := ioutil.ReadFile(fmt.Sprintf("testdata/tls-client-%d.pem", i+1))
+		serverCert, _ := ioutil.ReadFile(fmt.Sprintf("testdata/tls-server-%d.pem", i+1))
+		require.Equal(t, clientCert, c.GetClientTlsCert(), "expected extracted client cert to match input")
+		require.Equal(t, serverCert, c.GetServerTlsCert(), "expected extracted server cert to match input")
+		require.Equal(t, serverCert, c.GetIdentity(), "expected extracted identity to match input")
+		require.Equal(t, uint64(i+1), c.GetConsenterId())
+	}
+	require.True(t, proto.Equal(md.Options, unpacked.Options))
+
+	md.Consenters[0].Identity = []byte("testdata/missing.pem")
+	_, err = bft.Marshal(md)
+	require.EqualError(t, err, "cannot load identity for consenter node-1.example.com:7050: open testdata/missing.pem: no such file or directory")
+}
+
+func TestQuorum(t *testing.T) {
+	for _, tc := range []struct {
+		n, quorum int
+	}{
+		{1, 1},
+		{2, 2},
+		{3, 2},
+		{4, 3},
+		{5, 4},
+		{6, 4},
+		{7, 5},
+		{10, 7},
+	} {
+		require.Equal(t, tc.quorum, bft.Quorum(tc.n), "quorum of %d consenters", tc.n)
+	}
+}
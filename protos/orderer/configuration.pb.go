@@ -41,7 +41,7 @@ func (x ConsensusType_State) String() string {
 	return proto.EnumName(ConsensusType_State_name, int32(x))
 }
 func (ConsensusType_State) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{0, 0}
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{0, 0}
 }
 
 type ConsensusType struct {
@@ -60,7 +60,7 @@ func (m *ConsensusType) Reset()         { *m = ConsensusType{} }
 func (m *ConsensusType) String() string { return proto.CompactTextString(m) }
 func (*ConsensusType) ProtoMessage()    {}
 func (*ConsensusType) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{0}
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{0}
 }
 func (m *ConsensusType) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConsensusType.Unmarshal(m, b)
@@ -120,7 +120,7 @@ func (m *BatchSize) Reset()         { *m = BatchSize{} }
 func (m *BatchSize) String() string { return proto.CompactTextString(m) }
 func (*BatchSize) ProtoMessage()    {}
 func (*BatchSize) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{1}
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{1}
 }
 func (m *BatchSize) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchSize.Unmarshal(m, b)
@@ -174,7 +174,7 @@ func (m *BatchTimeout) Reset()         { *m = BatchTimeout{} }
 func (m *BatchTimeout) String() string { return proto.CompactTextString(m) }
 func (*BatchTimeout) ProtoMessage()    {}
 func (*BatchTimeout) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{2}
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{2}
 }
 func (m *BatchTimeout) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchTimeout.Unmarshal(m, b)
@@ -201,6 +201,117 @@ func (m *BatchTimeout) GetTimeout() string {
 	return ""
 }
 
+// BatchPriority defines the lanes ordering the messages of a batch, so that
+// latency-sensitive transactions are not held behind bulk submissions.
+type BatchPriority struct {
+	// The lanes in decreasing order of priority. Within a batch, messages are
+	// ordered by the first lane they match, and messages matching no lane come last.
+	Lanes                []*PriorityLane `protobuf:"bytes,1,rep,name=lanes,proto3" json:"lanes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *BatchPriority) Reset()         { *m = BatchPriority{} }
+func (m *BatchPriority) String() string { return proto.CompactTextString(m) }
+func (*BatchPriority) ProtoMessage()    {}
+func (*BatchPriority) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{3}
+}
+func (m *BatchPriority) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchPriority.Unmarshal(m, b)
+}
+func (m *BatchPriority) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchPriority.Marshal(b, m, deterministic)
+}
+func (dst *BatchPriority) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchPriority.Merge(dst, src)
+}
+func (m *BatchPriority) XXX_Size() int {
+	return xxx_messageInfo_BatchPriority.Size(m)
+}
+func (m *BatchPriority) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchPriority.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchPriority proto.InternalMessageInfo
+
+func (m *BatchPriority) GetLanes() []*PriorityLane {
+	if m != nil {
+		return m.Lanes
+	}
+	return nil
+}
+
+type PriorityLane struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The header types of the messages of the lane, e.g. ENDORSER_TRANSACTION.
+	// An empty list matches any header type.
+	HeaderTypes []string `protobuf:"bytes,2,rep,name=header_types,json=headerTypes,proto3" json:"header_types,omitempty"`
+	// The MSP IDs of the creators of the messages of the lane.
+	// An empty list matches any creator.
+	MspIds []string `protobuf:"bytes,3,rep,name=msp_ids,json=mspIds,proto3" json:"msp_ids,omitempty"`
+	// Any duration string parseable by ParseDuration(), the time after which a
+	// pending batch holding a message of the lane is cut, if it is shorter than
+	// the batch timeout.
+	MaxPendingAge        string   `protobuf:"bytes,4,opt,name=max_pending_age,json=maxPendingAge,proto3" json:"max_pending_age,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PriorityLane) Reset()         { *m = PriorityLane{} }
+func (m *PriorityLane) String() string { return proto.CompactTextString(m) }
+func (*PriorityLane) ProtoMessage()    {}
+func (*PriorityLane) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{4}
+}
+func (m *PriorityLane) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PriorityLane.Unmarshal(m, b)
+}
+func (m *PriorityLane) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PriorityLane.Marshal(b, m, deterministic)
+}
+func (dst *PriorityLane) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PriorityLane.Merge(dst, src)
+}
+func (m *PriorityLane) XXX_Size() int {
+	return xxx_messageInfo_PriorityLane.Size(m)
+}
+func (m *PriorityLane) XXX_DiscardUnknown() {
+	xxx_messageInfo_PriorityLane.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PriorityLane proto.InternalMessageInfo
+
+func (m *PriorityLane) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PriorityLane) GetHeaderTypes() []string {
+	if m != nil {
+		return m.HeaderTypes
+	}
+	return nil
+}
+
+func (m *PriorityLane) GetMspIds() []string {
+	if m != nil {
+		return m.MspIds
+	}
+	return nil
+}
+
+func (m *PriorityLane) GetMaxPendingAge() string {
+	if m != nil {
+		return m.MaxPendingAge
+	}
+	return ""
+}
+
 // Carries a list of bootstrap brokers, i.e. this is not the exclusive set of
 // brokers an ordering service
 type KafkaBrokers struct {
@@ -216,7 +327,7 @@ func (m *KafkaBrokers) Reset()         { *m = KafkaBrokers{} }
 func (m *KafkaBrokers) String() string { return proto.CompactTextString(m) }
 func (*KafkaBrokers) ProtoMessage()    {}
 func (*KafkaBrokers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{3}
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{5}
 }
 func (m *KafkaBrokers) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KafkaBrokers.Unmarshal(m, b)
@@ -255,7 +366,7 @@ func (m *ChannelRestrictions) Reset()         { *m = ChannelRestrictions{} }
 func (m *ChannelRestrictions) String() string { return proto.CompactTextString(m) }
 func (*ChannelRestrictions) ProtoMessage()    {}
 func (*ChannelRestrictions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_3a2420d24de6d468, []int{4}
+	return fileDescriptor_configuration_bcce68f21316dd30, []int{6}
 }
 func (m *ChannelRestrictions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelRestrictions.Unmarshal(m, b)
@@ -286,41 +397,49 @@ func init() {
 	proto.RegisterType((*ConsensusType)(nil), "orderer.ConsensusType")
 	proto.RegisterType((*BatchSize)(nil), "orderer.BatchSize")
 	proto.RegisterType((*BatchTimeout)(nil), "orderer.BatchTimeout")
+	proto.RegisterType((*BatchPriority)(nil), "orderer.BatchPriority")
+	proto.RegisterType((*PriorityLane)(nil), "orderer.PriorityLane")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
 	proto.RegisterType((*ChannelRestrictions)(nil), "orderer.ChannelRestrictions")
 	proto.RegisterEnum("orderer.ConsensusType_State", ConsensusType_State_name, ConsensusType_State_value)
 }
 
 func init() {
-	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_3a2420d24de6d468)
-}
-
-var fileDescriptor_configuration_3a2420d24de6d468 = []byte{
-	// 403 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xc1, 0x8a, 0xdb, 0x30,
-	0x10, 0x86, 0xeb, 0x66, 0xb7, 0xbb, 0x19, 0x92, 0x36, 0xd1, 0x52, 0x30, 0xdd, 0x1e, 0x82, 0xa1,
-	0x10, 0xca, 0x22, 0x97, 0xf4, 0x09, 0x92, 0x90, 0x43, 0x69, 0x93, 0x82, 0xe2, 0x5e, 0x7a, 0x09,
-	0x63, 0x67, 0xe2, 0x98, 0x8d, 0x2d, 0x23, 0xc9, 0x90, 0xf4, 0x3d, 0xfa, 0x08, 0x7d, 0xcf, 0x22,
-	0xc9, 0xde, 0x6e, 0x6f, 0xf3, 0xff, 0xf3, 0x69, 0x98, 0xd1, 0x0f, 0xf7, 0x52, 0xed, 0x49, 0x91,
-	0x8a, 0x33, 0x59, 0x1d, 0x8a, 0xbc, 0x51, 0x68, 0x0a, 0x59, 0xf1, 0x5a, 0x49, 0x23, 0xd9, 0x4d,
-	0xdb, 0x8c, 0xfe, 0x04, 0x30, 0x5c, 0xca, 0x4a, 0x53, 0xa5, 0x1b, 0x9d, 0x5c, 0x6a, 0x62, 0x0c,
-	0xae, 0xcc, 0xa5, 0xa6, 0x30, 0x98, 0x04, 0xd3, 0xbe, 0x70, 0x35, 0x7b, 0x07, 0xb7, 0x25, 0x19,
-	0xdc, 0xa3, 0xc1, 0xf0, 0xe5, 0x24, 0x98, 0x0e, 0xc4, 0x93, 0x66, 0x33, 0xb8, 0xd6, 0x06, 0x0d,
-	0x85, 0xbd, 0x49, 0x30, 0x7d, 0x3d, 0x7b, 0xcf, 0xdb, 0xd1, 0xfc, 0xbf, 0xb1, 0x7c, 0x6b, 0x19,
-	0xe1, 0xd1, 0xe8, 0x13, 0x5c, 0x3b, 0xcd, 0x46, 0x30, 0xd8, 0x26, 0xf3, 0x64, 0xb5, 0xdb, 0x7c,
-	0x17, 0xeb, 0xf9, 0xb7, 0xd1, 0x0b, 0xf6, 0x16, 0xc6, 0xde, 0x59, 0xcf, 0xbf, 0x6c, 0x92, 0xd5,
-	0x66, 0xbe, 0x59, 0xae, 0x46, 0x41, 0xf4, 0x3b, 0x80, 0xfe, 0x02, 0x4d, 0x76, 0xdc, 0x16, 0xbf,
-	0x88, 0x7d, 0x84, 0x71, 0x89, 0xe7, 0x5d, 0x49, 0x5a, 0x63, 0x4e, 0xbb, 0x4c, 0x36, 0x95, 0x71,
-	0x0b, 0x0f, 0xc5, 0x9b, 0x12, 0xcf, 0x6b, 0xef, 0x2f, 0xad, 0xcd, 0x1e, 0x80, 0x61, 0xaa, 0xe5,
-	0xa9, 0x31, 0xb4, 0xb3, 0x8f, 0xd2, 0x8b, 0x21, 0xed, 0xae, 0x18, 0x8a, 0x51, 0xd7, 0x59, 0xe3,
-	0x79, 0x61, 0x7d, 0xc6, 0xe1, 0xae, 0x56, 0x74, 0x20, 0xa5, 0x68, 0xff, 0x0c, 0xef, 0x39, 0x7c,
-	0xfc, 0xd4, 0xea, 0xf8, 0x68, 0x0a, 0x03, 0xb7, 0x56, 0x52, 0x94, 0x24, 0x1b, 0xc3, 0x42, 0xb8,
-	0x31, 0xbe, 0x6c, 0x3f, 0xb0, 0x93, 0x96, 0xfc, 0x8a, 0x87, 0x47, 0x5c, 0x28, 0xf9, 0x48, 0x4a,
-	0x5b, 0x32, 0xf5, 0x65, 0x18, 0x4c, 0x7a, 0x96, 0x6c, 0x65, 0x34, 0x83, 0xbb, 0xe5, 0x11, 0xab,
-	0x8a, 0x4e, 0x82, 0xb4, 0x51, 0x45, 0x66, 0x83, 0xd3, 0xec, 0x1e, 0xfa, 0x76, 0xa1, 0x7f, 0xc7,
-	0x5e, 0x89, 0xdb, 0x12, 0xcf, 0xee, 0xca, 0xc5, 0x0f, 0xf8, 0x20, 0x55, 0xce, 0x8f, 0x97, 0x9a,
-	0xd4, 0x89, 0xf6, 0x39, 0x29, 0x7e, 0xc0, 0x54, 0x15, 0x99, 0x0f, 0x5c, 0x77, 0xa9, 0xfc, 0x7c,
-	0xc8, 0x0b, 0x73, 0x6c, 0x52, 0x9e, 0xc9, 0x32, 0x7e, 0x46, 0xc7, 0x9e, 0x8e, 0x3d, 0x1d, 0xb7,
-	0x74, 0xfa, 0xca, 0xe9, 0xcf, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x90, 0x7c, 0x05, 0xd3, 0x4d,
-	0x02, 0x00, 0x00,
+	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_bcce68f21316dd30)
+}
+
+var fileDescriptor_configuration_bcce68f21316dd30 = []byte{
+	// 510 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x92, 0xd1, 0x8e, 0xd2, 0x40,
+	0x14, 0x86, 0xad, 0xc0, 0xb2, 0x9c, 0x05, 0x85, 0xd9, 0x6c, 0x6c, 0x5c, 0x2f, 0xb0, 0x89, 0xa6,
+	0xd1, 0x4d, 0x31, 0x78, 0xeb, 0x0d, 0x10, 0x2e, 0x36, 0x2e, 0xb8, 0x29, 0x78, 0xe3, 0x4d, 0x73,
+	0xda, 0x1e, 0x4a, 0xb3, 0x74, 0xa6, 0x99, 0x99, 0x26, 0xe0, 0x03, 0xf8, 0x06, 0x3e, 0x82, 0xef,
+	0x69, 0xa6, 0xa5, 0x88, 0x77, 0xe7, 0xfc, 0xe7, 0xeb, 0x74, 0xfe, 0xf3, 0x0f, 0xdc, 0x0a, 0x19,
+	0x93, 0x24, 0x39, 0x8a, 0x04, 0xdf, 0xa4, 0x49, 0x21, 0x51, 0xa7, 0x82, 0x7b, 0xb9, 0x14, 0x5a,
+	0xb0, 0xf6, 0x71, 0xe8, 0xfc, 0xb1, 0xa0, 0x37, 0x13, 0x5c, 0x11, 0x57, 0x85, 0x5a, 0x1f, 0x72,
+	0x62, 0x0c, 0x9a, 0xfa, 0x90, 0x93, 0x6d, 0x0d, 0x2d, 0xb7, 0xe3, 0x97, 0x35, 0x7b, 0x0d, 0x97,
+	0x19, 0x69, 0x8c, 0x51, 0xa3, 0xfd, 0x7c, 0x68, 0xb9, 0x5d, 0xff, 0xd4, 0xb3, 0x31, 0xb4, 0x94,
+	0x46, 0x4d, 0x76, 0x63, 0x68, 0xb9, 0x2f, 0xc6, 0x6f, 0xbc, 0xe3, 0xd1, 0xde, 0x7f, 0xc7, 0x7a,
+	0x2b, 0xc3, 0xf8, 0x15, 0xea, 0x7c, 0x82, 0x56, 0xd9, 0xb3, 0x3e, 0x74, 0x57, 0xeb, 0xc9, 0x7a,
+	0x1e, 0x2c, 0xbf, 0xf9, 0x8b, 0xc9, 0x43, 0xff, 0x19, 0xbb, 0x81, 0x41, 0xa5, 0x2c, 0x26, 0xf7,
+	0xcb, 0xf5, 0x7c, 0x39, 0x59, 0xce, 0xe6, 0x7d, 0xcb, 0xf9, 0x6d, 0x41, 0x67, 0x8a, 0x3a, 0xda,
+	0xae, 0xd2, 0x9f, 0xc4, 0x3e, 0xc0, 0x20, 0xc3, 0x7d, 0x90, 0x91, 0x52, 0x98, 0x50, 0x10, 0x89,
+	0x82, 0xeb, 0xf2, 0xc2, 0x3d, 0xff, 0x65, 0x86, 0xfb, 0x45, 0xa5, 0xcf, 0x8c, 0xcc, 0xee, 0x80,
+	0x61, 0xa8, 0xc4, 0xae, 0xd0, 0x14, 0x98, 0x8f, 0xc2, 0x83, 0x26, 0x55, 0xba, 0xe8, 0xf9, 0xfd,
+	0x7a, 0xb2, 0xc0, 0xfd, 0xd4, 0xe8, 0xcc, 0x83, 0xeb, 0x5c, 0xd2, 0x86, 0xa4, 0xa4, 0xf8, 0x0c,
+	0x6f, 0x94, 0xf8, 0xe0, 0x34, 0xaa, 0x79, 0xc7, 0x85, 0x6e, 0x79, 0xad, 0x75, 0x9a, 0x91, 0x28,
+	0x34, 0xb3, 0xa1, 0xad, 0xab, 0xf2, 0xb8, 0xc0, 0xba, 0x75, 0xbe, 0x40, 0xaf, 0x24, 0x1f, 0x65,
+	0x2a, 0x64, 0xaa, 0x0f, 0xec, 0x23, 0xb4, 0x76, 0xc8, 0x49, 0xd9, 0xd6, 0xb0, 0xe1, 0x5e, 0x8d,
+	0x6f, 0x4e, 0x8b, 0xab, 0x89, 0x07, 0xe4, 0xe4, 0x57, 0x8c, 0xf3, 0xcb, 0x82, 0xee, 0xb9, 0x6e,
+	0x62, 0xe2, 0x98, 0x9d, 0x62, 0x32, 0x35, 0x7b, 0x0b, 0xdd, 0x2d, 0x61, 0x4c, 0x32, 0x30, 0xa9,
+	0x19, 0x93, 0x0d, 0xb7, 0xe3, 0x5f, 0x55, 0x9a, 0x49, 0x41, 0xb1, 0x57, 0xd0, 0xce, 0x54, 0x1e,
+	0xa4, 0xb1, 0xf1, 0x64, 0xa6, 0x17, 0x99, 0xca, 0xef, 0x63, 0xc5, 0xde, 0x83, 0xd9, 0x5c, 0x90,
+	0x13, 0x8f, 0x53, 0x9e, 0x04, 0x98, 0x90, 0xdd, 0x2c, 0x8f, 0xee, 0x65, 0xb8, 0x7f, 0xac, 0xd4,
+	0x49, 0x42, 0xc6, 0xf0, 0x57, 0xdc, 0x3c, 0xe1, 0x54, 0x8a, 0x27, 0x92, 0xca, 0x18, 0x0e, 0xab,
+	0xb2, 0xf4, 0xd1, 0xf1, 0xeb, 0xd6, 0x19, 0xc3, 0xf5, 0x6c, 0x8b, 0x9c, 0xd3, 0xce, 0x27, 0xa5,
+	0x65, 0x1a, 0x99, 0xf7, 0xa7, 0xd8, 0x2d, 0x74, 0xcc, 0x8f, 0xfe, 0x65, 0xd6, 0xf4, 0x2f, 0x33,
+	0xdc, 0x97, 0x61, 0x4d, 0xbf, 0xc3, 0x3b, 0x21, 0x13, 0x6f, 0x7b, 0xc8, 0x49, 0xee, 0x28, 0x4e,
+	0x48, 0x7a, 0x1b, 0x0c, 0x65, 0x1a, 0x55, 0xef, 0x56, 0xd5, 0x3b, 0xfa, 0x71, 0x97, 0xa4, 0x7a,
+	0x5b, 0x84, 0x5e, 0x24, 0xb2, 0xd1, 0x19, 0x3d, 0xaa, 0xe8, 0x51, 0x45, 0x8f, 0x8e, 0x74, 0x78,
+	0x51, 0xf6, 0x9f, 0xff, 0x0e, 0x00, 0xaa, 0xb1, 0x0c, 0x49, 0x14, 0x03, 0x00, 0x00,
 }
@@ -32,7 +32,7 @@ func (m *ConfigMetadata) Reset()         { *m = ConfigMetadata{} }
 func (m *ConfigMetadata) String() string { return proto.CompactTextString(m) }
 func (*ConfigMetadata) ProtoMessage()    {}
 func (*ConfigMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_780531726dd41db7, []int{0}
+	return fileDescriptor_configuration_6f12d215c949b072, []int{0}
 }
 func (m *ConfigMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConfigMetadata.Unmarshal(m, b)
@@ -68,20 +68,28 @@ func (m *ConfigMetadata) GetOptions() *Options {
 
 // Consenter represents a consenting node (i.e. replica).
 type Consenter struct {
-	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
-	Port                 uint32   `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
-	ClientTlsCert        []byte   `protobuf:"bytes,3,opt,name=client_tls_cert,json=clientTlsCert,proto3" json:"client_tls_cert,omitempty"`
-	ServerTlsCert        []byte   `protobuf:"bytes,4,opt,name=server_tls_cert,json=serverTlsCert,proto3" json:"server_tls_cert,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Host          string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Port          uint32 `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	ClientTlsCert []byte `protobuf:"bytes,3,opt,name=client_tls_cert,json=clientTlsCert,proto3" json:"client_tls_cert,omitempty"`
+	ServerTlsCert []byte `protobuf:"bytes,4,opt,name=server_tls_cert,json=serverTlsCert,proto3" json:"server_tls_cert,omitempty"`
+	// Marks a consenter added by a config update to join the Raft
+	// cluster as a learner (non-voting member), which is promoted to
+	// a voter once it has caught up with the leader. It has no effect
+	// on the consenters which are already members of the cluster.
+	Learner bool `protobuf:"varint,5,opt,name=learner,proto3" json:"learner,omitempty"`
+	// Set by a consenter rotating its TLS certificates to the certificates
+	// it replaces, which are still accepted until the end of the grace period.
+	Rotation             *CertRotation `protobuf:"bytes,6,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Consenter) Reset()         { *m = Consenter{} }
 func (m *Consenter) String() string { return proto.CompactTextString(m) }
 func (*Consenter) ProtoMessage()    {}
 func (*Consenter) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_780531726dd41db7, []int{1}
+	return fileDescriptor_configuration_6f12d215c949b072, []int{1}
 }
 func (m *Consenter) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Consenter.Unmarshal(m, b)
@@ -129,6 +137,78 @@ func (m *Consenter) GetServerTlsCert() []byte {
 	return nil
 }
 
+func (m *Consenter) GetLearner() bool {
+	if m != nil {
+		return m.Learner
+	}
+	return false
+}
+
+func (m *Consenter) GetRotation() *CertRotation {
+	if m != nil {
+		return m.Rotation
+	}
+	return nil
+}
+
+// CertRotation holds the TLS certificates a consenter used before a
+// certificate rotation, along with the end of the grace period during
+// which the cluster accepts both the previous and the new certificates.
+type CertRotation struct {
+	ClientTlsCert []byte `protobuf:"bytes,1,opt,name=client_tls_cert,json=clientTlsCert,proto3" json:"client_tls_cert,omitempty"`
+	ServerTlsCert []byte `protobuf:"bytes,2,opt,name=server_tls_cert,json=serverTlsCert,proto3" json:"server_tls_cert,omitempty"`
+	// Time in RFC3339 format, e.g. 2006-01-02T15:04:05Z
+	GracePeriodEnd       string   `protobuf:"bytes,3,opt,name=grace_period_end,json=gracePeriodEnd,proto3" json:"grace_period_end,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CertRotation) Reset()         { *m = CertRotation{} }
+func (m *CertRotation) String() string { return proto.CompactTextString(m) }
+func (*CertRotation) ProtoMessage()    {}
+func (*CertRotation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_6f12d215c949b072, []int{2}
+}
+func (m *CertRotation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertRotation.Unmarshal(m, b)
+}
+func (m *CertRotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertRotation.Marshal(b, m, deterministic)
+}
+func (dst *CertRotation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertRotation.Merge(dst, src)
+}
+func (m *CertRotation) XXX_Size() int {
+	return xxx_messageInfo_CertRotation.Size(m)
+}
+func (m *CertRotation) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertRotation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertRotation proto.InternalMessageInfo
+
+func (m *CertRotation) GetClientTlsCert() []byte {
+	if m != nil {
+		return m.ClientTlsCert
+	}
+	return nil
+}
+
+func (m *CertRotation) GetServerTlsCert() []byte {
+	if m != nil {
+		return m.ServerTlsCert
+	}
+	return nil
+}
+
+func (m *CertRotation) GetGracePeriodEnd() string {
+	if m != nil {
+		return m.GracePeriodEnd
+	}
+	return ""
+}
+
 // Options to be specified for all the etcd/raft nodes. These can be modified on a
 // per-channel basis.
 type Options struct {
@@ -147,7 +227,7 @@ func (m *Options) Reset()         { *m = Options{} }
 func (m *Options) String() string { return proto.CompactTextString(m) }
 func (*Options) ProtoMessage()    {}
 func (*Options) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_780531726dd41db7, []int{2}
+	return fileDescriptor_configuration_6f12d215c949b072, []int{3}
 }
 func (m *Options) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Options.Unmarshal(m, b)
@@ -213,7 +293,10 @@ type BlockMetadata struct {
 	// to the next OSN that will join this cluster.
 	NextConsenterId uint64 `protobuf:"varint,2,opt,name=next_consenter_id,json=nextConsenterId,proto3" json:"next_consenter_id,omitempty"`
 	// Index of etcd/raft entry for current block.
-	RaftIndex            uint64   `protobuf:"varint,3,opt,name=raft_index,json=raftIndex,proto3" json:"raft_index,omitempty"`
+	RaftIndex uint64 `protobuf:"varint,3,opt,name=raft_index,json=raftIndex,proto3" json:"raft_index,omitempty"`
+	// Raft IDs of the OSNs which joined the cluster as learners
+	// and have not been promoted to voters yet.
+	LearnerIds           []uint64 `protobuf:"varint,4,rep,packed,name=learner_ids,json=learnerIds,proto3" json:"learner_ids,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -223,7 +306,7 @@ func (m *BlockMetadata) Reset()         { *m = BlockMetadata{} }
 func (m *BlockMetadata) String() string { return proto.CompactTextString(m) }
 func (*BlockMetadata) ProtoMessage()    {}
 func (*BlockMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_780531726dd41db7, []int{3}
+	return fileDescriptor_configuration_6f12d215c949b072, []int{4}
 }
 func (m *BlockMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockMetadata.Unmarshal(m, b)
@@ -264,45 +347,59 @@ func (m *BlockMetadata) GetRaftIndex() uint64 {
 	return 0
 }
 
+func (m *BlockMetadata) GetLearnerIds() []uint64 {
+	if m != nil {
+		return m.LearnerIds
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ConfigMetadata)(nil), "etcdraft.ConfigMetadata")
 	proto.RegisterType((*Consenter)(nil), "etcdraft.Consenter")
+	proto.RegisterType((*CertRotation)(nil), "etcdraft.CertRotation")
 	proto.RegisterType((*Options)(nil), "etcdraft.Options")
 	proto.RegisterType((*BlockMetadata)(nil), "etcdraft.BlockMetadata")
 }
 
 func init() {
-	proto.RegisterFile("orderer/etcdraft/configuration.proto", fileDescriptor_configuration_780531726dd41db7)
-}
-
-var fileDescriptor_configuration_780531726dd41db7 = []byte{
-	// 448 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x92, 0xc1, 0x6f, 0xd3, 0x30,
-	0x14, 0xc6, 0x15, 0x56, 0x18, 0x7d, 0x6b, 0x36, 0xd5, 0x43, 0x28, 0x17, 0xa4, 0xaa, 0x03, 0x54,
-	0x81, 0x94, 0x48, 0x1b, 0xfc, 0x03, 0xeb, 0xa9, 0x07, 0x84, 0x14, 0x76, 0xe2, 0x62, 0x39, 0xce,
-	0x6b, 0x62, 0x35, 0x8d, 0x23, 0xfb, 0x6d, 0x2a, 0xbb, 0x70, 0xe0, 0x1f, 0xe5, 0x4f, 0x41, 0xb6,
-	0x93, 0xb4, 0xe2, 0x66, 0x7d, 0xdf, 0xef, 0xb3, 0x3f, 0xeb, 0x3d, 0x78, 0xaf, 0x4d, 0x89, 0x06,
-	0x4d, 0x86, 0x24, 0x4b, 0x23, 0xb6, 0x94, 0x49, 0xdd, 0x6e, 0x55, 0xf5, 0x68, 0x04, 0x29, 0xdd,
-	0xa6, 0x9d, 0xd1, 0xa4, 0xd9, 0xeb, 0xc1, 0x5d, 0x1a, 0xb8, 0x5c, 0x7b, 0xe0, 0x1b, 0x92, 0x28,
-	0x05, 0x09, 0x76, 0x07, 0x20, 0x75, 0x6b, 0xb1, 0x25, 0x34, 0x36, 0x89, 0x16, 0x67, 0xab, 0x8b,
-	0xdb, 0xeb, 0x74, 0x08, 0xa4, 0xeb, 0xc1, 0xcb, 0x4f, 0x30, 0xf6, 0x19, 0xce, 0x75, 0xe7, 0x1e,
-	0xb0, 0xc9, 0x8b, 0x45, 0xb4, 0xba, 0xb8, 0x9d, 0x1f, 0x13, 0xdf, 0x83, 0x91, 0x0f, 0xc4, 0xf2,
-	0x4f, 0x04, 0xd3, 0xf1, 0x1a, 0xc6, 0x60, 0x52, 0x6b, 0x4b, 0x49, 0xb4, 0x88, 0x56, 0xd3, 0xdc,
-	0x9f, 0x9d, 0xd6, 0x69, 0x43, 0xfe, 0xae, 0x38, 0xf7, 0x67, 0xf6, 0x11, 0xae, 0x64, 0xa3, 0xb0,
-	0x25, 0x4e, 0x8d, 0xe5, 0x12, 0x0d, 0x25, 0x67, 0x8b, 0x68, 0x35, 0xcb, 0xe3, 0x20, 0x3f, 0x34,
-	0x76, 0x8d, 0x81, 0xb3, 0x68, 0x9e, 0xd0, 0x1c, 0xb9, 0x49, 0xe0, 0x82, 0xdc, 0x73, 0xcb, 0xbf,
-	0x11, 0x9c, 0xf7, 0xd5, 0xd8, 0x0d, 0xc4, 0xa4, 0xe4, 0x8e, 0x2b, 0xd7, 0xe8, 0x49, 0x34, 0x7d,
-	0x99, 0x99, 0x13, 0x37, 0xbd, 0xe6, 0x20, 0x6c, 0x50, 0xba, 0x04, 0x77, 0x46, 0xdf, 0x6e, 0x36,
-	0x88, 0x0f, 0x4a, 0xee, 0xd8, 0x07, 0xb8, 0xac, 0x51, 0x18, 0x2a, 0x50, 0x50, 0xa0, 0xce, 0x3c,
-	0x15, 0x8f, 0xaa, 0xc7, 0x52, 0xb8, 0xde, 0x8b, 0x03, 0x57, 0xed, 0xb6, 0x51, 0x55, 0x4d, 0xbc,
-	0x68, 0xb4, 0xdc, 0x59, 0x5f, 0x34, 0xce, 0xe7, 0x7b, 0x71, 0xd8, 0xf4, 0xce, 0xbd, 0x37, 0xd8,
-	0x17, 0x78, 0x6b, 0x5b, 0xd1, 0xd9, 0x5a, 0xd3, 0x58, 0x92, 0x5b, 0xf5, 0x8c, 0xc9, 0x4b, 0x1f,
-	0x79, 0x33, 0xb8, 0x43, 0xdb, 0x1f, 0xea, 0x19, 0x97, 0xbf, 0x21, 0xf6, 0xf9, 0x71, 0xb6, 0x37,
-	0x10, 0x8f, 0x43, 0xe3, 0xaa, 0x0c, 0xe3, 0x9d, 0xe4, 0xb3, 0x51, 0xdc, 0x94, 0x96, 0x7d, 0x82,
-	0x79, 0x8b, 0x07, 0xe2, 0xa7, 0xa4, 0xff, 0xeb, 0x24, 0xbf, 0x72, 0xc6, 0xfa, 0x08, 0xb3, 0x77,
-	0x00, 0x6e, 0xc6, 0x5c, 0xb5, 0x25, 0x1e, 0xfc, 0x57, 0x27, 0xf9, 0xd4, 0x29, 0x1b, 0x27, 0xdc,
-	0x57, 0x90, 0x6a, 0x53, 0xa5, 0xf5, 0xaf, 0x0e, 0x4d, 0x83, 0x65, 0x85, 0x26, 0xdd, 0x8a, 0xc2,
-	0x28, 0x19, 0xf6, 0xd0, 0xa6, 0xfd, 0xb6, 0x8e, 0xcb, 0xf2, 0xf3, 0x6b, 0xa5, 0xa8, 0x7e, 0x2c,
-	0x52, 0xa9, 0xf7, 0xd9, 0x49, 0x2c, 0x0b, 0xb1, 0x2c, 0xc4, 0xb2, 0xff, 0x97, 0xbc, 0x78, 0xe5,
-	0x8d, 0xbb, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x7e, 0x1e, 0x2b, 0x0a, 0xff, 0x02, 0x00, 0x00,
+	proto.RegisterFile("orderer/etcdraft/configuration.proto", fileDescriptor_configuration_6f12d215c949b072)
+}
+
+var fileDescriptor_configuration_6f12d215c949b072 = []byte{
+	// 543 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xcf, 0x6e, 0xd4, 0x30,
+	0x10, 0xc6, 0x95, 0x36, 0xb4, 0xdd, 0xe9, 0xa6, 0xa5, 0x2e, 0xaa, 0x72, 0x41, 0xac, 0xb6, 0x80,
+	0x22, 0x90, 0x12, 0x69, 0x0b, 0x2f, 0xd0, 0x15, 0x87, 0x3d, 0x20, 0x90, 0xe9, 0x89, 0x8b, 0xe5,
+	0x75, 0x66, 0x13, 0x6b, 0xb3, 0x76, 0x64, 0xbb, 0xd5, 0xd2, 0x27, 0xe0, 0x31, 0x78, 0x25, 0xde,
+	0x80, 0x47, 0x41, 0x71, 0xfe, 0xec, 0x0a, 0xf5, 0xc0, 0x2d, 0xf9, 0xbe, 0xdf, 0xd8, 0x33, 0xe3,
+	0x19, 0x78, 0xad, 0x4d, 0x8e, 0x06, 0x4d, 0x86, 0x4e, 0xe4, 0x86, 0xaf, 0x5c, 0x26, 0xb4, 0x5a,
+	0xc9, 0xe2, 0xde, 0x70, 0x27, 0xb5, 0x4a, 0x6b, 0xa3, 0x9d, 0x26, 0x27, 0xbd, 0x3b, 0x35, 0x70,
+	0x36, 0xf7, 0xc0, 0x67, 0x74, 0x3c, 0xe7, 0x8e, 0x93, 0x1b, 0x00, 0xa1, 0x95, 0x45, 0xe5, 0xd0,
+	0xd8, 0x38, 0x98, 0x1c, 0x26, 0xa7, 0xb3, 0xcb, 0xb4, 0x0f, 0x48, 0xe7, 0xbd, 0x47, 0xf7, 0x30,
+	0xf2, 0x1e, 0x8e, 0x75, 0xdd, 0x5c, 0x60, 0xe3, 0x83, 0x49, 0x90, 0x9c, 0xce, 0x2e, 0x76, 0x11,
+	0x5f, 0x5a, 0x83, 0xf6, 0xc4, 0xf4, 0x77, 0x00, 0xa3, 0xe1, 0x18, 0x42, 0x20, 0x2c, 0xb5, 0x75,
+	0x71, 0x30, 0x09, 0x92, 0x11, 0xf5, 0xdf, 0x8d, 0x56, 0x6b, 0xe3, 0xfc, 0x59, 0x11, 0xf5, 0xdf,
+	0xe4, 0x2d, 0x9c, 0x8b, 0x4a, 0xa2, 0x72, 0xcc, 0x55, 0x96, 0x09, 0x34, 0x2e, 0x3e, 0x9c, 0x04,
+	0xc9, 0x98, 0x46, 0xad, 0x7c, 0x57, 0xd9, 0x39, 0xb6, 0x9c, 0x45, 0xf3, 0x80, 0x66, 0xc7, 0x85,
+	0x2d, 0xd7, 0xca, 0x3d, 0x17, 0xc3, 0x71, 0x85, 0xdc, 0x28, 0x34, 0xf1, 0xb3, 0x49, 0x90, 0x9c,
+	0xd0, 0xfe, 0x97, 0xcc, 0xe0, 0xc4, 0x68, 0xe7, 0xfb, 0x15, 0x1f, 0xf9, 0x6a, 0xae, 0xf6, 0xea,
+	0x47, 0xe3, 0x68, 0xe7, 0xd2, 0x81, 0x9b, 0xfe, 0x0c, 0x60, 0xbc, 0x6f, 0x3d, 0x95, 0x6e, 0xf0,
+	0x9f, 0xe9, 0x1e, 0x3c, 0x95, 0x6e, 0x02, 0xcf, 0x0b, 0xc3, 0x05, 0xb2, 0x1a, 0x8d, 0xd4, 0x39,
+	0x43, 0x95, 0xfb, 0xfa, 0x47, 0xf4, 0xcc, 0xeb, 0x5f, 0xbd, 0xfc, 0x49, 0xe5, 0xd3, 0x3f, 0x01,
+	0x1c, 0x77, 0x3d, 0x27, 0xd7, 0x10, 0x39, 0x29, 0xd6, 0x4c, 0x36, 0xad, 0x7e, 0xe0, 0x55, 0xd7,
+	0xe5, 0x71, 0x23, 0x2e, 0x3a, 0xad, 0x81, 0xb0, 0x42, 0xd1, 0x44, 0xb0, 0xc6, 0xe8, 0xda, 0x3e,
+	0xee, 0xc5, 0x3b, 0x29, 0xd6, 0xe4, 0x0d, 0x9c, 0x95, 0xc8, 0x8d, 0x5b, 0x22, 0x77, 0x2d, 0x75,
+	0xe8, 0xa9, 0x68, 0x50, 0x3d, 0x96, 0xc2, 0xe5, 0x86, 0x6f, 0x99, 0x54, 0xab, 0x4a, 0x16, 0xa5,
+	0x63, 0xcb, 0x4a, 0x8b, 0xb5, 0xf5, 0x2f, 0x10, 0xd1, 0x8b, 0x0d, 0xdf, 0x2e, 0x3a, 0xe7, 0xd6,
+	0x1b, 0xe4, 0x03, 0x5c, 0x59, 0xc5, 0x6b, 0x5b, 0x6a, 0x37, 0x24, 0xc9, 0xac, 0x7c, 0x44, 0xff,
+	0x28, 0x11, 0x7d, 0xd1, 0xbb, 0x7d, 0xb6, 0xdf, 0xe4, 0x23, 0x4e, 0x7f, 0x05, 0x10, 0xf9, 0x03,
+	0x86, 0xa9, 0xbd, 0x86, 0x68, 0x18, 0x47, 0x26, 0xf3, 0x76, 0x70, 0x43, 0x3a, 0x1e, 0xc4, 0x45,
+	0x6e, 0xc9, 0x3b, 0xb8, 0x50, 0xb8, 0x75, 0x6c, 0x9f, 0xf4, 0xc5, 0x86, 0xf4, 0xbc, 0x31, 0xe6,
+	0x3b, 0x98, 0xbc, 0x04, 0x68, 0xde, 0x9b, 0x49, 0x95, 0xe3, 0xd6, 0xd7, 0x1a, 0xd2, 0x51, 0xa3,
+	0x2c, 0x1a, 0x81, 0xbc, 0x82, 0xd3, 0x6e, 0x5c, 0xfc, 0x6d, 0xa1, 0xbf, 0x0d, 0x3a, 0x69, 0x91,
+	0xdb, 0xdb, 0x02, 0x52, 0x6d, 0x8a, 0xb4, 0xfc, 0x51, 0xa3, 0xa9, 0x30, 0x2f, 0xd0, 0xa4, 0x2b,
+	0xbe, 0x34, 0x52, 0xb4, 0x2b, 0x68, 0xd3, 0x6e, 0x51, 0x87, 0xc9, 0xfa, 0xfe, 0xb1, 0x90, 0xae,
+	0xbc, 0x5f, 0xa6, 0x42, 0x6f, 0xb2, 0xbd, 0xb0, 0xac, 0x0d, 0xcb, 0xda, 0xb0, 0xec, 0xdf, 0xfd,
+	0x5e, 0x1e, 0x79, 0xe3, 0xe6, 0xef, 0x00, 0xe3, 0x60, 0x22, 0xc8, 0xfa, 0x03, 0x00, 0x00,
 }
@@ -27,28 +27,32 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type ChaincodeMessage_Type int32
 
 const (
-	ChaincodeMessage_UNDEFINED             ChaincodeMessage_Type = 0
-	ChaincodeMessage_REGISTER              ChaincodeMessage_Type = 1
-	ChaincodeMessage_REGISTERED            ChaincodeMessage_Type = 2
-	ChaincodeMessage_INIT                  ChaincodeMessage_Type = 3
-	ChaincodeMessage_READY                 ChaincodeMessage_Type = 4
-	ChaincodeMessage_TRANSACTION           ChaincodeMessage_Type = 5
-	ChaincodeMessage_COMPLETED             ChaincodeMessage_Type = 6
-	ChaincodeMessage_ERROR                 ChaincodeMessage_Type = 7
-	ChaincodeMessage_GET_STATE             ChaincodeMessage_Type = 8
-	ChaincodeMessage_PUT_STATE             ChaincodeMessage_Type = 9
-	ChaincodeMessage_DEL_STATE             ChaincodeMessage_Type = 10
-	ChaincodeMessage_INVOKE_CHAINCODE      ChaincodeMessage_Type = 11
-	ChaincodeMessage_RESPONSE              ChaincodeMessage_Type = 13
-	ChaincodeMessage_GET_STATE_BY_RANGE    ChaincodeMessage_Type = 14
-	ChaincodeMessage_GET_QUERY_RESULT      ChaincodeMessage_Type = 15
-	ChaincodeMessage_QUERY_STATE_NEXT      ChaincodeMessage_Type = 16
-	ChaincodeMessage_QUERY_STATE_CLOSE     ChaincodeMessage_Type = 17
-	ChaincodeMessage_KEEPALIVE             ChaincodeMessage_Type = 18
-	ChaincodeMessage_GET_HISTORY_FOR_KEY   ChaincodeMessage_Type = 19
-	ChaincodeMessage_GET_STATE_METADATA    ChaincodeMessage_Type = 20
-	ChaincodeMessage_PUT_STATE_METADATA    ChaincodeMessage_Type = 21
-	ChaincodeMessage_GET_PRIVATE_DATA_HASH ChaincodeMessage_Type = 22
+	ChaincodeMessage_UNDEFINED                        ChaincodeMessage_Type = 0
+	ChaincodeMessage_REGISTER                         ChaincodeMessage_Type = 1
+	ChaincodeMessage_REGISTERED                       ChaincodeMessage_Type = 2
+	ChaincodeMessage_INIT                             ChaincodeMessage_Type = 3
+	ChaincodeMessage_READY                            ChaincodeMessage_Type = 4
+	ChaincodeMessage_TRANSACTION                      ChaincodeMessage_Type = 5
+	ChaincodeMessage_COMPLETED                        ChaincodeMessage_Type = 6
+	ChaincodeMessage_ERROR                            ChaincodeMessage_Type = 7
+	ChaincodeMessage_GET_STATE                        ChaincodeMessage_Type = 8
+	ChaincodeMessage_PUT_STATE                        ChaincodeMessage_Type = 9
+	ChaincodeMessage_DEL_STATE                        ChaincodeMessage_Type = 10
+	ChaincodeMessage_INVOKE_CHAINCODE                 ChaincodeMessage_Type = 11
+	ChaincodeMessage_RESPONSE                         ChaincodeMessage_Type = 13
+	ChaincodeMessage_GET_STATE_BY_RANGE               ChaincodeMessage_Type = 14
+	ChaincodeMessage_GET_QUERY_RESULT                 ChaincodeMessage_Type = 15
+	ChaincodeMessage_QUERY_STATE_NEXT                 ChaincodeMessage_Type = 16
+	ChaincodeMessage_QUERY_STATE_CLOSE                ChaincodeMessage_Type = 17
+	ChaincodeMessage_KEEPALIVE                        ChaincodeMessage_Type = 18
+	ChaincodeMessage_GET_HISTORY_FOR_KEY              ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_STATE_METADATA               ChaincodeMessage_Type = 20
+	ChaincodeMessage_PUT_STATE_METADATA               ChaincodeMessage_Type = 21
+	ChaincodeMessage_GET_PRIVATE_DATA_HASH            ChaincodeMessage_Type = 22
+	ChaincodeMessage_GET_STATE_AT_HEIGHT              ChaincodeMessage_Type = 23
+	ChaincodeMessage_GET_STATE_BY_RANGE_AT_HEIGHT     ChaincodeMessage_Type = 24
+	ChaincodeMessage_GET_HISTORY_FOR_KEY_RANGE        ChaincodeMessage_Type = 25
+	ChaincodeMessage_GET_HISTORY_FOR_KEY_WITH_OPTIONS ChaincodeMessage_Type = 26
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -74,37 +78,45 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	20: "GET_STATE_METADATA",
 	21: "PUT_STATE_METADATA",
 	22: "GET_PRIVATE_DATA_HASH",
+	23: "GET_STATE_AT_HEIGHT",
+	24: "GET_STATE_BY_RANGE_AT_HEIGHT",
+	25: "GET_HISTORY_FOR_KEY_RANGE",
+	26: "GET_HISTORY_FOR_KEY_WITH_OPTIONS",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
-	"UNDEFINED":             0,
-	"REGISTER":              1,
-	"REGISTERED":            2,
-	"INIT":                  3,
-	"READY":                 4,
-	"TRANSACTION":           5,
-	"COMPLETED":             6,
-	"ERROR":                 7,
-	"GET_STATE":             8,
-	"PUT_STATE":             9,
-	"DEL_STATE":             10,
-	"INVOKE_CHAINCODE":      11,
-	"RESPONSE":              13,
-	"GET_STATE_BY_RANGE":    14,
-	"GET_QUERY_RESULT":      15,
-	"QUERY_STATE_NEXT":      16,
-	"QUERY_STATE_CLOSE":     17,
-	"KEEPALIVE":             18,
-	"GET_HISTORY_FOR_KEY":   19,
-	"GET_STATE_METADATA":    20,
-	"PUT_STATE_METADATA":    21,
-	"GET_PRIVATE_DATA_HASH": 22,
+	"UNDEFINED":                        0,
+	"REGISTER":                         1,
+	"REGISTERED":                       2,
+	"INIT":                             3,
+	"READY":                            4,
+	"TRANSACTION":                      5,
+	"COMPLETED":                        6,
+	"ERROR":                            7,
+	"GET_STATE":                        8,
+	"PUT_STATE":                        9,
+	"DEL_STATE":                        10,
+	"INVOKE_CHAINCODE":                 11,
+	"RESPONSE":                         13,
+	"GET_STATE_BY_RANGE":               14,
+	"GET_QUERY_RESULT":                 15,
+	"QUERY_STATE_NEXT":                 16,
+	"QUERY_STATE_CLOSE":                17,
+	"KEEPALIVE":                        18,
+	"GET_HISTORY_FOR_KEY":              19,
+	"GET_STATE_METADATA":               20,
+	"PUT_STATE_METADATA":               21,
+	"GET_PRIVATE_DATA_HASH":            22,
+	"GET_STATE_AT_HEIGHT":              23,
+	"GET_STATE_BY_RANGE_AT_HEIGHT":     24,
+	"GET_HISTORY_FOR_KEY_RANGE":        25,
+	"GET_HISTORY_FOR_KEY_WITH_OPTIONS": 26,
 }
 
 func (x ChaincodeMessage_Type) String() string {
 	return proto.EnumName(ChaincodeMessage_Type_name, int32(x))
 }
 func (ChaincodeMessage_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{0, 0}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{0, 0}
 }
 
 type ChaincodeMessage struct {
@@ -113,11 +125,11 @@ type ChaincodeMessage struct {
 	Payload   []byte                `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
 	Txid      string                `protobuf:"bytes,4,opt,name=txid,proto3" json:"txid,omitempty"`
 	Proposal  *SignedProposal       `protobuf:"bytes,5,opt,name=proposal,proto3" json:"proposal,omitempty"`
-	// event emitted by chaincode. Used only with Init or Invoke.
+	//event emitted by chaincode. Used only with Init or Invoke.
 	// This event is then stored (currently)
-	// with Block.NonHashData.TransactionResult
+	//with Block.NonHashData.TransactionResult
 	ChaincodeEvent *ChaincodeEvent `protobuf:"bytes,6,opt,name=chaincode_event,json=chaincodeEvent,proto3" json:"chaincode_event,omitempty"`
-	// channel id
+	//channel id
 	ChannelId            string   `protobuf:"bytes,7,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -128,7 +140,7 @@ func (m *ChaincodeMessage) Reset()         { *m = ChaincodeMessage{} }
 func (m *ChaincodeMessage) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeMessage) ProtoMessage()    {}
 func (*ChaincodeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{0}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{0}
 }
 func (m *ChaincodeMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeMessage.Unmarshal(m, b)
@@ -212,7 +224,7 @@ func (m *GetState) Reset()         { *m = GetState{} }
 func (m *GetState) String() string { return proto.CompactTextString(m) }
 func (*GetState) ProtoMessage()    {}
 func (*GetState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{1}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{1}
 }
 func (m *GetState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetState.Unmarshal(m, b)
@@ -258,7 +270,7 @@ func (m *GetStateMetadata) Reset()         { *m = GetStateMetadata{} }
 func (m *GetStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*GetStateMetadata) ProtoMessage()    {}
 func (*GetStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{2}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{2}
 }
 func (m *GetStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateMetadata.Unmarshal(m, b)
@@ -309,7 +321,7 @@ func (m *PutState) Reset()         { *m = PutState{} }
 func (m *PutState) String() string { return proto.CompactTextString(m) }
 func (*PutState) ProtoMessage()    {}
 func (*PutState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{3}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{3}
 }
 func (m *PutState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutState.Unmarshal(m, b)
@@ -363,7 +375,7 @@ func (m *PutStateMetadata) Reset()         { *m = PutStateMetadata{} }
 func (m *PutStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*PutStateMetadata) ProtoMessage()    {}
 func (*PutStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{4}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{4}
 }
 func (m *PutStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutStateMetadata.Unmarshal(m, b)
@@ -420,7 +432,7 @@ func (m *DelState) Reset()         { *m = DelState{} }
 func (m *DelState) String() string { return proto.CompactTextString(m) }
 func (*DelState) ProtoMessage()    {}
 func (*DelState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{5}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{5}
 }
 func (m *DelState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DelState.Unmarshal(m, b)
@@ -472,7 +484,7 @@ func (m *GetStateByRange) Reset()         { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()    {}
 func (*GetStateByRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{6}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{6}
 }
 func (m *GetStateByRange) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateByRange.Unmarshal(m, b)
@@ -537,7 +549,7 @@ func (m *GetQueryResult) Reset()         { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()    {}
 func (*GetQueryResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{7}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{7}
 }
 func (m *GetQueryResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetQueryResult.Unmarshal(m, b)
@@ -593,7 +605,7 @@ func (m *QueryMetadata) Reset()         { *m = QueryMetadata{} }
 func (m *QueryMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryMetadata) ProtoMessage()    {}
 func (*QueryMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{8}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{8}
 }
 func (m *QueryMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryMetadata.Unmarshal(m, b)
@@ -640,7 +652,7 @@ func (m *GetHistoryForKey) Reset()         { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()    {}
 func (*GetHistoryForKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{9}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{9}
 }
 func (m *GetHistoryForKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryForKey.Unmarshal(m, b)
@@ -667,6 +679,311 @@ func (m *GetHistoryForKey) GetKey() string {
 	return ""
 }
 
+// GetHistoryForKeyWithOptions is the payload of a ChaincodeMessage. It contains
+// a key for which the historical values need to be retrieved. The metadata hold
+// the byte representation of HistoryQueryMetadata.
+type GetHistoryForKeyWithOptions struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetHistoryForKeyWithOptions) Reset()         { *m = GetHistoryForKeyWithOptions{} }
+func (m *GetHistoryForKeyWithOptions) String() string { return proto.CompactTextString(m) }
+func (*GetHistoryForKeyWithOptions) ProtoMessage()    {}
+func (*GetHistoryForKeyWithOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{10}
+}
+func (m *GetHistoryForKeyWithOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetHistoryForKeyWithOptions.Unmarshal(m, b)
+}
+func (m *GetHistoryForKeyWithOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetHistoryForKeyWithOptions.Marshal(b, m, deterministic)
+}
+func (dst *GetHistoryForKeyWithOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetHistoryForKeyWithOptions.Merge(dst, src)
+}
+func (m *GetHistoryForKeyWithOptions) XXX_Size() int {
+	return xxx_messageInfo_GetHistoryForKeyWithOptions.Size(m)
+}
+func (m *GetHistoryForKeyWithOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetHistoryForKeyWithOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetHistoryForKeyWithOptions proto.InternalMessageInfo
+
+func (m *GetHistoryForKeyWithOptions) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetHistoryForKeyWithOptions) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// GetHistoryForKeyRange is the payload of a ChaincodeMessage. It contains a
+// start key and an end key of the range of keys for which the historical values
+// need to be retrieved. The metadata hold the byte representation of
+// HistoryQueryMetadata.
+type GetHistoryForKeyRange struct {
+	StartKey             string   `protobuf:"bytes,1,opt,name=startKey,proto3" json:"startKey,omitempty"`
+	EndKey               string   `protobuf:"bytes,2,opt,name=endKey,proto3" json:"endKey,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetHistoryForKeyRange) Reset()         { *m = GetHistoryForKeyRange{} }
+func (m *GetHistoryForKeyRange) String() string { return proto.CompactTextString(m) }
+func (*GetHistoryForKeyRange) ProtoMessage()    {}
+func (*GetHistoryForKeyRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{11}
+}
+func (m *GetHistoryForKeyRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetHistoryForKeyRange.Unmarshal(m, b)
+}
+func (m *GetHistoryForKeyRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetHistoryForKeyRange.Marshal(b, m, deterministic)
+}
+func (dst *GetHistoryForKeyRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetHistoryForKeyRange.Merge(dst, src)
+}
+func (m *GetHistoryForKeyRange) XXX_Size() int {
+	return xxx_messageInfo_GetHistoryForKeyRange.Size(m)
+}
+func (m *GetHistoryForKeyRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetHistoryForKeyRange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetHistoryForKeyRange proto.InternalMessageInfo
+
+func (m *GetHistoryForKeyRange) GetStartKey() string {
+	if m != nil {
+		return m.StartKey
+	}
+	return ""
+}
+
+func (m *GetHistoryForKeyRange) GetEndKey() string {
+	if m != nil {
+		return m.EndKey
+	}
+	return ""
+}
+
+func (m *GetHistoryForKeyRange) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// HistoryQueryMetadata is the metadata of a GetHistoryForKey and GetHistoryForKeyRange.
+// It contains the range of blocks, inclusive, and the time window, with the start
+// inclusive and the end exclusive, of the historical values to be retrieved, an end_block
+// of 0 denoting the last block and an unset time denoting an open window.
+// The values are retrieved newest first if newest_first is set. It also contains
+// a pageSize which denotes the number of records to be fetched and a bookmark.
+type HistoryQueryMetadata struct {
+	StartBlock           uint64               `protobuf:"varint,1,opt,name=start_block,json=startBlock,proto3" json:"start_block,omitempty"`
+	EndBlock             uint64               `protobuf:"varint,2,opt,name=end_block,json=endBlock,proto3" json:"end_block,omitempty"`
+	StartTime            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	NewestFirst          bool                 `protobuf:"varint,5,opt,name=newest_first,json=newestFirst,proto3" json:"newest_first,omitempty"`
+	PageSize             int32                `protobuf:"varint,6,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	Bookmark             string               `protobuf:"bytes,7,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HistoryQueryMetadata) Reset()         { *m = HistoryQueryMetadata{} }
+func (m *HistoryQueryMetadata) String() string { return proto.CompactTextString(m) }
+func (*HistoryQueryMetadata) ProtoMessage()    {}
+func (*HistoryQueryMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{12}
+}
+func (m *HistoryQueryMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryQueryMetadata.Unmarshal(m, b)
+}
+func (m *HistoryQueryMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryQueryMetadata.Marshal(b, m, deterministic)
+}
+func (dst *HistoryQueryMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryQueryMetadata.Merge(dst, src)
+}
+func (m *HistoryQueryMetadata) XXX_Size() int {
+	return xxx_messageInfo_HistoryQueryMetadata.Size(m)
+}
+func (m *HistoryQueryMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryQueryMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryQueryMetadata proto.InternalMessageInfo
+
+func (m *HistoryQueryMetadata) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetNewestFirst() bool {
+	if m != nil {
+		return m.NewestFirst
+	}
+	return false
+}
+
+func (m *HistoryQueryMetadata) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
+// GetStateAtHeight is the payload of a ChaincodeMessage. It contains a key
+// whose value is to be fetched as it was after the block block_number was
+// committed.
+type GetStateAtHeight struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateAtHeight) Reset()         { *m = GetStateAtHeight{} }
+func (m *GetStateAtHeight) String() string { return proto.CompactTextString(m) }
+func (*GetStateAtHeight) ProtoMessage()    {}
+func (*GetStateAtHeight) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{13}
+}
+func (m *GetStateAtHeight) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateAtHeight.Unmarshal(m, b)
+}
+func (m *GetStateAtHeight) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateAtHeight.Marshal(b, m, deterministic)
+}
+func (dst *GetStateAtHeight) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateAtHeight.Merge(dst, src)
+}
+func (m *GetStateAtHeight) XXX_Size() int {
+	return xxx_messageInfo_GetStateAtHeight.Size(m)
+}
+func (m *GetStateAtHeight) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateAtHeight.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateAtHeight proto.InternalMessageInfo
+
+func (m *GetStateAtHeight) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetStateAtHeight) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+// GetStateByRangeAtHeight is the payload of a ChaincodeMessage. It contains
+// a start key and an end key required to execute a range query on the state
+// as it was after the block block_number was committed.
+type GetStateByRangeAtHeight struct {
+	StartKey             string   `protobuf:"bytes,1,opt,name=startKey,proto3" json:"startKey,omitempty"`
+	EndKey               string   `protobuf:"bytes,2,opt,name=endKey,proto3" json:"endKey,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStateByRangeAtHeight) Reset()         { *m = GetStateByRangeAtHeight{} }
+func (m *GetStateByRangeAtHeight) String() string { return proto.CompactTextString(m) }
+func (*GetStateByRangeAtHeight) ProtoMessage()    {}
+func (*GetStateByRangeAtHeight) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{14}
+}
+func (m *GetStateByRangeAtHeight) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateByRangeAtHeight.Unmarshal(m, b)
+}
+func (m *GetStateByRangeAtHeight) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateByRangeAtHeight.Marshal(b, m, deterministic)
+}
+func (dst *GetStateByRangeAtHeight) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateByRangeAtHeight.Merge(dst, src)
+}
+func (m *GetStateByRangeAtHeight) XXX_Size() int {
+	return xxx_messageInfo_GetStateByRangeAtHeight.Size(m)
+}
+func (m *GetStateByRangeAtHeight) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateByRangeAtHeight.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStateByRangeAtHeight proto.InternalMessageInfo
+
+func (m *GetStateByRangeAtHeight) GetStartKey() string {
+	if m != nil {
+		return m.StartKey
+	}
+	return ""
+}
+
+func (m *GetStateByRangeAtHeight) GetEndKey() string {
+	if m != nil {
+		return m.EndKey
+	}
+	return ""
+}
+
+func (m *GetStateByRangeAtHeight) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
 type QueryStateNext struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -678,7 +995,7 @@ func (m *QueryStateNext) Reset()         { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()    {}
 func (*QueryStateNext) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{10}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{15}
 }
 func (m *QueryStateNext) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateNext.Unmarshal(m, b)
@@ -716,7 +1033,7 @@ func (m *QueryStateClose) Reset()         { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()    {}
 func (*QueryStateClose) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{11}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{16}
 }
 func (m *QueryStateClose) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateClose.Unmarshal(m, b)
@@ -755,7 +1072,7 @@ func (m *QueryResultBytes) Reset()         { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()    {}
 func (*QueryResultBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{12}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{17}
 }
 func (m *QueryResultBytes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResultBytes.Unmarshal(m, b)
@@ -783,7 +1100,7 @@ func (m *QueryResultBytes) GetResultBytes() []byte {
 }
 
 // QueryResponse is returned by the peer as a result of a GetStateByRange,
-// GetQueryResult, and GetHistoryForKey. It holds a bunch of records in
+// GetQueryResult, GetHistoryForKey and GetHistoryForKeyRange. It holds a bunch of records in
 // results field, a flag to denote whether more results need to be fetched from
 // the peer in has_more field, transaction id in id field, and a QueryResponseMetadata
 // in metadata field.
@@ -801,7 +1118,7 @@ func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{13}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{18}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
@@ -863,7 +1180,7 @@ func (m *QueryResponseMetadata) Reset()         { *m = QueryResponseMetadata{} }
 func (m *QueryResponseMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryResponseMetadata) ProtoMessage()    {}
 func (*QueryResponseMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{14}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{19}
 }
 func (m *QueryResponseMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponseMetadata.Unmarshal(m, b)
@@ -909,7 +1226,7 @@ func (m *StateMetadata) Reset()         { *m = StateMetadata{} }
 func (m *StateMetadata) String() string { return proto.CompactTextString(m) }
 func (*StateMetadata) ProtoMessage()    {}
 func (*StateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{15}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{20}
 }
 func (m *StateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadata.Unmarshal(m, b)
@@ -954,7 +1271,7 @@ func (m *StateMetadataResult) Reset()         { *m = StateMetadataResult{} }
 func (m *StateMetadataResult) String() string { return proto.CompactTextString(m) }
 func (*StateMetadataResult) ProtoMessage()    {}
 func (*StateMetadataResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_b04d3028f86b65a2, []int{16}
+	return fileDescriptor_chaincode_shim_e5819fec16c96da2, []int{21}
 }
 func (m *StateMetadataResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadataResult.Unmarshal(m, b)
@@ -992,6 +1309,11 @@ func init() {
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
 	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
+	proto.RegisterType((*GetHistoryForKeyWithOptions)(nil), "protos.GetHistoryForKeyWithOptions")
+	proto.RegisterType((*GetHistoryForKeyRange)(nil), "protos.GetHistoryForKeyRange")
+	proto.RegisterType((*HistoryQueryMetadata)(nil), "protos.HistoryQueryMetadata")
+	proto.RegisterType((*GetStateAtHeight)(nil), "protos.GetStateAtHeight")
+	proto.RegisterType((*GetStateByRangeAtHeight)(nil), "protos.GetStateByRangeAtHeight")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
@@ -1107,74 +1429,88 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_b04d3028f86b65a2)
-}
-
-var fileDescriptor_chaincode_shim_b04d3028f86b65a2 = []byte{
-	// 1027 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcf, 0x73, 0xda, 0x46,
-	0x14, 0x0e, 0x06, 0x8c, 0x78, 0xd8, 0x78, 0xb3, 0x0e, 0x2e, 0x66, 0x26, 0x2d, 0x65, 0x7a, 0xa0,
-	0x17, 0x68, 0x68, 0x0f, 0x3d, 0x74, 0x26, 0x83, 0x61, 0x8d, 0x19, 0xdb, 0x40, 0x56, 0xb2, 0x27,
-	0xee, 0x45, 0x23, 0xa4, 0xb5, 0xd0, 0x58, 0x68, 0x55, 0x69, 0x49, 0x43, 0x6f, 0xbd, 0xf6, 0xd8,
-	0x3f, 0xae, 0x7f, 0x4f, 0x67, 0xf5, 0xcb, 0x80, 0xeb, 0xa4, 0x93, 0x13, 0xfa, 0xde, 0xfb, 0xf6,
-	0x7b, 0xbf, 0xf6, 0x21, 0xc1, 0xa9, 0xcf, 0x58, 0xd0, 0x35, 0x17, 0x86, 0xe3, 0x99, 0xdc, 0x62,
-	0x7a, 0xb8, 0x70, 0x96, 0x1d, 0x3f, 0xe0, 0x82, 0xe3, 0xfd, 0xe8, 0x27, 0x6c, 0x34, 0x76, 0x28,
-	0xec, 0x03, 0xf3, 0x44, 0xcc, 0x69, 0x1c, 0x47, 0x3e, 0x3f, 0xe0, 0x3e, 0x0f, 0x0d, 0x37, 0x31,
-	0x7e, 0x63, 0x73, 0x6e, 0xbb, 0xac, 0x1b, 0xa1, 0xf9, 0xea, 0xbe, 0x2b, 0x9c, 0x25, 0x0b, 0x85,
-	0xb1, 0xf4, 0x63, 0x42, 0xeb, 0x9f, 0x22, 0xa0, 0x41, 0xaa, 0x77, 0xcd, 0xc2, 0xd0, 0xb0, 0x19,
-	0x7e, 0x03, 0x05, 0xb1, 0xf6, 0x59, 0x3d, 0xd7, 0xcc, 0xb5, 0xab, 0xbd, 0xd7, 0x31, 0x35, 0xec,
-	0xec, 0xf2, 0x3a, 0xda, 0xda, 0x67, 0x34, 0xa2, 0xe2, 0x9f, 0xa1, 0x9c, 0x49, 0xd7, 0xf7, 0x9a,
-	0xb9, 0x76, 0xa5, 0xd7, 0xe8, 0xc4, 0xc1, 0x3b, 0x69, 0xf0, 0x8e, 0x96, 0x32, 0xe8, 0x23, 0x19,
-	0xd7, 0xa1, 0xe4, 0x1b, 0x6b, 0x97, 0x1b, 0x56, 0x3d, 0xdf, 0xcc, 0xb5, 0x0f, 0x68, 0x0a, 0x31,
-	0x86, 0x82, 0xf8, 0xe8, 0x58, 0xf5, 0x42, 0x33, 0xd7, 0x2e, 0xd3, 0xe8, 0x19, 0xf7, 0x40, 0x49,
-	0x4b, 0xac, 0x17, 0xa3, 0x30, 0x27, 0x69, 0x7a, 0xaa, 0x63, 0x7b, 0xcc, 0x9a, 0x25, 0x5e, 0x9a,
-	0xf1, 0xf0, 0x5b, 0x38, 0xda, 0x69, 0x59, 0x7d, 0x7f, 0xfb, 0x68, 0x56, 0x19, 0x91, 0x5e, 0x5a,
-	0x35, 0xb7, 0x30, 0x7e, 0x0d, 0x60, 0x2e, 0x0c, 0xcf, 0x63, 0xae, 0xee, 0x58, 0xf5, 0x52, 0x94,
-	0x4e, 0x39, 0xb1, 0x8c, 0xad, 0xd6, 0xdf, 0x79, 0x28, 0xc8, 0x56, 0xe0, 0x43, 0x28, 0xdf, 0x4c,
-	0x86, 0xe4, 0x7c, 0x3c, 0x21, 0x43, 0xf4, 0x02, 0x1f, 0x80, 0x42, 0xc9, 0x68, 0xac, 0x6a, 0x84,
-	0xa2, 0x1c, 0xae, 0x02, 0xa4, 0x88, 0x0c, 0xd1, 0x1e, 0x56, 0xa0, 0x30, 0x9e, 0x8c, 0x35, 0x94,
-	0xc7, 0x65, 0x28, 0x52, 0xd2, 0x1f, 0xde, 0xa1, 0x02, 0x3e, 0x82, 0x8a, 0x46, 0xfb, 0x13, 0xb5,
-	0x3f, 0xd0, 0xc6, 0xd3, 0x09, 0x2a, 0x4a, 0xc9, 0xc1, 0xf4, 0x7a, 0x76, 0x45, 0x34, 0x32, 0x44,
-	0xfb, 0x92, 0x4a, 0x28, 0x9d, 0x52, 0x54, 0x92, 0x9e, 0x11, 0xd1, 0x74, 0x55, 0xeb, 0x6b, 0x04,
-	0x29, 0x12, 0xce, 0x6e, 0x52, 0x58, 0x96, 0x70, 0x48, 0xae, 0x12, 0x08, 0xf8, 0x15, 0xa0, 0xf1,
-	0xe4, 0x76, 0x7a, 0x49, 0xf4, 0xc1, 0x45, 0x7f, 0x3c, 0x19, 0x4c, 0x87, 0x04, 0x55, 0xe2, 0x04,
-	0xd5, 0xd9, 0x74, 0xa2, 0x12, 0x74, 0x88, 0x4f, 0x00, 0x67, 0x82, 0xfa, 0xd9, 0x9d, 0x4e, 0xfb,
-	0x93, 0x11, 0x41, 0x55, 0x79, 0x56, 0xda, 0xdf, 0xdd, 0x10, 0x7a, 0xa7, 0x53, 0xa2, 0xde, 0x5c,
-	0x69, 0xe8, 0x48, 0x5a, 0x63, 0x4b, 0xcc, 0x9f, 0x90, 0xf7, 0x1a, 0x42, 0xb8, 0x06, 0x2f, 0x37,
-	0xad, 0x83, 0xab, 0xa9, 0x4a, 0xd0, 0x4b, 0x99, 0xcd, 0x25, 0x21, 0xb3, 0xfe, 0xd5, 0xf8, 0x96,
-	0x20, 0x8c, 0xbf, 0x82, 0x63, 0xa9, 0x78, 0x31, 0x56, 0xb5, 0x29, 0xbd, 0xd3, 0xcf, 0xa7, 0x54,
-	0xbf, 0x24, 0x77, 0xe8, 0x78, 0x3b, 0x85, 0x6b, 0xa2, 0xf5, 0x87, 0x7d, 0xad, 0x8f, 0x5e, 0x49,
-	0x7b, 0x56, 0xdc, 0xa3, 0xbd, 0x86, 0x4f, 0xa1, 0x26, 0xf9, 0x33, 0x3a, 0xbe, 0x95, 0x1e, 0x69,
-	0xd5, 0x2f, 0xfa, 0xea, 0x05, 0x3a, 0x69, 0xfd, 0x02, 0xca, 0x88, 0x09, 0x55, 0x18, 0x82, 0x61,
-	0x04, 0xf9, 0x07, 0xb6, 0x8e, 0xae, 0x73, 0x99, 0xca, 0x47, 0xfc, 0x35, 0x80, 0xc9, 0x5d, 0x97,
-	0x99, 0xc2, 0xe1, 0x5e, 0x74, 0x5f, 0xcb, 0x74, 0xc3, 0xd2, 0x1a, 0x02, 0x4a, 0x4f, 0x5f, 0x33,
-	0x61, 0x58, 0x86, 0x30, 0xbe, 0x40, 0x85, 0x82, 0x32, 0x5b, 0x3d, 0x9b, 0xc3, 0x2b, 0x28, 0x7e,
-	0x30, 0xdc, 0x15, 0x8b, 0x0e, 0x1e, 0xd0, 0x18, 0xec, 0x68, 0xe6, 0x9f, 0x68, 0xfe, 0x0e, 0x28,
-	0xd5, 0xfc, 0xdf, 0x99, 0x3d, 0x51, 0xc1, 0x6f, 0x40, 0x59, 0x26, 0xa7, 0xa3, 0xf5, 0xaa, 0xf4,
-	0x6a, 0xd9, 0x1a, 0x6d, 0x4a, 0xd3, 0x8c, 0x26, 0x1b, 0x3a, 0x64, 0xee, 0x97, 0x36, 0xf4, 0xcf,
-	0x1c, 0x1c, 0xa5, 0x1d, 0x3d, 0x5b, 0x53, 0xc3, 0xb3, 0x19, 0x6e, 0x80, 0x12, 0x0a, 0x23, 0x10,
-	0x97, 0x99, 0x54, 0x86, 0xf1, 0x09, 0xec, 0x33, 0xcf, 0x92, 0x9e, 0x58, 0x2b, 0x41, 0x9f, 0x2d,
-	0xac, 0xb1, 0x53, 0xd8, 0xc1, 0x46, 0x05, 0x73, 0xa8, 0x8e, 0x98, 0x78, 0xb7, 0x62, 0xc1, 0x9a,
-	0xb2, 0x70, 0xe5, 0x0a, 0x39, 0x82, 0xdf, 0x24, 0x4c, 0xc2, 0xc7, 0xe0, 0x73, 0xb5, 0x6c, 0xc5,
-	0xc8, 0xef, 0xc4, 0x18, 0xc1, 0x61, 0x14, 0x20, 0x9b, 0x4d, 0x03, 0x14, 0xdf, 0xb0, 0x99, 0xea,
-	0xfc, 0x11, 0xff, 0x9f, 0x16, 0x69, 0x86, 0xa5, 0x6f, 0xce, 0xf9, 0xc3, 0xd2, 0x08, 0x1e, 0x92,
-	0x30, 0x19, 0x6e, 0x7d, 0x17, 0xdd, 0xc0, 0x0b, 0x27, 0x14, 0x3c, 0x58, 0x9f, 0xf3, 0x40, 0x16,
-	0xff, 0xa4, 0xed, 0xad, 0x26, 0x54, 0xa3, 0x70, 0x51, 0x5f, 0x27, 0xec, 0xa3, 0xc0, 0x55, 0xd8,
-	0x73, 0xac, 0x84, 0xb2, 0xe7, 0x58, 0xad, 0x6f, 0xe1, 0xe8, 0x91, 0x31, 0x70, 0x79, 0xc8, 0x9e,
-	0x50, 0x7e, 0x02, 0xb4, 0xd1, 0x94, 0xb3, 0xb5, 0x60, 0x21, 0x6e, 0x42, 0x25, 0x78, 0x84, 0x11,
-	0xf9, 0x80, 0x6e, 0x9a, 0x5a, 0x7f, 0xe5, 0x92, 0x52, 0x29, 0x0b, 0x7d, 0xee, 0x85, 0x0c, 0xf7,
-	0xa0, 0x14, 0x13, 0x24, 0x3f, 0xdf, 0xae, 0xf4, 0xea, 0xe9, 0x9d, 0xda, 0x95, 0xa7, 0x29, 0x11,
-	0x9f, 0x82, 0xb2, 0x30, 0x42, 0x7d, 0xc9, 0x83, 0x78, 0x0f, 0x14, 0x5a, 0x5a, 0x18, 0xe1, 0x35,
-	0x0f, 0xd2, 0x34, 0xf3, 0x69, 0x9a, 0x9f, 0x1c, 0xad, 0x0d, 0xb5, 0xad, 0x5c, 0xb2, 0xf6, 0xf7,
-	0xa0, 0x76, 0xcf, 0x84, 0xb9, 0x60, 0x96, 0x1e, 0x30, 0x93, 0x07, 0x56, 0xa8, 0x9b, 0x7c, 0xe5,
-	0x89, 0x64, 0x16, 0xc7, 0x89, 0x93, 0xc6, 0xbe, 0x81, 0x74, 0x7d, 0x72, 0x2c, 0x6f, 0xe1, 0x70,
-	0x7b, 0xf7, 0xea, 0x50, 0x92, 0x59, 0x3c, 0xce, 0x25, 0x85, 0xff, 0xbd, 0xdf, 0xad, 0x73, 0x38,
-	0xde, 0xde, 0xb0, 0xf8, 0x26, 0x76, 0xa1, 0xc4, 0x3c, 0x11, 0x38, 0x2c, 0xed, 0xdd, 0x33, 0xfb,
-	0x98, 0xb2, 0x7a, 0xef, 0x37, 0xde, 0xdb, 0xea, 0xca, 0xf7, 0x79, 0x20, 0xf0, 0x10, 0x14, 0xca,
-	0x6c, 0x27, 0x14, 0x2c, 0xc0, 0xf5, 0xe7, 0xde, 0xda, 0x8d, 0x67, 0x3d, 0xad, 0x17, 0xed, 0xdc,
-	0x0f, 0xb9, 0xb3, 0x29, 0xb4, 0x78, 0x60, 0x77, 0x16, 0x6b, 0x9f, 0x05, 0x2e, 0xb3, 0x6c, 0x16,
-	0x74, 0xee, 0x8d, 0x79, 0xe0, 0x98, 0xe9, 0x39, 0xf9, 0xa1, 0xf1, 0xeb, 0xf7, 0xb6, 0x23, 0x16,
-	0xab, 0x79, 0xc7, 0xe4, 0xcb, 0xee, 0x06, 0xb5, 0x1b, 0x53, 0xe3, 0x0f, 0x8e, 0xb0, 0x2b, 0xa9,
-	0xf3, 0xf8, 0xeb, 0xe5, 0xc7, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x3e, 0x82, 0x3d, 0x52, 0xe1,
-	0x08, 0x00, 0x00,
+	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_e5819fec16c96da2)
+}
+
+var fileDescriptor_chaincode_shim_e5819fec16c96da2 = []byte{
+	// 1249 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x4b, 0x73, 0xe2, 0x46,
+	0x10, 0x0e, 0x0f, 0x1b, 0xd1, 0x60, 0x3c, 0x3b, 0x36, 0x5e, 0xcc, 0x66, 0xb3, 0x98, 0xda, 0x83,
+	0x73, 0x81, 0x2c, 0x49, 0xaa, 0x92, 0xaa, 0x54, 0x6d, 0x61, 0x18, 0x03, 0x65, 0x1b, 0xd8, 0x91,
+	0xec, 0x8d, 0x73, 0x51, 0x09, 0x34, 0x06, 0x95, 0x41, 0x52, 0xa4, 0x61, 0x77, 0xc9, 0x2d, 0xd7,
+	0x1c, 0xf3, 0x0f, 0xf2, 0xa7, 0xf2, 0x7b, 0x52, 0x33, 0x92, 0x30, 0x0f, 0x3f, 0x2a, 0x3e, 0xc1,
+	0xd7, 0xfd, 0xf5, 0xd7, 0x3d, 0xdd, 0x9a, 0x07, 0x1c, 0xba, 0x8c, 0x79, 0xd5, 0xe1, 0xd8, 0xb0,
+	0xec, 0xa1, 0x63, 0x32, 0xdd, 0x1f, 0x5b, 0xd3, 0x8a, 0xeb, 0x39, 0xdc, 0xc1, 0xdb, 0xf2, 0xc7,
+	0x2f, 0x16, 0xd7, 0x28, 0xec, 0x13, 0xb3, 0x79, 0xc0, 0x29, 0xee, 0x49, 0x9f, 0xeb, 0x39, 0xae,
+	0xe3, 0x1b, 0x93, 0xd0, 0xf8, 0x66, 0xe4, 0x38, 0xa3, 0x09, 0xab, 0x4a, 0x34, 0x98, 0xdd, 0x54,
+	0xb9, 0x35, 0x65, 0x3e, 0x37, 0xa6, 0x6e, 0x40, 0x28, 0xff, 0xbb, 0x0d, 0xa8, 0x11, 0xe9, 0x5d,
+	0x30, 0xdf, 0x37, 0x46, 0x0c, 0xbf, 0x83, 0x24, 0x9f, 0xbb, 0xac, 0x10, 0x2b, 0xc5, 0x8e, 0x73,
+	0xb5, 0xd7, 0x01, 0xd5, 0xaf, 0xac, 0xf3, 0x2a, 0xda, 0xdc, 0x65, 0x54, 0x52, 0xf1, 0x4f, 0x90,
+	0x5e, 0x48, 0x17, 0xe2, 0xa5, 0xd8, 0x71, 0xa6, 0x56, 0xac, 0x04, 0xc9, 0x2b, 0x51, 0xf2, 0x8a,
+	0x16, 0x31, 0xe8, 0x1d, 0x19, 0x17, 0x20, 0xe5, 0x1a, 0xf3, 0x89, 0x63, 0x98, 0x85, 0x44, 0x29,
+	0x76, 0x9c, 0xa5, 0x11, 0xc4, 0x18, 0x92, 0xfc, 0x8b, 0x65, 0x16, 0x92, 0xa5, 0xd8, 0x71, 0x9a,
+	0xca, 0xff, 0xb8, 0x06, 0x4a, 0xb4, 0xc4, 0xc2, 0x96, 0x4c, 0x73, 0x10, 0x95, 0xa7, 0x5a, 0x23,
+	0x9b, 0x99, 0xfd, 0xd0, 0x4b, 0x17, 0x3c, 0xfc, 0x1e, 0x76, 0xd7, 0x5a, 0x56, 0xd8, 0x5e, 0x0d,
+	0x5d, 0xac, 0x8c, 0x08, 0x2f, 0xcd, 0x0d, 0x57, 0x30, 0x7e, 0x0d, 0x30, 0x1c, 0x1b, 0xb6, 0xcd,
+	0x26, 0xba, 0x65, 0x16, 0x52, 0xb2, 0x9c, 0x74, 0x68, 0xe9, 0x98, 0xe5, 0xbf, 0x93, 0x90, 0x14,
+	0xad, 0xc0, 0x3b, 0x90, 0xbe, 0xec, 0x36, 0xc9, 0x69, 0xa7, 0x4b, 0x9a, 0xe8, 0x2b, 0x9c, 0x05,
+	0x85, 0x92, 0x56, 0x47, 0xd5, 0x08, 0x45, 0x31, 0x9c, 0x03, 0x88, 0x10, 0x69, 0xa2, 0x38, 0x56,
+	0x20, 0xd9, 0xe9, 0x76, 0x34, 0x94, 0xc0, 0x69, 0xd8, 0xa2, 0xa4, 0xde, 0xbc, 0x46, 0x49, 0xbc,
+	0x0b, 0x19, 0x8d, 0xd6, 0xbb, 0x6a, 0xbd, 0xa1, 0x75, 0x7a, 0x5d, 0xb4, 0x25, 0x24, 0x1b, 0xbd,
+	0x8b, 0xfe, 0x39, 0xd1, 0x48, 0x13, 0x6d, 0x0b, 0x2a, 0xa1, 0xb4, 0x47, 0x51, 0x4a, 0x78, 0x5a,
+	0x44, 0xd3, 0x55, 0xad, 0xae, 0x11, 0xa4, 0x08, 0xd8, 0xbf, 0x8c, 0x60, 0x5a, 0xc0, 0x26, 0x39,
+	0x0f, 0x21, 0xe0, 0x7d, 0x40, 0x9d, 0xee, 0x55, 0xef, 0x8c, 0xe8, 0x8d, 0x76, 0xbd, 0xd3, 0x6d,
+	0xf4, 0x9a, 0x04, 0x65, 0x82, 0x02, 0xd5, 0x7e, 0xaf, 0xab, 0x12, 0xb4, 0x83, 0x0f, 0x00, 0x2f,
+	0x04, 0xf5, 0x93, 0x6b, 0x9d, 0xd6, 0xbb, 0x2d, 0x82, 0x72, 0x22, 0x56, 0xd8, 0x3f, 0x5c, 0x12,
+	0x7a, 0xad, 0x53, 0xa2, 0x5e, 0x9e, 0x6b, 0x68, 0x57, 0x58, 0x03, 0x4b, 0xc0, 0xef, 0x92, 0x5f,
+	0x35, 0x84, 0x70, 0x1e, 0x5e, 0x2c, 0x5b, 0x1b, 0xe7, 0x3d, 0x95, 0xa0, 0x17, 0xa2, 0x9a, 0x33,
+	0x42, 0xfa, 0xf5, 0xf3, 0xce, 0x15, 0x41, 0x18, 0xbf, 0x84, 0x3d, 0xa1, 0xd8, 0xee, 0xa8, 0x5a,
+	0x8f, 0x5e, 0xeb, 0xa7, 0x3d, 0xaa, 0x9f, 0x91, 0x6b, 0xb4, 0xb7, 0x5a, 0xc2, 0x05, 0xd1, 0xea,
+	0xcd, 0xba, 0x56, 0x47, 0xfb, 0xc2, 0xde, 0xbf, 0xdc, 0xb0, 0xe7, 0xf1, 0x21, 0xe4, 0x05, 0xbf,
+	0x4f, 0x3b, 0x57, 0xc2, 0x23, 0xac, 0x7a, 0xbb, 0xae, 0xb6, 0xd1, 0x41, 0x94, 0x23, 0x08, 0xa9,
+	0x6b, 0x7a, 0x9b, 0x74, 0x5a, 0x6d, 0x0d, 0xbd, 0xc4, 0x25, 0xf8, 0x7a, 0x73, 0x99, 0x4b, 0x8c,
+	0x02, 0x7e, 0x0d, 0x87, 0xf7, 0x94, 0x17, 0xf6, 0xe3, 0x10, 0xbf, 0x85, 0xd2, 0x7d, 0xee, 0x8f,
+	0x1d, 0xad, 0xad, 0xf7, 0xfa, 0x62, 0x6e, 0x2a, 0x2a, 0x96, 0x7f, 0x01, 0xa5, 0xc5, 0xb8, 0xca,
+	0x0d, 0xce, 0x30, 0x82, 0xc4, 0x2d, 0x9b, 0xcb, 0xed, 0x94, 0xa6, 0xe2, 0x2f, 0xfe, 0x06, 0x60,
+	0xe8, 0x4c, 0x26, 0x6c, 0xc8, 0x2d, 0xc7, 0x96, 0xfb, 0x25, 0x4d, 0x97, 0x2c, 0xe5, 0x26, 0xa0,
+	0x28, 0xfa, 0x82, 0x71, 0xc3, 0x34, 0xb8, 0xf1, 0x0c, 0x15, 0x0a, 0x4a, 0x7f, 0xf6, 0x60, 0x0d,
+	0xfb, 0xb0, 0xf5, 0xc9, 0x98, 0xcc, 0x98, 0x0c, 0xcc, 0xd2, 0x00, 0xac, 0x69, 0x26, 0x36, 0x34,
+	0x3f, 0x03, 0xea, 0xcf, 0xfe, 0x67, 0x65, 0x1b, 0x2a, 0xf8, 0x1d, 0x28, 0xd3, 0x30, 0x5a, 0x6e,
+	0xef, 0x4c, 0x2d, 0xbf, 0xd8, 0xc6, 0xcb, 0xd2, 0x74, 0x41, 0x13, 0x0d, 0x6d, 0xb2, 0xc9, 0x73,
+	0x1b, 0xfa, 0x67, 0x0c, 0x76, 0xa3, 0x8e, 0x9e, 0xcc, 0xa9, 0x61, 0x8f, 0x18, 0x2e, 0x82, 0xe2,
+	0x73, 0xc3, 0xe3, 0x67, 0x0b, 0xa9, 0x05, 0xc6, 0x07, 0xb0, 0xcd, 0x6c, 0x53, 0x78, 0x02, 0xad,
+	0x10, 0x3d, 0xb9, 0xb0, 0xe2, 0xda, 0xc2, 0xb2, 0x4b, 0x2b, 0x18, 0x40, 0xae, 0xc5, 0xf8, 0x87,
+	0x19, 0xf3, 0xe6, 0x94, 0xf9, 0xb3, 0x09, 0x17, 0x23, 0xf8, 0x5d, 0xc0, 0x30, 0x7d, 0x00, 0x9e,
+	0x5a, 0xcb, 0x4a, 0x8e, 0xc4, 0x5a, 0x8e, 0x16, 0xec, 0xc8, 0x04, 0x8b, 0xd9, 0x14, 0x41, 0x71,
+	0x8d, 0x11, 0x53, 0xad, 0x3f, 0x82, 0xf3, 0x7c, 0x8b, 0x2e, 0xb0, 0xf0, 0x0d, 0x1c, 0xe7, 0x76,
+	0x6a, 0x78, 0xb7, 0x61, 0x9a, 0x05, 0x2e, 0xbf, 0x95, 0x5f, 0x60, 0xdb, 0xf2, 0xb9, 0xe3, 0xcd,
+	0x4f, 0x1d, 0x4f, 0x2c, 0x7e, 0xa3, 0xed, 0xe5, 0x33, 0x78, 0xb5, 0xce, 0xfa, 0x68, 0xf1, 0x71,
+	0xcf, 0x15, 0x85, 0xfa, 0xf7, 0xcc, 0x69, 0xb9, 0xf6, 0xf8, 0x5a, 0xed, 0x23, 0xc8, 0xaf, 0x8b,
+	0x3d, 0x7f, 0x50, 0x8f, 0x35, 0xe9, 0x9f, 0x38, 0xec, 0x87, 0x69, 0x56, 0x9b, 0xf5, 0x06, 0x32,
+	0x52, 0x58, 0x1f, 0x4c, 0x9c, 0xe1, 0xad, 0xcc, 0x95, 0xa4, 0x20, 0x4d, 0x27, 0xc2, 0x82, 0x5f,
+	0x41, 0x9a, 0xd9, 0x66, 0xe8, 0x8e, 0x4b, 0xb7, 0xc2, 0x6c, 0x33, 0x70, 0xfe, 0x0c, 0x01, 0x55,
+	0x17, 0x97, 0x5b, 0x21, 0xf1, 0xf4, 0x25, 0x28, 0xd9, 0x02, 0xe3, 0x1f, 0x41, 0xc8, 0x04, 0x81,
+	0xc9, 0x27, 0x03, 0x53, 0xcc, 0x36, 0x65, 0xd8, 0x11, 0x64, 0x6d, 0xf6, 0x99, 0xf9, 0x5c, 0xbf,
+	0xb1, 0x3c, 0x9f, 0xcb, 0x1b, 0x51, 0xa1, 0x99, 0xc0, 0x76, 0x2a, 0x4c, 0x2b, 0xf3, 0xdf, 0x7e,
+	0x64, 0xfe, 0xa9, 0xb5, 0xf9, 0xb7, 0xee, 0x4e, 0xa0, 0x3a, 0x6f, 0x33, 0x6b, 0x34, 0xe6, 0xf7,
+	0x8c, 0xf3, 0x08, 0xb2, 0xb2, 0x17, 0xba, 0x3d, 0x9b, 0x0e, 0x98, 0x17, 0xb6, 0x24, 0x23, 0x6d,
+	0x5d, 0x69, 0x2a, 0xbb, 0xf0, 0x72, 0x6d, 0xe3, 0x2d, 0xf4, 0x9e, 0x33, 0xd7, 0xf5, 0x8c, 0x89,
+	0xcd, 0x8c, 0x25, 0xc8, 0xc9, 0xb1, 0xca, 0x9c, 0x5d, 0xf6, 0x85, 0xe3, 0x1c, 0xc4, 0x2d, 0x33,
+	0x4c, 0x11, 0xb7, 0xcc, 0xf2, 0x11, 0xec, 0xde, 0x31, 0x1a, 0x13, 0xc7, 0x67, 0x1b, 0x94, 0x1f,
+	0x00, 0x2d, 0xed, 0xd4, 0x93, 0x39, 0x67, 0x3e, 0x2e, 0x41, 0xc6, 0xbb, 0x83, 0x92, 0x9c, 0xa5,
+	0xcb, 0xa6, 0xf2, 0x5f, 0xb1, 0x70, 0xff, 0x51, 0xe6, 0xbb, 0x8e, 0xed, 0x33, 0x5c, 0x83, 0x54,
+	0x40, 0x10, 0xfc, 0xc4, 0x71, 0xa6, 0x56, 0x88, 0x0e, 0xba, 0x75, 0x79, 0x1a, 0x11, 0xf1, 0x21,
+	0x28, 0x63, 0xc3, 0xd7, 0xa7, 0x8e, 0x17, 0x1c, 0xce, 0x0a, 0x4d, 0x8d, 0x0d, 0xff, 0xc2, 0xf1,
+	0xa2, 0x32, 0x13, 0x51, 0x99, 0x8f, 0x9e, 0x37, 0x23, 0xc8, 0xaf, 0xd4, 0xb2, 0xf8, 0xcc, 0x6b,
+	0x90, 0xbf, 0x61, 0x7c, 0x38, 0x66, 0xa6, 0xee, 0xb1, 0xa1, 0xe3, 0x99, 0xbe, 0x3e, 0x74, 0x66,
+	0x36, 0x0f, 0x0f, 0x88, 0xbd, 0xd0, 0x49, 0x03, 0x5f, 0x43, 0xb8, 0x1e, 0x3d, 0x2b, 0xde, 0xc3,
+	0xce, 0xea, 0x85, 0x50, 0x80, 0x94, 0xa8, 0xe2, 0xee, 0x63, 0x89, 0xe0, 0xfd, 0x97, 0x4e, 0xf9,
+	0x14, 0xf6, 0x56, 0x8f, 0xfd, 0xe0, 0x78, 0xac, 0x42, 0x8a, 0xd9, 0xdc, 0xb3, 0x58, 0xd4, 0xbb,
+	0x07, 0x2e, 0x89, 0x88, 0x55, 0xbb, 0x5a, 0x7a, 0xcc, 0xaa, 0x33, 0xd7, 0x75, 0x3c, 0x8e, 0x4f,
+	0x40, 0xa1, 0x6c, 0x64, 0xf9, 0x9c, 0x79, 0xb8, 0xf0, 0xd0, 0x53, 0xb6, 0xf8, 0xa0, 0xe7, 0x38,
+	0xf6, 0x5d, 0xec, 0xa4, 0x07, 0x65, 0xc7, 0x1b, 0x55, 0xc6, 0x73, 0x97, 0x79, 0x13, 0x66, 0x8e,
+	0x98, 0x57, 0xb9, 0x31, 0x06, 0x9e, 0x35, 0x8c, 0xa2, 0xc4, 0xdb, 0xfb, 0xb7, 0x6f, 0x47, 0x16,
+	0x1f, 0xcf, 0x06, 0x95, 0xa1, 0x33, 0xad, 0x2e, 0x51, 0xab, 0x01, 0x35, 0x78, 0x83, 0xfb, 0x55,
+	0x41, 0x1d, 0x04, 0x0f, 0xfa, 0xef, 0xff, 0x1b, 0x00, 0x0f, 0xa7, 0x93, 0x32, 0xf4, 0x0b, 0x00,
+	0x00,
 }
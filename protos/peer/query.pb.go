@@ -33,7 +33,7 @@ func (m *ChaincodeQueryResponse) Reset()         { *m = ChaincodeQueryResponse{}
 func (m *ChaincodeQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeQueryResponse) ProtoMessage()    {}
 func (*ChaincodeQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{0}
+	return fileDescriptor_query_d45bcf7fe2423301, []int{0}
 }
 func (m *ChaincodeQueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeQueryResponse.Unmarshal(m, b)
@@ -91,7 +91,7 @@ func (m *ChaincodeInfo) Reset()         { *m = ChaincodeInfo{} }
 func (m *ChaincodeInfo) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeInfo) ProtoMessage()    {}
 func (*ChaincodeInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{1}
+	return fileDescriptor_query_d45bcf7fe2423301, []int{1}
 }
 func (m *ChaincodeInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeInfo.Unmarshal(m, b)
@@ -174,7 +174,7 @@ func (m *ChannelQueryResponse) Reset()         { *m = ChannelQueryResponse{} }
 func (m *ChannelQueryResponse) String() string { return proto.CompactTextString(m) }
 func (*ChannelQueryResponse) ProtoMessage()    {}
 func (*ChannelQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{2}
+	return fileDescriptor_query_d45bcf7fe2423301, []int{2}
 }
 func (m *ChannelQueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelQueryResponse.Unmarshal(m, b)
@@ -213,7 +213,7 @@ func (m *ChannelInfo) Reset()         { *m = ChannelInfo{} }
 func (m *ChannelInfo) String() string { return proto.CompactTextString(m) }
 func (*ChannelInfo) ProtoMessage()    {}
 func (*ChannelInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_query_37e5d407118e73f5, []int{3}
+	return fileDescriptor_query_d45bcf7fe2423301, []int{3}
 }
 func (m *ChannelInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelInfo.Unmarshal(m, b)
@@ -240,34 +240,78 @@ func (m *ChannelInfo) GetChannelId() string {
 	return ""
 }
 
+// PendingSnapshotRequests returns the block numbers of the pending requests
+// to generate snapshots of the ledger of a channel
+type PendingSnapshotRequests struct {
+	BlockNumbers         []uint64 `protobuf:"varint,1,rep,packed,name=block_numbers,json=blockNumbers,proto3" json:"block_numbers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PendingSnapshotRequests) Reset()         { *m = PendingSnapshotRequests{} }
+func (m *PendingSnapshotRequests) String() string { return proto.CompactTextString(m) }
+func (*PendingSnapshotRequests) ProtoMessage()    {}
+func (*PendingSnapshotRequests) Descriptor() ([]byte, []int) {
+	return fileDescriptor_query_d45bcf7fe2423301, []int{4}
+}
+func (m *PendingSnapshotRequests) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingSnapshotRequests.Unmarshal(m, b)
+}
+func (m *PendingSnapshotRequests) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingSnapshotRequests.Marshal(b, m, deterministic)
+}
+func (dst *PendingSnapshotRequests) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingSnapshotRequests.Merge(dst, src)
+}
+func (m *PendingSnapshotRequests) XXX_Size() int {
+	return xxx_messageInfo_PendingSnapshotRequests.Size(m)
+}
+func (m *PendingSnapshotRequests) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingSnapshotRequests.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingSnapshotRequests proto.InternalMessageInfo
+
+func (m *PendingSnapshotRequests) GetBlockNumbers() []uint64 {
+	if m != nil {
+		return m.BlockNumbers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeQueryResponse)(nil), "protos.ChaincodeQueryResponse")
 	proto.RegisterType((*ChaincodeInfo)(nil), "protos.ChaincodeInfo")
 	proto.RegisterType((*ChannelQueryResponse)(nil), "protos.ChannelQueryResponse")
 	proto.RegisterType((*ChannelInfo)(nil), "protos.ChannelInfo")
+	proto.RegisterType((*PendingSnapshotRequests)(nil), "protos.PendingSnapshotRequests")
 }
 
-func init() { proto.RegisterFile("peer/query.proto", fileDescriptor_query_37e5d407118e73f5) }
-
-var fileDescriptor_query_37e5d407118e73f5 = []byte{
-	// 296 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xdf, 0x4a, 0xc3, 0x30,
-	0x14, 0xc6, 0xe9, 0xfe, 0xba, 0x33, 0x15, 0x89, 0x53, 0x72, 0x23, 0x8c, 0x5e, 0x4d, 0x90, 0x16,
-	0x14, 0x5f, 0xc0, 0x5d, 0xc8, 0xae, 0x86, 0xbd, 0xf4, 0x46, 0xba, 0xe4, 0x6c, 0x0d, 0x6c, 0x49,
-	0x4c, 0xba, 0xc1, 0x9e, 0xc6, 0x57, 0x95, 0x93, 0xac, 0xa3, 0xbb, 0xea, 0x39, 0xbf, 0xef, 0x17,
-	0xca, 0x97, 0xc0, 0x9d, 0x45, 0x74, 0xf9, 0xef, 0x1e, 0xdd, 0x31, 0xb3, 0xce, 0xd4, 0x86, 0x0d,
-	0xc2, 0xc7, 0xa7, 0x4b, 0x78, 0x9c, 0x57, 0xa5, 0xd2, 0xc2, 0x48, 0xfc, 0xa2, 0xbc, 0x40, 0x6f,
-	0x8d, 0xf6, 0xc8, 0xde, 0x01, 0x44, 0x93, 0x78, 0x9e, 0x4c, 0xbb, 0xb3, 0xf1, 0xeb, 0x43, 0x3c,
-	0xed, 0xb3, 0xf3, 0x99, 0x85, 0x5e, 0x9b, 0xa2, 0x25, 0xa6, 0x7f, 0x09, 0xdc, 0x5c, 0xa4, 0x8c,
-	0x41, 0x4f, 0x97, 0x3b, 0xe4, 0xc9, 0x34, 0x99, 0x8d, 0x8a, 0x30, 0x33, 0x0e, 0xc3, 0x03, 0x3a,
-	0xaf, 0x8c, 0xe6, 0x9d, 0x80, 0x9b, 0x95, 0x6c, 0x5b, 0xd6, 0x15, 0xef, 0x46, 0x9b, 0x66, 0x36,
-	0x81, 0xbe, 0xd2, 0x76, 0x5f, 0xf3, 0x5e, 0x80, 0x71, 0x21, 0x13, 0xbd, 0x10, 0xbc, 0x1f, 0x4d,
-	0x9a, 0x89, 0x1d, 0x88, 0x0d, 0x22, 0xa3, 0x99, 0xdd, 0x42, 0x47, 0x49, 0x3e, 0x9c, 0x26, 0xb3,
-	0xeb, 0xa2, 0xa3, 0x64, 0xfa, 0x09, 0x93, 0x79, 0x55, 0x6a, 0x8d, 0xdb, 0xcb, 0xc2, 0x39, 0x5c,
-	0x89, 0xc8, 0x9b, 0xba, 0xf7, 0xad, 0xba, 0xc4, 0x43, 0xd9, 0xb3, 0x94, 0xbe, 0xc0, 0xb8, 0x15,
-	0xb0, 0xa7, 0x70, 0x61, 0xb4, 0xfe, 0x28, 0x79, 0x6a, 0x3b, 0x3a, 0x91, 0x85, 0xfc, 0x58, 0x42,
-	0x6a, 0xdc, 0x26, 0xab, 0x8e, 0x16, 0xdd, 0x16, 0xe5, 0x06, 0x5d, 0xb6, 0x2e, 0x57, 0x4e, 0x89,
-	0xe6, 0x27, 0xf4, 0x46, 0xdf, 0xcf, 0x1b, 0x55, 0x57, 0xfb, 0x55, 0x26, 0xcc, 0x2e, 0x6f, 0xa9,
-	0x79, 0x54, 0xf3, 0xa8, 0xe6, 0xa4, 0xae, 0xe2, 0x13, 0xbe, 0xfd, 0x07, 0x00, 0x00, 0xff, 0xff,
-	0x79, 0x94, 0xb3, 0xbd, 0xdd, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("peer/query.proto", fileDescriptor_query_d45bcf7fe2423301) }
+
+var fileDescriptor_query_d45bcf7fe2423301 = []byte{
+	// 338 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x92, 0xcd, 0x4e, 0xf2, 0x40,
+	0x14, 0x86, 0x53, 0x7e, 0x3f, 0x0e, 0xf0, 0xc5, 0x8c, 0xa8, 0xb3, 0x31, 0x21, 0x75, 0x83, 0x89,
+	0x69, 0x13, 0x8d, 0x5b, 0x17, 0xb2, 0x30, 0x6c, 0x44, 0xeb, 0xce, 0x0d, 0x69, 0xa7, 0x87, 0x76,
+	0x22, 0xcc, 0x94, 0x99, 0x96, 0x84, 0xab, 0xf1, 0x56, 0xcd, 0xcc, 0x50, 0x52, 0x56, 0x3d, 0xe7,
+	0x79, 0x9f, 0x49, 0xfb, 0xb6, 0x85, 0x8b, 0x02, 0x51, 0x85, 0xbb, 0x0a, 0xd5, 0x21, 0x28, 0x94,
+	0x2c, 0x25, 0xe9, 0xd9, 0x8b, 0xf6, 0x97, 0x70, 0x3d, 0xcf, 0x63, 0x2e, 0x98, 0x4c, 0xf1, 0xd3,
+	0xe4, 0x11, 0xea, 0x42, 0x0a, 0x8d, 0xe4, 0x19, 0x80, 0xd5, 0x89, 0xa6, 0xde, 0xb4, 0x3d, 0x1b,
+	0x3e, 0x5e, 0xb9, 0xd3, 0x3a, 0x38, 0x9d, 0x59, 0x88, 0xb5, 0x8c, 0x1a, 0xa2, 0xff, 0xeb, 0xc1,
+	0xf8, 0x2c, 0x25, 0x04, 0x3a, 0x22, 0xde, 0x22, 0xf5, 0xa6, 0xde, 0x6c, 0x10, 0xd9, 0x99, 0x50,
+	0xe8, 0xef, 0x51, 0x69, 0x2e, 0x05, 0x6d, 0x59, 0x5c, 0xaf, 0xc6, 0x2e, 0xe2, 0x32, 0xa7, 0x6d,
+	0x67, 0x9b, 0x99, 0x4c, 0xa0, 0xcb, 0x45, 0x51, 0x95, 0xb4, 0x63, 0xa1, 0x5b, 0x8c, 0x89, 0x9a,
+	0x31, 0xda, 0x75, 0xa6, 0x99, 0x0d, 0xdb, 0x1b, 0xd6, 0x73, 0xcc, 0xcc, 0xe4, 0x3f, 0xb4, 0x78,
+	0x4a, 0xfb, 0x53, 0x6f, 0x36, 0x8a, 0x5a, 0x3c, 0xf5, 0xdf, 0x60, 0x32, 0xcf, 0x63, 0x21, 0x70,
+	0x73, 0x5e, 0x38, 0x84, 0x7f, 0xcc, 0xf1, 0xba, 0xee, 0x65, 0xa3, 0xae, 0xe1, 0xb6, 0xec, 0x49,
+	0xf2, 0x1f, 0x60, 0xd8, 0x08, 0xc8, 0xad, 0x7d, 0x61, 0x66, 0x5d, 0xf1, 0xf4, 0xd8, 0x76, 0x70,
+	0x24, 0x8b, 0xd4, 0x7f, 0x81, 0x9b, 0x0f, 0x14, 0x29, 0x17, 0xd9, 0x97, 0x88, 0x0b, 0x9d, 0xcb,
+	0x32, 0xc2, 0x5d, 0x85, 0xba, 0xd4, 0xe4, 0x0e, 0xc6, 0xc9, 0x46, 0xb2, 0x9f, 0x95, 0xa8, 0xb6,
+	0x09, 0x2a, 0x77, 0xfb, 0x4e, 0x34, 0xb2, 0xf0, 0xdd, 0xb1, 0xd7, 0x25, 0xf8, 0x52, 0x65, 0x41,
+	0x7e, 0x28, 0x50, 0x6d, 0x30, 0xcd, 0x50, 0x05, 0xeb, 0x38, 0x51, 0x9c, 0xd5, 0x0f, 0x69, 0xbe,
+	0xf1, 0xf7, 0x7d, 0xc6, 0xcb, 0xbc, 0x4a, 0x02, 0x26, 0xb7, 0x61, 0x43, 0x0d, 0x9d, 0x1a, 0x3a,
+	0x35, 0x34, 0x6a, 0xe2, 0x7e, 0x81, 0xa7, 0xbf, 0x01, 0x00, 0x5b, 0x43, 0xeb, 0x89, 0x1d, 0x02,
+	0x00, 0x00,
 }
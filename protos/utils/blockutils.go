@@ -49,6 +49,49 @@ func GetChainIDFromBlock(block *cb.Block) (string, error) {
 	return chdr.ChannelId, nil
 }
 
+// hashingAlgorithmKey is the key of the HashingAlgorithm value in the
+// channel group, see channelconfig.HashingAlgorithmKey
+const hashingAlgorithmKey = "HashingAlgorithm"
+
+// GetHashingAlgorithmFromConfigGroup returns the name of the hashing
+// algorithm of the channel group, or an empty string when it is not set
+func GetHashingAlgorithmFromConfigGroup(channelGroup *cb.ConfigGroup) (string, error) {
+	value, ok := channelGroup.GetValues()[hashingAlgorithmKey]
+	if !ok || value == nil {
+		return "", nil
+	}
+	hashingAlgorithm := &cb.HashingAlgorithm{}
+	if err := proto.Unmarshal(value.Value, hashingAlgorithm); err != nil {
+		return "", errors.Wrap(err, "error unmarshaling HashingAlgorithm")
+	}
+	return hashingAlgorithm.Name, nil
+}
+
+// GetHashingAlgorithmFromBlock returns the name of the hashing algorithm of
+// the channel configured by the config block, or an empty string when it is
+// not set
+func GetHashingAlgorithmFromBlock(block *cb.Block) (string, error) {
+	if block == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return "", errors.New("failed to retrieve hashing algorithm - block is empty")
+	}
+	envelope, err := GetEnvelopeFromBlock(block.Data.Data[0])
+	if err != nil {
+		return "", err
+	}
+	payload, err := GetPayload(envelope)
+	if err != nil {
+		return "", err
+	}
+	configEnvelope := &cb.ConfigEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+		return "", errors.Wrap(err, "error unmarshaling ConfigEnvelope")
+	}
+	if configEnvelope.Config == nil {
+		return "", errors.New("failed to retrieve hashing algorithm - block is not a config block")
+	}
+	return GetHashingAlgorithmFromConfigGroup(configEnvelope.Config.ChannelGroup)
+}
+
 // GetMetadataFromBlock retrieves metadata at the specified index.
 func GetMetadataFromBlock(block *cb.Block, index cb.BlockMetadataIndex) (*cb.Metadata, error) {
 	md := &cb.Metadata{}
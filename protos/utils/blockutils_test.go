@@ -106,6 +106,40 @@ func TestGetChainIDFromBlock(t *testing.T) {
 	assert.Error(t, err, "Expected error when payload header is nil")
 }
 
+func TestGetHashingAlgorithmFromBlock(t *testing.T) {
+	_, err := utils.GetHashingAlgorithmFromBlock(nil)
+	assert.EqualError(t, err, "failed to retrieve hashing algorithm - block is empty")
+
+	gb, err := configtxtest.MakeGenesisBlock(testChainID)
+	assert.NoError(t, err, "Failed to create test configuration block")
+	name, err := utils.GetHashingAlgorithmFromBlock(gb)
+	assert.NoError(t, err)
+	assert.Equal(t, "SHA256", name)
+
+	_, err = utils.GetHashingAlgorithmFromBlock(&cb.Block{Data: &cb.BlockData{Data: [][]byte{[]byte("bad envelope")}}})
+	assert.Error(t, err, "Expected error with malformed envelope")
+}
+
+func TestGetHashingAlgorithmFromConfigGroup(t *testing.T) {
+	name, err := utils.GetHashingAlgorithmFromConfigGroup(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+
+	group := cb.NewConfigGroup()
+	name, err = utils.GetHashingAlgorithmFromConfigGroup(group)
+	assert.NoError(t, err)
+	assert.Empty(t, name)
+
+	group.Values["HashingAlgorithm"] = &cb.ConfigValue{Value: utils.MarshalOrPanic(&cb.HashingAlgorithm{Name: "SM3"})}
+	name, err = utils.GetHashingAlgorithmFromConfigGroup(group)
+	assert.NoError(t, err)
+	assert.Equal(t, "SM3", name)
+
+	group.Values["HashingAlgorithm"] = &cb.ConfigValue{Value: []byte("garbage")}
+	_, err = utils.GetHashingAlgorithmFromConfigGroup(group)
+	assert.Error(t, err)
+}
+
 func TestGetBlockFromBlockBytes(t *testing.T) {
 	testChainID := "myuniquetestchainid"
 	gb, err := configtxtest.MakeGenesisBlock(testChainID)
@@ -208,6 +208,16 @@ func SetTxID(channelHeader *cb.ChannelHeader, signatureHeader *cb.SignatureHeade
 	return nil
 }
 
+// SetTxIDWithHash generates a transaction id with hash based on the provided
+// signature header and sets the TxId field in the channel header
+func SetTxIDWithHash(channelHeader *cb.ChannelHeader, signatureHeader *cb.SignatureHeader, hash func([]byte) []byte) {
+	channelHeader.TxId = ComputeTxIDWithHash(
+		signatureHeader.Nonce,
+		signatureHeader.Creator,
+		hash,
+	)
+}
+
 // MakePayloadHeader creates a Payload Header.
 func MakePayloadHeader(ch *cb.ChannelHeader, sh *cb.SignatureHeader) *cb.Header {
 	return &cb.Header{
@@ -575,9 +575,9 @@ func createProposalFromCDS(chainID string, msg proto.Message, creator []byte, pr
 
 // ComputeTxID computes TxID as the Hash computed
 // over the concatenation of nonce and creator.
+// The channels hashing their blocks with another algorithm than SHA256
+// use ComputeTxIDWithHash.
 func ComputeTxID(nonce, creator []byte) (string, error) {
-	// TODO: Get the Hash function to be used from
-	// channel configuration
 	digest, err := factory.GetDefault().Hash(
 		append(nonce, creator...),
 		// TODO: matrix
@@ -605,6 +605,60 @@ func CheckTxID(txid string, nonce, creator []byte) error {
 	return nil
 }
 
+// ComputeTxIDWithHash computes TxID as the hash computed with hash
+// over the concatenation of nonce and creator.
+func ComputeTxIDWithHash(nonce, creator []byte, hash func([]byte) []byte) string {
+	return hex.EncodeToString(hash(append(nonce, creator...)))
+}
+
+// CheckTxIDWithHash checks that txid is equal to the hash computed
+// with hash over the concatenation of nonce and creator.
+func CheckTxIDWithHash(txid string, nonce, creator []byte, hash func([]byte) []byte) error {
+	computedTxID := ComputeTxIDWithHash(nonce, creator, hash)
+	if txid != computedTxID {
+		return errors.Errorf("invalid txid. got [%s], expected [%s]", txid, computedTxID)
+	}
+
+	return nil
+}
+
+// SetProposalTxIDWithHash sets the TxID of the unsigned proposal prop to the
+// hash computed with hash over the concatenation of its nonce and creator.
+// It returns the TxID.
+func SetProposalTxIDWithHash(prop *peer.Proposal, hash func([]byte) []byte) (string, error) {
+	if prop == nil {
+		return "", errors.New("proposal is nil")
+	}
+
+	hdr, err := GetHeader(prop.Header)
+	if err != nil {
+		return "", err
+	}
+
+	chdr, err := UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return "", err
+	}
+
+	shdr, err := GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return "", err
+	}
+
+	chdr.TxId = ComputeTxIDWithHash(shdr.Nonce, shdr.Creator, hash)
+	hdr.ChannelHeader, err = proto.Marshal(chdr)
+	if err != nil {
+		return "", errors.Wrap(err, "error marshaling ChannelHeader")
+	}
+
+	prop.Header, err = proto.Marshal(hdr)
+	if err != nil {
+		return "", errors.Wrap(err, "error marshaling Header")
+	}
+
+	return chdr.TxId, nil
+}
+
 // ComputeProposalBinding computes the binding of a proposal
 func ComputeProposalBinding(proposal *peer.Proposal) ([]byte, error) {
 	if proposal == nil {
@@ -636,6 +636,44 @@ func TestComputeProposalTxID(t *testing.T) {
 	assert.Equal(t, txid, txid2)
 }
 
+func TestComputeProposalTxIDWithHash(t *testing.T) {
+	txid := utils.ComputeTxIDWithHash([]byte{1}, []byte{1}, util.ComputeSM3)
+	assert.Equal(t, hex.EncodeToString(util.ComputeSM3([]byte{1, 1})), txid)
+
+	assert.NoError(t, utils.CheckTxIDWithHash(txid, []byte{1}, []byte{1}, util.ComputeSM3))
+	err := utils.CheckTxIDWithHash(txid, []byte{1}, []byte{1}, util.ComputeSHA256)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid txid")
+
+	// The TxIDs computed with SHA256 are the ones of ComputeTxID
+	txid, err = utils.ComputeTxID([]byte{1}, []byte{1})
+	assert.NoError(t, err)
+	assert.Equal(t, txid, utils.ComputeTxIDWithHash([]byte{1}, []byte{1}, util.ComputeSHA256))
+}
+
+func TestSetProposalTxIDWithHash(t *testing.T) {
+	_, err := utils.SetProposalTxIDWithHash(nil, util.ComputeSM3)
+	assert.EqualError(t, err, "proposal is nil")
+
+	prop, txid, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, testChainID, createCIS(), signerSerialized)
+	assert.NoError(t, err)
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	shdr, err := utils.GetSignatureHeader(hdr.SignatureHeader)
+	assert.NoError(t, err)
+	assert.NoError(t, utils.CheckTxID(txid, shdr.Nonce, shdr.Creator))
+
+	txid, err = utils.SetProposalTxIDWithHash(prop, util.ComputeSM3)
+	assert.NoError(t, err)
+	assert.NoError(t, utils.CheckTxIDWithHash(txid, shdr.Nonce, shdr.Creator, util.ComputeSM3))
+	hdr, err = utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, txid, chdr.TxId)
+	assert.Equal(t, testChainID, chdr.ChannelId)
+}
+
 var signer msp.SigningIdentity
 var signerSerialized []byte
 